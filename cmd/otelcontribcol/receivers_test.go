@@ -27,6 +27,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/chronyreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/datadogreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filelogreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/jmxreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/mongodbatlasreceiver"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/namedpipereceiver"
@@ -190,6 +191,14 @@ func TestDefaultReceivers(t *testing.T) {
 			receiver:      "file",
 			skipLifecycle: true, // Requires an existing JSONL file
 		},
+		{
+			receiver: "filereplay",
+			getConfigFn: func() component.Config {
+				cfg := rcvrFactories["filereplay"].CreateDefaultConfig().(*filereplayreceiver.Config)
+				cfg.Path = filepath.Join(t.TempDir(), "*.json")
+				return cfg
+			},
+		},
 		{
 			receiver: "filestats",
 		},
@@ -324,6 +333,9 @@ func TestDefaultReceivers(t *testing.T) {
 				return cfg
 			},
 		},
+		{
+			receiver: "prometheusremotewrite",
+		},
 		{
 			receiver:      "pulsar",
 			skipLifecycle: true, // TODO It requires a running pulsar instance to start successfully.
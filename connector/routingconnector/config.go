@@ -5,6 +5,7 @@ package routingconnector // import "github.com/open-telemetry/opentelemetry-coll
 
 import (
 	"errors"
+	"fmt"
 
 	"go.opentelemetry.io/collector/component"
 
@@ -16,6 +17,14 @@ var (
 	errNoPipelines        = errors.New("invalid route: no pipelines defined")
 	errUnexpectedConsumer = errors.New("expected consumer to be a connector router")
 	errNoTableItems       = errors.New("invalid routing table: the routing table is empty")
+	errContextNeedsOnce   = errors.New("invalid route: a context other than resource requires match_once to be enabled")
+)
+
+const (
+	resourceContext  = "resource"
+	spanContext      = "span"
+	logContext       = "log"
+	dataPointContext = "datapoint"
 )
 
 // Config defines configuration for the Routing processor.
@@ -62,6 +71,16 @@ func (c *Config) Validate() error {
 		if len(item.Pipelines) == 0 {
 			return errNoPipelines
 		}
+
+		switch item.Context {
+		case "", resourceContext:
+		case spanContext, logContext, dataPointContext:
+			if !c.MatchOnce {
+				return errContextNeedsOnce
+			}
+		default:
+			return fmt.Errorf("invalid route: unknown context %q", item.Context)
+		}
 	}
 
 	return nil
@@ -73,6 +92,14 @@ type RoutingTableItem struct {
 	// Required when 'Value' isn't provided.
 	Statement string `mapstructure:"statement"`
 
+	// Context is the OTTL context in which the statement is evaluated. Valid values are `resource`,
+	// `span` for traces, `log` for logs and `datapoint` for metrics. With `span`, `log` or
+	// `datapoint`, the statement is evaluated for every record, and the records are regrouped
+	// under their resource and scope for each pipeline. Contexts other than `resource` require
+	// MatchOnce to be enabled.
+	// The default value is `resource`.
+	Context string `mapstructure:"context"`
+
 	// Pipelines contains the list of pipelines to use when the value from the FromAttribute field
 	// matches this table item. When no pipelines are specified, the ones specified under
 	// DefaultPipelines are used, if any.
@@ -80,3 +107,14 @@ type RoutingTableItem struct {
 	// Optional.
 	Pipelines []component.ID `mapstructure:"pipelines"`
 }
+
+// validateContexts checks that the routing table only uses the resource context or the
+// record context of the signal.
+func validateContexts(table []RoutingTableItem, recordContext string) error {
+	for _, item := range table {
+		if item.Context != "" && item.Context != resourceContext && item.Context != recordContext {
+			return fmt.Errorf("invalid route: context %q is not supported for this signal, use %q or %q", item.Context, resourceContext, recordContext)
+		}
+	}
+	return nil
+}
@@ -156,6 +156,37 @@ func TestValidateConfig(t *testing.T) {
 			},
 			error: "invalid routing table: the routing table is empty",
 		},
+		{
+			name: "record context without match_once",
+			config: &Config{
+				Table: []RoutingTableItem{
+					{
+						Statement: `route() where attributes["attr"] == "acme"`,
+						Context:   "log",
+						Pipelines: []component.ID{
+							component.NewIDWithName(component.DataTypeLogs, "otlp"),
+						},
+					},
+				},
+			},
+			error: "invalid route: a context other than resource requires match_once to be enabled",
+		},
+		{
+			name: "unknown context",
+			config: &Config{
+				Table: []RoutingTableItem{
+					{
+						Statement: `route() where attributes["attr"] == "acme"`,
+						Context:   "scope",
+						Pipelines: []component.ID{
+							component.NewIDWithName(component.DataTypeLogs, "otlp"),
+						},
+					},
+				},
+				MatchOnce: true,
+			},
+			error: `invalid route: unknown context "scope"`,
+		},
 		{
 			name:   "empty config",
 			config: &Config{},
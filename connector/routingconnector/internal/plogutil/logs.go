@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package plogutil // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/routingconnector/internal/plogutil"
+
+import "go.opentelemetry.io/collector/pdata/plog"
+
+// MoveResourcesIf calls f sequentially for each ResourceLogs present in the first plog.Logs.
+// If f returns true, the element is removed from the first plog.Logs and added to the second plog.Logs.
+func MoveResourcesIf(from, to plog.Logs, f func(plog.ResourceLogs) bool) {
+	from.ResourceLogs().RemoveIf(func(rl plog.ResourceLogs) bool {
+		if !f(rl) {
+			return false
+		}
+		rl.MoveTo(to.ResourceLogs().AppendEmpty())
+		return true
+	})
+}
+
+// MoveRecordsWithContextIf calls f sequentially for each LogRecord present in the first plog.Logs.
+// If f returns true, the element is removed from the first plog.Logs and added to the second plog.Logs,
+// under a copy of its resource and scope. Resources and scopes left without records are removed.
+func MoveRecordsWithContextIf(from, to plog.Logs, f func(plog.ResourceLogs, plog.ScopeLogs, plog.LogRecord) bool) {
+	from.ResourceLogs().RemoveIf(func(rl plog.ResourceLogs) bool {
+		var rlTo plog.ResourceLogs
+		movedResource := false
+		rl.ScopeLogs().RemoveIf(func(sl plog.ScopeLogs) bool {
+			var slTo plog.ScopeLogs
+			movedScope := false
+			sl.LogRecords().RemoveIf(func(lr plog.LogRecord) bool {
+				if !f(rl, sl, lr) {
+					return false
+				}
+				if !movedResource {
+					rlTo = to.ResourceLogs().AppendEmpty()
+					rl.Resource().CopyTo(rlTo.Resource())
+					rlTo.SetSchemaUrl(rl.SchemaUrl())
+					movedResource = true
+				}
+				if !movedScope {
+					slTo = rlTo.ScopeLogs().AppendEmpty()
+					sl.Scope().CopyTo(slTo.Scope())
+					slTo.SetSchemaUrl(sl.SchemaUrl())
+					movedScope = true
+				}
+				lr.MoveTo(slTo.LogRecords().AppendEmpty())
+				return true
+			})
+			return movedScope && sl.LogRecords().Len() == 0
+		})
+		return movedResource && rl.ScopeLogs().Len() == 0
+	})
+}
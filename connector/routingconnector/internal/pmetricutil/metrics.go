@@ -0,0 +1,134 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package pmetricutil // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/routingconnector/internal/pmetricutil"
+
+import "go.opentelemetry.io/collector/pdata/pmetric"
+
+// MoveResourcesIf calls f sequentially for each ResourceMetrics present in the first pmetric.Metrics.
+// If f returns true, the element is removed from the first pmetric.Metrics and added to the second pmetric.Metrics.
+func MoveResourcesIf(from, to pmetric.Metrics, f func(pmetric.ResourceMetrics) bool) {
+	from.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		if !f(rm) {
+			return false
+		}
+		rm.MoveTo(to.ResourceMetrics().AppendEmpty())
+		return true
+	})
+}
+
+// MoveDataPointsWithContextIf calls f sequentially for each data point present in the first pmetric.Metrics.
+// The data point is a pmetric.NumberDataPoint, pmetric.HistogramDataPoint, pmetric.ExponentialHistogramDataPoint
+// or pmetric.SummaryDataPoint. If f returns true, the data point is removed from the first pmetric.Metrics and
+// added to the second pmetric.Metrics, under a copy of its resource, scope and metric. Resources, scopes and
+// metrics left without data points are removed.
+func MoveDataPointsWithContextIf(from, to pmetric.Metrics, f func(pmetric.ResourceMetrics, pmetric.ScopeMetrics, pmetric.Metric, any) bool) {
+	from.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		var rmTo pmetric.ResourceMetrics
+		movedResource := false
+		rm.ScopeMetrics().RemoveIf(func(sm pmetric.ScopeMetrics) bool {
+			var smTo pmetric.ScopeMetrics
+			movedScope := false
+			sm.Metrics().RemoveIf(func(m pmetric.Metric) bool {
+				var mTo pmetric.Metric
+				movedMetric := false
+				// destination returns the metric to which the data points are moved, creating it along
+				// with its resource and scope on the first move.
+				destination := func() pmetric.Metric {
+					if !movedResource {
+						rmTo = to.ResourceMetrics().AppendEmpty()
+						rm.Resource().CopyTo(rmTo.Resource())
+						rmTo.SetSchemaUrl(rm.SchemaUrl())
+						movedResource = true
+					}
+					if !movedScope {
+						smTo = rmTo.ScopeMetrics().AppendEmpty()
+						sm.Scope().CopyTo(smTo.Scope())
+						smTo.SetSchemaUrl(sm.SchemaUrl())
+						movedScope = true
+					}
+					if !movedMetric {
+						mTo = smTo.Metrics().AppendEmpty()
+						copyMetricDescription(m, mTo)
+						movedMetric = true
+					}
+					return mTo
+				}
+
+				remaining := 0
+				switch m.Type() {
+				case pmetric.MetricTypeGauge:
+					m.Gauge().DataPoints().RemoveIf(func(dp pmetric.NumberDataPoint) bool {
+						if !f(rm, sm, m, dp) {
+							return false
+						}
+						dp.MoveTo(destination().Gauge().DataPoints().AppendEmpty())
+						return true
+					})
+					remaining = m.Gauge().DataPoints().Len()
+				case pmetric.MetricTypeSum:
+					m.Sum().DataPoints().RemoveIf(func(dp pmetric.NumberDataPoint) bool {
+						if !f(rm, sm, m, dp) {
+							return false
+						}
+						dp.MoveTo(destination().Sum().DataPoints().AppendEmpty())
+						return true
+					})
+					remaining = m.Sum().DataPoints().Len()
+				case pmetric.MetricTypeHistogram:
+					m.Histogram().DataPoints().RemoveIf(func(dp pmetric.HistogramDataPoint) bool {
+						if !f(rm, sm, m, dp) {
+							return false
+						}
+						dp.MoveTo(destination().Histogram().DataPoints().AppendEmpty())
+						return true
+					})
+					remaining = m.Histogram().DataPoints().Len()
+				case pmetric.MetricTypeExponentialHistogram:
+					m.ExponentialHistogram().DataPoints().RemoveIf(func(dp pmetric.ExponentialHistogramDataPoint) bool {
+						if !f(rm, sm, m, dp) {
+							return false
+						}
+						dp.MoveTo(destination().ExponentialHistogram().DataPoints().AppendEmpty())
+						return true
+					})
+					remaining = m.ExponentialHistogram().DataPoints().Len()
+				case pmetric.MetricTypeSummary:
+					m.Summary().DataPoints().RemoveIf(func(dp pmetric.SummaryDataPoint) bool {
+						if !f(rm, sm, m, dp) {
+							return false
+						}
+						dp.MoveTo(destination().Summary().DataPoints().AppendEmpty())
+						return true
+					})
+					remaining = m.Summary().DataPoints().Len()
+				}
+				return movedMetric && remaining == 0
+			})
+			return movedScope && sm.Metrics().Len() == 0
+		})
+		return movedResource && rm.ScopeMetrics().Len() == 0
+	})
+}
+
+// copyMetricDescription copies everything but the data points of the metric.
+func copyMetricDescription(from, to pmetric.Metric) {
+	to.SetName(from.Name())
+	to.SetDescription(from.Description())
+	to.SetUnit(from.Unit())
+	from.Metadata().CopyTo(to.Metadata())
+
+	switch from.Type() {
+	case pmetric.MetricTypeGauge:
+		to.SetEmptyGauge()
+	case pmetric.MetricTypeSum:
+		to.SetEmptySum().SetAggregationTemporality(from.Sum().AggregationTemporality())
+		to.Sum().SetIsMonotonic(from.Sum().IsMonotonic())
+	case pmetric.MetricTypeHistogram:
+		to.SetEmptyHistogram().SetAggregationTemporality(from.Histogram().AggregationTemporality())
+	case pmetric.MetricTypeExponentialHistogram:
+		to.SetEmptyExponentialHistogram().SetAggregationTemporality(from.ExponentialHistogram().AggregationTemporality())
+	case pmetric.MetricTypeSummary:
+		to.SetEmptySummary()
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ptraceutil // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/routingconnector/internal/ptraceutil"
+
+import "go.opentelemetry.io/collector/pdata/ptrace"
+
+// MoveResourcesIf calls f sequentially for each ResourceSpans present in the first ptrace.Traces.
+// If f returns true, the element is removed from the first ptrace.Traces and added to the second ptrace.Traces.
+func MoveResourcesIf(from, to ptrace.Traces, f func(ptrace.ResourceSpans) bool) {
+	from.ResourceSpans().RemoveIf(func(rs ptrace.ResourceSpans) bool {
+		if !f(rs) {
+			return false
+		}
+		rs.MoveTo(to.ResourceSpans().AppendEmpty())
+		return true
+	})
+}
+
+// MoveSpansWithContextIf calls f sequentially for each Span present in the first ptrace.Traces.
+// If f returns true, the element is removed from the first ptrace.Traces and added to the second ptrace.Traces,
+// under a copy of its resource and scope. Resources and scopes left without spans are removed.
+func MoveSpansWithContextIf(from, to ptrace.Traces, f func(ptrace.ResourceSpans, ptrace.ScopeSpans, ptrace.Span) bool) {
+	from.ResourceSpans().RemoveIf(func(rs ptrace.ResourceSpans) bool {
+		var rsTo ptrace.ResourceSpans
+		movedResource := false
+		rs.ScopeSpans().RemoveIf(func(ss ptrace.ScopeSpans) bool {
+			var ssTo ptrace.ScopeSpans
+			movedScope := false
+			ss.Spans().RemoveIf(func(span ptrace.Span) bool {
+				if !f(rs, ss, span) {
+					return false
+				}
+				if !movedResource {
+					rsTo = to.ResourceSpans().AppendEmpty()
+					rs.Resource().CopyTo(rsTo.Resource())
+					rsTo.SetSchemaUrl(rs.SchemaUrl())
+					movedResource = true
+				}
+				if !movedScope {
+					ssTo = rsTo.ScopeSpans().AppendEmpty()
+					ss.Scope().CopyTo(ssTo.Scope())
+					ssTo.SetSchemaUrl(ss.SchemaUrl())
+					movedScope = true
+				}
+				span.MoveTo(ssTo.Spans().AppendEmpty())
+				return true
+			})
+			return movedScope && ss.Spans().Len() == 0
+		})
+		return movedResource && rs.ScopeSpans().Len() == 0
+	})
+}
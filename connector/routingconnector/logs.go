@@ -13,7 +13,9 @@ import (
 	"go.opentelemetry.io/collector/pdata/plog"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/routingconnector/internal/plogutil"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlresource"
 )
 
@@ -33,6 +35,10 @@ func newLogsConnector(
 ) (*logsConnector, error) {
 	cfg := config.(*Config)
 
+	if err := validateContexts(cfg.Table, logContext); err != nil {
+		return nil, err
+	}
+
 	lr, ok := logs.(connector.LogsRouterAndConsumer)
 	if !ok {
 		return nil, errUnexpectedConsumer
@@ -60,6 +66,10 @@ func (c *logsConnector) Capabilities() consumer.Capabilities {
 }
 
 func (c *logsConnector) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	if c.config.MatchOnce && c.router.hasRecordRoutes() {
+		return c.switchLogs(ctx, ld)
+	}
+
 	// routingEntry is used to group plog.ResourceLogs that are routed to
 	// the same set of exporters.
 	// This way we're not ending up with all the logs split up which would cause
@@ -117,3 +127,70 @@ func (c *logsConnector) group(
 	logs.CopyTo(group.ResourceLogs().AppendEmpty())
 	groups[consumer] = group
 }
+
+// switchLogs routes every resource or log record to the pipelines of the first route
+// which statement matches it. Routed log records are regrouped under a copy of their
+// resource and scope, and what is not routed goes to the default pipelines.
+func (c *logsConnector) switchLogs(ctx context.Context, ld plog.Logs) error {
+	// the routed data is moved out of a copy of the payload, so that it is
+	// evaluated by the following routes only if it was not matched.
+	remaining := plog.NewLogs()
+	ld.CopyTo(remaining)
+
+	// the data failing the statement of a route is routed to the default pipelines, like the
+	// resources failing the statements when routing whole resources.
+	failed := plog.NewLogs()
+	groups := make(map[consumer.Logs]plog.Logs)
+	for _, route := range c.router.routeSlice {
+		matched := plog.NewLogs()
+		var results matchResults
+		switch route.statementContext {
+		case resourceContext:
+			plogutil.MoveResourcesIf(remaining, failed, func(rl plog.ResourceLogs) bool {
+				rtx := ottlresource.NewTransformContext(rl.Resource())
+				_, isMatch, err := route.statement.Execute(ctx, rtx)
+				return results.add(isMatch, err)
+			})
+			plogutil.MoveResourcesIf(remaining, matched, func(plog.ResourceLogs) bool {
+				return results.next()
+			})
+		case logContext:
+			plogutil.MoveRecordsWithContextIf(remaining, failed, func(rl plog.ResourceLogs, sl plog.ScopeLogs, lr plog.LogRecord) bool {
+				tCtx := ottllog.NewTransformContext(lr, sl.Scope(), rl.Resource())
+				_, isMatch, err := route.logStatement.Execute(ctx, tCtx)
+				return results.add(isMatch, err)
+			})
+			plogutil.MoveRecordsWithContextIf(remaining, matched, func(plog.ResourceLogs, plog.ScopeLogs, plog.LogRecord) bool {
+				return results.next()
+			})
+		}
+		if results.errs != nil && c.config.ErrorMode == ottl.PropagateError {
+			return results.errs
+		}
+		c.groupAll(groups, route.consumer, matched)
+	}
+	c.groupAll(groups, c.router.defaultConsumer, failed)
+	c.groupAll(groups, c.router.defaultConsumer, remaining)
+
+	var errs error
+	for consumer, group := range groups {
+		errs = errors.Join(errs, consumer.ConsumeLogs(ctx, group))
+	}
+	return errs
+}
+
+func (c *logsConnector) groupAll(
+	groups map[consumer.Logs]plog.Logs,
+	consumer consumer.Logs,
+	ld plog.Logs,
+) {
+	if consumer == nil || ld.ResourceLogs().Len() == 0 {
+		return
+	}
+	group, ok := groups[consumer]
+	if !ok {
+		groups[consumer] = ld
+		return
+	}
+	ld.ResourceLogs().MoveAndAppendTo(group.ResourceLogs())
+}
@@ -16,6 +16,8 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 )
 
 func TestLogsRegisterConsumersForValidRoute(t *testing.T) {
@@ -465,3 +467,171 @@ func TestLogsConnectorCapabilities(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, false, conn.Capabilities().MutatesData)
 }
+
+func TestLogsAreCorrectlyRoutedPerLogRecord(t *testing.T) {
+	logsDefault := component.NewIDWithName(component.DataTypeLogs, "default")
+	logsAudit := component.NewIDWithName(component.DataTypeLogs, "audit")
+	logsAcme := component.NewIDWithName(component.DataTypeLogs, "acme")
+
+	cfg := &Config{
+		DefaultPipelines: []component.ID{logsDefault},
+		Table: []RoutingTableItem{
+			{
+				Statement: `route() where attributes["log.type"] == "audit"`,
+				Context:   "log",
+				Pipelines: []component.ID{logsAudit},
+			},
+			{
+				Statement: `route() where attributes["X-Tenant"] == "acme"`,
+				Pipelines: []component.ID{logsAcme},
+			},
+		},
+		MatchOnce: true,
+	}
+	require.NoError(t, component.ValidateConfig(cfg))
+
+	var defaultSink, auditSink, acmeSink consumertest.LogsSink
+
+	router := connector.NewLogsRouter(map[component.ID]consumer.Logs{
+		logsDefault: &defaultSink,
+		logsAudit:   &auditSink,
+		logsAcme:    &acmeSink,
+	})
+
+	conn, err := NewFactory().CreateLogsToLogs(
+		context.Background(),
+		connectortest.NewNopSettings(),
+		cfg,
+		router.(consumer.Logs),
+	)
+	require.NoError(t, err)
+
+	l := plog.NewLogs()
+	for _, tenant := range []string{"acme", "ecorp"} {
+		rl := l.ResourceLogs().AppendEmpty()
+		rl.Resource().Attributes().PutStr("X-Tenant", tenant)
+		sl := rl.ScopeLogs().AppendEmpty()
+		sl.Scope().SetName("scope")
+		sl.LogRecords().AppendEmpty().Body().SetStr(tenant + " app")
+		audit := sl.LogRecords().AppendEmpty()
+		audit.Body().SetStr(tenant + " audit")
+		audit.Attributes().PutStr("log.type", "audit")
+	}
+
+	require.NoError(t, conn.ConsumeLogs(context.Background(), l))
+
+	// the input is not modified
+	assert.Equal(t, 2, l.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().Len())
+
+	bodies := func(sink *consumertest.LogsSink) map[string][]string {
+		res := make(map[string][]string)
+		for _, logs := range sink.AllLogs() {
+			for i := 0; i < logs.ResourceLogs().Len(); i++ {
+				rl := logs.ResourceLogs().At(i)
+				tenant, _ := rl.Resource().Attributes().Get("X-Tenant")
+				sl := rl.ScopeLogs().At(0)
+				assert.Equal(t, "scope", sl.Scope().Name())
+				for j := 0; j < sl.LogRecords().Len(); j++ {
+					res[tenant.Str()] = append(res[tenant.Str()], sl.LogRecords().At(j).Body().Str())
+				}
+			}
+		}
+		return res
+	}
+
+	require.Len(t, auditSink.AllLogs(), 1)
+	assert.Equal(t, map[string][]string{"acme": {"acme audit"}, "ecorp": {"ecorp audit"}}, bodies(&auditSink))
+	assert.Equal(t, map[string][]string{"acme": {"acme app"}}, bodies(&acmeSink))
+	assert.Equal(t, map[string][]string{"ecorp": {"ecorp app"}}, bodies(&defaultSink))
+}
+
+func TestLogsRecordFailingStatementIsRoutedToDefault(t *testing.T) {
+	logsDefault := component.NewIDWithName(component.DataTypeLogs, "default")
+	logsAudit := component.NewIDWithName(component.DataTypeLogs, "audit")
+	logsAcme := component.NewIDWithName(component.DataTypeLogs, "acme")
+
+	cfg := &Config{
+		DefaultPipelines: []component.ID{logsDefault},
+		ErrorMode:        ottl.IgnoreError,
+		Table: []RoutingTableItem{
+			{
+				Statement: `route() where attributes["audit"]["kind"] == "login"`,
+				Context:   "log",
+				Pipelines: []component.ID{logsAudit},
+			},
+			{
+				Statement: `route() where attributes["X-Tenant"] == "acme"`,
+				Pipelines: []component.ID{logsAcme},
+			},
+		},
+		MatchOnce: true,
+	}
+	require.NoError(t, component.ValidateConfig(cfg))
+
+	var defaultSink, auditSink, acmeSink consumertest.LogsSink
+
+	router := connector.NewLogsRouter(map[component.ID]consumer.Logs{
+		logsDefault: &defaultSink,
+		logsAudit:   &auditSink,
+		logsAcme:    &acmeSink,
+	})
+
+	conn, err := NewFactory().CreateLogsToLogs(
+		context.Background(),
+		connectortest.NewNopSettings(),
+		cfg,
+		router.(consumer.Logs),
+	)
+	require.NoError(t, err)
+
+	l := plog.NewLogs()
+	rl := l.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("X-Tenant", "acme")
+	sl := rl.ScopeLogs().AppendEmpty()
+	login := sl.LogRecords().AppendEmpty()
+	login.Body().SetStr("login")
+	login.Attributes().PutEmptyMap("audit").PutStr("kind", "login")
+	// the statement fails on this record, which can't be indexed
+	invalid := sl.LogRecords().AppendEmpty()
+	invalid.Body().SetStr("invalid")
+	invalid.Attributes().PutStr("audit", "login")
+	sl.LogRecords().AppendEmpty().Body().SetStr("app")
+
+	require.NoError(t, conn.ConsumeLogs(context.Background(), l))
+
+	bodies := func(sink *consumertest.LogsSink) []string {
+		var res []string
+		for _, logs := range sink.AllLogs() {
+			records := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+			for i := 0; i < records.Len(); i++ {
+				res = append(res, records.At(i).Body().Str())
+			}
+		}
+		return res
+	}
+
+	// the record failing the statement is routed to the default pipelines, rather than to the following routes
+	assert.Equal(t, []string{"login"}, bodies(&auditSink))
+	assert.Equal(t, []string{"app"}, bodies(&acmeSink))
+	assert.Equal(t, []string{"invalid"}, bodies(&defaultSink))
+}
+
+func TestLogsUnsupportedContext(t *testing.T) {
+	cfg := &Config{
+		Table: []RoutingTableItem{
+			{
+				Statement: `route() where attributes["a"] == "b"`,
+				Context:   "span",
+				Pipelines: []component.ID{component.NewIDWithName(component.DataTypeLogs, "0")},
+			},
+		},
+		MatchOnce: true,
+	}
+
+	router := connector.NewLogsRouter(map[component.ID]consumer.Logs{
+		component.NewIDWithName(component.DataTypeLogs, "0"): consumertest.NewNop(),
+	})
+
+	_, err := NewFactory().CreateLogsToLogs(context.Background(), connectortest.NewNopSettings(), cfg, router.(consumer.Logs))
+	assert.EqualError(t, err, `invalid route: context "span" is not supported for this signal, use "resource" or "log"`)
+}
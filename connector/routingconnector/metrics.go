@@ -13,7 +13,9 @@ import (
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/routingconnector/internal/pmetricutil"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlresource"
 )
 
@@ -33,6 +35,10 @@ func newMetricsConnector(
 ) (*metricsConnector, error) {
 	cfg := config.(*Config)
 
+	if err := validateContexts(cfg.Table, dataPointContext); err != nil {
+		return nil, err
+	}
+
 	mr, ok := metrics.(connector.MetricsRouterAndConsumer)
 	if !ok {
 		return nil, errUnexpectedConsumer
@@ -60,6 +66,10 @@ func (c *metricsConnector) Capabilities() consumer.Capabilities {
 }
 
 func (c *metricsConnector) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	if c.config.MatchOnce && c.router.hasRecordRoutes() {
+		return c.switchMetrics(ctx, md)
+	}
+
 	// groups is used to group pmetric.ResourceMetrics that are routed to
 	// the same set of exporters. This way we're not ending up with all the
 	// metrics split up which would cause higher CPU usage.
@@ -118,3 +128,70 @@ func (c *metricsConnector) group(
 	metrics.CopyTo(group.ResourceMetrics().AppendEmpty())
 	groups[consumer] = group
 }
+
+// switchMetrics routes every resource or data point to the pipelines of the first route
+// which statement matches it. Routed data points are regrouped under a copy of their
+// resource and scope, and what is not routed goes to the default pipelines.
+func (c *metricsConnector) switchMetrics(ctx context.Context, md pmetric.Metrics) error {
+	// the routed data is moved out of a copy of the payload, so that it is
+	// evaluated by the following routes only if it was not matched.
+	remaining := pmetric.NewMetrics()
+	md.CopyTo(remaining)
+
+	// the data failing the statement of a route is routed to the default pipelines, like the
+	// resources failing the statements when routing whole resources.
+	failed := pmetric.NewMetrics()
+	groups := make(map[consumer.Metrics]pmetric.Metrics)
+	for _, route := range c.router.routeSlice {
+		matched := pmetric.NewMetrics()
+		var results matchResults
+		switch route.statementContext {
+		case resourceContext:
+			pmetricutil.MoveResourcesIf(remaining, failed, func(rm pmetric.ResourceMetrics) bool {
+				rtx := ottlresource.NewTransformContext(rm.Resource())
+				_, isMatch, err := route.statement.Execute(ctx, rtx)
+				return results.add(isMatch, err)
+			})
+			pmetricutil.MoveResourcesIf(remaining, matched, func(pmetric.ResourceMetrics) bool {
+				return results.next()
+			})
+		case dataPointContext:
+			pmetricutil.MoveDataPointsWithContextIf(remaining, failed, func(rm pmetric.ResourceMetrics, sm pmetric.ScopeMetrics, m pmetric.Metric, dp any) bool {
+				tCtx := ottldatapoint.NewTransformContext(dp, m, sm.Metrics(), sm.Scope(), rm.Resource())
+				_, isMatch, err := route.dataPointStatement.Execute(ctx, tCtx)
+				return results.add(isMatch, err)
+			})
+			pmetricutil.MoveDataPointsWithContextIf(remaining, matched, func(pmetric.ResourceMetrics, pmetric.ScopeMetrics, pmetric.Metric, any) bool {
+				return results.next()
+			})
+		}
+		if results.errs != nil && c.config.ErrorMode == ottl.PropagateError {
+			return results.errs
+		}
+		c.groupAll(groups, route.consumer, matched)
+	}
+	c.groupAll(groups, c.router.defaultConsumer, failed)
+	c.groupAll(groups, c.router.defaultConsumer, remaining)
+
+	var errs error
+	for consumer, group := range groups {
+		errs = errors.Join(errs, consumer.ConsumeMetrics(ctx, group))
+	}
+	return errs
+}
+
+func (c *metricsConnector) groupAll(
+	groups map[consumer.Metrics]pmetric.Metrics,
+	consumer consumer.Metrics,
+	md pmetric.Metrics,
+) {
+	if consumer == nil || md.ResourceMetrics().Len() == 0 {
+		return
+	}
+	group, ok := groups[consumer]
+	if !ok {
+		groups[consumer] = md
+		return
+	}
+	md.ResourceMetrics().MoveAndAppendTo(group.ResourceMetrics())
+}
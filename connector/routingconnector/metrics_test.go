@@ -495,3 +495,62 @@ func TestMetricsConnectorCapabilities(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, false, conn.Capabilities().MutatesData)
 }
+
+func TestMetricsAreCorrectlyRoutedPerDataPoint(t *testing.T) {
+	metricsDefault := component.NewIDWithName(component.DataTypeMetrics, "default")
+	metricsCanary := component.NewIDWithName(component.DataTypeMetrics, "canary")
+
+	cfg := &Config{
+		DefaultPipelines: []component.ID{metricsDefault},
+		Table: []RoutingTableItem{
+			{
+				Statement: `route() where attributes["deployment"] == "canary"`,
+				Context:   "datapoint",
+				Pipelines: []component.ID{metricsCanary},
+			},
+		},
+		MatchOnce: true,
+	}
+
+	var defaultSink, canarySink consumertest.MetricsSink
+
+	router := connector.NewMetricsRouter(map[component.ID]consumer.Metrics{
+		metricsDefault: &defaultSink,
+		metricsCanary:  &canarySink,
+	})
+
+	conn, err := NewFactory().CreateMetricsToMetrics(
+		context.Background(),
+		connectortest.NewNopSettings(),
+		cfg,
+		router.(consumer.Metrics),
+	)
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	m := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	m.SetName("requests")
+	m.SetUnit("1")
+	sum := m.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	for i, deployment := range []string{"stable", "canary", "stable"} {
+		dp := sum.DataPoints().AppendEmpty()
+		dp.SetIntValue(int64(i))
+		dp.Attributes().PutStr("deployment", deployment)
+	}
+
+	require.NoError(t, conn.ConsumeMetrics(context.Background(), md))
+
+	require.Len(t, canarySink.AllMetrics(), 1)
+	routed := canarySink.AllMetrics()[0].ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "requests", routed.Name())
+	assert.Equal(t, "1", routed.Unit())
+	assert.True(t, routed.Sum().IsMonotonic())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, routed.Sum().AggregationTemporality())
+	require.Equal(t, 1, routed.Sum().DataPoints().Len())
+	assert.Equal(t, int64(1), routed.Sum().DataPoints().At(0).IntValue())
+
+	require.Len(t, defaultSink.AllMetrics(), 1)
+	assert.Equal(t, 2, defaultSink.AllMetrics()[0].DataPointCount())
+}
@@ -13,7 +13,10 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/routingconnector/internal/common"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottldatapoint"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlresource"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 )
 
 var errPipelineNotFound = errors.New("pipeline not found")
@@ -27,8 +30,11 @@ type consumerProvider[C any] func(...component.ID) (C, error)
 // parameter C is expected to be one of: consumer.Traces, consumer.Metrics, or
 // consumer.Logs.
 type router[C any] struct {
-	logger *zap.Logger
-	parser ottl.Parser[ottlresource.TransformContext]
+	logger          *zap.Logger
+	parser          ottl.Parser[ottlresource.TransformContext]
+	spanParser      ottl.Parser[ottlspan.TransformContext]
+	logParser       ottl.Parser[ottllog.TransformContext]
+	dataPointParser ottl.Parser[ottldatapoint.TransformContext]
 
 	table      []RoutingTableItem
 	routes     map[string]routingItem[C]
@@ -55,9 +61,27 @@ func newRouter[C any](
 		return nil, err
 	}
 
+	spanParser, err := ottlspan.NewParser(common.Functions[ottlspan.TransformContext](), settings)
+	if err != nil {
+		return nil, err
+	}
+
+	logParser, err := ottllog.NewParser(common.Functions[ottllog.TransformContext](), settings)
+	if err != nil {
+		return nil, err
+	}
+
+	dataPointParser, err := ottldatapoint.NewParser(common.Functions[ottldatapoint.TransformContext](), settings)
+	if err != nil {
+		return nil, err
+	}
+
 	r := &router[C]{
 		logger:           settings.Logger,
 		parser:           parser,
+		spanParser:       spanParser,
+		logParser:        logParser,
+		dataPointParser:  dataPointParser,
 		table:            table,
 		routes:           make(map[string]routingItem[C]),
 		consumerProvider: provider,
@@ -71,8 +95,49 @@ func newRouter[C any](
 }
 
 type routingItem[C any] struct {
-	consumer  C
-	statement *ottl.Statement[ottlresource.TransformContext]
+	consumer           C
+	statementContext   string
+	statement          *ottl.Statement[ottlresource.TransformContext]
+	spanStatement      *ottl.Statement[ottlspan.TransformContext]
+	logStatement       *ottl.Statement[ottllog.TransformContext]
+	dataPointStatement *ottl.Statement[ottldatapoint.TransformContext]
+}
+
+// matchResults records the results of the statement of a route, so that the data failing the
+// statement and the data matching it are moved out in two passes over the same data, while the
+// statement is executed once.
+type matchResults struct {
+	matches []bool
+	errs    error
+}
+
+// add records the result of the statement for the next data, and returns whether it failed.
+func (r *matchResults) add(isMatch bool, err error) bool {
+	if err != nil {
+		r.errs = errors.Join(r.errs, err)
+		return true
+	}
+	r.matches = append(r.matches, isMatch)
+	return false
+}
+
+// next returns whether the next data which didn't fail the statement matched it, in the order
+// the results were added.
+func (r *matchResults) next() bool {
+	isMatch := r.matches[0]
+	r.matches = r.matches[1:]
+	return isMatch
+}
+
+// hasRecordRoutes returns true if at least one route evaluates its statement
+// for every span, log record or data point instead of every resource.
+func (r *router[C]) hasRecordRoutes() bool {
+	for _, route := range r.routeSlice {
+		if route.statementContext != resourceContext {
+			return true
+		}
+	}
+	return false
 }
 
 func (r *router[C]) registerConsumers(defaultPipelineIDs []component.ID) error {
@@ -112,14 +177,11 @@ func (r *router[C]) registerDefaultConsumer(pipelineIDs []component.ID) error {
 // for each route
 func (r *router[C]) registerRouteConsumers() error {
 	for _, item := range r.table {
-		statement, err := r.getStatementFrom(item)
-		if err != nil {
-			return err
-		}
-
 		route, ok := r.routes[key(item)]
 		if !ok {
-			route.statement = statement
+			if err := r.parseStatement(&route, item); err != nil {
+				return err
+			}
 		} else {
 			pipelineNames := []string{}
 			for _, pipeline := range item.Pipelines {
@@ -143,21 +205,28 @@ func (r *router[C]) registerRouteConsumers() error {
 	return nil
 }
 
-// getStatementFrom builds a routing OTTL statement from the provided
-// routing table entry configuration. If the routing table entry configuration
-// does not contain a valid OTTL statement then nil is returned.
-func (r *router[C]) getStatementFrom(item RoutingTableItem) (*ottl.Statement[ottlresource.TransformContext], error) {
-	var statement *ottl.Statement[ottlresource.TransformContext]
-	if item.Statement != "" {
-		var err error
-		statement, err = r.parser.ParseStatement(item.Statement)
-		if err != nil {
-			return statement, err
-		}
+// parseStatement parses the statement of the routing table entry with the
+// parser of its context.
+func (r *router[C]) parseStatement(route *routingItem[C], item RoutingTableItem) error {
+	var err error
+	route.statementContext = item.Context
+	switch item.Context {
+	case spanContext:
+		route.spanStatement, err = r.spanParser.ParseStatement(item.Statement)
+	case logContext:
+		route.logStatement, err = r.logParser.ParseStatement(item.Statement)
+	case dataPointContext:
+		route.dataPointStatement, err = r.dataPointParser.ParseStatement(item.Statement)
+	default:
+		route.statementContext = resourceContext
+		route.statement, err = r.parser.ParseStatement(item.Statement)
 	}
-	return statement, nil
+	return err
 }
 
 func key(entry RoutingTableItem) string {
-	return entry.Statement
+	if entry.Context == "" || entry.Context == resourceContext {
+		return entry.Statement
+	}
+	return entry.Context + ":" + entry.Statement
 }
@@ -13,8 +13,10 @@ import (
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/routingconnector/internal/ptraceutil"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlresource"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 )
 
 type tracesConnector struct {
@@ -33,6 +35,10 @@ func newTracesConnector(
 ) (*tracesConnector, error) {
 	cfg := config.(*Config)
 
+	if err := validateContexts(cfg.Table, spanContext); err != nil {
+		return nil, err
+	}
+
 	tr, ok := traces.(connector.TracesRouterAndConsumer)
 	if !ok {
 		return nil, errUnexpectedConsumer
@@ -60,6 +66,10 @@ func (*tracesConnector) Capabilities() consumer.Capabilities {
 }
 
 func (c *tracesConnector) ConsumeTraces(ctx context.Context, t ptrace.Traces) error {
+	if c.config.MatchOnce && c.router.hasRecordRoutes() {
+		return c.switchTraces(ctx, t)
+	}
+
 	// groups is used to group ptrace.ResourceSpans that are routed to
 	// the same set of pipelines. This way we're not ending up with all the
 	// spans split up which would cause higher CPU usage.
@@ -117,3 +127,70 @@ func (c *tracesConnector) group(
 	spans.CopyTo(group.ResourceSpans().AppendEmpty())
 	groups[consumer] = group
 }
+
+// switchTraces routes every resource or span to the pipelines of the first route
+// which statement matches it. Routed spans are regrouped under a copy of their
+// resource and scope, and what is not routed goes to the default pipelines.
+func (c *tracesConnector) switchTraces(ctx context.Context, t ptrace.Traces) error {
+	// the routed data is moved out of a copy of the payload, so that it is
+	// evaluated by the following routes only if it was not matched.
+	remaining := ptrace.NewTraces()
+	t.CopyTo(remaining)
+
+	// the data failing the statement of a route is routed to the default pipelines, like the
+	// resources failing the statements when routing whole resources.
+	failed := ptrace.NewTraces()
+	groups := make(map[consumer.Traces]ptrace.Traces)
+	for _, route := range c.router.routeSlice {
+		matched := ptrace.NewTraces()
+		var results matchResults
+		switch route.statementContext {
+		case resourceContext:
+			ptraceutil.MoveResourcesIf(remaining, failed, func(rs ptrace.ResourceSpans) bool {
+				rtx := ottlresource.NewTransformContext(rs.Resource())
+				_, isMatch, err := route.statement.Execute(ctx, rtx)
+				return results.add(isMatch, err)
+			})
+			ptraceutil.MoveResourcesIf(remaining, matched, func(ptrace.ResourceSpans) bool {
+				return results.next()
+			})
+		case spanContext:
+			ptraceutil.MoveSpansWithContextIf(remaining, failed, func(rs ptrace.ResourceSpans, ss ptrace.ScopeSpans, span ptrace.Span) bool {
+				tCtx := ottlspan.NewTransformContext(span, ss.Scope(), rs.Resource())
+				_, isMatch, err := route.spanStatement.Execute(ctx, tCtx)
+				return results.add(isMatch, err)
+			})
+			ptraceutil.MoveSpansWithContextIf(remaining, matched, func(ptrace.ResourceSpans, ptrace.ScopeSpans, ptrace.Span) bool {
+				return results.next()
+			})
+		}
+		if results.errs != nil && c.config.ErrorMode == ottl.PropagateError {
+			return results.errs
+		}
+		c.groupAll(groups, route.consumer, matched)
+	}
+	c.groupAll(groups, c.router.defaultConsumer, failed)
+	c.groupAll(groups, c.router.defaultConsumer, remaining)
+
+	var errs error
+	for consumer, group := range groups {
+		errs = errors.Join(errs, consumer.ConsumeTraces(ctx, group))
+	}
+	return errs
+}
+
+func (c *tracesConnector) groupAll(
+	groups map[consumer.Traces]ptrace.Traces,
+	consumer consumer.Traces,
+	t ptrace.Traces,
+) {
+	if consumer == nil || t.ResourceSpans().Len() == 0 {
+		return
+	}
+	group, ok := groups[consumer]
+	if !ok {
+		groups[consumer] = t
+		return
+	}
+	t.ResourceSpans().MoveAndAppendTo(group.ResourceSpans())
+}
@@ -419,3 +419,61 @@ func TestTraceConnectorCapabilities(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, false, conn.Capabilities().MutatesData)
 }
+
+func TestTracesAreCorrectlyRoutedPerSpan(t *testing.T) {
+	tracesDefault := component.NewIDWithName(component.DataTypeTraces, "default")
+	tracesErrors := component.NewIDWithName(component.DataTypeTraces, "errors")
+
+	cfg := &Config{
+		DefaultPipelines: []component.ID{tracesDefault},
+		Table: []RoutingTableItem{
+			{
+				Statement: `route() where status.code == STATUS_CODE_ERROR`,
+				Context:   "span",
+				Pipelines: []component.ID{tracesErrors},
+			},
+		},
+		MatchOnce: true,
+	}
+
+	var defaultSink, errorsSink consumertest.TracesSink
+
+	router := connector.NewTracesRouter(map[component.ID]consumer.Traces{
+		tracesDefault: &defaultSink,
+		tracesErrors:  &errorsSink,
+	})
+
+	conn, err := NewFactory().CreateTracesToTraces(
+		context.Background(),
+		connectortest.NewNopSettings(),
+		cfg,
+		router.(consumer.Traces),
+	)
+	require.NoError(t, err)
+
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	ss := rs.ScopeSpans().AppendEmpty()
+	ss.Spans().AppendEmpty().SetName("ok")
+	failed := ss.Spans().AppendEmpty()
+	failed.SetName("failed")
+	failed.Status().SetCode(ptrace.StatusCodeError)
+	// a resource without errors is not split
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName("other")
+
+	require.NoError(t, conn.ConsumeTraces(context.Background(), td))
+
+	require.Len(t, errorsSink.AllTraces(), 1)
+	routed := errorsSink.AllTraces()[0]
+	require.Equal(t, 1, routed.SpanCount())
+	assert.Equal(t, "failed", routed.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+	serviceName, _ := routed.ResourceSpans().At(0).Resource().Attributes().Get("service.name")
+	assert.Equal(t, "checkout", serviceName.Str())
+
+	require.Len(t, defaultSink.AllTraces(), 1)
+	remaining := defaultSink.AllTraces()[0]
+	assert.Equal(t, 2, remaining.ResourceSpans().Len())
+	assert.Equal(t, "ok", remaining.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+	assert.Equal(t, "other", remaining.ResourceSpans().At(1).ScopeSpans().At(0).Spans().At(0).Name())
+}
@@ -44,6 +44,9 @@ type Config struct {
 	// DatabaseNameAttribute is the attribute name used to identify the database name from span attributes.
 	// The default value is db.name.
 	DatabaseNameAttribute string `mapstructure:"database_name_attribute"`
+
+	// MessagingSystem contains the config for the detection of edges going through messaging systems.
+	MessagingSystem MessagingSystemConfig `mapstructure:"messaging_system"`
 }
 
 type StoreConfig struct {
@@ -52,3 +55,18 @@ type StoreConfig struct {
 	// TTL is the time to live for items in the store.
 	TTL time.Duration `mapstructure:"ttl"`
 }
+
+type MessagingSystemConfig struct {
+	// Enabled turns producer and consumer spans carrying a messaging destination into edges to and from
+	// a virtual node representing the destination, instead of pairing the producer and consumer spans directly.
+	Enabled bool `mapstructure:"enabled"`
+	// DestinationAttributes is the list of span attributes identifying the destination, the higher the front, the higher the priority.
+	// The default value is messaging.destination.name, messaging.destination.
+	DestinationAttributes []string `mapstructure:"destination_attributes"`
+	// SystemAttribute is the attribute name used to identify the messaging system, prefixing the name of the destination node.
+	// The default value is messaging.system.
+	SystemAttribute string `mapstructure:"system_attribute"`
+	// UseSpanLinks pairs consumer spans with the producer spans they link to, instead of their parent span.
+	// This allows batch consumers, whose spans link to the producer spans of several traces, to be paired.
+	UseSpanLinks bool `mapstructure:"use_span_links"`
+}
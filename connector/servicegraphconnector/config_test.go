@@ -40,6 +40,11 @@ func TestLoadConfig(t *testing.T) {
 			CacheLoop:             time.Minute,
 			StoreExpirationLoop:   2 * time.Second,
 			DatabaseNameAttribute: "db.name",
+			MessagingSystem: MessagingSystemConfig{
+				Enabled:               true,
+				DestinationAttributes: []string{"messaging.destination.name"},
+				UseSpanLinks:          true,
+			},
 		},
 		cfg.Connectors[component.NewID(metadata.Type)],
 	)
@@ -44,6 +44,11 @@ var (
 	}
 
 	defaultDatabaseNameAttribute = semconv.AttributeDBName
+
+	defaultMessagingDestinationAttributes = []string{
+		"messaging.destination.name", semconv.AttributeMessagingDestination,
+	}
+	defaultMessagingSystemAttribute = semconv.AttributeMessagingSystem
 )
 
 type metricSeries struct {
@@ -62,16 +67,19 @@ type serviceGraphConnector struct {
 
 	startTime time.Time
 
-	seriesMutex                          sync.Mutex
-	reqTotal                             map[string]int64
-	reqFailedTotal                       map[string]int64
-	reqClientDurationSecondsCount        map[string]uint64
-	reqClientDurationSecondsSum          map[string]float64
-	reqClientDurationSecondsBucketCounts map[string][]uint64
-	reqServerDurationSecondsCount        map[string]uint64
-	reqServerDurationSecondsSum          map[string]float64
-	reqServerDurationSecondsBucketCounts map[string][]uint64
-	reqDurationBounds                    []float64
+	seriesMutex                           sync.Mutex
+	reqTotal                              map[string]int64
+	reqFailedTotal                        map[string]int64
+	reqClientDurationSecondsCount         map[string]uint64
+	reqClientDurationSecondsSum           map[string]float64
+	reqClientDurationSecondsBucketCounts  map[string][]uint64
+	reqServerDurationSecondsCount         map[string]uint64
+	reqServerDurationSecondsSum           map[string]float64
+	reqServerDurationSecondsBucketCounts  map[string][]uint64
+	reqDurationBounds                     []float64
+	reqMessagingSystemSecondsCount        map[string]uint64
+	reqMessagingSystemSecondsSum          map[string]float64
+	reqMessagingSystemSecondsBucketCounts map[string][]uint64
 
 	metricMutex sync.RWMutex
 	keyToMetric map[string]metricSeries
@@ -112,6 +120,14 @@ func newConnector(set component.TelemetrySettings, config component.Config, next
 		pConfig.DatabaseNameAttribute = defaultDatabaseNameAttribute
 	}
 
+	if pConfig.MessagingSystem.DestinationAttributes == nil {
+		pConfig.MessagingSystem.DestinationAttributes = defaultMessagingDestinationAttributes
+	}
+
+	if pConfig.MessagingSystem.SystemAttribute == "" {
+		pConfig.MessagingSystem.SystemAttribute = defaultMessagingSystemAttribute
+	}
+
 	telemetryBuilder, err := metadata.NewTelemetryBuilder(set)
 	if err != nil {
 		return nil, err
@@ -122,19 +138,22 @@ func newConnector(set component.TelemetrySettings, config component.Config, next
 		logger:          set.Logger,
 		metricsConsumer: next,
 
-		startTime:                            time.Now(),
-		reqTotal:                             make(map[string]int64),
-		reqFailedTotal:                       make(map[string]int64),
-		reqClientDurationSecondsCount:        make(map[string]uint64),
-		reqClientDurationSecondsSum:          make(map[string]float64),
-		reqClientDurationSecondsBucketCounts: make(map[string][]uint64),
-		reqServerDurationSecondsCount:        make(map[string]uint64),
-		reqServerDurationSecondsSum:          make(map[string]float64),
-		reqServerDurationSecondsBucketCounts: make(map[string][]uint64),
-		reqDurationBounds:                    bounds,
-		keyToMetric:                          make(map[string]metricSeries),
-		shutdownCh:                           make(chan any),
-		telemetryBuilder:                     telemetryBuilder,
+		startTime:                             time.Now(),
+		reqTotal:                              make(map[string]int64),
+		reqFailedTotal:                        make(map[string]int64),
+		reqClientDurationSecondsCount:         make(map[string]uint64),
+		reqClientDurationSecondsSum:           make(map[string]float64),
+		reqClientDurationSecondsBucketCounts:  make(map[string][]uint64),
+		reqServerDurationSecondsCount:         make(map[string]uint64),
+		reqServerDurationSecondsSum:           make(map[string]float64),
+		reqServerDurationSecondsBucketCounts:  make(map[string][]uint64),
+		reqDurationBounds:                     bounds,
+		reqMessagingSystemSecondsCount:        make(map[string]uint64),
+		reqMessagingSystemSecondsSum:          make(map[string]float64),
+		reqMessagingSystemSecondsBucketCounts: make(map[string][]uint64),
+		keyToMetric:                           make(map[string]metricSeries),
+		shutdownCh:                            make(chan any),
+		telemetryBuilder:                      telemetryBuilder,
 	}, nil
 }
 
@@ -240,6 +259,10 @@ func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.
 
 				switch span.Kind() {
 				case ptrace.SpanKindProducer:
+					if queueNode, ok := p.findQueueNode(rAttributes, span.Attributes()); ok {
+						isNew, err = p.upsertPublishEdges(ctx, serviceName, queueNode, rAttributes, span)
+						break
+					}
 					// override connection type and continue processing as span kind client
 					connectionType = store.MessagingSystem
 					fallthrough
@@ -267,6 +290,10 @@ func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.
 						}
 					})
 				case ptrace.SpanKindConsumer:
+					if p.config.MessagingSystem.Enabled {
+						isNew, err = p.upsertConsumeEdges(serviceName, rAttributes, span)
+						break
+					}
 					// override connection type and continue processing as span kind server
 					connectionType = store.MessagingSystem
 					fallthrough
@@ -306,6 +333,101 @@ func (p *serviceGraphConnector) aggregateMetrics(ctx context.Context, td ptrace.
 	return nil
 }
 
+// findQueueNode returns the name of the virtual node representing the messaging destination of the span.
+// The name is prefixed by the messaging system, if known.
+func (p *serviceGraphConnector) findQueueNode(resourceAttr pcommon.Map, spanAttr pcommon.Map) (string, bool) {
+	if !p.config.MessagingSystem.Enabled {
+		return "", false
+	}
+	for _, attr := range p.config.MessagingSystem.DestinationAttributes {
+		destination, ok := findAttributeValue(attr, spanAttr)
+		if !ok {
+			continue
+		}
+		if system, ok := findAttributeValue(p.config.MessagingSystem.SystemAttribute, spanAttr, resourceAttr); ok {
+			return system + "/" + destination, true
+		}
+		return destination, true
+	}
+	return "", false
+}
+
+// upsertPublishEdges records the edges of a message published to a queue node: the edge from the producer
+// to the queue node, which is complete right away, and the edge from the queue node to the consumer,
+// which is completed once the consumer span is found.
+func (p *serviceGraphConnector) upsertPublishEdges(ctx context.Context, serviceName, queueNode string, rAttributes pcommon.Map, span ptrace.Span) (bool, error) {
+	traceID := span.TraceID()
+
+	publish := &store.Edge{
+		Key:              store.NewKey(traceID, span.SpanID()),
+		TraceID:          traceID,
+		ConnectionType:   store.MessagingSystem,
+		ClientService:    serviceName,
+		ServerService:    queueNode,
+		ClientLatencySec: spanDuration(span),
+		ServerLatencySec: spanDuration(span),
+		Failed:           span.Status().Code() == ptrace.StatusCodeError,
+		Dimensions:       make(map[string]string),
+		Peer:             make(map[string]string),
+	}
+	p.upsertDimensions(clientKind, publish.Dimensions, rAttributes, span.Attributes())
+
+	isNew, err := p.store.UpsertEdge(store.NewKey(traceID, span.SpanID()), func(e *store.Edge) {
+		e.TraceID = traceID
+		e.ConnectionType = store.MessagingSystem
+		e.ClientService = queueNode
+		e.QueueNode = queueNode
+		e.PublishEndTime = span.EndTimestamp()
+	})
+	if err != nil {
+		// the span is dropped, so neither edge is recorded
+		return false, err
+	}
+	p.onComplete(publish)
+	p.telemetryBuilder.ConnectorServicegraphTotalEdges.Add(ctx, 1)
+	return isNew, nil
+}
+
+// upsertConsumeEdges records the edges from a queue node to the consumer of a message. The consumer span is
+// paired with its parent span or, when enabled, with the producer spans it links to.
+func (p *serviceGraphConnector) upsertConsumeEdges(serviceName string, rAttributes pcommon.Map, span ptrace.Span) (isNew bool, err error) {
+	queueNode, _ := p.findQueueNode(rAttributes, span.Attributes())
+
+	update := func(traceID pcommon.TraceID) store.Callback {
+		return func(e *store.Edge) {
+			e.TraceID = traceID
+			e.ConnectionType = store.MessagingSystem
+			e.ServerService = serviceName
+			e.ServerLatencySec = spanDuration(span)
+			e.ConsumeStartTime = span.StartTimestamp()
+			e.Failed = e.Failed || span.Status().Code() == ptrace.StatusCodeError
+			if e.QueueNode == "" {
+				e.QueueNode = queueNode
+			}
+			// The queue node doesn't report its own latency, use the consumer one for both sides.
+			// A producer span not publishing to a queue node overrides it with its own latency.
+			if e.ClientService == "" || e.ClientService == e.QueueNode {
+				e.ClientLatencySec = e.ServerLatencySec
+			}
+			p.upsertDimensions(serverKind, e.Dimensions, rAttributes, span.Attributes())
+		}
+	}
+
+	if !p.config.MessagingSystem.UseSpanLinks || span.Links().Len() == 0 {
+		return p.store.UpsertEdge(store.NewKey(span.TraceID(), span.ParentSpanID()), update(span.TraceID()))
+	}
+
+	for l := 0; l < span.Links().Len(); l++ {
+		link := span.Links().At(l)
+		linkIsNew, linkErr := p.store.UpsertEdge(store.NewKey(link.TraceID(), link.SpanID()), update(link.TraceID()))
+		if linkErr != nil {
+			return isNew, linkErr
+		}
+		isNew = isNew || linkIsNew
+	}
+	return isNew, nil
+}
+
 func (p *serviceGraphConnector) upsertDimensions(kind string, m map[string]string, resourceAttr pcommon.Map, spanAttr pcommon.Map) {
 	for _, dim := range p.config.Dimensions {
 		if v, ok := findAttributeValue(dim, resourceAttr, spanAttr); ok {
@@ -345,6 +467,16 @@ func (p *serviceGraphConnector) onExpire(e *store.Edge) {
 
 	p.telemetryBuilder.ConnectorServicegraphExpiredEdges.Add(context.Background(), 1)
 
+	if e.ConnectionType == store.MessagingSystem && e.QueueNode != "" {
+		// The producer span was not found, the queue node stands in for it.
+		// If instead the consumer span was not found, the message is not (yet) consumed and there is no edge.
+		if len(e.ClientService) == 0 && len(e.ServerService) != 0 {
+			e.ClientService = e.QueueNode
+			p.onComplete(e)
+		}
+		return
+	}
+
 	if virtualNodeFeatureGate.IsEnabled() && len(p.config.VirtualNodePeerAttributes) > 0 {
 		e.ConnectionType = store.VirtualNode
 		if len(e.ClientService) == 0 && e.Key.SpanIDIsEmpty() {
@@ -371,6 +503,9 @@ func (p *serviceGraphConnector) aggregateMetricsForEdge(e *store.Edge) {
 		p.updateErrorMetrics(metricKey)
 	}
 	p.updateDurationMetrics(metricKey, e.ServerLatencySec, e.ClientLatencySec)
+	if e.PublishEndTime != 0 && e.ConsumeStartTime != 0 {
+		p.updateMessagingSystemMetrics(metricKey, messagingSystemLatency(e))
+	}
 }
 
 func (p *serviceGraphConnector) updateSeries(key string, dimensions pcommon.Map) {
@@ -422,6 +557,16 @@ func (p *serviceGraphConnector) updateClientDurationMetrics(key string, duration
 	p.reqClientDurationSecondsBucketCounts[key][index]++
 }
 
+func (p *serviceGraphConnector) updateMessagingSystemMetrics(key string, latency float64) {
+	index := sort.SearchFloat64s(p.reqDurationBounds, latency) // Search bucket index
+	if _, ok := p.reqMessagingSystemSecondsBucketCounts[key]; !ok {
+		p.reqMessagingSystemSecondsBucketCounts[key] = make([]uint64, len(p.reqDurationBounds)+1)
+	}
+	p.reqMessagingSystemSecondsSum[key] += latency
+	p.reqMessagingSystemSecondsCount[key]++
+	p.reqMessagingSystemSecondsBucketCounts[key][index]++
+}
+
 func buildDimensions(e *store.Edge) pcommon.Map {
 	dims := pcommon.NewMap()
 	dims.PutStr("client", e.ClientService)
@@ -451,6 +596,10 @@ func (p *serviceGraphConnector) buildMetrics() (pmetric.Metrics, error) {
 		return m, err
 	}
 
+	if err := p.collectMessagingSystemMetrics(ilm); err != nil {
+		return m, err
+	}
+
 	return m, nil
 }
 
@@ -567,6 +716,32 @@ func (p *serviceGraphConnector) collectServerLatencyMetrics(ilm pmetric.ScopeMet
 	return nil
 }
 
+func (p *serviceGraphConnector) collectMessagingSystemMetrics(ilm pmetric.ScopeMetrics) error {
+	for key := range p.reqMessagingSystemSecondsCount {
+		dimensions, ok := p.dimensionsForSeries(key)
+		if !ok {
+			return fmt.Errorf("failed to find dimensions for key %s", key)
+		}
+
+		timestamp := pcommon.NewTimestampFromTime(time.Now())
+
+		mLatency := ilm.Metrics().AppendEmpty()
+		mLatency.SetName("traces_service_graph_request_messaging_system_seconds")
+		// TODO: Support other aggregation temporalities
+		mLatency.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+
+		dpLatency := mLatency.Histogram().DataPoints().AppendEmpty()
+		dpLatency.SetStartTimestamp(pcommon.NewTimestampFromTime(p.startTime))
+		dpLatency.SetTimestamp(timestamp)
+		dpLatency.ExplicitBounds().FromRaw(p.reqDurationBounds)
+		dpLatency.BucketCounts().FromRaw(p.reqMessagingSystemSecondsBucketCounts[key])
+		dpLatency.SetCount(p.reqMessagingSystemSecondsCount[key])
+		dpLatency.SetSum(p.reqMessagingSystemSecondsSum[key])
+		dimensions.CopyTo(dpLatency.Attributes())
+	}
+	return nil
+}
+
 func (p *serviceGraphConnector) buildMetricKey(clientName, serverName, connectionType string, edgeDimensions map[string]string) string {
 	var metricKey strings.Builder
 	metricKey.WriteString(clientName + metricKeySeparator + serverName + metricKeySeparator + connectionType)
@@ -641,6 +816,9 @@ func (p *serviceGraphConnector) cleanCache() {
 		delete(p.reqServerDurationSecondsCount, key)
 		delete(p.reqServerDurationSecondsSum, key)
 		delete(p.reqServerDurationSecondsBucketCounts, key)
+		delete(p.reqMessagingSystemSecondsCount, key)
+		delete(p.reqMessagingSystemSecondsSum, key)
+		delete(p.reqMessagingSystemSecondsBucketCounts, key)
 	}
 	p.seriesMutex.Unlock()
 
@@ -659,6 +837,16 @@ func spanDuration(span ptrace.Span) float64 {
 	return float64(span.EndTimestamp()-span.StartTimestamp()) / float64(time.Second.Nanoseconds())
 }
 
+// messagingSystemLatency returns the time in seconds (legacy ms) between the publication of a message
+// and the start of its consumption.
+func messagingSystemLatency(e *store.Edge) float64 {
+	if e.ConsumeStartTime <= e.PublishEndTime {
+		// Clock skew between the producer and the consumer.
+		return 0
+	}
+	return durationToFloat(time.Duration(e.ConsumeStartTime - e.PublishEndTime))
+}
+
 // durationToFloat converts the given duration to the number of seconds (legacy ms) it represents.
 func durationToFloat(d time.Duration) float64 {
 	if legacyLatencyUnitMsFeatureGate.IsEnabled() {
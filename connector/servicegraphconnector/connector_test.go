@@ -27,6 +27,8 @@ import (
 	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 	"go.opentelemetry.io/otel/sdk/metric/metricdata/metricdatatest"
 	"go.uber.org/zap/zaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/servicegraphconnector/internal/store"
 )
 
 func TestConnectorStart(t *testing.T) {
@@ -372,6 +374,155 @@ func TestMapsAreConsistentDuringCleanup(t *testing.T) {
 	assert.NoError(t, p.Shutdown(context.Background()))
 }
 
+func TestMessagingSystemEdges(t *testing.T) {
+	tPublish := time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC)
+	tConsume := tPublish.Add(3 * time.Second)
+
+	traceID := pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	otherTraceID := pcommon.TraceID([16]byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1})
+	producerSpanID := pcommon.SpanID([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+
+	producerTrace := func() ptrace.Traces {
+		traces := ptrace.NewTraces()
+		rs := traces.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr(semconv.AttributeServiceName, "producer")
+		span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		span.SetTraceID(traceID)
+		span.SetSpanID(producerSpanID)
+		span.SetKind(ptrace.SpanKindProducer)
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(tPublish.Add(-time.Second)))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(tPublish))
+		span.Attributes().PutStr(semconv.AttributeMessagingSystem, "kafka")
+		span.Attributes().PutStr("messaging.destination.name", "orders")
+		return traces
+	}
+	consumerTrace := func(link bool) ptrace.Traces {
+		traces := ptrace.NewTraces()
+		rs := traces.ResourceSpans().AppendEmpty()
+		rs.Resource().Attributes().PutStr(semconv.AttributeServiceName, "consumer")
+		span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		span.SetTraceID(traceID)
+		span.SetParentSpanID(producerSpanID)
+		if link {
+			span.SetTraceID(otherTraceID)
+			span.SetParentSpanID(pcommon.SpanID{})
+			l := span.Links().AppendEmpty()
+			l.SetTraceID(traceID)
+			l.SetSpanID(producerSpanID)
+		}
+		span.SetSpanID(pcommon.SpanID([8]byte{8, 7, 6, 5, 4, 3, 2, 1}))
+		span.SetKind(ptrace.SpanKindConsumer)
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(tConsume))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(tConsume.Add(time.Second)))
+		span.Attributes().PutStr(semconv.AttributeMessagingSystem, "kafka")
+		span.Attributes().PutStr("messaging.destination.name", "orders")
+		return traces
+	}
+
+	tests := []struct {
+		name         string
+		useSpanLinks bool
+		maxItems     int
+		traces       []ptrace.Traces
+		wantEdges    []string
+		wantLatency  bool
+	}{
+		{
+			name:        "producer and consumer",
+			traces:      []ptrace.Traces{producerTrace(), consumerTrace(false)},
+			wantEdges:   []string{"producer->kafka/orders", "kafka/orders->consumer"},
+			wantLatency: true,
+		},
+		{
+			name:        "consumer before producer",
+			traces:      []ptrace.Traces{consumerTrace(false), producerTrace()},
+			wantEdges:   []string{"producer->kafka/orders", "kafka/orders->consumer"},
+			wantLatency: true,
+		},
+		{
+			name:         "batch consumer linking to the producer",
+			useSpanLinks: true,
+			traces:       []ptrace.Traces{producerTrace(), consumerTrace(true)},
+			wantEdges:    []string{"producer->kafka/orders", "kafka/orders->consumer"},
+			wantLatency:  true,
+		},
+		{
+			name:      "producer only",
+			traces:    []ptrace.Traces{producerTrace()},
+			wantEdges: []string{"producer->kafka/orders"},
+		},
+		{
+			name:      "consumer only",
+			traces:    []ptrace.Traces{consumerTrace(false)},
+			wantEdges: []string{"kafka/orders->consumer"},
+		},
+		{
+			// the producer span is dropped, so its edge to the queue node isn't recorded either
+			name:      "store full",
+			maxItems:  1,
+			traces:    []ptrace.Traces{consumerTrace(true), producerTrace()},
+			wantEdges: []string{"kafka/orders->consumer"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{
+				Store: StoreConfig{MaxItems: 10},
+				MessagingSystem: MessagingSystemConfig{
+					Enabled:      true,
+					UseSpanLinks: tt.useSpanLinks,
+				},
+			}
+			if tt.maxItems > 0 {
+				cfg.Store.MaxItems = tt.maxItems
+			}
+			set := componenttest.NewNopTelemetrySettings()
+			set.Logger = zaptest.NewLogger(t)
+			conn, err := newConnector(set, cfg, newMockMetricsExporter())
+			require.NoError(t, err)
+			require.NoError(t, conn.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() { require.NoError(t, conn.Shutdown(context.Background())) }()
+
+			for _, td := range tt.traces {
+				require.NoError(t, conn.ConsumeTraces(context.Background(), td))
+			}
+			conn.store.Expire()
+
+			md, err := conn.buildMetrics()
+			require.NoError(t, err)
+
+			var edges []string
+			latency := pmetric.NewMetric()
+			ms := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+			for i := 0; i < ms.Len(); i++ {
+				m := ms.At(i)
+				switch m.Name() {
+				case "traces_service_graph_request_total":
+					attrs := m.Sum().DataPoints().At(0).Attributes()
+					client, _ := attrs.Get("client")
+					server, _ := attrs.Get("server")
+					verifyAttr(t, attrs, "connection_type", string(store.MessagingSystem))
+					edges = append(edges, client.Str()+"->"+server.Str())
+				case "traces_service_graph_request_messaging_system_seconds":
+					latency = m
+				}
+			}
+			assert.ElementsMatch(t, tt.wantEdges, edges)
+
+			if !tt.wantLatency {
+				assert.Equal(t, pmetric.MetricTypeEmpty, latency.Type())
+				return
+			}
+			require.Equal(t, pmetric.MetricTypeHistogram, latency.Type())
+			dp := latency.Histogram().DataPoints().At(0)
+			assert.Equal(t, uint64(1), dp.Count())
+			assert.Equal(t, float64(3), dp.Sum())
+			verifyAttr(t, dp.Attributes(), "client", "kafka/orders")
+			verifyAttr(t, dp.Attributes(), "server", "consumer")
+		})
+	}
+}
+
 func setupTelemetry(reader *sdkmetric.ManualReader) component.TelemetrySettings {
 	settings := componenttest.NewNopTelemetrySettings()
 	settings.MetricsLevel = configtelemetry.LevelNormal
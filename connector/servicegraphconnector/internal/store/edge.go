@@ -38,6 +38,13 @@ type Edge struct {
 	expiration time.Time
 
 	Peer map[string]string
+
+	// QueueNode is the name of the virtual node representing the messaging destination
+	// the edge goes through, if any.
+	QueueNode string
+	// PublishEndTime is the end time of the producer span of a messaging edge,
+	// and ConsumeStartTime the start time of its consumer span.
+	PublishEndTime, ConsumeStartTime pcommon.Timestamp
 }
 
 func newEdge(key Key, ttl time.Duration) *Edge {
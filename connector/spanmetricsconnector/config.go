@@ -10,8 +10,11 @@ import (
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/spanmetricsconnector/internal/metrics"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 )
 
 const (
@@ -29,6 +32,10 @@ var defaultDeltaTimestampCacheSize = 1000
 type Dimension struct {
 	Name    string  `mapstructure:"name"`
 	Default *string `mapstructure:"default"`
+	// Expression is an optional OTTL value expression computing the dimension value, instead of reading
+	// the attribute with the same name. It is evaluated in the span context for span dimensions, and in the
+	// span event context for event dimensions. The default value is used when the expression evaluates to nil.
+	Expression string `mapstructure:"expression"`
 }
 
 // Config defines the configuration options for spanmetricsconnector.
@@ -84,6 +91,17 @@ type Config struct {
 
 	// Events defines the configuration for events section of spans.
 	Events EventsConfig `mapstructure:"events"`
+
+	// Links defines the configuration for links section of spans.
+	Links LinksConfig `mapstructure:"links"`
+
+	// Conditions is an optional list of OTTL conditions in the span context. When set, only the spans
+	// matching at least one of the conditions generate metrics.
+	Conditions []string `mapstructure:"conditions"`
+
+	// ErrorMode determines how errors returned by the evaluation of OTTL dimension expressions and conditions
+	// are handled. The default is "propagate".
+	ErrorMode ottl.ErrorMode `mapstructure:"error_mode"`
 }
 
 type HistogramConfig struct {
@@ -112,6 +130,17 @@ type EventsConfig struct {
 	Enabled bool `mapstructure:"enabled"`
 	// Dimensions defines the list of dimensions to add to the events metric.
 	Dimensions []Dimension `mapstructure:"dimensions"`
+	// Conditions is an optional list of OTTL conditions in the span event context. When set, only the events
+	// matching at least one of the conditions are counted.
+	Conditions []string `mapstructure:"conditions"`
+}
+
+type LinksConfig struct {
+	// Enabled is a flag to enable links.
+	Enabled bool `mapstructure:"enabled"`
+	// Dimensions defines the list of dimensions to add to the links metric. The dimensions are fetched from the
+	// link's attributes, falling back to the span's and resource's attributes.
+	Dimensions []Dimension `mapstructure:"dimensions"`
 }
 
 var _ component.ConfigValidator = (*Config)(nil)
@@ -124,6 +153,27 @@ func (c Config) Validate() error {
 	if err := validateEventDimensions(c.Events.Enabled, c.Events.Dimensions); err != nil {
 		return fmt.Errorf("failed validating event dimensions: %w", err)
 	}
+	if err := validateLinkDimensions(c.Links.Enabled, c.Links.Dimensions); err != nil {
+		return fmt.Errorf("failed validating link dimensions: %w", err)
+	}
+
+	set := component.TelemetrySettings{Logger: zap.NewNop()}
+	if _, err := newSpanExpressions(c.Dimensions, set); err != nil {
+		return fmt.Errorf("failed parsing dimension expressions: %w", err)
+	}
+	if _, err := newSpanEventExpressions(c.Events.Dimensions, set); err != nil {
+		return fmt.Errorf("failed parsing event dimension expressions: %w", err)
+	}
+	if len(c.Conditions) > 0 {
+		if _, err := filterottl.NewBoolExprForSpan(c.Conditions, filterottl.StandardSpanFuncs(), ottl.PropagateError, set); err != nil {
+			return fmt.Errorf("failed parsing conditions: %w", err)
+		}
+	}
+	if len(c.Events.Conditions) > 0 {
+		if _, err := filterottl.NewBoolExprForSpanEvent(c.Events.Conditions, filterottl.StandardSpanEventFuncs(), ottl.PropagateError, set); err != nil {
+			return fmt.Errorf("failed parsing event conditions: %w", err)
+		}
+	}
 
 	if c.DimensionsCacheSize <= 0 {
 		return fmt.Errorf(
@@ -197,3 +247,16 @@ func validateEventDimensions(enabled bool, dimensions []Dimension) error {
 	}
 	return validateDimensions(dimensions)
 }
+
+// validateLinkDimensions checks for duplicates and unsupported expressions for the dimensions configured.
+func validateLinkDimensions(enabled bool, dimensions []Dimension) error {
+	if !enabled {
+		return nil
+	}
+	for _, d := range dimensions {
+		if d.Expression != "" {
+			return fmt.Errorf("expressions are not supported for link dimension %s", d.Name)
+		}
+	}
+	return validateDimensions(dimensions)
+}
@@ -17,6 +17,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/spanmetricsconnector/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/spanmetricsconnector/internal/metrics"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -66,6 +67,7 @@ func TestLoadConfig(t *testing.T) {
 						},
 					},
 				},
+				ErrorMode: ottl.PropagateError,
 			}},
 		{
 			id: component.NewIDWithName(metadata.Type, "exponential_histogram"),
@@ -80,6 +82,7 @@ func TestLoadConfig(t *testing.T) {
 						MaxSize: 10,
 					},
 				},
+				ErrorMode: ottl.PropagateError,
 			},
 		},
 		{
@@ -103,6 +106,7 @@ func TestLoadConfig(t *testing.T) {
 				MetricsFlushInterval:     60 * time.Second,
 				Histogram:                HistogramConfig{Disable: false, Unit: defaultUnit},
 				Exemplars:                ExemplarsConfig{Enabled: true},
+				ErrorMode:                ottl.PropagateError,
 			},
 		},
 		{
@@ -114,6 +118,7 @@ func TestLoadConfig(t *testing.T) {
 				MetricsFlushInterval:     60 * time.Second,
 				Histogram:                HistogramConfig{Disable: false, Unit: defaultUnit},
 				Exemplars:                ExemplarsConfig{Enabled: true, MaxPerDataPoint: &defaultMaxPerDatapoint},
+				ErrorMode:                ottl.PropagateError,
 			},
 		},
 		{
@@ -125,6 +130,7 @@ func TestLoadConfig(t *testing.T) {
 				ResourceMetricsKeyAttributes: []string{"service.name", "telemetry.sdk.language", "telemetry.sdk.name"},
 				MetricsFlushInterval:         60 * time.Second,
 				Histogram:                    HistogramConfig{Disable: false, Unit: defaultUnit},
+				ErrorMode:                    ottl.PropagateError,
 			},
 		},
 		{
@@ -136,6 +142,7 @@ func TestLoadConfig(t *testing.T) {
 				ResourceMetricsCacheSize: defaultResourceMetricsCacheSize,
 				MetricsFlushInterval:     60 * time.Second,
 				Histogram:                HistogramConfig{Disable: false, Unit: defaultUnit},
+				ErrorMode:                ottl.PropagateError,
 			},
 		},
 		{
@@ -146,11 +153,45 @@ func TestLoadConfig(t *testing.T) {
 				ResourceMetricsCacheSize: defaultResourceMetricsCacheSize,
 				MetricsFlushInterval:     60 * time.Second,
 				Histogram:                HistogramConfig{Disable: false, Unit: defaultUnit},
+				ErrorMode:                ottl.PropagateError,
 			},
 			extraAssertions: func(config *Config) {
 				assert.Equal(t, defaultDeltaTimestampCacheSize, config.GetDeltaTimestampCacheSize())
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "ottl"),
+			expected: &Config{
+				AggregationTemporality: "AGGREGATION_TEMPORALITY_CUMULATIVE",
+				Dimensions: []Dimension{
+					{Name: "http.method"},
+					{Name: "route", Expression: `attributes["http.route"]`},
+				},
+				DimensionsCacheSize:      defaultDimensionsCacheSize,
+				ResourceMetricsCacheSize: defaultResourceMetricsCacheSize,
+				MetricsFlushInterval:     60 * time.Second,
+				Histogram:                HistogramConfig{Disable: false, Unit: defaultUnit},
+				Conditions:               []string{`kind == SPAN_KIND_SERVER`},
+				ErrorMode:                ottl.IgnoreError,
+				Events: EventsConfig{
+					Enabled:    true,
+					Dimensions: []Dimension{{Name: "exception.type", Expression: `attributes["exception.type"]`}},
+					Conditions: []string{`name == "exception"`},
+				},
+				Links: LinksConfig{
+					Enabled:    true,
+					Dimensions: []Dimension{{Name: "messaging.system"}},
+				},
+			},
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_dimension_expression"),
+			errorMessage: "failed parsing dimension expressions: dimension route",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_condition"),
+			errorMessage: "failed parsing conditions",
+		},
 		{
 			id:           component.NewIDWithName(metadata.Type, "invalid_delta_timestamp_cache_size"),
 			errorMessage: "invalid delta timestamp cache size: 0, the maximum number of the items in the cache should be positive",
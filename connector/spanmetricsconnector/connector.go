@@ -23,6 +23,10 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/spanmetricsconnector/internal/cache"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/spanmetricsconnector/internal/metrics"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/traceutil"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/expr"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspanevent"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
 )
 
@@ -39,6 +43,7 @@ const (
 	metricNameDuration = "duration"
 	metricNameCalls    = "calls"
 	metricNameEvents   = "events"
+	metricNameLinks    = "links"
 
 	defaultUnit = metrics.Milliseconds
 )
@@ -74,6 +79,17 @@ type connectorImp struct {
 
 	events EventsConfig
 
+	// Link dimensions to add to the links metric.
+	lDimensions []dimension
+
+	// Conditions selecting the spans and events generating metrics, nil when not configured.
+	spanConditions  expr.BoolExpr[ottlspan.TransformContext]
+	eventConditions expr.BoolExpr[ottlspanevent.TransformContext]
+
+	// Expressions computing the values of dimensions.
+	spanExpressions  []dimensionExpression[ottlspan.TransformContext]
+	eventExpressions []dimensionExpression[ottlspanevent.TransformContext]
+
 	// Tracks the last TimestampUnixNano for delta metrics so that they represent an uninterrupted series. Unused for cumulative span metrics.
 	lastDeltaTimestamps *simplelru.LRU[metrics.Key, pcommon.Timestamp]
 }
@@ -82,6 +98,7 @@ type resourceMetrics struct {
 	histograms metrics.HistogramMetrics
 	sums       metrics.SumMetrics
 	events     metrics.SumMetrics
+	links      metrics.SumMetrics
 	attributes pcommon.Map
 	// startTimestamp captures when the first data points for this resource are recorded.
 	startTimestamp pcommon.Timestamp
@@ -92,6 +109,8 @@ type resourceMetrics struct {
 type dimension struct {
 	name  string
 	value *pcommon.Value
+	// expression indicates that the dimension value is computed by an OTTL expression.
+	expression bool
 }
 
 func newDimensions(cfgDims []Dimension) []dimension {
@@ -101,6 +120,7 @@ func newDimensions(cfgDims []Dimension) []dimension {
 	dims := make([]dimension, len(cfgDims))
 	for i := range cfgDims {
 		dims[i].name = cfgDims[i].Name
+		dims[i].expression = cfgDims[i].Expression != ""
 		if cfgDims[i].Default != nil {
 			val := pcommon.NewValueStr(*cfgDims[i].Default)
 			dims[i].value = &val
@@ -109,7 +129,8 @@ func newDimensions(cfgDims []Dimension) []dimension {
 	return dims
 }
 
-func newConnector(logger *zap.Logger, config component.Config, ticker *clock.Ticker) (*connectorImp, error) {
+func newConnector(set component.TelemetrySettings, config component.Config, ticker *clock.Ticker) (*connectorImp, error) {
+	logger := set.Logger
 	logger.Info("Building spanmetrics connector")
 	cfg := config.(*Config)
 
@@ -139,6 +160,30 @@ func newConnector(logger *zap.Logger, config component.Config, ticker *clock.Tic
 		}
 	}
 
+	spanExpressions, err := newSpanExpressions(cfg.Dimensions, set)
+	if err != nil {
+		return nil, err
+	}
+	eventExpressions, err := newSpanEventExpressions(cfg.Events.Dimensions, set)
+	if err != nil {
+		return nil, err
+	}
+
+	var spanConditions expr.BoolExpr[ottlspan.TransformContext]
+	if len(cfg.Conditions) > 0 {
+		spanConditions, err = filterottl.NewBoolExprForSpan(cfg.Conditions, filterottl.StandardSpanFuncs(), cfg.ErrorMode, set)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var eventConditions expr.BoolExpr[ottlspanevent.TransformContext]
+	if len(cfg.Events.Conditions) > 0 {
+		eventConditions, err = filterottl.NewBoolExprForSpanEvent(cfg.Events.Conditions, filterottl.StandardSpanEventFuncs(), cfg.ErrorMode, set)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &connectorImp{
 		logger:                       logger,
 		config:                       *cfg,
@@ -152,6 +197,11 @@ func newConnector(logger *zap.Logger, config component.Config, ticker *clock.Tic
 		done:                         make(chan struct{}),
 		eDimensions:                  newDimensions(cfg.Events.Dimensions),
 		events:                       cfg.Events,
+		lDimensions:                  newDimensions(cfg.Links.Dimensions),
+		spanConditions:               spanConditions,
+		eventConditions:              eventConditions,
+		spanExpressions:              spanExpressions,
+		eventExpressions:             eventExpressions,
 	}, nil
 }
 
@@ -241,11 +291,10 @@ func (p *connectorImp) Capabilities() consumer.Capabilities {
 
 // ConsumeTraces implements the consumer.Traces interface.
 // It aggregates the trace data to generate metrics.
-func (p *connectorImp) ConsumeTraces(_ context.Context, traces ptrace.Traces) error {
+func (p *connectorImp) ConsumeTraces(ctx context.Context, traces ptrace.Traces) error {
 	p.lock.Lock()
-	p.aggregateMetrics(traces)
-	p.lock.Unlock()
-	return nil
+	defer p.lock.Unlock()
+	return p.aggregateMetrics(ctx, traces)
 }
 
 func (p *connectorImp) exportMetrics(ctx context.Context) {
@@ -312,6 +361,12 @@ func (p *connectorImp) buildMetrics() pmetric.Metrics {
 			events.BuildMetrics(metric, startTimeGenerator, timestamp, p.config.GetAggregationTemporality())
 		}
 
+		if p.config.Links.Enabled {
+			metric = sm.Metrics().AppendEmpty()
+			metric.SetName(buildMetricName(p.config.Namespace, metricNameLinks))
+			rawMetrics.links.BuildMetrics(metric, startTimeGenerator, timestamp, p.config.GetAggregationTemporality())
+		}
+
 		for mk := range deltaMetricKeys {
 			// For delta metrics, cache the current data point's timestamp, which will be the start timestamp for the next data points in the series
 			p.lastDeltaTimestamps.Add(mk, timestamp)
@@ -342,6 +397,7 @@ func (p *connectorImp) resetState() {
 			if p.config.Exemplars.Enabled {
 				m.sums.ClearExemplars()
 				m.events.ClearExemplars()
+				m.links.ClearExemplars()
 				if !p.config.Histogram.Disable {
 					m.histograms.ClearExemplars()
 				}
@@ -364,7 +420,14 @@ func (p *connectorImp) resetState() {
 // Each metric is identified by a key that is built from the service name
 // and span metadata such as name, kind, status_code and any additional
 // dimensions the user has configured.
-func (p *connectorImp) aggregateMetrics(traces ptrace.Traces) {
+func (p *connectorImp) aggregateMetrics(ctx context.Context, traces ptrace.Traces) error {
+	evaluations, err := p.evaluateSpans(ctx, traces)
+	if err != nil {
+		return err
+	}
+	// n is the index of the evaluation of the next span
+	n := 0
+
 	startTimestamp := pcommon.NewTimestampFromTime(time.Now())
 	for i := 0; i < traces.ResourceSpans().Len(); i++ {
 		rspans := traces.ResourceSpans().At(i)
@@ -378,6 +441,7 @@ func (p *connectorImp) aggregateMetrics(traces ptrace.Traces) {
 		sums := rm.sums
 		histograms := rm.histograms
 		events := rm.events
+		links := rm.links
 
 		unitDivider := unitDivider(p.config.Histogram.Unit)
 		serviceName := serviceAttr.Str()
@@ -387,6 +451,19 @@ func (p *connectorImp) aggregateMetrics(traces ptrace.Traces) {
 			spans := ils.Spans()
 			for k := 0; k < spans.Len(); k++ {
 				span := spans.At(k)
+
+				exprValues := pcommon.NewMap()
+				var eventEvaluations []eventEvaluation
+				if evaluations != nil {
+					evaluation := evaluations[n]
+					n++
+					if !evaluation.matched {
+						continue
+					}
+					exprValues = evaluation.exprValues
+					eventEvaluations = evaluation.events
+				}
+
 				// Protect against end timestamps before start timestamps. Assume 0 duration.
 				duration := float64(0)
 				startTime := span.StartTimestamp()
@@ -394,11 +471,11 @@ func (p *connectorImp) aggregateMetrics(traces ptrace.Traces) {
 				if endTime > startTime {
 					duration = float64(endTime-startTime) / float64(unitDivider)
 				}
-				key := p.buildKey(serviceName, span, p.dimensions, resourceAttr)
+				key := p.buildKey(serviceName, span, p.dimensions, exprValues, resourceAttr)
 
 				attributes, ok := p.metricKeyToDimensions.Get(key)
 				if !ok {
-					attributes = p.buildAttributes(serviceName, span, resourceAttr, p.dimensions)
+					attributes = p.buildAttributes(serviceName, span, exprValues, resourceAttr, p.dimensions)
 					p.metricKeyToDimensions.Add(key, attributes)
 				}
 				if !p.config.Histogram.Disable {
@@ -422,15 +499,23 @@ func (p *connectorImp) aggregateMetrics(traces ptrace.Traces) {
 						eDimensions := p.dimensions
 						eDimensions = append(eDimensions, p.eDimensions...)
 
+						eExprValues := exprValues
+						if eventEvaluations != nil {
+							if !eventEvaluations[l].matched {
+								continue
+							}
+							eExprValues = eventEvaluations[l].exprValues
+						}
+
 						rscAndEventAttrs := pcommon.NewMap()
 						rscAndEventAttrs.EnsureCapacity(resourceAttr.Len() + event.Attributes().Len())
 						resourceAttr.CopyTo(rscAndEventAttrs)
 						event.Attributes().CopyTo(rscAndEventAttrs)
 
-						eKey := p.buildKey(serviceName, span, eDimensions, rscAndEventAttrs)
+						eKey := p.buildKey(serviceName, span, eDimensions, eExprValues, rscAndEventAttrs)
 						eAttributes, ok := p.metricKeyToDimensions.Get(eKey)
 						if !ok {
-							eAttributes = p.buildAttributes(serviceName, span, rscAndEventAttrs, eDimensions)
+							eAttributes = p.buildAttributes(serviceName, span, eExprValues, rscAndEventAttrs, eDimensions)
 							p.metricKeyToDimensions.Add(eKey, eAttributes)
 						}
 						e := events.GetOrCreate(eKey, eAttributes)
@@ -440,9 +525,128 @@ func (p *connectorImp) aggregateMetrics(traces ptrace.Traces) {
 						e.Add(1)
 					}
 				}
+
+				// aggregate links metrics
+				if p.config.Links.Enabled {
+					for l := 0; l < span.Links().Len(); l++ {
+						link := span.Links().At(l)
+						lDimensions := p.dimensions
+						lDimensions = append(lDimensions, p.lDimensions...)
+
+						rscAndLinkAttrs := pcommon.NewMap()
+						rscAndLinkAttrs.EnsureCapacity(resourceAttr.Len() + link.Attributes().Len())
+						resourceAttr.CopyTo(rscAndLinkAttrs)
+						link.Attributes().CopyTo(rscAndLinkAttrs)
+
+						lKey := p.buildKey(serviceName, span, lDimensions, exprValues, rscAndLinkAttrs)
+						lAttributes, ok := p.metricKeyToDimensions.Get(lKey)
+						if !ok {
+							lAttributes = p.buildAttributes(serviceName, span, exprValues, rscAndLinkAttrs, lDimensions)
+							p.metricKeyToDimensions.Add(lKey, lAttributes)
+						}
+						lm := links.GetOrCreate(lKey, lAttributes)
+						if p.config.Exemplars.Enabled && !span.TraceID().IsEmpty() {
+							lm.AddExemplar(span.TraceID(), span.SpanID(), duration)
+						}
+						lm.Add(1)
+					}
+				}
 			}
 		}
 	}
+	return nil
+}
+
+// spanEvaluation is the result of the conditions and dimension expressions of a span.
+type spanEvaluation struct {
+	matched    bool
+	exprValues pcommon.Map
+	// events holds the evaluations of the events of the span, when they are evaluated.
+	events []eventEvaluation
+}
+
+// eventEvaluation is the result of the conditions and dimension expressions of a span event.
+type eventEvaluation struct {
+	matched    bool
+	exprValues pcommon.Map
+}
+
+// evaluateSpans evaluates the conditions and dimension expressions of the spans with a service name, in the
+// order they are aggregated. They are all evaluated before any metric is aggregated, so that an error doesn't
+// leave the batch partly aggregated, to be counted twice once retried. It returns nil when there are none.
+func (p *connectorImp) evaluateSpans(ctx context.Context, traces ptrace.Traces) ([]spanEvaluation, error) {
+	evalSpans := p.spanConditions != nil || len(p.spanExpressions) > 0
+	evalEvents := p.events.Enabled && (p.eventConditions != nil || len(p.eventExpressions) > 0)
+	if !evalSpans && !evalEvents {
+		return nil, nil
+	}
+
+	var evaluations []spanEvaluation
+	for i := 0; i < traces.ResourceSpans().Len(); i++ {
+		rspans := traces.ResourceSpans().At(i)
+		if _, ok := rspans.Resource().Attributes().Get(conventions.AttributeServiceName); !ok {
+			continue
+		}
+		ilsSlice := rspans.ScopeSpans()
+		for j := 0; j < ilsSlice.Len(); j++ {
+			ils := ilsSlice.At(j)
+			spans := ils.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				evaluation := spanEvaluation{matched: true, exprValues: pcommon.NewMap()}
+				if evalSpans {
+					tCtx := ottlspan.NewTransformContext(span, ils.Scope(), rspans.Resource())
+					if p.spanConditions != nil {
+						matched, err := p.spanConditions.Eval(ctx, tCtx)
+						if err != nil {
+							return nil, err
+						}
+						evaluation.matched = matched
+					}
+					if evaluation.matched {
+						if err := evalDimensionExpressions(ctx, tCtx, p.spanExpressions, evaluation.exprValues, p.config.ErrorMode, p.logger); err != nil {
+							return nil, err
+						}
+					}
+				}
+				if evaluation.matched && evalEvents {
+					evaluation.events = make([]eventEvaluation, span.Events().Len())
+					for l := 0; l < span.Events().Len(); l++ {
+						eventEval, err := p.evaluateEvent(ctx, span.Events().At(l), span, ils.Scope(), rspans.Resource(), evaluation.exprValues)
+						if err != nil {
+							return nil, err
+						}
+						evaluation.events[l] = eventEval
+					}
+				}
+				evaluations = append(evaluations, evaluation)
+			}
+		}
+	}
+	return evaluations, nil
+}
+
+// evaluateEvent evaluates the conditions and dimension expressions of a span event, whose dimension
+// values are added to the ones of its span.
+func (p *connectorImp) evaluateEvent(ctx context.Context, event ptrace.SpanEvent, span ptrace.Span, scope pcommon.InstrumentationScope,
+	resource pcommon.Resource, spanExprValues pcommon.Map) (eventEvaluation, error) {
+	evaluation := eventEvaluation{matched: true, exprValues: spanExprValues}
+	tCtx := ottlspanevent.NewTransformContext(event, span, scope, resource)
+	if p.eventConditions != nil {
+		matched, err := p.eventConditions.Eval(ctx, tCtx)
+		if err != nil {
+			return evaluation, err
+		}
+		evaluation.matched = matched
+	}
+	if evaluation.matched && len(p.eventExpressions) > 0 {
+		evaluation.exprValues = pcommon.NewMap()
+		spanExprValues.CopyTo(evaluation.exprValues)
+		if err := evalDimensionExpressions(ctx, tCtx, p.eventExpressions, evaluation.exprValues, p.config.ErrorMode, p.logger); err != nil {
+			return evaluation, err
+		}
+	}
+	return evaluation, nil
 }
 
 func (p *connectorImp) addExemplar(span ptrace.Span, duration float64, h metrics.Histogram) {
@@ -479,6 +683,7 @@ func (p *connectorImp) getOrCreateResourceMetrics(attr pcommon.Map, startTimesta
 			histograms:     initHistogramMetrics(p.config),
 			sums:           metrics.NewSumMetrics(p.config.Exemplars.MaxPerDataPoint),
 			events:         metrics.NewSumMetrics(p.config.Exemplars.MaxPerDataPoint),
+			links:          metrics.NewSumMetrics(p.config.Exemplars.MaxPerDataPoint),
 			attributes:     attr,
 			startTimestamp: startTimestamp,
 		}
@@ -503,7 +708,7 @@ func contains(elements []string, value string) bool {
 	return false
 }
 
-func (p *connectorImp) buildAttributes(serviceName string, span ptrace.Span, resourceAttrs pcommon.Map, dimensions []dimension) pcommon.Map {
+func (p *connectorImp) buildAttributes(serviceName string, span ptrace.Span, exprValues pcommon.Map, resourceAttrs pcommon.Map, dimensions []dimension) pcommon.Map {
 	attr := pcommon.NewMap()
 	attr.EnsureCapacity(4 + len(dimensions))
 	if !contains(p.config.ExcludeDimensions, serviceNameKey) {
@@ -519,7 +724,7 @@ func (p *connectorImp) buildAttributes(serviceName string, span ptrace.Span, res
 		attr.PutStr(statusCodeKey, traceutil.StatusCodeStr(span.Status().Code()))
 	}
 	for _, d := range dimensions {
-		if v, ok := getDimensionValue(d, span.Attributes(), resourceAttrs); ok {
+		if v, ok := getDimensionValue(d, exprValues, span.Attributes(), resourceAttrs); ok {
 			v.CopyTo(attr.PutEmpty(d.name))
 		}
 	}
@@ -536,9 +741,10 @@ func concatDimensionValue(dest *bytes.Buffer, value string, prefixSep bool) {
 // buildKey builds the metric key from the service name and span metadata such as name, kind, status_code and
 // will attempt to add any additional dimensions the user has configured that match the span's attributes
 // or resource/event attributes. If the dimension exists in both, the span's attributes, being the most specific, takes precedence.
+// Dimensions computed by OTTL expressions take their value from exprValues instead.
 //
 // The metric key is a simple concatenation of dimension values, delimited by a null character.
-func (p *connectorImp) buildKey(serviceName string, span ptrace.Span, optionalDims []dimension, resourceOrEventAttrs pcommon.Map) metrics.Key {
+func (p *connectorImp) buildKey(serviceName string, span ptrace.Span, optionalDims []dimension, exprValues pcommon.Map, resourceOrEventAttrs pcommon.Map) metrics.Key {
 	p.keyBuf.Reset()
 	if !contains(p.config.ExcludeDimensions, serviceNameKey) {
 		concatDimensionValue(p.keyBuf, serviceName, false)
@@ -554,7 +760,7 @@ func (p *connectorImp) buildKey(serviceName string, span ptrace.Span, optionalDi
 	}
 
 	for _, d := range optionalDims {
-		if v, ok := getDimensionValue(d, span.Attributes(), resourceOrEventAttrs); ok {
+		if v, ok := getDimensionValue(d, exprValues, span.Attributes(), resourceOrEventAttrs); ok {
 			concatDimensionValue(p.keyBuf, v.AsString(), true)
 		}
 	}
@@ -563,19 +769,26 @@ func (p *connectorImp) buildKey(serviceName string, span ptrace.Span, optionalDi
 }
 
 // getDimensionValue gets the dimension value for the given configured dimension.
-// It searches through the span's attributes first, being the more specific;
+// For dimensions computed by an OTTL expression, the value is taken from the evaluated expressions.
+// Otherwise, it searches through the span's attributes first, being the more specific;
 // falling back to searching in resource attributes if it can't be found in the span.
 // Finally, falls back to the configured default value if provided.
 //
 // The ok flag indicates if a dimension value was fetched in order to differentiate
 // an empty string value from a state where no value was found.
-func getDimensionValue(d dimension, spanAttr pcommon.Map, resourceAttr pcommon.Map) (v pcommon.Value, ok bool) {
-	// The more specific span attribute should take precedence.
-	if attr, exists := spanAttr.Get(d.name); exists {
-		return attr, true
-	}
-	if attr, exists := resourceAttr.Get(d.name); exists {
-		return attr, true
+func getDimensionValue(d dimension, exprValues pcommon.Map, spanAttr pcommon.Map, resourceAttr pcommon.Map) (v pcommon.Value, ok bool) {
+	if d.expression {
+		if val, exists := exprValues.Get(d.name); exists {
+			return val, true
+		}
+	} else {
+		// The more specific span attribute should take precedence.
+		if attr, exists := spanAttr.Get(d.name); exists {
+			return attr, true
+		}
+		if attr, exists := resourceAttr.Get(d.name); exists {
+			return attr, true
+		}
 	}
 	// Set the default if configured, otherwise this metric will have no value set for the dimension.
 	if d.value != nil {
@@ -24,11 +24,11 @@ import (
 	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"go.uber.org/zap/zaptest"
 	"go.uber.org/zap/zaptest/observer"
 	"google.golang.org/grpc/metadata"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/spanmetricsconnector/internal/metrics"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 )
 
 const (
@@ -463,19 +463,19 @@ func newConnectorImp(defaultNullValue *string, histogramConfig func() HistogramC
 		ResourceMetricsKeyAttributes: resourceMetricsKeyAttributes,
 		Dimensions: []Dimension{
 			// Set nil defaults to force a lookup for the attribute in the span.
-			{stringAttrName, nil},
-			{intAttrName, nil},
-			{doubleAttrName, nil},
-			{boolAttrName, nil},
-			{mapAttrName, nil},
-			{arrayAttrName, nil},
-			{nullAttrName, defaultNullValue},
+			{Name: stringAttrName, Default: nil},
+			{Name: intAttrName, Default: nil},
+			{Name: doubleAttrName, Default: nil},
+			{Name: boolAttrName, Default: nil},
+			{Name: mapAttrName, Default: nil},
+			{Name: arrayAttrName, Default: nil},
+			{Name: nullAttrName, Default: defaultNullValue},
 			// Add a default value for an attribute that doesn't exist in a span
-			{notInSpanAttrName0, stringp("defaultNotInSpanAttrVal")},
+			{Name: notInSpanAttrName0, Default: stringp("defaultNotInSpanAttrVal")},
 			// Leave the default value unset to test that this dimension should not be added to the metric.
-			{notInSpanAttrName1, nil},
+			{Name: notInSpanAttrName1, Default: nil},
 			// Add a resource attribute to test "process" attributes like IP, host, region, cluster, etc.
-			{regionResourceAttrName, nil},
+			{Name: regionResourceAttrName, Default: nil},
 		},
 		Events:             eventsConfig(),
 		MetricsExpiration:  expiration,
@@ -485,7 +485,7 @@ func newConnectorImp(defaultNullValue *string, histogramConfig func() HistogramC
 	mockClock := clock.NewMock(time.Now())
 	ticker := mockClock.NewTicker(time.Nanosecond)
 
-	c, err := newConnector(zap.NewNop(), cfg, ticker)
+	c, err := newConnector(componenttest.NewNopTelemetrySettings(), cfg, ticker)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -500,16 +500,16 @@ func stringp(str string) *string {
 func TestBuildKeySameServiceNameCharSequence(t *testing.T) {
 	factory := NewFactory()
 	cfg := factory.CreateDefaultConfig().(*Config)
-	c, err := newConnector(zaptest.NewLogger(t), cfg, nil)
+	c, err := newConnector(componenttest.NewNopTelemetrySettings(), cfg, nil)
 	require.NoError(t, err)
 
 	span0 := ptrace.NewSpan()
 	span0.SetName("c")
-	k0 := c.buildKey("ab", span0, nil, pcommon.NewMap())
+	k0 := c.buildKey("ab", span0, nil, pcommon.NewMap(), pcommon.NewMap())
 
 	span1 := ptrace.NewSpan()
 	span1.SetName("bc")
-	k1 := c.buildKey("a", span1, nil, pcommon.NewMap())
+	k1 := c.buildKey("a", span1, nil, pcommon.NewMap(), pcommon.NewMap())
 
 	assert.NotEqual(t, k0, k1)
 	assert.Equal(t, metrics.Key("ab\u0000c\u0000SPAN_KIND_UNSPECIFIED\u0000STATUS_CODE_UNSET"), k0)
@@ -520,12 +520,12 @@ func TestBuildKeyExcludeDimensionsAll(t *testing.T) {
 	factory := NewFactory()
 	cfg := factory.CreateDefaultConfig().(*Config)
 	cfg.ExcludeDimensions = []string{"span.kind", "service.name", "span.name", "status.code"}
-	c, err := newConnector(zaptest.NewLogger(t), cfg, nil)
+	c, err := newConnector(componenttest.NewNopTelemetrySettings(), cfg, nil)
 	require.NoError(t, err)
 
 	span0 := ptrace.NewSpan()
 	span0.SetName("spanName")
-	k0 := c.buildKey("serviceName", span0, nil, pcommon.NewMap())
+	k0 := c.buildKey("serviceName", span0, nil, pcommon.NewMap(), pcommon.NewMap())
 	assert.Equal(t, metrics.Key(""), k0)
 }
 
@@ -533,19 +533,19 @@ func TestBuildKeyExcludeWrongDimensions(t *testing.T) {
 	factory := NewFactory()
 	cfg := factory.CreateDefaultConfig().(*Config)
 	cfg.ExcludeDimensions = []string{"span.kind", "service.name.wrong.name", "span.name", "status.code"}
-	c, err := newConnector(zaptest.NewLogger(t), cfg, nil)
+	c, err := newConnector(componenttest.NewNopTelemetrySettings(), cfg, nil)
 	require.NoError(t, err)
 
 	span0 := ptrace.NewSpan()
 	span0.SetName("spanName")
-	k0 := c.buildKey("serviceName", span0, nil, pcommon.NewMap())
+	k0 := c.buildKey("serviceName", span0, nil, pcommon.NewMap(), pcommon.NewMap())
 	assert.Equal(t, metrics.Key("serviceName"), k0)
 }
 
 func TestBuildKeyWithDimensions(t *testing.T) {
 	factory := NewFactory()
 	cfg := factory.CreateDefaultConfig().(*Config)
-	c, err := newConnector(zaptest.NewLogger(t), cfg, nil)
+	c, err := newConnector(componenttest.NewNopTelemetrySettings(), cfg, nil)
 	require.NoError(t, err)
 
 	defaultFoo := pcommon.NewValueStr("bar")
@@ -614,7 +614,7 @@ func TestBuildKeyWithDimensions(t *testing.T) {
 			span0 := ptrace.NewSpan()
 			assert.NoError(t, span0.Attributes().FromRaw(tc.spanAttrMap))
 			span0.SetName("c")
-			key := c.buildKey("ab", span0, tc.optionalDims, resAttr)
+			key := c.buildKey("ab", span0, tc.optionalDims, pcommon.NewMap(), resAttr)
 			assert.Equal(t, metrics.Key(tc.wantKey), key)
 		})
 	}
@@ -686,7 +686,7 @@ func TestConnectorCapabilities(t *testing.T) {
 	cfg := factory.CreateDefaultConfig().(*Config)
 
 	// Test
-	c, err := newConnector(zaptest.NewLogger(t), cfg, nil)
+	c, err := newConnector(componenttest.NewNopTelemetrySettings(), cfg, nil)
 	// Override the default no-op consumer for testing.
 	c.metricsConsumer = new(consumertest.MetricsSink)
 	assert.NoError(t, err)
@@ -1489,7 +1489,7 @@ func TestSpanMetrics_Events(t *testing.T) {
 			factory := NewFactory()
 			cfg := factory.CreateDefaultConfig().(*Config)
 			cfg.Events = tt.eventsConfig
-			c, err := newConnector(zaptest.NewLogger(t), cfg, nil)
+			c, err := newConnector(componenttest.NewNopTelemetrySettings(), cfg, nil)
 			require.NoError(t, err)
 			err = c.ConsumeTraces(context.Background(), buildSampleTrace())
 			require.NoError(t, err)
@@ -1520,6 +1520,141 @@ func TestSpanMetrics_Events(t *testing.T) {
 		})
 	}
 }
+func TestSpanMetrics_OTTL(t *testing.T) {
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr(conventions.AttributeServiceName, "service-a")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+
+	server := spans.AppendEmpty()
+	server.SetName("GET /users/{id}")
+	server.SetKind(ptrace.SpanKindServer)
+	server.Attributes().PutStr("http.route", "/users/{id}")
+	exception := server.Events().AppendEmpty()
+	exception.SetName("exception")
+	exception.Attributes().PutStr(exceptionTypeAttrName, "NullPointerException")
+	log := server.Events().AppendEmpty()
+	log.SetName("log")
+	log.Attributes().PutStr(exceptionTypeAttrName, "ignored")
+	link := server.Links().AppendEmpty()
+	link.Attributes().PutStr("messaging.system", "kafka")
+
+	client := spans.AppendEmpty()
+	client.SetName("SELECT")
+	client.SetKind(ptrace.SpanKindClient)
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Dimensions = []Dimension{{Name: "route", Expression: `Concat(["route", attributes["http.route"]], ":")`}}
+	cfg.Conditions = []string{`kind == SPAN_KIND_SERVER`}
+	cfg.Events = EventsConfig{
+		Enabled:    true,
+		Dimensions: []Dimension{{Name: "event.exception", Expression: `attributes["exception.type"]`}},
+		Conditions: []string{`name == "exception"`},
+	}
+	cfg.Links = LinksConfig{
+		Enabled:    true,
+		Dimensions: []Dimension{{Name: "messaging.system"}},
+	}
+
+	c, err := newConnector(componenttest.NewNopTelemetrySettings(), cfg, nil)
+	require.NoError(t, err)
+	require.NoError(t, c.ConsumeTraces(context.Background(), traces))
+
+	got := map[string][]map[string]any{}
+	metrics := c.buildMetrics()
+	require.Equal(t, 1, metrics.ResourceMetrics().Len())
+	ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	for i := 0; i < ms.Len(); i++ {
+		m := ms.At(i)
+		var dps pmetric.NumberDataPointSlice
+		switch m.Type() {
+		case pmetric.MetricTypeSum:
+			dps = m.Sum().DataPoints()
+		default:
+			continue
+		}
+		for j := 0; j < dps.Len(); j++ {
+			got[m.Name()] = append(got[m.Name()], dps.At(j).Attributes().AsRaw())
+		}
+	}
+
+	// only the server span matches the conditions
+	require.Len(t, got["calls"], 1)
+	assert.Equal(t, "route:/users/{id}", got["calls"][0]["route"])
+	assert.Equal(t, "GET /users/{id}", got["calls"][0][spanNameKey])
+
+	// only the exception event matches the event conditions
+	require.Len(t, got["events"], 1)
+	assert.Equal(t, "NullPointerException", got["events"][0]["event.exception"])
+	assert.Equal(t, "route:/users/{id}", got["events"][0]["route"])
+
+	require.Len(t, got["links"], 1)
+	assert.Equal(t, "kafka", got["links"][0]["messaging.system"])
+	assert.Equal(t, "route:/users/{id}", got["links"][0]["route"])
+}
+
+func TestSpanMetrics_ExpressionErrorMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		errorMode ottl.ErrorMode
+		wantErr   bool
+	}{
+		{
+			name:      "propagate",
+			errorMode: ottl.PropagateError,
+			wantErr:   true,
+		},
+		{
+			name:      "ignore",
+			errorMode: ottl.IgnoreError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig().(*Config)
+			// Int fails on a map value.
+			cfg.Dimensions = []Dimension{{Name: "invalid", Expression: `Int(resource.attributes)`}}
+			cfg.ErrorMode = tt.errorMode
+
+			c, err := newConnector(componenttest.NewNopTelemetrySettings(), cfg, nil)
+			require.NoError(t, err)
+			err = c.ConsumeTraces(context.Background(), buildSampleTrace())
+			if tt.wantErr {
+				assert.ErrorContains(t, err, "failed to evaluate expression for dimension invalid")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestSpanMetrics_ExpressionErrorNotPartlyAggregated(t *testing.T) {
+	traces := ptrace.NewTraces()
+	rs := traces.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr(conventions.AttributeServiceName, "service-a")
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	valid := spans.AppendEmpty()
+	valid.SetName("valid")
+	valid.Attributes().PutStr("retries", "1")
+	invalid := spans.AppendEmpty()
+	invalid.SetName("invalid")
+	invalid.Attributes().PutEmptyMap("retries")
+
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	// Int fails on the map value of the second span.
+	cfg.Dimensions = []Dimension{{Name: "retries", Expression: `Int(attributes["retries"])`}}
+
+	c, err := newConnector(componenttest.NewNopTelemetrySettings(), cfg, nil)
+	require.NoError(t, err)
+	require.Error(t, c.ConsumeTraces(context.Background(), traces))
+
+	// the first span isn't aggregated either, so that it isn't counted twice when the batch is retried
+	assert.Equal(t, 0, c.buildMetrics().ResourceMetrics().Len())
+}
+
 func TestExemplarsAreDiscardedAfterFlushing(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package spanmetricsconnector // import "github.com/open-telemetry/opentelemetry-collector-contrib/connector/spanmetricsconnector"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/filter/filterottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspanevent"
+)
+
+// dimensionExpression is a dimension whose value is computed by an OTTL value expression.
+type dimensionExpression[K any] struct {
+	name string
+	expr *ottl.ValueExpression[K]
+}
+
+func newSpanExpressions(dims []Dimension, set component.TelemetrySettings) ([]dimensionExpression[ottlspan.TransformContext], error) {
+	parser, err := ottlspan.NewParser(filterottl.StandardSpanFuncs(), set)
+	if err != nil {
+		return nil, err
+	}
+	return parseDimensionExpressions(dims, parser)
+}
+
+func newSpanEventExpressions(dims []Dimension, set component.TelemetrySettings) ([]dimensionExpression[ottlspanevent.TransformContext], error) {
+	parser, err := ottlspanevent.NewParser(filterottl.StandardSpanEventFuncs(), set)
+	if err != nil {
+		return nil, err
+	}
+	return parseDimensionExpressions(dims, parser)
+}
+
+func parseDimensionExpressions[K any](dims []Dimension, parser ottl.Parser[K]) ([]dimensionExpression[K], error) {
+	var exprs []dimensionExpression[K]
+	for _, d := range dims {
+		if d.Expression == "" {
+			continue
+		}
+		expr, err := parser.ParseValueExpression(d.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("dimension %s: %w", d.Name, err)
+		}
+		exprs = append(exprs, dimensionExpression[K]{name: d.Name, expr: expr})
+	}
+	return exprs, nil
+}
+
+// evalDimensionExpressions evaluates the given expressions and stores their non-nil results in dest,
+// keyed by dimension name. Depending on the error mode, evaluation errors are either returned or logged.
+func evalDimensionExpressions[K any](ctx context.Context, tCtx K, exprs []dimensionExpression[K], dest pcommon.Map, errorMode ottl.ErrorMode, logger *zap.Logger) error {
+	for _, e := range exprs {
+		val, err := e.expr.Eval(ctx, tCtx)
+		if err == nil {
+			err = setDimensionValue(dest, e.name, val)
+		}
+		if err != nil {
+			if errorMode == ottl.PropagateError {
+				return fmt.Errorf("failed to evaluate expression for dimension %s: %w", e.name, err)
+			}
+			if errorMode == ottl.IgnoreError {
+				logger.Warn("failed to evaluate dimension expression", zap.String("dimension", e.name), zap.Error(err))
+			}
+		}
+	}
+	return nil
+}
+
+func setDimensionValue(dest pcommon.Map, name string, val any) error {
+	switch v := val.(type) {
+	case nil:
+		return nil
+	case pcommon.Value:
+		v.CopyTo(dest.PutEmpty(name))
+	case pcommon.Map:
+		v.CopyTo(dest.PutEmptyMap(name))
+	case pcommon.Slice:
+		v.CopyTo(dest.PutEmptySlice(name))
+	case string, bool, int64, float64, []byte, []any, map[string]any:
+		return dest.PutEmpty(name).FromRaw(v)
+	default:
+		return fmt.Errorf("unsupported value type %T", val)
+	}
+	return nil
+}
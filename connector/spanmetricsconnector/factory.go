@@ -15,6 +15,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/connector/spanmetricsconnector/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 )
 
 // NewFactory creates a factory for the spanmetrics connector.
@@ -33,11 +34,12 @@ func createDefaultConfig() component.Config {
 		ResourceMetricsCacheSize: defaultResourceMetricsCacheSize,
 		MetricsFlushInterval:     60 * time.Second,
 		Histogram:                HistogramConfig{Disable: false, Unit: defaultUnit},
+		ErrorMode:                ottl.PropagateError,
 	}
 }
 
 func createTracesToMetricsConnector(ctx context.Context, params connector.Settings, cfg component.Config, nextConsumer consumer.Metrics) (connector.Traces, error) {
-	c, err := newConnector(params.Logger, cfg, metricsTicker(ctx, cfg))
+	c, err := newConnector(params.TelemetrySettings, cfg, metricsTicker(ctx, cfg))
 	if err != nil {
 		return nil, err
 	}
@@ -36,7 +36,7 @@ func TestNewConnector(t *testing.T) {
 			},
 			wantDimensions: []dimension{
 				{name: "http.method", value: &defaultMethodValue},
-				{"http.status_code", nil},
+				{name: "http.status_code"},
 			},
 		},
 	} {
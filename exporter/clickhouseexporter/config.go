@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
@@ -49,6 +50,31 @@ type Config struct {
 	ClusterName string `mapstructure:"cluster_name"`
 	// CreateSchema if set to true will run the DDL for creating the database and tables. default is true.
 	CreateSchema *bool `mapstructure:"create_schema"`
+	// TableTTLs overrides 'ttl' for individual tables.
+	TableTTLs TableTTLs `mapstructure:"table_ttls"`
+	// MetricsRollups configures the rollup tables the gauge and sum metrics are downsampled into.
+	MetricsRollups MetricsRollups `mapstructure:"metrics_rollups"`
+}
+
+// TableTTLs defines the data time-to-live of individual tables, example 30m, 48h. 0 means the table uses 'ttl'.
+type TableTTLs struct {
+	// Logs is the time-to-live of the logs table.
+	Logs time.Duration `mapstructure:"logs"`
+	// Traces is the time-to-live of the traces tables.
+	Traces time.Duration `mapstructure:"traces"`
+	// Metrics is the time-to-live of the metrics tables.
+	Metrics time.Duration `mapstructure:"metrics"`
+	// MetricsRollup1m is the time-to-live of the 1 minute rollup tables.
+	MetricsRollup1m time.Duration `mapstructure:"metrics_rollup_1m"`
+	// MetricsRollup1h is the time-to-live of the 1 hour rollup tables.
+	MetricsRollup1h time.Duration `mapstructure:"metrics_rollup_1h"`
+}
+
+// MetricsRollups defines the rollup tables of the metrics.
+type MetricsRollups struct {
+	// Enabled if set to true will create the 1 minute and 1 hour rollup tables of the gauge and sum metrics,
+	// and the materialized views populating them. default is false.
+	Enabled bool `mapstructure:"enabled"`
 }
 
 // TableEngine defines the ENGINE string value when creating the table.
@@ -64,8 +90,16 @@ var (
 	errConfigNoEndpoint      = errors.New("endpoint must be specified")
 	errConfigInvalidEndpoint = errors.New("endpoint must be url format")
 	errConfigTTL             = errors.New("both 'ttl_days' and 'ttl' can not be provided. 'ttl_days' is deprecated, use 'ttl' instead")
+	errConfigRollupEngine    = errors.New("'metrics_rollups' requires the table engine to be MergeTree, ReplicatedMergeTree or SharedMergeTree")
 )
 
+// rollupTableEnginePrefixes are the prefixes of the MergeTree engines having an AggregatingMergeTree variant.
+var rollupTableEnginePrefixes = map[string]struct{}{
+	"":           {},
+	"Replicated": {},
+	"Shared":     {},
+}
+
 // Validate the ClickHouse server configuration.
 func (cfg *Config) Validate() (err error) {
 	if cfg.Endpoint == "" {
@@ -80,6 +114,13 @@ func (cfg *Config) Validate() (err error) {
 		err = errors.Join(err, errConfigTTL)
 	}
 
+	if cfg.MetricsRollups.Enabled && cfg.TableEngine.Name != "" {
+		prefix, found := strings.CutSuffix(cfg.TableEngine.Name, defaultTableEngineName)
+		if _, ok := rollupTableEnginePrefixes[prefix]; !found || !ok {
+			err = errors.Join(err, errConfigRollupEngine)
+		}
+	}
+
 	// Validate DSN with clickhouse driver.
 	// Last chance to catch invalid config.
 	if _, e := clickhouse.ParseDSN(dsn); e != nil {
@@ -171,6 +212,21 @@ func (cfg *Config) TableEngineString() string {
 	return fmt.Sprintf("%s(%s)", engine, params)
 }
 
+// RollupTableEngineString generates the ENGINE string of the rollup tables,
+// the AggregatingMergeTree variant of the table engine.
+func (cfg *Config) RollupTableEngineString() string {
+	engine := cfg.TableEngine.Name
+	params := cfg.TableEngine.Params
+
+	if cfg.TableEngine.Name == "" {
+		engine = defaultTableEngineName
+		params = ""
+	}
+
+	prefix, _ := strings.CutSuffix(engine, defaultTableEngineName)
+	return fmt.Sprintf("%sAggregating%s(%s)", prefix, defaultTableEngineName, params)
+}
+
 // ClusterString generates the ON CLUSTER string. Returns empty string if not set.
 func (cfg *Config) ClusterString() string {
 	if cfg.ClusterName == "" {
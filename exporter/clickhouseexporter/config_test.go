@@ -77,6 +77,19 @@ func TestLoadConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "rollups"),
+			expected: withDefaultConfig(func(cfg *Config) {
+				cfg.Endpoint = defaultEndpoint
+				cfg.TTL = 72 * time.Hour
+				cfg.TableTTLs = TableTTLs{
+					Logs:            24 * time.Hour,
+					MetricsRollup1m: 30 * 24 * time.Hour,
+					MetricsRollup1h: 365 * 24 * time.Hour,
+				}
+				cfg.MetricsRollups.Enabled = true
+			}),
+		},
 	}
 
 	for _, tt := range tests {
@@ -361,6 +374,60 @@ func TestTableEngineConfigParsing(t *testing.T) {
 	}
 }
 
+func TestConfig_rollupTableEngine(t *testing.T) {
+	t.Parallel()
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	cfg := createDefaultConfig()
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "rollups-invalid-table-engine").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+	assert.ErrorIs(t, component.ValidateConfig(cfg), errConfigRollupEngine)
+
+	// the table engine is only checked when the rollups are enabled
+	cfg.(*Config).MetricsRollups.Enabled = false
+	assert.NoError(t, component.ValidateConfig(cfg))
+}
+
+func TestRollupTableEngineString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input    TableEngine
+		expected string
+	}{
+		{
+			input:    TableEngine{},
+			expected: "AggregatingMergeTree()",
+		},
+		{
+			input:    TableEngine{Name: "MergeTree", Params: "whatever"},
+			expected: "AggregatingMergeTree(whatever)",
+		},
+		{
+			input:    TableEngine{Name: "ReplicatedMergeTree", Params: "'/clickhouse/tables/{shard}/{table}', '{replica}'"},
+			expected: "ReplicatedAggregatingMergeTree('/clickhouse/tables/{shard}/{table}', '{replica}')",
+		},
+		{
+			input:    TableEngine{Name: "SharedMergeTree"},
+			expected: "SharedAggregatingMergeTree()",
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("RollupTableEngineString case %d", i), func(t *testing.T) {
+			cfg := createDefaultConfig()
+			cfg.(*Config).Endpoint = defaultEndpoint
+			cfg.(*Config).TableEngine = tt.input
+			cfg.(*Config).MetricsRollups.Enabled = true
+
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg.(*Config).RollupTableEngineString())
+		})
+	}
+}
+
 func TestClusterString(t *testing.T) {
 	t.Parallel()
 
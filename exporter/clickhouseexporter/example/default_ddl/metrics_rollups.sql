@@ -0,0 +1,89 @@
+-- Default metrics rollup tables DDL, the 1 hour rollups are the same with `INTERVAL 1 HOUR`
+
+CREATE TABLE IF NOT EXISTS otel_metrics_gauge_1m (
+    ResourceAttributes Map(LowCardinality(String), String) CODEC(ZSTD(1)),
+    ScopeName String CODEC(ZSTD(1)),
+    ScopeVersion String CODEC(ZSTD(1)),
+    ServiceName LowCardinality(String) CODEC(ZSTD(1)),
+    MetricName String CODEC(ZSTD(1)),
+    MetricUnit String CODEC(ZSTD(1)),
+    Attributes Map(LowCardinality(String), String) CODEC(ZSTD(1)),
+    TimeUnix DateTime CODEC(Delta, ZSTD(1)),
+    Min SimpleAggregateFunction(min, Float64) CODEC(ZSTD(1)),
+    Max SimpleAggregateFunction(max, Float64) CODEC(ZSTD(1)),
+    Sum SimpleAggregateFunction(sum, Float64) CODEC(ZSTD(1)),
+    Count SimpleAggregateFunction(sum, UInt64) CODEC(ZSTD(1)),
+    Last AggregateFunction(argMax, Float64, DateTime64(9)) CODEC(ZSTD(1))
+) ENGINE = AggregatingMergeTree()
+TTL toDateTime(TimeUnix) + toIntervalDay(30)
+PARTITION BY toDate(TimeUnix)
+ORDER BY (ServiceName, MetricName, MetricUnit, ResourceAttributes, ScopeName, ScopeVersion, Attributes, TimeUnix)
+SETTINGS index_granularity=8192, ttl_only_drop_parts = 1;
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS otel_metrics_gauge_1m_mv
+TO otel_metrics_gauge_1m
+AS SELECT
+    ResourceAttributes,
+    ScopeName,
+    ScopeVersion,
+    ServiceName,
+    MetricName,
+    MetricUnit,
+    Attributes,
+    toStartOfInterval(Time, INTERVAL 1 MINUTE) AS TimeUnix,
+    min(Value) AS Min,
+    max(Value) AS Max,
+    sum(Value) AS Sum,
+    count() AS Count,
+    argMaxState(Value, Time) AS Last
+FROM (
+    SELECT ResourceAttributes, ScopeName, ScopeVersion, ServiceName, MetricName, MetricUnit, Attributes, TimeUnix AS Time, Value
+    FROM otel_metrics_gauge
+)
+GROUP BY ResourceAttributes, ScopeName, ScopeVersion, ServiceName, MetricName, MetricUnit, Attributes, TimeUnix;
+
+CREATE TABLE IF NOT EXISTS otel_metrics_sum_1m (
+    ResourceAttributes Map(LowCardinality(String), String) CODEC(ZSTD(1)),
+    ScopeName String CODEC(ZSTD(1)),
+    ScopeVersion String CODEC(ZSTD(1)),
+    ServiceName LowCardinality(String) CODEC(ZSTD(1)),
+    MetricName String CODEC(ZSTD(1)),
+    MetricUnit String CODEC(ZSTD(1)),
+    Attributes Map(LowCardinality(String), String) CODEC(ZSTD(1)),
+    AggTemp Int32 CODEC(ZSTD(1)),
+    IsMonotonic Boolean CODEC(Delta, ZSTD(1)),
+    TimeUnix DateTime CODEC(Delta, ZSTD(1)),
+    Min SimpleAggregateFunction(min, Float64) CODEC(ZSTD(1)),
+    Max SimpleAggregateFunction(max, Float64) CODEC(ZSTD(1)),
+    Sum SimpleAggregateFunction(sum, Float64) CODEC(ZSTD(1)),
+    Count SimpleAggregateFunction(sum, UInt64) CODEC(ZSTD(1)),
+    Last AggregateFunction(argMax, Float64, DateTime64(9)) CODEC(ZSTD(1))
+) ENGINE = AggregatingMergeTree()
+TTL toDateTime(TimeUnix) + toIntervalDay(30)
+PARTITION BY toDate(TimeUnix)
+ORDER BY (ServiceName, MetricName, MetricUnit, ResourceAttributes, ScopeName, ScopeVersion, Attributes, AggTemp, IsMonotonic, TimeUnix)
+SETTINGS index_granularity=8192, ttl_only_drop_parts = 1;
+
+CREATE MATERIALIZED VIEW IF NOT EXISTS otel_metrics_sum_1m_mv
+TO otel_metrics_sum_1m
+AS SELECT
+    ResourceAttributes,
+    ScopeName,
+    ScopeVersion,
+    ServiceName,
+    MetricName,
+    MetricUnit,
+    Attributes,
+    AggTemp,
+    IsMonotonic,
+    toStartOfInterval(Time, INTERVAL 1 MINUTE) AS TimeUnix,
+    min(Value) AS Min,
+    max(Value) AS Max,
+    sumIf(Value, AggTemp = 1) AS Sum,
+    count() AS Count,
+    argMaxState(Value, Time) AS Last
+FROM (
+    SELECT ResourceAttributes, ScopeName, ScopeVersion, ServiceName, MetricName, MetricUnit, Attributes, AggTemp, IsMonotonic, TimeUnix AS Time, Value
+    FROM otel_metrics_sum
+)
+GROUP BY ResourceAttributes, ScopeName, ScopeVersion, ServiceName, MetricName, MetricUnit, Attributes, AggTemp, IsMonotonic, TimeUnix;
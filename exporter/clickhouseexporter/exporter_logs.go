@@ -50,7 +50,15 @@ func (e *logsExporter) start(ctx context.Context, _ component.Host) error {
 		return err
 	}
 
-	return createLogsTable(ctx, e.cfg, e.client)
+	if err := createLogsTable(ctx, e.cfg, e.client); err != nil {
+		return err
+	}
+
+	if err := migrateSchema(ctx, e.cfg, e.client, e.logger, e.cfg.LogsTableName, logsSchemaMigrations); err != nil {
+		return err
+	}
+
+	return modifyTableTTL(ctx, e.cfg, e.client, e.logger, e.cfg.LogsTableName, tableTTLExpr(e.cfg, e.cfg.TableTTLs.Logs, "Timestamp"))
 }
 
 // shutdown will shut down the exporter.
@@ -238,7 +246,7 @@ func createLogsTable(ctx context.Context, cfg *Config, db *sql.DB) error {
 }
 
 func renderCreateLogsTableSQL(cfg *Config) string {
-	ttlExpr := generateTTLExpr(cfg.TTLDays, cfg.TTL, "Timestamp")
+	ttlExpr := tableTTLExpr(cfg, cfg.TableTTLs.Logs, "Timestamp")
 	return fmt.Sprintf(createLogsTableSQL, cfg.LogsTableName, cfg.ClusterString(), cfg.TableEngineString(), ttlExpr)
 }
 
@@ -8,6 +8,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"io"
 	"strings"
 	"testing"
 	"time"
@@ -79,7 +80,7 @@ func TestExporter_pushLogsData(t *testing.T) {
 		var items int
 		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
 			t.Logf("%d, values:%+v", items, values)
-			if strings.HasPrefix(query, "INSERT") {
+			if strings.HasPrefix(query, "INSERT INTO otel_logs") {
 				items++
 			}
 			return nil
@@ -93,7 +94,7 @@ func TestExporter_pushLogsData(t *testing.T) {
 	})
 	t.Run("test check resource metadata", func(t *testing.T) {
 		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
-			if strings.HasPrefix(query, "INSERT") {
+			if strings.HasPrefix(query, "INSERT INTO otel_logs") {
 				require.Equal(t, "https://opentelemetry.io/schemas/1.4.0", values[8])
 				require.Equal(t, map[string]string{
 					"service.name": "test-service",
@@ -106,7 +107,7 @@ func TestExporter_pushLogsData(t *testing.T) {
 	})
 	t.Run("test check scope metadata", func(t *testing.T) {
 		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
-			if strings.HasPrefix(query, "INSERT") {
+			if strings.HasPrefix(query, "INSERT INTO otel_logs") {
 				require.Equal(t, "https://opentelemetry.io/schemas/1.7.0", values[10])
 				require.Equal(t, "io.opentelemetry.contrib.clickhouse", values[11])
 				require.Equal(t, "1.0.0", values[12])
@@ -187,9 +188,15 @@ func mustPushLogsData(t *testing.T, exporter *logsExporter, ld plog.Logs) {
 }
 
 func initClickhouseTestServer(t *testing.T, recorder recorder) {
+	initClickhouseTestServerWithRows(t, recorder, nil)
+}
+
+// initClickhouseTestServerWithRows registers a test driver whose queries return the rows.
+func initClickhouseTestServerWithRows(t *testing.T, recorder recorder, rows [][]driver.Value) {
 	driverName = t.Name()
 	sql.Register(t.Name(), &testClickhouseDriver{
 		recorder: recorder,
+		rows:     rows,
 	})
 }
 
@@ -197,22 +204,26 @@ type recorder func(query string, values []driver.Value) error
 
 type testClickhouseDriver struct {
 	recorder recorder
+	rows     [][]driver.Value
 }
 
 func (t *testClickhouseDriver) Open(_ string) (driver.Conn, error) {
 	return &testClickhouseDriverConn{
 		recorder: t.recorder,
+		rows:     t.rows,
 	}, nil
 }
 
 type testClickhouseDriverConn struct {
 	recorder recorder
+	rows     [][]driver.Value
 }
 
 func (t *testClickhouseDriverConn) Prepare(query string) (driver.Stmt, error) {
 	return &testClickhouseDriverStmt{
 		query:    query,
 		recorder: t.recorder,
+		rows:     t.rows,
 	}, nil
 }
 
@@ -231,6 +242,7 @@ func (*testClickhouseDriverConn) CheckNamedValue(_ *driver.NamedValue) error {
 type testClickhouseDriverStmt struct {
 	query    string
 	recorder recorder
+	rows     [][]driver.Value
 }
 
 func (*testClickhouseDriverStmt) Close() error {
@@ -245,8 +257,35 @@ func (t *testClickhouseDriverStmt) Exec(args []driver.Value) (driver.Result, err
 	return nil, t.recorder(t.query, args)
 }
 
-func (t *testClickhouseDriverStmt) Query(_ []driver.Value) (driver.Rows, error) {
-	return nil, nil
+func (t *testClickhouseDriverStmt) Query(args []driver.Value) (driver.Rows, error) {
+	if err := t.recorder(t.query, args); err != nil {
+		return nil, err
+	}
+	return &testClickhouseDriverRows{rows: t.rows}, nil
+}
+
+type testClickhouseDriverRows struct {
+	rows [][]driver.Value
+}
+
+func (t *testClickhouseDriverRows) Columns() []string {
+	if len(t.rows) == 0 {
+		return nil
+	}
+	return make([]string, len(t.rows[0]))
+}
+
+func (*testClickhouseDriverRows) Close() error {
+	return nil
+}
+
+func (t *testClickhouseDriverRows) Next(dest []driver.Value) error {
+	if len(t.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, t.rows[0])
+	t.rows = t.rows[1:]
+	return nil
 }
 
 type testClickhouseDriverTx struct {
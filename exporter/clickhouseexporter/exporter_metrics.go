@@ -47,8 +47,52 @@ func (e *metricsExporter) start(ctx context.Context, _ component.Host) error {
 		return err
 	}
 
-	ttlExpr := generateTTLExpr(e.cfg.TTLDays, e.cfg.TTL, "TimeUnix")
-	return internal.NewMetricsTable(ctx, e.cfg.MetricsTableName, e.cfg.ClusterString(), e.cfg.TableEngineString(), ttlExpr, e.client)
+	ttlExpr := tableTTLExpr(e.cfg, e.cfg.TableTTLs.Metrics, "TimeUnix")
+	if err := internal.NewMetricsTable(ctx, e.cfg.MetricsTableName, e.cfg.ClusterString(), e.cfg.TableEngineString(), ttlExpr, e.client); err != nil {
+		return err
+	}
+
+	if err := migrateSchema(ctx, e.cfg, e.client, e.logger, e.cfg.MetricsTableName, metricsSchemaMigrations); err != nil {
+		return err
+	}
+
+	for _, tableName := range internal.MetricsTableNames(e.cfg.MetricsTableName) {
+		if err := modifyTableTTL(ctx, e.cfg, e.client, e.logger, tableName, ttlExpr); err != nil {
+			return err
+		}
+	}
+
+	if !e.cfg.MetricsRollups.Enabled {
+		return nil
+	}
+	rollups := metricsRollups(e.cfg)
+	if err := internal.NewMetricsRollupTables(ctx, e.cfg.Database, e.cfg.MetricsTableName, e.cfg.ClusterString(),
+		e.cfg.RollupTableEngineString(), rollups, e.client); err != nil {
+		return err
+	}
+
+	for _, rollup := range rollups {
+		for _, tableName := range rollup.TableNames(e.cfg.MetricsTableName) {
+			if err := migrateSchema(ctx, e.cfg, e.client, e.logger, tableName, metricsRollupSchemaMigrations); err != nil {
+				return err
+			}
+			if err := migrateSchema(ctx, e.cfg, e.client, e.logger, tableName+"_mv", metricsRollupViewSchemaMigrations); err != nil {
+				return err
+			}
+			if err := modifyTableTTL(ctx, e.cfg, e.client, e.logger, tableName, rollup.TTLExpr); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// metricsRollups returns the 1 minute and 1 hour rollups of the metrics.
+func metricsRollups(cfg *Config) []internal.MetricsRollup {
+	return []internal.MetricsRollup{
+		{Name: "1m", Interval: "1 MINUTE", TTLExpr: tableTTLExpr(cfg, cfg.TableTTLs.MetricsRollup1m, "TimeUnix")},
+		{Name: "1h", Interval: "1 HOUR", TTLExpr: tableTTLExpr(cfg, cfg.TableTTLs.MetricsRollup1h, "TimeUnix")},
+	}
 }
 
 // shutdown will shut down the exporter.
@@ -33,12 +33,96 @@ func TestMetricsTableEngineConfig(t *testing.T) {
 	})
 }
 
+func TestMetricsExporter_rollups(t *testing.T) {
+	t.Run("enabled", func(t *testing.T) {
+		var queries, migrated []string
+		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
+			if strings.Contains(query, "_1m") || strings.Contains(query, "_1h") {
+				queries = append(queries, query)
+			}
+			if strings.HasPrefix(query, "INSERT INTO otel_schema_migrations") {
+				migrated = append(migrated, values[0].(string))
+			}
+			return nil
+		})
+		newTestMetricsExporter(t, defaultEndpoint, func(cfg *Config) {
+			cfg.TTL = 72 * time.Hour
+			cfg.TableTTLs.MetricsRollup1h = 365 * 24 * time.Hour
+			cfg.MetricsRollups.Enabled = true
+		})
+
+		require.Len(t, queries, 8)
+		firstLines := make([]string, 0, len(queries))
+		for _, query := range queries {
+			firstLines = append(firstLines, getQueryFirstLine(query))
+			if strings.HasPrefix(query, "\nCREATE TABLE") {
+				require.NoError(t, checkTableEngineQueryDefinition(query, "AggregatingMergeTree()"))
+			}
+			switch {
+			case strings.Contains(query, "TABLE IF NOT EXISTS otel_metrics_gauge_1m"), strings.Contains(query, "TABLE IF NOT EXISTS otel_metrics_sum_1m"):
+				require.Contains(t, query, "TTL toDateTime(TimeUnix) + toIntervalDay(3)")
+			case strings.Contains(query, "TABLE IF NOT EXISTS otel_metrics_gauge_1h"), strings.Contains(query, "TABLE IF NOT EXISTS otel_metrics_sum_1h"):
+				require.Contains(t, query, "TTL toDateTime(TimeUnix) + toIntervalDay(365)")
+			case strings.Contains(query, "VIEW IF NOT EXISTS otel_metrics_gauge_1m_mv"):
+				require.Contains(t, query, "TO default.otel_metrics_gauge_1m")
+				require.Contains(t, query, "INTERVAL 1 MINUTE")
+				require.Contains(t, query, "FROM default.otel_metrics_gauge\n")
+			case strings.Contains(query, "VIEW IF NOT EXISTS otel_metrics_sum_1h_mv"):
+				require.Contains(t, query, "TO default.otel_metrics_sum_1h")
+				require.Contains(t, query, "INTERVAL 1 HOUR")
+				require.Contains(t, query, "FROM default.otel_metrics_sum\n")
+				require.Contains(t, query, "sumIf(Value, AggTemp = 1) AS Sum")
+			}
+			// the time series of different resources and scopes are not merged together
+			if strings.HasPrefix(query, "\nCREATE TABLE") {
+				require.Contains(t, query, "ResourceAttributes, ScopeName, ScopeVersion, Attributes,")
+			} else {
+				require.Contains(t, query, "GROUP BY ResourceAttributes, ScopeName, ScopeVersion,")
+			}
+		}
+		require.ElementsMatch(t, []string{
+			"CREATE TABLE IF NOT EXISTS otel_metrics_gauge_1m",
+			"CREATE MATERIALIZED VIEW IF NOT EXISTS otel_metrics_gauge_1m_mv",
+			"CREATE TABLE IF NOT EXISTS otel_metrics_sum_1m",
+			"CREATE MATERIALIZED VIEW IF NOT EXISTS otel_metrics_sum_1m_mv",
+			"CREATE TABLE IF NOT EXISTS otel_metrics_gauge_1h",
+			"CREATE MATERIALIZED VIEW IF NOT EXISTS otel_metrics_gauge_1h_mv",
+			"CREATE TABLE IF NOT EXISTS otel_metrics_sum_1h",
+			"CREATE MATERIALIZED VIEW IF NOT EXISTS otel_metrics_sum_1h_mv",
+		}, firstLines)
+		// the schema of the rollup tables and of their views is recorded
+		require.ElementsMatch(t, []string{
+			"otel_metrics",
+			"otel_metrics_gauge_1m", "otel_metrics_gauge_1m_mv", "otel_metrics_sum_1m", "otel_metrics_sum_1m_mv",
+			"otel_metrics_gauge_1h", "otel_metrics_gauge_1h_mv", "otel_metrics_sum_1h", "otel_metrics_sum_1h_mv",
+		}, migrated)
+	})
+	t.Run("disabled", func(t *testing.T) {
+		initClickhouseTestServer(t, func(query string, _ []driver.Value) error {
+			require.NotContains(t, query, "_1m")
+			require.NotContains(t, query, "_1h")
+			return nil
+		})
+		newTestMetricsExporter(t, defaultEndpoint)
+	})
+}
+
+func TestMetricsRollupsClusterConfig(t *testing.T) {
+	testClusterConfig(t, func(t *testing.T, dsn string, clusterTest clusterTestConfig, fns ...func(*Config)) {
+		fns = append(fns, func(cfg *Config) {
+			cfg.MetricsRollups.Enabled = true
+		})
+		exporter := newTestMetricsExporter(t, dsn, fns...)
+		clusterTest.verifyConfig(t, exporter.cfg)
+	})
+}
+
 func TestExporter_pushMetricsData(t *testing.T) {
 	t.Parallel()
 	t.Run("push success", func(t *testing.T) {
 		items := &atomic.Int32{}
 		initClickhouseTestServer(t, func(query string, _ []driver.Value) error {
-			if strings.HasPrefix(query, "INSERT") {
+			if strings.HasPrefix(query, "INSERT INTO otel_metrics") {
 				items.Add(1)
 			}
 			return nil
@@ -50,7 +134,7 @@ func TestExporter_pushMetricsData(t *testing.T) {
 	})
 	t.Run("push failure", func(t *testing.T) {
 		initClickhouseTestServer(t, func(query string, _ []driver.Value) error {
-			if strings.HasPrefix(query, "INSERT") {
+			if strings.HasPrefix(query, "INSERT INTO otel_metrics") {
 				return fmt.Errorf("mock insert error")
 			}
 			return nil
@@ -72,7 +156,7 @@ func TestExporter_pushMetricsData(t *testing.T) {
 			"otel_metrics_summary":               {},
 		}
 		initClickhouseTestServer(t, func(query string, values []driver.Value) error {
-			if strings.HasPrefix(query, "INSERT") {
+			if strings.HasPrefix(query, "INSERT INTO otel_metrics") {
 				items.Add(1)
 				if strings.HasPrefix(query, "INSERT INTO otel_metrics_exponential_histogram") {
 					idx := itemIdxs["otel_metrics_exponential_histogram"]
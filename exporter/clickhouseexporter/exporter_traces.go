@@ -50,7 +50,18 @@ func (e *tracesExporter) start(ctx context.Context, _ component.Host) error {
 		return err
 	}
 
-	return createTracesTable(ctx, e.cfg, e.client)
+	if err := createTracesTable(ctx, e.cfg, e.client); err != nil {
+		return err
+	}
+
+	if err := migrateSchema(ctx, e.cfg, e.client, e.logger, e.cfg.TracesTableName, tracesSchemaMigrations); err != nil {
+		return err
+	}
+
+	if err := modifyTableTTL(ctx, e.cfg, e.client, e.logger, e.cfg.TracesTableName, tableTTLExpr(e.cfg, e.cfg.TableTTLs.Traces, "Timestamp")); err != nil {
+		return err
+	}
+	return modifyTableTTL(ctx, e.cfg, e.client, e.logger, e.cfg.TracesTableName+"_trace_id_ts", tableTTLExpr(e.cfg, e.cfg.TableTTLs.Traces, "Start"))
 }
 
 // shutdown will shut down the exporter.
@@ -295,12 +306,12 @@ func renderInsertTracesSQL(cfg *Config) string {
 }
 
 func renderCreateTracesTableSQL(cfg *Config) string {
-	ttlExpr := generateTTLExpr(cfg.TTLDays, cfg.TTL, "Timestamp")
+	ttlExpr := tableTTLExpr(cfg, cfg.TableTTLs.Traces, "Timestamp")
 	return fmt.Sprintf(createTracesTableSQL, cfg.TracesTableName, cfg.ClusterString(), cfg.TableEngineString(), ttlExpr)
 }
 
 func renderCreateTraceIDTsTableSQL(cfg *Config) string {
-	ttlExpr := generateTTLExpr(cfg.TTLDays, cfg.TTL, "Start")
+	ttlExpr := tableTTLExpr(cfg, cfg.TableTTLs.Traces, "Start")
 	return fmt.Sprintf(createTraceIDTsTableSQL, cfg.TracesTableName, cfg.ClusterString(), cfg.TableEngineString(), ttlExpr)
 }
 
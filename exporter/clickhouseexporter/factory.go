@@ -143,3 +143,11 @@ func generateTTLExpr(ttlDays uint, ttl time.Duration, timeField string) string {
 	}
 	return ""
 }
+
+// tableTTLExpr generates the TTL expression of a table, its own time-to-live overriding the one of the config.
+func tableTTLExpr(cfg *Config, tableTTL time.Duration, timeField string) string {
+	if tableTTL > 0 {
+		return generateTTLExpr(0, tableTTL, timeField)
+	}
+	return generateTTLExpr(cfg.TTLDays, cfg.TTL, timeField)
+}
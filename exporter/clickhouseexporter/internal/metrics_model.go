@@ -61,6 +61,17 @@ func NewMetricsTable(ctx context.Context, tableName, cluster, engine, ttlExpr st
 	return nil
 }
 
+// MetricsTableNames returns the names of the tables of the metric types.
+func MetricsTableNames(tableName string) []string {
+	return []string{
+		tableName + "_gauge",
+		tableName + "_sum",
+		tableName + "_histogram",
+		tableName + "_exponential_histogram",
+		tableName + "_summary",
+	}
+}
+
 // NewMetricsModel create a model for contain different metric data
 func NewMetricsModel(tableName string) map[pmetric.MetricType]MetricsModel {
 	return map[pmetric.MetricType]MetricsModel{
@@ -0,0 +1,155 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package internal // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter/internal"
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+const (
+	// language=ClickHouse SQL
+	createGaugeRollupTableSQL = `
+CREATE TABLE IF NOT EXISTS %s_gauge_%s %s (
+    ResourceAttributes Map(LowCardinality(String), String) CODEC(ZSTD(1)),
+    ScopeName String CODEC(ZSTD(1)),
+    ScopeVersion String CODEC(ZSTD(1)),
+    ServiceName LowCardinality(String) CODEC(ZSTD(1)),
+    MetricName String CODEC(ZSTD(1)),
+    MetricUnit String CODEC(ZSTD(1)),
+    Attributes Map(LowCardinality(String), String) CODEC(ZSTD(1)),
+    TimeUnix DateTime CODEC(Delta, ZSTD(1)),
+    Min SimpleAggregateFunction(min, Float64) CODEC(ZSTD(1)),
+    Max SimpleAggregateFunction(max, Float64) CODEC(ZSTD(1)),
+    Sum SimpleAggregateFunction(sum, Float64) CODEC(ZSTD(1)),
+    Count SimpleAggregateFunction(sum, UInt64) CODEC(ZSTD(1)),
+    Last AggregateFunction(argMax, Float64, DateTime64(9)) CODEC(ZSTD(1))
+) ENGINE = %s
+%s
+PARTITION BY toDate(TimeUnix)
+ORDER BY (ServiceName, MetricName, MetricUnit, ResourceAttributes, ScopeName, ScopeVersion, Attributes, TimeUnix)
+SETTINGS index_granularity=8192, ttl_only_drop_parts = 1;
+`
+	// language=ClickHouse SQL
+	createGaugeRollupMaterializedViewSQL = `
+CREATE MATERIALIZED VIEW IF NOT EXISTS %s_gauge_%s_mv %s
+TO %s.%s_gauge_%s
+AS SELECT
+    ResourceAttributes,
+    ScopeName,
+    ScopeVersion,
+    ServiceName,
+    MetricName,
+    MetricUnit,
+    Attributes,
+    toStartOfInterval(Time, INTERVAL %s) AS TimeUnix,
+    min(Value) AS Min,
+    max(Value) AS Max,
+    sum(Value) AS Sum,
+    count() AS Count,
+    argMaxState(Value, Time) AS Last
+FROM (
+    SELECT ResourceAttributes, ScopeName, ScopeVersion, ServiceName, MetricName, MetricUnit, Attributes, TimeUnix AS Time, Value
+    FROM %s.%s_gauge
+)
+GROUP BY ResourceAttributes, ScopeName, ScopeVersion, ServiceName, MetricName, MetricUnit, Attributes, TimeUnix;
+`
+	// language=ClickHouse SQL
+	createSumRollupTableSQL = `
+CREATE TABLE IF NOT EXISTS %s_sum_%s %s (
+    ResourceAttributes Map(LowCardinality(String), String) CODEC(ZSTD(1)),
+    ScopeName String CODEC(ZSTD(1)),
+    ScopeVersion String CODEC(ZSTD(1)),
+    ServiceName LowCardinality(String) CODEC(ZSTD(1)),
+    MetricName String CODEC(ZSTD(1)),
+    MetricUnit String CODEC(ZSTD(1)),
+    Attributes Map(LowCardinality(String), String) CODEC(ZSTD(1)),
+    AggTemp Int32 CODEC(ZSTD(1)),
+    IsMonotonic Boolean CODEC(Delta, ZSTD(1)),
+    TimeUnix DateTime CODEC(Delta, ZSTD(1)),
+    Min SimpleAggregateFunction(min, Float64) CODEC(ZSTD(1)),
+    Max SimpleAggregateFunction(max, Float64) CODEC(ZSTD(1)),
+    Sum SimpleAggregateFunction(sum, Float64) CODEC(ZSTD(1)),
+    Count SimpleAggregateFunction(sum, UInt64) CODEC(ZSTD(1)),
+    Last AggregateFunction(argMax, Float64, DateTime64(9)) CODEC(ZSTD(1))
+) ENGINE = %s
+%s
+PARTITION BY toDate(TimeUnix)
+ORDER BY (ServiceName, MetricName, MetricUnit, ResourceAttributes, ScopeName, ScopeVersion, Attributes, AggTemp, IsMonotonic, TimeUnix)
+SETTINGS index_granularity=8192, ttl_only_drop_parts = 1;
+`
+	// The cumulative data points are running totals, adding them up is meaningless, so only the delta data
+	// points (AggTemp = 1) are summed up.
+	// language=ClickHouse SQL
+	createSumRollupMaterializedViewSQL = `
+CREATE MATERIALIZED VIEW IF NOT EXISTS %s_sum_%s_mv %s
+TO %s.%s_sum_%s
+AS SELECT
+    ResourceAttributes,
+    ScopeName,
+    ScopeVersion,
+    ServiceName,
+    MetricName,
+    MetricUnit,
+    Attributes,
+    AggTemp,
+    IsMonotonic,
+    toStartOfInterval(Time, INTERVAL %s) AS TimeUnix,
+    min(Value) AS Min,
+    max(Value) AS Max,
+    sumIf(Value, AggTemp = 1) AS Sum,
+    count() AS Count,
+    argMaxState(Value, Time) AS Last
+FROM (
+    SELECT ResourceAttributes, ScopeName, ScopeVersion, ServiceName, MetricName, MetricUnit, Attributes, AggTemp, IsMonotonic, TimeUnix AS Time, Value
+    FROM %s.%s_sum
+)
+GROUP BY ResourceAttributes, ScopeName, ScopeVersion, ServiceName, MetricName, MetricUnit, Attributes, AggTemp, IsMonotonic, TimeUnix;
+`
+)
+
+// rollupMetricTypes maps the DDL of the rollup tables to the DDL of the materialized views populating them.
+var rollupMetricTypes = map[string]string{
+	createGaugeRollupTableSQL: createGaugeRollupMaterializedViewSQL,
+	createSumRollupTableSQL:   createSumRollupMaterializedViewSQL,
+}
+
+// MetricsRollup downsamples the gauge and sum metrics, aggregating their data points over an interval.
+type MetricsRollup struct {
+	// Name is the suffix of the rollup tables, for example 1m.
+	Name string
+	// Interval is the ClickHouse interval the data points are aggregated over, for example 1 MINUTE.
+	Interval string
+	// TTLExpr is the TTL expression of the rollup tables.
+	TTLExpr string
+}
+
+// TableNames returns the names of the rollup tables of the metrics, each populated by the materialized view
+// named after it with the _mv suffix.
+func (r MetricsRollup) TableNames(tableName string) []string {
+	return []string{
+		fmt.Sprintf("%s_gauge_%s", tableName, r.Name),
+		fmt.Sprintf("%s_sum_%s", tableName, r.Name),
+	}
+}
+
+// NewMetricsRollupTables create the rollup tables of the gauge and sum metrics, populated by materialized views
+// on the metric tables. The rollup tables keep the min, max, sum, count and last value of the data points of each
+// time series, identified by its resource and scope attributes along with the data point attributes.
+func NewMetricsRollupTables(ctx context.Context, database, tableName, cluster, engine string, rollups []MetricsRollup, db *sql.DB) error {
+	for _, rollup := range rollups {
+		for table, view := range rollupMetricTypes {
+			query := fmt.Sprintf(table, tableName, rollup.Name, cluster, engine, rollup.TTLExpr)
+			if _, err := db.ExecContext(ctx, query); err != nil {
+				return fmt.Errorf("exec create metrics rollup table sql: %w", err)
+			}
+			query = fmt.Sprintf(view, tableName, rollup.Name, cluster, database, tableName, rollup.Name, rollup.Interval, database, tableName)
+			if _, err := db.ExecContext(ctx, query); err != nil {
+				return fmt.Errorf("exec create metrics rollup view sql: %w", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouseexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/clickhouseexporter"
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// schemaMigrationsTableName is the table recording the schema version of the tables created by the exporter.
+const schemaMigrationsTableName = "otel_schema_migrations"
+
+const (
+	// language=ClickHouse SQL
+	createSchemaMigrationsTableSQL = `
+CREATE TABLE IF NOT EXISTS %s %s (
+    TableName String CODEC(ZSTD(1)),
+    Version UInt32 CODEC(ZSTD(1)),
+    Description String CODEC(ZSTD(1)),
+    AppliedAt DateTime DEFAULT now() CODEC(Delta, ZSTD(1))
+) ENGINE = %s
+ORDER BY (TableName, Version);
+`
+	// language=ClickHouse SQL
+	selectSchemaVersionSQL = `SELECT max(Version) FROM %s WHERE TableName = ?`
+	// language=ClickHouse SQL
+	insertSchemaMigrationSQL = `INSERT INTO %s (TableName, Version, Description) VALUES (?, ?, ?)`
+	// language=ClickHouse SQL
+	selectCreateTableQuerySQL = `SELECT create_table_query FROM system.tables WHERE database = ? AND name = ?`
+	// language=ClickHouse SQL
+	modifyTableTTLSQL = `ALTER TABLE %s.%s %s MODIFY %s`
+)
+
+// schemaMigration changes the schema of the tables created by an older version of the exporter.
+// The tables are created with the latest schema, and the tables created before the migrations were
+// recorded have no version: the statements must be idempotent, such as ADD COLUMN IF NOT EXISTS.
+type schemaMigration struct {
+	version     uint32
+	description string
+	statements  func(cfg *Config) []string
+}
+
+// The migrations of the tables by ascending version, the first version being the schema the tables were
+// created with when the migrations were introduced.
+var (
+	logsSchemaMigrations = []schemaMigration{
+		{version: 1, description: "initial schema"},
+	}
+	tracesSchemaMigrations = []schemaMigration{
+		{version: 1, description: "initial schema"},
+	}
+	metricsSchemaMigrations = []schemaMigration{
+		{version: 1, description: "initial schema"},
+	}
+	// the rollup tables and the materialized views populating them are migrated separately, as changing the
+	// aggregation of a view requires to replace it.
+	metricsRollupSchemaMigrations = []schemaMigration{
+		{version: 1, description: "initial schema"},
+	}
+	metricsRollupViewSchemaMigrations = []schemaMigration{
+		{version: 1, description: "initial schema"},
+	}
+)
+
+// migrateSchema applies the migrations of a table newer than its recorded version, and records them.
+func migrateSchema(ctx context.Context, cfg *Config, db *sql.DB, logger *zap.Logger, tableName string, migrations []schemaMigration) error {
+	if _, err := db.ExecContext(ctx, renderCreateSchemaMigrationsTableSQL(cfg)); err != nil {
+		return fmt.Errorf("exec create schema migrations table sql: %w", err)
+	}
+
+	var version uint32
+	err := db.QueryRowContext(ctx, fmt.Sprintf(selectSchemaVersionSQL, schemaMigrationsTableName), tableName).Scan(&version)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("select schema version: %w", err)
+	}
+
+	latest := migrations[len(migrations)-1].version
+	if version > latest {
+		logger.Warn("The table was migrated by a newer version of the exporter, its schema may be incompatible",
+			zap.String("table", tableName),
+			zap.Uint32("version", version),
+			zap.Uint32("exporter_version", latest))
+		return nil
+	}
+
+	for _, migration := range migrations {
+		if migration.version <= version {
+			continue
+		}
+		if migration.statements != nil {
+			for _, statement := range migration.statements(cfg) {
+				if _, err := db.ExecContext(ctx, statement); err != nil {
+					return fmt.Errorf("migrate %s to version %d: %w", tableName, migration.version, err)
+				}
+			}
+		}
+		err := doWithTx(ctx, db, func(tx *sql.Tx) error {
+			statement, err := tx.PrepareContext(ctx, fmt.Sprintf(insertSchemaMigrationSQL, schemaMigrationsTableName))
+			if err != nil {
+				return fmt.Errorf("PrepareContext:%w", err)
+			}
+			defer func() {
+				_ = statement.Close()
+			}()
+			if _, err := statement.ExecContext(ctx, tableName, migration.version, migration.description); err != nil {
+				return fmt.Errorf("ExecContext:%w", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("record schema version %d of %s: %w", migration.version, tableName, err)
+		}
+		logger.Info("Migrated the table schema",
+			zap.String("table", tableName),
+			zap.Uint32("version", migration.version),
+			zap.String("description", migration.description))
+	}
+	return nil
+}
+
+// modifyTableTTL sets the TTL of a table created before the TTL was configured or changed, which CREATE TABLE
+// IF NOT EXISTS leaves as it is. The table is only altered when its TTL differs, as ClickHouse applies the new
+// TTL to all the existing data, and the TTL of the table is kept when no TTL is configured.
+func modifyTableTTL(ctx context.Context, cfg *Config, db *sql.DB, logger *zap.Logger, tableName, ttlExpr string) error {
+	if ttlExpr == "" {
+		return nil
+	}
+
+	var createTableQuery string
+	err := db.QueryRowContext(ctx, selectCreateTableQuerySQL, cfg.Database, tableName).Scan(&createTableQuery)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("select create table query of %s: %w", tableName, err)
+	}
+	if strings.Contains(createTableQuery, ttlExpr) {
+		return nil
+	}
+
+	if _, err := db.ExecContext(ctx, fmt.Sprintf(modifyTableTTLSQL, cfg.Database, tableName, cfg.ClusterString(), ttlExpr)); err != nil {
+		return fmt.Errorf("modify TTL of %s: %w", tableName, err)
+	}
+	logger.Info("Modified the table TTL",
+		zap.String("table", tableName),
+		zap.String("ttl", ttlExpr))
+	return nil
+}
+
+func renderCreateSchemaMigrationsTableSQL(cfg *Config) string {
+	return fmt.Sprintf(createSchemaMigrationsTableSQL, schemaMigrationsTableName, cfg.ClusterString(), cfg.TableEngineString())
+}
@@ -0,0 +1,170 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package clickhouseexporter
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+)
+
+var testSchemaMigrations = []schemaMigration{
+	{version: 1, description: "initial schema"},
+	{
+		version:     2,
+		description: "add the Foo column",
+		statements: func(cfg *Config) []string {
+			return []string{"ALTER TABLE " + cfg.LogsTableName + " ADD COLUMN IF NOT EXISTS Foo String"}
+		},
+	},
+}
+
+func TestMigrateSchema(t *testing.T) {
+	tests := []struct {
+		name             string
+		version          int64
+		expectedAlter    bool
+		expectedVersions []driver.Value
+	}{
+		{
+			name:             "no version",
+			expectedAlter:    true,
+			expectedVersions: []driver.Value{uint32(1), uint32(2)},
+		},
+		{
+			name:             "older version",
+			version:          1,
+			expectedAlter:    true,
+			expectedVersions: []driver.Value{uint32(2)},
+		},
+		{
+			name:    "latest version",
+			version: 2,
+		},
+		{
+			name:    "newer version",
+			version: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				alter    bool
+				versions []driver.Value
+			)
+			initClickhouseTestServerWithRows(t, func(query string, values []driver.Value) error {
+				switch {
+				case strings.HasPrefix(query, "SELECT"):
+					assert.Equal(t, []driver.Value{"otel_logs"}, values)
+				case strings.HasPrefix(query, "ALTER"):
+					alter = true
+				case strings.HasPrefix(query, "INSERT"):
+					assert.Equal(t, "otel_logs", values[0])
+					versions = append(versions, values[1])
+				}
+				return nil
+			}, [][]driver.Value{{tt.version}})
+
+			cfg := withTestExporterConfig()(defaultEndpoint)
+			db, err := newClickhouseClient(cfg)
+			require.NoError(t, err)
+			defer func() { _ = db.Close() }()
+
+			require.NoError(t, migrateSchema(context.Background(), cfg, db, zaptest.NewLogger(t), cfg.LogsTableName, testSchemaMigrations))
+			assert.Equal(t, tt.expectedAlter, alter)
+			assert.Equal(t, tt.expectedVersions, versions)
+		})
+	}
+}
+
+func TestMigrateSchema_failure(t *testing.T) {
+	recorded := false
+	initClickhouseTestServer(t, func(query string, _ []driver.Value) error {
+		switch {
+		case strings.HasPrefix(query, "ALTER"):
+			return errors.New("mock alter error")
+		case strings.HasPrefix(query, "INSERT"):
+			recorded = true
+		}
+		return nil
+	})
+
+	cfg := withTestExporterConfig()(defaultEndpoint)
+	db, err := newClickhouseClient(cfg)
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	err = migrateSchema(context.Background(), cfg, db, zaptest.NewLogger(t), cfg.LogsTableName, testSchemaMigrations)
+	assert.ErrorContains(t, err, "migrate otel_logs to version 2")
+	// the versions up to the failed migration are recorded
+	assert.True(t, recorded)
+}
+
+func TestModifyTableTTL(t *testing.T) {
+	const ttlExpr = "TTL toDateTime(Timestamp) + toIntervalDay(3)"
+	tests := []struct {
+		name          string
+		ttlExpr       string
+		rows          [][]driver.Value
+		expectedAlter string
+	}{
+		{
+			name:    "no TTL",
+			ttlExpr: "",
+			rows:    [][]driver.Value{{"CREATE TABLE default.otel_logs (...) TTL toDateTime(Timestamp) + toIntervalDay(30)"}},
+		},
+		{
+			name:    "same TTL",
+			ttlExpr: ttlExpr,
+			rows:    [][]driver.Value{{"CREATE TABLE default.otel_logs (...) TTL toDateTime(Timestamp) + toIntervalDay(3) SETTINGS index_granularity = 8192"}},
+		},
+		{
+			name:          "different TTL",
+			ttlExpr:       ttlExpr,
+			rows:          [][]driver.Value{{"CREATE TABLE default.otel_logs (...) TTL toDateTime(Timestamp) + toIntervalDay(30) SETTINGS index_granularity = 8192"}},
+			expectedAlter: "ALTER TABLE default.otel_logs  MODIFY " + ttlExpr,
+		},
+		{
+			name:          "no TTL yet",
+			ttlExpr:       ttlExpr,
+			rows:          [][]driver.Value{{"CREATE TABLE default.otel_logs (...) SETTINGS index_granularity = 8192"}},
+			expectedAlter: "ALTER TABLE default.otel_logs  MODIFY " + ttlExpr,
+		},
+		{
+			name:    "missing table",
+			ttlExpr: ttlExpr,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var selected bool
+			var alter string
+			initClickhouseTestServerWithRows(t, func(query string, values []driver.Value) error {
+				switch {
+				case strings.HasPrefix(query, "SELECT"):
+					selected = true
+					assert.Equal(t, []driver.Value{"default", "otel_logs"}, values)
+				case strings.HasPrefix(query, "ALTER"):
+					alter = query
+				}
+				return nil
+			}, tt.rows)
+
+			cfg := withTestExporterConfig()(defaultEndpoint)
+			db, err := newClickhouseClient(cfg)
+			require.NoError(t, err)
+			defer func() { _ = db.Close() }()
+
+			require.NoError(t, modifyTableTTL(context.Background(), cfg, db, zaptest.NewLogger(t), cfg.LogsTableName, tt.ttlExpr))
+			assert.Equal(t, tt.ttlExpr != "", selected)
+			assert.Equal(t, tt.expectedAlter, alter)
+		})
+	}
+}
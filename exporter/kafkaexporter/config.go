@@ -13,6 +13,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
 )
 
 // Config defines configuration for Kafka exporter.
@@ -62,6 +63,35 @@ type Config struct {
 
 	// Authentication defines used authentication mechanism.
 	Authentication kafka.Authentication `mapstructure:"auth"`
+
+	// SchemaRegistry configures the schema registry used by the avro and protobuf encodings.
+	SchemaRegistry SchemaRegistry `mapstructure:"schema_registry"`
+}
+
+// SchemaRegistry defines the schema registry the messages of the avro and protobuf encodings are
+// serialized with.
+type SchemaRegistry struct {
+	schemaregistry.ClientConfig `mapstructure:",squash"`
+
+	// Subject the schema is registered under (default <topic>-value).
+	Subject string `mapstructure:"subject"`
+
+	// Schema the log bodies are serialized with. When empty, the latest version of the schema
+	// registered under the subject is used.
+	Schema string `mapstructure:"schema"`
+
+	// Message is the full name of the Protobuf message the log bodies are serialized as,
+	// the first message of the schema is used when it's empty.
+	Message string `mapstructure:"message"`
+
+	// AutoRegister registers the schema under the subject when it's not registered yet,
+	// otherwise it must already be registered (default true).
+	AutoRegister bool `mapstructure:"auto_register"`
+
+	// RefreshInterval is how long the latest version of the schema registered under the subject
+	// is used before it's requested again, when Schema is empty. It's never requested again when
+	// it's zero.
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
 }
 
 // Metadata defines configuration for retrieving metadata from the broker.
@@ -125,6 +155,10 @@ func (cfg *Config) Validate() error {
 		return err
 	}
 
+	if isSchemaRegistryEncoding(cfg.Encoding) && cfg.SchemaRegistry.Endpoint == "" {
+		return fmt.Errorf("schema_registry.endpoint is required by the %s encoding", cfg.Encoding)
+	}
+
 	return validateSASLConfig(cfg.Authentication.SASL)
 }
 
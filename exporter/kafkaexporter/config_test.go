@@ -20,6 +20,7 @@ import (
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter/internal/metadata"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -79,6 +80,14 @@ func TestLoadConfig(t *testing.T) {
 					RequiredAcks:    sarama.WaitForAll,
 					Compression:     "none",
 				},
+				SchemaRegistry: SchemaRegistry{
+					ClientConfig: schemaregistry.ClientConfig{
+						Endpoint: "http://localhost:8081",
+						Timeout:  defaultSchemaRegistryTimeout,
+					},
+					Subject:         "logs-value",
+					RefreshInterval: defaultSchemaRegistryRefreshInterval,
+				},
 			},
 		},
 		{
@@ -140,6 +149,14 @@ func TestLoadConfig(t *testing.T) {
 					RequiredAcks:    sarama.WaitForAll,
 					Compression:     "none",
 				},
+				SchemaRegistry: SchemaRegistry{
+					ClientConfig: schemaregistry.ClientConfig{
+						Endpoint: "http://localhost:8081",
+						Timeout:  defaultSchemaRegistryTimeout,
+					},
+					Subject:         "logs-value",
+					RefreshInterval: defaultSchemaRegistryRefreshInterval,
+				},
 			},
 		},
 		{
@@ -189,6 +206,14 @@ func TestLoadConfig(t *testing.T) {
 					RequiredAcks:    sarama.WaitForAll,
 					Compression:     "none",
 				},
+				SchemaRegistry: SchemaRegistry{
+					ClientConfig: schemaregistry.ClientConfig{
+						Endpoint: "http://localhost:8081",
+						Timeout:  defaultSchemaRegistryTimeout,
+					},
+					Subject:         "logs-value",
+					RefreshInterval: defaultSchemaRegistryRefreshInterval,
+				},
 			},
 		},
 	}
@@ -218,6 +243,18 @@ func TestValidate_err_compression(t *testing.T) {
 	assert.EqualError(t, err, "producer.compression should be one of 'none', 'gzip', 'snappy', 'lz4', or 'zstd'. configured value idk")
 }
 
+func TestValidate_schema_registry_endpoint(t *testing.T) {
+	config := &Config{
+		Encoding: "avro",
+		Producer: Producer{
+			Compression: "none",
+		},
+	}
+
+	err := config.Validate()
+	assert.EqualError(t, err, "schema_registry.endpoint is required by the avro encoding")
+}
+
 func TestValidate_sasl_username(t *testing.T) {
 	config := &Config{
 		Producer: Producer{
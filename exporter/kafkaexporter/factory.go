@@ -15,6 +15,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/exporterhelper"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
 )
 
 const (
@@ -40,6 +41,10 @@ const (
 	defaultFluxMaxMessages = 0
 	// partitioning metrics by resource attributes is disabled by default
 	defaultPartitionMetricsByResourceAttributesEnabled = false
+	// default timeout of the requests to the schema registry
+	defaultSchemaRegistryTimeout = 10 * time.Second
+	// default interval the latest version of the schema of the subject is requested again
+	defaultSchemaRegistryRefreshInterval = 5 * time.Minute
 )
 
 // FactoryOption applies changes to kafkaExporterFactory.
@@ -115,6 +120,13 @@ func createDefaultConfig() component.Config {
 			Compression:      defaultCompression,
 			FlushMaxMessages: defaultFluxMaxMessages,
 		},
+		SchemaRegistry: SchemaRegistry{
+			ClientConfig: schemaregistry.ClientConfig{
+				Timeout: defaultSchemaRegistryTimeout,
+			},
+			AutoRegister:    true,
+			RefreshInterval: defaultSchemaRegistryRefreshInterval,
+		},
 	}
 }
 
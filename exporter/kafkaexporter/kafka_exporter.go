@@ -19,6 +19,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
 )
 
 var errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
@@ -124,9 +125,19 @@ type kafkaLogsProducer struct {
 	logger    *zap.Logger
 }
 
-func (e *kafkaLogsProducer) logsDataPusher(_ context.Context, ld plog.Logs) error {
-	messages, err := e.marshaler.Marshal(ld, getTopic(&e.cfg, ld.ResourceLogs()))
+func (e *kafkaLogsProducer) logsDataPusher(ctx context.Context, ld plog.Logs) error {
+	var messages []*sarama.ProducerMessage
+	var err error
+	if m, ok := e.marshaler.(contextLogsMarshaler); ok {
+		messages, err = m.MarshalContext(ctx, ld, getTopic(&e.cfg, ld.ResourceLogs()))
+	} else {
+		messages, err = e.marshaler.Marshal(ld, getTopic(&e.cfg, ld.ResourceLogs()))
+	}
 	if err != nil {
+		if schemaregistry.IsTemporary(err) {
+			// the schema registry may be available when the logs are retried
+			return err
+		}
 		return consumererror.NewPermanent(err)
 	}
 	err = e.producer.SendMessages(messages)
@@ -244,6 +255,12 @@ func newTracesExporter(config Config, set exporter.Settings, marshalers map[stri
 
 func newLogsExporter(config Config, set exporter.Settings, marshalers map[string]LogsMarshaler) (*kafkaLogsProducer, error) {
 	marshaler := marshalers[config.Encoding]
+	if marshaler == nil && isSchemaRegistryEncoding(config.Encoding) {
+		var err error
+		if marshaler, err = newSchemaRegistryMarshaler(config.Encoding, config.SchemaRegistry); err != nil {
+			return nil, err
+		}
+	}
 	if marshaler == nil {
 		return nil, errUnrecognizedEncoding
 	}
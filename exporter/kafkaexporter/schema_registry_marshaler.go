@@ -0,0 +1,107 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkaexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+)
+
+// The encodings serializing the log bodies with a schema of the schema registry.
+const (
+	avroEncoding     = "avro"
+	protobufEncoding = "protobuf"
+)
+
+func isSchemaRegistryEncoding(encoding string) bool {
+	return encoding == avroEncoding || encoding == protobufEncoding
+}
+
+// schemaRegistryMarshaler produces a message per log record, made of its body serialized in the wire format
+// of the schema registry. The bodies are converted to JSON, which must be valid against the JSON mapping of the schema.
+type schemaRegistryMarshaler struct {
+	encoding   string
+	subject    string
+	serializer *schemaregistry.Serializer
+}
+
+var (
+	_ LogsMarshaler        = (*schemaRegistryMarshaler)(nil)
+	_ contextLogsMarshaler = (*schemaRegistryMarshaler)(nil)
+)
+
+// contextLogsMarshaler is implemented by the logs marshalers making requests while marshaling, so that
+// the requests are bound to the context of the export.
+type contextLogsMarshaler interface {
+	MarshalContext(ctx context.Context, logs plog.Logs, topic string) ([]*sarama.ProducerMessage, error)
+}
+
+func newSchemaRegistryMarshaler(encoding string, cfg SchemaRegistry) (*schemaRegistryMarshaler, error) {
+	client, err := schemaregistry.NewClient(cfg.ClientConfig)
+	if err != nil {
+		return nil, err
+	}
+	schemaType := schemaregistry.SchemaTypeAvro
+	if encoding == protobufEncoding {
+		schemaType = schemaregistry.SchemaTypeProtobuf
+	}
+	return &schemaRegistryMarshaler{
+		encoding: encoding,
+		subject:  cfg.Subject,
+		serializer: schemaregistry.NewSerializer(client, schemaregistry.SerializerConfig{
+			SchemaType:      schemaType,
+			Schema:          cfg.Schema,
+			Message:         cfg.Message,
+			AutoRegister:    cfg.AutoRegister,
+			RefreshInterval: cfg.RefreshInterval,
+		}),
+	}, nil
+}
+
+func (m *schemaRegistryMarshaler) Marshal(logs plog.Logs, topic string) ([]*sarama.ProducerMessage, error) {
+	return m.MarshalContext(context.Background(), logs, topic)
+}
+
+// MarshalContext marshals the logs, requesting the schema registry with the context.
+func (m *schemaRegistryMarshaler) MarshalContext(ctx context.Context, logs plog.Logs, topic string) ([]*sarama.ProducerMessage, error) {
+	subject := m.subject
+	if subject == "" {
+		// the default subject name strategy of the Confluent serializers
+		subject = topic + "-value"
+	}
+
+	var messages []*sarama.ProducerMessage
+	for i := 0; i < logs.ResourceLogs().Len(); i++ {
+		rl := logs.ResourceLogs().At(i)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			sl := rl.ScopeLogs().At(j)
+			for k := 0; k < sl.LogRecords().Len(); k++ {
+				body := sl.LogRecords().At(k).Body()
+				if body.Type() == pcommon.ValueTypeEmpty {
+					continue
+				}
+				b, err := m.serializer.Serialize(ctx, subject, body.AsRaw())
+				if err != nil {
+					return nil, err
+				}
+
+				messages = append(messages, &sarama.ProducerMessage{
+					Topic: topic,
+					Value: sarama.ByteEncoder(b),
+				})
+			}
+		}
+	}
+
+	return messages, nil
+}
+
+func (m *schemaRegistryMarshaler) Encoding() string {
+	return m.encoding
+}
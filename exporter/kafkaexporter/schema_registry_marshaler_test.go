@@ -0,0 +1,195 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkaexporter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry/schemaregistrytest"
+)
+
+const userSchema = `{
+  "type": "record",
+  "name": "User",
+  "fields": [
+    {"name": "name", "type": "string"},
+    {"name": "age", "type": "long"}
+  ]
+}`
+
+const userProto = `syntax = "proto3";
+package test;
+
+message User {
+  string name = 1;
+  int64 age = 2;
+}
+`
+
+func newUserLogs(t *testing.T) plog.Logs {
+	logs := plog.NewLogs()
+	records := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	require.NoError(t, records.AppendEmpty().Body().SetEmptyMap().FromRaw(map[string]any{"name": "alice", "age": 42}))
+	// the records without body aren't produced
+	records.AppendEmpty()
+	return logs
+}
+
+func TestSchemaRegistryMarshaler(t *testing.T) {
+	tests := []struct {
+		name       string
+		encoding   string
+		schemaType string
+		schema     string
+		expected   any
+	}{
+		{
+			name:       "avro",
+			encoding:   avroEncoding,
+			schemaType: schemaregistry.SchemaTypeAvro,
+			schema:     userSchema,
+			expected:   map[string]any{"name": "alice", "age": int64(42)},
+		},
+		{
+			name:       "protobuf",
+			encoding:   protobufEncoding,
+			schemaType: schemaregistry.SchemaTypeProtobuf,
+			schema:     userProto,
+			// the JSON mapping of Protobuf represents the 64 bits integers as strings
+			expected: map[string]any{"name": "alice", "age": "42"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := schemaregistrytest.NewRegistry()
+			defer registry.Close()
+
+			cfg := SchemaRegistry{
+				ClientConfig: schemaregistry.ClientConfig{Endpoint: registry.URL},
+				Schema:       tt.schema,
+				AutoRegister: true,
+			}
+			marshaler, err := newSchemaRegistryMarshaler(tt.encoding, cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.encoding, marshaler.Encoding())
+
+			messages, err := marshaler.Marshal(newUserLogs(t), "users")
+			require.NoError(t, err)
+			require.Len(t, messages, 1)
+			assert.Equal(t, "users", messages[0].Topic)
+
+			client, err := schemaregistry.NewClient(schemaregistry.ClientConfig{Endpoint: registry.URL})
+			require.NoError(t, err)
+			// the schema is registered under the default subject of the topic
+			schema, err := client.LatestSchema(context.Background(), "users-value")
+			require.NoError(t, err)
+			assert.Equal(t, tt.schemaType, schema.Type())
+
+			value, err := schemaregistry.NewDeserializer(client, tt.schemaType).Deserialize(context.Background(), messages[0].Value.(sarama.ByteEncoder))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func TestSchemaRegistryMarshaler_latest_schema(t *testing.T) {
+	registry := schemaregistrytest.NewRegistry()
+	defer registry.Close()
+	id := registry.Register("users", schemaregistry.Schema{Schema: userSchema})
+
+	cfg := SchemaRegistry{
+		ClientConfig: schemaregistry.ClientConfig{Endpoint: registry.URL},
+		Subject:      "users",
+	}
+	marshaler, err := newSchemaRegistryMarshaler(avroEncoding, cfg)
+	require.NoError(t, err)
+
+	messages, err := marshaler.Marshal(newUserLogs(t), "logs")
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	buf := messages[0].Value.(sarama.ByteEncoder)
+	assert.Equal(t, []byte{0, 0, 0, 0, byte(id)}, []byte(buf[:5]))
+}
+
+func TestSchemaRegistryMarshaler_invalid_body(t *testing.T) {
+	registry := schemaregistrytest.NewRegistry()
+	defer registry.Close()
+
+	cfg := SchemaRegistry{
+		ClientConfig: schemaregistry.ClientConfig{Endpoint: registry.URL},
+		Schema:       userSchema,
+		AutoRegister: true,
+	}
+	marshaler, err := newSchemaRegistryMarshaler(avroEncoding, cfg)
+	require.NoError(t, err)
+
+	logs := plog.NewLogs()
+	logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("alice")
+	_, err = marshaler.Marshal(logs, "users")
+	assert.Error(t, err)
+}
+
+func TestLogsDataPusher_schema_registry_errors(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	newProducer := func() kafkaLogsProducer {
+		marshaler, err := newSchemaRegistryMarshaler(avroEncoding, SchemaRegistry{
+			ClientConfig: schemaregistry.ClientConfig{Endpoint: server.URL},
+			Subject:      "users",
+		})
+		require.NoError(t, err)
+		return kafkaLogsProducer{marshaler: marshaler, logger: zap.NewNop()}
+	}
+
+	// the failures of the schema registry which may not happen again are retried
+	p := newProducer()
+	err := p.logsDataPusher(context.Background(), newUserLogs(t))
+	require.Error(t, err)
+	assert.False(t, consumererror.IsPermanent(err))
+
+	// the logs which can't be serialized are dropped
+	status = http.StatusNotFound
+	p = newProducer()
+	err = p.logsDataPusher(context.Background(), newUserLogs(t))
+	require.Error(t, err)
+	assert.True(t, consumererror.IsPermanent(err))
+
+	// the schema registry is requested with the context of the export
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p = newProducer()
+	err = p.logsDataPusher(ctx, newUserLogs(t))
+	require.ErrorIs(t, err, context.Canceled)
+	assert.False(t, consumererror.IsPermanent(err))
+}
+
+func TestNewLogsExporter_schema_registry(t *testing.T) {
+	c := Config{
+		Encoding:       avroEncoding,
+		SchemaRegistry: SchemaRegistry{ClientConfig: schemaregistry.ClientConfig{Endpoint: "http://localhost:8081"}},
+	}
+	lexp, err := newLogsExporter(c, exportertest.NewNopSettings(), logsMarshalers())
+	require.NoError(t, err)
+	assert.Equal(t, avroEncoding, lexp.marshaler.Encoding())
+
+	c.SchemaRegistry.Endpoint = ""
+	_, err = newLogsExporter(c, exportertest.NewNopSettings(), logsMarshalers())
+	assert.Error(t, err)
+}
@@ -0,0 +1,78 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"errors"
+	"math"
+)
+
+const defaultLoadFactor = 1.25
+
+var errInvalidLoadFactor = errors.New("the bounded load factor must be at least 1")
+
+// newBoundedHashRing builds a consistent hash ring with bounded loads, following Mirrokni et al. over the
+// positions of the ring rather than over the keys: each ring item covers the positions since the previous
+// item, and goes to the first endpoint from its position whose share of the positions is below the capacity,
+// which is the average share of an endpoint times the load factor. The items are assigned in the order of
+// the ring, so the assignment only depends on the endpoints: every instance of the load balancer routes a
+// key to the same endpoint, without remembering the keys it saw.
+//
+// The load of an endpoint is the share of the keys it gets when the keys are spread evenly over the ring,
+// not the number of keys it actually got.
+func newBoundedHashRing(endpoints []string, loadFactor float64) *hashRing {
+	ring := newHashRing(endpoints)
+	if len(ring.items) == 0 {
+		return ring
+	}
+
+	capacity := int(math.Ceil(loadFactor * float64(maxPositions) / float64(countEndpoints(ring))))
+	loads := map[string]int{}
+	items := make([]ringItem, len(ring.items))
+	for i, item := range ring.items {
+		arc := int(item.pos)
+		if i > 0 {
+			arc -= int(ring.items[i-1].pos)
+		} else {
+			// the first item also covers the positions past the last item
+			arc += int(maxPositions - uint32(ring.items[len(ring.items)-1].pos))
+		}
+
+		endpoint := item.endpoint
+		for j := 0; j < len(ring.items); j++ {
+			if candidate := ring.items[(i+j)%len(ring.items)].endpoint; loads[candidate] < capacity {
+				endpoint = candidate
+				break
+			}
+		}
+		loads[endpoint] += arc
+		items[i] = ringItem{pos: item.pos, endpoint: endpoint}
+	}
+	return &hashRing{items: items}
+}
+
+// movedShare returns the share of the positions of the ring which go to another endpoint in the candidate ring.
+func (h *hashRing) movedShare(candidate *hashRing) float64 {
+	if h == nil || len(h.items) == 0 {
+		return 0
+	}
+	moved := 0
+	for pos := position(0); uint32(pos) < maxPositions; pos++ {
+		if h.findEndpoint(pos) != candidate.findEndpoint(pos) {
+			moved++
+		}
+	}
+	return float64(moved) / float64(maxPositions)
+}
+
+func countEndpoints(ring *hashRing) int {
+	if ring == nil {
+		return 0
+	}
+	endpoints := map[string]bool{}
+	for _, item := range ring.items {
+		endpoints[item.endpoint] = true
+	}
+	return len(endpoints)
+}
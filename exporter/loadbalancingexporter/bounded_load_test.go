@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// shares returns the share of the positions of the ring going to each endpoint.
+func shares(ring *hashRing) map[string]float64 {
+	counts := map[string]float64{}
+	for pos := position(0); uint32(pos) < maxPositions; pos++ {
+		counts[ring.findEndpoint(pos)]++
+	}
+	for endpoint := range counts {
+		counts[endpoint] /= float64(maxPositions)
+	}
+	return counts
+}
+
+func TestBoundedHashRingCapacity(t *testing.T) {
+	endpoints := make([]string, 10)
+	for i := range endpoints {
+		endpoints[i] = fmt.Sprintf("endpoint-%d", i)
+	}
+
+	// test
+	ring := newBoundedHashRing(endpoints, 1)
+
+	// verify
+	// an endpoint can go over the capacity by the last position range it got
+	var maxArc uint32
+	for i := 1; i < len(ring.items); i++ {
+		maxArc = max(maxArc, uint32(ring.items[i].pos-ring.items[i-1].pos))
+	}
+	limit := 1/float64(len(endpoints)) + float64(maxArc)/float64(maxPositions)
+	loads := shares(ring)
+	assert.Len(t, loads, len(endpoints))
+	for endpoint, share := range loads {
+		assert.LessOrEqual(t, share, limit, "endpoint %s", endpoint)
+	}
+
+	// without the bound, some endpoints get more keys
+	var highest float64
+	for _, share := range shares(newHashRing(endpoints)) {
+		highest = max(highest, share)
+	}
+	assert.Greater(t, highest, limit)
+}
+
+func TestBoundedHashRingUnbounded(t *testing.T) {
+	endpoints := []string{"endpoint-1", "endpoint-2", "endpoint-3"}
+
+	// test
+	ring := newBoundedHashRing(endpoints, float64(len(endpoints)))
+
+	// verify
+	// when an endpoint can take all the keys, the positions stay with their endpoint
+	assert.True(t, newHashRing(endpoints).equal(ring))
+}
+
+func TestBoundedHashRingInstancesAgree(t *testing.T) {
+	endpoints := []string{"endpoint-1", "endpoint-2", "endpoint-3"}
+
+	// test
+	first := newBoundedHashRing(endpoints, 1.1)
+	second := newBoundedHashRing(endpoints, 1.1)
+
+	// verify
+	// the instances of the load balancer route the keys the same way, whichever keys they saw before
+	assert.True(t, first.equal(second))
+	for i := 0; i < 1000; i++ {
+		key := []byte(fmt.Sprintf("key-%d", i))
+		assert.Equal(t, first.endpointFor(key), second.endpointFor(key))
+	}
+}
+
+func TestBoundedHashRingScaling(t *testing.T) {
+	// prepare
+	ring := newBoundedHashRing([]string{"endpoint-1", "endpoint-2"}, 1.25)
+
+	// test
+	scaledUp := newBoundedHashRing([]string{"endpoint-1", "endpoint-2", "endpoint-3"}, 1.25)
+	scaledDown := newBoundedHashRing([]string{"endpoint-1", "endpoint-3"}, 1.25)
+
+	// verify
+	// the new endpoint takes about its share of the keys, rather than reshuffling all the keys
+	moved := ring.movedShare(scaledUp)
+	assert.Greater(t, moved, 0.2)
+	assert.Less(t, moved, 0.5)
+	assert.InDelta(t, 1.0/3, shares(scaledUp)["endpoint-3"], 0.1)
+
+	moved = scaledUp.movedShare(scaledDown)
+	assert.Greater(t, moved, 0.2)
+	assert.Less(t, moved, 0.5)
+	assert.Zero(t, shares(scaledDown)["endpoint-2"])
+}
+
+func TestBoundedHashRingNoEndpoints(t *testing.T) {
+	// test
+	ring := newBoundedHashRing(nil, 1.25)
+
+	// verify
+	assert.Empty(t, ring.items)
+	assert.Equal(t, "", ring.endpointFor([]byte("key-1")))
+	assert.Zero(t, ring.movedShare(newBoundedHashRing([]string{"endpoint-1"}, 1.25)))
+}
@@ -17,6 +17,8 @@ const (
 	svcRouting
 	metricNameRouting
 	resourceRouting
+	attributesRouting
+	streamIDRouting
 )
 
 // Config defines configuration for the exporter.
@@ -24,6 +26,21 @@ type Config struct {
 	Protocol   Protocol         `mapstructure:"protocol"`
 	Resolver   ResolverSettings `mapstructure:"resolver"`
 	RoutingKey string           `mapstructure:"routing_key"`
+
+	// RoutingAttributes is the list of attributes whose values are used to route the data
+	// when the routing key is "attributes".
+	RoutingAttributes []string `mapstructure:"routing_attributes"`
+
+	// BoundedLoad enables the consistent hashing with bounded loads, which caps the share of the
+	// routing keys going to each backend.
+	BoundedLoad *BoundedLoadSettings `mapstructure:"bounded_load"`
+}
+
+// BoundedLoadSettings defines the configuration for the consistent hashing with bounded loads
+type BoundedLoadSettings struct {
+	// LoadFactor is the maximum share of the keys of a backend relative to the average, e.g. 1.25
+	// allows backends to get 25% more keys than the average.
+	LoadFactor float64 `mapstructure:"load_factor"`
 }
 
 // Protocol holds the individual protocol-specific settings. Only OTLP is supported at the moment.
@@ -57,6 +74,23 @@ type K8sSvcResolver struct {
 	Service string        `mapstructure:"service"`
 	Ports   []int32       `mapstructure:"ports"`
 	Timeout time.Duration `mapstructure:"timeout"`
+
+	// UseEndpointSlices watches the EndpointSlices of the service instead of its Endpoints, so that the
+	// readiness and termination of the backends are taken into account.
+	UseEndpointSlices bool `mapstructure:"use_endpoint_slices"`
+	// Topology restricts the backends to the zone of this collector. It requires the EndpointSlices.
+	Topology *K8sTopology `mapstructure:"topology"`
+}
+
+// K8sTopology defines the configuration for the topology aware resolution of the Kubernetes backends
+type K8sTopology struct {
+	// Zone is the zone of this collector, usually the topology.kubernetes.io/zone label of its node.
+	Zone string `mapstructure:"zone"`
+	// MinZoneBackends is the minimum number of backends in the zone below which the backends of all the zones are used.
+	MinZoneBackends int `mapstructure:"min_zone_backends"`
+	// CrossZoneFallback controls whether the backends of all the zones are used when the zone doesn't have enough
+	// backends. Defaults to true.
+	CrossZoneFallback *bool `mapstructure:"cross_zone_fallback"`
 }
 
 type AWSCloudMapResolver struct {
@@ -7,6 +7,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
@@ -25,3 +26,38 @@ func TestLoadConfig(t *testing.T) {
 	require.NoError(t, sub.Unmarshal(cfg))
 	require.NotNil(t, cfg)
 }
+
+func TestLoadRoutingConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "5").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+
+	assert.Equal(t, "attributes", cfg.(*Config).RoutingKey)
+	assert.Equal(t, []string{"tenant.id"}, cfg.(*Config).RoutingAttributes)
+	assert.Equal(t, &BoundedLoadSettings{LoadFactor: 1.25}, cfg.(*Config).BoundedLoad)
+}
+
+func TestLoadK8sTopologyConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "6").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+
+	k8sCfg := cfg.(*Config).Resolver.K8sSvc
+	require.NotNil(t, k8sCfg)
+	assert.True(t, k8sCfg.UseEndpointSlices)
+	require.NotNil(t, k8sCfg.Topology)
+	assert.Equal(t, "us-east-1a", k8sCfg.Topology.Zone)
+	assert.Equal(t, 2, k8sCfg.Topology.MinZoneBackends)
+	require.NotNil(t, k8sCfg.Topology.CrossZoneFallback)
+	assert.True(t, *k8sCfg.Topology.CrossZoneFallback)
+}
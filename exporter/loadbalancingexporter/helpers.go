@@ -4,10 +4,17 @@
 package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
 
 import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 )
 
+var errNoRoutingAttributes = errors.New("the routing_attributes must be set when routing by attributes")
+
 // mergeTraces concatenates two ptrace.Traces into a single ptrace.Traces.
 func mergeTraces(t1 ptrace.Traces, t2 ptrace.Traces) ptrace.Traces {
 	t2.ResourceSpans().MoveAndAppendTo(t1.ResourceSpans())
@@ -19,3 +26,23 @@ func mergeMetrics(m1 pmetric.Metrics, m2 pmetric.Metrics) pmetric.Metrics {
 	m2.ResourceMetrics().MoveAndAppendTo(m1.ResourceMetrics())
 	return m1
 }
+
+// attributesRoutingKey builds a routing key from the values of the given attributes, which are looked up
+// with the lookup function. Each value is prefixed with its length so that different values never build the
+// same key, and the missing attributes are marked as such, so that they differ from the empty values.
+func attributesRoutingKey(names []string, lookup func(name string) (pcommon.Value, bool)) string {
+	var key strings.Builder
+	for _, name := range names {
+		v, ok := lookup(name)
+		if !ok {
+			key.WriteString("-;")
+			continue
+		}
+		value := v.AsString()
+		key.WriteString(strconv.Itoa(len(value)))
+		key.WriteByte(':')
+		key.WriteString(value)
+		key.WriteByte(';')
+	}
+	return key.String()
+}
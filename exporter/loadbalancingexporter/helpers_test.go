@@ -6,7 +6,9 @@ package loadbalancingexporter
 import (
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
@@ -173,3 +175,17 @@ func BenchmarkMergeMetrics_X500(b *testing.B) {
 func BenchmarkMergeMetrics_X1000(b *testing.B) {
 	benchMergeMetrics(b, 1000)
 }
+
+func TestAttributesRoutingKey(t *testing.T) {
+	key := func(attrs map[string]any) string {
+		m := pcommon.NewMap()
+		require.NoError(t, m.FromRaw(attrs))
+		return attributesRoutingKey([]string{"a", "b"}, m.Get)
+	}
+
+	assert.Equal(t, key(map[string]any{"a": "x", "b": "y"}), key(map[string]any{"b": "y", "a": "x"}))
+	assert.NotEqual(t, key(map[string]any{"a": "ab", "b": "c"}), key(map[string]any{"a": "a", "b": "bc"}))
+	assert.NotEqual(t, key(map[string]any{"a": "1:x;", "b": "y"}), key(map[string]any{"a": "1:x", "b": ";y"}))
+	assert.NotEqual(t, key(map[string]any{"a": "", "b": "y"}), key(map[string]any{"b": "y"}))
+	assert.NotEqual(t, key(map[string]any{"a": "-;"}), key(map[string]any{"b": "-;"}))
+}
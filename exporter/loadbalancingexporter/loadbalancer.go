@@ -10,6 +10,7 @@ import (
 	"strings"
 	"sync"
 
+	"go.opencensus.io/stats"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/exporter"
 	"go.uber.org/zap"
@@ -32,6 +33,8 @@ type loadBalancer struct {
 
 	res  resolver
 	ring *hashRing
+	// loadFactor is the load factor of the consistent hashing with bounded loads, which is disabled when zero.
+	loadFactor float64
 
 	componentFactory componentFactory
 	exporters        map[string]*wrappedExporter
@@ -81,11 +84,20 @@ func newLoadBalancer(params exporter.Settings, cfg component.Config, factory com
 	if oCfg.Resolver.K8sSvc != nil {
 		k8sLogger := params.Logger.With(zap.String("resolver", "k8s service"))
 
+		k8sCfg := oCfg.Resolver.K8sSvc
+		if k8sCfg.Topology != nil && !k8sCfg.UseEndpointSlices {
+			return nil, errTopologyWithoutEndpointSlices
+		}
+
 		clt, err := newInClusterClient()
 		if err != nil {
 			return nil, err
 		}
-		res, err = newK8sResolver(clt, k8sLogger, oCfg.Resolver.K8sSvc.Service, oCfg.Resolver.K8sSvc.Ports, oCfg.Resolver.K8sSvc.Timeout)
+		if k8sCfg.UseEndpointSlices {
+			res, err = newK8sEndpointSliceResolver(clt, k8sLogger, k8sCfg.Service, k8sCfg.Ports, k8sCfg.Timeout, k8sCfg.Topology)
+		} else {
+			res, err = newK8sResolver(clt, k8sLogger, k8sCfg.Service, k8sCfg.Ports, k8sCfg.Timeout)
+		}
 		if err != nil {
 			return nil, err
 		}
@@ -104,9 +116,21 @@ func newLoadBalancer(params exporter.Settings, cfg component.Config, factory com
 		return nil, errNoResolver
 	}
 
+	var loadFactor float64
+	if oCfg.BoundedLoad != nil {
+		loadFactor = oCfg.BoundedLoad.LoadFactor
+		if loadFactor == 0 {
+			loadFactor = defaultLoadFactor
+		}
+		if loadFactor < 1 {
+			return nil, errInvalidLoadFactor
+		}
+	}
+
 	return &loadBalancer{
 		logger:           params.Logger,
 		res:              res,
+		loadFactor:       loadFactor,
 		componentFactory: factory,
 		exporters:        map[string]*wrappedExporter{},
 	}, nil
@@ -120,11 +144,17 @@ func (lb *loadBalancer) Start(ctx context.Context, host component.Host) error {
 
 func (lb *loadBalancer) onBackendChanges(resolved []string) {
 	newRing := newHashRing(resolved)
+	if lb.loadFactor > 0 {
+		newRing = newBoundedHashRing(resolved, lb.loadFactor)
+	}
 
 	if !newRing.equal(lb.ring) {
 		lb.updateLock.Lock()
 		defer lb.updateLock.Unlock()
 
+		if lb.loadFactor > 0 {
+			stats.Record(context.Background(), mRebalancedRatio.M(lb.ring.movedShare(newRing)))
+		}
 		lb.ring = newRing
 
 		// TODO: set a timeout?
@@ -82,6 +82,25 @@ func TestNewLoadBalancerInvalidK8sResolver(t *testing.T) {
 	assert.True(t, clientcmd.IsConfigurationInvalid(err) || errors.Is(err, errNoSvc))
 }
 
+func TestNewLoadBalancerK8sTopologyWithoutEndpointSlices(t *testing.T) {
+	// prepare
+	cfg := &Config{
+		Resolver: ResolverSettings{
+			K8sSvc: &K8sSvcResolver{
+				Service:  "lb",
+				Topology: &K8sTopology{Zone: "zone-a"},
+			},
+		},
+	}
+
+	// test
+	p, err := newLoadBalancer(exportertest.NewNopSettings(), cfg, nil)
+
+	// verify
+	assert.Nil(t, p)
+	assert.Equal(t, errTopologyWithoutEndpointSlices, err)
+}
+
 func TestLoadBalancerStart(t *testing.T) {
 	// prepare
 	cfg := simpleConfig()
@@ -99,6 +118,38 @@ func TestLoadBalancerStart(t *testing.T) {
 	assert.Nil(t, res)
 }
 
+func TestLoadBalancerBoundedLoad(t *testing.T) {
+	// prepare
+	cfg := simpleConfig()
+	cfg.BoundedLoad = &BoundedLoadSettings{LoadFactor: 1.1}
+	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
+		return newNopMockExporter(), nil
+	}
+	p, err := newLoadBalancer(exportertest.NewNopSettings(), cfg, componentFactory)
+	require.NotNil(t, p)
+	require.NoError(t, err)
+	assert.Equal(t, 1.1, p.loadFactor)
+
+	// test
+	p.onBackendChanges([]string{"endpoint-1", "endpoint-2", "endpoint-3"})
+	exp, endpoint, err := p.exporterAndEndpoint([]byte("key-1"))
+
+	// verify
+	require.NoError(t, err)
+	assert.NotNil(t, exp)
+	assert.Equal(t, newBoundedHashRing([]string{"endpoint-1", "endpoint-2", "endpoint-3"}, 1.1).endpointFor([]byte("key-1")), endpoint)
+}
+
+func TestLoadBalancerInvalidBoundedLoad(t *testing.T) {
+	cfg := simpleConfig()
+	cfg.BoundedLoad = &BoundedLoadSettings{LoadFactor: 0.9}
+
+	p, err := newLoadBalancer(exportertest.NewNopSettings(), cfg, nil)
+
+	assert.Nil(t, p)
+	assert.Equal(t, errInvalidLoadFactor, err)
+}
+
 func TestWithDNSResolver(t *testing.T) {
 	cfg := &Config{
 		Resolver: ResolverSettings{
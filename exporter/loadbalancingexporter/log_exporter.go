@@ -25,7 +25,9 @@ import (
 var _ exporter.Logs = (*logExporterImp)(nil)
 
 type logExporterImp struct {
-	loadBalancer *loadBalancer
+	loadBalancer      *loadBalancer
+	routingKey        routingKey
+	routingAttributes []string
 
 	started    bool
 	shutdownWg sync.WaitGroup
@@ -43,9 +45,16 @@ func newLogsExporter(params exporter.Settings, cfg component.Config) (*logExport
 		return nil, err
 	}
 
-	return &logExporterImp{
-		loadBalancer: lb,
-	}, nil
+	logExporter := logExporterImp{loadBalancer: lb, routingKey: traceIDRouting}
+
+	if cfg.(*Config).RoutingKey == "attributes" {
+		if len(cfg.(*Config).RoutingAttributes) == 0 {
+			return nil, errNoRoutingAttributes
+		}
+		logExporter.routingKey = attributesRouting
+		logExporter.routingAttributes = cfg.(*Config).RoutingAttributes
+	}
+	return &logExporter, nil
 }
 
 func (e *logExporterImp) Capabilities() consumer.Capabilities {
@@ -68,6 +77,10 @@ func (e *logExporterImp) Shutdown(ctx context.Context) error {
 }
 
 func (e *logExporterImp) ConsumeLogs(ctx context.Context, ld plog.Logs) error {
+	if e.routingKey == attributesRouting {
+		return e.consumeLogsByAttributes(ctx, ld)
+	}
+
 	var errs error
 	batches := batchpersignal.SplitLogs(ld)
 	for _, batch := range batches {
@@ -113,6 +126,87 @@ func (e *logExporterImp) consumeLog(ctx context.Context, ld plog.Logs) error {
 	return err
 }
 
+// consumeLogsByAttributes routes each log record on its own, based on the routing attributes of its
+// resource or of the record itself.
+func (e *logExporterImp) consumeLogsByAttributes(ctx context.Context, ld plog.Logs) error {
+	exporterSegregatedLogs := make(map[*wrappedExporter]plog.Logs)
+	endpoints := make(map[*wrappedExporter]string)
+	// the index of the latest resource added to the logs of each exporter
+	lastResources := make(map[*wrappedExporter]int)
+
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		sls := rl.ScopeLogs()
+		for j := 0; j < sls.Len(); j++ {
+			sl := sls.At(j)
+			// the scope of each exporter which the records of this scope were added to
+			scopes := make(map[*wrappedExporter]plog.ScopeLogs)
+			logs := sl.LogRecords()
+			for k := 0; k < logs.Len(); k++ {
+				record := logs.At(k)
+				rid := attributesRoutingKey(e.routingAttributes, func(name string) (pcommon.Value, bool) {
+					if v, ok := rl.Resource().Attributes().Get(name); ok {
+						return v, true
+					}
+					return record.Attributes().Get(name)
+				})
+				le, endpoint, err := e.loadBalancer.exporterAndEndpoint([]byte(rid))
+				if err != nil {
+					return err
+				}
+
+				scope, ok := scopes[le]
+				if !ok {
+					dest, found := exporterSegregatedLogs[le]
+					if !found {
+						le.consumeWG.Add(1)
+						dest = plog.NewLogs()
+						exporterSegregatedLogs[le] = dest
+						endpoints[le] = endpoint
+					}
+					// the resource might have been added already for a previous scope of this resource
+					drl := dest.ResourceLogs()
+					if idx, seen := lastResources[le]; !seen || idx != i {
+						newRL := drl.AppendEmpty()
+						rl.Resource().CopyTo(newRL.Resource())
+						newRL.SetSchemaUrl(rl.SchemaUrl())
+						lastResources[le] = i
+					}
+					scope = drl.At(drl.Len() - 1).ScopeLogs().AppendEmpty()
+					sl.Scope().CopyTo(scope.Scope())
+					scope.SetSchemaUrl(sl.SchemaUrl())
+					scopes[le] = scope
+				}
+				record.CopyTo(scope.LogRecords().AppendEmpty())
+			}
+		}
+	}
+
+	var errs error
+	for le, logs := range exporterSegregatedLogs {
+		start := time.Now()
+		err := le.ConsumeLogs(ctx, logs)
+		le.consumeWG.Done()
+		errs = multierr.Append(errs, err)
+		duration := time.Since(start)
+
+		if err == nil {
+			_ = stats.RecordWithTags(
+				ctx,
+				[]tag.Mutator{tag.Upsert(endpointTagKey, endpoints[le]), successTrueMutator},
+				mBackendLatency.M(duration.Milliseconds()))
+		} else {
+			_ = stats.RecordWithTags(
+				ctx,
+				[]tag.Mutator{tag.Upsert(endpointTagKey, endpoints[le]), successFalseMutator},
+				mBackendLatency.M(duration.Milliseconds()))
+		}
+	}
+
+	return errs
+}
+
 func traceIDFromLogs(ld plog.Logs) pcommon.TraceID {
 	rl := ld.ResourceLogs()
 	if rl.Len() == 0 {
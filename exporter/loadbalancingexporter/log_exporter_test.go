@@ -42,6 +42,19 @@ func TestNewLogsExporter(t *testing.T) {
 			&Config{},
 			errNoResolver,
 		},
+		{
+			"attributes",
+			attributesBasedRoutingConfig(),
+			nil,
+		},
+		{
+			"attributes without routing attributes",
+			&Config{
+				Resolver:   ResolverSettings{Static: &StaticResolver{Hostnames: []string{"endpoint-1"}}},
+				RoutingKey: attributesRouteKey,
+			},
+			errNoRoutingAttributes,
+		},
 	} {
 		t.Run(tt.desc, func(t *testing.T) {
 			// test
@@ -148,6 +161,93 @@ func TestConsumeLogs(t *testing.T) {
 	assert.Nil(t, res)
 }
 
+func TestConsumeLogsAttributesBased(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string][]plog.Logs{}
+	componentFactory := func(_ context.Context, endpoint string) (component.Component, error) {
+		return newMockLogsExporter(func(_ context.Context, ld plog.Logs) error {
+			mu.Lock()
+			defer mu.Unlock()
+			received[endpoint] = append(received[endpoint], ld)
+			return nil
+		}), nil
+	}
+	lb, err := newLoadBalancer(exportertest.NewNopSettings(), attributesBasedRoutingConfig(), componentFactory)
+	require.NotNil(t, lb)
+	require.NoError(t, err)
+
+	p, err := newLogsExporter(exportertest.NewNopSettings(), attributesBasedRoutingConfig())
+	require.NotNil(t, p)
+	require.NoError(t, err)
+	assert.Equal(t, p.routingKey, attributesRouting)
+
+	lb.addMissingExporters(context.Background(), []string{"endpoint-1", "endpoint-2"})
+	lb.res = &mockResolver{
+		triggerCallbacks: true,
+		onResolve: func(_ context.Context) ([]string, error) {
+			return []string{"endpoint-1", "endpoint-2"}, nil
+		},
+	}
+	p.loadBalancer = lb
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	// the tenant of the records of the first resource comes from the resource, the one of the second from the records
+	ld := plog.NewLogs()
+	rl := ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("tenant", "tenant-0")
+	records := rl.ScopeLogs().AppendEmpty().LogRecords()
+	for i := 0; i < 3; i++ {
+		records.AppendEmpty().Attributes().PutStr("tenant", fmt.Sprintf("tenant-%d", i+1))
+	}
+	rl = ld.ResourceLogs().AppendEmpty()
+	rl.Resource().Attributes().PutStr("service.name", "service-1")
+	sl := rl.ScopeLogs().AppendEmpty()
+	sl.Scope().SetName("scope-1")
+	for i := 0; i < 20; i++ {
+		sl.LogRecords().AppendEmpty().Attributes().PutStr("tenant", fmt.Sprintf("tenant-%d", i))
+	}
+
+	// test
+	require.NoError(t, p.ConsumeLogs(context.Background(), ld))
+
+	// verify
+	assert.Len(t, received, 2)
+	tenants := map[string]string{}
+	total := 0
+	for endpoint, batches := range received {
+		require.Len(t, batches, 1)
+		rls := batches[0].ResourceLogs()
+		for i := 0; i < rls.Len(); i++ {
+			resourceTenant, fromResource := rls.At(i).Resource().Attributes().Get("tenant")
+			if !fromResource {
+				svc, _ := rls.At(i).Resource().Attributes().Get("service.name")
+				assert.Equal(t, "service-1", svc.Str())
+				require.Equal(t, 1, rls.At(i).ScopeLogs().Len())
+				assert.Equal(t, "scope-1", rls.At(i).ScopeLogs().At(0).Scope().Name())
+			}
+			records := rls.At(i).ScopeLogs().At(0).LogRecords()
+			for j := 0; j < records.Len(); j++ {
+				tenant := resourceTenant
+				if !fromResource {
+					tenant, _ = records.At(j).Attributes().Get("tenant")
+				}
+				if previous, found := tenants[tenant.Str()]; found {
+					assert.Equal(t, previous, endpoint, "tenant %s", tenant.Str())
+				}
+				tenants[tenant.Str()] = endpoint
+				total++
+			}
+		}
+	}
+	assert.Equal(t, 23, total)
+	assert.Len(t, tenants, 20)
+}
+
 func TestConsumeLogsUnexpectedExporterType(t *testing.T) {
 	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
 		return newNopMockExporter(), nil
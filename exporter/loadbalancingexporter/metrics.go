@@ -10,9 +10,10 @@ import (
 )
 
 var (
-	mNumResolutions = stats.Int64("loadbalancer_num_resolutions", "Number of times the resolver triggered a new resolutions", stats.UnitDimensionless)
-	mNumBackends    = stats.Int64("loadbalancer_num_backends", "Current number of backends in use", stats.UnitDimensionless)
-	mBackendLatency = stats.Int64("loadbalancer_backend_latency", "Response latency in ms for the backends", stats.UnitMilliseconds)
+	mNumResolutions  = stats.Int64("loadbalancer_num_resolutions", "Number of times the resolver triggered a new resolutions", stats.UnitDimensionless)
+	mNumBackends     = stats.Int64("loadbalancer_num_backends", "Current number of backends in use", stats.UnitDimensionless)
+	mBackendLatency  = stats.Int64("loadbalancer_backend_latency", "Response latency in ms for the backends", stats.UnitMilliseconds)
+	mRebalancedRatio = stats.Float64("loadbalancer_rebalanced_ratio", "Share of the routing keys moved to another backend by the last change of the backends, with the bounded load balancing", stats.UnitDimensionless)
 
 	endpointTagKey      = tag.MustNewKey("endpoint")
 	successTrueMutator  = tag.Upsert(tag.MustNewKey("success"), "true")
@@ -69,5 +70,11 @@ func metricViews() []*view.View {
 			},
 			Aggregation: view.Count(),
 		},
+		{
+			Name:        mRebalancedRatio.Name(),
+			Measure:     mRebalancedRatio,
+			Description: mRebalancedRatio.Description(),
+			Aggregation: view.LastValue(),
+		},
 	}
 }
@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -31,8 +32,9 @@ var _ exporter.Metrics = (*metricExporterImp)(nil)
 type exporterMetrics map[*wrappedExporter]pmetric.Metrics
 
 type metricExporterImp struct {
-	loadBalancer *loadBalancer
-	routingKey   routingKey
+	loadBalancer      *loadBalancer
+	routingKey        routingKey
+	routingAttributes []string
 
 	stopped    bool
 	shutdownWg sync.WaitGroup
@@ -59,6 +61,14 @@ func newMetricsExporter(params exporter.Settings, cfg component.Config) (*metric
 		metricExporter.routingKey = resourceRouting
 	case "metric":
 		metricExporter.routingKey = metricNameRouting
+	case "attributes":
+		if len(cfg.(*Config).RoutingAttributes) == 0 {
+			return nil, errNoRoutingAttributes
+		}
+		metricExporter.routingKey = attributesRouting
+		metricExporter.routingAttributes = cfg.(*Config).RoutingAttributes
+	case "streamID":
+		metricExporter.routingKey = streamIDRouting
 	default:
 		return nil, fmt.Errorf("unsupported routing_key: %q", cfg.(*Config).RoutingKey)
 	}
@@ -82,6 +92,48 @@ func (e *metricExporterImp) Shutdown(ctx context.Context) error {
 }
 
 func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metrics) error {
+	var (
+		exporterSegregatedMetrics exporterMetrics
+		endpoints                 map[*wrappedExporter]string
+		err                       error
+	)
+	switch e.routingKey {
+	case attributesRouting, streamIDRouting:
+		exporterSegregatedMetrics, endpoints, err = e.splitByDataPoint(md)
+	default:
+		exporterSegregatedMetrics, endpoints, err = e.splitByBatch(md)
+	}
+	if err != nil {
+		return err
+	}
+
+	var errs error
+
+	for exp, metrics := range exporterSegregatedMetrics {
+		start := time.Now()
+		err := exp.ConsumeMetrics(ctx, metrics)
+		exp.consumeWG.Done()
+		duration := time.Since(start)
+		errs = multierr.Append(errs, err)
+
+		if err == nil {
+			_ = stats.RecordWithTags(
+				ctx,
+				[]tag.Mutator{tag.Upsert(endpointTagKey, endpoints[exp]), successTrueMutator},
+				mBackendLatency.M(duration.Milliseconds()))
+		} else {
+			_ = stats.RecordWithTags(
+				ctx,
+				[]tag.Mutator{tag.Upsert(endpointTagKey, endpoints[exp]), successFalseMutator},
+				mBackendLatency.M(duration.Milliseconds()))
+		}
+	}
+
+	return errs
+}
+
+// splitByBatch routes the metrics as a whole, based on their resource or name.
+func (e *metricExporterImp) splitByBatch(md pmetric.Metrics) (exporterMetrics, map[*wrappedExporter]string, error) {
 	batches := batchpersignal.SplitMetrics(md)
 
 	exporterSegregatedMetrics := make(exporterMetrics)
@@ -90,13 +142,13 @@ func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metri
 	for _, batch := range batches {
 		routingIDs, err := routingIdentifiersFromMetrics(batch, e.routingKey)
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 
 		for rid := range routingIDs {
 			exp, endpoint, err := e.loadBalancer.exporterAndEndpoint([]byte(rid))
 			if err != nil {
-				return err
+				return nil, nil, err
 			}
 
 			_, ok := exporterSegregatedMetrics[exp]
@@ -110,29 +162,171 @@ func (e *metricExporterImp) ConsumeMetrics(ctx context.Context, md pmetric.Metri
 		}
 	}
 
-	var errs error
+	return exporterSegregatedMetrics, endpoints, nil
+}
 
-	for exp, metrics := range exporterSegregatedMetrics {
-		start := time.Now()
-		err := exp.ConsumeMetrics(ctx, metrics)
-		exp.consumeWG.Done()
-		duration := time.Since(start)
-		errs = multierr.Append(errs, err)
+// splitByDataPoint routes each data point on its own, keeping the resource, scope and metric it belongs to.
+func (e *metricExporterImp) splitByDataPoint(md pmetric.Metrics) (exporterMetrics, map[*wrappedExporter]string, error) {
+	builders := make(map[*wrappedExporter]*metricsBuilder)
+	endpoints := make(map[*wrappedExporter]string)
 
-		if err == nil {
-			_ = stats.RecordWithTags(
-				ctx,
-				[]tag.Mutator{tag.Upsert(endpointTagKey, endpoints[exp]), successTrueMutator},
-				mBackendLatency.M(duration.Milliseconds()))
-		} else {
-			_ = stats.RecordWithTags(
-				ctx,
-				[]tag.Mutator{tag.Upsert(endpointTagKey, endpoints[exp]), successFalseMutator},
-				mBackendLatency.M(duration.Milliseconds()))
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		sms := rm.ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			sm := sms.At(j)
+			metrics := sm.Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				metric := metrics.At(k)
+
+				// destination returns the metric of the exporter responsible for the data point with the given attributes
+				destination := func(attrs pcommon.Map) (pmetric.Metric, error) {
+					rid := e.dataPointRoutingKey(rm.Resource(), sm.Scope(), metric, attrs)
+					exp, endpoint, err := e.loadBalancer.exporterAndEndpoint([]byte(rid))
+					if err != nil {
+						return pmetric.Metric{}, err
+					}
+
+					b, ok := builders[exp]
+					if !ok {
+						exp.consumeWG.Add(1)
+						b = newMetricsBuilder()
+						builders[exp] = b
+						endpoints[exp] = endpoint
+					}
+					return b.metricFor(rm, i, j, k), nil
+				}
+
+				switch metric.Type() {
+				case pmetric.MetricTypeGauge:
+					dps := metric.Gauge().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dest, err := destination(dps.At(l).Attributes())
+						if err != nil {
+							return nil, nil, err
+						}
+						dps.At(l).CopyTo(dest.Gauge().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricTypeSum:
+					dps := metric.Sum().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dest, err := destination(dps.At(l).Attributes())
+						if err != nil {
+							return nil, nil, err
+						}
+						dps.At(l).CopyTo(dest.Sum().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricTypeHistogram:
+					dps := metric.Histogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dest, err := destination(dps.At(l).Attributes())
+						if err != nil {
+							return nil, nil, err
+						}
+						dps.At(l).CopyTo(dest.Histogram().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					dps := metric.ExponentialHistogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dest, err := destination(dps.At(l).Attributes())
+						if err != nil {
+							return nil, nil, err
+						}
+						dps.At(l).CopyTo(dest.ExponentialHistogram().DataPoints().AppendEmpty())
+					}
+				case pmetric.MetricTypeSummary:
+					dps := metric.Summary().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						dest, err := destination(dps.At(l).Attributes())
+						if err != nil {
+							return nil, nil, err
+						}
+						dps.At(l).CopyTo(dest.Summary().DataPoints().AppendEmpty())
+					}
+				}
+			}
 		}
 	}
 
-	return errs
+	exporterSegregatedMetrics := make(exporterMetrics, len(builders))
+	for exp, b := range builders {
+		exporterSegregatedMetrics[exp] = b.md
+	}
+	return exporterSegregatedMetrics, endpoints, nil
+}
+
+func (e *metricExporterImp) dataPointRoutingKey(resource pcommon.Resource, scope pcommon.InstrumentationScope, metric pmetric.Metric, attrs pcommon.Map) string {
+	if e.routingKey == attributesRouting {
+		return attributesRoutingKey(e.routingAttributes, func(name string) (pcommon.Value, bool) {
+			if v, ok := resource.Attributes().Get(name); ok {
+				return v, true
+			}
+			return attrs.Get(name)
+		})
+	}
+	return streamIDRoutingKey(resource, scope, metric, attrs)
+}
+
+// metricsBuilder collects the data points routed to an exporter. As the data points are added in order,
+// it only needs to remember the source resource, scope and metric of the latest one to group them likewise.
+type metricsBuilder struct {
+	md     pmetric.Metrics
+	rm     pmetric.ResourceMetrics
+	sm     pmetric.ScopeMetrics
+	metric pmetric.Metric
+
+	rmIdx, smIdx, metricIdx int
+}
+
+func newMetricsBuilder() *metricsBuilder {
+	return &metricsBuilder{md: pmetric.NewMetrics(), rmIdx: -1, smIdx: -1, metricIdx: -1}
+}
+
+// metricFor returns the metric to which the data points of the source metric k, in the scope j of the
+// resource metrics rm at index i, are added.
+func (b *metricsBuilder) metricFor(rm pmetric.ResourceMetrics, i, j, k int) pmetric.Metric {
+	if i != b.rmIdx {
+		b.rm = b.md.ResourceMetrics().AppendEmpty()
+		rm.Resource().CopyTo(b.rm.Resource())
+		b.rm.SetSchemaUrl(rm.SchemaUrl())
+		b.rmIdx, b.smIdx = i, -1
+	}
+	sm := rm.ScopeMetrics().At(j)
+	if j != b.smIdx {
+		b.sm = b.rm.ScopeMetrics().AppendEmpty()
+		sm.Scope().CopyTo(b.sm.Scope())
+		b.sm.SetSchemaUrl(sm.SchemaUrl())
+		b.smIdx, b.metricIdx = j, -1
+	}
+	if k != b.metricIdx {
+		b.metric = b.sm.Metrics().AppendEmpty()
+		copyMetricWithoutDataPoints(sm.Metrics().At(k), b.metric)
+		b.metricIdx = k
+	}
+	return b.metric
+}
+
+func copyMetricWithoutDataPoints(src pmetric.Metric, dest pmetric.Metric) {
+	dest.SetName(src.Name())
+	dest.SetDescription(src.Description())
+	dest.SetUnit(src.Unit())
+	src.Metadata().CopyTo(dest.Metadata())
+
+	switch src.Type() {
+	case pmetric.MetricTypeGauge:
+		dest.SetEmptyGauge()
+	case pmetric.MetricTypeSum:
+		sum := dest.SetEmptySum()
+		sum.SetAggregationTemporality(src.Sum().AggregationTemporality())
+		sum.SetIsMonotonic(src.Sum().IsMonotonic())
+	case pmetric.MetricTypeHistogram:
+		dest.SetEmptyHistogram().SetAggregationTemporality(src.Histogram().AggregationTemporality())
+	case pmetric.MetricTypeExponentialHistogram:
+		dest.SetEmptyExponentialHistogram().SetAggregationTemporality(src.ExponentialHistogram().AggregationTemporality())
+	case pmetric.MetricTypeSummary:
+		dest.SetEmptySummary()
+	}
 }
 
 func routingIdentifiersFromMetrics(mds pmetric.Metrics, key routingKey) (map[string]bool, error) {
@@ -222,3 +416,23 @@ func resourceRoutingKey(md pmetric.Metric, attrs pcommon.Map) string {
 func metricRoutingKey(md pmetric.Metric) string {
 	return md.Name()
 }
+
+// streamIDRoutingKey identifies the stream of a data point, so that all the data points of a stream are routed
+// to the same backend, as required by the processors keeping per stream state, such as deltatocumulative.
+func streamIDRoutingKey(resource pcommon.Resource, scope pcommon.InstrumentationScope, md pmetric.Metric, attrs pcommon.Map) string {
+	parts := sortedMapAttrs(resource.Attributes())
+	parts = append(parts, scope.Name(), scope.Version())
+	parts = append(parts, sortedMapAttrs(scope.Attributes())...)
+	parts = append(parts, md.Name(), md.Type().String(), md.Unit())
+	switch md.Type() {
+	case pmetric.MetricTypeSum:
+		parts = append(parts, md.Sum().AggregationTemporality().String(), strconv.FormatBool(md.Sum().IsMonotonic()))
+	case pmetric.MetricTypeHistogram:
+		parts = append(parts, md.Histogram().AggregationTemporality().String())
+	case pmetric.MetricTypeExponentialHistogram:
+		parts = append(parts, md.ExponentialHistogram().AggregationTemporality().String())
+	}
+	parts = append(parts, sortedMapAttrs(attrs)...)
+
+	return strings.Join(parts, "")
+}
@@ -35,9 +35,11 @@ import (
 )
 
 const (
-	serviceRouteKey  = "service"
-	resourceRouteKey = "resource"
-	metricRouteKey   = "metric"
+	serviceRouteKey    = "service"
+	resourceRouteKey   = "resource"
+	metricRouteKey     = "metric"
+	attributesRouteKey = "attributes"
+	streamIDRouteKey   = "streamID"
 
 	ilsName1          = "library-1"
 	ilsName2          = "library-2"
@@ -85,6 +87,24 @@ func TestNewMetricsExporter(t *testing.T) {
 			resourceBasedRoutingConfig(),
 			nil,
 		},
+		{
+			"attributes",
+			attributesBasedRoutingConfig(),
+			nil,
+		},
+		{
+			"attributes without routing attributes",
+			&Config{
+				Resolver:   ResolverSettings{Static: &StaticResolver{Hostnames: []string{"endpoint-1"}}},
+				RoutingKey: attributesRouteKey,
+			},
+			errNoRoutingAttributes,
+		},
+		{
+			"streamID",
+			streamIDBasedRoutingConfig(),
+			nil,
+		},
 		{
 			"traceID",
 			&Config{
@@ -589,6 +609,184 @@ func TestMetricNameRoutingKey(t *testing.T) {
 
 }
 
+func TestConsumeMetricsStreamIDBased(t *testing.T) {
+	var mu sync.Mutex
+	received := map[string][]pmetric.Metrics{}
+	componentFactory := func(_ context.Context, endpoint string) (component.Component, error) {
+		return newMockMetricsExporter(func(_ context.Context, md pmetric.Metrics) error {
+			mu.Lock()
+			defer mu.Unlock()
+			received[endpoint] = append(received[endpoint], md)
+			return nil
+		}), nil
+	}
+	lb, err := newLoadBalancer(exportertest.NewNopSettings(), streamIDBasedRoutingConfig(), componentFactory)
+	require.NotNil(t, lb)
+	require.NoError(t, err)
+
+	p, err := newMetricsExporter(exportertest.NewNopSettings(), streamIDBasedRoutingConfig())
+	require.NotNil(t, p)
+	require.NoError(t, err)
+	assert.Equal(t, p.routingKey, streamIDRouting)
+
+	lb.addMissingExporters(context.Background(), []string{"endpoint-1", "endpoint-2"})
+	lb.res = &mockResolver{
+		triggerCallbacks: true,
+		onResolve: func(_ context.Context) ([]string, error) {
+			return []string{"endpoint-1", "endpoint-2"}, nil
+		},
+	}
+	p.loadBalancer = lb
+
+	err = p.Start(context.Background(), componenttest.NewNopHost())
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr(conventions.AttributeServiceName, serviceName1)
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName(ilsName1)
+	metric := sm.Metrics().AppendEmpty()
+	metric.SetName(signal1Name)
+	sum := metric.SetEmptySum()
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	sum.SetIsMonotonic(true)
+	for i := 0; i < 20; i++ {
+		dp := sum.DataPoints().AppendEmpty()
+		dp.Attributes().PutInt("stream", int64(i))
+		dp.SetIntValue(int64(i))
+	}
+
+	// the streams are routed to the same backend every time
+	streams := map[int64]string{}
+	for round := 0; round < 2; round++ {
+		received = map[string][]pmetric.Metrics{}
+		require.NoError(t, p.ConsumeMetrics(context.Background(), md))
+		assert.Len(t, received, 2)
+
+		total := 0
+		for endpoint, batches := range received {
+			require.Len(t, batches, 1)
+			// the data points keep their resource, scope and metric
+			require.Equal(t, 1, batches[0].ResourceMetrics().Len())
+			rm := batches[0].ResourceMetrics().At(0)
+			assert.Equal(t, map[string]any{conventions.AttributeServiceName: serviceName1}, rm.Resource().Attributes().AsRaw())
+			require.Equal(t, 1, rm.ScopeMetrics().Len())
+			assert.Equal(t, ilsName1, rm.ScopeMetrics().At(0).Scope().Name())
+			require.Equal(t, 1, rm.ScopeMetrics().At(0).Metrics().Len())
+			metric := rm.ScopeMetrics().At(0).Metrics().At(0)
+			assert.Equal(t, signal1Name, metric.Name())
+			assert.Equal(t, pmetric.AggregationTemporalityDelta, metric.Sum().AggregationTemporality())
+			assert.True(t, metric.Sum().IsMonotonic())
+
+			dps := metric.Sum().DataPoints()
+			for i := 0; i < dps.Len(); i++ {
+				stream, _ := dps.At(i).Attributes().Get("stream")
+				assert.Equal(t, stream.Int(), dps.At(i).IntValue())
+				if previous, found := streams[stream.Int()]; found {
+					assert.Equal(t, previous, endpoint)
+				}
+				streams[stream.Int()] = endpoint
+				total++
+			}
+		}
+		assert.Equal(t, 20, total)
+	}
+	assert.Len(t, streams, 20)
+}
+
+func TestStreamIDRoutingKey(t *testing.T) {
+	newDataPoint := func() (pmetric.ResourceMetrics, pmetric.ScopeMetrics, pmetric.Metric, pmetric.NumberDataPoint) {
+		rm := pmetric.NewResourceMetrics()
+		rm.Resource().Attributes().PutStr(conventions.AttributeServiceName, serviceName1)
+		sm := rm.ScopeMetrics().AppendEmpty()
+		sm.Scope().SetName(ilsName1)
+		metric := sm.Metrics().AppendEmpty()
+		metric.SetName(signal1Name)
+		sum := metric.SetEmptySum()
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		dp := sum.DataPoints().AppendEmpty()
+		dp.Attributes().PutStr(signal1Attr1Key, signal1Attr1Value)
+		return rm, sm, metric, dp
+	}
+	rm, sm, metric, dp := newDataPoint()
+	key := streamIDRoutingKey(rm.Resource(), sm.Scope(), metric, dp.Attributes())
+
+	for _, tt := range []struct {
+		desc   string
+		modify func(pmetric.ResourceMetrics, pmetric.ScopeMetrics, pmetric.Metric, pmetric.NumberDataPoint)
+		same   bool
+	}{
+		{
+			"value",
+			func(_ pmetric.ResourceMetrics, _ pmetric.ScopeMetrics, _ pmetric.Metric, dp pmetric.NumberDataPoint) {
+				dp.SetIntValue(10)
+			},
+			true,
+		},
+		{
+			"data point attributes",
+			func(_ pmetric.ResourceMetrics, _ pmetric.ScopeMetrics, _ pmetric.Metric, dp pmetric.NumberDataPoint) {
+				dp.Attributes().PutStr(signal1Attr1Key, "other")
+			},
+			false,
+		},
+		{
+			"resource",
+			func(rm pmetric.ResourceMetrics, _ pmetric.ScopeMetrics, _ pmetric.Metric, _ pmetric.NumberDataPoint) {
+				rm.Resource().Attributes().PutStr(conventions.AttributeServiceName, serviceName2)
+			},
+			false,
+		},
+		{
+			"scope",
+			func(_ pmetric.ResourceMetrics, sm pmetric.ScopeMetrics, _ pmetric.Metric, _ pmetric.NumberDataPoint) {
+				sm.Scope().SetName(ilsName2)
+			},
+			false,
+		},
+		{
+			"temporality",
+			func(_ pmetric.ResourceMetrics, _ pmetric.ScopeMetrics, metric pmetric.Metric, _ pmetric.NumberDataPoint) {
+				metric.Sum().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+			},
+			false,
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			rm, sm, metric, dp := newDataPoint()
+			tt.modify(rm, sm, metric, dp)
+			if tt.same {
+				assert.Equal(t, key, streamIDRoutingKey(rm.Resource(), sm.Scope(), metric, dp.Attributes()))
+			} else {
+				assert.NotEqual(t, key, streamIDRoutingKey(rm.Resource(), sm.Scope(), metric, dp.Attributes()))
+			}
+		})
+	}
+}
+
+func TestMetricsAttributesRoutingKey(t *testing.T) {
+	p, err := newMetricsExporter(exportertest.NewNopSettings(), attributesBasedRoutingConfig())
+	require.NoError(t, err)
+	assert.Equal(t, p.routingKey, attributesRouting)
+
+	resource := pcommon.NewResource()
+	metric := pmetric.NewMetric()
+	attrs := pcommon.NewMap()
+	attrs.PutStr("tenant", "tenant-2")
+	attrs.PutStr(signal1Attr1Key, signal1Attr1Value)
+
+	// the data point attributes are used when the resource doesn't have them
+	assert.Equal(t, "8:tenant-2;", p.dataPointRoutingKey(resource, pcommon.NewInstrumentationScope(), metric, attrs))
+
+	// the resource attributes take precedence
+	resource.Attributes().PutStr("tenant", "tenant-1")
+	assert.Equal(t, "8:tenant-1;", p.dataPointRoutingKey(resource, pcommon.NewInstrumentationScope(), metric, attrs))
+}
+
 func TestRollingUpdatesWhenConsumeMetrics(t *testing.T) {
 	t.Skip("Flaky Test - See https://github.com/open-telemetry/opentelemetry-collector-contrib/issues/13331")
 
@@ -847,6 +1045,25 @@ func metricNameBasedRoutingConfig() *Config {
 	}
 }
 
+func attributesBasedRoutingConfig() *Config {
+	return &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}},
+		},
+		RoutingKey:        attributesRouteKey,
+		RoutingAttributes: []string{"tenant"},
+	}
+}
+
+func streamIDBasedRoutingConfig() *Config {
+	return &Config{
+		Resolver: ResolverSettings{
+			Static: &StaticResolver{Hostnames: []string{"endpoint-1", "endpoint-2"}},
+		},
+		RoutingKey: streamIDRouteKey,
+	}
+}
+
 func randomMetrics() pmetric.Metrics {
 	v1 := uint64(rand.Intn(256))
 	name := strconv.FormatUint(v1, 10)
@@ -48,9 +48,10 @@ type k8sResolver struct {
 	svcNs   string
 	port    []int32
 
-	handler        *handler
+	handler        cache.ResourceEventHandler
 	once           *sync.Once
 	epsListWatcher cache.ListerWatcher
+	epsObjType     runtime.Object
 	endpointsStore *sync.Map
 
 	lwTimeout time.Duration
@@ -77,19 +78,7 @@ func newK8sResolver(clt kubernetes.Interface,
 		timeout = defaultListWatchTimeout
 	}
 
-	nAddr := strings.SplitN(service, ".", 2)
-	name, namespace := nAddr[0], "default"
-	if len(nAddr) > 1 {
-		namespace = nAddr[1]
-	} else {
-		logger.Info("the namespace for the Kubernetes service wasn't provided, trying to determine the current namespace", zap.String("name", name))
-		if ns, err := getInClusterNamespace(); err == nil {
-			namespace = ns
-			logger.Info("namespace for the Collector determined", zap.String("namespace", namespace))
-		} else {
-			logger.Warn(`could not determine the namespace for this collector, will use "default" as the namespace`, zap.Error(err))
-		}
-	}
+	name, namespace := serviceNameAndNamespace(logger, service)
 
 	epsSelector := fmt.Sprintf("metadata.name=%s", name)
 	epsListWatcher := &cache.ListWatch{
@@ -115,6 +104,7 @@ func newK8sResolver(clt kubernetes.Interface,
 		once:           &sync.Once{},
 		endpointsStore: epsStore,
 		epsListWatcher: epsListWatcher,
+		epsObjType:     &corev1.Endpoints{},
 		handler:        h,
 		stopCh:         make(chan struct{}),
 		lwTimeout:      timeout,
@@ -124,12 +114,31 @@ func newK8sResolver(clt kubernetes.Interface,
 	return r, nil
 }
 
+// serviceNameAndNamespace splits the service into its name and namespace, determining the namespace
+// when the service doesn't have any.
+func serviceNameAndNamespace(logger *zap.Logger, service string) (string, string) {
+	nAddr := strings.SplitN(service, ".", 2)
+	name, namespace := nAddr[0], "default"
+	if len(nAddr) > 1 {
+		namespace = nAddr[1]
+	} else {
+		logger.Info("the namespace for the Kubernetes service wasn't provided, trying to determine the current namespace", zap.String("name", name))
+		if ns, err := getInClusterNamespace(); err == nil {
+			namespace = ns
+			logger.Info("namespace for the Collector determined", zap.String("namespace", namespace))
+		} else {
+			logger.Warn(`could not determine the namespace for this collector, will use "default" as the namespace`, zap.Error(err))
+		}
+	}
+	return name, namespace
+}
+
 func (r *k8sResolver) start(_ context.Context) error {
 	var initErr error
 	r.once.Do(func() {
 		if r.epsListWatcher != nil {
 			r.logger.Debug("creating and starting endpoints informer")
-			epsInformer := cache.NewSharedInformer(r.epsListWatcher, &corev1.Endpoints{}, 0)
+			epsInformer := cache.NewSharedInformer(r.epsListWatcher, r.epsObjType, 0)
 			if _, err := epsInformer.AddEventHandler(r.handler); err != nil {
 				r.logger.Error("unable to start watching for changes to the specified service names", zap.Error(err))
 			}
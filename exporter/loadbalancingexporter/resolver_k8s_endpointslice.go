@@ -0,0 +1,253 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opencensus.io/stats"
+	"go.uber.org/zap"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/ptr"
+)
+
+var (
+	errNoZone                        = errors.New("no zone specified for the topology aware resolution of the backends")
+	errTopologyWithoutEndpointSlices = errors.New("the topology aware resolution of the backends requires use_endpoint_slices")
+)
+
+const defaultMinZoneBackends = 1
+
+// newK8sEndpointSliceResolver creates a resolver for the backends of a Kubernetes service, based on its EndpointSlices.
+func newK8sEndpointSliceResolver(clt kubernetes.Interface,
+	logger *zap.Logger,
+	service string,
+	ports []int32, timeout time.Duration, topology *K8sTopology) (*k8sResolver, error) {
+
+	if len(service) == 0 {
+		return nil, errNoSvc
+	}
+
+	if timeout == 0 {
+		timeout = defaultListWatchTimeout
+	}
+
+	var zone *zoneFilter
+	if topology != nil {
+		if len(topology.Zone) == 0 {
+			return nil, errNoZone
+		}
+		zone = &zoneFilter{
+			zone:              topology.Zone,
+			minBackends:       topology.MinZoneBackends,
+			crossZoneFallback: topology.CrossZoneFallback == nil || *topology.CrossZoneFallback,
+		}
+		if zone.minBackends <= 0 {
+			zone.minBackends = defaultMinZoneBackends
+		}
+	}
+
+	name, namespace := serviceNameAndNamespace(logger, service)
+
+	slicesSelector := fmt.Sprintf("%s=%s", discoveryv1.LabelServiceName, name)
+	slicesListWatcher := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			options.LabelSelector = slicesSelector
+			options.TimeoutSeconds = ptr.To[int64](int64(timeout.Seconds()))
+			return clt.DiscoveryV1().EndpointSlices(namespace).List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			options.LabelSelector = slicesSelector
+			options.TimeoutSeconds = ptr.To[int64](int64(timeout.Seconds()))
+			return clt.DiscoveryV1().EndpointSlices(namespace).Watch(context.Background(), options)
+		},
+	}
+
+	epsStore := &sync.Map{}
+	h := &endpointSliceHandler{
+		endpoints: epsStore,
+		logger:    logger,
+		zone:      zone,
+		slices:    map[string]*discoveryv1.EndpointSlice{},
+	}
+	r := &k8sResolver{
+		logger:         logger,
+		svcName:        name,
+		svcNs:          namespace,
+		port:           ports,
+		once:           &sync.Once{},
+		endpointsStore: epsStore,
+		epsListWatcher: slicesListWatcher,
+		epsObjType:     &discoveryv1.EndpointSlice{},
+		handler:        h,
+		stopCh:         make(chan struct{}),
+		lwTimeout:      timeout,
+	}
+	h.callback = r.resolve
+
+	return r, nil
+}
+
+// zoneFilter restricts the backends to the ones of a zone.
+type zoneFilter struct {
+	zone              string
+	minBackends       int
+	crossZoneFallback bool
+}
+
+var _ cache.ResourceEventHandler = (*endpointSliceHandler)(nil)
+
+// endpointSliceHandler keeps track of the EndpointSlices of a service, and stores the addresses of the backends to use.
+// As the backends are selected among the endpoints of all the slices, the slices are kept as a whole.
+type endpointSliceHandler struct {
+	endpoints *sync.Map
+	callback  func(ctx context.Context) ([]string, error)
+	logger    *zap.Logger
+	zone      *zoneFilter
+
+	mu     sync.Mutex
+	slices map[string]*discoveryv1.EndpointSlice
+}
+
+func (h *endpointSliceHandler) OnAdd(obj any, _ bool) {
+	slice, ok := obj.(*discoveryv1.EndpointSlice)
+	if !ok {
+		h.logger.Warn("Got an unexpected Kubernetes data type during the inclusion of a new pods for the service", zap.Any("obj", obj))
+		_ = stats.RecordWithTags(context.Background(), k8sResolverSuccessFalseMutators, mNumResolutions.M(1))
+		return
+	}
+	h.update(func() {
+		h.slices[slice.Name] = slice
+	})
+}
+
+func (h *endpointSliceHandler) OnUpdate(_, newObj any) {
+	slice, ok := newObj.(*discoveryv1.EndpointSlice)
+	if !ok {
+		h.logger.Warn("Got an unexpected Kubernetes data type during the update of the pods for a service", zap.Any("obj", newObj))
+		_ = stats.RecordWithTags(context.Background(), k8sResolverSuccessFalseMutators, mNumResolutions.M(1))
+		return
+	}
+	h.update(func() {
+		h.slices[slice.Name] = slice
+	})
+}
+
+func (h *endpointSliceHandler) OnDelete(obj any) {
+	switch object := obj.(type) {
+	case cache.DeletedFinalStateUnknown:
+		h.OnDelete(object.Obj)
+	case *cache.DeletedFinalStateUnknown:
+		h.OnDelete(object.Obj)
+	case *discoveryv1.EndpointSlice:
+		h.update(func() {
+			delete(h.slices, object.Name)
+		})
+	default: // unsupported
+		h.logger.Warn("Got an unexpected Kubernetes data type during the removal of the pods for a service", zap.Any("obj", obj))
+		_ = stats.RecordWithTags(context.Background(), k8sResolverSuccessFalseMutators, mNumResolutions.M(1))
+	}
+}
+
+// update applies the change to the slices, and propagates the resulting backends if they changed.
+func (h *endpointSliceHandler) update(change func()) {
+	h.mu.Lock()
+	change()
+	backends := h.backends()
+	h.mu.Unlock()
+
+	changed := false
+	h.endpoints.Range(func(address, _ any) bool {
+		if !backends[address.(string)] {
+			h.endpoints.Delete(address)
+			changed = true
+		}
+		return true
+	})
+	for address := range backends {
+		if _, loaded := h.endpoints.LoadOrStore(address, true); !loaded {
+			changed = true
+		}
+	}
+	if changed {
+		_, _ = h.callback(context.Background())
+	}
+}
+
+// backends returns the addresses of the endpoints to use, among the endpoints of all the slices:
+//   - the ready endpoints, or the serving ones if none is ready, such as when all the backends are terminating.
+//   - when a zone is configured, the endpoints of the zone, unless the zone has too few of them and the
+//     backends of the other zones can be used. The endpoints having topology hints are in the zones of their
+//     hints, while the others are in the zone they are running in.
+func (h *endpointSliceHandler) backends() map[string]bool {
+	var ready, serving []discoveryv1.Endpoint
+	for _, slice := range h.slices {
+		for _, endpoint := range slice.Endpoints {
+			switch {
+			case isEndpointReady(endpoint):
+				ready = append(ready, endpoint)
+			case endpoint.Conditions.Serving != nil && *endpoint.Conditions.Serving:
+				serving = append(serving, endpoint)
+			}
+		}
+	}
+
+	candidates := ready
+	if len(candidates) == 0 {
+		candidates = serving
+	}
+
+	if h.zone != nil {
+		var inZone []discoveryv1.Endpoint
+		for _, endpoint := range candidates {
+			if h.zone.contains(endpoint) {
+				inZone = append(inZone, endpoint)
+			}
+		}
+		if len(inZone) >= h.zone.minBackends || !h.zone.crossZoneFallback {
+			candidates = inZone
+		} else {
+			h.logger.Debug("not enough backends in the zone, using the backends of all the zones",
+				zap.String("zone", h.zone.zone), zap.Int("backends", len(inZone)))
+		}
+	}
+
+	backends := map[string]bool{}
+	for _, endpoint := range candidates {
+		for _, address := range endpoint.Addresses {
+			backends[address] = true
+		}
+	}
+	return backends
+}
+
+// isEndpointReady returns whether the endpoint is ready and not terminating. An unknown readiness
+// is interpreted as ready, as recommended by the EndpointSlice API.
+func isEndpointReady(endpoint discoveryv1.Endpoint) bool {
+	if endpoint.Conditions.Terminating != nil && *endpoint.Conditions.Terminating {
+		return false
+	}
+	return endpoint.Conditions.Ready == nil || *endpoint.Conditions.Ready
+}
+
+func (z *zoneFilter) contains(endpoint discoveryv1.Endpoint) bool {
+	if endpoint.Hints != nil && len(endpoint.Hints.ForZones) > 0 {
+		for _, hint := range endpoint.Hints.ForZones {
+			if hint.Name == z.zone {
+				return true
+			}
+		}
+		return false
+	}
+	return endpoint.Zone != nil && *endpoint.Zone == z.zone
+}
@@ -0,0 +1,245 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package loadbalancingexporter
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestK8sEndpointSliceResolve(t *testing.T) {
+	// prepare
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "lb-abcde",
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "lb"},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints: []discoveryv1.Endpoint{
+			newTestEndpoint("10.0.0.1", "zone-a", true, false),
+			newTestEndpoint("10.0.0.2", "zone-b", true, false),
+			newTestEndpoint("10.0.0.3", "zone-a", false, false),
+		},
+	}
+	cl := fake.NewSimpleClientset(slice)
+	res, err := newK8sEndpointSliceResolver(cl, zap.NewNop(), "lb", []int32{4317}, defaultListWatchTimeout, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, res.start(context.Background()))
+	defer func() {
+		require.NoError(t, res.shutdown(context.Background()))
+	}()
+
+	// verify
+	// the endpoint which isn't ready isn't used
+	assert.Equal(t, []string{"10.0.0.1:4317", "10.0.0.2:4317"}, res.Endpoints())
+
+	// test
+	updated := slice.DeepCopy()
+	updated.Endpoints[0] = newTestEndpoint("10.0.0.1", "zone-a", false, true)
+	_, err = cl.DiscoveryV1().EndpointSlices("default").Update(context.Background(), updated, metav1.UpdateOptions{})
+	require.NoError(t, err)
+
+	// verify
+	assert.Eventually(t, func() bool {
+		_, err := res.resolve(context.Background())
+		return err == nil && assert.ObjectsAreEqual([]string{"10.0.0.2:4317"}, res.Endpoints())
+	}, time.Second, 20*time.Millisecond)
+
+	// test
+	other := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "lb-fghij",
+			Namespace: "default",
+			Labels:    map[string]string{discoveryv1.LabelServiceName: "lb"},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+		Endpoints: []discoveryv1.Endpoint{
+			newTestEndpoint("10.0.1.1", "zone-b", true, false),
+		},
+	}
+	_, err = cl.DiscoveryV1().EndpointSlices("default").Create(context.Background(), other, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	// verify
+	assert.Eventually(t, func() bool {
+		_, err := res.resolve(context.Background())
+		return err == nil && assert.ObjectsAreEqual([]string{"10.0.0.2:4317", "10.0.1.1:4317"}, res.Endpoints())
+	}, time.Second, 20*time.Millisecond)
+
+	// test
+	require.NoError(t, cl.DiscoveryV1().EndpointSlices("default").Delete(context.Background(), slice.Name, metav1.DeleteOptions{}))
+
+	// verify
+	assert.Eventually(t, func() bool {
+		_, err := res.resolve(context.Background())
+		return err == nil && assert.ObjectsAreEqual([]string{"10.0.1.1:4317"}, res.Endpoints())
+	}, time.Second, 20*time.Millisecond)
+}
+
+func TestEndpointSliceHandlerBackends(t *testing.T) {
+	withHints := func(endpoint discoveryv1.Endpoint, zones ...string) discoveryv1.Endpoint {
+		endpoint.Hints = &discoveryv1.EndpointHints{}
+		for _, zone := range zones {
+			endpoint.Hints.ForZones = append(endpoint.Hints.ForZones, discoveryv1.ForZone{Name: zone})
+		}
+		return endpoint
+	}
+
+	for _, tt := range []struct {
+		desc      string
+		topology  *K8sTopology
+		endpoints []discoveryv1.Endpoint
+		expected  []string
+	}{
+		{
+			desc: "ready endpoints",
+			endpoints: []discoveryv1.Endpoint{
+				newTestEndpoint("10.0.0.1", "zone-a", true, false),
+				newTestEndpoint("10.0.0.2", "zone-b", false, false),
+				newTestEndpoint("10.0.0.3", "zone-b", true, true),
+				{Addresses: []string{"10.0.0.4"}},
+			},
+			expected: []string{"10.0.0.1", "10.0.0.4"},
+		},
+		{
+			desc: "serving endpoints when none is ready",
+			endpoints: []discoveryv1.Endpoint{
+				newTestEndpoint("10.0.0.1", "zone-a", false, true),
+				newTestEndpoint("10.0.0.2", "zone-b", false, false),
+			},
+			expected: []string{"10.0.0.1"},
+		},
+		{
+			desc:     "endpoints of the zone",
+			topology: &K8sTopology{Zone: "zone-a"},
+			endpoints: []discoveryv1.Endpoint{
+				newTestEndpoint("10.0.0.1", "zone-a", true, false),
+				newTestEndpoint("10.0.0.2", "zone-b", true, false),
+			},
+			expected: []string{"10.0.0.1"},
+		},
+		{
+			desc:     "topology hints",
+			topology: &K8sTopology{Zone: "zone-a"},
+			endpoints: []discoveryv1.Endpoint{
+				withHints(newTestEndpoint("10.0.0.1", "zone-a", true, false), "zone-c"),
+				withHints(newTestEndpoint("10.0.0.2", "zone-b", true, false), "zone-a", "zone-b"),
+			},
+			expected: []string{"10.0.0.2"},
+		},
+		{
+			desc:     "cross zone fallback",
+			topology: &K8sTopology{Zone: "zone-a", MinZoneBackends: 2},
+			endpoints: []discoveryv1.Endpoint{
+				newTestEndpoint("10.0.0.1", "zone-a", true, false),
+				newTestEndpoint("10.0.0.2", "zone-b", true, false),
+			},
+			expected: []string{"10.0.0.1", "10.0.0.2"},
+		},
+		{
+			desc:     "cross zone fallback disabled",
+			topology: &K8sTopology{Zone: "zone-c", CrossZoneFallback: ptr.To(false)},
+			endpoints: []discoveryv1.Endpoint{
+				newTestEndpoint("10.0.0.1", "zone-a", true, false),
+				newTestEndpoint("10.0.0.2", "zone-b", true, false),
+			},
+			expected: nil,
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			res, err := newK8sEndpointSliceResolver(fake.NewSimpleClientset(), zap.NewNop(), "lb.default", nil, defaultListWatchTimeout, tt.topology)
+			require.NoError(t, err)
+			h := res.handler.(*endpointSliceHandler)
+
+			// test
+			h.OnAdd(&discoveryv1.EndpointSlice{
+				ObjectMeta: metav1.ObjectMeta{Name: "lb-abcde"},
+				Endpoints:  tt.endpoints,
+			}, false)
+
+			// verify
+			var backends []string
+			h.endpoints.Range(func(address, _ any) bool {
+				backends = append(backends, address.(string))
+				return true
+			})
+			sort.Strings(backends)
+			assert.Equal(t, tt.expected, backends)
+			assert.Equal(t, tt.expected, res.Endpoints())
+		})
+	}
+}
+
+func Test_newK8sEndpointSliceResolver(t *testing.T) {
+	for _, tt := range []struct {
+		desc     string
+		service  string
+		topology *K8sTopology
+		expected *zoneFilter
+		err      error
+	}{
+		{
+			desc:    "no service",
+			service: "",
+			err:     errNoSvc,
+		},
+		{
+			desc:     "no zone",
+			service:  "lb.default",
+			topology: &K8sTopology{},
+			err:      errNoZone,
+		},
+		{
+			desc:     "topology defaults",
+			service:  "lb.default",
+			topology: &K8sTopology{Zone: "zone-a"},
+			expected: &zoneFilter{zone: "zone-a", minBackends: 1, crossZoneFallback: true},
+		},
+		{
+			desc:     "topology",
+			service:  "lb.default",
+			topology: &K8sTopology{Zone: "zone-a", MinZoneBackends: 3, CrossZoneFallback: ptr.To(false)},
+			expected: &zoneFilter{zone: "zone-a", minBackends: 3, crossZoneFallback: false},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// test
+			res, err := newK8sEndpointSliceResolver(fake.NewSimpleClientset(), zap.NewNop(), tt.service, nil, 0, tt.topology)
+
+			// verify
+			require.Equal(t, tt.err, err)
+			if err == nil {
+				assert.Equal(t, "lb", res.svcName)
+				assert.Equal(t, "default", res.svcNs)
+				assert.Equal(t, defaultListWatchTimeout, res.lwTimeout)
+				assert.Equal(t, tt.expected, res.handler.(*endpointSliceHandler).zone)
+			}
+		})
+	}
+}
+
+func newTestEndpoint(address string, zone string, ready bool, terminating bool) discoveryv1.Endpoint {
+	return discoveryv1.Endpoint{
+		Addresses: []string{address},
+		Zone:      ptr.To(zone),
+		Conditions: discoveryv1.EndpointConditions{
+			Ready:       ptr.To(ready),
+			Serving:     ptr.To(ready || terminating),
+			Terminating: ptr.To(terminating),
+		},
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/exporter"
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.uber.org/multierr"
 
@@ -27,8 +28,9 @@ var _ exporter.Traces = (*traceExporterImp)(nil)
 type exporterTraces map[*wrappedExporter]ptrace.Traces
 
 type traceExporterImp struct {
-	loadBalancer *loadBalancer
-	routingKey   routingKey
+	loadBalancer      *loadBalancer
+	routingKey        routingKey
+	routingAttributes []string
 
 	stopped    bool
 	shutdownWg sync.WaitGroup
@@ -52,6 +54,12 @@ func newTracesExporter(params exporter.Settings, cfg component.Config) (*traceEx
 	case "service":
 		traceExporter.routingKey = svcRouting
 	case "traceID", "":
+	case "attributes":
+		if len(cfg.(*Config).RoutingAttributes) == 0 {
+			return nil, errNoRoutingAttributes
+		}
+		traceExporter.routingKey = attributesRouting
+		traceExporter.routingAttributes = cfg.(*Config).RoutingAttributes
 	default:
 		return nil, fmt.Errorf("unsupported routing_key: %s", cfg.(*Config).RoutingKey)
 	}
@@ -85,7 +93,7 @@ func (e *traceExporterImp) ConsumeTraces(ctx context.Context, td ptrace.Traces)
 	exporterSegregatedTraces := make(exporterTraces)
 	endpoints := make(map[*wrappedExporter]string)
 	for _, batch := range batches {
-		routingID, err := routingIdentifiersFromTraces(batch, e.routingKey)
+		routingID, err := e.routingIdentifiers(batch)
 		if err != nil {
 			return err
 		}
@@ -132,6 +140,39 @@ func (e *traceExporterImp) ConsumeTraces(ctx context.Context, td ptrace.Traces)
 	return errs
 }
 
+func (e *traceExporterImp) routingIdentifiers(td ptrace.Traces) (map[string]bool, error) {
+	if e.routingKey == attributesRouting {
+		// the whole trace goes to the same backend, so that the tail-based samplers see all of it
+		rid := attributesRoutingKey(e.routingAttributes, func(name string) (pcommon.Value, bool) {
+			return traceAttribute(td, name)
+		})
+		return map[string]bool{rid: true}, nil
+	}
+	return routingIdentifiersFromTraces(td, e.routingKey)
+}
+
+// traceAttribute returns the value of the attribute from the resources of the trace, or else from its first span having it.
+func traceAttribute(td ptrace.Traces, name string) (pcommon.Value, bool) {
+	rs := td.ResourceSpans()
+	for i := 0; i < rs.Len(); i++ {
+		if v, ok := rs.At(i).Resource().Attributes().Get(name); ok {
+			return v, true
+		}
+	}
+	for i := 0; i < rs.Len(); i++ {
+		ils := rs.At(i).ScopeSpans()
+		for j := 0; j < ils.Len(); j++ {
+			spans := ils.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				if v, ok := spans.At(k).Attributes().Get(name); ok {
+					return v, true
+				}
+			}
+		}
+	}
+	return pcommon.Value{}, false
+}
+
 func routingIdentifiersFromTraces(td ptrace.Traces, key routingKey) (map[string]bool, error) {
 	ids := make(map[string]bool)
 	rs := td.ResourceSpans()
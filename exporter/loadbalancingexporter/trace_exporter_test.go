@@ -49,6 +49,19 @@ func TestNewTracesExporter(t *testing.T) {
 			&Config{},
 			errNoResolver,
 		},
+		{
+			"attributes",
+			attributesBasedRoutingConfig(),
+			nil,
+		},
+		{
+			"attributes without routing attributes",
+			&Config{
+				Resolver:   ResolverSettings{Static: &StaticResolver{Hostnames: []string{"endpoint-1"}}},
+				RoutingKey: attributesRouteKey,
+			},
+			errNoRoutingAttributes,
+		},
 	} {
 		t.Run(tt.desc, func(t *testing.T) {
 			// test
@@ -274,6 +287,51 @@ func TestServiceBasedRoutingForSameTraceId(t *testing.T) {
 	}
 }
 
+func TestAttributesBasedRoutingForTraces(t *testing.T) {
+	p, err := newTracesExporter(exportertest.NewNopSettings(), attributesBasedRoutingConfig())
+	require.NoError(t, err)
+	assert.Equal(t, p.routingKey, attributesRouting)
+
+	withSpanAttribute := ptrace.NewTraces()
+	rs := withSpanAttribute.ResourceSpans().AppendEmpty()
+	spans := rs.ScopeSpans().AppendEmpty().Spans()
+	spans.AppendEmpty().SetTraceID([16]byte{1, 2, 3, 4})
+	spans.AppendEmpty().Attributes().PutStr("tenant", "tenant-1")
+	spans.At(1).SetTraceID([16]byte{1, 2, 3, 4})
+
+	withResourceAttribute := ptrace.NewTraces()
+	withSpanAttribute.CopyTo(withResourceAttribute)
+	withResourceAttribute.ResourceSpans().At(0).Resource().Attributes().PutStr("tenant", "tenant-2")
+
+	for _, tt := range []struct {
+		desc  string
+		batch ptrace.Traces
+		res   map[string]bool
+	}{
+		{
+			"attribute of a span",
+			withSpanAttribute,
+			map[string]bool{"8:tenant-1;": true},
+		},
+		{
+			"attribute of the resource",
+			withResourceAttribute,
+			map[string]bool{"8:tenant-2;": true},
+		},
+		{
+			"no attribute",
+			simpleTraces(),
+			map[string]bool{"-;": true},
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			res, err := p.routingIdentifiers(tt.batch)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.res, res)
+		})
+	}
+}
+
 func TestConsumeTracesExporterNoEndpoint(t *testing.T) {
 	componentFactory := func(_ context.Context, _ string) (component.Component, error) {
 		return newNopMockTracesExporter(), nil
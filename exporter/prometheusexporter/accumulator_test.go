@@ -568,6 +568,96 @@ func TestAccumulateDeltaToCumulativeHistogram(t *testing.T) {
 	})
 }
 
+func TestAccumulateDeltaToCumulativeExponentialHistogram(t *testing.T) {
+	appendDeltaHistogram := func(startTs time.Time, ts time.Time, scale int32, offset int32, counts []uint64, metrics pmetric.MetricSlice) {
+		metric := metrics.AppendEmpty()
+		metric.SetName("test_metric")
+		metric.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+		dp := metric.ExponentialHistogram().DataPoints().AppendEmpty()
+		dp.SetScale(scale)
+		dp.Positive().SetOffset(offset)
+		dp.Positive().BucketCounts().FromRaw(counts)
+		var count uint64
+		for _, c := range counts {
+			count += c
+		}
+		dp.SetCount(count + 1)
+		dp.SetSum(float64(count))
+		dp.SetZeroCount(1)
+		dp.Attributes().PutStr("label_1", "1")
+		dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+		dp.SetStartTimestamp(pcommon.NewTimestampFromTime(startTs))
+	}
+
+	t.Run("AccumulateHappyPath", func(t *testing.T) {
+		startTs := time.Now().Add(-5 * time.Second)
+		ts1 := time.Now().Add(-4 * time.Second)
+		ts2 := time.Now().Add(-3 * time.Second)
+		resourceMetrics := pmetric.NewResourceMetrics()
+		ilm := resourceMetrics.ScopeMetrics().AppendEmpty()
+		ilm.Scope().SetName("test")
+		appendDeltaHistogram(startTs, ts1, 1, 2, []uint64{1, 2, 3}, ilm.Metrics())
+		appendDeltaHistogram(ts1, ts2, 1, 3, []uint64{1, 1, 0, 5}, ilm.Metrics())
+
+		signature := timeseriesSignature(ilm.Scope().Name(), ilm.Metrics().At(0), ilm.Metrics().At(0).ExponentialHistogram().DataPoints().At(0).Attributes(), pcommon.NewMap())
+
+		a := newAccumulator(zap.NewNop(), 1*time.Hour).(*lastValueAccumulator)
+		n := a.Accumulate(resourceMetrics)
+		require.Equal(t, 2, n)
+
+		m, ok := a.registeredMetrics.Load(signature)
+		require.True(t, ok)
+		v := m.(*accumulatedValue).value.ExponentialHistogram().DataPoints().At(0)
+
+		require.Equal(t, pcommon.NewTimestampFromTime(startTs), v.StartTimestamp())
+		require.Equal(t, pcommon.NewTimestampFromTime(ts2), v.Timestamp())
+		require.Equal(t, uint64(15), v.Count())
+		require.Equal(t, 13.0, v.Sum())
+		require.Equal(t, uint64(2), v.ZeroCount())
+		require.Equal(t, int32(1), v.Scale())
+		require.Equal(t, int32(2), v.Positive().Offset())
+		require.Equal(t, []uint64{1, 3, 4, 0, 5}, v.Positive().BucketCounts().AsRaw())
+	})
+	t.Run("DifferentScales", func(t *testing.T) {
+		startTs := time.Now().Add(-5 * time.Second)
+		ts1 := time.Now().Add(-4 * time.Second)
+		ts2 := time.Now().Add(-3 * time.Second)
+		resourceMetrics := pmetric.NewResourceMetrics()
+		ilm := resourceMetrics.ScopeMetrics().AppendEmpty()
+		ilm.Scope().SetName("test")
+		appendDeltaHistogram(startTs, ts1, 2, -2, []uint64{1, 2, 3, 4}, ilm.Metrics())
+		appendDeltaHistogram(ts1, ts2, 1, 0, []uint64{5}, ilm.Metrics())
+
+		signature := timeseriesSignature(ilm.Scope().Name(), ilm.Metrics().At(0), ilm.Metrics().At(0).ExponentialHistogram().DataPoints().At(0).Attributes(), pcommon.NewMap())
+
+		a := newAccumulator(zap.NewNop(), 1*time.Hour).(*lastValueAccumulator)
+		require.Equal(t, 2, a.Accumulate(resourceMetrics))
+
+		m, ok := a.registeredMetrics.Load(signature)
+		require.True(t, ok)
+		v := m.(*accumulatedValue).value.ExponentialHistogram().DataPoints().At(0)
+
+		// the buckets of scale 2 are merged pairwise into the buckets of scale 1
+		require.Equal(t, int32(1), v.Scale())
+		require.Equal(t, int32(-1), v.Positive().Offset())
+		require.Equal(t, []uint64{3, 12}, v.Positive().BucketCounts().AsRaw())
+	})
+	t.Run("Misaligned", func(t *testing.T) {
+		startTs := time.Now().Add(-5 * time.Second)
+		ts1 := time.Now().Add(-3 * time.Second)
+		ts2 := time.Now().Add(-4 * time.Second)
+		resourceMetrics := pmetric.NewResourceMetrics()
+		ilm := resourceMetrics.ScopeMetrics().AppendEmpty()
+		ilm.Scope().SetName("test")
+		appendDeltaHistogram(startTs, ts1, 1, 0, []uint64{1}, ilm.Metrics())
+		appendDeltaHistogram(startTs, ts2, 1, 0, []uint64{2}, ilm.Metrics())
+
+		// the second data point starts before the end of the first one, so it is dropped
+		a := newAccumulator(zap.NewNop(), 1*time.Hour).(*lastValueAccumulator)
+		require.Equal(t, 1, a.Accumulate(resourceMetrics))
+	})
+}
+
 func TestAccumulateDroppedMetrics(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -0,0 +1,168 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusexporter // import "github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusexporter"
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// nativeHistogramMinSchema and nativeHistogramMaxSchema bound the schemas of native histograms,
+	// which are the scales of exponential histograms.
+	nativeHistogramMinSchema = -4
+	nativeHistogramMaxSchema = 8
+)
+
+// nativeHistogram exposes an exponential histogram as a native histogram, along with the
+// equivalent classic buckets, which are the only ones served to the scrapers negotiating a text format.
+type nativeHistogram struct {
+	desc       *prometheus.Desc
+	labelPairs []*dto.LabelPair
+	histogram  *dto.Histogram
+}
+
+var _ prometheus.Metric = (*nativeHistogram)(nil)
+
+func (h *nativeHistogram) Desc() *prometheus.Desc {
+	return h.desc
+}
+
+func (h *nativeHistogram) Write(out *dto.Metric) error {
+	out.Label = h.labelPairs
+	out.Histogram = h.histogram
+	return nil
+}
+
+func newNativeHistogram(desc *prometheus.Desc, dp pmetric.ExponentialHistogramDataPoint, labelValues []string) (prometheus.Metric, error) {
+	schema := dp.Scale()
+	if schema < nativeHistogramMinSchema {
+		return nil, fmt.Errorf("cannot convert exponential histogram with scale %d, the minimum native histogram schema is %d", schema, nativeHistogramMinSchema)
+	}
+	// Buckets too fine-grained for native histograms are merged.
+	var scaleDown int32
+	if schema > nativeHistogramMaxSchema {
+		scaleDown = schema - nativeHistogramMaxSchema
+		schema = nativeHistogramMaxSchema
+	}
+	positiveOffset, positiveCounts := downscaleBuckets(dp.Positive(), scaleDown)
+	negativeOffset, negativeCounts := downscaleBuckets(dp.Negative(), scaleDown)
+
+	h := &dto.Histogram{
+		SampleCount:   proto.Uint64(dp.Count()),
+		SampleSum:     proto.Float64(dp.Sum()),
+		Schema:        proto.Int32(schema),
+		ZeroThreshold: proto.Float64(dp.ZeroThreshold()),
+		ZeroCount:     proto.Uint64(dp.ZeroCount()),
+	}
+	h.PositiveSpan, h.PositiveDelta = bucketSpans(positiveOffset, positiveCounts)
+	h.NegativeSpan, h.NegativeDelta = bucketSpans(negativeOffset, negativeCounts)
+	if len(h.PositiveSpan) == 0 && len(h.NegativeSpan) == 0 && dp.ZeroThreshold() == 0 {
+		// An empty span tells the scrapers that the histogram is native even if it has no buckets.
+		h.PositiveSpan = []*dto.BucketSpan{{Offset: proto.Int32(0), Length: proto.Uint32(0)}}
+	}
+	h.Bucket = classicBuckets(schema, dp.ZeroThreshold(), dp.ZeroCount(), positiveOffset, positiveCounts, negativeOffset, negativeCounts)
+
+	return &nativeHistogram{
+		desc:       desc,
+		labelPairs: prometheus.MakeLabelPairs(desc, labelValues),
+		histogram:  h,
+	}, nil
+}
+
+// downscaleBuckets returns the index of the first bucket and the counts of the buckets, after merging
+// every 2^scaleDown consecutive buckets.
+func downscaleBuckets(buckets pmetric.ExponentialHistogramDataPointBuckets, scaleDown int32) (int32, []uint64) {
+	counts := buckets.BucketCounts()
+	if counts.Len() == 0 {
+		return 0, nil
+	}
+	if scaleDown == 0 {
+		return buckets.Offset(), counts.AsRaw()
+	}
+	offset := buckets.Offset() >> scaleDown
+	last := (buckets.Offset() + int32(counts.Len()) - 1) >> scaleDown
+	merged := make([]uint64, last-offset+1)
+	for i := 0; i < counts.Len(); i++ {
+		merged[(buckets.Offset()+int32(i))>>scaleDown-offset] += counts.At(i)
+	}
+	return offset, merged
+}
+
+// bucketSpans converts the dense buckets of an exponential histogram, starting at index offset,
+// to the sparse buckets of a native histogram. The exponential histogram bucket index i covers
+// (base^i, base^(i+1)], while the native histogram bucket index i covers (base^(i-1), base^i].
+func bucketSpans(offset int32, counts []uint64) ([]*dto.BucketSpan, []int64) {
+	var (
+		spans     []*dto.BucketSpan
+		deltas    []int64
+		prevCount int64
+		nextIndex int32
+	)
+	appendDelta := func(count int64) {
+		*spans[len(spans)-1].Length++
+		deltas = append(deltas, count-prevCount)
+		prevCount = count
+	}
+	for i, count := range counts {
+		if count == 0 {
+			continue
+		}
+		index := offset + int32(i) + 1
+		switch gap := index - nextIndex; {
+		case len(spans) == 0:
+			spans = append(spans, &dto.BucketSpan{Offset: proto.Int32(index), Length: proto.Uint32(0)})
+		case gap > 2:
+			spans = append(spans, &dto.BucketSpan{Offset: proto.Int32(gap), Length: proto.Uint32(0)})
+		default:
+			// Short gaps are cheaper to fill with empty buckets than to start a new span.
+			for ; nextIndex < index; nextIndex++ {
+				appendDelta(0)
+			}
+		}
+		appendDelta(int64(count))
+		nextIndex = index + 1
+	}
+	return spans, deltas
+}
+
+// classicBuckets returns the cumulative buckets equivalent to the buckets of a native histogram.
+func classicBuckets(schema int32, zeroThreshold float64, zeroCount uint64, positiveOffset int32, positiveCounts []uint64,
+	negativeOffset int32, negativeCounts []uint64) []*dto.Bucket {
+	// upperBound returns the upper bound of the absolute values in the bucket index of the exponential histogram.
+	upperBound := func(index int32) float64 {
+		return math.Exp2(float64(index+1) * math.Exp2(-float64(schema)))
+	}
+
+	buckets := make([]*dto.Bucket, 0, len(negativeCounts)+len(positiveCounts)+1)
+	var cumulative uint64
+	// The negative buckets hold increasingly large absolute values, so they are added in reverse order.
+	for i := len(negativeCounts) - 1; i >= 0; i-- {
+		cumulative += negativeCounts[i]
+		buckets = append(buckets, &dto.Bucket{
+			UpperBound:      proto.Float64(-upperBound(negativeOffset + int32(i) - 1)),
+			CumulativeCount: proto.Uint64(cumulative),
+		})
+	}
+	if zeroCount > 0 || len(negativeCounts) > 0 {
+		cumulative += zeroCount
+		buckets = append(buckets, &dto.Bucket{
+			UpperBound:      proto.Float64(zeroThreshold),
+			CumulativeCount: proto.Uint64(cumulative),
+		})
+	}
+	for i, count := range positiveCounts {
+		cumulative += count
+		buckets = append(buckets, &dto.Bucket{
+			UpperBound:      proto.Float64(upperBound(positiveOffset + int32(i))),
+			CumulativeCount: proto.Uint64(cumulative),
+		})
+	}
+	return buckets
+}
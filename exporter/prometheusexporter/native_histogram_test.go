@@ -0,0 +1,156 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusexporter
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewNativeHistogram(t *testing.T) {
+	desc := prometheus.NewDesc("test_metric", "test description", []string{"label_1"}, prometheus.Labels{"const": "c"})
+
+	dp := pmetric.NewExponentialHistogramDataPoint()
+	dp.SetScale(1)
+	dp.SetCount(10)
+	dp.SetSum(12.5)
+	dp.SetZeroCount(1)
+	// (base^-2, base^-1], and (base^0, base^1] to (base^5, base^6] with a long gap
+	dp.Positive().SetOffset(-2)
+	dp.Positive().BucketCounts().FromRaw([]uint64{2, 0, 1, 0, 0, 0, 0, 3})
+	dp.Negative().SetOffset(0)
+	dp.Negative().BucketCounts().FromRaw([]uint64{1, 2})
+
+	m, err := newNativeHistogram(desc, dp, []string{"1"})
+	require.NoError(t, err)
+	pb := &dto.Metric{}
+	require.NoError(t, m.Write(pb))
+
+	assert.Equal(t, []*dto.LabelPair{
+		{Name: proto.String("const"), Value: proto.String("c")},
+		{Name: proto.String("label_1"), Value: proto.String("1")},
+	}, pb.Label)
+
+	h := pb.Histogram
+	assert.Equal(t, uint64(10), h.GetSampleCount())
+	assert.Equal(t, 12.5, h.GetSampleSum())
+	assert.Equal(t, int32(1), h.GetSchema())
+	assert.Equal(t, uint64(1), h.GetZeroCount())
+
+	// the native histogram bucket indexes are the exponential histogram ones plus one
+	assert.Equal(t, []*dto.BucketSpan{
+		{Offset: proto.Int32(-1), Length: proto.Uint32(3)},
+		{Offset: proto.Int32(4), Length: proto.Uint32(1)},
+	}, h.PositiveSpan)
+	assert.Equal(t, []int64{2, -2, 1, 2}, h.PositiveDelta)
+	assert.Equal(t, []*dto.BucketSpan{{Offset: proto.Int32(1), Length: proto.Uint32(2)}}, h.NegativeSpan)
+	assert.Equal(t, []int64{1, 1}, h.NegativeDelta)
+
+	// the classic buckets are cumulative, from the largest negative values to the largest positive ones
+	base := math.Sqrt2
+	expected := []struct {
+		upperBound float64
+		count      uint64
+	}{
+		{-math.Pow(base, 1), 2},
+		{-1, 3},
+		{0, 4},
+		{math.Pow(base, -1), 6},
+		{1, 6},
+		{base, 7},
+		{math.Pow(base, 2), 7},
+		{math.Pow(base, 3), 7},
+		{math.Pow(base, 4), 7},
+		{math.Pow(base, 5), 7},
+		{math.Pow(base, 6), 10},
+	}
+	require.Len(t, h.Bucket, len(expected))
+	for i, e := range expected {
+		assert.InDelta(t, e.upperBound, h.Bucket[i].GetUpperBound(), 1e-9, "bucket %d", i)
+		assert.Equal(t, e.count, h.Bucket[i].GetCumulativeCount(), "bucket %d", i)
+	}
+}
+
+func TestNewNativeHistogramScales(t *testing.T) {
+	desc := prometheus.NewDesc("test_metric", "", nil, nil)
+
+	t.Run("too fine-grained buckets are merged", func(t *testing.T) {
+		dp := pmetric.NewExponentialHistogramDataPoint()
+		dp.SetScale(10)
+		dp.SetCount(10)
+		dp.Positive().SetOffset(-3)
+		dp.Positive().BucketCounts().FromRaw([]uint64{1, 2, 3, 4})
+
+		m, err := newNativeHistogram(desc, dp, nil)
+		require.NoError(t, err)
+		pb := &dto.Metric{}
+		require.NoError(t, m.Write(pb))
+
+		// indexes -3 to -1 are merged into -1, and 0 into 0
+		assert.Equal(t, int32(nativeHistogramMaxSchema), pb.Histogram.GetSchema())
+		assert.Equal(t, []*dto.BucketSpan{{Offset: proto.Int32(0), Length: proto.Uint32(2)}}, pb.Histogram.PositiveSpan)
+		assert.Equal(t, []int64{6, -2}, pb.Histogram.PositiveDelta)
+	})
+
+	t.Run("too coarse buckets are rejected", func(t *testing.T) {
+		dp := pmetric.NewExponentialHistogramDataPoint()
+		dp.SetScale(-5)
+		_, err := newNativeHistogram(desc, dp, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("empty histogram is native", func(t *testing.T) {
+		m, err := newNativeHistogram(desc, pmetric.NewExponentialHistogramDataPoint(), nil)
+		require.NoError(t, err)
+		pb := &dto.Metric{}
+		require.NoError(t, m.Write(pb))
+		assert.Equal(t, []*dto.BucketSpan{{Offset: proto.Int32(0), Length: proto.Uint32(0)}}, pb.Histogram.PositiveSpan)
+		assert.Empty(t, pb.Histogram.Bucket)
+	})
+}
+
+func TestConvertExponentialHistogram(t *testing.T) {
+	metric := pmetric.NewMetric()
+	metric.SetName("test_metric")
+	metric.SetDescription("test description")
+	h := metric.SetEmptyExponentialHistogram()
+	h.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp := h.DataPoints().AppendEmpty()
+	dp.SetScale(0)
+	dp.SetCount(3)
+	dp.SetSum(6)
+	dp.Positive().BucketCounts().FromRaw([]uint64{1, 2})
+	dp.Attributes().PutStr("label_1", "1")
+	setTestExemplarWithDoubleValue(dp.Exemplars().AppendEmpty(), 3)
+
+	c := collector{
+		accumulator: &mockAccumulator{
+			[]pmetric.Metric{metric},
+			pcommon.NewMap(),
+		},
+		logger: zap.NewNop(),
+	}
+
+	m, err := c.convertMetric(metric, pcommon.NewMap())
+	require.NoError(t, err)
+	pb := &dto.Metric{}
+	require.NoError(t, m.Write(pb))
+
+	assert.Equal(t, int32(0), pb.Histogram.GetSchema())
+	assert.Equal(t, []int64{1, 1}, pb.Histogram.PositiveDelta)
+	// the exemplar is attached to the classic bucket (2, 4]
+	require.Len(t, pb.Histogram.Bucket, 2)
+	assert.Equal(t, 4.0, pb.Histogram.Bucket[1].GetUpperBound())
+	require.NotNil(t, pb.Histogram.Bucket[1].Exemplar)
+	assert.Equal(t, 3.0, pb.Histogram.Bucket[1].Exemplar.GetValue())
+}
@@ -8,10 +8,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
 
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
@@ -519,3 +522,51 @@ func metricBuilder(delta int64, prefix, job, instance string) pmetric.Metrics {
 
 	return md
 }
+
+func TestPrometheusExporter_nativeHistograms(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:0"
+	exp, err := newPrometheusExporter(cfg, exportertest.NewNopSettings())
+	require.NoError(t, err)
+
+	md := pmetric.NewMetrics()
+	metric := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+	metric.SetName("latency")
+	h := metric.SetEmptyExponentialHistogram()
+	h.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp := h.DataPoints().AppendEmpty()
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	dp.SetScale(0)
+	dp.SetCount(3)
+	dp.SetSum(6)
+	dp.Positive().BucketCounts().FromRaw([]uint64{1, 2})
+	require.NoError(t, exp.ConsumeMetrics(context.Background(), md))
+
+	scrape := func(accept string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set("Accept", accept)
+		resp := httptest.NewRecorder()
+		exp.handler.ServeHTTP(resp, req)
+		require.Equal(t, http.StatusOK, resp.Code)
+		return resp
+	}
+
+	// the scrapers negotiating the protobuf format get the native histogram
+	resp := scrape("application/vnd.google.protobuf;proto=io.prometheus.client.MetricFamily;encoding=delimited")
+	dec := expfmt.NewDecoder(resp.Body, expfmt.ResponseFormat(resp.Header()))
+	var mf dto.MetricFamily
+	require.NoError(t, dec.Decode(&mf))
+	assert.Equal(t, "latency", mf.GetName())
+	assert.Equal(t, dto.MetricType_HISTOGRAM, mf.GetType())
+	nh := mf.Metric[0].Histogram
+	assert.Equal(t, int32(0), nh.GetSchema())
+	assert.Equal(t, []int64{1, 1}, nh.PositiveDelta)
+
+	// the text scrapers get the classic buckets
+	resp = scrape("text/plain")
+	body := resp.Body.String()
+	assert.Contains(t, body, `latency_bucket{le="2"} 1`)
+	assert.Contains(t, body, `latency_bucket{le="4"} 3`)
+	assert.Contains(t, body, `latency_bucket{le="+Inf"} 3`)
+	assert.Contains(t, body, `latency_count 3`)
+}
@@ -78,7 +78,8 @@ func TestLoadConfig(t *testing.T) {
 				TargetInfo: &TargetInfo{
 					Enabled: true,
 				},
-				CreatedMetric: &CreatedMetric{Enabled: true},
+				CreatedMetric:   &CreatedMetric{Enabled: true},
+				ProtobufMessage: protobufMessageV1,
 			},
 		},
 		{
@@ -89,6 +90,14 @@ func TestLoadConfig(t *testing.T) {
 			id:           component.NewIDWithName(metadata.Type, "negative_num_consumers"),
 			errorMessage: "remote write consumer number can't be negative",
 		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "unsupported_protobuf_message"),
+			errorMessage: `unsupported protobuf_message "io.prometheus.write.v3.Request", supported: [prometheus.WriteRequest, io.prometheus.write.v2.Request]`,
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "remote_write_v2_wal"),
+			errorMessage: `wal is not supported with protobuf_message "io.prometheus.write.v2.Request"`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -123,6 +132,34 @@ func TestDisabledQueue(t *testing.T) {
 	assert.False(t, cfg.(*Config).RemoteWriteQueue.Enabled)
 }
 
+func TestRemoteWriteV2Config(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "remote_write_v2").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+
+	assert.NoError(t, component.ValidateConfig(cfg))
+	assert.Equal(t, protobufMessageV2, cfg.(*Config).ProtobufMessage)
+}
+
+func TestEmptyProtobufMessageConfig(t *testing.T) {
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+
+	sub, err := cm.Sub(component.NewIDWithName(metadata.Type, "empty_protobuf_message").String())
+	require.NoError(t, err)
+	require.NoError(t, sub.Unmarshal(cfg))
+
+	assert.NoError(t, component.ValidateConfig(cfg))
+	assert.Equal(t, protobufMessageV1, cfg.(*Config).ProtobufMessage)
+}
+
 func TestDisabledTargetInfo(t *testing.T) {
 	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
 	require.NoError(t, err)
@@ -14,6 +14,7 @@ import (
 	"net/url"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/cenkalti/backoff/v4"
 	"github.com/gogo/protobuf/proto"
@@ -33,8 +34,23 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/prometheusremotewriteexporter/internal/metadata"
 	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
 )
 
+// remoteWriteVersion holds the headers identifying a version of the remote write protocol.
+type remoteWriteVersion struct {
+	contentType string
+	version     string
+}
+
+var (
+	remoteWriteV1 = remoteWriteVersion{contentType: "application/x-protobuf", version: "0.1.0"}
+	remoteWriteV2 = remoteWriteVersion{contentType: writev2.ContentType, version: "2.0.0"}
+)
+
+// errRemoteWriteV2Unsupported is returned when the endpoint does not accept remote write 2.0 requests.
+var errRemoteWriteV2Unsupported = errors.New("remote write 2.0 is not supported by the endpoint")
+
 type prwTelemetry interface {
 	recordTranslationFailure(ctx context.Context)
 	recordTranslatedTimeSeries(ctx context.Context, numTS int)
@@ -69,6 +85,11 @@ type prwExporter struct {
 	wal               *prweWAL
 	exporterSettings  prometheusremotewrite.Settings
 	telemetry         prwTelemetry
+
+	// remoteWriteV2 is true if the metrics are sent with remote write 2.0, until the endpoint
+	// rejects it and fallbackToV1 is set.
+	remoteWriteV2 bool
+	fallbackToV1  atomic.Bool
 }
 
 func newPRWTelemetry(set exporter.Settings) (prwTelemetry, error) {
@@ -123,7 +144,8 @@ func newPRWExporter(cfg *Config, set exporter.Settings) (*prwExporter, error) {
 			AddMetricSuffixes:   cfg.AddMetricSuffixes,
 			SendMetadata:        cfg.SendMetadata,
 		},
-		telemetry: prwTelemetry,
+		telemetry:     prwTelemetry,
+		remoteWriteV2: cfg.ProtobufMessage == protobufMessageV2,
 	}
 
 	prwe.wal = newWAL(cfg.WAL, prwe.export)
@@ -170,6 +192,9 @@ func (prwe *prwExporter) PushMetrics(ctx context.Context, md pmetric.Metrics) er
 	case <-prwe.closeChan:
 		return errors.New("shutdown has been called")
 	default:
+		if prwe.remoteWriteV2 && !prwe.fallbackToV1.Load() {
+			return prwe.pushMetricsV2(ctx, md)
+		}
 
 		tsMap, err := prometheusremotewrite.FromMetrics(md, prwe.exporterSettings)
 		if err != nil {
@@ -189,6 +214,22 @@ func (prwe *prwExporter) PushMetrics(ctx context.Context, md pmetric.Metrics) er
 	}
 }
 
+// pushMetricsV2 converts metrics to remote write 2.0 time series, which carry their metadata, and sends them.
+func (prwe *prwExporter) pushMetricsV2(ctx context.Context, md pmetric.Metrics) error {
+	series, err := prometheusremotewrite.FromMetricsV2(md, prwe.exporterSettings)
+	if err != nil {
+		prwe.telemetry.recordTranslationFailure(ctx)
+		prwe.settings.Logger.Debug("failed to translate metrics, exporting remaining metrics", zap.Error(err), zap.Int("translated", len(series)))
+	}
+
+	prwe.telemetry.recordTranslatedTimeSeries(ctx, len(series))
+
+	if len(series) == 0 {
+		return nil
+	}
+	return prwe.exportV2(ctx, batchTimeSeriesV2(series, prwe.maxBatchSizeBytes))
+}
+
 func validateAndSanitizeExternalLabels(cfg *Config) (map[string]string, error) {
 	sanitizedLabels := make(map[string]string)
 	for key, value := range cfg.ExternalLabels {
@@ -227,7 +268,17 @@ func (prwe *prwExporter) handleExport(ctx context.Context, tsMap map[string]*pro
 
 // export sends a Snappy-compressed WriteRequest containing TimeSeries to a remote write endpoint in order
 func (prwe *prwExporter) export(ctx context.Context, requests []*prompb.WriteRequest) error {
-	input := make(chan *prompb.WriteRequest, len(requests))
+	return exportConcurrently(ctx, prwe.concurrency, requests, prwe.execute)
+}
+
+// exportV2 sends each batch of time series in a remote write 2.0 request.
+func (prwe *prwExporter) exportV2(ctx context.Context, batches [][]prometheusremotewrite.TimeSeriesV2) error {
+	return exportConcurrently(ctx, prwe.concurrency, batches, prwe.executeV2)
+}
+
+// exportConcurrently executes the requests with up to concurrency workers.
+func exportConcurrently[T any](ctx context.Context, concurrency int, requests []T, execute func(context.Context, T) error) error {
+	input := make(chan T, len(requests))
 	for _, request := range requests {
 		input <- request
 	}
@@ -235,7 +286,7 @@ func (prwe *prwExporter) export(ctx context.Context, requests []*prompb.WriteReq
 
 	var wg sync.WaitGroup
 
-	concurrencyLimit := int(math.Min(float64(prwe.concurrency), float64(len(requests))))
+	concurrencyLimit := int(math.Min(float64(concurrency), float64(len(requests))))
 	wg.Add(concurrencyLimit) // used to wait for workers to be finished
 
 	var mu sync.Mutex
@@ -254,7 +305,7 @@ func (prwe *prwExporter) export(ctx context.Context, requests []*prompb.WriteReq
 					if !ok {
 						return
 					}
-					if errExecute := prwe.execute(ctx, request); errExecute != nil {
+					if errExecute := execute(ctx, request); errExecute != nil {
 						mu.Lock()
 						errs = multierr.Append(errs, consumererror.NewPermanent(errExecute))
 						mu.Unlock()
@@ -274,6 +325,35 @@ func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 	if errMarshal != nil {
 		return consumererror.NewPermanent(errMarshal)
 	}
+	return prwe.send(ctx, data, remoteWriteV1)
+}
+
+// executeV2 sends the time series in a remote write 2.0 request. If the endpoint rejects it,
+// the time series and all the following ones are sent with remote write 1.0 instead.
+func (prwe *prwExporter) executeV2(ctx context.Context, series []prometheusremotewrite.TimeSeriesV2) error {
+	if !prwe.fallbackToV1.Load() {
+		data, errMarshal := prometheusremotewrite.NewRequestV2(series).Marshal()
+		if errMarshal != nil {
+			return consumererror.NewPermanent(errMarshal)
+		}
+		err := prwe.send(ctx, data, remoteWriteV2)
+		if !errors.Is(err, errRemoteWriteV2Unsupported) {
+			return err
+		}
+		if prwe.fallbackToV1.CompareAndSwap(false, true) {
+			prwe.settings.Logger.Warn("Falling back to remote write 1.0", zap.Error(err))
+		}
+	}
+
+	tsArray := make([]prompb.TimeSeries, len(series))
+	for i := range series {
+		tsArray[i] = series[i].TimeSeries
+	}
+	return prwe.execute(ctx, convertTimeseriesToRequest(tsArray))
+}
+
+// send posts the marshaled request to the endpoint, retrying on recoverable errors.
+func (prwe *prwExporter) send(ctx context.Context, data []byte, rwVersion remoteWriteVersion) error {
 	buf := make([]byte, len(data), cap(data))
 	compressedData := snappy.Encode(buf, data)
 
@@ -297,8 +377,8 @@ func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 		// Add necessary headers specified by:
 		// https://cortexmetrics.io/docs/apis/#remote-api
 		req.Header.Add("Content-Encoding", "snappy")
-		req.Header.Set("Content-Type", "application/x-protobuf")
-		req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+		req.Header.Set("Content-Type", rwVersion.contentType)
+		req.Header.Set("X-Prometheus-Remote-Write-Version", rwVersion.version)
 		req.Header.Set("User-Agent", prwe.userAgentHeader)
 
 		resp, err := prwe.client.Do(req)
@@ -312,11 +392,19 @@ func (prwe *prwExporter) execute(ctx context.Context, writeReq *prompb.WriteRequ
 		// Reference for different behavior according to status code:
 		// https://github.com/prometheus/prometheus/pull/2552/files#diff-ae8db9d16d8057358e49d694522e7186
 		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			// 1.0 endpoints ignore the content type and decode 2.0 requests as empty ones,
+			// while 2.0 endpoints always report the number of samples written.
+			if rwVersion == remoteWriteV2 && resp.Header.Get(writev2.SamplesWrittenHeader) == "" {
+				return backoff.Permanent(fmt.Errorf("%w: the response has no %s header", errRemoteWriteV2Unsupported, writev2.SamplesWrittenHeader))
+			}
 			return nil
 		}
 
 		body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
 		rerr := fmt.Errorf("remote write returned HTTP status %v; err = %w: %s", resp.Status, err, body)
+		if rwVersion == remoteWriteV2 && resp.StatusCode == http.StatusUnsupportedMediaType {
+			return backoff.Permanent(fmt.Errorf("%w: %w", errRemoteWriteV2Unsupported, rerr))
+		}
 		if resp.StatusCode >= 500 && resp.StatusCode < 600 {
 			return rerr
 		}
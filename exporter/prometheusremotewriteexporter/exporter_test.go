@@ -33,6 +33,7 @@ import (
 	"go.opentelemetry.io/otel/sdk/metric/metricdata"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/coreinternal/testdata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
 )
 
 // Test_NewPRWExporter checks that a new exporter instance with non-nil fields is initialized
@@ -1162,3 +1163,105 @@ func TestRetries(t *testing.T) {
 		})
 	}
 }
+
+func TestRemoteWriteV2(t *testing.T) {
+	tests := []struct {
+		name string
+		// respondV2 answers the remote write 2.0 requests
+		respondV2    func(w http.ResponseWriter)
+		expectedV2   int
+		expectedV1   int
+		fallbackToV1 bool
+	}{
+		{
+			name: "endpoint supports 2.0",
+			respondV2: func(w http.ResponseWriter) {
+				w.Header().Set(writev2.SamplesWrittenHeader, "1")
+				w.WriteHeader(http.StatusNoContent)
+			},
+			expectedV2: 2,
+		},
+		{
+			name: "endpoint rejects the content type",
+			respondV2: func(w http.ResponseWriter) {
+				http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+			},
+			expectedV2:   1,
+			expectedV1:   2,
+			fallbackToV1: true,
+		},
+		{
+			name: "endpoint ignores the content type",
+			respondV2: func(w http.ResponseWriter) {
+				w.WriteHeader(http.StatusNoContent)
+			},
+			expectedV2:   1,
+			expectedV1:   2,
+			fallbackToV1: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var v1Requests []prompb.WriteRequest
+			var v2Requests []writev2.Request
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				compressed, err := io.ReadAll(r.Body)
+				assert.NoError(t, err)
+				data, err := snappy.Decode(nil, compressed)
+				assert.NoError(t, err)
+
+				mu.Lock()
+				defer mu.Unlock()
+				switch r.Header.Get("Content-Type") {
+				case writev2.ContentType:
+					assert.Equal(t, "2.0.0", r.Header.Get("X-Prometheus-Remote-Write-Version"))
+					var req writev2.Request
+					assert.NoError(t, req.Unmarshal(data))
+					v2Requests = append(v2Requests, req)
+					tt.respondV2(w)
+				default:
+					assert.Equal(t, "0.1.0", r.Header.Get("X-Prometheus-Remote-Write-Version"))
+					var req prompb.WriteRequest
+					assert.NoError(t, proto.Unmarshal(data, &req))
+					v1Requests = append(v1Requests, req)
+					w.WriteHeader(http.StatusNoContent)
+				}
+			}))
+			defer server.Close()
+
+			cfg := createDefaultConfig().(*Config)
+			cfg.ClientConfig.Endpoint = server.URL
+			cfg.ProtobufMessage = protobufMessageV2
+			cfg.RemoteWriteQueue.NumConsumers = 1
+			cfg.TargetInfo.Enabled = false
+			prwe, err := newPRWExporter(cfg, exportertest.NewNopSettings())
+			require.NoError(t, err)
+			require.NoError(t, prwe.Start(context.Background(), componenttest.NewNopHost()))
+			defer func() { require.NoError(t, prwe.Shutdown(context.Background())) }()
+
+			metric := getIntSumMetric("requests", getAttributes("method", "GET"), 5, uint64(time.Now().UnixNano()))
+			metric.SetDescription("Number of requests")
+			for i := 0; i < 2; i++ {
+				require.NoError(t, prwe.PushMetrics(context.Background(), getMetricsFromMetricList(metric)))
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			assert.Len(t, v2Requests, tt.expectedV2)
+			assert.Len(t, v1Requests, tt.expectedV1)
+			assert.Equal(t, tt.fallbackToV1, prwe.fallbackToV1.Load())
+
+			require.NotEmpty(t, v2Requests)
+			req := v2Requests[0]
+			require.Len(t, req.Timeseries, 1)
+			help, err := req.Symbol(req.Timeseries[0].Metadata.HelpRef)
+			require.NoError(t, err)
+			assert.Equal(t, "Number of requests", help)
+			for _, v1Req := range v1Requests {
+				require.Len(t, v1Req.Timeseries, 1)
+				assert.Len(t, v1Req.Timeseries[0].Samples, 1)
+			}
+		})
+	}
+}
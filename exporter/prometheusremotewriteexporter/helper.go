@@ -8,6 +8,8 @@ import (
 	"sort"
 
 	"github.com/prometheus/prometheus/prompb"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
 )
 
 // batchTimeSeries splits series into multiple batch write requests.
@@ -69,6 +71,32 @@ func batchTimeSeries(tsMap map[string]*prompb.TimeSeries, maxBatchByteSize int,
 	return requests, nil
 }
 
+// batchTimeSeriesV2 splits series into batches sent in separate remote write 2.0 requests.
+// The size of the series in the 1.0 format is used as an upper bound of their size in the request.
+func batchTimeSeriesV2(series []prometheusremotewrite.TimeSeriesV2, maxBatchByteSize int) [][]prometheusremotewrite.TimeSeriesV2 {
+	var batches [][]prometheusremotewrite.TimeSeriesV2
+	start := 0
+	sizeOfCurrentBatch := 0
+	for i := range series {
+		sL := series[i].Samples
+		sort.Slice(sL, func(i, j int) bool {
+			return sL[i].Timestamp < sL[j].Timestamp
+		})
+
+		sizeOfSeries := series[i].Size() + len(series[i].Help) + len(series[i].Unit)
+		if i > start && sizeOfCurrentBatch+sizeOfSeries >= maxBatchByteSize {
+			batches = append(batches, series[start:i])
+			start = i
+			sizeOfCurrentBatch = 0
+		}
+		sizeOfCurrentBatch += sizeOfSeries
+	}
+	if start < len(series) {
+		batches = append(batches, series[start:])
+	}
+	return batches
+}
+
 func convertTimeseriesToRequest(tsArray []prompb.TimeSeries) *prompb.WriteRequest {
 	// the remote_write endpoint only requires the timeseries.
 	// otlp defines it's own way to handle metric metadata
@@ -8,6 +8,8 @@ import (
 
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
 )
 
 // Test_batchTimeSeries checks batchTimeSeries return the correct number of requests
@@ -180,3 +182,23 @@ func TestEnsureTimeseriesPointsAreSortedByTimestamp(t *testing.T) {
 		}
 	}
 }
+
+func Test_batchTimeSeriesV2(t *testing.T) {
+	labels := getPromLabels(label11, value11, label12, value12)
+	series := []prometheusremotewrite.TimeSeriesV2{
+		{TimeSeries: *getTimeSeries(labels, getSample(floatVal2, msTime2), getSample(floatVal1, msTime1))},
+		{TimeSeries: *getTimeSeries(labels, getSample(floatVal3, msTime3)), Help: "help"},
+		{TimeSeries: *getTimeSeries(labels, getSample(floatVal1, msTime1))},
+	}
+
+	batches := batchTimeSeriesV2(series, 1000000)
+	assert.Len(t, batches, 1)
+	// the samples are sorted by timestamp
+	assert.Equal(t, msTime1, batches[0][0].Samples[0].Timestamp)
+
+	// each series exceeds the maximum size, so it is sent on its own
+	batches = batchTimeSeriesV2(series, 10)
+	assert.Len(t, batches, 3)
+
+	assert.Empty(t, batchTimeSeriesV2(nil, 10))
+}
@@ -37,6 +37,11 @@ type fileStorageClient struct {
 	openTimeout     time.Duration
 	cancel          context.CancelFunc
 	closed          bool
+	encryptor       *encryptor
+	// usageMutex serializes the transactions which change the usage of the client
+	usageMutex sync.Mutex
+	usageCfg   *usageConfig
+	usage      usage
 }
 
 func bboltOptions(timeout time.Duration, noSync bool) *bbolt.Options {
@@ -48,24 +53,32 @@ func bboltOptions(timeout time.Duration, noSync bool) *bbolt.Options {
 	}
 }
 
-func newClient(logger *zap.Logger, filePath string, timeout time.Duration, compactionCfg *CompactionConfig, noSync bool) (*fileStorageClient, error) {
+func newClient(logger *zap.Logger, filePath string, timeout time.Duration, compactionCfg *CompactionConfig, noSync bool, encryptor *encryptor, usageCfg *usageConfig) (*fileStorageClient, error) {
 	options := bboltOptions(timeout, noSync)
 	db, err := bbolt.Open(filePath, 0600, options)
 	if err != nil {
 		return nil, err
 	}
 
+	client := &fileStorageClient{logger: logger, db: db, compactionCfg: compactionCfg, openTimeout: timeout, encryptor: encryptor, usageCfg: usageCfg}
 	initBucket := func(tx *bbolt.Tx) error {
-		_, err := tx.CreateBucketIfNotExists(defaultBucket)
-		return err
+		if _, err := tx.CreateBucketIfNotExists(defaultBucket); err != nil {
+			return err
+		}
+		if err := initEncryption(tx, encryptor); err != nil {
+			return err
+		}
+		return client.initUsage(tx, time.Now())
 	}
 	if err := db.Update(initBucket); err != nil {
 		_ = db.Close()
 		return nil, err
 	}
+	if usageCfg != nil {
+		client.record(client.usage)
+	}
 
-	client := &fileStorageClient{logger: logger, db: db, compactionCfg: compactionCfg, openTimeout: timeout}
-	if compactionCfg.OnRebound {
+	if compactionCfg.OnRebound || (usageCfg != nil && usageCfg.ttl > 0) {
 		client.startCompactionLoop(context.Background())
 	}
 
@@ -95,6 +108,8 @@ func (c *fileStorageClient) Delete(ctx context.Context, key string) error {
 
 // Batch executes the specified operations in order. Get operation results are updated in place
 func (c *fileStorageClient) Batch(_ context.Context, ops ...storage.Operation) error {
+	now := time.Now()
+	var delta usage
 	batch := func(tx *bbolt.Tx) error {
 		bucket := tx.Bucket(defaultBucket)
 		if bucket == nil {
@@ -102,21 +117,40 @@ func (c *fileStorageClient) Batch(_ context.Context, ops ...storage.Operation) e
 		}
 
 		var err error
+		written := make(map[string]struct{})
 		for _, op := range ops {
 			switch op.Type {
 			case storage.Get:
 				value := bucket.Get([]byte(op.Key))
-				if value != nil {
+				switch {
+				case value == nil, c.expired(tx, []byte(op.Key), now):
+					op.Value = nil
+				case c.encryptor != nil:
+					// decrypting also copies the value
+					op.Value, err = c.encryptor.decrypt([]byte(op.Key), value)
+				default:
 					// the output of Bucket.Get is only valid within a transaction, so we need to make a copy
 					// to be able to return the value
 					op.Value = make([]byte, len(value))
 					copy(op.Value, value)
-				} else {
-					op.Value = nil
 				}
 			case storage.Set:
-				err = bucket.Put([]byte(op.Key), op.Value)
+				value := op.Value
+				if c.encryptor != nil {
+					if value, err = c.encryptor.encrypt([]byte(op.Key), op.Value); err != nil {
+						return err
+					}
+				}
+				if err = c.trackSet(tx, []byte(op.Key), value, &delta, now); err != nil {
+					return err
+				}
+				written[op.Key] = struct{}{}
+				err = bucket.Put([]byte(op.Key), value)
 			case storage.Delete:
+				if err = c.trackDelete(tx, []byte(op.Key), &delta); err != nil {
+					return err
+				}
+				delete(written, op.Key)
 				err = bucket.Delete([]byte(op.Key))
 			default:
 				return errors.New("wrong operation type")
@@ -127,12 +161,21 @@ func (c *fileStorageClient) Batch(_ context.Context, ops ...storage.Operation) e
 			}
 		}
 
-		return nil
+		return c.enforceQuota(tx, written, &delta)
 	}
 
 	c.compactionMutex.RLock()
 	defer c.compactionMutex.RUnlock()
-	return c.db.Update(batch)
+	c.usageMutex.Lock()
+	defer c.usageMutex.Unlock()
+	if err := c.db.Update(batch); err != nil {
+		if delta.rejected {
+			c.record(usage{rejected: true})
+		}
+		return err
+	}
+	c.applyUsage(delta)
+	return nil
 }
 
 // Close will close the database
@@ -143,6 +186,12 @@ func (c *fileStorageClient) Close(_ context.Context) error {
 	if c.cancel != nil {
 		c.cancel()
 	}
+	if c.usageCfg != nil && !c.closed {
+		c.usageMutex.Lock()
+		// the usage of the client is no longer reported
+		c.record(usage{size: -c.usage.size, entries: -c.usage.entries})
+		c.usageMutex.Unlock()
+	}
 	c.closed = true
 	return c.db.Close()
 }
@@ -183,6 +232,11 @@ func (c *fileStorageClient) Compact(compactionDirectory string, timeout time.Dur
 		return nil
 	}
 
+	// expired keys are evicted first, so that the space they used is reclaimed
+	if err = c.expire(time.Now()); err != nil {
+		c.logger.Error("evicting expired keys before compaction failed", zap.Error(err))
+	}
+
 	c.logger.Debug("starting compaction",
 		zap.String(directoryKey, c.db.Path()),
 		zap.String(tempDirectoryKey, file.Name()))
@@ -199,6 +253,14 @@ func (c *fileStorageClient) Compact(compactionDirectory string, timeout time.Dur
 		return err
 	}
 
+	// values written before the key was rotated are encrypted with the current key
+	if c.encryptor != nil {
+		if err = c.encryptor.reencrypt(compactedDb, maxTransactionSize); err != nil {
+			compactedDb.Close()
+			return fmt.Errorf("failed to encrypt values during compaction: %w", err)
+		}
+	}
+
 	dbPath := c.db.Path()
 	compactedDbPath := compactedDb.Path()
 
@@ -253,6 +315,7 @@ func (c *fileStorageClient) startCompactionLoop(ctx context.Context) {
 		for {
 			select {
 			case <-compactionTicker.C:
+				c.expireLoop()
 				if c.shouldCompact() {
 					err := c.Compact(c.compactionCfg.Directory, c.openTimeout, c.compactionCfg.MaxTransactionSize)
 					if err != nil {
@@ -269,6 +332,18 @@ func (c *fileStorageClient) startCompactionLoop(ctx context.Context) {
 	}()
 }
 
+// expireLoop evicts the expired keys, unless the database is being compacted or is closed
+func (c *fileStorageClient) expireLoop() {
+	c.compactionMutex.RLock()
+	defer c.compactionMutex.RUnlock()
+	if c.closed {
+		return
+	}
+	if err := c.expire(time.Now()); err != nil {
+		c.logger.Error("evicting expired keys failed", zap.Error(err))
+	}
+}
+
 // shouldCompact checks whether the conditions for online compaction are met
 func (c *fileStorageClient) shouldCompact() bool {
 	if !c.compactionCfg.OnRebound {
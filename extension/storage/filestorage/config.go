@@ -20,6 +20,48 @@ type Config struct {
 
 	// FSync specifies that fsync should be called after each database write
 	FSync bool `mapstructure:"fsync,omitempty"`
+
+	// Encryption specifies that the stored values are encrypted at rest
+	Encryption *EncryptionConfig `mapstructure:"encryption,omitempty"`
+
+	// Quota limits the size of the keys and values stored by each client
+	Quota *QuotaConfig `mapstructure:"quota,omitempty"`
+
+	// TTL specifies that keys which were not written for this duration are evicted
+	TTL time.Duration `mapstructure:"ttl,omitempty"`
+}
+
+const (
+	// QuotaReject rejects the writes which would exceed the quota
+	QuotaReject = "reject"
+	// QuotaEvictOldest evicts the least recently written keys until the writes fit in the quota
+	QuotaEvictOldest = "evict_oldest"
+)
+
+// QuotaConfig defines the size limit of each client.
+type QuotaConfig struct {
+	// MaxSizeMiB is the maximum size of the keys and values stored by a client
+	MaxSizeMiB int64 `mapstructure:"max_size_mib"`
+	// OnExceeded specifies what happens when a write would exceed the quota, either reject or evict_oldest
+	OnExceeded string `mapstructure:"on_exceeded,omitempty"`
+}
+
+// EncryptionConfig defines configuration for the encryption of the stored values with AES-GCM.
+type EncryptionConfig struct {
+	// Key is used to encrypt the values, and to decrypt the values it encrypted
+	Key KeyConfig `mapstructure:"key"`
+	// PreviousKeys are only used to decrypt the values encrypted before the key was rotated.
+	// These values are encrypted again with Key when the database is compacted.
+	PreviousKeys []KeyConfig `mapstructure:"previous_keys,omitempty"`
+}
+
+// KeyConfig defines where a base64 encoded AES key of 16, 24 or 32 bytes is read from.
+// Exactly one of File and Env must be set.
+type KeyConfig struct {
+	// File is the path of a file containing the key
+	File string `mapstructure:"file,omitempty"`
+	// Env is the name of an environment variable containing the key
+	Env string `mapstructure:"env,omitempty"`
 }
 
 // CompactionConfig defines configuration for optional file storage compaction.
@@ -83,5 +125,40 @@ func (cfg *Config) Validate() error {
 		return errors.New("compaction check interval must be positive when rebound compaction is set")
 	}
 
+	if cfg.Quota != nil {
+		if cfg.Quota.MaxSizeMiB <= 0 {
+			return errors.New("quota max size must be positive")
+		}
+		if cfg.Quota.OnExceeded != "" && cfg.Quota.OnExceeded != QuotaReject && cfg.Quota.OnExceeded != QuotaEvictOldest {
+			return fmt.Errorf("quota on_exceeded must be %q or %q", QuotaReject, QuotaEvictOldest)
+		}
+	}
+
+	if cfg.TTL < 0 {
+		return errors.New("ttl cannot be negative")
+	}
+
+	if cfg.TTL > 0 && cfg.Compaction.CheckInterval <= 0 {
+		return errors.New("compaction check interval must be positive when ttl is set")
+	}
+
+	if cfg.Encryption != nil {
+		if err := cfg.Encryption.Key.validate(); err != nil {
+			return fmt.Errorf("encryption key: %w", err)
+		}
+		for i, key := range cfg.Encryption.PreviousKeys {
+			if err := key.validate(); err != nil {
+				return fmt.Errorf("previous encryption key %d: %w", i, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (cfg KeyConfig) validate() error {
+	if (cfg.File == "") == (cfg.Env == "") {
+		return errors.New("exactly one of file or env must be set")
+	}
 	return nil
 }
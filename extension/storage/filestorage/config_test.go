@@ -51,6 +51,28 @@ func TestLoadConfig(t *testing.T) {
 				FSync:   true,
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "encryption"),
+			expected: func() component.Config {
+				ret := NewFactory().CreateDefaultConfig().(*Config)
+				ret.Directory = "."
+				ret.Encryption = &EncryptionConfig{
+					Key:          KeyConfig{Env: "FILE_STORAGE_KEY"},
+					PreviousKeys: []KeyConfig{{File: "/etc/otelcol/previous.key"}},
+				}
+				return ret
+			}(),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "quota"),
+			expected: func() component.Config {
+				ret := NewFactory().CreateDefaultConfig().(*Config)
+				ret.Directory = "."
+				ret.Quota = &QuotaConfig{MaxSizeMiB: 512, OnExceeded: QuotaEvictOldest}
+				ret.TTL = 168 * time.Hour
+				return ret
+			}(),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.id.String(), func(t *testing.T) {
@@ -95,3 +117,86 @@ func TestHandleProvidingFilePathAsDirWithAnError(t *testing.T) {
 	require.Error(t, err)
 	require.EqualError(t, err, file.Name()+" is not a directory")
 }
+
+func TestHandleInvalidEncryptionKeys(t *testing.T) {
+	tests := []struct {
+		name       string
+		encryption *EncryptionConfig
+		expected   string
+	}{
+		{
+			name:       "no key",
+			encryption: &EncryptionConfig{},
+			expected:   "encryption key: exactly one of file or env must be set",
+		},
+		{
+			name: "file and env",
+			encryption: &EncryptionConfig{
+				Key: KeyConfig{File: "key", Env: "KEY"},
+			},
+			expected: "encryption key: exactly one of file or env must be set",
+		},
+		{
+			name: "previous key",
+			encryption: &EncryptionConfig{
+				Key:          KeyConfig{Env: "KEY"},
+				PreviousKeys: []KeyConfig{{}},
+			},
+			expected: "previous encryption key 0: exactly one of file or env must be set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig().(*Config)
+			cfg.Directory = t.TempDir()
+			cfg.Encryption = tt.encryption
+			require.EqualError(t, component.ValidateConfig(cfg), tt.expected)
+		})
+	}
+}
+
+func TestHandleInvalidQuotaAndTTL(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(cfg *Config)
+		expected string
+	}{
+		{
+			name: "no max size",
+			modify: func(cfg *Config) {
+				cfg.Quota = &QuotaConfig{}
+			},
+			expected: "quota max size must be positive",
+		},
+		{
+			name: "unknown behavior",
+			modify: func(cfg *Config) {
+				cfg.Quota = &QuotaConfig{MaxSizeMiB: 1, OnExceeded: "drop"}
+			},
+			expected: `quota on_exceeded must be "reject" or "evict_oldest"`,
+		},
+		{
+			name: "negative ttl",
+			modify: func(cfg *Config) {
+				cfg.TTL = -time.Second
+			},
+			expected: "ttl cannot be negative",
+		},
+		{
+			name: "ttl without check interval",
+			modify: func(cfg *Config) {
+				cfg.TTL = time.Hour
+				cfg.Compaction.CheckInterval = 0
+			},
+			expected: "compaction check interval must be positive when ttl is set",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewFactory().CreateDefaultConfig().(*Config)
+			cfg.Directory = t.TempDir()
+			tt.modify(cfg)
+			require.EqualError(t, component.ValidateConfig(cfg), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorage // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/filestorage"
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.etcd.io/bbolt"
+)
+
+const (
+	encryptionVersion = 1
+	keyIDSize         = 4
+)
+
+var (
+	// encryptionBucket records that the values are encrypted: once it exists, every value of the
+	// default bucket is encrypted.
+	encryptionBucket     = []byte(`encryption`)
+	encryptionVersionKey = []byte(`version`)
+)
+
+// encryptedPrefix starts the encrypted values. The values being opaque, unencrypted values may start
+// with it too, so it's only checked to reject the values which were not written encrypted, whether
+// a value is encrypted being recorded by the encryption bucket.
+var encryptedPrefix = []byte("\x00enc")
+
+// encryptor encrypts values with AES-GCM, using the key of the value as additional data so that
+// encrypted values cannot be swapped between keys.
+type encryptor struct {
+	currentID uint32
+	aeads     map[uint32]cipher.AEAD
+}
+
+func newEncryptor(cfg *EncryptionConfig) (*encryptor, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	e := &encryptor{aeads: make(map[uint32]cipher.AEAD)}
+	var err error
+	if e.currentID, err = e.addKey(cfg.Key); err != nil {
+		return nil, fmt.Errorf("encryption key: %w", err)
+	}
+	for i, key := range cfg.PreviousKeys {
+		if _, err = e.addKey(key); err != nil {
+			return nil, fmt.Errorf("previous encryption key %d: %w", i, err)
+		}
+	}
+	return e, nil
+}
+
+func (e *encryptor) addKey(cfg KeyConfig) (uint32, error) {
+	key, err := cfg.load()
+	if err != nil {
+		return 0, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return 0, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return 0, err
+	}
+	sum := sha256.Sum256(key)
+	id := binary.BigEndian.Uint32(sum[:keyIDSize])
+	e.aeads[id] = aead
+	return id, nil
+}
+
+// load reads the base64 encoded key from its file or environment variable.
+func (cfg KeyConfig) load() ([]byte, error) {
+	var encoded string
+	if cfg.File != "" {
+		content, err := os.ReadFile(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+		encoded = string(content)
+	} else {
+		var ok bool
+		if encoded, ok = os.LookupEnv(cfg.Env); !ok {
+			return nil, fmt.Errorf("environment variable %q is not set", cfg.Env)
+		}
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("decoding key: %w", err)
+	}
+	return key, nil
+}
+
+// encrypt seals the value with the current key. The result consists of the prefix, the version,
+// the identifier of the key, the nonce and the sealed value.
+func (e *encryptor) encrypt(key []byte, value []byte) ([]byte, error) {
+	aead := e.aeads[e.currentID]
+	headerSize := len(encryptedPrefix) + 1 + keyIDSize
+	out := make([]byte, headerSize+aead.NonceSize(), headerSize+aead.NonceSize()+len(value)+aead.Overhead())
+	copy(out, encryptedPrefix)
+	out[len(encryptedPrefix)] = encryptionVersion
+	binary.BigEndian.PutUint32(out[len(encryptedPrefix)+1:], e.currentID)
+	if _, err := rand.Read(out[headerSize:]); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+	return aead.Seal(out, out[headerSize:], value, key), nil
+}
+
+// decrypt opens a value encrypted with any of the keys.
+func (e *encryptor) decrypt(key []byte, value []byte) ([]byte, error) {
+	if !bytes.HasPrefix(value, encryptedPrefix) {
+		return nil, fmt.Errorf("value of %q is not encrypted", key)
+	}
+	id, aead, err := e.header(value)
+	if err != nil {
+		return nil, err
+	}
+	if aead == nil {
+		return nil, fmt.Errorf("value of %q is encrypted with an unknown key %08x", key, id)
+	}
+	nonceStart := len(encryptedPrefix) + 1 + keyIDSize
+	if len(value) < nonceStart+aead.NonceSize()+aead.Overhead() {
+		return nil, fmt.Errorf("value of %q is too short to be encrypted", key)
+	}
+	nonce := value[nonceStart : nonceStart+aead.NonceSize()]
+	plain, err := aead.Open(nil, nonce, value[nonceStart+aead.NonceSize():], key)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting value of %q: %w", key, err)
+	}
+	return plain, nil
+}
+
+func (e *encryptor) header(value []byte) (uint32, cipher.AEAD, error) {
+	if len(value) < len(encryptedPrefix)+1+keyIDSize {
+		return 0, nil, errors.New("encrypted value is too short")
+	}
+	if version := value[len(encryptedPrefix)]; version != encryptionVersion {
+		return 0, nil, fmt.Errorf("unsupported encryption version %d", version)
+	}
+	id := binary.BigEndian.Uint32(value[len(encryptedPrefix)+1:])
+	return id, e.aeads[id], nil
+}
+
+// isCurrent returns true if the value is encrypted with the current key.
+func (e *encryptor) isCurrent(value []byte) bool {
+	if !bytes.HasPrefix(value, encryptedPrefix) {
+		return false
+	}
+	id, _, err := e.header(value)
+	return err == nil && id == e.currentID
+}
+
+// initEncryption encrypts the values written before encryption was enabled, and records that the
+// values are encrypted. The values of an encrypted database cannot be read without encryption.
+func initEncryption(tx *bbolt.Tx, e *encryptor) error {
+	encrypted := tx.Bucket(encryptionBucket) != nil
+	switch {
+	case e == nil && encrypted:
+		return errors.New("the values are encrypted, but encryption is not configured")
+	case e == nil || encrypted:
+		return nil
+	}
+
+	bucket := tx.Bucket(defaultBucket)
+	type update struct{ key, value []byte }
+	var updates []update
+	err := bucket.ForEach(func(k, v []byte) error {
+		encryptedValue, err := e.encrypt(k, v)
+		if err != nil {
+			return err
+		}
+		updates = append(updates, update{key: bytes.Clone(k), value: encryptedValue})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, u := range updates {
+		if err = bucket.Put(u.key, u.value); err != nil {
+			return err
+		}
+	}
+
+	marker, err := tx.CreateBucket(encryptionBucket)
+	if err != nil {
+		return err
+	}
+	return marker.Put(encryptionVersionKey, []byte{encryptionVersion})
+}
+
+// reencrypt encrypts the values which are encrypted with a previous key with the current key. Values
+// are processed in transactions of at most maxTransactionSize values, or in a single one if it is zero.
+func (e *encryptor) reencrypt(db *bbolt.DB, maxTransactionSize int64) error {
+	next := []byte{}
+	for next != nil {
+		err := db.Update(func(tx *bbolt.Tx) error {
+			bucket := tx.Bucket(defaultBucket)
+			if bucket == nil {
+				next = nil
+				return nil
+			}
+
+			type update struct{ key, value []byte }
+			var updates []update
+			var processed int64
+			cursor := bucket.Cursor()
+			k, v := cursor.Seek(next)
+			for ; k != nil; k, v = cursor.Next() {
+				if maxTransactionSize > 0 && processed >= maxTransactionSize {
+					break
+				}
+				processed++
+				if e.isCurrent(v) {
+					continue
+				}
+				plain, err := e.decrypt(k, v)
+				if err != nil {
+					return err
+				}
+				encrypted, err := e.encrypt(k, plain)
+				if err != nil {
+					return err
+				}
+				updates = append(updates, update{key: bytes.Clone(k), value: encrypted})
+			}
+			// the key returned by the cursor is only valid within the transaction
+			next = bytes.Clone(k)
+
+			for _, u := range updates {
+				if err := bucket.Put(u.key, u.value); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
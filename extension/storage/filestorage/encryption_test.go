@@ -0,0 +1,200 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorage
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+	"go.uber.org/zap"
+)
+
+func newTestKey(t *testing.T, size int) string {
+	key := make([]byte, size)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+func newTestKeyFile(t *testing.T) KeyConfig {
+	file := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(file, []byte(newTestKey(t, 32)+"\n"), 0600))
+	return KeyConfig{File: file}
+}
+
+func TestNewEncryptor(t *testing.T) {
+	t.Setenv("FILE_STORAGE_TEST_KEY", newTestKey(t, 16))
+	t.Setenv("FILE_STORAGE_TEST_SHORT_KEY", newTestKey(t, 10))
+	t.Setenv("FILE_STORAGE_TEST_INVALID_KEY", "not base64!")
+
+	tests := []struct {
+		name     string
+		cfg      *EncryptionConfig
+		expected string
+	}{
+		{
+			name: "env",
+			cfg:  &EncryptionConfig{Key: KeyConfig{Env: "FILE_STORAGE_TEST_KEY"}},
+		},
+		{
+			name: "file with previous keys",
+			cfg: &EncryptionConfig{
+				Key:          newTestKeyFile(t),
+				PreviousKeys: []KeyConfig{{Env: "FILE_STORAGE_TEST_KEY"}},
+			},
+		},
+		{
+			name:     "unset env",
+			cfg:      &EncryptionConfig{Key: KeyConfig{Env: "FILE_STORAGE_TEST_UNSET_KEY"}},
+			expected: `encryption key: environment variable "FILE_STORAGE_TEST_UNSET_KEY" is not set`,
+		},
+		{
+			name:     "invalid size",
+			cfg:      &EncryptionConfig{Key: KeyConfig{Env: "FILE_STORAGE_TEST_SHORT_KEY"}},
+			expected: "encryption key: crypto/aes: invalid key size 10",
+		},
+		{
+			name: "invalid encoding",
+			cfg: &EncryptionConfig{
+				Key:          KeyConfig{Env: "FILE_STORAGE_TEST_KEY"},
+				PreviousKeys: []KeyConfig{{Env: "FILE_STORAGE_TEST_INVALID_KEY"}},
+			},
+			expected: "previous encryption key 0: decoding key: illegal base64 data at input byte 3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := newEncryptor(tt.cfg)
+			if tt.expected != "" {
+				require.EqualError(t, err, tt.expected)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, e.aeads, 1+len(tt.cfg.PreviousKeys))
+		})
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	previousKey := newTestKeyFile(t)
+	previous, err := newEncryptor(&EncryptionConfig{Key: previousKey})
+	require.NoError(t, err)
+	e, err := newEncryptor(&EncryptionConfig{Key: newTestKeyFile(t), PreviousKeys: []KeyConfig{previousKey}})
+	require.NoError(t, err)
+
+	key := []byte("key")
+	value := []byte("value")
+
+	encrypted, err := e.encrypt(key, value)
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(encrypted, value))
+	assert.True(t, e.isCurrent(encrypted))
+	decrypted, err := e.decrypt(key, encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, value, decrypted)
+
+	// values encrypted with a previous key can still be decrypted
+	encryptedWithPrevious, err := previous.encrypt(key, value)
+	require.NoError(t, err)
+	assert.False(t, e.isCurrent(encryptedWithPrevious))
+	decrypted, err = e.decrypt(key, encryptedWithPrevious)
+	require.NoError(t, err)
+	assert.Equal(t, value, decrypted)
+
+	// but not the other way around
+	_, err = previous.decrypt(key, encrypted)
+	assert.ErrorContains(t, err, "encrypted with an unknown key")
+
+	// unencrypted values are rejected
+	_, err = e.decrypt(key, value)
+	assert.ErrorContains(t, err, `value of "key" is not encrypted`)
+	assert.False(t, e.isCurrent(value))
+
+	// values cannot be moved to another key
+	_, err = e.decrypt([]byte("other"), encrypted)
+	assert.ErrorContains(t, err, `decrypting value of "other"`)
+
+	// tampered values are detected
+	tampered := bytes.Clone(encrypted)
+	tampered[len(tampered)-1] ^= 1
+	_, err = e.decrypt(key, tampered)
+	assert.ErrorContains(t, err, "message authentication failed")
+}
+
+func TestClientEncryption(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "my_db")
+	ctx := context.Background()
+
+	// write a database before encryption is enabled, with a value starting like the encrypted values
+	client, err := newClient(zap.NewNop(), dbFile, time.Second, &CompactionConfig{}, false, nil, nil)
+	require.NoError(t, err)
+	require.NoError(t, client.Set(ctx, "plain", []byte("plain value")))
+	require.NoError(t, client.Set(ctx, "prefixed", append(bytes.Clone(encryptedPrefix), "prefixed value"...)))
+	require.NoError(t, client.Close(ctx))
+
+	oldKey := newTestKeyFile(t)
+	old, err := newEncryptor(&EncryptionConfig{Key: oldKey})
+	require.NoError(t, err)
+	client, err = newClient(zap.NewNop(), dbFile, time.Second, &CompactionConfig{}, false, old, nil)
+	require.NoError(t, err)
+	value, err := client.Get(ctx, "plain")
+	require.NoError(t, err)
+	require.Equal(t, []byte("plain value"), value)
+	value, err = client.Get(ctx, "prefixed")
+	require.NoError(t, err)
+	require.Equal(t, append(bytes.Clone(encryptedPrefix), "prefixed value"...), value)
+	require.NoError(t, client.Set(ctx, "old", []byte("old value")))
+	require.NoError(t, client.Close(ctx))
+	// the values written before encryption was enabled are encrypted when opening the database
+	require.Equal(t, map[string]bool{"plain": true, "prefixed": true, "old": true}, rawValuesEncryptedWith(t, dbFile, old))
+
+	// rotate the key, values are encrypted with it when compacting
+	current, err := newEncryptor(&EncryptionConfig{Key: newTestKeyFile(t), PreviousKeys: []KeyConfig{oldKey}})
+	require.NoError(t, err)
+	client, err = newClient(zap.NewNop(), dbFile, time.Second, &CompactionConfig{}, false, current, nil)
+	require.NoError(t, err)
+	require.NoError(t, client.Set(ctx, "current", []byte("current value")))
+	require.NoError(t, client.Compact(t.TempDir(), time.Second, 1))
+	for key, expected := range map[string]string{"plain": "plain value", "old": "old value", "current": "current value"} {
+		value, err = client.Get(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, []byte(expected), value)
+	}
+	require.NoError(t, client.Close(ctx))
+	require.Equal(t, map[string]bool{"plain": true, "prefixed": true, "old": true, "current": true}, rawValuesEncryptedWith(t, dbFile, current))
+
+	// the encrypted database cannot be opened without encryption
+	_, err = newClient(zap.NewNop(), dbFile, time.Second, &CompactionConfig{}, false, nil, nil)
+	assert.ErrorContains(t, err, "the values are encrypted, but encryption is not configured")
+}
+
+// rawValuesEncryptedWith returns whether each value of the database is encrypted with the current key,
+// and checks that the encrypted values are not stored in clear text.
+func rawValuesEncryptedWith(t *testing.T, dbFile string, e *encryptor) map[string]bool {
+	db, err := bbolt.Open(dbFile, 0600, nil)
+	require.NoError(t, err)
+	defer db.Close()
+
+	result := map[string]bool{}
+	require.NoError(t, db.View(func(tx *bbolt.Tx) error {
+		return tx.Bucket(defaultBucket).ForEach(func(k, v []byte) error {
+			result[string(k)] = e.isCurrent(v)
+			if e.isCurrent(v) && bytes.Contains(v, []byte("value")) {
+				return fmt.Errorf("value of %q is stored in clear text", k)
+			}
+			return nil
+		})
+	}))
+	return result
+}
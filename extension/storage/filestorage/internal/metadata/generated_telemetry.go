@@ -3,9 +3,14 @@
 package metadata
 
 import (
-	"go.opentelemetry.io/collector/component"
+	"errors"
+
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
 	"go.opentelemetry.io/otel/trace"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configtelemetry"
 )
 
 func Meter(settings component.TelemetrySettings) metric.Meter {
@@ -15,3 +20,64 @@ func Meter(settings component.TelemetrySettings) metric.Meter {
 func Tracer(settings component.TelemetrySettings) trace.Tracer {
 	return settings.TracerProvider.Tracer("otelcol/filestorage")
 }
+
+// TelemetryBuilder provides an interface for components to report telemetry
+// as defined in metadata and user config.
+type TelemetryBuilder struct {
+	meter                           metric.Meter
+	FilestorageClientEntries        metric.Int64UpDownCounter
+	FilestorageClientEvictions      metric.Int64Counter
+	FilestorageClientRejectedWrites metric.Int64Counter
+	FilestorageClientSize           metric.Int64UpDownCounter
+	level                           configtelemetry.Level
+}
+
+// telemetryBuilderOption applies changes to default builder.
+type telemetryBuilderOption func(*TelemetryBuilder)
+
+// WithLevel sets the current telemetry level for the component.
+func WithLevel(lvl configtelemetry.Level) telemetryBuilderOption {
+	return func(builder *TelemetryBuilder) {
+		builder.level = lvl
+	}
+}
+
+// NewTelemetryBuilder provides a struct with methods to update all internal telemetry
+// for a component
+func NewTelemetryBuilder(settings component.TelemetrySettings, options ...telemetryBuilderOption) (*TelemetryBuilder, error) {
+	builder := TelemetryBuilder{level: configtelemetry.LevelBasic}
+	for _, op := range options {
+		op(&builder)
+	}
+	var err, errs error
+	if builder.level >= configtelemetry.LevelBasic {
+		builder.meter = Meter(settings)
+	} else {
+		builder.meter = noop.Meter{}
+	}
+	builder.FilestorageClientEntries, err = builder.meter.Int64UpDownCounter(
+		"filestorage_client_entries",
+		metric.WithDescription("Number of entries stored by a client"),
+		metric.WithUnit("1"),
+	)
+	errs = errors.Join(errs, err)
+	builder.FilestorageClientEvictions, err = builder.meter.Int64Counter(
+		"filestorage_client_evictions",
+		metric.WithDescription("Number of entries evicted from a client because its quota was exceeded or their TTL expired"),
+		metric.WithUnit("1"),
+	)
+	errs = errors.Join(errs, err)
+	builder.FilestorageClientRejectedWrites, err = builder.meter.Int64Counter(
+		"filestorage_client_rejected_writes",
+		metric.WithDescription("Number of writes rejected because they would exceed the quota of a client"),
+		metric.WithUnit("1"),
+	)
+	errs = errors.Join(errs, err)
+	builder.FilestorageClientSize, err = builder.meter.Int64UpDownCounter(
+		"filestorage_client_size",
+		metric.WithDescription("Size of the keys and values stored by a client"),
+		metric.WithUnit("By"),
+	)
+	errs = errors.Join(errs, err)
+	return &builder, errs
+}
@@ -0,0 +1,335 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorage // import "github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/filestorage"
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.etcd.io/bbolt"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage/filestorage/internal/metadata"
+)
+
+const (
+	clientKey = "client"
+	reasonKey = "reason"
+
+	evictionReasonQuota = "quota"
+	evictionReasonTTL   = "ttl"
+)
+
+var (
+	// writeOrderBucket maps a sequence number, increasing with each write, to the time of the write followed by the key.
+	writeOrderBucket = []byte(`write_order`)
+	// writeSequenceBucket maps each key to the sequence number of its last write.
+	writeSequenceBucket = []byte(`write_sequence`)
+
+	errQuotaExceeded = errors.New("storage quota exceeded")
+
+	// queueMetadataKeys are the keys where the persistent queue of the exporters keeps its read and write indexes
+	// and its dispatched items. Evicting them would corrupt the queue, so they're never indexed, and never evicted.
+	queueMetadataKeys = map[string]bool{"ri": true, "wi": true, "di": true}
+)
+
+// usageConfig defines how the usage of a client is reported and limited.
+type usageConfig struct {
+	// name identifies the client in its telemetry
+	name      string
+	quota     *QuotaConfig
+	ttl       time.Duration
+	telemetry *metadata.TelemetryBuilder
+}
+
+// indexed returns true if the order of the writes must be tracked, to find the oldest or expired keys.
+func (u *usageConfig) indexed() bool {
+	return u.ttl > 0 || (u.quota != nil && u.quota.OnExceeded == QuotaEvictOldest)
+}
+
+// usage is the size and number of entries stored by a client, and the changes made by a transaction.
+type usage struct {
+	size     int64
+	entries  int64
+	evicted  map[string]int64
+	rejected bool
+}
+
+func (u *usage) add(key []byte, value []byte, sign int64) {
+	u.size += sign * int64(len(key)+len(value))
+	u.entries += sign
+}
+
+func (u *usage) evict(reason string) {
+	if u.evicted == nil {
+		u.evicted = make(map[string]int64)
+	}
+	u.evicted[reason]++
+}
+
+// initUsage measures the usage of the client, and prepares or removes the index of the writes.
+// The keys which are not indexed yet are indexed as if they were written now.
+func (c *fileStorageClient) initUsage(tx *bbolt.Tx, now time.Time) error {
+	if c.usageCfg == nil {
+		return nil
+	}
+	bucket := tx.Bucket(defaultBucket)
+	if !c.usageCfg.indexed() {
+		// drop the index, which is no longer maintained and would be stale if it was enabled again
+		for _, name := range [][]byte{writeOrderBucket, writeSequenceBucket} {
+			if err := tx.DeleteBucket(name); err != nil && !errors.Is(err, bbolt.ErrBucketNotFound) {
+				return err
+			}
+		}
+		return bucket.ForEach(func(k, v []byte) error {
+			c.usage.add(k, v, 1)
+			return nil
+		})
+	}
+
+	order, err := tx.CreateBucketIfNotExists(writeOrderBucket)
+	if err != nil {
+		return err
+	}
+	sequences, err := tx.CreateBucketIfNotExists(writeSequenceBucket)
+	if err != nil {
+		return err
+	}
+	var missing [][]byte
+	err = bucket.ForEach(func(k, v []byte) error {
+		c.usage.add(k, v, 1)
+		if sequences.Get(k) == nil {
+			missing = append(missing, bytes.Clone(k))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, k := range missing {
+		if err = indexWrite(order, sequences, k, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexWrite records that the key was written at the given time.
+func indexWrite(order *bbolt.Bucket, sequences *bbolt.Bucket, key []byte, now time.Time) error {
+	if err := unindexWrite(order, sequences, key); err != nil || queueMetadataKeys[string(key)] {
+		return err
+	}
+	seq, err := order.NextSequence()
+	if err != nil {
+		return err
+	}
+	seqKey := binary.BigEndian.AppendUint64(nil, seq)
+	entry := binary.BigEndian.AppendUint64(nil, uint64(now.UnixNano()))
+	if err = order.Put(seqKey, append(entry, key...)); err != nil {
+		return err
+	}
+	return sequences.Put(key, seqKey)
+}
+
+// unindexWrite removes the key from the index.
+func unindexWrite(order *bbolt.Bucket, sequences *bbolt.Bucket, key []byte) error {
+	seqKey := sequences.Get(key)
+	if seqKey == nil {
+		return nil
+	}
+	if err := order.Delete(seqKey); err != nil {
+		return err
+	}
+	return sequences.Delete(key)
+}
+
+// writeTime returns the time of the last write of the key, if it is indexed.
+func writeTime(order *bbolt.Bucket, sequences *bbolt.Bucket, key []byte) (time.Time, bool) {
+	seqKey := sequences.Get(key)
+	if seqKey == nil {
+		return time.Time{}, false
+	}
+	entry := order.Get(seqKey)
+	if len(entry) < 8 {
+		return time.Time{}, false
+	}
+	return time.Unix(0, int64(binary.BigEndian.Uint64(entry))), true
+}
+
+// expired returns true if the key was not written for longer than the TTL.
+func (c *fileStorageClient) expired(tx *bbolt.Tx, key []byte, now time.Time) bool {
+	if c.usageCfg == nil || c.usageCfg.ttl <= 0 {
+		return false
+	}
+	order, sequences := tx.Bucket(writeOrderBucket), tx.Bucket(writeSequenceBucket)
+	if order == nil || sequences == nil {
+		return false
+	}
+	written, ok := writeTime(order, sequences, key)
+	return ok && !now.Before(written.Add(c.usageCfg.ttl))
+}
+
+// trackSet accounts for the value set for the key, which replaces any previous value.
+func (c *fileStorageClient) trackSet(tx *bbolt.Tx, key []byte, value []byte, delta *usage, now time.Time) error {
+	if c.usageCfg == nil {
+		return nil
+	}
+	if previous := tx.Bucket(defaultBucket).Get(key); previous != nil {
+		delta.add(key, previous, -1)
+	}
+	delta.add(key, value, 1)
+	if !c.usageCfg.indexed() {
+		return nil
+	}
+	return indexWrite(tx.Bucket(writeOrderBucket), tx.Bucket(writeSequenceBucket), key, now)
+}
+
+// trackDelete accounts for the deletion of the key.
+func (c *fileStorageClient) trackDelete(tx *bbolt.Tx, key []byte, delta *usage) error {
+	if c.usageCfg == nil {
+		return nil
+	}
+	if previous := tx.Bucket(defaultBucket).Get(key); previous != nil {
+		delta.add(key, previous, -1)
+	}
+	if !c.usageCfg.indexed() {
+		return nil
+	}
+	return unindexWrite(tx.Bucket(writeOrderBucket), tx.Bucket(writeSequenceBucket), key)
+}
+
+// enforceQuota rejects the transaction if it grows the client beyond its quota, or evicts the least
+// recently written keys, except the ones written by the transaction, until the client fits in it.
+func (c *fileStorageClient) enforceQuota(tx *bbolt.Tx, written map[string]struct{}, delta *usage) error {
+	if c.usageCfg == nil || c.usageCfg.quota == nil || delta.size <= 0 {
+		return nil
+	}
+	maxSize := c.usageCfg.quota.MaxSizeMiB * oneMiB
+	if c.usage.size+delta.size <= maxSize {
+		return nil
+	}
+	if c.usageCfg.quota.OnExceeded != QuotaEvictOldest {
+		delta.rejected = true
+		return fmt.Errorf("%w: %d bytes are used out of %d", errQuotaExceeded, c.usage.size+delta.size, maxSize)
+	}
+
+	bucket := tx.Bucket(defaultBucket)
+	order, sequences := tx.Bucket(writeOrderBucket), tx.Bucket(writeSequenceBucket)
+	var evicted [][]byte
+	excess := c.usage.size + delta.size - maxSize
+	cursor := order.Cursor()
+	for seqKey, entry := cursor.First(); seqKey != nil && excess > 0; seqKey, entry = cursor.Next() {
+		key := entry[8:]
+		if _, ok := written[string(key)]; ok {
+			continue
+		}
+		if value := bucket.Get(key); value != nil {
+			excess -= int64(len(key) + len(value))
+		}
+		evicted = append(evicted, bytes.Clone(key))
+	}
+	if excess > 0 {
+		delta.rejected = true
+		return fmt.Errorf("%w: the written values are larger than the quota of %d bytes", errQuotaExceeded, maxSize)
+	}
+	for _, key := range evicted {
+		if err := c.evictKey(bucket, order, sequences, key, delta, evictionReasonQuota); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *fileStorageClient) evictKey(bucket, order, sequences *bbolt.Bucket, key []byte, delta *usage, reason string) error {
+	if value := bucket.Get(key); value != nil {
+		delta.add(key, value, -1)
+		delta.evict(reason)
+	}
+	if err := bucket.Delete(key); err != nil {
+		return err
+	}
+	return unindexWrite(order, sequences, key)
+}
+
+// expire evicts the keys which were not written for longer than the TTL.
+// It must be called while holding the compaction mutex.
+func (c *fileStorageClient) expire(now time.Time) error {
+	if c.usageCfg == nil || c.usageCfg.ttl <= 0 {
+		return nil
+	}
+	c.usageMutex.Lock()
+	defer c.usageMutex.Unlock()
+
+	var delta usage
+	err := c.db.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket(defaultBucket)
+		order, sequences := tx.Bucket(writeOrderBucket), tx.Bucket(writeSequenceBucket)
+		if bucket == nil || order == nil || sequences == nil {
+			return nil
+		}
+		// the keys are ordered by write, so the expired keys come first
+		var expired [][]byte
+		cursor := order.Cursor()
+		for seqKey, entry := cursor.First(); seqKey != nil; seqKey, entry = cursor.Next() {
+			written := time.Unix(0, int64(binary.BigEndian.Uint64(entry)))
+			if now.Before(written.Add(c.usageCfg.ttl)) {
+				break
+			}
+			expired = append(expired, bytes.Clone(entry[8:]))
+		}
+		for _, key := range expired {
+			if err := c.evictKey(bucket, order, sequences, key, &delta, evictionReasonTTL); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	c.applyUsage(delta)
+	if n := delta.evicted[evictionReasonTTL]; n > 0 {
+		c.logger.Debug("evicted expired keys", zap.Int64("count", n))
+	}
+	return nil
+}
+
+// applyUsage updates the usage of the client with the changes of a committed transaction.
+func (c *fileStorageClient) applyUsage(delta usage) {
+	if c.usageCfg == nil {
+		return
+	}
+	c.usage.size += delta.size
+	c.usage.entries += delta.entries
+	c.record(delta)
+}
+
+// record reports the changes of the usage of the client.
+func (c *fileStorageClient) record(delta usage) {
+	telemetry := c.usageCfg.telemetry
+	if telemetry == nil {
+		return
+	}
+	ctx := context.Background()
+	attrs := metric.WithAttributeSet(attribute.NewSet(attribute.String(clientKey, c.usageCfg.name)))
+	if delta.size != 0 {
+		telemetry.FilestorageClientSize.Add(ctx, delta.size, attrs)
+	}
+	if delta.entries != 0 {
+		telemetry.FilestorageClientEntries.Add(ctx, delta.entries, attrs)
+	}
+	for reason, n := range delta.evicted {
+		telemetry.FilestorageClientEvictions.Add(ctx, n, metric.WithAttributes(
+			attribute.String(clientKey, c.usageCfg.name), attribute.String(reasonKey, reason)))
+	}
+	if delta.rejected {
+		telemetry.FilestorageClientRejectedWrites.Add(ctx, 1, attrs)
+	}
+}
@@ -0,0 +1,263 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filestorage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.etcd.io/bbolt"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/extension/experimental/storage"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.uber.org/zap"
+)
+
+func TestClientQuotaReject(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "my_db")
+	client, err := newClient(zap.NewNop(), dbFile, time.Second, &CompactionConfig{}, false, nil, &usageConfig{
+		quota: &QuotaConfig{MaxSizeMiB: 1, OnExceeded: QuotaReject},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(context.TODO()))
+	})
+
+	ctx := context.Background()
+	value := make([]byte, 600_000)
+	require.NoError(t, client.Set(ctx, "a", value))
+
+	err = client.Set(ctx, "b", value)
+	require.ErrorIs(t, err, errQuotaExceeded)
+	got, err := client.Get(ctx, "b")
+	require.NoError(t, err)
+	require.Nil(t, got, "the rejected write must not be stored")
+
+	// writes which do not grow the client are accepted
+	require.NoError(t, client.Set(ctx, "a", value))
+	require.NoError(t, client.Delete(ctx, "a"))
+	require.NoError(t, client.Set(ctx, "b", value))
+	assert.Equal(t, usage{size: 600_001, entries: 1}, client.usage)
+}
+
+func TestClientQuotaEvictOldest(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "my_db")
+	client, err := newClient(zap.NewNop(), dbFile, time.Second, &CompactionConfig{}, false, nil, &usageConfig{
+		quota: &QuotaConfig{MaxSizeMiB: 1, OnExceeded: QuotaEvictOldest},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(context.TODO()))
+	})
+
+	ctx := context.Background()
+	value := make([]byte, 400_000)
+	require.NoError(t, client.Set(ctx, "a", value))
+	require.NoError(t, client.Set(ctx, "b", value))
+	// a is rewritten, so b becomes the oldest key
+	require.NoError(t, client.Set(ctx, "a", value))
+	require.NoError(t, client.Set(ctx, "c", value))
+
+	for key, expected := range map[string][]byte{"a": value, "b": nil, "c": value} {
+		got, err := client.Get(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, expected, got, key)
+	}
+	assert.Equal(t, usage{size: 800_002, entries: 2}, client.usage)
+
+	// the written values cannot be evicted to make room for themselves
+	err = client.Batch(ctx,
+		storage.SetOperation("d", value),
+		storage.SetOperation("e", value),
+		storage.SetOperation("f", value),
+	)
+	require.ErrorIs(t, err, errQuotaExceeded)
+	assert.Equal(t, usage{size: 800_002, entries: 2}, client.usage)
+}
+
+func TestClientQueueMetadataKeysAreNotEvicted(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "my_db")
+	ttl := 200 * time.Millisecond
+	client, err := newClient(zap.NewNop(), dbFile, time.Second, &CompactionConfig{CheckInterval: 10 * time.Millisecond}, false, nil, &usageConfig{
+		quota: &QuotaConfig{MaxSizeMiB: 1, OnExceeded: QuotaEvictOldest},
+		ttl:   ttl,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(context.TODO()))
+	})
+
+	ctx := context.Background()
+	for _, key := range []string{"ri", "wi", "di"} {
+		require.NoError(t, client.Set(ctx, key, []byte{1}))
+	}
+	value := make([]byte, 400_000)
+	require.NoError(t, client.Set(ctx, "0", value))
+	require.NoError(t, client.Set(ctx, "1", value))
+	require.NoError(t, client.Set(ctx, "2", value))
+
+	// the oldest item is evicted, rather than the older metadata of the queue
+	got, err := client.Get(ctx, "0")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+
+	// the metadata of the queue doesn't expire either
+	require.Eventually(t, func() bool {
+		return rawEntries(t, client) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	for _, key := range []string{"ri", "wi", "di"} {
+		got, err := client.Get(ctx, key)
+		require.NoError(t, err)
+		assert.Equal(t, []byte{1}, got, key)
+	}
+}
+
+func TestClientTTL(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "my_db")
+
+	// keys written before the TTL is enabled expire as if they were written when it is
+	client, err := newClient(zap.NewNop(), dbFile, time.Second, &CompactionConfig{}, false, nil, nil)
+	require.NoError(t, err)
+	ctx := context.Background()
+	require.NoError(t, client.Set(ctx, "existing", []byte("value")))
+	require.NoError(t, client.Close(ctx))
+
+	ttl := 200 * time.Millisecond
+	client, err = newClient(zap.NewNop(), dbFile, time.Second, &CompactionConfig{CheckInterval: 10 * time.Millisecond}, false, nil, &usageConfig{ttl: ttl})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(context.TODO()))
+	})
+	require.NoError(t, client.Set(ctx, "new", []byte("value")))
+
+	for _, key := range []string{"existing", "new"} {
+		got, err := client.Get(ctx, key)
+		require.NoError(t, err)
+		require.Equal(t, []byte("value"), got)
+	}
+
+	require.Eventually(t, func() bool {
+		return rawEntries(t, client) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+	for _, key := range []string{"existing", "new"} {
+		got, err := client.Get(ctx, key)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	}
+}
+
+func TestClientExpiredKeysAreNotRead(t *testing.T) {
+	dbFile := filepath.Join(t.TempDir(), "my_db")
+	client, err := newClient(zap.NewNop(), dbFile, time.Second, &CompactionConfig{CheckInterval: time.Hour}, false, nil, &usageConfig{ttl: time.Hour})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, client.Close(context.TODO()))
+	})
+
+	ctx := context.Background()
+	require.NoError(t, client.Set(ctx, "key", []byte("value")))
+
+	// expired keys which were not evicted yet are not returned
+	require.NoError(t, client.db.View(func(tx *bbolt.Tx) error {
+		assert.False(t, client.expired(tx, []byte("key"), time.Now()))
+		assert.True(t, client.expired(tx, []byte("key"), time.Now().Add(time.Hour)))
+		return nil
+	}))
+
+	// and are evicted before compaction
+	require.NoError(t, client.expire(time.Now().Add(time.Hour)))
+	assert.Equal(t, 0, rawEntries(t, client))
+	assert.Equal(t, usage{}, client.usage)
+}
+
+func rawEntries(t *testing.T, client *fileStorageClient) int {
+	client.compactionMutex.RLock()
+	defer client.compactionMutex.RUnlock()
+	var n int
+	require.NoError(t, client.db.View(func(tx *bbolt.Tx) error {
+		n = tx.Bucket(defaultBucket).Stats().KeyN
+		return nil
+	}))
+	return n
+}
+
+func TestClientUsageTelemetry(t *testing.T) {
+	tt := setupTestTelemetry()
+	t.Cleanup(func() {
+		require.NoError(t, tt.Shutdown(context.Background()))
+	})
+
+	f := NewFactory()
+	cfg := f.CreateDefaultConfig().(*Config)
+	cfg.Directory = t.TempDir()
+	cfg.Quota = &QuotaConfig{MaxSizeMiB: 1, OnExceeded: QuotaEvictOldest}
+	ext, err := f.CreateExtension(context.Background(), tt.NewSettings(), cfg)
+	require.NoError(t, err)
+	se := ext.(storage.Extension)
+	require.NoError(t, se.Start(context.Background(), componenttest.NewNopHost()))
+	t.Cleanup(func() {
+		require.NoError(t, se.Shutdown(context.Background()))
+	})
+
+	ctx := context.Background()
+	client, err := se.GetClient(ctx, component.KindReceiver, newTestEntity("my_component"), "")
+	require.NoError(t, err)
+	value := make([]byte, 600_000)
+	require.NoError(t, client.Set(ctx, "a", value))
+	require.NoError(t, client.Set(ctx, "b", value))
+	err = client.Set(ctx, "c", make([]byte, 2*oneMiB))
+	require.ErrorIs(t, err, errQuotaExceeded)
+
+	clientAttrs := attribute.NewSet(attribute.String("client", "receiver_nop_my_component"))
+	tt.assertMetrics(t, []metricdata.Metrics{
+		{
+			Name:        "filestorage_client_size",
+			Description: "Size of the keys and values stored by a client",
+			Unit:        "By",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: false,
+				DataPoints:  []metricdata.DataPoint[int64]{{Value: 600_001, Attributes: clientAttrs}},
+			},
+		},
+		{
+			Name:        "filestorage_client_entries",
+			Description: "Number of entries stored by a client",
+			Unit:        "1",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: false,
+				DataPoints:  []metricdata.DataPoint[int64]{{Value: 1, Attributes: clientAttrs}},
+			},
+		},
+		{
+			Name:        "filestorage_client_evictions",
+			Description: "Number of entries evicted from a client because its quota was exceeded or their TTL expired",
+			Unit:        "1",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints: []metricdata.DataPoint[int64]{{Value: 1, Attributes: attribute.NewSet(
+					attribute.String("client", "receiver_nop_my_component"), attribute.String("reason", "quota"))}},
+			},
+		},
+		{
+			Name:        "filestorage_client_rejected_writes",
+			Description: "Number of writes rejected because they would exceed the quota of a client",
+			Unit:        "1",
+			Data: metricdata.Sum[int64]{
+				Temporality: metricdata.CumulativeTemporality,
+				IsMonotonic: true,
+				DataPoints:  []metricdata.DataPoint[int64]{{Value: 1, Attributes: clientAttrs}},
+			},
+		},
+	})
+	require.NoError(t, client.Close(ctx))
+}
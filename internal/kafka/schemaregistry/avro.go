@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/linkedin/goavro/v2"
+)
+
+var errAvroReferences = errors.New("avro schema references are not supported")
+
+// avroCodec converts the Avro messages from and to the JSON values of their schema: the values of the
+// unions aren't wrapped in an object naming their type, as in the Avro JSON encoding.
+type avroCodec struct {
+	codec *goavro.Codec
+}
+
+func newAvroCodec(schema *Schema) (*avroCodec, error) {
+	if len(schema.References) != 0 {
+		return nil, errAvroReferences
+	}
+	codec, err := goavro.NewCodecForStandardJSONFull(schema.Schema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create avro codec: %w", err)
+	}
+	return &avroCodec{codec: codec}, nil
+}
+
+func (c *avroCodec) decode(buf []byte) (any, error) {
+	native, rest, err := c.codec.NativeFromBinary(buf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize avro record: %w", err)
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("failed to deserialize avro record: %d trailing bytes", len(rest))
+	}
+	textual, err := c.codec.TextualFromNative(nil, native)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize avro record: %w", err)
+	}
+	return fromJSON(textual)
+}
+
+func (c *avroCodec) encode(buf []byte, value any) ([]byte, error) {
+	textual, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	native, _, err := c.codec.NativeFromTextual(textual)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize avro record: %w", err)
+	}
+	buf, err = c.codec.BinaryFromNative(buf, native)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize avro record: %w", err)
+	}
+	return buf, nil
+}
@@ -0,0 +1,116 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// failureTTL is how long a failure to resolve a value is returned again, rather than requesting the
+	// schema registry for every message while it's failing.
+	failureTTL = time.Second
+	// maxFailures is the maximum number of failures cached, so that the keys which are never resolved,
+	// such as the schema IDs read from garbage messages, don't grow the cache.
+	maxFailures = 1000
+)
+
+// cache caches the values resolved from the schema registry. The value of a key is resolved once for all
+// the concurrent callers, without holding the lock of the cache, so that the other keys are still served
+// while the schema registry is requested.
+type cache[K comparable, V any] struct {
+	// refreshInterval is how long a value is used before it's resolved again, the values are never
+	// resolved again when it's zero.
+	refreshInterval time.Duration
+	now             func() time.Time
+
+	mu      sync.Mutex
+	entries map[K]*cacheEntry[V]
+	// failures is the number of entries holding a failure.
+	failures int
+	group    singleflight.Group
+}
+
+type cacheEntry[V any] struct {
+	value V
+	err   error
+	// expiry is when the entry must be resolved again, the entry never expires when it's zero.
+	expiry time.Time
+}
+
+func newCache[K comparable, V any](refreshInterval time.Duration) *cache[K, V] {
+	return &cache[K, V]{
+		refreshInterval: refreshInterval,
+		now:             time.Now,
+		entries:         map[K]*cacheEntry[V]{},
+	}
+}
+
+// get returns the value of the key, which is resolved when it's not cached or its entry expired.
+// When a value can't be resolved again, the previous value is used until the next attempt.
+func (c *cache[K, V]) get(ctx context.Context, key K, resolve func(ctx context.Context) (V, error)) (V, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && (entry.expiry.IsZero() || c.now().Before(entry.expiry)) {
+		return entry.value, entry.err
+	}
+
+	res, _, _ := c.group.Do(fmt.Sprint(key), func() (any, error) {
+		value, err := resolve(ctx)
+		if err != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+			// the failure comes from the caller, not from the schema registry
+			return &cacheEntry[V]{value: value, err: err}, nil
+		}
+
+		now := c.now()
+		resolved := &cacheEntry[V]{value: value, err: err}
+		switch {
+		case err != nil && ok && entry.err == nil:
+			resolved = &cacheEntry[V]{value: entry.value, expiry: now.Add(failureTTL)}
+		case err != nil:
+			resolved.expiry = now.Add(failureTTL)
+		case c.refreshInterval > 0:
+			resolved.expiry = now.Add(c.refreshInterval)
+		}
+		c.mu.Lock()
+		c.store(key, resolved, now)
+		c.mu.Unlock()
+		return resolved, nil
+	})
+	resolved := res.(*cacheEntry[V])
+	return resolved.value, resolved.err
+}
+
+// store caches the entry of the key. When maxFailures failures are cached, the expired failures are removed
+// to cache a new one, and the failure isn't cached if none expired yet.
+func (c *cache[K, V]) store(key K, entry *cacheEntry[V], now time.Time) {
+	if previous, ok := c.entries[key]; ok {
+		delete(c.entries, key)
+		if previous.err != nil {
+			c.failures--
+		}
+	}
+	if entry.err != nil {
+		if c.failures >= maxFailures {
+			for k, e := range c.entries {
+				if e.err != nil && !now.Before(e.expiry) {
+					delete(c.entries, k)
+					c.failures--
+				}
+			}
+		}
+		if c.failures >= maxFailures {
+			return
+		}
+		c.failures++
+	}
+	c.entries[key] = entry
+}
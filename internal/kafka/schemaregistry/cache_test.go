@@ -0,0 +1,122 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheResolvesOnce(t *testing.T) {
+	c := newCache[int, string](0)
+	ctx := context.Background()
+
+	// the concurrent callers share the resolution, which doesn't block the other keys
+	var resolutions atomic.Int32
+	release := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := c.get(ctx, 1, func(context.Context) (string, error) {
+				resolutions.Add(1)
+				<-release
+				return "one", nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, "one", value)
+		}()
+	}
+	require.Eventually(t, func() bool { return resolutions.Load() == 1 }, 5*time.Second, 10*time.Millisecond)
+	value, err := c.get(ctx, 2, func(context.Context) (string, error) { return "two", nil })
+	require.NoError(t, err)
+	assert.Equal(t, "two", value)
+	close(release)
+	wg.Wait()
+
+	value, err = c.get(ctx, 1, func(context.Context) (string, error) {
+		t.Fatal("the value must be cached")
+		return "", nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "one", value)
+	assert.Equal(t, int32(1), resolutions.Load())
+}
+
+func TestCacheFailures(t *testing.T) {
+	now := time.Unix(1000, 0)
+	c := newCache[string, string](time.Minute)
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+	errUnavailable := errors.New("unavailable")
+
+	// the failures are cached briefly
+	_, err := c.get(ctx, "subject", func(context.Context) (string, error) { return "", errUnavailable })
+	require.ErrorIs(t, err, errUnavailable)
+	_, err = c.get(ctx, "subject", func(context.Context) (string, error) { return "v1", nil })
+	require.ErrorIs(t, err, errUnavailable)
+	now = now.Add(failureTTL)
+	value, err := c.get(ctx, "subject", func(context.Context) (string, error) { return "v1", nil })
+	require.NoError(t, err)
+	assert.Equal(t, "v1", value)
+
+	// the values are refreshed, and kept while they can't be
+	now = now.Add(time.Minute)
+	value, err = c.get(ctx, "subject", func(context.Context) (string, error) { return "", errUnavailable })
+	require.NoError(t, err)
+	assert.Equal(t, "v1", value)
+	now = now.Add(failureTTL)
+	value, err = c.get(ctx, "subject", func(context.Context) (string, error) { return "v2", nil })
+	require.NoError(t, err)
+	assert.Equal(t, "v2", value)
+
+	// the failures of the callers aren't cached
+	_, err = c.get(ctx, "other", func(context.Context) (string, error) { return "", context.Canceled })
+	require.ErrorIs(t, err, context.Canceled)
+	value, err = c.get(ctx, "other", func(context.Context) (string, error) { return "other", nil })
+	require.NoError(t, err)
+	assert.Equal(t, "other", value)
+}
+
+func TestCacheBoundsFailures(t *testing.T) {
+	now := time.Unix(1000, 0)
+	c := newCache[int, string](0)
+	c.now = func() time.Time { return now }
+	ctx := context.Background()
+	errNotFound := errors.New("not found")
+	resolve := func(context.Context) (string, error) { return "", errNotFound }
+
+	// the failures of the keys which are never resolved are bounded
+	for id := 0; id < maxFailures+10; id++ {
+		_, err := c.get(ctx, id, resolve)
+		require.ErrorIs(t, err, errNotFound)
+	}
+	assert.Len(t, c.entries, maxFailures)
+	assert.Equal(t, maxFailures, c.failures)
+
+	// and they are removed once they expired, to cache the new failures
+	now = now.Add(failureTTL)
+	_, err := c.get(ctx, -1, resolve)
+	require.ErrorIs(t, err, errNotFound)
+	assert.Len(t, c.entries, 1)
+	assert.Equal(t, 1, c.failures)
+
+	// the failures resolved later are no longer counted
+	value, err := c.get(ctx, -1, func(context.Context) (string, error) { return "resolved", nil })
+	require.ErrorIs(t, err, errNotFound)
+	assert.Empty(t, value)
+	now = now.Add(failureTTL)
+	value, err = c.get(ctx, -1, func(context.Context) (string, error) { return "resolved", nil })
+	require.NoError(t, err)
+	assert.Equal(t, "resolved", value)
+	assert.Zero(t, c.failures)
+}
@@ -0,0 +1,290 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const contentType = "application/vnd.schemaregistry.v1+json"
+
+// The types of the schemas, the schemas without type are Avro schemas.
+const (
+	SchemaTypeAvro     = "AVRO"
+	SchemaTypeProtobuf = "PROTOBUF"
+)
+
+// Reference is a reference of a schema to a schema registered under another subject,
+// such as an imported Protobuf file.
+type Reference struct {
+	// Name of the reference, which is the path of the imported file for Protobuf.
+	Name    string `json:"name"`
+	Subject string `json:"subject"`
+	Version int    `json:"version"`
+}
+
+// Schema is a schema of the registry.
+type Schema struct {
+	ID         int         `json:"id,omitempty"`
+	Subject    string      `json:"subject,omitempty"`
+	Version    int         `json:"version,omitempty"`
+	SchemaType string      `json:"schemaType,omitempty"`
+	Schema     string      `json:"schema"`
+	References []Reference `json:"references,omitempty"`
+}
+
+// Type returns the type of the schema.
+func (s *Schema) Type() string {
+	if s.SchemaType == "" {
+		return SchemaTypeAvro
+	}
+	return s.SchemaType
+}
+
+// Error is an error returned by the schema registry.
+type Error struct {
+	StatusCode int    `json:"-"`
+	Code       int    `json:"error_code"`
+	Message    string `json:"message"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("schema registry error %d: %s", e.Code, e.Message)
+}
+
+// IsNotFound returns whether the error is returned by the schema registry for a missing subject, version or schema.
+func IsNotFound(err error) bool {
+	var registryErr *Error
+	return errors.As(err, &registryErr) && registryErr.StatusCode == http.StatusNotFound
+}
+
+// IsTemporary returns whether the error may not happen again when the request is retried: the schema registry
+// couldn't be reached, or it failed with a server error or because of too many requests. The other errors, such
+// as the missing schemas, are returned again whenever the request is retried.
+func IsTemporary(err error) bool {
+	var registryErr *Error
+	if errors.As(err, &registryErr) {
+		return registryErr.StatusCode >= http.StatusInternalServerError || registryErr.StatusCode == http.StatusTooManyRequests
+	}
+	var unavailableErr *unavailableError
+	return errors.As(err, &unavailableErr)
+}
+
+// unavailableError is the error of a request which didn't get a response from the schema registry.
+type unavailableError struct {
+	err error
+}
+
+func (e *unavailableError) Error() string {
+	return e.err.Error()
+}
+
+func (e *unavailableError) Unwrap() error {
+	return e.err
+}
+
+// Client is a client of the schema registry REST API. The schemas and their IDs are cached, except the
+// latest version of a subject, which is requested every time as it changes when a version is registered.
+type Client struct {
+	endpoint   string
+	username   string
+	password   string
+	httpClient *http.Client
+
+	mu            sync.Mutex
+	schemasByID   map[int]*Schema
+	versions      map[subjectVersion]*Schema
+	registrations map[registration]int
+}
+
+type subjectVersion struct {
+	subject string
+	version string
+}
+
+type registration struct {
+	subject    string
+	schemaType string
+	schema     string
+}
+
+// NewClient creates a client of the schema registry.
+func NewClient(cfg ClientConfig) (*Client, error) {
+	if cfg.Endpoint == "" {
+		return nil, errors.New("schema registry endpoint must be non-empty")
+	}
+	if _, err := url.Parse(cfg.Endpoint); err != nil {
+		return nil, fmt.Errorf("invalid schema registry endpoint: %w", err)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if cfg.TLS != nil {
+		tlsConfig, err := cfg.TLS.LoadTLSConfig(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return &Client{
+		endpoint: strings.TrimSuffix(cfg.Endpoint, "/"),
+		username: cfg.Username,
+		password: cfg.Password,
+		httpClient: &http.Client{
+			Transport: transport,
+			Timeout:   cfg.Timeout,
+		},
+		schemasByID:   map[int]*Schema{},
+		versions:      map[subjectVersion]*Schema{},
+		registrations: map[registration]int{},
+	}, nil
+}
+
+// SchemaByID returns the schema registered with an ID.
+func (c *Client) SchemaByID(ctx context.Context, id int) (*Schema, error) {
+	c.mu.Lock()
+	schema, ok := c.schemasByID[id]
+	c.mu.Unlock()
+	if ok {
+		return schema, nil
+	}
+
+	schema = &Schema{}
+	if err := c.do(ctx, http.MethodGet, "/schemas/ids/"+strconv.Itoa(id), nil, schema); err != nil {
+		return nil, err
+	}
+	schema.ID = id
+
+	c.mu.Lock()
+	c.schemasByID[id] = schema
+	c.mu.Unlock()
+	return schema, nil
+}
+
+// SchemaByVersion returns a version of the schema of a subject.
+func (c *Client) SchemaByVersion(ctx context.Context, subject string, version int) (*Schema, error) {
+	return c.schemaByVersion(ctx, subject, strconv.Itoa(version))
+}
+
+// LatestSchema returns the latest version of the schema of a subject.
+func (c *Client) LatestSchema(ctx context.Context, subject string) (*Schema, error) {
+	return c.schemaByVersion(ctx, subject, "latest")
+}
+
+func (c *Client) schemaByVersion(ctx context.Context, subject string, version string) (*Schema, error) {
+	key := subjectVersion{subject: subject, version: version}
+	latest := version == "latest"
+	c.mu.Lock()
+	schema, ok := c.versions[key]
+	c.mu.Unlock()
+	if ok && !latest {
+		return schema, nil
+	}
+
+	schema = &Schema{}
+	path := "/subjects/" + url.PathEscape(subject) + "/versions/" + version
+	if err := c.do(ctx, http.MethodGet, path, nil, schema); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if !latest {
+		c.versions[key] = schema
+	}
+	c.schemasByID[schema.ID] = schema
+	c.mu.Unlock()
+	return schema, nil
+}
+
+// Register registers a schema under a subject, and returns its ID. The ID of the schema is returned
+// when it's already registered.
+func (c *Client) Register(ctx context.Context, subject string, schema *Schema) (int, error) {
+	return c.resolveID(ctx, "/subjects/"+url.PathEscape(subject)+"/versions", subject, schema)
+}
+
+// Lookup returns the ID of a schema registered under a subject.
+func (c *Client) Lookup(ctx context.Context, subject string, schema *Schema) (int, error) {
+	return c.resolveID(ctx, "/subjects/"+url.PathEscape(subject), subject, schema)
+}
+
+func (c *Client) resolveID(ctx context.Context, path string, subject string, schema *Schema) (int, error) {
+	key := registration{subject: subject, schemaType: schema.Type(), schema: schema.Schema}
+	c.mu.Lock()
+	id, ok := c.registrations[key]
+	c.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	request := &Schema{
+		SchemaType: schema.SchemaType,
+		Schema:     schema.Schema,
+		References: schema.References,
+	}
+	response := &Schema{}
+	if err := c.do(ctx, http.MethodPost, path, request, response); err != nil {
+		return 0, err
+	}
+
+	c.mu.Lock()
+	c.registrations[key] = response.ID
+	c.mu.Unlock()
+	return response.ID, nil
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, request any, response any) error {
+	var body io.Reader
+	if request != nil {
+		buf, err := json.Marshal(request)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(buf)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", contentType)
+	if request != nil {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("schema registry request failed: %w", &unavailableError{err: err})
+	}
+	defer resp.Body.Close()
+
+	buf, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading schema registry response: %w", &unavailableError{err: err})
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		registryErr := &Error{StatusCode: resp.StatusCode}
+		if json.Unmarshal(buf, registryErr) != nil || registryErr.Message == "" {
+			registryErr.Code = resp.StatusCode
+			registryErr.Message = http.StatusText(resp.StatusCode)
+		}
+		return registryErr
+	}
+	if err := json.Unmarshal(buf, response); err != nil {
+		return fmt.Errorf("invalid schema registry response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry/schemaregistrytest"
+)
+
+const userSchema = `{"type":"record","name":"User","fields":[{"name":"name","type":"string"}]}`
+
+func TestClient(t *testing.T) {
+	registry := schemaregistrytest.NewRegistry()
+	defer registry.Close()
+	client, err := schemaregistry.NewClient(schemaregistry.ClientConfig{Endpoint: registry.URL + "/"})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	id, err := client.Register(ctx, "users-value", &schemaregistry.Schema{Schema: userSchema})
+	require.NoError(t, err)
+	assert.Equal(t, 1, id)
+
+	lookedUp, err := client.Lookup(ctx, "users-value", &schemaregistry.Schema{Schema: userSchema})
+	require.NoError(t, err)
+	assert.Equal(t, id, lookedUp)
+
+	schema, err := client.SchemaByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, id, schema.ID)
+	assert.Equal(t, schemaregistry.SchemaTypeAvro, schema.Type())
+	assert.Equal(t, userSchema, schema.Schema)
+
+	latest, err := client.LatestSchema(ctx, "users-value")
+	require.NoError(t, err)
+	assert.Equal(t, id, latest.ID)
+	assert.Equal(t, 1, latest.Version)
+
+	version, err := client.SchemaByVersion(ctx, "users-value", 1)
+	require.NoError(t, err)
+	assert.Equal(t, id, version.ID)
+
+	// the responses are cached
+	requests := registry.Requests()
+	_, err = client.Register(ctx, "users-value", &schemaregistry.Schema{Schema: userSchema})
+	require.NoError(t, err)
+	_, err = client.SchemaByID(ctx, id)
+	require.NoError(t, err)
+	_, err = client.SchemaByVersion(ctx, "users-value", 1)
+	require.NoError(t, err)
+	assert.Equal(t, requests, registry.Requests())
+
+	// except the latest version, which changes when a version is registered
+	_, err = client.Register(ctx, "users-value", &schemaregistry.Schema{Schema: `"string"`})
+	require.NoError(t, err)
+	latest, err = client.LatestSchema(ctx, "users-value")
+	require.NoError(t, err)
+	assert.Equal(t, 2, latest.Version)
+}
+
+func TestClientErrors(t *testing.T) {
+	registry := schemaregistrytest.NewRegistry()
+	defer registry.Close()
+	client, err := schemaregistry.NewClient(schemaregistry.ClientConfig{Endpoint: registry.URL})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = client.SchemaByID(ctx, 42)
+	assert.True(t, schemaregistry.IsNotFound(err))
+	assert.False(t, schemaregistry.IsTemporary(err))
+	assert.EqualError(t, err, "schema registry error 40403: Schema not found")
+
+	_, err = client.LatestSchema(ctx, "missing-value")
+	assert.True(t, schemaregistry.IsNotFound(err))
+
+	_, err = client.Lookup(ctx, "missing-value", &schemaregistry.Schema{Schema: userSchema})
+	assert.True(t, schemaregistry.IsNotFound(err))
+
+	_, err = schemaregistry.NewClient(schemaregistry.ClientConfig{})
+	assert.EqualError(t, err, "schema registry endpoint must be non-empty")
+}
+
+func TestClientAuthentication(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "user" || password != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error_code":401,"message":"Unauthorized"}`))
+			return
+		}
+		assert.Equal(t, "application/vnd.schemaregistry.v1+json", r.Header.Get("Accept"))
+		_, _ = w.Write([]byte(`{"schema":"\"string\""}`))
+	}))
+	defer server.Close()
+
+	client, err := schemaregistry.NewClient(schemaregistry.ClientConfig{Endpoint: server.URL, Username: "user", Password: "secret"})
+	require.NoError(t, err)
+	schema, err := client.SchemaByID(context.Background(), 1)
+	require.NoError(t, err)
+	assert.Equal(t, `"string"`, schema.Schema)
+
+	client, err = schemaregistry.NewClient(schemaregistry.ClientConfig{Endpoint: server.URL, Username: "user", Password: "wrong"})
+	require.NoError(t, err)
+	_, err = client.SchemaByID(context.Background(), 1)
+	assert.EqualError(t, err, "schema registry error 401: Unauthorized")
+	assert.False(t, schemaregistry.IsNotFound(err))
+	assert.False(t, schemaregistry.IsTemporary(err))
+}
+
+func TestClientTemporaryErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client, err := schemaregistry.NewClient(schemaregistry.ClientConfig{Endpoint: server.URL})
+	require.NoError(t, err)
+	_, err = client.SchemaByID(context.Background(), 1)
+	assert.EqualError(t, err, "schema registry error 503: Service Unavailable")
+	assert.True(t, schemaregistry.IsTemporary(err))
+
+	// the schema registry can't be reached
+	server.Close()
+	_, err = client.SchemaByID(context.Background(), 1)
+	assert.ErrorContains(t, err, "schema registry request failed")
+	assert.True(t, schemaregistry.IsTemporary(err))
+	assert.False(t, schemaregistry.IsNotFound(err))
+}
@@ -0,0 +1,24 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configtls"
+)
+
+// ClientConfig defines the connection to the schema registry.
+type ClientConfig struct {
+	// Endpoint of the schema registry, such as http://localhost:8081.
+	Endpoint string `mapstructure:"endpoint"`
+	// Username used for the basic authentication, no authentication is used when empty.
+	Username string `mapstructure:"username"`
+	// Password used for the basic authentication.
+	Password string `mapstructure:"password"`
+	// TLS configures the connection to an HTTPS endpoint.
+	TLS *configtls.ClientConfig `mapstructure:"tls"`
+	// Timeout of the requests to the schema registry, the requests don't time out when it's zero.
+	Timeout time.Duration `mapstructure:"timeout"`
+}
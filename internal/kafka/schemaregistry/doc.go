@@ -0,0 +1,7 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package schemaregistry implements a client of the Confluent schema registry, and the serialization of
+// the Avro and Protobuf messages in the wire format of the registry: a magic byte and the ID of the schema
+// of the message, followed by the message.
+package schemaregistry // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// schemaFileName is the name of the compiled Protobuf schema, the schemas it imports are named after their references.
+const schemaFileName = "schema-registry/schema.proto"
+
+// protobufCodec converts the Protobuf messages from and to the JSON mapping of Protobuf.
+type protobufCodec struct {
+	file protoreflect.FileDescriptor
+	// message is the message the values are serialized as, and indexes its path in the file.
+	message protoreflect.MessageDescriptor
+	indexes []int
+}
+
+// newProtobufCodec compiles a Protobuf schema, with the schemas it references. The values are serialized as
+// the message with the full name, or as the first message of the schema when it's empty.
+func newProtobufCodec(ctx context.Context, client *Client, schema *Schema, message string) (*protobufCodec, error) {
+	sources := map[string]string{schemaFileName: schema.Schema}
+	if err := fetchReferences(ctx, client, schema.References, sources); err != nil {
+		return nil, err
+	}
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(sources),
+		}),
+	}
+	files, err := compiler.Compile(ctx, schemaFileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile protobuf schema: %w", err)
+	}
+
+	c := &protobufCodec{file: files[0]}
+	if message == "" {
+		if c.file.Messages().Len() == 0 {
+			return nil, fmt.Errorf("protobuf schema %d has no message", schema.ID)
+		}
+		c.message, c.indexes = c.file.Messages().Get(0), []int{0}
+		return c, nil
+	}
+	c.message, c.indexes = findMessage(c.file.Messages(), protoreflect.FullName(message), nil)
+	if c.message == nil {
+		return nil, fmt.Errorf("protobuf schema %d has no message %q", schema.ID, message)
+	}
+	return c, nil
+}
+
+// fetchReferences adds the schemas referenced by a schema to the sources, and the schemas they reference.
+func fetchReferences(ctx context.Context, client *Client, references []Reference, sources map[string]string) error {
+	for _, reference := range references {
+		if _, ok := sources[reference.Name]; ok {
+			continue
+		}
+		schema, err := client.SchemaByVersion(ctx, reference.Subject, reference.Version)
+		if err != nil {
+			return fmt.Errorf("failed to fetch reference %q: %w", reference.Name, err)
+		}
+		sources[reference.Name] = schema.Schema
+		if err := fetchReferences(ctx, client, schema.References, sources); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findMessage returns the message with the full name, and its path in the file.
+func findMessage(messages protoreflect.MessageDescriptors, name protoreflect.FullName, path []int) (protoreflect.MessageDescriptor, []int) {
+	for i := 0; i < messages.Len(); i++ {
+		message := messages.Get(i)
+		indexes := append(append([]int{}, path...), i)
+		if message.FullName() == name {
+			return message, indexes
+		}
+		if found, foundIndexes := findMessage(message.Messages(), name, indexes); found != nil {
+			return found, foundIndexes
+		}
+	}
+	return nil, nil
+}
+
+// messageAt returns the message at a path of the file.
+func (c *protobufCodec) messageAt(indexes []int) (protoreflect.MessageDescriptor, error) {
+	messages := c.file.Messages()
+	var message protoreflect.MessageDescriptor
+	for _, index := range indexes {
+		if index >= messages.Len() {
+			return nil, fmt.Errorf("protobuf message indexes %v not found in schema", indexes)
+		}
+		message = messages.Get(index)
+		messages = message.Messages()
+	}
+	return message, nil
+}
+
+func (c *protobufCodec) decode(buf []byte) (any, error) {
+	indexes, buf, err := parseMessageIndexes(buf)
+	if err != nil {
+		return nil, err
+	}
+	descriptor, err := c.messageAt(indexes)
+	if err != nil {
+		return nil, err
+	}
+	message := dynamicpb.NewMessage(descriptor)
+	if err = proto.Unmarshal(buf, message); err != nil {
+		return nil, fmt.Errorf("failed to deserialize protobuf message: %w", err)
+	}
+	textual, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize protobuf message: %w", err)
+	}
+	return fromJSON(textual)
+}
+
+func (c *protobufCodec) encode(buf []byte, value any) ([]byte, error) {
+	textual, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	message := dynamicpb.NewMessage(c.message)
+	if err = protojson.Unmarshal(textual, message); err != nil {
+		return nil, fmt.Errorf("failed to serialize protobuf message: %w", err)
+	}
+	buf = appendMessageIndexes(buf, c.indexes)
+	buf, err = proto.MarshalOptions{}.MarshalAppend(buf, message)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize protobuf message: %w", err)
+	}
+	return buf, nil
+}
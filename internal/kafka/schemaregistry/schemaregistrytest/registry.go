@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package schemaregistrytest implements a fake schema registry for the tests.
+package schemaregistrytest // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry/schemaregistrytest"
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+)
+
+// Registry is a fake schema registry, serving the part of the REST API used by the schema registry client.
+type Registry struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	schemas  []*schemaregistry.Schema
+	subjects map[string][]int
+	requests int
+}
+
+// NewRegistry starts a fake schema registry, it must be closed once the test is done.
+func NewRegistry() *Registry {
+	r := &Registry{subjects: map[string][]int{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /schemas/ids/{id}", r.getSchemaByID)
+	mux.HandleFunc("GET /subjects/{subject}/versions/{version}", r.getSchemaByVersion)
+	mux.HandleFunc("POST /subjects/{subject}/versions", r.registerSchema)
+	mux.HandleFunc("POST /subjects/{subject}", r.lookupSchema)
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mu.Lock()
+		r.requests++
+		r.mu.Unlock()
+		mux.ServeHTTP(w, req)
+	}))
+	return r
+}
+
+// Register registers a schema under a subject, and returns its ID.
+func (r *Registry) Register(subject string, schema schemaregistry.Schema) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.register(subject, &schema)
+}
+
+// Requests returns the number of requests served by the registry.
+func (r *Registry) Requests() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.requests
+}
+
+func (r *Registry) register(subject string, schema *schemaregistry.Schema) int {
+	if id := r.find(subject, schema); id != 0 {
+		return id
+	}
+	id := len(r.schemas) + 1
+	r.subjects[subject] = append(r.subjects[subject], id)
+	r.schemas = append(r.schemas, &schemaregistry.Schema{
+		ID:         id,
+		Subject:    subject,
+		Version:    len(r.subjects[subject]),
+		SchemaType: schema.SchemaType,
+		Schema:     schema.Schema,
+		References: schema.References,
+	})
+	return id
+}
+
+// find returns the ID of a schema registered under a subject, or 0 when it's not registered.
+func (r *Registry) find(subject string, schema *schemaregistry.Schema) int {
+	for _, id := range r.subjects[subject] {
+		registered := r.schemas[id-1]
+		if registered.Type() == schema.Type() && registered.Schema == schema.Schema {
+			return id
+		}
+	}
+	return 0
+}
+
+func (r *Registry) getSchemaByID(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	id, err := strconv.Atoi(req.PathValue("id"))
+	if err != nil || id < 1 || id > len(r.schemas) {
+		writeError(w, http.StatusNotFound, 40403, "Schema not found")
+		return
+	}
+	schema := r.schemas[id-1]
+	writeJSON(w, &schemaregistry.Schema{
+		SchemaType: schema.SchemaType,
+		Schema:     schema.Schema,
+		References: schema.References,
+	})
+}
+
+func (r *Registry) getSchemaByVersion(w http.ResponseWriter, req *http.Request) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	versions, ok := r.subjects[req.PathValue("subject")]
+	if !ok {
+		writeError(w, http.StatusNotFound, 40401, "Subject not found")
+		return
+	}
+	version := len(versions)
+	if v := req.PathValue("version"); v != "latest" {
+		var err error
+		if version, err = strconv.Atoi(v); err != nil || version < 1 || version > len(versions) {
+			writeError(w, http.StatusNotFound, 40402, "Version not found")
+			return
+		}
+	}
+	writeJSON(w, r.schemas[versions[version-1]-1])
+}
+
+func (r *Registry) registerSchema(w http.ResponseWriter, req *http.Request) {
+	schema := &schemaregistry.Schema{}
+	if err := json.NewDecoder(req.Body).Decode(schema); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, 42201, "Invalid schema")
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	writeJSON(w, map[string]int{"id": r.register(req.PathValue("subject"), schema)})
+}
+
+func (r *Registry) lookupSchema(w http.ResponseWriter, req *http.Request) {
+	schema := &schemaregistry.Schema{}
+	if err := json.NewDecoder(req.Body).Decode(schema); err != nil {
+		writeError(w, http.StatusUnprocessableEntity, 42201, "Invalid schema")
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subject := req.PathValue("subject")
+	if _, ok := r.subjects[subject]; !ok {
+		writeError(w, http.StatusNotFound, 40401, "Subject not found")
+		return
+	}
+	id := r.find(subject, schema)
+	if id == 0 {
+		writeError(w, http.StatusNotFound, 40403, "Schema not found")
+		return
+	}
+	writeJSON(w, r.schemas[id-1])
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, code int, message string) {
+	w.Header().Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{"error_code": code, "message": message})
+}
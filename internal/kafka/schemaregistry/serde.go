@@ -0,0 +1,178 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// codec converts the messages of a schema from and to values made of the types of JSON: maps, slices,
+// strings, booleans, nil, and int64 or float64 numbers.
+type codec interface {
+	decode(buf []byte) (any, error)
+	// encode appends the message of a value to the buffer.
+	encode(buf []byte, value any) ([]byte, error)
+}
+
+func newCodec(ctx context.Context, client *Client, schema *Schema, message string) (codec, error) {
+	switch schema.Type() {
+	case SchemaTypeAvro:
+		return newAvroCodec(schema)
+	case SchemaTypeProtobuf:
+		return newProtobufCodec(ctx, client, schema, message)
+	default:
+		return nil, fmt.Errorf("unsupported schema type %q", schema.Type())
+	}
+}
+
+// Deserializer deserializes the messages of a type of schema, resolving their schemas by the ID preceding them.
+// The schemas of the IDs never change, so their codecs are cached for good.
+type Deserializer struct {
+	client     *Client
+	schemaType string
+	codecs     *cache[int, codec]
+}
+
+// NewDeserializer creates a deserializer of the messages of a type of schema.
+func NewDeserializer(client *Client, schemaType string) *Deserializer {
+	return &Deserializer{
+		client:     client,
+		schemaType: schemaType,
+		codecs:     newCache[int, codec](0),
+	}
+}
+
+// Deserialize returns the value of a message: the Avro messages are converted to the JSON values of their
+// schema, and the Protobuf messages to the JSON mapping of Protobuf, keeping the names of the fields.
+func (d *Deserializer) Deserialize(ctx context.Context, buf []byte) (any, error) {
+	id, buf, err := parseHeader(buf)
+	if err != nil {
+		return nil, err
+	}
+	c, err := d.codec(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return c.decode(buf)
+}
+
+func (d *Deserializer) codec(ctx context.Context, id int) (codec, error) {
+	return d.codecs.get(ctx, id, func(ctx context.Context) (codec, error) {
+		return d.newCodec(ctx, id)
+	})
+}
+
+func (d *Deserializer) newCodec(ctx context.Context, id int) (codec, error) {
+	schema, err := d.client.SchemaByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if schema.Type() != d.schemaType {
+		return nil, fmt.Errorf("schema %d is a %s schema, not a %s schema", id, schema.Type(), d.schemaType)
+	}
+	return newCodec(ctx, d.client, schema, "")
+}
+
+// SerializerConfig defines the schema the values are serialized with.
+type SerializerConfig struct {
+	// SchemaType is the type of the schema.
+	SchemaType string
+	// Schema the values are serialized with. When empty, the values are serialized with the latest
+	// version of the schema of their subject.
+	Schema string
+	// Message is the full name of the Protobuf message the values are serialized as, the first
+	// message of the schema is used when it's empty.
+	Message string
+	// AutoRegister registers the schema under the subjects it's used with, instead of requiring it to
+	// be registered.
+	AutoRegister bool
+	// RefreshInterval is how long the latest version of the schema of a subject is used before it's
+	// requested again, when Schema is empty. It's used until the process restarts when it's zero.
+	RefreshInterval time.Duration
+}
+
+// Serializer serializes the values with a schema registered under the subjects they're serialized for.
+type Serializer struct {
+	client   *Client
+	cfg      SerializerConfig
+	subjects *cache[string, *subjectCodec]
+}
+
+type subjectCodec struct {
+	id    int
+	codec codec
+}
+
+// NewSerializer creates a serializer of the values with a schema.
+func NewSerializer(client *Client, cfg SerializerConfig) *Serializer {
+	var refreshInterval time.Duration
+	if cfg.Schema == "" {
+		// the ID of a given schema never changes, unlike the latest version of a subject
+		refreshInterval = cfg.RefreshInterval
+	}
+	return &Serializer{
+		client:   client,
+		cfg:      cfg,
+		subjects: newCache[string, *subjectCodec](refreshInterval),
+	}
+}
+
+// Serialize returns the message of a value, serialized with the schema registered under the subject.
+// The value is converted to JSON, which must be valid against the JSON mapping of the schema.
+func (s *Serializer) Serialize(ctx context.Context, subject string, value any) ([]byte, error) {
+	sc, err := s.subjectCodec(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	return sc.codec.encode(appendHeader(nil, sc.id), value)
+}
+
+func (s *Serializer) subjectCodec(ctx context.Context, subject string) (*subjectCodec, error) {
+	return s.subjects.get(ctx, subject, func(ctx context.Context) (*subjectCodec, error) {
+		return s.newSubjectCodec(ctx, subject)
+	})
+}
+
+func (s *Serializer) newSubjectCodec(ctx context.Context, subject string) (*subjectCodec, error) {
+	schema, err := s.resolveSchema(ctx, subject)
+	if err != nil {
+		return nil, err
+	}
+	c, err := newCodec(ctx, s.client, schema, s.cfg.Message)
+	if err != nil {
+		return nil, err
+	}
+	return &subjectCodec{id: schema.ID, codec: c}, nil
+}
+
+func (s *Serializer) resolveSchema(ctx context.Context, subject string) (*Schema, error) {
+	if s.cfg.Schema == "" {
+		schema, err := s.client.LatestSchema(ctx, subject)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the latest schema of subject %q: %w", subject, err)
+		}
+		if schema.Type() != s.cfg.SchemaType {
+			return nil, fmt.Errorf("subject %q has a %s schema, not a %s schema", subject, schema.Type(), s.cfg.SchemaType)
+		}
+		return schema, nil
+	}
+
+	schema := &Schema{Schema: s.cfg.Schema}
+	// the Avro schemas are registered without type, as the registry defaults to Avro
+	if s.cfg.SchemaType != SchemaTypeAvro {
+		schema.SchemaType = s.cfg.SchemaType
+	}
+	var err error
+	if s.cfg.AutoRegister {
+		schema.ID, err = s.client.Register(ctx, subject, schema)
+	} else {
+		schema.ID, err = s.client.Lookup(ctx, subject, schema)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the schema of subject %q: %w", subject, err)
+	}
+	return schema, nil
+}
@@ -0,0 +1,268 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry/schemaregistrytest"
+)
+
+const orderSchema = `{
+  "type": "record",
+  "name": "Order",
+  "fields": [
+    {"name": "id", "type": "long"},
+    {"name": "customer", "type": ["null", "string"], "default": null},
+    {"name": "amount", "type": "double"},
+    {"name": "created", "type": {"type": "long", "logicalType": "timestamp-millis"}},
+    {"name": "items", "type": {"type": "array", "items": "string"}}
+  ]
+}`
+
+const commonProto = `syntax = "proto3";
+package common;
+
+message Money {
+  string currency = 1;
+  int64 units = 2;
+}
+`
+
+const orderProto = `syntax = "proto3";
+package shop;
+
+import "common/money.proto";
+import "google/protobuf/timestamp.proto";
+
+message Customer {
+  string name = 1;
+}
+
+message Order {
+  message Item {
+    string sku = 1;
+    int32 quantity = 2;
+  }
+  int64 id = 1;
+  common.Money total = 2;
+  repeated Item items = 3;
+  google.protobuf.Timestamp created = 4;
+}
+`
+
+func newClient(t *testing.T, registry *schemaregistrytest.Registry) *schemaregistry.Client {
+	client, err := schemaregistry.NewClient(schemaregistry.ClientConfig{Endpoint: registry.URL})
+	require.NoError(t, err)
+	return client
+}
+
+func TestAvroSerde(t *testing.T) {
+	registry := schemaregistrytest.NewRegistry()
+	defer registry.Close()
+	client := newClient(t, registry)
+	ctx := context.Background()
+
+	serializer := schemaregistry.NewSerializer(client, schemaregistry.SerializerConfig{
+		SchemaType:   schemaregistry.SchemaTypeAvro,
+		Schema:       orderSchema,
+		AutoRegister: true,
+	})
+	order := map[string]any{
+		"id":       int64(42),
+		"customer": "alice",
+		"amount":   12.5,
+		"created":  int64(1697187201488),
+		"items":    []any{"book", "pen"},
+	}
+	buf, err := serializer.Serialize(ctx, "orders-value", order)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 0, 1}, buf[:5])
+
+	deserializer := schemaregistry.NewDeserializer(client, schemaregistry.SchemaTypeAvro)
+	value, err := deserializer.Deserialize(ctx, buf)
+	require.NoError(t, err)
+	assert.Equal(t, order, value)
+
+	// the unions are nullable
+	order["customer"] = nil
+	buf, err = serializer.Serialize(ctx, "orders-value", order)
+	require.NoError(t, err)
+	value, err = deserializer.Deserialize(ctx, buf)
+	require.NoError(t, err)
+	assert.Equal(t, order, value)
+
+	_, err = serializer.Serialize(ctx, "orders-value", map[string]any{"id": "not a number"})
+	assert.ErrorContains(t, err, "failed to serialize avro record")
+
+	_, err = deserializer.Deserialize(ctx, []byte("not in the wire format"))
+	assert.ErrorContains(t, err, "magic byte")
+}
+
+func TestAvroSerializerLatestSchema(t *testing.T) {
+	registry := schemaregistrytest.NewRegistry()
+	defer registry.Close()
+	id := registry.Register("orders-value", schemaregistry.Schema{Schema: `{"type":"record","name":"Order","fields":[{"name":"id","type":"long"}]}`})
+	client := newClient(t, registry)
+	ctx := context.Background()
+
+	serializer := schemaregistry.NewSerializer(client, schemaregistry.SerializerConfig{SchemaType: schemaregistry.SchemaTypeAvro})
+	buf, err := serializer.Serialize(ctx, "orders-value", map[string]any{"id": int64(1)})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 0, byte(id)}, buf[:5])
+
+	_, err = serializer.Serialize(ctx, "missing-value", map[string]any{"id": int64(1)})
+	assert.ErrorContains(t, err, `failed to get the latest schema of subject "missing-value"`)
+
+	// the protobuf serializer can't use the avro schema
+	protobufSerializer := schemaregistry.NewSerializer(client, schemaregistry.SerializerConfig{SchemaType: schemaregistry.SchemaTypeProtobuf})
+	_, err = protobufSerializer.Serialize(ctx, "orders-value", map[string]any{"id": int64(1)})
+	assert.EqualError(t, err, `subject "orders-value" has a AVRO schema, not a PROTOBUF schema`)
+
+	// neither can the protobuf deserializer
+	_, err = schemaregistry.NewDeserializer(client, schemaregistry.SchemaTypeProtobuf).Deserialize(ctx, buf)
+	assert.EqualError(t, err, "schema 1 is a AVRO schema, not a PROTOBUF schema")
+}
+
+func TestSerializerWithoutAutoRegister(t *testing.T) {
+	registry := schemaregistrytest.NewRegistry()
+	defer registry.Close()
+	registry.Register("orders-value", schemaregistry.Schema{Schema: `"string"`})
+	client := newClient(t, registry)
+	ctx := context.Background()
+
+	serializer := schemaregistry.NewSerializer(client, schemaregistry.SerializerConfig{
+		SchemaType: schemaregistry.SchemaTypeAvro,
+		Schema:     orderSchema,
+	})
+	_, err := serializer.Serialize(ctx, "orders-value", map[string]any{})
+	assert.ErrorContains(t, err, `failed to resolve the schema of subject "orders-value"`)
+	assert.True(t, schemaregistry.IsNotFound(err))
+
+	// the failure is returned again for a moment, without requesting the registry
+	registry.Register("orders-value", schemaregistry.Schema{Schema: orderSchema})
+	requests := registry.Requests()
+	order := map[string]any{
+		"id":       int64(42),
+		"customer": nil,
+		"amount":   12.5,
+		"created":  int64(1697187201488),
+		"items":    []any{},
+	}
+	_, err = serializer.Serialize(ctx, "orders-value", order)
+	assert.True(t, schemaregistry.IsNotFound(err))
+	assert.Equal(t, requests, registry.Requests())
+
+	assert.Eventually(t, func() bool {
+		_, err = serializer.Serialize(ctx, "orders-value", order)
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestProtobufSerde(t *testing.T) {
+	registry := schemaregistrytest.NewRegistry()
+	defer registry.Close()
+	registry.Register("common/money.proto", schemaregistry.Schema{SchemaType: schemaregistry.SchemaTypeProtobuf, Schema: commonProto})
+	client := newClient(t, registry)
+	ctx := context.Background()
+
+	serializer := schemaregistry.NewSerializer(client, schemaregistry.SerializerConfig{
+		SchemaType:   schemaregistry.SchemaTypeProtobuf,
+		Schema:       orderProto,
+		Message:      "shop.Order",
+		AutoRegister: true,
+	})
+	order := map[string]any{
+		"id":    int64(42),
+		"total": map[string]any{"currency": "EUR", "units": int64(12)},
+		"items": []any{
+			map[string]any{"sku": "book", "quantity": int64(2)},
+		},
+		"created": "2023-10-13T08:53:21.488Z",
+	}
+	// the schemas registered by the serializer can't have references
+	_, err := serializer.Serialize(ctx, "unresolved-value", order)
+	assert.ErrorContains(t, err, "failed to compile protobuf schema")
+
+	serializer = schemaregistry.NewSerializer(client, schemaregistry.SerializerConfig{
+		SchemaType: schemaregistry.SchemaTypeProtobuf,
+		Message:    "shop.Order",
+	})
+	registry.Register("orders-value", schemaregistry.Schema{
+		SchemaType: schemaregistry.SchemaTypeProtobuf,
+		Schema:     orderProto,
+		References: []schemaregistry.Reference{{Name: "common/money.proto", Subject: "common/money.proto", Version: 1}},
+	})
+	buf, err := serializer.Serialize(ctx, "orders-value", order)
+	require.NoError(t, err)
+	// the message is the second one of the schema
+	assert.Equal(t, []byte{2, 2}, buf[5:7])
+
+	deserializer := schemaregistry.NewDeserializer(client, schemaregistry.SchemaTypeProtobuf)
+	value, err := deserializer.Deserialize(ctx, buf)
+	require.NoError(t, err)
+	// the 64 bits integers are strings in the JSON mapping of protobuf
+	assert.Equal(t, map[string]any{
+		"id":    "42",
+		"total": map[string]any{"currency": "EUR", "units": "12"},
+		"items": []any{
+			map[string]any{"sku": "book", "quantity": int64(2)},
+		},
+		"created": "2023-10-13T08:53:21.488Z",
+	}, value)
+
+	_, err = serializer.Serialize(ctx, "orders-value", map[string]any{"unknown": true})
+	assert.ErrorContains(t, err, "failed to serialize protobuf message")
+
+	unknownMessage := schemaregistry.NewSerializer(client, schemaregistry.SerializerConfig{
+		SchemaType: schemaregistry.SchemaTypeProtobuf,
+		Message:    "shop.Missing",
+	})
+	_, err = unknownMessage.Serialize(ctx, "orders-value", order)
+	assert.ErrorContains(t, err, `has no message "shop.Missing"`)
+}
+
+func TestProtobufNestedAndFirstMessage(t *testing.T) {
+	registry := schemaregistrytest.NewRegistry()
+	defer registry.Close()
+	registry.Register("common/money.proto", schemaregistry.Schema{SchemaType: schemaregistry.SchemaTypeProtobuf, Schema: commonProto})
+	registry.Register("orders-value", schemaregistry.Schema{
+		SchemaType: schemaregistry.SchemaTypeProtobuf,
+		Schema:     orderProto,
+		References: []schemaregistry.Reference{{Name: "common/money.proto", Subject: "common/money.proto", Version: 1}},
+	})
+	client := newClient(t, registry)
+	ctx := context.Background()
+	deserializer := schemaregistry.NewDeserializer(client, schemaregistry.SchemaTypeProtobuf)
+
+	// the first message of the schema is used by default
+	serializer := schemaregistry.NewSerializer(client, schemaregistry.SerializerConfig{SchemaType: schemaregistry.SchemaTypeProtobuf})
+	buf, err := serializer.Serialize(ctx, "orders-value", map[string]any{"name": "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, byte(0), buf[5])
+	value, err := deserializer.Deserialize(ctx, buf)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"name": "alice"}, value)
+
+	serializer = schemaregistry.NewSerializer(client, schemaregistry.SerializerConfig{
+		SchemaType: schemaregistry.SchemaTypeProtobuf,
+		Message:    "shop.Order.Item",
+	})
+	buf, err = serializer.Serialize(ctx, "orders-value", map[string]any{"sku": "pen"})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{4, 2, 0}, buf[5:8])
+	value, err = deserializer.Deserialize(ctx, buf)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"sku": "pen"}, value)
+
+	// the message indexes don't match the schema
+	_, err = deserializer.Deserialize(ctx, []byte{0, 0, 0, 0, 2, 2, 10})
+	assert.ErrorContains(t, err, "protobuf message indexes [5] not found in schema")
+}
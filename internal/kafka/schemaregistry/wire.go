@@ -0,0 +1,115 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry // import "github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// magicByte is the first byte of the messages in the wire format of the schema registry.
+const magicByte = 0
+
+// headerSize is the size of the magic byte and of the schema ID preceding the messages.
+const headerSize = 5
+
+var (
+	errMissingMagicByte      = errors.New("message doesn't start with the schema registry magic byte")
+	errInvalidMessageIndexes = errors.New("invalid protobuf message indexes")
+)
+
+// appendHeader appends the magic byte and the schema ID preceding a message.
+func appendHeader(buf []byte, id int) []byte {
+	buf = append(buf, magicByte)
+	return binary.BigEndian.AppendUint32(buf, uint32(id))
+}
+
+// parseHeader returns the schema ID of a message, and the message following it.
+func parseHeader(buf []byte) (int, []byte, error) {
+	if len(buf) < headerSize || buf[0] != magicByte {
+		return 0, nil, errMissingMagicByte
+	}
+	return int(binary.BigEndian.Uint32(buf[1:headerSize])), buf[headerSize:], nil
+}
+
+// appendMessageIndexes appends the path of a Protobuf message in its schema: the index of the message
+// in the file, followed by the indexes of the nested messages. The path of the first message of the
+// file is written as a single 0.
+func appendMessageIndexes(buf []byte, indexes []int) []byte {
+	if len(indexes) == 1 && indexes[0] == 0 {
+		return binary.AppendVarint(buf, 0)
+	}
+	buf = binary.AppendVarint(buf, int64(len(indexes)))
+	for _, index := range indexes {
+		buf = binary.AppendVarint(buf, int64(index))
+	}
+	return buf
+}
+
+// parseMessageIndexes returns the path of a Protobuf message in its schema, and the message following it.
+func parseMessageIndexes(buf []byte) ([]int, []byte, error) {
+	count, n := binary.Varint(buf)
+	if n <= 0 || count < 0 || count > int64(len(buf)) {
+		return nil, nil, errInvalidMessageIndexes
+	}
+	buf = buf[n:]
+	if count == 0 {
+		return []int{0}, buf, nil
+	}
+	indexes := make([]int, count)
+	for i := range indexes {
+		index, n := binary.Varint(buf)
+		if n <= 0 || index < 0 {
+			return nil, nil, errInvalidMessageIndexes
+		}
+		indexes[i] = int(index)
+		buf = buf[n:]
+	}
+	return indexes, buf, nil
+}
+
+// fromJSON decodes a JSON document, the integers are decoded as int64 instead of float64.
+func fromJSON(buf []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(buf))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return convertNumbers(value)
+}
+
+func convertNumbers(value any) (any, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q: %w", v, err)
+		}
+		return f, nil
+	case map[string]any:
+		for key, item := range v {
+			converted, err := convertNumbers(item)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = converted
+		}
+	case []any:
+		for i, item := range v {
+			converted, err := convertNumbers(item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = converted
+		}
+	}
+	return value, nil
+}
@@ -0,0 +1,73 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package schemaregistry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeader(t *testing.T) {
+	buf := append(appendHeader(nil, 258), "message"...)
+	assert.Equal(t, []byte{0, 0, 0, 1, 2}, buf[:headerSize])
+
+	id, message, err := parseHeader(buf)
+	require.NoError(t, err)
+	assert.Equal(t, 258, id)
+	assert.Equal(t, "message", string(message))
+
+	_, _, err = parseHeader([]byte{0, 0, 1})
+	assert.ErrorIs(t, err, errMissingMagicByte)
+	_, _, err = parseHeader([]byte{1, 0, 0, 0, 1})
+	assert.ErrorIs(t, err, errMissingMagicByte)
+}
+
+func TestMessageIndexes(t *testing.T) {
+	tests := []struct {
+		indexes []int
+		encoded []byte
+	}{
+		{
+			indexes: []int{0},
+			encoded: []byte{0},
+		},
+		{
+			indexes: []int{1},
+			encoded: []byte{2, 2},
+		},
+		{
+			indexes: []int{0, 2},
+			encoded: []byte{4, 0, 4},
+		},
+	}
+	for _, tt := range tests {
+		encoded := appendMessageIndexes(nil, tt.indexes)
+		assert.Equal(t, tt.encoded, encoded)
+		indexes, rest, err := parseMessageIndexes(append(encoded, 0xff))
+		require.NoError(t, err)
+		assert.Equal(t, tt.indexes, indexes)
+		assert.Equal(t, []byte{0xff}, rest)
+	}
+
+	_, _, err := parseMessageIndexes(nil)
+	assert.ErrorIs(t, err, errInvalidMessageIndexes)
+	_, _, err = parseMessageIndexes([]byte{4, 0})
+	assert.ErrorIs(t, err, errInvalidMessageIndexes)
+}
+
+func TestFromJSON(t *testing.T) {
+	value, err := fromJSON([]byte(`{"int":9007199254740993,"float":1.5,"list":[1,"a",null],"nested":{"bool":true}}`))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"int":    int64(9007199254740993),
+		"float":  1.5,
+		"list":   []any{int64(1), "a", nil},
+		"nested": map[string]any{"bool": true},
+	}, value)
+
+	_, err = fromJSON([]byte(`{`))
+	assert.Error(t, err)
+}
@@ -9,10 +9,12 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/plog"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatatest/plogtest"
@@ -459,6 +461,30 @@ func Test_e2e_converters(t *testing.T) {
 				tCtx.GetLogRecord().Attributes().PutInt("test", 4)
 			},
 		},
+		{
+			statement: `set(attributes["test"], Sort(Unique(["c", "a", "c", "b"]), "desc"))`,
+			want: func(tCtx ottllog.TransformContext) {
+				s := tCtx.GetLogRecord().Attributes().PutEmptySlice("test")
+				s.AppendEmpty().SetStr("c")
+				s.AppendEmpty().SetStr("b")
+				s.AppendEmpty().SetStr("a")
+			},
+		},
+		{
+			statement: `set(attributes["test"], IndexOf(SliceRange(["a", "b", "c"], 1), "c")) where ContainsValue(["GET", "HEAD"], "HEAD")`,
+			want: func(tCtx ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutInt("test", 1)
+			},
+		},
+		{
+			statement: `set(attributes["test"], KeepPatternMatches(ExtractKeys(attributes), "^http"))`,
+			want: func(tCtx ottllog.TransformContext) {
+				s := tCtx.GetLogRecord().Attributes().PutEmptySlice("test")
+				s.AppendEmpty().SetStr("http.method")
+				s.AppendEmpty().SetStr("http.path")
+				s.AppendEmpty().SetStr("http.url")
+			},
+		},
 		{
 			statement: `set(attributes["test"], Log(1))`,
 			want: func(tCtx ottllog.TransformContext) {
@@ -816,6 +842,90 @@ func Test_e2e_ottl_features(t *testing.T) {
 	}
 }
 
+func Test_e2e_macros(t *testing.T) {
+	macros := []ottl.Macro{
+		{
+			Name: "normalize_http",
+			Parameters: []ottl.MacroParameter{
+				{Name: "target", Type: ottl.MacroParameterTypePath},
+				{Name: "method", Type: ottl.MacroParameterTypeString},
+			},
+			Statements: []string{
+				`set(target["http.request.method"], target["http.method"]) where target["http.method"] != nil`,
+				`delete_key(target, "http.method")`,
+				`set(target["http.request.method"], method) where target["http.request.method"] == nil`,
+			},
+		},
+		{
+			Name: "sanitize",
+			Parameters: []ottl.MacroParameter{
+				{Name: "target", Type: ottl.MacroParameterTypePath},
+			},
+			Statements: []string{
+				`normalize_http(target, "GET")`,
+				`replace_pattern(target["http.url"], "localhost", "example.com")`,
+			},
+		},
+	}
+	require.NoError(t, ottl.ValidateMacros(macros))
+
+	tests := []struct {
+		name      string
+		statement string
+		want      func(tCtx ottllog.TransformContext)
+	}{
+		{
+			name:      "macro",
+			statement: `normalize_http(attributes, "GET")`,
+			want: func(tCtx ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutStr("http.request.method", "get")
+				tCtx.GetLogRecord().Attributes().Remove("http.method")
+			},
+		},
+		{
+			name:      "macro with named arguments",
+			statement: `normalize_http(method="POST", target=attributes["foo"])`,
+			want: func(tCtx ottllog.TransformContext) {
+				m, _ := tCtx.GetLogRecord().Attributes().Get("foo")
+				m.Map().PutStr("http.request.method", "POST")
+			},
+		},
+		{
+			name:      "macro with where clause",
+			statement: `normalize_http(attributes, "GET") where body == "operationB"`,
+			want:      func(_ ottllog.TransformContext) {},
+		},
+		{
+			name:      "nested macros",
+			statement: `sanitize(attributes)`,
+			want: func(tCtx ottllog.TransformContext) {
+				tCtx.GetLogRecord().Attributes().PutStr("http.request.method", "get")
+				tCtx.GetLogRecord().Attributes().Remove("http.method")
+				tCtx.GetLogRecord().Attributes().PutStr("http.url", "http://example.com/health")
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := componenttest.NewNopTelemetrySettings()
+			logParser, err := ottllog.NewParser(ottlfuncs.StandardFuncs[ottllog.TransformContext](), settings, ottllog.Option(ottl.WithMacros[ottllog.TransformContext](macros)))
+			require.NoError(t, err)
+			logStatement, err := logParser.ParseStatement(tt.statement)
+			require.NoError(t, err)
+
+			tCtx := constructLogTransformContext()
+			_, _, err = logStatement.Execute(context.Background(), tCtx)
+			require.NoError(t, err)
+
+			exTCtx := constructLogTransformContext()
+			tt.want(exTCtx)
+
+			assert.NoError(t, plogtest.CompareResourceLogs(newResourceLogs(exTCtx), newResourceLogs(tCtx)))
+		})
+	}
+}
+
 func constructLogTransformContext() ottllog.TransformContext {
 	resource := pcommon.NewResource()
 	resource.Attributes().PutStr("host.name", "localhost")
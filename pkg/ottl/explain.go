@@ -0,0 +1,236 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottl // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// ExplainOutput is where the explanations of sampled evaluations are emitted.
+type ExplainOutput string
+
+const (
+	// ExplainOutputLog emits the explanations as debug logs.
+	ExplainOutputLog ExplainOutput = "log"
+	// ExplainOutputSpanEvent emits the explanations as events of the span found in the context of the evaluation.
+	ExplainOutputSpanEvent ExplainOutput = "span_event"
+)
+
+// ExplainConfig configures the explain mode of StatementSequence and ConditionSequence. In explain mode, every
+// statement or condition evaluated for a sampled TransformContext is recorded with the outcome of its condition
+// and, for statements, the values of the paths passed to the function before and after its execution.
+type ExplainConfig struct {
+	// SamplingRatio is the ratio of evaluations which are explained, between 0 and 1. 0 disables the explain mode.
+	SamplingRatio float64 `mapstructure:"sampling_ratio"`
+	// Output is where the explanations are emitted. Defaults to `log`.
+	Output ExplainOutput `mapstructure:"output"`
+}
+
+// Validate checks the ExplainConfig.
+func (c ExplainConfig) Validate() error {
+	var errs []error
+	if c.SamplingRatio < 0 || c.SamplingRatio > 1 {
+		errs = append(errs, fmt.Errorf("sampling_ratio must be between 0 and 1, got %v", c.SamplingRatio))
+	}
+	switch c.Output {
+	case "", ExplainOutputLog, ExplainOutputSpanEvent:
+	default:
+		errs = append(errs, fmt.Errorf("unknown output %q, must be %q or %q", c.Output, ExplainOutputLog, ExplainOutputSpanEvent))
+	}
+	return errors.Join(errs...)
+}
+
+// WithStatementSequenceExplain enables the explain mode of a StatementSequence.
+func WithStatementSequenceExplain[K any](config ExplainConfig) StatementSequenceOption[K] {
+	return func(s *StatementSequence[K]) {
+		s.explain = config
+	}
+}
+
+// WithConditionSequenceExplain enables the explain mode of a ConditionSequence.
+func WithConditionSequenceExplain[K any](config ExplainConfig) ConditionSequenceOption[K] {
+	return func(c *ConditionSequence[K]) {
+		c.explain = config
+	}
+}
+
+// sampled returns true if the current evaluation must be explained.
+func (c ExplainConfig) sampled(ctx context.Context, logger *zap.Logger) bool {
+	if c.SamplingRatio <= 0 || rand.Float64() >= c.SamplingRatio {
+		return false
+	}
+	if c.Output == ExplainOutputSpanEvent {
+		return trace.SpanFromContext(ctx).IsRecording()
+	}
+	return logger.Core().Enabled(zap.DebugLevel)
+}
+
+// explainedPath is a path passed to the function of a statement, which values are recorded in explain mode.
+type explainedPath[K any] struct {
+	text   string
+	getter Getter[K]
+}
+
+// newExplainedPaths returns the paths passed as arguments to the editor.
+func (p *Parser[K]) newExplainedPaths(e editor) ([]explainedPath[K], error) {
+	var paths []explainedPath[K]
+	for _, arg := range e.Arguments {
+		if arg.Value.Literal == nil || arg.Value.Literal.Path == nil {
+			continue
+		}
+		text := pathText(*arg.Value.Literal.Path)
+		getter, err := p.newGetter(arg.Value)
+		if err != nil {
+			return nil, fmt.Errorf("path %q: %w", text, err)
+		}
+		paths = append(paths, explainedPath[K]{text: text, getter: getter})
+	}
+	return paths, nil
+}
+
+// explainedPaths returns the paths passed to the function of the statement, which are built the first time
+// the statement is explained, rather than for every statement parsed.
+func (s *Statement[K]) explainedPaths() ([]explainedPath[K], error) {
+	s.pathsOnce.Do(func() {
+		if s.newPaths != nil {
+			s.paths, s.pathsErr = s.newPaths()
+		}
+	})
+	return s.paths, s.pathsErr
+}
+
+func pathText(p path) string {
+	var sb strings.Builder
+	for i, f := range p.Fields {
+		if i > 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(f.Name)
+		for _, k := range f.Keys {
+			sb.WriteByte('[')
+			if k.String != nil {
+				sb.WriteString(strconv.Quote(*k.String))
+			} else if k.Int != nil {
+				sb.WriteString(strconv.FormatInt(*k.Int, 10))
+			}
+			sb.WriteByte(']')
+		}
+	}
+	return sb.String()
+}
+
+// pathValues returns the current values of the paths, formatted for the explanation.
+func pathValues[K any](ctx context.Context, tCtx K, paths []explainedPath[K]) []string {
+	values := make([]string, len(paths))
+	for i, p := range paths {
+		v, err := p.getter.Get(ctx, tCtx)
+		if err != nil {
+			values[i] = fmt.Sprintf("<error: %v>", err)
+			continue
+		}
+		values[i] = explainValue(v)
+	}
+	return values
+}
+
+func explainValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "nil"
+	case string:
+		return strconv.Quote(val)
+	case pcommon.Value:
+		return explainValue(val.AsRaw())
+	case pcommon.Map:
+		return fmt.Sprint(val.AsRaw())
+	case pcommon.Slice:
+		return fmt.Sprint(val.AsRaw())
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// explainStatement executes the statement and emits its explanation.
+func explainStatement[K any](ctx context.Context, tCtx K, statement *Statement[K], config ExplainConfig, logger *zap.Logger) (bool, error) {
+	explainedPaths, pathsErr := statement.explainedPaths()
+	before := pathValues(ctx, tCtx, explainedPaths)
+	_, condition, err := statement.Execute(ctx, tCtx)
+	after := pathValues(ctx, tCtx, explainedPaths)
+
+	paths := make([]string, len(explainedPaths))
+	for i, p := range explainedPaths {
+		paths[i] = p.text
+	}
+
+	if config.Output == ExplainOutputSpanEvent {
+		attrs := []attribute.KeyValue{
+			attribute.String("ottl.statement", statement.origText),
+			attribute.Bool("ottl.condition_matched", condition),
+			attribute.StringSlice("ottl.paths", paths),
+			attribute.StringSlice("ottl.values.before", before),
+			attribute.StringSlice("ottl.values.after", after),
+		}
+		if err != nil {
+			attrs = append(attrs, attribute.String("ottl.error", err.Error()))
+		}
+		if pathsErr != nil {
+			attrs = append(attrs, attribute.String("ottl.paths_error", pathsErr.Error()))
+		}
+		trace.SpanFromContext(ctx).AddEvent("ottl.statement", trace.WithAttributes(attrs...))
+		return condition, err
+	}
+
+	fields := []zap.Field{
+		zap.String("statement", statement.origText),
+		zap.Bool("condition matched", condition),
+	}
+	for i, p := range paths {
+		fields = append(fields, zap.Dict(p, zap.String("before", before[i]), zap.String("after", after[i])))
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	if pathsErr != nil {
+		fields = append(fields, zap.NamedError("paths error", pathsErr))
+	}
+	logger.Debug("explain statement", fields...)
+	return condition, err
+}
+
+// explainCondition evaluates the condition and emits its explanation.
+func explainCondition[K any](ctx context.Context, tCtx K, condition *Condition[K], config ExplainConfig, logger *zap.Logger) (bool, error) {
+	match, err := condition.Eval(ctx, tCtx)
+
+	if config.Output == ExplainOutputSpanEvent {
+		attrs := []attribute.KeyValue{
+			attribute.String("ottl.condition", condition.origText),
+			attribute.Bool("ottl.condition_matched", match),
+		}
+		if err != nil {
+			attrs = append(attrs, attribute.String("ottl.error", err.Error()))
+		}
+		trace.SpanFromContext(ctx).AddEvent("ottl.condition", trace.WithAttributes(attrs...))
+		return match, err
+	}
+
+	fields := []zap.Field{
+		zap.String("condition", condition.origText),
+		zap.Bool("match", match),
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	logger.Debug("explain condition", fields...)
+	return match, err
+}
@@ -0,0 +1,247 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+type explainSetArguments struct {
+	Target GetSetter[pcommon.Map]
+	Value  Getter[pcommon.Map]
+}
+
+func newExplainTestParser(t *testing.T) Parser[pcommon.Map] {
+	set := NewFactory("set", &explainSetArguments{}, func(_ FunctionContext, oArgs Arguments) (ExprFunc[pcommon.Map], error) {
+		args := oArgs.(*explainSetArguments)
+		return func(ctx context.Context, tCtx pcommon.Map) (any, error) {
+			val, err := args.Value.Get(ctx, tCtx)
+			if err != nil {
+				return nil, err
+			}
+			return nil, args.Target.Set(ctx, tCtx, val)
+		}, nil
+	})
+	parsePath := func(p Path[pcommon.Map]) (GetSetter[pcommon.Map], error) {
+		if p.Name() != "attributes" || len(p.Keys()) != 1 {
+			return nil, errors.New("only attributes[key] is supported")
+		}
+		key := p.Keys()[0]
+		return &StandardGetSetter[pcommon.Map]{
+			Getter: func(ctx context.Context, tCtx pcommon.Map) (any, error) {
+				k, err := key.String(ctx, tCtx)
+				if err != nil {
+					return nil, err
+				}
+				if v, ok := tCtx.Get(*k); ok {
+					return v.AsRaw(), nil
+				}
+				return nil, nil
+			},
+			Setter: func(ctx context.Context, tCtx pcommon.Map, val any) error {
+				k, err := key.String(ctx, tCtx)
+				if err != nil {
+					return err
+				}
+				s, ok := val.(string)
+				if !ok {
+					return errors.New("only strings can be set")
+				}
+				tCtx.PutStr(*k, s)
+				return nil
+			},
+		}, nil
+	}
+	p, err := NewParser(CreateFactoryMap(set), parsePath, componenttest.NewNopTelemetrySettings())
+	require.NoError(t, err)
+	return p
+}
+
+func Test_StatementSequence_Explain_Log(t *testing.T) {
+	p := newExplainTestParser(t)
+	statements, err := p.ParseStatements([]string{
+		`set(attributes["team"], "payments") where attributes["account"] == "1234"`,
+		`set(attributes["site"], attributes["team"]) where attributes["account"] == "5678"`,
+		`set(attributes["team"], 1)`,
+	})
+	require.NoError(t, err)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	settings := componenttest.NewNopTelemetrySettings()
+	settings.Logger = zap.New(core)
+	sequence := NewStatementSequence(statements, settings, WithStatementSequenceErrorMode[pcommon.Map](IgnoreError), WithStatementSequenceExplain[pcommon.Map](ExplainConfig{SamplingRatio: 1}))
+
+	tCtx := pcommon.NewMap()
+	tCtx.PutStr("account", "1234")
+	require.NoError(t, sequence.Execute(context.Background(), tCtx))
+
+	explained := logs.FilterMessage("explain statement").AllUntimed()
+	require.Len(t, explained, 3)
+
+	assert.Equal(t, map[string]any{
+		"statement":         `set(attributes["team"], "payments") where attributes["account"] == "1234"`,
+		"condition matched": true,
+		`attributes["team"]`: map[string]any{
+			"before": "nil",
+			"after":  `"payments"`,
+		},
+	}, explained[0].ContextMap())
+	assert.Equal(t, map[string]any{
+		"statement":         `set(attributes["site"], attributes["team"]) where attributes["account"] == "5678"`,
+		"condition matched": false,
+		`attributes["site"]`: map[string]any{
+			"before": "nil",
+			"after":  "nil",
+		},
+		`attributes["team"]`: map[string]any{
+			"before": `"payments"`,
+			"after":  `"payments"`,
+		},
+	}, explained[1].ContextMap())
+	assert.Equal(t, "only strings can be set", explained[2].ContextMap()["error"])
+}
+
+func Test_StatementSequence_Explain_Disabled(t *testing.T) {
+	p := newExplainTestParser(t)
+	statements, err := p.ParseStatements([]string{`set(attributes["team"], "payments")`})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		level  zapcore.Level
+		config ExplainConfig
+	}{
+		{
+			name:   "not sampled",
+			level:  zapcore.DebugLevel,
+			config: ExplainConfig{},
+		},
+		{
+			name:   "debug logs disabled",
+			level:  zapcore.InfoLevel,
+			config: ExplainConfig{SamplingRatio: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			core, logs := observer.New(tt.level)
+			settings := componenttest.NewNopTelemetrySettings()
+			settings.Logger = zap.New(core)
+			sequence := NewStatementSequence(statements, settings, WithStatementSequenceExplain[pcommon.Map](tt.config))
+
+			require.NoError(t, sequence.Execute(context.Background(), pcommon.NewMap()))
+			assert.Empty(t, logs.FilterMessage("explain statement").All())
+			assert.Nil(t, statements[0].paths, "the paths must only be built when the statement is explained")
+		})
+	}
+}
+
+func Test_newExplainedPaths_Error(t *testing.T) {
+	p := newExplainTestParser(t)
+	parsed, err := parseStatement(`set(attributes, "payments")`)
+	require.NoError(t, err)
+
+	_, err = p.newExplainedPaths(parsed.Editor)
+	assert.ErrorContains(t, err, `path "attributes": only attributes[key] is supported`)
+}
+
+func Test_StatementSequence_Explain_SpanEvent(t *testing.T) {
+	p := newExplainTestParser(t)
+	statements, err := p.ParseStatements([]string{`set(attributes["team"], "payments")`})
+	require.NoError(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	ctx, span := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)).Tracer("test").Start(context.Background(), "transform")
+	sequence := NewStatementSequence(statements, componenttest.NewNopTelemetrySettings(), WithStatementSequenceExplain[pcommon.Map](ExplainConfig{SamplingRatio: 1, Output: ExplainOutputSpanEvent}))
+
+	require.NoError(t, sequence.Execute(ctx, pcommon.NewMap()))
+	span.End()
+
+	require.Len(t, recorder.Ended(), 1)
+	events := recorder.Ended()[0].Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "ottl.statement", events[0].Name)
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("ottl.statement", `set(attributes["team"], "payments")`),
+		attribute.Bool("ottl.condition_matched", true),
+		attribute.StringSlice("ottl.paths", []string{`attributes["team"]`}),
+		attribute.StringSlice("ottl.values.before", []string{"nil"}),
+		attribute.StringSlice("ottl.values.after", []string{`"payments"`}),
+	}, events[0].Attributes)
+}
+
+func Test_StatementSequence_Explain_Macro(t *testing.T) {
+	p := newExplainTestParser(t)
+	WithMacros[pcommon.Map]([]Macro{
+		{
+			Name:       "assign",
+			Parameters: []MacroParameter{{Name: "team", Type: MacroParameterTypeString}},
+			Statements: []string{`set(attributes["team"], team)`, `set(attributes["owner"], attributes["team"])`},
+		},
+	})(&p)
+	statements, err := p.ParseStatements([]string{`assign("payments")`})
+	require.NoError(t, err)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	settings := componenttest.NewNopTelemetrySettings()
+	settings.Logger = zap.New(core)
+	sequence := NewStatementSequence(statements, settings, WithStatementSequenceExplain[pcommon.Map](ExplainConfig{SamplingRatio: 1}))
+	require.NoError(t, sequence.Execute(context.Background(), pcommon.NewMap()))
+
+	explained := logs.FilterMessage("explain statement").AllUntimed()
+	require.Len(t, explained, 1)
+	assert.Equal(t, map[string]any{
+		"statement":         `assign("payments")`,
+		"condition matched": true,
+		`attributes["team"]`: map[string]any{
+			"before": "nil",
+			"after":  `"payments"`,
+		},
+		`attributes["owner"]`: map[string]any{
+			"before": "nil",
+			"after":  `"payments"`,
+		},
+	}, explained[0].ContextMap())
+}
+
+func Test_ConditionSequence_Explain(t *testing.T) {
+	p := newExplainTestParser(t)
+	conditions, err := p.ParseConditions([]string{`attributes["account"] == "5678"`, `attributes["account"] == "1234"`})
+	require.NoError(t, err)
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	settings := componenttest.NewNopTelemetrySettings()
+	settings.Logger = zap.New(core)
+	sequence := NewConditionSequence(conditions, settings, WithConditionSequenceExplain[pcommon.Map](ExplainConfig{SamplingRatio: 1}))
+
+	tCtx := pcommon.NewMap()
+	tCtx.PutStr("account", "1234")
+	match, err := sequence.Eval(context.Background(), tCtx)
+	require.NoError(t, err)
+	assert.True(t, match)
+
+	explained := logs.FilterMessage("explain condition").AllUntimed()
+	require.Len(t, explained, 2)
+	assert.Equal(t, map[string]any{"condition": `attributes["account"] == "5678"`, "match": false}, explained[0].ContextMap())
+	assert.Equal(t, map[string]any{"condition": `attributes["account"] == "1234"`, "match": true}, explained[1].ContextMap())
+}
+
+func Test_ExplainConfig_Validate(t *testing.T) {
+	assert.NoError(t, ExplainConfig{}.Validate())
+	assert.NoError(t, ExplainConfig{SamplingRatio: 0.5, Output: ExplainOutputSpanEvent}.Validate())
+	assert.EqualError(t, ExplainConfig{SamplingRatio: 2, Output: "stdout"}.Validate(), "sampling_ratio must be between 0 and 1, got 2\nunknown output \"stdout\", must be \"log\" or \"span_event\"")
+}
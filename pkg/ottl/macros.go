@@ -0,0 +1,435 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottl // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/alecthomas/participle/v2/lexer"
+)
+
+// Macro is a named, reusable list of statements which can be invoked from a statement like an editor.
+// Invocations are expanded when statements are parsed: every occurrence of a parameter name in the
+// macro statements is replaced with the argument given for the parameter. The where clause of the
+// invocation applies to all statements of the macro, which are executed in order.
+type Macro struct {
+	// Name is the name used to invoke the macro. It must be a valid editor name.
+	Name string `mapstructure:"name"`
+	// Parameters are the parameters of the macro, in the order positional arguments are bound to them.
+	Parameters []MacroParameter `mapstructure:"parameters"`
+	// Statements are the statements the macro expands to.
+	Statements []string `mapstructure:"statements"`
+}
+
+// MacroParameter is a parameter of a Macro.
+type MacroParameter struct {
+	// Name is the name used to reference the parameter in the macro statements.
+	Name string `mapstructure:"name"`
+	// Type restricts the arguments accepted for the parameter. Defaults to MacroParameterTypeAny.
+	Type MacroParameterType `mapstructure:"type"`
+}
+
+// MacroParameterType is the type of the arguments accepted for a MacroParameter.
+type MacroParameterType string
+
+const (
+	// MacroParameterTypeAny accepts any value: paths, literals, converters, math expressions and lists.
+	MacroParameterTypeAny MacroParameterType = "any"
+	// MacroParameterTypePath accepts paths only, which allows the parameter to be used as a setter.
+	MacroParameterTypePath MacroParameterType = "path"
+	// MacroParameterTypeString accepts string literals only.
+	MacroParameterTypeString MacroParameterType = "string"
+	// MacroParameterTypeInt accepts int literals only.
+	MacroParameterTypeInt MacroParameterType = "int"
+	// MacroParameterTypeFloat accepts float and int literals only.
+	MacroParameterTypeFloat MacroParameterType = "float"
+	// MacroParameterTypeBool accepts bool literals only.
+	MacroParameterTypeBool MacroParameterType = "bool"
+)
+
+var macroNameRegex = regexp.MustCompile(`^[a-z][a-zA-Z0-9_]*$`)
+var macroParameterNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// WithMacros makes the given macros available to the statements parsed by the Parser.
+// The macros should be validated with ValidateMacros beforehand.
+func WithMacros[K any](macros []Macro) Option[K] {
+	return func(p *Parser[K]) {
+		if len(macros) == 0 {
+			return
+		}
+		p.macros = make(map[string]Macro, len(macros))
+		for _, macro := range macros {
+			p.macros[macro.Name] = macro
+		}
+	}
+}
+
+// ValidateMacros checks that the given macros are well-formed: names and parameters are valid and unique,
+// statements are syntactically valid, and macros do not invoke each other in a cycle.
+func ValidateMacros(macros []Macro) error {
+	var errs []error
+	byName := make(map[string]Macro, len(macros))
+	for _, macro := range macros {
+		if _, ok := byName[macro.Name]; ok {
+			errs = append(errs, fmt.Errorf("duplicate macro %q", macro.Name))
+			continue
+		}
+		byName[macro.Name] = macro
+		if err := macro.validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	// Detect cycles between macros with a depth first search on the invocations found in macro statements.
+	visited := map[string]bool{}
+	var visit func(name string, stack []string) error
+	visit = func(name string, stack []string) error {
+		if idx := slices.Index(stack, name); idx >= 0 {
+			return fmt.Errorf("macro cycle detected: %s", strings.Join(append(stack[idx:], name), " -> "))
+		}
+		if visited[name] {
+			return nil
+		}
+		stack = append(stack, name)
+		for _, statement := range byName[name].Statements {
+			parsed, _ := parseStatement(statement)
+			if _, ok := byName[parsed.Editor.Function]; ok {
+				if err := visit(parsed.Editor.Function, stack); err != nil {
+					return err
+				}
+			}
+		}
+		visited[name] = true
+		return nil
+	}
+	for _, macro := range macros {
+		if err := visit(macro.Name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m Macro) validate() error {
+	if !macroNameRegex.MatchString(m.Name) {
+		return fmt.Errorf("invalid macro name %q: must start with a lowercase letter and contain only letters, digits and underscores", m.Name)
+	}
+	var errs []error
+	params := map[string]bool{}
+	for _, param := range m.Parameters {
+		switch {
+		case !macroParameterNameRegex.MatchString(param.Name) || param.Name == "where" || param.Name == "nil":
+			errs = append(errs, fmt.Errorf("invalid parameter name %q", param.Name))
+		case params[param.Name]:
+			errs = append(errs, fmt.Errorf("duplicate parameter %q", param.Name))
+		}
+		params[param.Name] = true
+		switch param.Type {
+		case "", MacroParameterTypeAny, MacroParameterTypePath, MacroParameterTypeString, MacroParameterTypeInt, MacroParameterTypeFloat, MacroParameterTypeBool:
+		default:
+			errs = append(errs, fmt.Errorf("unknown type %q for parameter %q", param.Type, param.Name))
+		}
+	}
+	if len(m.Statements) == 0 {
+		errs = append(errs, errors.New("at least one statement is required"))
+	}
+	for i, statement := range m.Statements {
+		if _, err := parseStatement(statement); err != nil {
+			errs = append(errs, fmt.Errorf("statement %d %q: %w", i, statement, err))
+			continue
+		}
+		roots, err := macroPathRoots(statement)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("statement %d %q: %w", i, statement, err))
+			continue
+		}
+		// the references to the parameter would be replaced in the paths as well
+		for _, root := range roots {
+			if params[root] {
+				errs = append(errs, fmt.Errorf("parameter %q is used as the root of a path in statement %d %q", root, i, statement))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid macro %q: %w", m.Name, errors.Join(errs...))
+	}
+	return nil
+}
+
+// expandMacro parses the statements of the macro invoked by the statement and returns a Statement executing them in order.
+// expanding holds the names of the macros currently being expanded, in order to detect cycles.
+func (p *Parser[K]) expandMacro(macro Macro, statement string, parsed *parsedStatement, expanding []string) (*Statement[K], error) {
+	if _, ok := p.functions[macro.Name]; ok {
+		return nil, fmt.Errorf("macro %q conflicts with the function of the same name", macro.Name)
+	}
+	if slices.Contains(expanding, macro.Name) {
+		return nil, fmt.Errorf("macro cycle detected: %s -> %s", strings.Join(expanding, " -> "), macro.Name)
+	}
+	args, err := macroArguments(statement)
+	if err != nil {
+		return nil, err
+	}
+	// A parameter named like a path of the context would replace the path in the statements of the macro.
+	for _, param := range macro.Parameters {
+		path, err := newPath[K]([]field{{Name: param.Name}})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.pathParser(path); err == nil {
+			return nil, fmt.Errorf("parameter %q of macro %q conflicts with the path of the same name", param.Name, macro.Name)
+		}
+	}
+	bindings, err := macro.bind(args)
+	if err != nil {
+		return nil, err
+	}
+	condition, err := p.newBoolExpr(parsed.WhereClause)
+	if err != nil {
+		return nil, err
+	}
+
+	expanding = append(slices.Clone(expanding), macro.Name)
+	statements := make([]*Statement[K], 0, len(macro.Statements))
+	for i, macroStatement := range macro.Statements {
+		expanded, err := expandMacroStatement(macroStatement, bindings)
+		if err != nil {
+			return nil, fmt.Errorf("macro %q statement %d %q: %w", macro.Name, i, macroStatement, err)
+		}
+		s, err := p.parseStatement(expanded, expanding)
+		if err != nil {
+			return nil, fmt.Errorf("macro %q statement %d %q: %w", macro.Name, i, macroStatement, err)
+		}
+		statements = append(statements, s)
+	}
+
+	return &Statement[K]{
+		function: Expr[K]{
+			exprFunc: func(ctx context.Context, tCtx K) (any, error) {
+				for _, s := range statements {
+					if _, _, err := s.Execute(ctx, tCtx); err != nil {
+						return nil, fmt.Errorf("failed to execute statement %q of macro %q: %w", s.origText, macro.Name, err)
+					}
+				}
+				return nil, nil
+			},
+		},
+		condition: condition,
+		origText:  statement,
+		newPaths: func() ([]explainedPath[K], error) {
+			var paths []explainedPath[K]
+			for _, s := range statements {
+				statementPaths, err := s.explainedPaths()
+				if err != nil {
+					return nil, fmt.Errorf("statement %q of macro %q: %w", s.origText, macro.Name, err)
+				}
+				for _, path := range statementPaths {
+					if !slices.ContainsFunc(paths, func(p explainedPath[K]) bool { return p.text == path.text }) {
+						paths = append(paths, path)
+					}
+				}
+			}
+			return paths, nil
+		},
+	}, nil
+}
+
+// macroArgument is an argument of a macro invocation, as written in the statement.
+type macroArgument struct {
+	name string
+	text string
+	// compound is true if the argument is a math expression which must be wrapped in parentheses when substituted.
+	compound bool
+}
+
+// bind returns the argument text bound to each parameter of the macro, after checking the arguments against the parameter types.
+func (m Macro) bind(args []macroArgument) (map[string]string, error) {
+	bindings := make(map[string]string, len(m.Parameters))
+	named := false
+	for i, arg := range args {
+		var param MacroParameter
+		if arg.name == "" {
+			if named {
+				return nil, fmt.Errorf("unnamed argument used after named argument in macro %q invocation", m.Name)
+			}
+			if i >= len(m.Parameters) {
+				return nil, fmt.Errorf("too many arguments for macro %q: expected %d, got %d", m.Name, len(m.Parameters), len(args))
+			}
+			param = m.Parameters[i]
+		} else {
+			named = true
+			idx := slices.IndexFunc(m.Parameters, func(p MacroParameter) bool { return p.Name == arg.name })
+			if idx < 0 {
+				return nil, fmt.Errorf("undefined parameter %q for macro %q", arg.name, m.Name)
+			}
+			param = m.Parameters[idx]
+		}
+		if _, ok := bindings[param.Name]; ok {
+			return nil, fmt.Errorf("duplicate argument for parameter %q of macro %q", param.Name, m.Name)
+		}
+		if err := checkMacroArgumentType(param, arg.text); err != nil {
+			return nil, fmt.Errorf("invalid argument for parameter %q of macro %q: %w", param.Name, m.Name, err)
+		}
+		text := arg.text
+		if arg.compound {
+			text = "(" + text + ")"
+		}
+		bindings[param.Name] = text
+	}
+	for _, param := range m.Parameters {
+		if _, ok := bindings[param.Name]; !ok {
+			return nil, fmt.Errorf("missing argument for parameter %q of macro %q", param.Name, m.Name)
+		}
+	}
+	return bindings, nil
+}
+
+func checkMacroArgumentType(param MacroParameter, text string) error {
+	v, err := parseValueExpression(text)
+	if err != nil {
+		return err
+	}
+	var ok bool
+	switch param.Type {
+	case MacroParameterTypePath:
+		ok = v.Literal != nil && v.Literal.Path != nil
+	case MacroParameterTypeString:
+		ok = v.String != nil
+	case MacroParameterTypeInt:
+		ok = v.Literal != nil && v.Literal.Int != nil
+	case MacroParameterTypeFloat:
+		ok = v.Literal != nil && (v.Literal.Float != nil || v.Literal.Int != nil)
+	case MacroParameterTypeBool:
+		ok = v.Bool != nil
+	default:
+		ok = true
+	}
+	if !ok {
+		return fmt.Errorf("expected a %s but got %q", param.Type, text)
+	}
+	return nil
+}
+
+var macroLexer = buildLexer()
+
+// macroTokens returns the tokens of the statement, without whitespaces.
+func macroTokens(statement string) ([]lexer.Token, error) {
+	lex, err := macroLexer.LexString("", statement)
+	if err != nil {
+		return nil, err
+	}
+	whitespace := macroLexer.Symbols()["whitespace"]
+	var tokens []lexer.Token
+	for {
+		token, err := lex.Next()
+		if err != nil {
+			return nil, err
+		}
+		if token.EOF() {
+			return tokens, nil
+		}
+		if token.Type != whitespace {
+			tokens = append(tokens, token)
+		}
+	}
+}
+
+// macroArguments returns the arguments of the macro invoked by the statement.
+func macroArguments(statement string) ([]macroArgument, error) {
+	tokens, err := macroTokens(statement)
+	if err != nil {
+		return nil, err
+	}
+	symbols := macroLexer.Symbols()
+
+	// The statement starts with the macro name followed by the opening parenthesis of the invocation.
+	var args []macroArgument
+	start, depth, compound := 2, 0, false
+	for i := 2; i < len(tokens); i++ {
+		token := tokens[i]
+		switch {
+		case token.Type == symbols["LParen"] || token.Value == "[":
+			depth++
+		case depth > 0 && (token.Type == symbols["RParen"] || token.Value == "]"):
+			depth--
+		case depth == 0 && (token.Type == symbols["OpAddSub"] || token.Type == symbols["OpMultDiv"]):
+			compound = true
+		case depth == 0 && (token.Type == symbols["RParen"] || token.Value == ","):
+			if i > start {
+				arg := macroArgument{compound: compound}
+				first := start
+				if i-start > 2 && tokens[start].Type == symbols["Lowercase"] && tokens[start+1].Type == symbols["Equal"] {
+					arg.name = tokens[start].Value
+					first = start + 2
+				}
+				last := tokens[i-1]
+				arg.text = statement[tokens[first].Pos.Offset : last.Pos.Offset+len(last.Value)]
+				args = append(args, arg)
+			}
+			if token.Type == symbols["RParen"] {
+				return args, nil
+			}
+			start, compound = i+1, false
+		}
+	}
+	return nil, errors.New("unterminated macro invocation")
+}
+
+// macroPathRoots returns the lowercase identifiers of the statement which are the first segment of a path with several segments.
+func macroPathRoots(statement string) ([]string, error) {
+	tokens, err := macroTokens(statement)
+	if err != nil {
+		return nil, err
+	}
+	symbols := macroLexer.Symbols()
+
+	var roots []string
+	for i, token := range tokens {
+		if token.Type != symbols["Lowercase"] || (i > 0 && tokens[i-1].Value == ".") {
+			continue
+		}
+		if i+1 < len(tokens) && tokens[i+1].Value == "." {
+			roots = append(roots, token.Value)
+		}
+	}
+	return roots, nil
+}
+
+// expandMacroStatement replaces the references to the macro parameters in the statement with the bound arguments.
+// A reference is a lowercase identifier matching a parameter name which is not a path segment following a '.',
+// a function invocation, or the name of a named argument.
+func expandMacroStatement(statement string, bindings map[string]string) (string, error) {
+	tokens, err := macroTokens(statement)
+	if err != nil {
+		return "", err
+	}
+	symbols := macroLexer.Symbols()
+
+	var sb strings.Builder
+	offset := 0
+	for i, token := range tokens {
+		binding, ok := bindings[token.Value]
+		if !ok || token.Type != symbols["Lowercase"] {
+			continue
+		}
+		if i > 0 && tokens[i-1].Value == "." {
+			continue
+		}
+		if i+1 < len(tokens) && (tokens[i+1].Type == symbols["LParen"] || tokens[i+1].Type == symbols["Equal"]) {
+			continue
+		}
+		sb.WriteString(statement[offset:token.Pos.Offset])
+		sb.WriteString(binding)
+		offset = token.Pos.Offset + len(token.Value)
+	}
+	sb.WriteString(statement[offset:])
+	return sb.String(), nil
+}
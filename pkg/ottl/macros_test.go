@@ -0,0 +1,231 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+)
+
+func Test_ValidateMacros(t *testing.T) {
+	tests := []struct {
+		name          string
+		macros        []Macro
+		expectedError string
+	}{
+		{
+			name: "valid",
+			macros: []Macro{
+				{
+					Name:       "first",
+					Parameters: []MacroParameter{{Name: "target", Type: MacroParameterTypePath}, {Name: "value"}},
+					Statements: []string{`set(target, value)`, `second(target)`},
+				},
+				{
+					Name:       "second",
+					Parameters: []MacroParameter{{Name: "target"}},
+					Statements: []string{`set(target, nil)`},
+				},
+			},
+		},
+		{
+			name: "invalid name",
+			macros: []Macro{
+				{Name: "Invalid", Statements: []string{`set(name, "x")`}},
+			},
+			expectedError: `invalid macro name "Invalid"`,
+		},
+		{
+			name: "duplicate macro",
+			macros: []Macro{
+				{Name: "macro", Statements: []string{`set(name, "x")`}},
+				{Name: "macro", Statements: []string{`set(name, "y")`}},
+			},
+			expectedError: `duplicate macro "macro"`,
+		},
+		{
+			name: "invalid parameter",
+			macros: []Macro{
+				{Name: "macro", Parameters: []MacroParameter{{Name: "where"}}, Statements: []string{`set(name, "x")`}},
+			},
+			expectedError: `invalid parameter name "where"`,
+		},
+		{
+			name: "parameter used as path root",
+			macros: []Macro{
+				{Name: "macro", Parameters: []MacroParameter{{Name: "resource"}}, Statements: []string{`set(resource.attributes["x"], resource)`}},
+			},
+			expectedError: `parameter "resource" is used as the root of a path in statement 0`,
+		},
+		{
+			name: "duplicate parameter",
+			macros: []Macro{
+				{Name: "macro", Parameters: []MacroParameter{{Name: "a"}, {Name: "a"}}, Statements: []string{`set(name, a)`}},
+			},
+			expectedError: `duplicate parameter "a"`,
+		},
+		{
+			name: "unknown parameter type",
+			macros: []Macro{
+				{Name: "macro", Parameters: []MacroParameter{{Name: "a", Type: "map"}}, Statements: []string{`set(name, a)`}},
+			},
+			expectedError: `unknown type "map" for parameter "a"`,
+		},
+		{
+			name: "no statements",
+			macros: []Macro{
+				{Name: "macro"},
+			},
+			expectedError: `at least one statement is required`,
+		},
+		{
+			name: "invalid statement",
+			macros: []Macro{
+				{Name: "macro", Statements: []string{`set(name, "x")`, `set(`}},
+			},
+			expectedError: `invalid macro "macro": statement 1 "set("`,
+		},
+		{
+			name: "cycle",
+			macros: []Macro{
+				{Name: "first", Statements: []string{`second()`}},
+				{Name: "second", Statements: []string{`set(name, "x")`, `third()`}},
+				{Name: "third", Statements: []string{`first()`}},
+			},
+			expectedError: `macro cycle detected: first -> second -> third -> first`,
+		},
+		{
+			name: "self invocation",
+			macros: []Macro{
+				{Name: "first", Statements: []string{`first()`}},
+			},
+			expectedError: `macro cycle detected: first -> first`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMacros(tt.macros)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func Test_macroArguments(t *testing.T) {
+	args, err := macroArguments(`macro(attributes["a,b"], name="x(y)", value=Concat(["a", "b"], ","), 1 + 2, [1, 2]) where name == "x"`)
+	require.NoError(t, err)
+	assert.Equal(t, []macroArgument{
+		{text: `attributes["a,b"]`},
+		{name: "name", text: `"x(y)"`},
+		{name: "value", text: `Concat(["a", "b"], ",")`},
+		{text: `1 + 2`, compound: true},
+		{text: `[1, 2]`},
+	}, args)
+
+	args, err = macroArguments(`macro( )`)
+	require.NoError(t, err)
+	assert.Empty(t, args)
+}
+
+func Test_expandMacroStatement(t *testing.T) {
+	expanded, err := expandMacroStatement(
+		`set(target["name"], value) where target.value != nil and value(target=value) == "target"`,
+		map[string]string{"target": `resource.attributes`, "value": `(1 + 2)`},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, `set(resource.attributes["name"], (1 + 2)) where resource.attributes.value != nil and value(target=(1 + 2)) == "target"`, expanded)
+}
+
+func Test_ParseStatement_Macros(t *testing.T) {
+	macros := []Macro{
+		{
+			Name:       "get_string",
+			Parameters: []MacroParameter{{Name: "value", Type: MacroParameterTypeString}},
+			Statements: []string{`testing_getter(value)`},
+		},
+		{
+			Name:       "get_path",
+			Parameters: []MacroParameter{{Name: "target", Type: MacroParameterTypePath}},
+			Statements: []string{`testing_getsetter(target)`},
+		},
+		{
+			Name:       "get_numbers",
+			Parameters: []MacroParameter{{Name: "i", Type: MacroParameterTypeInt}, {Name: "f", Type: MacroParameterTypeFloat}, {Name: "b", Type: MacroParameterTypeBool}},
+			Statements: []string{`testing_getter(i)`, `testing_getter(f)`, `testing_getter(b)`},
+		},
+		{
+			Name:       "get_any",
+			Parameters: []MacroParameter{{Name: "value"}},
+			Statements: []string{`get_string("x") where value != nil`, `testing_getter(value)`},
+		},
+		{
+			Name:       "testing_noop",
+			Statements: []string{`testing_getter(name)`},
+		},
+		{
+			Name:       "shadow_path",
+			Parameters: []MacroParameter{{Name: "attributes"}},
+			Statements: []string{`testing_getter(attributes)`},
+		},
+		{
+			Name:       "loop",
+			Statements: []string{`loop()`},
+		},
+		{
+			Name:       "unknown_function",
+			Statements: []string{`unknown(name)`},
+		},
+	}
+	p, err := NewParser(
+		defaultFunctionsForTests(),
+		testParsePath[any],
+		componenttest.NewNopTelemetrySettings(),
+		WithEnumParser[any](testParseEnum),
+		WithMacros[any](macros),
+	)
+	require.NoError(t, err)
+
+	tests := []struct {
+		statement     string
+		expectedError string
+	}{
+		{statement: `get_string("x") where name == "y"`},
+		{statement: `get_string(value="x")`},
+		{statement: `get_path(name)`},
+		{statement: `get_numbers(1, 2, true)`},
+		{statement: `get_numbers(1, 2.5, b=false)`},
+		{statement: `get_any(1 + 2)`},
+		{statement: `get_any(SHA256("x"))`},
+		{statement: `get_string(1)`, expectedError: `invalid argument for parameter "value" of macro "get_string": expected a string but got "1"`},
+		{statement: `get_path("x")`, expectedError: `expected a path but got "\"x\""`},
+		{statement: `get_numbers(1.5, 2, true)`, expectedError: `expected a int but got "1.5"`},
+		{statement: `get_numbers(1, name, true)`, expectedError: `expected a float but got "name"`},
+		{statement: `get_numbers(1, 2, "true")`, expectedError: `expected a bool but got "\"true\""`},
+		{statement: `get_string()`, expectedError: `missing argument for parameter "value" of macro "get_string"`},
+		{statement: `get_string("x", "y")`, expectedError: `too many arguments for macro "get_string"`},
+		{statement: `get_string(other="x")`, expectedError: `undefined parameter "other" for macro "get_string"`},
+		{statement: `get_string("x", value="y")`, expectedError: `duplicate argument for parameter "value" of macro "get_string"`},
+		{statement: `get_numbers(i=1, 2, true)`, expectedError: `unnamed argument used after named argument`},
+		{statement: `testing_noop()`, expectedError: `macro "testing_noop" conflicts with the function of the same name`},
+		{statement: `shadow_path("x")`, expectedError: `parameter "attributes" of macro "shadow_path" conflicts with the path of the same name`},
+		{statement: `loop()`, expectedError: `macro cycle detected: loop -> loop`},
+		{statement: `unknown_function()`, expectedError: `macro "unknown_function" statement 0 "unknown(name)"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.statement, func(t *testing.T) {
+			_, err := p.ParseStatement(tt.statement)
+			if tt.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectedError)
+			}
+		})
+	}
+}
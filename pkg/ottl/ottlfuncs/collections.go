@@ -0,0 +1,84 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// listValues returns the raw elements of a list, which can be a pcommon.Slice, a pcommon.Value holding a slice or a Go slice.
+func listValues(target any) ([]any, error) {
+	switch t := target.(type) {
+	case pcommon.Slice:
+		return t.AsRaw(), nil
+	case pcommon.Value:
+		if t.Type() == pcommon.ValueTypeSlice {
+			return t.Slice().AsRaw(), nil
+		}
+		return nil, fmt.Errorf("expected a list but got a value of type %q", t.Type())
+	case []any:
+		return rawValues(t), nil
+	case []string:
+		return toAnySlice(t), nil
+	case []int64:
+		return toAnySlice(t), nil
+	case []float64:
+		return toAnySlice(t), nil
+	case []bool:
+		return toAnySlice(t), nil
+	default:
+		return nil, fmt.Errorf("expected a list but got %T", target)
+	}
+}
+
+func toAnySlice[T any](values []T) []any {
+	res := make([]any, len(values))
+	for i, v := range values {
+		res[i] = v
+	}
+	return res
+}
+
+func rawValues(values []any) []any {
+	res := make([]any, len(values))
+	for i, v := range values {
+		res[i] = rawValue(v)
+	}
+	return res
+}
+
+// rawValue converts pdata values to their raw representation so that they can be compared to the elements of a list.
+func rawValue(v any) any {
+	switch val := v.(type) {
+	case pcommon.Value:
+		return val.AsRaw()
+	case pcommon.Map:
+		return val.AsRaw()
+	case pcommon.Slice:
+		return val.AsRaw()
+	case int:
+		return int64(val)
+	case []any:
+		return rawValues(val)
+	default:
+		return v
+	}
+}
+
+// equalValues returns true if the raw values are equal. Integers and doubles are not equal to each other.
+func equalValues(a, b any) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+// newList converts raw values to a pcommon.Slice.
+func newList(values []any) (pcommon.Slice, error) {
+	res := pcommon.NewSlice()
+	if err := res.FromRaw(values); err != nil {
+		return pcommon.Slice{}, err
+	}
+	return res, nil
+}
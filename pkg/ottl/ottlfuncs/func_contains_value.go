@@ -0,0 +1,40 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type ContainsValueArguments[K any] struct {
+	Target ottl.Getter[K]
+	Value  ottl.Getter[K]
+}
+
+func NewContainsValueFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("ContainsValue", &ContainsValueArguments[K]{}, createContainsValueFunction[K])
+}
+
+func createContainsValueFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*ContainsValueArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("ContainsValueFactory args must be of type *ContainsValueArguments[K]")
+	}
+
+	return containsValue(args.Target, args.Value), nil
+}
+
+func containsValue[K any](target ottl.Getter[K], value ottl.Getter[K]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		index, err := listIndex(ctx, tCtx, target, value)
+		if err != nil {
+			return nil, err
+		}
+		return index >= 0, nil
+	}
+}
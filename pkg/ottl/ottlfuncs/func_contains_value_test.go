@@ -0,0 +1,53 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ContainsValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   any
+		value    any
+		expected bool
+	}{
+		{
+			name:     "contains",
+			target:   []string{"GET", "HEAD"},
+			value:    "HEAD",
+			expected: true,
+		},
+		{
+			name:     "does not contain",
+			target:   []string{"GET", "HEAD"},
+			value:    "POST",
+			expected: false,
+		},
+		{
+			name:     "bool",
+			target:   []bool{false},
+			value:    false,
+			expected: true,
+		},
+		{
+			name:     "nil",
+			target:   []any{"a"},
+			value:    nil,
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := containsValue(newLiteralGetter(tt.target), newLiteralGetter(tt.value))(context.Background(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
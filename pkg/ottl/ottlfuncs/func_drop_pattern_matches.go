@@ -0,0 +1,29 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"fmt"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type DropPatternMatchesArguments[K any] struct {
+	Target  ottl.Getter[K]
+	Pattern string
+}
+
+func NewDropPatternMatchesFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("DropPatternMatches", &DropPatternMatchesArguments[K]{}, createDropPatternMatchesFunction[K])
+}
+
+func createDropPatternMatchesFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*DropPatternMatchesArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("DropPatternMatchesFactory args must be of type *DropPatternMatchesArguments[K]")
+	}
+
+	return filterPatternMatches("DropPatternMatches", args.Target, args.Pattern, false)
+}
@@ -0,0 +1,31 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_DropPatternMatches(t *testing.T) {
+	target := pcommon.NewValueSlice()
+	require.NoError(t, target.Slice().FromRaw([]any{"internal.debug", "user", int64(1), "internal.trace"}))
+
+	exprFunc, err := createDropPatternMatchesFunction[any](ottl.FunctionContext{}, &DropPatternMatchesArguments[any]{
+		Target:  newLiteralGetter(target),
+		Pattern: `^internal\.`,
+	})
+	require.NoError(t, err)
+	result, err := exprFunc(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"user", int64(1)}, result.(pcommon.Slice).AsRaw())
+	// The target is not modified.
+	assert.Equal(t, 4, target.Slice().Len())
+}
@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type ExtractKeysArguments[K any] struct {
+	Target ottl.PMapGetter[K]
+}
+
+func NewExtractKeysFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("ExtractKeys", &ExtractKeysArguments[K]{}, createExtractKeysFunction[K])
+}
+
+func createExtractKeysFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*ExtractKeysArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("ExtractKeysFactory args must be of type *ExtractKeysArguments[K]")
+	}
+
+	return extractKeys(args.Target), nil
+}
+
+func extractKeys[K any](target ottl.PMapGetter[K]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		m, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		res := pcommon.NewSlice()
+		res.EnsureCapacity(m.Len())
+		m.Range(func(k string, _ pcommon.Value) bool {
+			res.AppendEmpty().SetStr(k)
+			return true
+		})
+		return res, nil
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func newMapGetter(m pcommon.Map) ottl.PMapGetter[any] {
+	return &ottl.StandardPMapGetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return m, nil
+		},
+	}
+}
+
+func Test_ExtractKeys(t *testing.T) {
+	m := pcommon.NewMap()
+	m.PutStr("a", "1")
+	m.PutInt("b", 2)
+
+	result, err := extractKeys(newMapGetter(m))(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"a", "b"}, result.(pcommon.Slice).AsRaw())
+
+	result, err = extractKeys(newMapGetter(pcommon.NewMap()))(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.(pcommon.Slice).Len())
+}
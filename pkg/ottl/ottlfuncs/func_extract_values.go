@@ -0,0 +1,47 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type ExtractValuesArguments[K any] struct {
+	Target ottl.PMapGetter[K]
+}
+
+func NewExtractValuesFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("ExtractValues", &ExtractValuesArguments[K]{}, createExtractValuesFunction[K])
+}
+
+func createExtractValuesFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*ExtractValuesArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("ExtractValuesFactory args must be of type *ExtractValuesArguments[K]")
+	}
+
+	return extractValues(args.Target), nil
+}
+
+func extractValues[K any](target ottl.PMapGetter[K]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		m, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		res := pcommon.NewSlice()
+		res.EnsureCapacity(m.Len())
+		m.Range(func(_ string, v pcommon.Value) bool {
+			v.CopyTo(res.AppendEmpty())
+			return true
+		})
+		return res, nil
+	}
+}
@@ -0,0 +1,24 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func Test_ExtractValues(t *testing.T) {
+	m := pcommon.NewMap()
+	m.PutStr("a", "1")
+	m.PutInt("b", 2)
+	m.PutEmptyMap("c").PutBool("d", true)
+
+	result, err := extractValues(newMapGetter(m))(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"1", int64(2), map[string]any{"d": true}}, result.(pcommon.Slice).AsRaw())
+}
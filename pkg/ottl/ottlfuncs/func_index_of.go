@@ -0,0 +1,59 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type IndexOfArguments[K any] struct {
+	Target ottl.Getter[K]
+	Value  ottl.Getter[K]
+}
+
+func NewIndexOfFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("IndexOf", &IndexOfArguments[K]{}, createIndexOfFunction[K])
+}
+
+func createIndexOfFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*IndexOfArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("IndexOfFactory args must be of type *IndexOfArguments[K]")
+	}
+
+	return indexOf(args.Target, args.Value), nil
+}
+
+func indexOf[K any](target ottl.Getter[K], value ottl.Getter[K]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		index, err := listIndex(ctx, tCtx, target, value)
+		if err != nil {
+			return nil, err
+		}
+		return int64(index), nil
+	}
+}
+
+// listIndex returns the index of the first element of the target list equal to the value, or -1.
+func listIndex[K any](ctx context.Context, tCtx K, target ottl.Getter[K], value ottl.Getter[K]) (int, error) {
+	val, err := target.Get(ctx, tCtx)
+	if err != nil {
+		return -1, err
+	}
+	values, err := listValues(val)
+	if err != nil {
+		return -1, err
+	}
+	v, err := value.Get(ctx, tCtx)
+	if err != nil {
+		return -1, err
+	}
+	v = rawValue(v)
+	return slices.IndexFunc(values, func(e any) bool { return equalValues(e, v) }), nil
+}
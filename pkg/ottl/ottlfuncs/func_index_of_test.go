@@ -0,0 +1,74 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func Test_IndexOf(t *testing.T) {
+	pSlice := pcommon.NewSlice()
+	require.NoError(t, pSlice.FromRaw([]any{"a", int64(1), []any{"x"}}))
+
+	tests := []struct {
+		name     string
+		target   any
+		value    any
+		expected int64
+	}{
+		{
+			name:     "string",
+			target:   pSlice,
+			value:    "a",
+			expected: 0,
+		},
+		{
+			name:     "int",
+			target:   pSlice,
+			value:    int64(1),
+			expected: 1,
+		},
+		{
+			name:     "pcommon.Value",
+			target:   pSlice,
+			value:    pcommon.NewValueStr("a"),
+			expected: 0,
+		},
+		{
+			name:     "nested list",
+			target:   pSlice,
+			value:    []any{"x"},
+			expected: 2,
+		},
+		{
+			name:     "double is not equal to int",
+			target:   pSlice,
+			value:    1.0,
+			expected: -1,
+		},
+		{
+			name:     "Go slice",
+			target:   []float64{1.5, 2.5},
+			value:    2.5,
+			expected: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := indexOf(newLiteralGetter(tt.target), newLiteralGetter(tt.value))(context.Background(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_IndexOf_Error(t *testing.T) {
+	_, err := indexOf(newLiteralGetter(pcommon.NewValueStr("a")), newLiteralGetter("a"))(context.Background(), nil)
+	assert.ErrorContains(t, err, `expected a list but got a value of type "Str"`)
+}
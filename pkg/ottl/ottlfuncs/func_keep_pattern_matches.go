@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type KeepPatternMatchesArguments[K any] struct {
+	Target  ottl.Getter[K]
+	Pattern string
+}
+
+func NewKeepPatternMatchesFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("KeepPatternMatches", &KeepPatternMatchesArguments[K]{}, createKeepPatternMatchesFunction[K])
+}
+
+func createKeepPatternMatchesFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*KeepPatternMatchesArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("KeepPatternMatchesFactory args must be of type *KeepPatternMatchesArguments[K]")
+	}
+
+	return filterPatternMatches("KeepPatternMatches", args.Target, args.Pattern, true)
+}
+
+// filterPatternMatches returns the elements of the list which string representation matches the pattern when keep is true,
+// or the elements which do not match otherwise. name is the name of the function, used in the errors.
+func filterPatternMatches[K any](name string, target ottl.Getter[K], pattern string, keep bool) (ottl.ExprFunc[K], error) {
+	compiledPattern, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("the regex pattern supplied to %s is not a valid pattern: %w", name, err)
+	}
+
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		values, err := listValues(val)
+		if err != nil {
+			return nil, err
+		}
+		res := make([]any, 0, len(values))
+		for _, v := range values {
+			s := pcommon.NewValueEmpty()
+			if err := s.FromRaw(v); err != nil {
+				return nil, err
+			}
+			if compiledPattern.MatchString(s.AsString()) == keep {
+				res = append(res, v)
+			}
+		}
+		return newList(res)
+	}, nil
+}
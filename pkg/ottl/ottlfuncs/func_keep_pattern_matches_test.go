@@ -0,0 +1,66 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_KeepPatternMatches(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   any
+		pattern  string
+		expected []any
+	}{
+		{
+			name:     "strings",
+			target:   []string{"team:payments", "env:prod", "team:search"},
+			pattern:  "^team:",
+			expected: []any{"team:payments", "team:search"},
+		},
+		{
+			name:     "string representation",
+			target:   []any{int64(200), int64(404), "500", true},
+			pattern:  "^[45]",
+			expected: []any{int64(404), "500"},
+		},
+		{
+			name:     "no match",
+			target:   []string{"a"},
+			pattern:  "b",
+			expected: []any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := filterPatternMatches("KeepPatternMatches", newLiteralGetter(tt.target), tt.pattern, true)
+			require.NoError(t, err)
+			result, err := exprFunc(context.Background(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.(pcommon.Slice).AsRaw())
+		})
+	}
+}
+
+func Test_KeepPatternMatches_InvalidPattern(t *testing.T) {
+	_, err := createKeepPatternMatchesFunction[any](ottl.FunctionContext{}, &KeepPatternMatchesArguments[any]{
+		Target:  newLiteralGetter([]string{}),
+		Pattern: "(",
+	})
+	assert.ErrorContains(t, err, "the regex pattern supplied to KeepPatternMatches is not a valid pattern")
+
+	_, err = createDropPatternMatchesFunction[any](ottl.FunctionContext{}, &DropPatternMatchesArguments[any]{
+		Target:  newLiteralGetter([]string{}),
+		Pattern: "(",
+	})
+	assert.ErrorContains(t, err, "the regex pattern supplied to DropPatternMatches is not a valid pattern")
+}
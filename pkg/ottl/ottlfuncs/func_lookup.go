@@ -0,0 +1,50 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type LookupArguments[K any] struct {
+	Table   string
+	Key     ottl.StringLikeGetter[K]
+	Default ottl.Optional[ottl.Getter[K]]
+}
+
+// NewLookupFactory returns the factory of the Lookup converter, which looks up keys in the given tables.
+func NewLookupFactory[K any](tables *LookupTables) ottl.Factory[K] {
+	return ottl.NewFactory("Lookup", &LookupArguments[K]{}, func(_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+		args, ok := oArgs.(*LookupArguments[K])
+		if !ok {
+			return nil, fmt.Errorf("LookupFactory args must be of type *LookupArguments[K]")
+		}
+		table, ok := tables.Get(args.Table)
+		if !ok {
+			return nil, fmt.Errorf("unknown lookup table %q", args.Table)
+		}
+		return lookup(table, args.Key, args.Default), nil
+	})
+}
+
+func lookup[K any](table *LookupTable, key ottl.StringLikeGetter[K], defaultValue ottl.Optional[ottl.Getter[K]]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		k, err := key.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		if k != nil {
+			if value, ok := table.Lookup(*k); ok {
+				return value, nil
+			}
+		}
+		if defaultValue.IsEmpty() {
+			return nil, nil
+		}
+		return defaultValue.Get().Get(ctx, tCtx)
+	}
+}
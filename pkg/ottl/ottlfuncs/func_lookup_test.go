@@ -0,0 +1,79 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_Lookup(t *testing.T) {
+	table := newLookupTable(t, LookupTableConfig{Path: writeLookupFile(t, "accounts.csv", "account,team\n1234,payments\n")})
+	defaultGetter := ottl.NewTestingOptional[ottl.Getter[any]](ottl.StandardGetSetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return "unknown", nil
+		},
+	})
+
+	tests := []struct {
+		name         string
+		key          any
+		defaultValue ottl.Optional[ottl.Getter[any]]
+		expected     any
+	}{
+		{
+			name:     "found",
+			key:      "1234",
+			expected: "payments",
+		},
+		{
+			name:     "int key",
+			key:      int64(1234),
+			expected: "payments",
+		},
+		{
+			name:         "default",
+			key:          "0000",
+			defaultValue: defaultGetter,
+			expected:     "unknown",
+		},
+		{
+			name:         "nil key",
+			key:          nil,
+			defaultValue: defaultGetter,
+			expected:     "unknown",
+		},
+		{
+			name: "no default",
+			key:  "0000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := ottl.StandardStringLikeGetter[any]{
+				Getter: func(context.Context, any) (any, error) {
+					return tt.key, nil
+				},
+			}
+			result, err := lookup[any](table, key, tt.defaultValue)(context.Background(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func Test_Lookup_UnknownTable(t *testing.T) {
+	tables, err := NewLookupTables(nil, zap.NewNop())
+	require.NoError(t, err)
+	factory := NewLookupFactory[any](tables)
+	_, err = factory.CreateFunction(ottl.FunctionContext{Set: componenttest.NewNopTelemetrySettings()}, &LookupArguments[any]{Table: "missing"})
+	assert.EqualError(t, err, `unknown lookup table "missing"`)
+}
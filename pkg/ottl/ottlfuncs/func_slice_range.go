@@ -0,0 +1,57 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+type SliceRangeArguments[K any] struct {
+	Target ottl.Getter[K]
+	Start  int64
+	End    ottl.Optional[int64]
+}
+
+func NewSliceRangeFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("SliceRange", &SliceRangeArguments[K]{}, createSliceRangeFunction[K])
+}
+
+func createSliceRangeFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*SliceRangeArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("SliceRangeFactory args must be of type *SliceRangeArguments[K]")
+	}
+
+	return sliceRange(args.Target, args.Start, args.End)
+}
+
+func sliceRange[K any](target ottl.Getter[K], start int64, end ottl.Optional[int64]) (ottl.ExprFunc[K], error) {
+	if start < 0 {
+		return nil, fmt.Errorf("invalid start for SliceRange function, %d cannot be negative", start)
+	}
+	if !end.IsEmpty() && end.Get() < start {
+		return nil, fmt.Errorf("invalid end for SliceRange function, %d cannot be lower than start %d", end.Get(), start)
+	}
+
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		values, err := listValues(val)
+		if err != nil {
+			return nil, err
+		}
+		length := int64(len(values))
+		from, to := min(start, length), length
+		if !end.IsEmpty() {
+			to = min(end.Get(), length)
+		}
+		return newList(values[from:to])
+	}, nil
+}
@@ -0,0 +1,72 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func Test_SliceRange(t *testing.T) {
+	target := []string{"a", "b", "c", "d"}
+
+	tests := []struct {
+		name     string
+		start    int64
+		end      ottl.Optional[int64]
+		expected []any
+	}{
+		{
+			name:     "range",
+			start:    1,
+			end:      ottl.NewTestingOptional[int64](3),
+			expected: []any{"b", "c"},
+		},
+		{
+			name:     "no end",
+			start:    2,
+			expected: []any{"c", "d"},
+		},
+		{
+			name:     "end clamped",
+			start:    3,
+			end:      ottl.NewTestingOptional[int64](10),
+			expected: []any{"d"},
+		},
+		{
+			name:     "start beyond length",
+			start:    5,
+			expected: []any{},
+		},
+		{
+			name:     "empty range",
+			start:    1,
+			end:      ottl.NewTestingOptional[int64](1),
+			expected: []any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := sliceRange(newLiteralGetter(target), tt.start, tt.end)
+			require.NoError(t, err)
+			result, err := exprFunc(context.Background(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.(pcommon.Slice).AsRaw())
+		})
+	}
+}
+
+func Test_SliceRange_Error(t *testing.T) {
+	_, err := sliceRange(newLiteralGetter([]string{}), -1, ottl.Optional[int64]{})
+	assert.ErrorContains(t, err, "cannot be negative")
+
+	_, err = sliceRange(newLiteralGetter([]string{}), 2, ottl.NewTestingOptional[int64](1))
+	assert.ErrorContains(t, err, "cannot be lower than start 2")
+}
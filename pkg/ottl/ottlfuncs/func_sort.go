@@ -0,0 +1,111 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+const (
+	sortAscending  = "asc"
+	sortDescending = "desc"
+)
+
+type SortArguments[K any] struct {
+	Target ottl.Getter[K]
+	Order  ottl.Optional[string]
+}
+
+func NewSortFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("Sort", &SortArguments[K]{}, createSortFunction[K])
+}
+
+func createSortFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*SortArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("SortFactory args must be of type *SortArguments[K]")
+	}
+
+	order := sortAscending
+	if !args.Order.IsEmpty() {
+		order = args.Order.Get()
+	}
+	if order != sortAscending && order != sortDescending {
+		return nil, fmt.Errorf("invalid order %q, must be %q or %q", order, sortAscending, sortDescending)
+	}
+
+	return sortList(args.Target, order), nil
+}
+
+func sortList[K any](target ottl.Getter[K], order string) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		values, err := listValues(val)
+		if err != nil {
+			return nil, err
+		}
+		slices.SortStableFunc(values, func(a, b any) int {
+			if order == sortDescending {
+				return compareValues(b, a)
+			}
+			return compareValues(a, b)
+		})
+		return newList(values)
+	}
+}
+
+// compareValues orders numbers, then strings, then booleans, then any other value by its string representation.
+func compareValues(a, b any) int {
+	rankA, rankB := sortRank(a), sortRank(b)
+	if rankA != rankB {
+		return cmp.Compare(rankA, rankB)
+	}
+	switch rankA {
+	case 0:
+		return cmp.Compare(toFloat(a), toFloat(b))
+	case 1:
+		return strings.Compare(a.(string), b.(string))
+	case 2:
+		return cmp.Compare(boolRank(a.(bool)), boolRank(b.(bool)))
+	default:
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+	}
+}
+
+func sortRank(v any) int {
+	switch v.(type) {
+	case int64, float64:
+		return 0
+	case string:
+		return 1
+	case bool:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func toFloat(v any) float64 {
+	if i, ok := v.(int64); ok {
+		return float64(i)
+	}
+	return v.(float64)
+}
+
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,88 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+)
+
+func newLiteralGetter(value any) ottl.Getter[any] {
+	return &ottl.StandardGetSetter[any]{
+		Getter: func(context.Context, any) (any, error) {
+			return value, nil
+		},
+	}
+}
+
+func Test_Sort(t *testing.T) {
+	pSlice := pcommon.NewSlice()
+	require.NoError(t, pSlice.FromRaw([]any{"b", "c", "a"}))
+	pValue := pcommon.NewValueSlice()
+	require.NoError(t, pValue.Slice().FromRaw([]any{int64(3), 1.5, int64(-2)}))
+
+	tests := []struct {
+		name     string
+		target   any
+		order    ottl.Optional[string]
+		expected []any
+	}{
+		{
+			name:     "pcommon.Slice of strings",
+			target:   pSlice,
+			expected: []any{"a", "b", "c"},
+		},
+		{
+			name:     "pcommon.Value of numbers",
+			target:   pValue,
+			expected: []any{int64(-2), 1.5, int64(3)},
+		},
+		{
+			name:     "descending",
+			target:   []int64{1, 3, 2},
+			order:    ottl.NewTestingOptional[string]("desc"),
+			expected: []any{int64(3), int64(2), int64(1)},
+		},
+		{
+			name:     "booleans",
+			target:   []bool{true, false, true},
+			expected: []any{false, true, true},
+		},
+		{
+			name:     "mixed types",
+			target:   []any{"b", true, int64(2), "a", 1.5, false},
+			expected: []any{1.5, int64(2), "a", "b", false, true},
+		},
+		{
+			name:     "empty",
+			target:   []string{},
+			expected: []any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exprFunc, err := createSortFunction[any](ottl.FunctionContext{}, &SortArguments[any]{Target: newLiteralGetter(tt.target), Order: tt.order})
+			require.NoError(t, err)
+			result, err := exprFunc(context.Background(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.(pcommon.Slice).AsRaw())
+		})
+	}
+}
+
+func Test_Sort_Error(t *testing.T) {
+	_, err := createSortFunction[any](ottl.FunctionContext{}, &SortArguments[any]{Target: newLiteralGetter([]string{}), Order: ottl.NewTestingOptional[string]("random")})
+	assert.ErrorContains(t, err, `invalid order "random"`)
+
+	exprFunc, err := createSortFunction[any](ottl.FunctionContext{}, &SortArguments[any]{Target: newLiteralGetter("not a list")})
+	require.NoError(t, err)
+	_, err = exprFunc(context.Background(), nil)
+	assert.ErrorContains(t, err, "expected a list but got string")
+}
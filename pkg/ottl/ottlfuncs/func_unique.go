@@ -0,0 +1,60 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/pdatautil"
+)
+
+type UniqueArguments[K any] struct {
+	Target ottl.Getter[K]
+}
+
+func NewUniqueFactory[K any]() ottl.Factory[K] {
+	return ottl.NewFactory("Unique", &UniqueArguments[K]{}, createUniqueFunction[K])
+}
+
+func createUniqueFunction[K any](_ ottl.FunctionContext, oArgs ottl.Arguments) (ottl.ExprFunc[K], error) {
+	args, ok := oArgs.(*UniqueArguments[K])
+
+	if !ok {
+		return nil, fmt.Errorf("UniqueFactory args must be of type *UniqueArguments[K]")
+	}
+
+	return unique(args.Target), nil
+}
+
+func unique[K any](target ottl.Getter[K]) ottl.ExprFunc[K] {
+	return func(ctx context.Context, tCtx K) (any, error) {
+		val, err := target.Get(ctx, tCtx)
+		if err != nil {
+			return nil, err
+		}
+		values, err := listValues(val)
+		if err != nil {
+			return nil, err
+		}
+		res, err := newList(values)
+		if err != nil {
+			return nil, err
+		}
+		// the elements are compared by hash, which includes their type, rather than one to another
+		seen := make(map[[16]byte]struct{}, res.Len())
+		res.RemoveIf(func(v pcommon.Value) bool {
+			hash := pdatautil.ValueHash(v)
+			if _, ok := seen[hash]; ok {
+				return true
+			}
+			seen[hash] = struct{}{}
+			return false
+		})
+		return res, nil
+	}
+}
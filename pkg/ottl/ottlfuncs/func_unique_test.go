@@ -0,0 +1,54 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func Test_Unique(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   any
+		expected []any
+	}{
+		{
+			name:     "strings",
+			target:   []string{"b", "a", "b", "c", "a"},
+			expected: []any{"b", "a", "c"},
+		},
+		{
+			name:     "values of different types are different",
+			target:   []any{int64(1), 1.0, "1", 1, map[string]any{"a": "b"}, map[string]any{"a": "b"}},
+			expected: []any{int64(1), 1.0, "1", map[string]any{"a": "b"}},
+		},
+		{
+			name:     "nested lists and maps",
+			target:   []any{[]any{"a", int64(1)}, map[string]any{"a": []any{"b"}, "c": "d"}, []any{int64(1), "a"}, []any{"a", int64(1)}, map[string]any{"c": "d", "a": []any{"b"}}},
+			expected: []any{[]any{"a", int64(1)}, map[string]any{"a": []any{"b"}, "c": "d"}, []any{int64(1), "a"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := unique(newLiteralGetter(tt.target))(context.Background(), nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.(pcommon.Slice).AsRaw())
+		})
+	}
+}
+
+func Test_Unique_DoesNotModifyTarget(t *testing.T) {
+	target := pcommon.NewSlice()
+	require.NoError(t, target.FromRaw([]any{"a", "a", "b"}))
+
+	result, err := unique(newLiteralGetter(target))(context.Background(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, []any{"a", "b"}, result.(pcommon.Slice).AsRaw())
+	assert.Equal(t, []any{"a", "a", "b"}, target.AsRaw())
+}
@@ -38,14 +38,19 @@ func converters[K any]() []ottl.Factory[K] {
 		// Converters
 		NewBase64DecodeFactory[K](),
 		NewConcatFactory[K](),
+		NewContainsValueFactory[K](),
 		NewConvertCaseFactory[K](),
 		NewDayFactory[K](),
 		NewDoubleFactory[K](),
+		NewDropPatternMatchesFactory[K](),
 		NewDurationFactory[K](),
+		NewExtractKeysFactory[K](),
 		NewExtractPatternsFactory[K](),
+		NewExtractValuesFactory[K](),
 		NewFnvFactory[K](),
 		NewHourFactory[K](),
 		NewHoursFactory[K](),
+		NewIndexOfFactory[K](),
 		NewIntFactory[K](),
 		NewIsBoolFactory[K](),
 		NewIsDoubleFactory[K](),
@@ -54,6 +59,7 @@ func converters[K any]() []ottl.Factory[K] {
 		NewIsMapFactory[K](),
 		NewIsMatchFactory[K](),
 		NewIsStringFactory[K](),
+		NewKeepPatternMatchesFactory[K](),
 		NewLenFactory[K](),
 		NewLogFactory[K](),
 		NewMicrosecondsFactory[K](),
@@ -70,6 +76,8 @@ func converters[K any]() []ottl.Factory[K] {
 		NewSecondsFactory[K](),
 		NewSHA1Factory[K](),
 		NewSHA256Factory[K](),
+		NewSliceRangeFactory[K](),
+		NewSortFactory[K](),
 		NewSpanIDFactory[K](),
 		NewSplitFactory[K](),
 		NewStringFactory[K](),
@@ -77,6 +85,7 @@ func converters[K any]() []ottl.Factory[K] {
 		NewTimeFactory[K](),
 		NewTruncateTimeFactory[K](),
 		NewTraceIDFactory[K](),
+		NewUniqueFactory[K](),
 		NewUnixFactory[K](),
 		NewUnixMicroFactory[K](),
 		NewUnixMilliFactory[K](),
@@ -0,0 +1,511 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+)
+
+// LookupTableFormat is the format of the file backing a lookup table.
+type LookupTableFormat string
+
+const (
+	// LookupTableFormatCSV reads the table from a CSV file with a header row.
+	LookupTableFormatCSV LookupTableFormat = "csv"
+	// LookupTableFormatJSON reads the table from a JSON object mapping keys to values.
+	LookupTableFormatJSON LookupTableFormat = "json"
+)
+
+// LookupMatchMode determines how the keys of a lookup table are matched.
+type LookupMatchMode string
+
+const (
+	// LookupMatchExact matches keys which are equal to the looked up key.
+	LookupMatchExact LookupMatchMode = "exact"
+	// LookupMatchPrefix matches the longest key which is a prefix of the looked up key.
+	LookupMatchPrefix LookupMatchMode = "prefix"
+	// LookupMatchCIDR matches the most specific network, in CIDR notation, containing the looked up IP address.
+	LookupMatchCIDR LookupMatchMode = "cidr"
+)
+
+const defaultLookupReloadInterval = 30 * time.Second
+
+// LookupTableConfig defines a lookup table loaded from a local file.
+type LookupTableConfig struct {
+	// Name is the name used to reference the table in the Lookup converter.
+	Name string `mapstructure:"name"`
+	// Path is the path of the file backing the table.
+	Path string `mapstructure:"path"`
+	// Format is the format of the file. Defaults to the format matching the file extension.
+	Format LookupTableFormat `mapstructure:"format"`
+	// Match is how keys are matched. Defaults to `exact`.
+	Match LookupMatchMode `mapstructure:"match"`
+	// KeyColumn is the name of the CSV column holding the keys. Defaults to the first column.
+	KeyColumn string `mapstructure:"key_column"`
+	// ValueColumn is the name of the CSV column holding the values. Defaults to the second column.
+	ValueColumn string `mapstructure:"value_column"`
+	// ReloadInterval is the interval between checks of the file for changes. Defaults to 30s.
+	// A negative value disables reloading.
+	ReloadInterval time.Duration `mapstructure:"reload_interval"`
+}
+
+func (c LookupTableConfig) format() LookupTableFormat {
+	if c.Format != "" {
+		return c.Format
+	}
+	return LookupTableFormat(strings.TrimPrefix(strings.ToLower(filepath.Ext(c.Path)), "."))
+}
+
+func (c LookupTableConfig) match() LookupMatchMode {
+	if c.Match != "" {
+		return c.Match
+	}
+	return LookupMatchExact
+}
+
+func (c LookupTableConfig) reloadInterval() time.Duration {
+	if c.ReloadInterval == 0 {
+		return defaultLookupReloadInterval
+	}
+	return c.ReloadInterval
+}
+
+// Validate checks the lookup table configuration without reading the file.
+func (c LookupTableConfig) Validate() error {
+	var errs []error
+	if c.Name == "" {
+		errs = append(errs, errors.New("name is required"))
+	}
+	if c.Path == "" {
+		errs = append(errs, errors.New("path is required"))
+	}
+	switch c.format() {
+	case LookupTableFormatCSV:
+	case LookupTableFormatJSON:
+		if c.KeyColumn != "" || c.ValueColumn != "" {
+			errs = append(errs, errors.New("key_column and value_column are only supported for the csv format"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unsupported format %q, must be one of %q or %q", c.format(), LookupTableFormatCSV, LookupTableFormatJSON))
+	}
+	switch c.match() {
+	case LookupMatchExact, LookupMatchPrefix, LookupMatchCIDR:
+	default:
+		errs = append(errs, fmt.Errorf("unsupported match mode %q, must be one of %q, %q or %q", c.Match, LookupMatchExact, LookupMatchPrefix, LookupMatchCIDR))
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("invalid lookup table %q: %w", c.Name, err)
+	}
+	return nil
+}
+
+// LookupTables is a set of named lookup tables.
+type LookupTables struct {
+	tables map[string]*LookupTable
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewLookupTables validates the given configurations and returns the tables they define.
+// The tables are empty until Load is called, and they're reloaded once Start is called.
+func NewLookupTables(configs []LookupTableConfig, logger *zap.Logger) (*LookupTables, error) {
+	tables := &LookupTables{tables: make(map[string]*LookupTable, len(configs))}
+	var errs []error
+	for _, config := range configs {
+		if err := config.Validate(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if _, ok := tables.tables[config.Name]; ok {
+			errs = append(errs, fmt.Errorf("duplicate lookup table %q", config.Name))
+			continue
+		}
+		tables.tables[config.Name] = &LookupTable{
+			config: config,
+			logger: logger.With(zap.String("lookup_table", config.Name)),
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return tables, nil
+}
+
+// Load reads the files backing every table.
+func (t *LookupTables) Load() error {
+	var errs []error
+	for _, table := range t.tables {
+		errs = append(errs, table.load())
+	}
+	return errors.Join(errs...)
+}
+
+// Start checks the files backing the tables for changes in the background, at the reload interval of each table,
+// until Shutdown is called.
+func (t *LookupTables) Start() {
+	if t == nil || t.done != nil {
+		return
+	}
+	t.done = make(chan struct{})
+	for _, table := range t.tables {
+		interval := table.config.reloadInterval()
+		if interval < 0 {
+			continue
+		}
+		t.wg.Add(1)
+		go func(table *LookupTable) {
+			defer t.wg.Done()
+			table.reloadLoop(interval, t.done)
+		}(table)
+	}
+}
+
+// Shutdown stops checking the files backing the tables for changes.
+func (t *LookupTables) Shutdown() {
+	if t == nil || t.done == nil {
+		return
+	}
+	close(t.done)
+	t.wg.Wait()
+	t.done = nil
+}
+
+// Get returns the table with the given name.
+func (t *LookupTables) Get(name string) (*LookupTable, bool) {
+	if t == nil {
+		return nil, false
+	}
+	table, ok := t.tables[name]
+	return table, ok
+}
+
+// LookupTable maps keys to values read from a file. The file is checked for changes in the background, and the
+// table is replaced if the file changed and can be read, so that looking up keys never waits for the file.
+type LookupTable struct {
+	config LookupTableConfig
+	logger *zap.Logger
+	data   atomic.Pointer[lookupData]
+}
+
+type lookupData struct {
+	entries lookupEntries
+	modTime time.Time
+	size    int64
+}
+
+// Lookup returns the value matching the key.
+func (t *LookupTable) Lookup(key string) (any, bool) {
+	data := t.data.Load()
+	if data == nil {
+		return nil, false
+	}
+	value, ok := data.entries.lookup(key)
+	if !ok {
+		return nil, false
+	}
+	return lookupValue(value), true
+}
+
+func (t *LookupTable) load() error {
+	info, err := os.Stat(t.config.Path)
+	if err != nil {
+		return fmt.Errorf("failed to load lookup table %q: %w", t.config.Name, err)
+	}
+	entries, err := readLookupEntries(t.config)
+	if err != nil {
+		return fmt.Errorf("failed to load lookup table %q: %w", t.config.Name, err)
+	}
+	t.data.Store(&lookupData{entries: entries, modTime: info.ModTime(), size: info.Size()})
+	return nil
+}
+
+func (t *LookupTable) reloadLoop(interval time.Duration, done <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			t.reload()
+		}
+	}
+}
+
+// reload replaces the table when the file changed since it was last read.
+func (t *LookupTable) reload() {
+	info, err := os.Stat(t.config.Path)
+	if err != nil {
+		t.logger.Warn("Failed to check lookup table file for changes", zap.Error(err))
+		return
+	}
+	if data := t.data.Load(); data != nil && data.modTime.Equal(info.ModTime()) && data.size == info.Size() {
+		return
+	}
+	if err := t.load(); err != nil {
+		t.logger.Warn("Failed to reload lookup table, keeping the previous version", zap.Error(err))
+		return
+	}
+	t.logger.Info("Reloaded lookup table")
+}
+
+func readLookupEntries(config LookupTableConfig) (lookupEntries, error) {
+	content, err := os.ReadFile(config.Path)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]any
+	switch config.format() {
+	case LookupTableFormatCSV:
+		values, err = readLookupCSV(content, config.KeyColumn, config.ValueColumn)
+	default:
+		values, err = readLookupJSON(content)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch config.match() {
+	case LookupMatchPrefix:
+		return newPrefixEntries(values), nil
+	case LookupMatchCIDR:
+		return newCIDREntries(values)
+	default:
+		return exactEntries(values), nil
+	}
+}
+
+func readLookupCSV(content []byte, keyColumn, valueColumn string) (map[string]any, error) {
+	reader := csv.NewReader(bytes.NewReader(content))
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the CSV header: %w", err)
+	}
+	keyIndex, err := lookupColumnIndex(header, keyColumn, 0)
+	if err != nil {
+		return nil, err
+	}
+	valueIndex, err := lookupColumnIndex(header, valueColumn, 1)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]any)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := values[record[keyIndex]]; ok {
+			return nil, fmt.Errorf("duplicate key %q", record[keyIndex])
+		}
+		values[record[keyIndex]] = record[valueIndex]
+	}
+}
+
+func lookupColumnIndex(header []string, column string, defaultIndex int) (int, error) {
+	if column == "" {
+		if defaultIndex >= len(header) {
+			return 0, fmt.Errorf("the CSV header must have at least %d columns", defaultIndex+1)
+		}
+		return defaultIndex, nil
+	}
+	index := slices.Index(header, column)
+	if index < 0 {
+		return 0, fmt.Errorf("column %q not found in the CSV header", column)
+	}
+	return index, nil
+}
+
+func readLookupJSON(content []byte) (map[string]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	var values map[string]any
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("failed to decode the JSON object: %w", err)
+	}
+	return values, nil
+}
+
+// lookupValue converts a value read from a table file to an OTTL value. Maps and slices are copied
+// on every lookup so that statements cannot modify the table.
+func lookupValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		m := pcommon.NewMap()
+		_ = m.FromRaw(normalizeJSONNumbers(v).(map[string]any))
+		return m
+	case []any:
+		s := pcommon.NewSlice()
+		_ = s.FromRaw(normalizeJSONNumbers(v).([]any))
+		return s
+	default:
+		return v
+	}
+}
+
+func normalizeJSONNumbers(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		return lookupValue(v)
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for key, value := range v {
+			m[key] = normalizeJSONNumbers(value)
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, value := range v {
+			s[i] = normalizeJSONNumbers(value)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+type lookupEntries interface {
+	lookup(key string) (any, bool)
+}
+
+type exactEntries map[string]any
+
+func (e exactEntries) lookup(key string) (any, bool) {
+	value, ok := e[key]
+	return value, ok
+}
+
+// prefixEntries finds the longest matching prefix by checking the prefixes of the key for every key length of the table.
+type prefixEntries struct {
+	values  map[string]any
+	lengths []int
+}
+
+func newPrefixEntries(values map[string]any) *prefixEntries {
+	e := &prefixEntries{values: values}
+	for key := range values {
+		if !slices.Contains(e.lengths, len(key)) {
+			e.lengths = append(e.lengths, len(key))
+		}
+	}
+	slices.Sort(e.lengths)
+	slices.Reverse(e.lengths)
+	return e
+}
+
+func (e *prefixEntries) lookup(key string) (any, bool) {
+	for _, length := range e.lengths {
+		if length > len(key) {
+			continue
+		}
+		if value, ok := e.values[key[:length]]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+// cidrEntries finds the most specific network by checking the networks of the address for every prefix length of the table.
+type cidrEntries struct {
+	values map[netip.Prefix]any
+	bits4  []int
+	bits6  []int
+}
+
+func newCIDREntries(values map[string]any) (*cidrEntries, error) {
+	e := &cidrEntries{values: make(map[netip.Prefix]any, len(values))}
+	for key, value := range values {
+		prefix, err := parseLookupPrefix(key)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := e.values[prefix]; ok {
+			return nil, fmt.Errorf("duplicate network %q", key)
+		}
+		e.values[prefix] = value
+		bits := &e.bits6
+		if prefix.Addr().Is4() {
+			bits = &e.bits4
+		}
+		if !slices.Contains(*bits, prefix.Bits()) {
+			*bits = append(*bits, prefix.Bits())
+		}
+	}
+	for _, bits := range [][]int{e.bits4, e.bits6} {
+		slices.Sort(bits)
+		slices.Reverse(bits)
+	}
+	return e, nil
+}
+
+// parseLookupPrefix parses a network in CIDR notation, or a single IP address.
+func parseLookupPrefix(key string) (netip.Prefix, error) {
+	if strings.Contains(key, "/") {
+		prefix, err := netip.ParsePrefix(key)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+			return netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96).Masked(), nil
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(key)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func (e *cidrEntries) lookup(key string) (any, bool) {
+	addr, err := netip.ParseAddr(key)
+	if err != nil {
+		return nil, false
+	}
+	addr = addr.Unmap()
+	bits := e.bits6
+	if addr.Is4() {
+		bits = e.bits4
+	}
+	for _, b := range bits {
+		prefix, err := addr.Prefix(b)
+		if err != nil {
+			continue
+		}
+		if value, ok := e.values[prefix]; ok {
+			return value, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,306 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package ottlfuncs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+)
+
+func writeLookupFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func newLookupTable(t *testing.T, config LookupTableConfig) *LookupTable {
+	config.Name = "test"
+	tables, err := NewLookupTables([]LookupTableConfig{config}, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, tables.Load())
+	tables.Start()
+	t.Cleanup(tables.Shutdown)
+	table, ok := tables.Get("test")
+	require.True(t, ok)
+	return table
+}
+
+func Test_LookupTable(t *testing.T) {
+	accounts := "account,team,region\n1234,payments,eu\n5678,search,us\n"
+	sites := `{"10.0.0.0/8": "internal", "10.1.0.0/16": "dc1", "10.1.2.3": "gateway", "2001:db8::/32": "v6"}`
+	prefixes := `{"/api": "api", "/api/v2": "api_v2", "/": "root"}`
+	objects := `{"a": {"team": "payments", "replicas": 3, "ratio": 0.5, "tags": ["x", 1]}, "b": true, "c": 12}`
+
+	tests := []struct {
+		name     string
+		config   LookupTableConfig
+		key      string
+		expected any
+		found    bool
+	}{
+		{
+			name:     "csv default columns",
+			config:   LookupTableConfig{Path: writeLookupFile(t, "accounts.csv", accounts)},
+			key:      "1234",
+			expected: "payments",
+			found:    true,
+		},
+		{
+			name:     "csv named columns",
+			config:   LookupTableConfig{Path: writeLookupFile(t, "accounts.csv", accounts), KeyColumn: "team", ValueColumn: "region"},
+			key:      "search",
+			expected: "us",
+			found:    true,
+		},
+		{
+			name:   "csv missing key",
+			config: LookupTableConfig{Path: writeLookupFile(t, "accounts.csv", accounts)},
+			key:    "0000",
+		},
+		{
+			name:     "cidr most specific network",
+			config:   LookupTableConfig{Path: writeLookupFile(t, "sites.json", sites), Match: LookupMatchCIDR},
+			key:      "10.1.9.9",
+			expected: "dc1",
+			found:    true,
+		},
+		{
+			name:     "cidr single address",
+			config:   LookupTableConfig{Path: writeLookupFile(t, "sites.json", sites), Match: LookupMatchCIDR},
+			key:      "10.1.2.3",
+			expected: "gateway",
+			found:    true,
+		},
+		{
+			name:     "cidr ipv4 mapped address",
+			config:   LookupTableConfig{Path: writeLookupFile(t, "sites.json", sites), Match: LookupMatchCIDR},
+			key:      "::ffff:10.200.0.1",
+			expected: "internal",
+			found:    true,
+		},
+		{
+			name:     "cidr ipv6",
+			config:   LookupTableConfig{Path: writeLookupFile(t, "sites.json", sites), Match: LookupMatchCIDR},
+			key:      "2001:db8::1",
+			expected: "v6",
+			found:    true,
+		},
+		{
+			name:   "cidr no match",
+			config: LookupTableConfig{Path: writeLookupFile(t, "sites.json", sites), Match: LookupMatchCIDR},
+			key:    "192.168.0.1",
+		},
+		{
+			name:   "cidr invalid address",
+			config: LookupTableConfig{Path: writeLookupFile(t, "sites.json", sites), Match: LookupMatchCIDR},
+			key:    "not an address",
+		},
+		{
+			name:     "prefix longest match",
+			config:   LookupTableConfig{Path: writeLookupFile(t, "prefixes.json", prefixes), Match: LookupMatchPrefix},
+			key:      "/api/v2/users",
+			expected: "api_v2",
+			found:    true,
+		},
+		{
+			name:     "prefix shorter match",
+			config:   LookupTableConfig{Path: writeLookupFile(t, "prefixes.json", prefixes), Match: LookupMatchPrefix},
+			key:      "/health",
+			expected: "root",
+			found:    true,
+		},
+		{
+			name: "json map value",
+			config: LookupTableConfig{
+				Path:   writeLookupFile(t, "objects", objects),
+				Format: LookupTableFormatJSON,
+			},
+			key: "a",
+			expected: func() pcommon.Map {
+				m := pcommon.NewMap()
+				m.PutStr("team", "payments")
+				m.PutInt("replicas", 3)
+				m.PutDouble("ratio", 0.5)
+				s := m.PutEmptySlice("tags")
+				s.AppendEmpty().SetStr("x")
+				s.AppendEmpty().SetInt(1)
+				return m
+			}(),
+			found: true,
+		},
+		{
+			name:     "json bool value",
+			config:   LookupTableConfig{Path: writeLookupFile(t, "objects.json", objects)},
+			key:      "b",
+			expected: true,
+			found:    true,
+		},
+		{
+			name:     "json int value",
+			config:   LookupTableConfig{Path: writeLookupFile(t, "objects.json", objects)},
+			key:      "c",
+			expected: int64(12),
+			found:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, found := newLookupTable(t, tt.config).Lookup(tt.key)
+			assert.Equal(t, tt.found, found)
+			if expected, ok := tt.expected.(pcommon.Map); ok {
+				require.IsType(t, pcommon.Map{}, value)
+				assert.Equal(t, expected.AsRaw(), value.(pcommon.Map).AsRaw())
+				return
+			}
+			assert.Equal(t, tt.expected, value)
+		})
+	}
+}
+
+func Test_LookupTable_Reload(t *testing.T) {
+	path := writeLookupFile(t, "accounts.csv", "account,team\n1234,payments\n")
+	table := newLookupTable(t, LookupTableConfig{Path: path, ReloadInterval: time.Millisecond})
+
+	value, found := table.Lookup("1234")
+	assert.True(t, found)
+	assert.Equal(t, "payments", value)
+
+	require.NoError(t, os.WriteFile(path, []byte("account,team\n1234,checkout\n5678,search\n"), 0o600))
+	assert.Eventually(t, func() bool {
+		value, _ := table.Lookup("1234")
+		return value == "checkout"
+	}, 5*time.Second, 5*time.Millisecond)
+
+	// An invalid file keeps the previous version of the table.
+	require.NoError(t, os.WriteFile(path, []byte("account\n1234\n"), 0o600))
+	time.Sleep(10 * time.Millisecond)
+	value, found = table.Lookup("5678")
+	assert.True(t, found)
+	assert.Equal(t, "search", value)
+}
+
+func Test_LookupTable_NoReload(t *testing.T) {
+	path := writeLookupFile(t, "accounts.csv", "account,team\n1234,payments\n")
+	table := newLookupTable(t, LookupTableConfig{Path: path, ReloadInterval: -1})
+
+	require.NoError(t, os.WriteFile(path, []byte("account,team\n1234,checkout\n"), 0o600))
+	time.Sleep(10 * time.Millisecond)
+	value, _ := table.Lookup("1234")
+	assert.Equal(t, "payments", value)
+}
+
+func Test_LookupTables_Shutdown(t *testing.T) {
+	path := writeLookupFile(t, "accounts.csv", "account,team\n1234,payments\n")
+	tables, err := NewLookupTables([]LookupTableConfig{{Name: "test", Path: path, ReloadInterval: time.Millisecond}}, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, tables.Load())
+	tables.Start()
+	tables.Shutdown()
+	tables.Shutdown()
+
+	require.NoError(t, os.WriteFile(path, []byte("account,team\n1234,checkout\n"), 0o600))
+	time.Sleep(10 * time.Millisecond)
+	table, _ := tables.Get("test")
+	value, _ := table.Lookup("1234")
+	assert.Equal(t, "payments", value)
+}
+
+func Test_NewLookupTables_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		configs       []LookupTableConfig
+		expectedError string
+	}{
+		{
+			name:          "missing name and path",
+			configs:       []LookupTableConfig{{Format: LookupTableFormatCSV}},
+			expectedError: "name is required\npath is required",
+		},
+		{
+			name:          "unknown format",
+			configs:       []LookupTableConfig{{Name: "test", Path: "table.txt"}},
+			expectedError: `unsupported format "txt"`,
+		},
+		{
+			name:          "unknown match mode",
+			configs:       []LookupTableConfig{{Name: "test", Path: "table.csv", Match: "regex"}},
+			expectedError: `unsupported match mode "regex"`,
+		},
+		{
+			name:          "columns with json",
+			configs:       []LookupTableConfig{{Name: "test", Path: "table.json", KeyColumn: "key"}},
+			expectedError: "key_column and value_column are only supported for the csv format",
+		},
+		{
+			name:          "duplicate table",
+			configs:       []LookupTableConfig{{Name: "test", Path: "table.csv"}, {Name: "test", Path: "other.csv"}},
+			expectedError: `duplicate lookup table "test"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLookupTables(tt.configs, zap.NewNop())
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
+
+func Test_LookupTables_Load_Errors(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        LookupTableConfig
+		expectedError string
+	}{
+		{
+			name:          "missing file",
+			config:        LookupTableConfig{Path: filepath.Join(t.TempDir(), "missing.csv")},
+			expectedError: "no such file or directory",
+		},
+		{
+			name:          "missing column",
+			config:        LookupTableConfig{Path: writeLookupFile(t, "table.csv", "key,value\na,b\n"), ValueColumn: "team"},
+			expectedError: `column "team" not found in the CSV header`,
+		},
+		{
+			name:          "single column",
+			config:        LookupTableConfig{Path: writeLookupFile(t, "table.csv", "key\na\n")},
+			expectedError: "the CSV header must have at least 2 columns",
+		},
+		{
+			name:          "duplicate key",
+			config:        LookupTableConfig{Path: writeLookupFile(t, "table.csv", "key,value\na,b\na,c\n")},
+			expectedError: `duplicate key "a"`,
+		},
+		{
+			name:          "invalid json",
+			config:        LookupTableConfig{Path: writeLookupFile(t, "table.json", `["a"]`)},
+			expectedError: "failed to decode the JSON object",
+		},
+		{
+			name:          "invalid network",
+			config:        LookupTableConfig{Path: writeLookupFile(t, "table.json", `{"10.0.0.0/40": "a"}`), Match: LookupMatchCIDR},
+			expectedError: `failed to load lookup table "test"`,
+		},
+		{
+			name:          "duplicate network",
+			config:        LookupTableConfig{Path: writeLookupFile(t, "table.json", `{"10.0.0.0/8": "a", "10.1.0.0/8": "b"}`), Match: LookupMatchCIDR},
+			expectedError: "duplicate network",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.Name = "test"
+			tables, err := NewLookupTables([]LookupTableConfig{tt.config}, zap.NewNop())
+			require.NoError(t, err)
+			assert.ErrorContains(t, tables.Load(), tt.expectedError)
+		})
+	}
+}
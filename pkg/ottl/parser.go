@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"github.com/alecthomas/participle/v2"
 	"go.opentelemetry.io/collector/component"
@@ -19,6 +20,12 @@ type Statement[K any] struct {
 	function  Expr[K]
 	condition BoolExpr[K]
 	origText  string
+	// newPaths builds the paths passed to the function, which values are recorded in explain mode.
+	// They're only built the first time the statement is explained.
+	newPaths  func() ([]explainedPath[K], error)
+	pathsOnce sync.Once
+	paths     []explainedPath[K]
+	pathsErr  error
 }
 
 // Execute is a function that will execute the statement's function if the statement's condition is met.
@@ -51,6 +58,18 @@ func (c *Condition[K]) Eval(ctx context.Context, tCtx K) (bool, error) {
 	return c.condition.Eval(ctx, tCtx)
 }
 
+// ValueExpression holds a top level value expression, such as a path, a converter invocation, a literal
+// or a math expression, which can be evaluated against telemetry to produce a value.
+type ValueExpression[K any] struct {
+	getter   Getter[K]
+	origText string
+}
+
+// Eval evaluates the value expression for the given TransformContext and returns the resulting value.
+func (v *ValueExpression[K]) Eval(ctx context.Context, tCtx K) (any, error) {
+	return v.getter.Get(ctx, tCtx)
+}
+
 // Parser provides the means to parse OTTL StatementSequence and Conditions given a specific set of functions,
 // a PathExpressionParser, and an EnumParser.
 type Parser[K any] struct {
@@ -58,6 +77,7 @@ type Parser[K any] struct {
 	pathParser        PathExpressionParser[K]
 	enumParser        EnumParser
 	telemetrySettings component.TelemetrySettings
+	macros            map[string]Macro
 }
 
 func NewParser[K any](
@@ -118,10 +138,17 @@ func (p *Parser[K]) ParseStatements(statements []string) ([]*Statement[K], error
 // Returns a Statement and a nil error on successful parsing.
 // If parsing fails, returns nil and an error.
 func (p *Parser[K]) ParseStatement(statement string) (*Statement[K], error) {
+	return p.parseStatement(statement, nil)
+}
+
+func (p *Parser[K]) parseStatement(statement string, expanding []string) (*Statement[K], error) {
 	parsed, err := parseStatement(statement)
 	if err != nil {
 		return nil, err
 	}
+	if macro, ok := p.macros[parsed.Editor.Function]; ok {
+		return p.expandMacro(macro, statement, parsed, expanding)
+	}
 	function, err := p.newFunctionCall(parsed.Editor)
 	if err != nil {
 		return nil, err
@@ -134,6 +161,9 @@ func (p *Parser[K]) ParseStatement(statement string) (*Statement[K], error) {
 		function:  function,
 		condition: expression,
 		origText:  statement,
+		newPaths: func() ([]explainedPath[K], error) {
+			return p.newExplainedPaths(parsed.Editor)
+		},
 	}, nil
 }
 
@@ -178,8 +208,27 @@ func (p *Parser[K]) ParseCondition(condition string) (*Condition[K], error) {
 	}, nil
 }
 
+// ParseValueExpression parses a single string value expression into a ValueExpression ready for execution.
+// Returns a ValueExpression and a nil error on successful parsing.
+// If parsing fails, returns nil and an error.
+func (p *Parser[K]) ParseValueExpression(expression string) (*ValueExpression[K], error) {
+	parsed, err := parseValueExpression(expression)
+	if err != nil {
+		return nil, err
+	}
+	getter, err := p.newGetter(*parsed)
+	if err != nil {
+		return nil, err
+	}
+	return &ValueExpression[K]{
+		getter:   getter,
+		origText: expression,
+	}, nil
+}
+
 var parser = newParser[parsedStatement]()
 var conditionParser = newParser[booleanExpression]()
+var valueExpressionParser = newParser[value]()
 
 func parseStatement(raw string) (*parsedStatement, error) {
 	parsed, err := parser.ParseString("", raw)
@@ -209,6 +258,20 @@ func parseCondition(raw string) (*booleanExpression, error) {
 	return parsed, nil
 }
 
+func parseValueExpression(raw string) (*value, error) {
+	parsed, err := valueExpressionParser.ParseString("", raw)
+
+	if err != nil {
+		return nil, fmt.Errorf("value expression has invalid syntax: %w", err)
+	}
+	err = parsed.checkForCustomError()
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed, nil
+}
+
 // newParser returns a parser that can be used to read a string into a parsedStatement. An error will be returned if the string
 // is not formatted for the DSL.
 func newParser[G any]() *participle.Parser[G] {
@@ -231,6 +294,7 @@ type StatementSequence[K any] struct {
 	statements        []*Statement[K]
 	errorMode         ErrorMode
 	telemetrySettings component.TelemetrySettings
+	explain           ExplainConfig
 }
 
 type StatementSequenceOption[K any] func(*StatementSequence[K])
@@ -263,8 +327,15 @@ func NewStatementSequence[K any](statements []*Statement[K], telemetrySettings c
 // When the ErrorMode of the StatementSequence is `silent`, errors are not logged and execution continues to the next statement.
 func (s *StatementSequence[K]) Execute(ctx context.Context, tCtx K) error {
 	s.telemetrySettings.Logger.Debug("initial TransformContext", zap.Any("TransformContext", tCtx))
+	explain := s.explain.sampled(ctx, s.telemetrySettings.Logger)
 	for _, statement := range s.statements {
-		_, condition, err := statement.Execute(ctx, tCtx)
+		var condition bool
+		var err error
+		if explain {
+			condition, err = explainStatement(ctx, tCtx, statement, s.explain, s.telemetrySettings.Logger)
+		} else {
+			_, condition, err = statement.Execute(ctx, tCtx)
+		}
 		s.telemetrySettings.Logger.Debug("TransformContext after statement execution", zap.String("statement", statement.origText), zap.Bool("condition matched", condition), zap.Any("TransformContext", tCtx))
 		if err != nil {
 			if s.errorMode == PropagateError {
@@ -287,6 +358,7 @@ type ConditionSequence[K any] struct {
 	errorMode         ErrorMode
 	telemetrySettings component.TelemetrySettings
 	logicOp           LogicOperation
+	explain           ExplainConfig
 }
 
 type ConditionSequenceOption[K any] func(*ConditionSequence[K])
@@ -333,8 +405,15 @@ func NewConditionSequence[K any](conditions []*Condition[K], telemetrySettings c
 // When using the AND LogicOperation with the `ignore` ErrorMode the sequence will evaluate to false if all conditions error.
 func (c *ConditionSequence[K]) Eval(ctx context.Context, tCtx K) (bool, error) {
 	var atLeastOneMatch bool
+	explain := c.explain.sampled(ctx, c.telemetrySettings.Logger)
 	for _, condition := range c.conditions {
-		match, err := condition.Eval(ctx, tCtx)
+		var match bool
+		var err error
+		if explain {
+			match, err = explainCondition(ctx, tCtx, condition, c.explain, c.telemetrySettings.Logger)
+		} else {
+			match, err = condition.Eval(ctx, tCtx)
+		}
 		c.telemetrySettings.Logger.Debug("condition evaluation result", zap.String("condition", condition.origText), zap.Bool("match", match), zap.Any("TransformContext", tCtx))
 		if err != nil {
 			if c.errorMode == PropagateError {
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottltest"
@@ -2337,3 +2338,60 @@ func Test_ConditionSequence_Eval_Error(t *testing.T) {
 		})
 	}
 }
+
+func Test_ValueExpression_Eval(t *testing.T) {
+	tests := []struct {
+		name       string
+		expression string
+		expected   any
+	}{
+		{
+			name:       "string literal",
+			expression: `"foo"`,
+			expected:   "foo",
+		},
+		{
+			name:       "int literal",
+			expression: `42`,
+			expected:   int64(42),
+		},
+		{
+			name:       "math expression",
+			expression: `503 / 100`,
+			expected:   int64(5),
+		},
+		{
+			name:       "list",
+			expression: `["a", "b"]`,
+			expected:   []any{"a", "b"},
+		},
+		{
+			name:       "enum",
+			expression: `TEST_ENUM_ONE`,
+			expected:   int64(1),
+		},
+		{
+			name:       "nil",
+			expression: `nil`,
+			expected:   nil,
+		},
+	}
+
+	p, _ := NewParser(
+		CreateFactoryMap[any](),
+		testParsePath[any],
+		componenttest.NewNopTelemetrySettings(),
+		WithEnumParser[any](testParseEnum),
+	)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expression, err := p.ParseValueExpression(tt.expression)
+			require.NoError(t, err)
+
+			result, err := expression.Eval(context.Background(), nil)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
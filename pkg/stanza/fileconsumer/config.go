@@ -87,6 +87,7 @@ type Config struct {
 	DeleteAfterRead         bool            `mapstructure:"delete_after_read,omitempty"`
 	IncludeFileRecordNumber bool            `mapstructure:"include_file_record_number,omitempty"`
 	Compression             string          `mapstructure:"compression,omitempty"`
+	FingerprintMode         string          `mapstructure:"fingerprint_mode,omitempty"`
 }
 
 type HeaderConfig struct {
@@ -170,6 +171,7 @@ func (c Config) Build(set component.TelemetrySettings, emit emit.Callback, opts
 		DeleteAtEOF:             c.DeleteAfterRead,
 		IncludeFileRecordNumber: c.IncludeFileRecordNumber,
 		Compression:             c.Compression,
+		FingerprintMode:         c.FingerprintMode,
 	}
 
 	var t tracker.Tracker
@@ -219,6 +221,10 @@ func (c Config) validate() error {
 		return fmt.Errorf("'fingerprint_size' must be at least %d bytes", fingerprint.MinSize)
 	}
 
+	if err := fingerprint.ValidateMode(c.FingerprintMode); err != nil {
+		return fmt.Errorf("invalid 'fingerprint_mode': %w", err)
+	}
+
 	if c.MaxLogSize <= 0 {
 		return fmt.Errorf("'max_log_size' must be positive")
 	}
@@ -236,6 +242,10 @@ func (c Config) validate() error {
 		return err
 	}
 
+	if err = reader.ValidateCompression(c.Compression); err != nil {
+		return fmt.Errorf("invalid 'compression': %w", err)
+	}
+
 	if c.DeleteAfterRead {
 		if !allowFileDeletion.IsEnabled() {
 			return fmt.Errorf("'delete_after_read' requires feature gate '%s'", allowFileDeletion.ID())
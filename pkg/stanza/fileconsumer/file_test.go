@@ -1594,3 +1594,67 @@ func TestReadGzipCompressedLogsFromEnd(t *testing.T) {
 	operator.poll(context.TODO())
 	sink.ExpectToken(t, []byte("testlog4"))
 }
+
+func TestIdentityFingerprintSharedHeader(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.FingerprintSize = 16
+	cfg.FingerprintMode = "identity"
+	cfg.StartAt = "beginning"
+	operator, sink := testManager(t, cfg)
+
+	// The header is longer than the fingerprint, so the files only differ by their identity
+	header := "timestamp,severity,message\n"
+	file1 := filetest.OpenTemp(t, tempDir)
+	file2 := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, file1, header+"2024-01-01,INFO,from file 1\n")
+	filetest.WriteString(t, file2, header+"2024-01-01,INFO,from file 2\n")
+
+	operator.poll(context.Background())
+	sink.ExpectTokens(t,
+		[]byte("timestamp,severity,message"), []byte("2024-01-01,INFO,from file 1"),
+		[]byte("timestamp,severity,message"), []byte("2024-01-01,INFO,from file 2"),
+	)
+
+	filetest.WriteString(t, file1, "2024-01-02,WARN,more from file 1\n")
+	filetest.WriteString(t, file2, "2024-01-02,WARN,more from file 2\n")
+	operator.poll(context.Background())
+	sink.ExpectTokens(t, []byte("2024-01-02,WARN,more from file 1"), []byte("2024-01-02,WARN,more from file 2"))
+	sink.ExpectNoCalls(t)
+}
+
+func TestIdentityFingerprintMigration(t *testing.T) {
+	t.Parallel()
+
+	tempDir := t.TempDir()
+	cfg := NewConfig().includeDir(tempDir)
+	cfg.FingerprintSize = 16
+	cfg.StartAt = "beginning"
+	persister := testutil.NewUnscopedMockPersister()
+
+	header := "timestamp,severity,message\n"
+	file1 := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, file1, header+"2024-01-01,INFO,before migration\n")
+
+	// Checkpoint the file with fingerprints which only consist of the first bytes
+	operatorOne, sink1 := testManager(t, cfg)
+	require.NoError(t, operatorOne.Start(persister))
+	sink1.ExpectTokens(t, []byte("timestamp,severity,message"), []byte("2024-01-01,INFO,before migration"))
+	require.NoError(t, operatorOne.Stop())
+
+	filetest.WriteString(t, file1, "2024-01-02,INFO,during restart\n")
+
+	// The checkpointed file is matched by its first bytes and then identified by its identity,
+	// so that a new file with the same header is not mistaken for it
+	cfg.FingerprintMode = "identity"
+	operatorTwo, sink2 := testManager(t, cfg)
+	require.NoError(t, operatorTwo.Start(persister))
+	sink2.ExpectToken(t, []byte("2024-01-02,INFO,during restart"))
+
+	file2 := filetest.OpenTemp(t, tempDir)
+	filetest.WriteString(t, file2, header+"2024-01-03,INFO,new file\n")
+	sink2.ExpectTokens(t, []byte("timestamp,severity,message"), []byte("2024-01-03,INFO,new file"))
+	require.NoError(t, operatorTwo.Stop())
+}
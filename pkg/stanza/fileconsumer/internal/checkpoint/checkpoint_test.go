@@ -73,6 +73,16 @@ func TestNopEncodingDifferentLogSizes(t *testing.T) {
 				},
 			},
 		},
+		{
+			"identity",
+			[]*reader.Metadata{
+				{
+					FileAttributes: make(map[string]any),
+					Fingerprint:    fingerprintWithIdentity([]byte("foo"), &fingerprint.Identity{Device: 1, Inode: 2, Path: "a.log"}),
+					Offset:         3,
+				},
+			},
+		},
 		{
 			"other_fields",
 			[]*reader.Metadata{
@@ -112,6 +122,12 @@ func TestNopEncodingDifferentLogSizes(t *testing.T) {
 	}
 }
 
+func fingerprintWithIdentity(first []byte, id *fingerprint.Identity) *fingerprint.Fingerprint {
+	fp := fingerprint.New(first)
+	fp.SetIdentity(id)
+	return fp
+}
+
 type deprecatedMetadata struct {
 	reader.Metadata
 	HeaderAttributes map[string]any
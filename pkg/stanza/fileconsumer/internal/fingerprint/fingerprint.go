@@ -17,9 +17,11 @@ const DefaultSize = 1000 // bytes
 const MinSize = 16 // bytes
 
 // Fingerprint is used to identify a file
-// A file's fingerprint is the first N bytes of the file
+// A file's fingerprint is the first N bytes of the file,
+// optionally complemented by the identity of the file
 type Fingerprint struct {
 	firstBytes []byte
+	identity   *Identity
 }
 
 func New(first []byte) *Fingerprint {
@@ -39,17 +41,30 @@ func NewFromFile(file *os.File, size int) (*Fingerprint, error) {
 func (f Fingerprint) Copy() *Fingerprint {
 	buf := make([]byte, len(f.firstBytes), cap(f.firstBytes))
 	n := copy(buf, f.firstBytes)
-	return New(buf[:n])
+	cp := New(buf[:n])
+	if f.identity != nil {
+		id := *f.identity
+		cp.identity = &id
+	}
+	return cp
+}
+
+// Identity returns the identity of the file, or nil if the fingerprint only consists of its first bytes.
+func (f Fingerprint) Identity() *Identity {
+	return f.identity
+}
+
+// SetIdentity sets the identity of the file. A nil identity reverts to identifying the file by its first bytes only.
+func (f *Fingerprint) SetIdentity(id *Identity) {
+	f.identity = id
 }
 
 func (f *Fingerprint) Len() int {
 	return len(f.firstBytes)
 }
 
-// Equal returns true if the fingerprints have the same FirstBytes,
-// false otherwise. This does not compare other aspects of the fingerprints
-// because the primary purpose of a fingerprint is to convey a unique
-// identity, and only the FirstBytes field contributes to this goal.
+// Equal returns true if the fingerprints have the same FirstBytes
+// and compatible identities, false otherwise.
 func (f Fingerprint) Equal(other *Fingerprint) bool {
 	l0 := len(other.firstBytes)
 	l1 := len(f.firstBytes)
@@ -61,7 +76,7 @@ func (f Fingerprint) Equal(other *Fingerprint) bool {
 			return false
 		}
 	}
-	return true
+	return f.identity.matches(other.identity)
 }
 
 // StartsWith returns true if the fingerprints are the same
@@ -79,11 +94,14 @@ func (f Fingerprint) StartsWith(old *Fingerprint) bool {
 	if l0 > l1 {
 		return false
 	}
-	return bytes.Equal(old.firstBytes[:l0], f.firstBytes[:l0])
+	if !bytes.Equal(old.firstBytes[:l0], f.firstBytes[:l0]) {
+		return false
+	}
+	return f.identity.matches(old.identity)
 }
 
 func (f *Fingerprint) MarshalJSON() ([]byte, error) {
-	m := marshal{FirstBytes: f.firstBytes}
+	m := marshal{FirstBytes: f.firstBytes, Identity: f.identity}
 	return json.Marshal(&m)
 }
 
@@ -93,9 +111,11 @@ func (f *Fingerprint) UnmarshalJSON(data []byte) error {
 		return err
 	}
 	f.firstBytes = m.FirstBytes
+	f.identity = m.Identity
 	return nil
 }
 
 type marshal struct {
-	FirstBytes []byte `json:"first_bytes"`
+	FirstBytes []byte    `json:"first_bytes"`
+	Identity   *Identity `json:"identity,omitempty"`
 }
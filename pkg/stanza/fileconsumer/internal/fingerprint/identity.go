@@ -0,0 +1,94 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fingerprint // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/fingerprint"
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"os"
+)
+
+const (
+	// ModeFirstBytes identifies files by their first bytes only.
+	ModeFirstBytes = "first_bytes"
+	// ModeIdentity additionally identifies files by their device and inode, where available,
+	// a hash of their first bytes and of the region that follows them, and their path.
+	ModeIdentity = "identity"
+)
+
+// ValidateMode checks that the fingerprint mode is supported.
+func ValidateMode(mode string) error {
+	switch mode {
+	case "", ModeFirstBytes, ModeIdentity:
+		return nil
+	default:
+		return fmt.Errorf("unsupported fingerprint mode %q, must be one of %q or %q", mode, ModeFirstBytes, ModeIdentity)
+	}
+}
+
+// Identity distinguishes files which start with the same bytes, such as
+// files sharing a banner or a CSV header longer than the fingerprint.
+type Identity struct {
+	// Device and Inode identify the file on platforms where they are available,
+	// and follow the file when it is renamed.
+	Device uint64 `json:"device,omitempty"`
+	Inode  uint64 `json:"inode,omitempty"`
+	// SampleHash is the hash of the first bytes of the file followed by the region
+	// of the same size which comes after them. It is zero until the file is large
+	// enough for the whole region to be sampled.
+	SampleHash uint64 `json:"sample_hash,omitempty"`
+	Path       string `json:"path,omitempty"`
+}
+
+// NewIdentityFromFile returns the identity of the file, sampling twice the fingerprint size.
+func NewIdentityFromFile(file *os.File, size int) (*Identity, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat: %w", err)
+	}
+	id := &Identity{Path: file.Name()}
+	id.Device, id.Inode, _ = fileID(info)
+
+	buf := make([]byte, 2*size)
+	n, err := file.ReadAt(buf, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("reading sample bytes: %w", err)
+	}
+	if n == len(buf) {
+		h := fnv.New64a()
+		_, _ = h.Write(buf)
+		id.SampleHash = h.Sum64()
+	}
+	return id, nil
+}
+
+// Incomplete returns true if the file was not large enough to sample its whole region.
+func (id *Identity) Incomplete() bool {
+	return id != nil && id.SampleHash == 0
+}
+
+// matches returns true if the identities may belong to the same file. Fingerprints
+// without identity, such as the ones restored from checkpoints saved before identities
+// were introduced, match any identity, so that they are upgraded when the file is read again.
+// Otherwise, the device and inode must be equal when both are known, and so must the sample
+// hashes. The path is only compared when neither of them is known for both identities.
+func (id *Identity) matches(other *Identity) bool {
+	if id == nil || other == nil {
+		return true
+	}
+	hasFileID := id.Inode != 0 && other.Inode != 0
+	if hasFileID && (id.Device != other.Device || id.Inode != other.Inode) {
+		return false
+	}
+	hasSample := id.SampleHash != 0 && other.SampleHash != 0
+	if hasSample && id.SampleHash != other.SampleHash {
+		return false
+	}
+	if !hasFileID && !hasSample {
+		return id.Path == other.Path
+	}
+	return true
+}
@@ -0,0 +1,164 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package fingerprint
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateMode(t *testing.T) {
+	require.NoError(t, ValidateMode(""))
+	require.NoError(t, ValidateMode(ModeFirstBytes))
+	require.NoError(t, ValidateMode(ModeIdentity))
+	require.EqualError(t, ValidateMode("inode"), `unsupported fingerprint mode "inode", must be one of "first_bytes" or "identity"`)
+}
+
+func TestNewIdentityFromFile(t *testing.T) {
+	temp, err := os.CreateTemp(t.TempDir(), "")
+	require.NoError(t, err)
+	defer temp.Close()
+
+	_, err = temp.WriteString("header\n")
+	require.NoError(t, err)
+
+	id, err := NewIdentityFromFile(temp, 8)
+	require.NoError(t, err)
+	assert.Equal(t, temp.Name(), id.Path)
+	assert.True(t, id.Incomplete(), "the sampled region is not complete yet")
+	if runtime.GOOS != "windows" {
+		assert.NotZero(t, id.Inode)
+	}
+
+	_, err = temp.WriteString("line 1\nline 2\n")
+	require.NoError(t, err)
+
+	complete, err := NewIdentityFromFile(temp, 8)
+	require.NoError(t, err)
+	assert.False(t, complete.Incomplete())
+	assert.True(t, complete.matches(id))
+
+	// The sample is only taken from the first bytes and the region that follows them
+	_, err = temp.WriteString("line 3\n")
+	require.NoError(t, err)
+	grown, err := NewIdentityFromFile(temp, 8)
+	require.NoError(t, err)
+	assert.Equal(t, complete, grown)
+}
+
+func TestIdentityMatches(t *testing.T) {
+	cases := []struct {
+		name     string
+		a        *Identity
+		b        *Identity
+		expected bool
+	}{
+		{
+			name:     "legacy",
+			a:        nil,
+			b:        &Identity{Inode: 1, Path: "a.log"},
+			expected: true,
+		},
+		{
+			name:     "same_inode_renamed",
+			a:        &Identity{Device: 1, Inode: 2, Path: "a.log"},
+			b:        &Identity{Device: 1, Inode: 2, Path: "a.log.1"},
+			expected: true,
+		},
+		{
+			name:     "different_inode_same_path",
+			a:        &Identity{Device: 1, Inode: 2, Path: "a.log"},
+			b:        &Identity{Device: 1, Inode: 3, Path: "a.log"},
+			expected: false,
+		},
+		{
+			name:     "different_device",
+			a:        &Identity{Device: 1, Inode: 2, Path: "a.log"},
+			b:        &Identity{Device: 2, Inode: 2, Path: "a.log"},
+			expected: false,
+		},
+		{
+			name:     "reused_inode_different_sample",
+			a:        &Identity{Device: 1, Inode: 2, SampleHash: 10},
+			b:        &Identity{Device: 1, Inode: 2, SampleHash: 11},
+			expected: false,
+		},
+		{
+			name:     "incomplete_sample",
+			a:        &Identity{Device: 1, Inode: 2},
+			b:        &Identity{Device: 1, Inode: 2, SampleHash: 11},
+			expected: true,
+		},
+		{
+			name:     "no_inode_same_sample_renamed",
+			a:        &Identity{SampleHash: 10, Path: "a.log"},
+			b:        &Identity{SampleHash: 10, Path: "a.log.1"},
+			expected: true,
+		},
+		{
+			name:     "no_inode_different_sample",
+			a:        &Identity{SampleHash: 10, Path: "a.log"},
+			b:        &Identity{SampleHash: 11, Path: "a.log"},
+			expected: false,
+		},
+		{
+			name:     "no_inode_no_sample_same_path",
+			a:        &Identity{Path: "a.log"},
+			b:        &Identity{SampleHash: 11, Path: "a.log"},
+			expected: true,
+		},
+		{
+			name:     "no_inode_no_sample_different_path",
+			a:        &Identity{Path: "a.log"},
+			b:        &Identity{Path: "b.log"},
+			expected: false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, tc.a.matches(tc.b))
+			assert.Equal(t, tc.expected, tc.b.matches(tc.a))
+		})
+	}
+}
+
+func TestFingerprintWithIdentity(t *testing.T) {
+	header := New([]byte("timestamp,message"))
+	header.SetIdentity(&Identity{Device: 1, Inode: 2})
+
+	other := New([]byte("timestamp,message"))
+	other.SetIdentity(&Identity{Device: 1, Inode: 3})
+	assert.False(t, header.Equal(other))
+	assert.False(t, other.StartsWith(header))
+
+	grown := New([]byte("timestamp,message\n2024"))
+	grown.SetIdentity(&Identity{Device: 1, Inode: 2})
+	assert.True(t, grown.StartsWith(header))
+
+	cp := header.Copy()
+	assert.Equal(t, header, cp)
+	cp.Identity().Inode = 4
+	assert.Equal(t, uint64(2), header.Identity().Inode)
+}
+
+func TestMarshalUnmarshalIdentity(t *testing.T) {
+	fp := New([]byte("hello"))
+	fp.SetIdentity(&Identity{Device: 1, Inode: 2, SampleHash: 3, Path: "a.log"})
+	b, err := fp.MarshalJSON()
+	require.NoError(t, err)
+
+	fp2 := new(Fingerprint)
+	require.NoError(t, fp2.UnmarshalJSON(b))
+	require.Equal(t, fp, fp2)
+
+	// Fingerprints saved before identities were introduced have none
+	legacy := new(Fingerprint)
+	require.NoError(t, legacy.UnmarshalJSON([]byte(`{"first_bytes":"aGVsbG8="}`)))
+	require.Equal(t, New([]byte("hello")), legacy)
+	require.True(t, fp.Equal(legacy))
+}
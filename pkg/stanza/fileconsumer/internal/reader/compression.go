@@ -0,0 +1,370 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/reader"
+
+import (
+	"bufio"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	CompressionGzip  = "gzip"
+	CompressionZstd  = "zstd"
+	CompressionBzip2 = "bzip2"
+	// CompressionAuto detects the compression of each file from its magic bytes.
+	// Files which do not start with known magic bytes are read uncompressed.
+	CompressionAuto = "auto"
+)
+
+const (
+	zstdMagic             = 0xFD2FB528
+	zstdSkippableMagic    = 0x184D2A50
+	zstdSkippableMagicMax = 0x184D2A5F
+	// magicSize is the number of bytes needed to detect the compression of a file.
+	magicSize = 4
+)
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+var bzip2Magic = []byte("BZh")
+
+// ValidateCompression checks that the compression is supported.
+func ValidateCompression(compression string) error {
+	switch compression {
+	case "", CompressionGzip, CompressionZstd, CompressionBzip2, CompressionAuto:
+		return nil
+	default:
+		return fmt.Errorf("unsupported compression %q, must be one of %q, %q, %q or %q", compression, CompressionGzip, CompressionZstd, CompressionBzip2, CompressionAuto)
+	}
+}
+
+// detectCompression returns the compression of the file based on its magic bytes, and the number
+// of bytes read to detect it. It returns false if the file is too short to be detected yet.
+func detectCompression(file *os.File) (string, int, bool) {
+	magic := make([]byte, magicSize)
+	n, _ := file.ReadAt(magic, 0)
+	if n < magicSize {
+		return "", n, false
+	}
+	switch m := binary.LittleEndian.Uint32(magic); {
+	case bytes.HasPrefix(magic, gzipMagic):
+		return CompressionGzip, n, true
+	case m == zstdMagic, m >= zstdSkippableMagic && m <= zstdSkippableMagicMax:
+		return CompressionZstd, n, true
+	case bytes.HasPrefix(magic, bzip2Magic) && magic[3] >= '1' && magic[3] <= '9':
+		return CompressionBzip2, n, true
+	default:
+		return "", n, true
+	}
+}
+
+// detectCompression returns the compression of the file, or false if it can't be detected yet.
+// As a compressed file is never shorter than the magic bytes, a shorter file is read uncompressed
+// once it stopped growing since the previous poll. Empty files are not, as they may be created
+// before their compressed content is written.
+func (r *Reader) detectCompression() (string, bool) {
+	compression, size, ok := detectCompression(r.file)
+	switch {
+	case ok:
+		return compression, true
+	case size > 0 && int64(size) == r.undetectedSize:
+		return "", true
+	default:
+		r.undetectedSize = int64(size)
+		return "", false
+	}
+}
+
+// grown returns false if the compressed file was read entirely and didn't grow since, so that it is
+// not decompressed again. This matters for bzip2 files, which are decompressed from their start.
+func (r *Reader) grown() bool {
+	info, err := r.file.Stat()
+	return err != nil || info.Size() != r.readSize
+}
+
+// boundary is the start of a compressed frame, with its position in the decompressed content.
+type boundary struct {
+	decompressed int64
+	compressed   int64
+}
+
+// decompressReader decompresses a file from the start of a frame, and records the boundaries
+// of the frames it reads completely so that offsets can be checkpointed at a frame which can
+// be decompressed again after a restart. Gzip members and zstd frames are decompressed one by
+// one, while bzip2 streams are decompressed as a whole.
+//
+// An incomplete frame at the end of the file, which may still be written, is read as far as
+// possible and then reported as io.EOF.
+type decompressReader struct {
+	compression string
+	src         *io.SectionReader
+	start       int64
+	// counter counts the bytes consumed by the gzip and bzip2 decompressors, which read
+	// byte by byte from an io.ByteReader and never consume past the end of a frame.
+	counter *countingReader
+
+	frame      io.Reader
+	frameStart int64
+	frameEnd   int64
+	gzip       *gzip.Reader
+	zstd       *zstd.Decoder
+
+	decompressed int64
+	boundaries   []boundary
+	done         bool
+}
+
+func newDecompressReader(file *os.File, compression string, offset int64) (*decompressReader, error) {
+	info, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("stat: %w", err)
+	}
+	if offset > info.Size() {
+		offset = info.Size()
+	}
+	src := io.NewSectionReader(file, offset, info.Size()-offset)
+	return &decompressReader{
+		compression: compression,
+		src:         src,
+		start:       offset,
+		counter:     &countingReader{r: bufio.NewReader(src)},
+		boundaries:  []boundary{{compressed: offset}},
+	}, nil
+}
+
+func (d *decompressReader) Read(dst []byte) (int, error) {
+	for {
+		if d.done {
+			return 0, io.EOF
+		}
+		if d.frame == nil {
+			if err := d.nextFrame(); err != nil {
+				d.done = true
+				if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+					return 0, io.EOF
+				}
+				return 0, err
+			}
+			continue
+		}
+
+		n, err := d.frame.Read(dst)
+		d.decompressed += int64(n)
+		switch {
+		case errors.Is(err, io.EOF):
+			d.endFrame()
+			err = nil
+		case errors.Is(err, io.ErrUnexpectedEOF):
+			// the frame is still being written
+			d.done = true
+			err = nil
+		case err != nil:
+			d.done = true
+		}
+		if n > 0 || err != nil {
+			return n, err
+		}
+	}
+}
+
+// nextFrame prepares the decompression of the frame following the last complete frame.
+func (d *decompressReader) nextFrame() error {
+	switch d.compression {
+	case CompressionGzip:
+		if d.gzip == nil {
+			gz, err := gzip.NewReader(d.counter)
+			if err != nil {
+				return err
+			}
+			d.gzip = gz
+		} else if err := d.gzip.Reset(d.counter); err != nil {
+			return err
+		}
+		d.gzip.Multistream(false)
+		d.frame = d.gzip
+	case CompressionBzip2:
+		if d.counter.n > 0 {
+			return io.EOF
+		}
+		// Ensure the stream is not empty, as the bzip2 reader does not report it.
+		if _, err := d.counter.r.Peek(1); err != nil {
+			return err
+		}
+		d.frame = bzip2.NewReader(d.counter)
+	case CompressionZstd:
+		return d.nextZstdFrame()
+	default:
+		return fmt.Errorf("unsupported compression %q", d.compression)
+	}
+	return nil
+}
+
+// nextZstdFrame finds the length of the next zstd frame from its headers, so that it is
+// only decompressed once it is complete. Skippable frames, such as the seek table of the
+// seekable format, are skipped.
+func (d *decompressReader) nextZstdFrame() error {
+	for {
+		pos := d.frameStart
+		length, skippable, err := zstdFrameLength(d.src, pos)
+		if err != nil {
+			return err
+		}
+		if skippable {
+			d.frameStart += length
+			d.boundaries = append(d.boundaries, boundary{decompressed: d.decompressed, compressed: d.start + d.frameStart})
+			continue
+		}
+		if d.zstd == nil {
+			if d.zstd, err = zstd.NewReader(nil, zstd.WithDecoderConcurrency(1)); err != nil {
+				return err
+			}
+		}
+		if err = d.zstd.Reset(io.NewSectionReader(d.src, pos, length)); err != nil {
+			return err
+		}
+		d.frame = d.zstd
+		d.frameEnd = pos + length
+		return nil
+	}
+}
+
+// zstdFrameLength returns the length of the frame starting at pos, by walking its block headers.
+// It returns io.ErrUnexpectedEOF if the frame is not complete.
+func zstdFrameLength(src *io.SectionReader, pos int64) (length int64, skippable bool, err error) {
+	header := make([]byte, 8)
+	if _, err = src.ReadAt(header[:5], pos); err != nil {
+		return 0, false, err
+	}
+	magic := binary.LittleEndian.Uint32(header)
+	if magic >= zstdSkippableMagic && magic <= zstdSkippableMagicMax {
+		if _, err = src.ReadAt(header, pos); err != nil {
+			return 0, false, err
+		}
+		length = 8 + int64(binary.LittleEndian.Uint32(header[4:]))
+		if pos+length > src.Size() {
+			return 0, false, io.ErrUnexpectedEOF
+		}
+		return length, true, nil
+	}
+	if magic != zstdMagic {
+		return 0, false, fmt.Errorf("invalid zstd frame magic number %#x", magic)
+	}
+
+	descriptor := header[4]
+	headerSize := int64(1)
+	singleSegment := descriptor&0x20 != 0
+	if !singleSegment {
+		headerSize++ // window descriptor
+	}
+	headerSize += []int64{0, 1, 2, 4}[descriptor&0x3]
+	switch descriptor >> 6 {
+	case 0:
+		if singleSegment {
+			headerSize++
+		}
+	case 1:
+		headerSize += 2
+	case 2:
+		headerSize += 4
+	case 3:
+		headerSize += 8
+	}
+
+	end := pos + 4 + headerSize
+	block := make([]byte, 3)
+	for {
+		if _, err = src.ReadAt(block, end); err != nil {
+			return 0, false, err
+		}
+		blockHeader := uint32(block[0]) | uint32(block[1])<<8 | uint32(block[2])<<16
+		last := blockHeader&1 != 0
+		size := int64(blockHeader >> 3)
+		switch (blockHeader >> 1) & 0x3 {
+		case 1: // RLE block
+			size = 1
+		case 3:
+			return 0, false, errors.New("invalid zstd block type")
+		}
+		end += 3 + size
+		if last {
+			break
+		}
+	}
+	if descriptor&0x4 != 0 {
+		end += 4 // content checksum
+	}
+	if end > src.Size() {
+		return 0, false, io.ErrUnexpectedEOF
+	}
+	return end - pos, false, nil
+}
+
+// endFrame records the boundary at the end of a complete frame.
+func (d *decompressReader) endFrame() {
+	d.frame = nil
+	if d.compression == CompressionZstd {
+		d.frameStart = d.frameEnd
+	} else {
+		d.frameStart = d.counter.n
+	}
+	d.boundaries = append(d.boundaries, boundary{decompressed: d.decompressed, compressed: d.start + d.frameStart})
+}
+
+// readAll returns true if the decompressed content was read up to pos, and the file was read to its end.
+func (d *decompressReader) readAll(pos int64) bool {
+	return d.done && pos == d.decompressed
+}
+
+// size returns the size of the file when the decompression started.
+func (d *decompressReader) size() int64 {
+	return d.start + d.src.Size()
+}
+
+// checkpoint returns the compressed offset of the last frame starting at or before the
+// decompressed position, and the position relative to the start of this frame.
+func (d *decompressReader) checkpoint(pos int64) (offset int64, decompressedOffset int64) {
+	b := d.boundaries[0]
+	for _, next := range d.boundaries[1:] {
+		if next.decompressed > pos {
+			break
+		}
+		b = next
+	}
+	return b.compressed, pos - b.decompressed
+}
+
+func (d *decompressReader) Close() {
+	if d.zstd != nil {
+		d.zstd.Close()
+	}
+}
+
+// countingReader counts the bytes read from a buffered reader.
+type countingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
@@ -0,0 +1,310 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package reader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/stanza/fileconsumer/internal/filetest"
+)
+
+func gzipMember(t *testing.T, content string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func zstdFrame(t *testing.T, content string) []byte {
+	enc, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	defer enc.Close()
+	return enc.EncodeAll([]byte(content), nil)
+}
+
+// zstdSkippableFrame returns a skippable frame, such as the seek table of the seekable format.
+func zstdSkippableFrame(content []byte) []byte {
+	frame := binary.LittleEndian.AppendUint32(nil, 0x184D2A5E)
+	frame = binary.LittleEndian.AppendUint32(frame, uint32(len(content)))
+	return append(frame, content...)
+}
+
+func TestDetectCompression(t *testing.T) {
+	bz2, err := os.ReadFile(filepath.Join("testdata", "logs.bz2"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		content     []byte
+		compression string
+		detected    bool
+	}{
+		{name: "gzip", content: gzipMember(t, "log\n"), compression: CompressionGzip, detected: true},
+		{name: "zstd", content: zstdFrame(t, "log\n"), compression: CompressionZstd, detected: true},
+		{name: "zstd skippable frame", content: zstdSkippableFrame([]byte("seek table")), compression: CompressionZstd, detected: true},
+		{name: "bzip2", content: bz2, compression: CompressionBzip2, detected: true},
+		{name: "plain", content: []byte("BZh log\n"), detected: true},
+		{name: "too short", content: []byte("lo")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			temp := filetest.OpenTemp(t, t.TempDir())
+			_, err := temp.Write(tt.content)
+			require.NoError(t, err)
+
+			compression, _, detected := detectCompression(temp)
+			assert.Equal(t, tt.compression, compression)
+			assert.Equal(t, tt.detected, detected)
+		})
+	}
+}
+
+func TestReadAutoShortFile(t *testing.T) {
+	temp := filetest.OpenTemp(t, t.TempDir())
+	f, sink := testFactory(t, withCompression(CompressionAuto))
+	fp, err := f.NewFingerprint(temp)
+	require.NoError(t, err)
+	r, err := f.NewReader(filetest.OpenFile(t, temp.Name()), fp)
+	require.NoError(t, err)
+	defer r.Close()
+
+	// empty files are not read until their compression is detected
+	r.ReadToEnd(context.Background())
+	r.ReadToEnd(context.Background())
+	sink.ExpectNoCalls(t)
+
+	// files shorter than the magic bytes are read uncompressed once they stop growing
+	_, err = temp.WriteString("a\n")
+	require.NoError(t, err)
+	r.ReadToEnd(context.Background())
+	sink.ExpectNoCalls(t)
+	r.ReadToEnd(context.Background())
+	sink.ExpectToken(t, []byte("a"))
+	sink.ExpectNoCalls(t)
+	assert.Equal(t, int64(2), r.Offset)
+}
+
+func TestReadCompressedUnchanged(t *testing.T) {
+	bz2, err := os.ReadFile(filepath.Join("testdata", "logs.bz2"))
+	require.NoError(t, err)
+	temp := filetest.OpenTemp(t, t.TempDir())
+	_, err = temp.Write(bz2)
+	require.NoError(t, err)
+
+	f, sink := testFactory(t, withCompression(CompressionBzip2))
+	fp, err := f.NewFingerprint(temp)
+	require.NoError(t, err)
+	r, err := f.NewReader(filetest.OpenFile(t, temp.Name()), fp)
+	require.NoError(t, err)
+	r.ReadToEnd(context.Background())
+	for _, expected := range []string{"bzip2 log 1", "bzip2 log 2", "bzip2 log 3"} {
+		sink.ExpectToken(t, []byte(expected))
+	}
+	m := r.Close()
+
+	// the file is not decompressed again until it grows
+	r, err = f.NewReaderFromMetadata(filetest.OpenFile(t, temp.Name()), m)
+	require.NoError(t, err)
+	r.ReadToEnd(context.Background())
+	assert.Nil(t, r.reader)
+	sink.ExpectNoCalls(t)
+	m = r.Close()
+
+	_, err = temp.Write(bz2)
+	require.NoError(t, err)
+	r, err = f.NewReaderFromMetadata(filetest.OpenFile(t, temp.Name()), m)
+	require.NoError(t, err)
+	defer r.Close()
+	r.ReadToEnd(context.Background())
+	for _, expected := range []string{"bzip2 log 1", "bzip2 log 2", "bzip2 log 3"} {
+		sink.ExpectToken(t, []byte(expected))
+	}
+	sink.ExpectNoCalls(t)
+}
+
+func TestValidateCompression(t *testing.T) {
+	assert.NoError(t, ValidateCompression(""))
+	assert.NoError(t, ValidateCompression(CompressionAuto))
+	assert.EqualError(t, ValidateCompression("lz4"), `unsupported compression "lz4", must be one of "gzip", "zstd", "bzip2" or "auto"`)
+}
+
+func TestReadCompressed(t *testing.T) {
+	bz2, err := os.ReadFile(filepath.Join("testdata", "logs.bz2"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		compression string
+		content     []byte
+		expected    []string
+	}{
+		{
+			name:        "gzip members",
+			compression: CompressionGzip,
+			content:     append(gzipMember(t, "gzip log 1\n"), gzipMember(t, "gzip log 2\n")...),
+			expected:    []string{"gzip log 1", "gzip log 2"},
+		},
+		{
+			name:        "seekable zstd",
+			compression: CompressionZstd,
+			content: bytes.Join([][]byte{
+				zstdFrame(t, "zstd log 1\n"),
+				zstdFrame(t, "zstd log 2\nzstd log 3\n"),
+				zstdSkippableFrame([]byte("seek table")),
+			}, nil),
+			expected: []string{"zstd log 1", "zstd log 2", "zstd log 3"},
+		},
+		{
+			name:        "bzip2 streams",
+			compression: CompressionBzip2,
+			content:     bz2,
+			expected:    []string{"bzip2 log 1", "bzip2 log 2", "bzip2 log 3"},
+		},
+		{
+			name:        "auto detected zstd",
+			compression: CompressionAuto,
+			content:     zstdFrame(t, "zstd log\n"),
+			expected:    []string{"zstd log"},
+		},
+		{
+			name:        "auto detected plain",
+			compression: CompressionAuto,
+			content:     []byte("plain log\n"),
+			expected:    []string{"plain log"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			temp := filetest.OpenTemp(t, t.TempDir())
+			_, err := temp.Write(tt.content)
+			require.NoError(t, err)
+
+			f, sink := testFactory(t, withCompression(tt.compression))
+			fp, err := f.NewFingerprint(temp)
+			require.NoError(t, err)
+			r, err := f.NewReader(filetest.OpenFile(t, temp.Name()), fp)
+			require.NoError(t, err)
+			defer r.Close()
+
+			r.ReadToEnd(context.Background())
+			for _, expected := range tt.expected {
+				sink.ExpectToken(t, []byte(expected))
+			}
+			sink.ExpectNoCalls(t)
+			assert.Equal(t, int64(len(tt.content)), r.Offset)
+			assert.Zero(t, r.DecompressedOffset)
+		})
+	}
+}
+
+func TestReadCompressedResume(t *testing.T) {
+	tests := []struct {
+		name        string
+		compression string
+		// first is written and read before the reader is closed. second is written before
+		// the file is read by a new reader created from the metadata of the first one.
+		first  []byte
+		second []byte
+		// firstTokens and secondTokens are read by the first and second reader.
+		firstTokens  []string
+		secondTokens []string
+		// offset and decompressedOffset are the metadata of the first reader.
+		offset             int64
+		decompressedOffset int64
+	}{
+		{
+			name:               "zstd incomplete line",
+			compression:        CompressionZstd,
+			first:              zstdFrame(t, "log 1\nlog 2\npartial"),
+			second:             zstdFrame(t, " log 3\nlog 4\n"),
+			firstTokens:        []string{"log 1", "log 2"},
+			secondTokens:       []string{"partial log 3", "log 4"},
+			offset:             0,
+			decompressedOffset: 12,
+		},
+		{
+			name:        "zstd incomplete frame",
+			compression: CompressionZstd,
+			first: func() []byte {
+				frame := zstdFrame(t, " log 3\nlog 4\n")
+				return append(zstdFrame(t, "log 1\nlog 2\npartial"), frame[:len(frame)/2]...)
+			}(),
+			second: func() []byte {
+				frame := zstdFrame(t, " log 3\nlog 4\n")
+				return frame[len(frame)/2:]
+			}(),
+			firstTokens:        []string{"log 1", "log 2"},
+			secondTokens:       []string{"partial log 3", "log 4"},
+			offset:             0,
+			decompressedOffset: 12,
+		},
+		{
+			// the content of an incomplete gzip member is read, but the offset is
+			// only moved to the member once it is complete.
+			name:        "gzip incomplete member",
+			compression: CompressionGzip,
+			first: func() []byte {
+				member := gzipMember(t, "log 3\nlog 4\n")
+				return append(gzipMember(t, "log 1\nlog 2\n"), member[:len(member)-4]...)
+			}(),
+			second: func() []byte {
+				member := gzipMember(t, "log 3\nlog 4\n")
+				return member[len(member)-4:]
+			}(),
+			firstTokens:        []string{"log 1", "log 2", "log 3", "log 4"},
+			offset:             int64(len(gzipMember(t, "log 1\nlog 2\n"))),
+			decompressedOffset: 12,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			temp := filetest.OpenTemp(t, t.TempDir())
+			_, err := temp.Write(tt.first)
+			require.NoError(t, err)
+
+			f, sink := testFactory(t, withCompression(tt.compression))
+			fp, err := f.NewFingerprint(temp)
+			require.NoError(t, err)
+			r, err := f.NewReader(filetest.OpenFile(t, temp.Name()), fp)
+			require.NoError(t, err)
+
+			r.ReadToEnd(context.Background())
+			for _, token := range tt.firstTokens {
+				sink.ExpectToken(t, []byte(token))
+			}
+			sink.ExpectNoCalls(t)
+
+			m := r.Close()
+			assert.Equal(t, tt.offset, m.Offset)
+			assert.Equal(t, tt.decompressedOffset, m.DecompressedOffset)
+
+			_, err = temp.Write(tt.second)
+			require.NoError(t, err)
+
+			r, err = f.NewReaderFromMetadata(filetest.OpenFile(t, temp.Name()), m)
+			require.NoError(t, err)
+			defer r.Close()
+
+			r.ReadToEnd(context.Background())
+			for _, token := range tt.secondTokens {
+				sink.ExpectToken(t, []byte(token))
+			}
+			sink.ExpectNoCalls(t)
+			assert.Equal(t, int64(len(tt.first)+len(tt.second)), r.Offset)
+			assert.Zero(t, r.DecompressedOffset)
+		})
+	}
+}
@@ -44,10 +44,27 @@ type Factory struct {
 	DeleteAtEOF             bool
 	IncludeFileRecordNumber bool
 	Compression             string
+	FingerprintMode         string
 }
 
 func (f *Factory) NewFingerprint(file *os.File) (*fingerprint.Fingerprint, error) {
-	return fingerprint.NewFromFile(file, f.FingerprintSize)
+	return newFingerprint(file, f.FingerprintSize, f.FingerprintMode)
+}
+
+// newFingerprint reads the fingerprint of the file, along with its identity when the mode requires it.
+func newFingerprint(file *os.File, size int, mode string) (*fingerprint.Fingerprint, error) {
+	fp, err := fingerprint.NewFromFile(file, size)
+	if err != nil {
+		return nil, err
+	}
+	if mode == fingerprint.ModeIdentity {
+		id, err := fingerprint.NewIdentityFromFile(file, size)
+		if err != nil {
+			return nil, err
+		}
+		fp.SetIdentity(id)
+	}
+	return fp, nil
 }
 
 func (f *Factory) NewReader(file *os.File, fp *fingerprint.Fingerprint) (*Reader, error) {
@@ -77,6 +94,7 @@ func (f *Factory) NewReaderFromMetadata(file *os.File, m *Metadata) (r *Reader,
 		deleteAtEOF:          f.DeleteAtEOF,
 		includeFileRecordNum: f.IncludeFileRecordNumber,
 		compression:          f.Compression,
+		fingerprintMode:      f.FingerprintMode,
 	}
 	r.set.Logger = r.set.Logger.With(zap.String("path", r.fileName))
 
@@ -92,6 +110,16 @@ func (f *Factory) NewReaderFromMetadata(file *os.File, m *Metadata) (r *Reader,
 		m.Fingerprint = shorter
 	}
 
+	// Fingerprints restored from checkpoints saved in another mode are migrated
+	// to the current one, now that they have been matched to this file.
+	var id *fingerprint.Identity
+	if f.FingerprintMode == fingerprint.ModeIdentity {
+		if id, err = fingerprint.NewIdentityFromFile(file, r.fingerprintSize); err != nil {
+			return nil, fmt.Errorf("read identity: %w", err)
+		}
+	}
+	m.Fingerprint.SetIdentity(id)
+
 	if !f.FromBeginning {
 		var info os.FileInfo
 		if info, err = r.file.Stat(); err != nil {
@@ -5,7 +5,6 @@ package reader // import "github.com/open-telemetry/opentelemetry-collector-cont
 
 import (
 	"bufio"
-	"compress/gzip"
 	"context"
 	"errors"
 	"io"
@@ -24,12 +23,21 @@ import (
 )
 
 type Metadata struct {
-	Fingerprint     *fingerprint.Fingerprint
-	Offset          int64
-	RecordNum       int64
-	FileAttributes  map[string]any
-	HeaderFinalized bool
-	FlushState      *flush.State
+	Fingerprint *fingerprint.Fingerprint
+	// Offset is the position in the file from which to resume reading. In a compressed file, it is
+	// the start of a compressed frame, and DecompressedOffset is the position in the decompressed
+	// content of this frame.
+	Offset             int64
+	DecompressedOffset int64 `json:",omitempty"`
+	RecordNum          int64
+	FileAttributes     map[string]any
+	HeaderFinalized    bool
+	FlushState         *flush.State
+
+	// undetectedSize is the size of the file when its compression could not be detected by the last poll.
+	undetectedSize int64
+	// readSize is the size of the compressed file when its content was last read entirely.
+	readSize int64
 }
 
 // Reader manages a single file
@@ -52,45 +60,28 @@ type Reader struct {
 	needsUpdateFingerprint bool
 	includeFileRecordNum   bool
 	compression            string
+	fingerprintMode        string
+	decompressor           *decompressReader
 }
 
 // ReadToEnd will read until the end of the file
 func (r *Reader) ReadToEnd(ctx context.Context) {
-	switch r.compression {
-	case "gzip":
-		// We need to create a gzip reader each time ReadToEnd is called because the underlying
-		// SectionReader can only read a fixed window (from previous offset to EOF).
-		info, err := r.file.Stat()
-		if err != nil {
-			r.set.Logger.Error("Failed to stat", zap.Error(err))
-			return
-		}
-		currentEOF := info.Size()
-
-		// use a gzip Reader with an underlying SectionReader to pick up at the last
-		// offset of a gzip compressed file
-		gzipReader, err := gzip.NewReader(io.NewSectionReader(r.file, r.Offset, currentEOF))
-		if err != nil {
-			if !errors.Is(err, io.EOF) {
-				r.set.Logger.Error("Failed to create gzip reader", zap.Error(err))
-			}
-			return
-		} else {
-			r.reader = gzipReader
+	if r.compression == CompressionAuto {
+		compression, ok := r.detectCompression()
+		if !ok {
+			return // not enough data to detect the compression yet
 		}
-		// Offset tracking in an uncompressed file is based on the length of emitted tokens, but in this case
-		// we need to set the offset to the end of the file.
-		defer func() {
-			r.Offset = currentEOF
-		}()
-	default:
-		r.reader = r.file
+		r.compression = compression
+	}
+	if r.compression != "" && !r.grown() {
+		return // nothing was written since the content was read entirely
 	}
 
-	if _, err := r.file.Seek(r.Offset, 0); err != nil {
-		r.set.Logger.Error("Failed to seek", zap.Error(err))
+	startOffset, ok := r.openReader()
+	if !ok {
 		return
 	}
+	defer r.closeReader()
 
 	defer func() {
 		if r.needsUpdateFingerprint {
@@ -98,7 +89,7 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		}
 	}()
 
-	s := scanner.New(r, r.maxLogSize, r.initialBufferSize, r.Offset, r.splitFunc)
+	s := scanner.New(r, r.maxLogSize, r.initialBufferSize, startOffset, r.splitFunc)
 
 	// Iterate over the tokenized file, emitting entries as we go
 	for {
@@ -112,7 +103,16 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		if !ok {
 			if err := s.Error(); err != nil {
 				r.set.Logger.Error("Failed during scan", zap.Error(err))
-			} else if r.deleteAtEOF {
+				return
+			}
+			if r.decompressor != nil {
+				// move the offset past the frames which were read completely
+				r.updateOffset(s.Pos())
+				if r.decompressor.readAll(s.Pos()) {
+					r.readSize = r.decompressor.size()
+				}
+			}
+			if r.deleteAtEOF {
 				r.delete()
 			}
 			return
@@ -121,7 +121,7 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		token, err := r.decoder.Decode(s.Bytes())
 		if err != nil {
 			r.set.Logger.Error("decode: %w", zap.Error(err))
-			r.Offset = s.Pos() // move past the bad token or we may be stuck
+			r.updateOffset(s.Pos()) // move past the bad token or we may be stuck
 			continue
 		}
 
@@ -132,13 +132,13 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 
 		err = r.processFunc(ctx, token, r.FileAttributes)
 		if err == nil {
-			r.Offset = s.Pos() // successful emit, update offset
+			r.updateOffset(s.Pos()) // successful emit, update offset
 			continue
 		}
 
 		if !errors.Is(err, header.ErrEndOfHeader) {
 			r.set.Logger.Error("process: %w", zap.Error(err))
-			r.Offset = s.Pos() // move past the bad token or we may be stuck
+			r.updateOffset(s.Pos()) // move past the bad token or we may be stuck
 			continue
 		}
 
@@ -156,14 +156,60 @@ func (r *Reader) ReadToEnd(ctx context.Context) {
 		// Recreate the scanner with the normal split func.
 		// Do not use the updated offset from the old scanner, as the most recent token
 		// could be split differently with the new splitter.
-		if _, err = r.file.Seek(r.Offset, 0); err != nil {
-			r.set.Logger.Error("Failed to seek post-header", zap.Error(err))
+		r.closeReader()
+		if startOffset, ok = r.openReader(); !ok {
 			return
 		}
-		s = scanner.New(r, r.maxLogSize, scanner.DefaultBufferSize, r.Offset, r.splitFunc)
+		s = scanner.New(r, r.maxLogSize, scanner.DefaultBufferSize, startOffset, r.splitFunc)
 	}
 }
 
+// openReader prepares the reader to read from the current offset, and returns the
+// position of this offset in the content read.
+func (r *Reader) openReader() (int64, bool) {
+	if r.compression == "" {
+		if _, err := r.file.Seek(r.Offset, 0); err != nil {
+			r.set.Logger.Error("Failed to seek", zap.Error(err))
+			return 0, false
+		}
+		r.reader = r.file
+		return r.Offset, true
+	}
+
+	decompressor, err := newDecompressReader(r.file, r.compression, r.Offset)
+	if err != nil {
+		r.set.Logger.Error("Failed to create decompressor", zap.String("compression", r.compression), zap.Error(err))
+		return 0, false
+	}
+	// Skip the content of the current frame which was already read.
+	if _, err = io.CopyN(io.Discard, decompressor, r.DecompressedOffset); err != nil {
+		if !errors.Is(err, io.EOF) {
+			r.set.Logger.Error("Failed to decompress", zap.String("compression", r.compression), zap.Error(err))
+		}
+		decompressor.Close()
+		return 0, false
+	}
+	r.decompressor = decompressor
+	r.reader = decompressor
+	return r.DecompressedOffset, true
+}
+
+func (r *Reader) closeReader() {
+	if r.decompressor != nil {
+		r.decompressor.Close()
+		r.decompressor = nil
+	}
+}
+
+// updateOffset sets the offset to the position of the last token read.
+func (r *Reader) updateOffset(pos int64) {
+	if r.decompressor == nil {
+		r.Offset = pos
+		return
+	}
+	r.Offset, r.DecompressedOffset = r.decompressor.checkpoint(pos)
+}
+
 // Delete will close and delete the file
 func (r *Reader) delete() {
 	r.close()
@@ -202,7 +248,7 @@ func (r *Reader) Read(dst []byte) (n int, err error) {
 		return
 	}
 
-	if !r.needsUpdateFingerprint && r.Fingerprint.Len() < r.fingerprintSize {
+	if !r.needsUpdateFingerprint && (r.Fingerprint.Len() < r.fingerprintSize || r.Fingerprint.Identity().Incomplete()) {
 		r.needsUpdateFingerprint = true
 	}
 	return
@@ -240,7 +286,7 @@ func (r *Reader) updateFingerprint() {
 	if r.file == nil {
 		return
 	}
-	refreshedFingerprint, err := fingerprint.NewFromFile(r.file, r.fingerprintSize)
+	refreshedFingerprint, err := newFingerprint(r.file, r.fingerprintSize, r.fingerprintMode)
 	if err != nil {
 		return
 	}
@@ -9,6 +9,7 @@ require (
 	github.com/fsnotify/fsnotify v1.7.0
 	github.com/jpillora/backoff v1.0.0
 	github.com/json-iterator/go v1.1.12
+	github.com/klauspost/compress v1.17.8
 	github.com/leodido/go-syslog/v4 v4.1.0
 	github.com/open-telemetry/opentelemetry-collector-contrib/extension/storage v0.103.0
 	github.com/open-telemetry/opentelemetry-collector-contrib/internal/common v0.103.0
@@ -13,6 +13,7 @@ require (
 	go.opentelemetry.io/collector/semconv v0.103.0
 	go.uber.org/goleak v1.3.0
 	go.uber.org/multierr v1.11.0
+	google.golang.org/protobuf v1.34.2
 )
 
 require (
@@ -30,7 +31,6 @@ require (
 	golang.org/x/text v0.16.0 // indirect
 	google.golang.org/genproto/googleapis/rpc v0.0.0-20240520151616-dc85e6b867a5 // indirect
 	google.golang.org/grpc v1.64.1 // indirect
-	google.golang.org/protobuf v1.34.2 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
 
@@ -204,6 +204,7 @@ func (c *prometheusConverter) addHistogramDataPoints(dataPoints pmetric.Histogra
 	resource pcommon.Resource, settings Settings, baseName string) {
 	for x := 0; x < dataPoints.Len(); x++ {
 		pt := dataPoints.At(x)
+		c.startTimestamp = pt.StartTimestamp()
 		timestamp := convertTimeStamp(pt.Timestamp())
 		baseLabels := createAttributes(resource, pt.Attributes(), settings.ExternalLabels, nil, false)
 
@@ -387,6 +388,7 @@ func (c *prometheusConverter) addSummaryDataPoints(dataPoints pmetric.SummaryDat
 	settings Settings, baseName string) {
 	for x := 0; x < dataPoints.Len(); x++ {
 		pt := dataPoints.At(x)
+		c.startTimestamp = pt.StartTimestamp()
 		timestamp := convertTimeStamp(pt.Timestamp())
 		baseLabels := createAttributes(resource, pt.Attributes(), settings.ExternalLabels, nil, false)
 
@@ -478,6 +480,7 @@ func (c *prometheusConverter) getOrCreateTimeSeries(lbls []prompb.Label) (*promp
 			Labels: lbls,
 		}
 		c.conflicts[h] = append(c.conflicts[h], ts)
+		c.trackMetadata(ts)
 		return ts, true
 	}
 
@@ -486,6 +489,7 @@ func (c *prometheusConverter) getOrCreateTimeSeries(lbls []prompb.Label) (*promp
 		Labels: lbls,
 	}
 	c.unique[h] = ts
+	c.trackMetadata(ts)
 	return ts, true
 }
 
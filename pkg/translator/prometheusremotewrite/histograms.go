@@ -20,6 +20,7 @@ func (c *prometheusConverter) addExponentialHistogramDataPoints(dataPoints pmetr
 	resource pcommon.Resource, settings Settings, baseName string) error {
 	for x := 0; x < dataPoints.Len(); x++ {
 		pt := dataPoints.At(x)
+		c.startTimestamp = pt.StartTimestamp()
 		lbls := createAttributes(
 			resource,
 			pt.Attributes(),
@@ -15,6 +15,7 @@ import (
 	"go.uber.org/multierr"
 
 	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
 )
 
 type Settings struct {
@@ -43,6 +44,14 @@ func FromMetrics(md pmetric.Metrics, settings Settings) (map[string]*prompb.Time
 type prometheusConverter struct {
 	unique    map[uint64]*prompb.TimeSeries
 	conflicts map[uint64][]*prompb.TimeSeries
+
+	// metadata holds the metadata of the time series when converting to the remote-write 2.0
+	// format, and is nil otherwise.
+	metadata map[*prompb.TimeSeries]seriesMetadata
+	// current is the metadata of the metric being converted, and startTimestamp the start
+	// timestamp of the data point being converted.
+	current        seriesMetadata
+	startTimestamp pcommon.Timestamp
 }
 
 func newPrometheusConverter() *prometheusConverter {
@@ -76,6 +85,12 @@ func (c *prometheusConverter) fromMetrics(md pmetric.Metrics, settings Settings)
 				}
 
 				promName := prometheustranslator.BuildCompliantName(metric, settings.Namespace, settings.AddMetricSuffixes)
+				c.current = seriesMetadata{
+					typ:  writev2.MetricType(otelMetricTypeToPromMetricType(metric)),
+					help: metric.Description(),
+					unit: metric.Unit(),
+				}
+				c.startTimestamp = 0
 
 				// handle individual metrics based on type
 				//exhaustive:enforce
@@ -125,6 +140,8 @@ func (c *prometheusConverter) fromMetrics(md pmetric.Metrics, settings Settings)
 				}
 			}
 		}
+		c.current = seriesMetadata{typ: writev2.MetricTypeGauge, help: targetInfoHelp}
+		c.startTimestamp = 0
 		addResourceTargetInfo(resource, settings, mostRecentTimestamp, c)
 	}
 
@@ -0,0 +1,162 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite"
+
+import (
+	"github.com/prometheus/prometheus/prompb"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+const targetInfoHelp = "Target metadata"
+
+// seriesMetadata is the metadata sent inline with each time series by remote-write 2.0.
+type seriesMetadata struct {
+	typ              writev2.MetricType
+	help             string
+	unit             string
+	createdTimestamp int64
+}
+
+// TimeSeriesV2 is a time series along with the metadata of its metric, as sent by the
+// remote-write 2.0 protocol.
+type TimeSeriesV2 struct {
+	prompb.TimeSeries
+	Type writev2.MetricType
+	Help string
+	Unit string
+	// CreatedTimestamp is the start timestamp in milliseconds of the cumulative metric, or 0 if unknown.
+	CreatedTimestamp int64
+}
+
+// FromMetricsV2 converts pmetric.Metrics to time series for the Prometheus remote-write 2.0 format.
+// The created timestamps are sent with the series, so ExportCreatedMetric is ignored.
+func FromMetricsV2(md pmetric.Metrics, settings Settings) ([]TimeSeriesV2, error) {
+	settings.ExportCreatedMetric = false
+	c := newPrometheusConverter()
+	c.metadata = map[*prompb.TimeSeries]seriesMetadata{}
+	errs := c.fromMetrics(md, settings)
+
+	out := make([]TimeSeriesV2, 0, len(c.metadata))
+	add := func(ts *prompb.TimeSeries) {
+		m := c.metadata[ts]
+		out = append(out, TimeSeriesV2{
+			TimeSeries:       *ts,
+			Type:             m.typ,
+			Help:             m.help,
+			Unit:             m.unit,
+			CreatedTimestamp: m.createdTimestamp,
+		})
+	}
+	for _, ts := range c.unique {
+		add(ts)
+	}
+	for _, cTS := range c.conflicts {
+		for _, ts := range cTS {
+			add(ts)
+		}
+	}
+	return out, errs
+}
+
+// trackMetadata records the metadata of a new time series when converting to remote-write 2.0.
+func (c *prometheusConverter) trackMetadata(ts *prompb.TimeSeries) {
+	if c.metadata == nil {
+		return
+	}
+	m := c.current
+	switch m.typ {
+	case writev2.MetricTypeCounter, writev2.MetricTypeHistogram, writev2.MetricTypeSummary:
+		if c.startTimestamp != 0 {
+			m.createdTimestamp = convertTimeStamp(c.startTimestamp)
+		}
+	}
+	c.metadata[ts] = m
+}
+
+// NewRequestV2 builds a remote-write 2.0 request holding the time series, whose strings are
+// interned in the symbols of the request.
+func NewRequestV2(series []TimeSeriesV2) *writev2.Request {
+	symbols := writev2.NewSymbolsTable()
+	req := &writev2.Request{Timeseries: make([]writev2.TimeSeries, len(series))}
+	for i, s := range series {
+		ts := &req.Timeseries[i]
+		ts.LabelsRefs = symbolizeLabels(symbols, s.Labels, nil)
+		ts.Metadata = writev2.Metadata{
+			Type:    s.Type,
+			HelpRef: symbols.Symbolize(s.Help),
+			UnitRef: symbols.Symbolize(s.Unit),
+		}
+		ts.CreatedTimestamp = s.CreatedTimestamp
+
+		if len(s.Samples) > 0 {
+			ts.Samples = make([]writev2.Sample, len(s.Samples))
+			for j, sample := range s.Samples {
+				ts.Samples[j] = writev2.Sample{Value: sample.Value, Timestamp: sample.Timestamp}
+			}
+		}
+		if len(s.Histograms) > 0 {
+			ts.Histograms = make([]writev2.Histogram, len(s.Histograms))
+			for j := range s.Histograms {
+				ts.Histograms[j] = histogramToV2(&s.Histograms[j])
+			}
+		}
+		if len(s.Exemplars) > 0 {
+			ts.Exemplars = make([]writev2.Exemplar, len(s.Exemplars))
+			for j, e := range s.Exemplars {
+				ts.Exemplars[j] = writev2.Exemplar{
+					LabelsRefs: symbolizeLabels(symbols, e.Labels, nil),
+					Value:      e.Value,
+					Timestamp:  e.Timestamp,
+				}
+			}
+		}
+	}
+	req.Symbols = symbols.Symbols()
+	return req
+}
+
+func symbolizeLabels(symbols *writev2.SymbolsTable, lbls []prompb.Label, refs []uint32) []uint32 {
+	for _, l := range lbls {
+		refs = append(refs, symbols.Symbolize(l.Name), symbols.Symbolize(l.Value))
+	}
+	return refs
+}
+
+func histogramToV2(h *prompb.Histogram) writev2.Histogram {
+	out := writev2.Histogram{
+		Sum:            h.Sum,
+		Schema:         h.Schema,
+		ZeroThreshold:  h.ZeroThreshold,
+		NegativeSpans:  spansToV2(h.NegativeSpans),
+		NegativeDeltas: h.NegativeDeltas,
+		NegativeCounts: h.NegativeCounts,
+		PositiveSpans:  spansToV2(h.PositiveSpans),
+		PositiveDeltas: h.PositiveDeltas,
+		PositiveCounts: h.PositiveCounts,
+		ResetHint:      writev2.ResetHint(h.ResetHint),
+		Timestamp:      h.Timestamp,
+	}
+	if h.IsFloatHistogram() {
+		out.Float = true
+		out.CountFloat = h.GetCountFloat()
+		out.ZeroCountFloat = h.GetZeroCountFloat()
+	} else {
+		out.CountInt = h.GetCountInt()
+		out.ZeroCountInt = h.GetZeroCountInt()
+	}
+	return out
+}
+
+func spansToV2(spans []prompb.BucketSpan) []writev2.BucketSpan {
+	if len(spans) == 0 {
+		return nil
+	}
+	out := make([]writev2.BucketSpan, len(spans))
+	for i, s := range spans {
+		out[i] = writev2.BucketSpan{Offset: s.Offset, Length: s.Length}
+	}
+	return out
+}
@@ -0,0 +1,151 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewrite
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+func TestFromMetricsV2(t *testing.T) {
+	start := time.Unix(100, 0)
+	ts := time.Unix(200, 0)
+
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.name", "api")
+	rm.Resource().Attributes().PutStr("host.name", "web-1")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	counter := metrics.AppendEmpty()
+	counter.SetName("requests")
+	counter.SetDescription("Number of requests")
+	counter.SetUnit("1")
+	sum := counter.SetEmptySum()
+	sum.SetIsMonotonic(true)
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	dp := sum.DataPoints().AppendEmpty()
+	dp.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	dp.SetIntValue(5)
+
+	gauge := metrics.AppendEmpty()
+	gauge.SetName("temperature")
+	gauge.SetUnit("Cel")
+	gdp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	gdp.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	gdp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	gdp.SetDoubleValue(21.5)
+
+	expHist := metrics.AppendEmpty()
+	expHist.SetName("latency")
+	eh := expHist.SetEmptyExponentialHistogram()
+	eh.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	edp := eh.DataPoints().AppendEmpty()
+	edp.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	edp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	edp.SetCount(3)
+	edp.SetSum(6)
+	edp.Positive().BucketCounts().FromRaw([]uint64{1, 2})
+
+	series, err := FromMetricsV2(md, Settings{ExportCreatedMetric: true})
+	require.NoError(t, err)
+
+	byName := map[string]TimeSeriesV2{}
+	for _, s := range series {
+		for _, l := range s.Labels {
+			if l.Name == "__name__" {
+				byName[l.Value] = s
+			}
+		}
+	}
+	// the created timestamps are sent inline rather than as _created series
+	require.Len(t, byName, 4)
+	assert.NotContains(t, byName, "requests_created")
+
+	assert.Equal(t, writev2.MetricTypeCounter, byName["requests"].Type)
+	assert.Equal(t, "Number of requests", byName["requests"].Help)
+	assert.Equal(t, "1", byName["requests"].Unit)
+	assert.Equal(t, start.UnixMilli(), byName["requests"].CreatedTimestamp)
+
+	assert.Equal(t, writev2.MetricTypeGauge, byName["temperature"].Type)
+	assert.Equal(t, "Cel", byName["temperature"].Unit)
+	assert.Zero(t, byName["temperature"].CreatedTimestamp)
+
+	assert.Equal(t, writev2.MetricTypeHistogram, byName["latency"].Type)
+	assert.Equal(t, start.UnixMilli(), byName["latency"].CreatedTimestamp)
+	require.Len(t, byName["latency"].Histograms, 1)
+
+	assert.Equal(t, writev2.MetricTypeGauge, byName["target_info"].Type)
+	assert.Equal(t, targetInfoHelp, byName["target_info"].Help)
+}
+
+func TestNewRequestV2(t *testing.T) {
+	series := []TimeSeriesV2{
+		{
+			TimeSeries: prompb.TimeSeries{
+				Labels:    getPromLabels("__name__", "requests", "job", "api"),
+				Samples:   []prompb.Sample{getSample(5, 2000)},
+				Exemplars: []prompb.Exemplar{{Labels: getPromLabels("trace_id", "abc"), Value: 1, Timestamp: 1500}},
+			},
+			Type:             writev2.MetricTypeCounter,
+			Help:             "Number of requests",
+			CreatedTimestamp: 1000,
+		},
+		{
+			TimeSeries: prompb.TimeSeries{
+				Labels: getPromLabels("__name__", "latency", "job", "api"),
+				Histograms: []prompb.Histogram{{
+					Count:          &prompb.Histogram_CountInt{CountInt: 3},
+					Sum:            6,
+					Schema:         1,
+					ZeroCount:      &prompb.Histogram_ZeroCountInt{ZeroCountInt: 0},
+					PositiveSpans:  []prompb.BucketSpan{{Offset: 1, Length: 2}},
+					PositiveDeltas: []int64{1, 1},
+					Timestamp:      2000,
+				}},
+			},
+			Type: writev2.MetricTypeHistogram,
+		},
+	}
+
+	req := NewRequestV2(series)
+	require.Len(t, req.Timeseries, 2)
+	assert.Equal(t, "", req.Symbols[0])
+	// the labels shared by the series are interned once
+	assert.Equal(t, []string{"", "__name__", "requests", "job", "api", "Number of requests", "trace_id", "abc", "latency"}, req.Symbols)
+
+	labels := map[string]string{}
+	require.NoError(t, req.Labels(req.Timeseries[0].LabelsRefs, func(name, value string) { labels[name] = value }))
+	assert.Equal(t, map[string]string{"__name__": "requests", "job": "api"}, labels)
+	assert.Equal(t, []writev2.Sample{{Value: 5, Timestamp: 2000}}, req.Timeseries[0].Samples)
+	assert.Equal(t, writev2.Metadata{Type: writev2.MetricTypeCounter, HelpRef: 5}, req.Timeseries[0].Metadata)
+	assert.Equal(t, int64(1000), req.Timeseries[0].CreatedTimestamp)
+	assert.Equal(t, []writev2.Exemplar{{LabelsRefs: []uint32{6, 7}, Value: 1, Timestamp: 1500}}, req.Timeseries[0].Exemplars)
+
+	assert.Equal(t, []writev2.Histogram{{
+		CountInt:       3,
+		Sum:            6,
+		Schema:         1,
+		PositiveSpans:  []writev2.BucketSpan{{Offset: 1, Length: 2}},
+		PositiveDeltas: []int64{1, 1},
+		Timestamp:      2000,
+	}}, req.Timeseries[1].Histograms)
+
+	// the request survives a round trip through the wire format
+	data, err := req.Marshal()
+	require.NoError(t, err)
+	var decoded writev2.Request
+	require.NoError(t, decoded.Unmarshal(data))
+	assert.Equal(t, req.Symbols, decoded.Symbols)
+	assert.Len(t, decoded.Timeseries, 2)
+}
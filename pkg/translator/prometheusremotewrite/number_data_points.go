@@ -47,6 +47,7 @@ func (c *prometheusConverter) addSumNumberDataPoints(dataPoints pmetric.NumberDa
 	resource pcommon.Resource, metric pmetric.Metric, settings Settings, name string) {
 	for x := 0; x < dataPoints.Len(); x++ {
 		pt := dataPoints.At(x)
+		c.startTimestamp = pt.StartTimestamp()
 		lbls := createAttributes(
 			resource,
 			pt.Attributes(),
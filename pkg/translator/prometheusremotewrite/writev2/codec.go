@@ -0,0 +1,409 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package writev2 // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+var errInvalidWireType = errors.New("invalid wire type")
+
+// Marshal encodes the request in the protobuf wire format.
+func (r *Request) Marshal() ([]byte, error) {
+	var b []byte
+	for _, s := range r.Symbols {
+		b = protowire.AppendTag(b, 4, protowire.BytesType)
+		b = protowire.AppendString(b, s)
+	}
+	for i := range r.Timeseries {
+		b = appendMessage(b, 5, r.Timeseries[i].append)
+	}
+	return b, nil
+}
+
+// Unmarshal decodes the request from the protobuf wire format.
+func (r *Request) Unmarshal(b []byte) error {
+	*r = Request{}
+	return consumeMessage(b, func(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+		switch {
+		case num == 4 && typ == protowire.BytesType:
+			s, n := protowire.ConsumeString(b)
+			if n < 0 {
+				return n, nil
+			}
+			r.Symbols = append(r.Symbols, s)
+			return n, nil
+		case num == 5 && typ == protowire.BytesType:
+			r.Timeseries = append(r.Timeseries, TimeSeries{})
+			return consumeEmbedded(b, r.Timeseries[len(r.Timeseries)-1].consume)
+		}
+		return unknownField, nil
+	})
+}
+
+func (ts *TimeSeries) append(b []byte) []byte {
+	b = appendPackedUint32(b, 1, ts.LabelsRefs)
+	for i := range ts.Samples {
+		b = appendMessage(b, 2, ts.Samples[i].append)
+	}
+	for i := range ts.Histograms {
+		b = appendMessage(b, 3, ts.Histograms[i].append)
+	}
+	for i := range ts.Exemplars {
+		b = appendMessage(b, 4, ts.Exemplars[i].append)
+	}
+	if ts.Metadata != (Metadata{}) {
+		b = appendMessage(b, 5, ts.Metadata.append)
+	}
+	return appendVarint(b, 6, uint64(ts.CreatedTimestamp))
+}
+
+func (ts *TimeSeries) consume(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+	switch num {
+	case 1:
+		return consumeRepeatedUint32(b, typ, &ts.LabelsRefs)
+	case 2:
+		if typ == protowire.BytesType {
+			ts.Samples = append(ts.Samples, Sample{})
+			return consumeEmbedded(b, ts.Samples[len(ts.Samples)-1].consume)
+		}
+	case 3:
+		if typ == protowire.BytesType {
+			ts.Histograms = append(ts.Histograms, Histogram{})
+			return consumeEmbedded(b, ts.Histograms[len(ts.Histograms)-1].consume)
+		}
+	case 4:
+		if typ == protowire.BytesType {
+			ts.Exemplars = append(ts.Exemplars, Exemplar{})
+			return consumeEmbedded(b, ts.Exemplars[len(ts.Exemplars)-1].consume)
+		}
+	case 5:
+		if typ == protowire.BytesType {
+			return consumeEmbedded(b, ts.Metadata.consume)
+		}
+	case 6:
+		return consumeVarint(b, typ, func(v uint64) { ts.CreatedTimestamp = int64(v) })
+	}
+	return unknownField, nil
+}
+
+func (s *Sample) append(b []byte) []byte {
+	b = appendDouble(b, 1, s.Value)
+	return appendVarint(b, 2, uint64(s.Timestamp))
+}
+
+func (s *Sample) consume(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+	switch num {
+	case 1:
+		return consumeDouble(b, typ, &s.Value)
+	case 2:
+		return consumeVarint(b, typ, func(v uint64) { s.Timestamp = int64(v) })
+	}
+	return unknownField, nil
+}
+
+func (e *Exemplar) append(b []byte) []byte {
+	b = appendPackedUint32(b, 1, e.LabelsRefs)
+	b = appendDouble(b, 2, e.Value)
+	return appendVarint(b, 3, uint64(e.Timestamp))
+}
+
+func (e *Exemplar) consume(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+	switch num {
+	case 1:
+		return consumeRepeatedUint32(b, typ, &e.LabelsRefs)
+	case 2:
+		return consumeDouble(b, typ, &e.Value)
+	case 3:
+		return consumeVarint(b, typ, func(v uint64) { e.Timestamp = int64(v) })
+	}
+	return unknownField, nil
+}
+
+func (m *Metadata) append(b []byte) []byte {
+	b = appendVarint(b, 1, uint64(m.Type))
+	b = appendVarint(b, 3, uint64(m.HelpRef))
+	return appendVarint(b, 4, uint64(m.UnitRef))
+}
+
+func (m *Metadata) consume(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+	switch num {
+	case 1:
+		return consumeVarint(b, typ, func(v uint64) { m.Type = MetricType(v) })
+	case 3:
+		return consumeVarint(b, typ, func(v uint64) { m.HelpRef = uint32(v) })
+	case 4:
+		return consumeVarint(b, typ, func(v uint64) { m.UnitRef = uint32(v) })
+	}
+	return unknownField, nil
+}
+
+func (h *Histogram) append(b []byte) []byte {
+	// the counts are fields of a oneof, which are encoded even if they are zero
+	if h.Float {
+		b = protowire.AppendTag(b, 2, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(h.CountFloat))
+	} else {
+		b = protowire.AppendTag(b, 1, protowire.VarintType)
+		b = protowire.AppendVarint(b, h.CountInt)
+	}
+	b = appendDouble(b, 3, h.Sum)
+	b = appendVarint(b, 4, protowire.EncodeZigZag(int64(h.Schema)))
+	b = appendDouble(b, 5, h.ZeroThreshold)
+	if h.Float {
+		b = protowire.AppendTag(b, 7, protowire.Fixed64Type)
+		b = protowire.AppendFixed64(b, math.Float64bits(h.ZeroCountFloat))
+	} else {
+		b = protowire.AppendTag(b, 6, protowire.VarintType)
+		b = protowire.AppendVarint(b, h.ZeroCountInt)
+	}
+	for i := range h.NegativeSpans {
+		b = appendMessage(b, 8, h.NegativeSpans[i].append)
+	}
+	b = appendPackedSint64(b, 9, h.NegativeDeltas)
+	b = appendPackedDouble(b, 10, h.NegativeCounts)
+	for i := range h.PositiveSpans {
+		b = appendMessage(b, 11, h.PositiveSpans[i].append)
+	}
+	b = appendPackedSint64(b, 12, h.PositiveDeltas)
+	b = appendPackedDouble(b, 13, h.PositiveCounts)
+	b = appendVarint(b, 14, uint64(h.ResetHint))
+	b = appendVarint(b, 15, uint64(h.Timestamp))
+	return appendPackedDouble(b, 16, h.CustomValues)
+}
+
+func (h *Histogram) consume(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+	switch num {
+	case 1:
+		return consumeVarint(b, typ, func(v uint64) { h.CountInt, h.Float = v, false })
+	case 2:
+		h.Float = true
+		return consumeDouble(b, typ, &h.CountFloat)
+	case 3:
+		return consumeDouble(b, typ, &h.Sum)
+	case 4:
+		return consumeVarint(b, typ, func(v uint64) { h.Schema = int32(protowire.DecodeZigZag(v)) })
+	case 5:
+		return consumeDouble(b, typ, &h.ZeroThreshold)
+	case 6:
+		return consumeVarint(b, typ, func(v uint64) { h.ZeroCountInt = v })
+	case 7:
+		return consumeDouble(b, typ, &h.ZeroCountFloat)
+	case 8:
+		if typ == protowire.BytesType {
+			h.NegativeSpans = append(h.NegativeSpans, BucketSpan{})
+			return consumeEmbedded(b, h.NegativeSpans[len(h.NegativeSpans)-1].consume)
+		}
+	case 9:
+		return consumeRepeatedSint64(b, typ, &h.NegativeDeltas)
+	case 10:
+		return consumeRepeatedDouble(b, typ, &h.NegativeCounts)
+	case 11:
+		if typ == protowire.BytesType {
+			h.PositiveSpans = append(h.PositiveSpans, BucketSpan{})
+			return consumeEmbedded(b, h.PositiveSpans[len(h.PositiveSpans)-1].consume)
+		}
+	case 12:
+		return consumeRepeatedSint64(b, typ, &h.PositiveDeltas)
+	case 13:
+		return consumeRepeatedDouble(b, typ, &h.PositiveCounts)
+	case 14:
+		return consumeVarint(b, typ, func(v uint64) { h.ResetHint = ResetHint(v) })
+	case 15:
+		return consumeVarint(b, typ, func(v uint64) { h.Timestamp = int64(v) })
+	case 16:
+		return consumeRepeatedDouble(b, typ, &h.CustomValues)
+	}
+	return unknownField, nil
+}
+
+func (s *BucketSpan) append(b []byte) []byte {
+	b = appendVarint(b, 1, protowire.EncodeZigZag(int64(s.Offset)))
+	return appendVarint(b, 2, uint64(s.Length))
+}
+
+func (s *BucketSpan) consume(num protowire.Number, typ protowire.Type, b []byte) (int, error) {
+	switch num {
+	case 1:
+		return consumeVarint(b, typ, func(v uint64) { s.Offset = int32(protowire.DecodeZigZag(v)) })
+	case 2:
+		return consumeVarint(b, typ, func(v uint64) { s.Length = uint32(v) })
+	}
+	return unknownField, nil
+}
+
+// unknownField is returned by a consumeFunc for the fields it does not know, which are skipped.
+const unknownField = math.MinInt
+
+// consumeFunc consumes the value of a field and returns its length, or protowire's negative
+// error code if the field cannot be parsed.
+type consumeFunc func(num protowire.Number, typ protowire.Type, b []byte) (int, error)
+
+func consumeMessage(b []byte, fn consumeFunc) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+		n, err := fn(num, typ, b)
+		if err != nil {
+			return fmt.Errorf("field %d: %w", num, err)
+		}
+		if n == unknownField {
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return fmt.Errorf("field %d: %w", num, protowire.ParseError(n))
+		}
+		b = b[n:]
+	}
+	return nil
+}
+
+func consumeEmbedded(b []byte, fn consumeFunc) (int, error) {
+	v, n := protowire.ConsumeBytes(b)
+	if n < 0 {
+		return n, nil
+	}
+	return n, consumeMessage(v, fn)
+}
+
+func consumeVarint(b []byte, typ protowire.Type, fn func(uint64)) (int, error) {
+	if typ != protowire.VarintType {
+		return 0, errInvalidWireType
+	}
+	v, n := protowire.ConsumeVarint(b)
+	if n >= 0 {
+		fn(v)
+	}
+	return n, nil
+}
+
+func consumeDouble(b []byte, typ protowire.Type, dst *float64) (int, error) {
+	if typ != protowire.Fixed64Type {
+		return 0, errInvalidWireType
+	}
+	v, n := protowire.ConsumeFixed64(b)
+	if n >= 0 {
+		*dst = math.Float64frombits(v)
+	}
+	return n, nil
+}
+
+// consumeRepeated consumes a repeated scalar field, which is either packed or made of one field per element.
+func consumeRepeated(b []byte, typ protowire.Type, elemType protowire.Type, fn func(b []byte) int) (int, error) {
+	if typ == elemType {
+		return fn(b), nil
+	}
+	if typ != protowire.BytesType {
+		return 0, errInvalidWireType
+	}
+	packed, n := protowire.ConsumeBytes(b)
+	if n < 0 {
+		return n, nil
+	}
+	for len(packed) > 0 {
+		m := fn(packed)
+		if m < 0 {
+			return m, nil
+		}
+		packed = packed[m:]
+	}
+	return n, nil
+}
+
+func consumeRepeatedUint32(b []byte, typ protowire.Type, dst *[]uint32) (int, error) {
+	return consumeRepeated(b, typ, protowire.VarintType, func(b []byte) int {
+		v, n := protowire.ConsumeVarint(b)
+		if n >= 0 {
+			*dst = append(*dst, uint32(v))
+		}
+		return n
+	})
+}
+
+func consumeRepeatedSint64(b []byte, typ protowire.Type, dst *[]int64) (int, error) {
+	return consumeRepeated(b, typ, protowire.VarintType, func(b []byte) int {
+		v, n := protowire.ConsumeVarint(b)
+		if n >= 0 {
+			*dst = append(*dst, protowire.DecodeZigZag(v))
+		}
+		return n
+	})
+}
+
+func consumeRepeatedDouble(b []byte, typ protowire.Type, dst *[]float64) (int, error) {
+	return consumeRepeated(b, typ, protowire.Fixed64Type, func(b []byte) int {
+		v, n := protowire.ConsumeFixed64(b)
+		if n >= 0 {
+			*dst = append(*dst, math.Float64frombits(v))
+		}
+		return n
+	})
+}
+
+func appendMessage(b []byte, num protowire.Number, fn func([]byte) []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, fn(nil))
+}
+
+// appendVarint appends the varint field, unless it has the default value.
+func appendVarint(b []byte, num protowire.Number, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.VarintType)
+	return protowire.AppendVarint(b, v)
+}
+
+// appendDouble appends the double field, unless it has the default value.
+func appendDouble(b []byte, num protowire.Number, v float64) []byte {
+	bits := math.Float64bits(v)
+	if bits == 0 {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.Fixed64Type)
+	return protowire.AppendFixed64(b, bits)
+}
+
+func appendPackedUint32(b []byte, num protowire.Number, vs []uint32) []byte {
+	if len(vs) == 0 {
+		return b
+	}
+	var packed []byte
+	for _, v := range vs {
+		packed = protowire.AppendVarint(packed, uint64(v))
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, packed)
+}
+
+func appendPackedSint64(b []byte, num protowire.Number, vs []int64) []byte {
+	if len(vs) == 0 {
+		return b
+	}
+	var packed []byte
+	for _, v := range vs {
+		packed = protowire.AppendVarint(packed, protowire.EncodeZigZag(v))
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, packed)
+}
+
+func appendPackedDouble(b []byte, num protowire.Number, vs []float64) []byte {
+	if len(vs) == 0 {
+		return b
+	}
+	packed := make([]byte, 0, 8*len(vs))
+	for _, v := range vs {
+		packed = protowire.AppendFixed64(packed, math.Float64bits(v))
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, packed)
+}
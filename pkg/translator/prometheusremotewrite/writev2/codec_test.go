@@ -0,0 +1,143 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package writev2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestRequestRoundTrip(t *testing.T) {
+	symbols := NewSymbolsTable()
+	series := TimeSeries{
+		LabelsRefs: symbols.SymbolizeLabels([]string{"__name__", "job"}, []string{"http_requests_total", "api"}, nil),
+		Samples: []Sample{
+			{Value: 1.5, Timestamp: 1000},
+			{Value: 2, Timestamp: 2000},
+		},
+		Exemplars: []Exemplar{
+			{LabelsRefs: symbols.SymbolizeLabels([]string{"trace_id"}, []string{"0102"}, nil), Value: 1, Timestamp: 1500},
+		},
+		Metadata: Metadata{
+			Type:    MetricTypeCounter,
+			HelpRef: symbols.Symbolize("Number of requests"),
+		},
+		CreatedTimestamp: 500,
+	}
+	histograms := TimeSeries{
+		LabelsRefs: symbols.SymbolizeLabels([]string{"__name__"}, []string{"latency"}, nil),
+		Histograms: []Histogram{
+			{
+				CountInt:       12,
+				Sum:            -3.5,
+				Schema:         -2,
+				ZeroThreshold:  0.001,
+				ZeroCountInt:   0,
+				NegativeSpans:  []BucketSpan{{Offset: -3, Length: 2}},
+				NegativeDeltas: []int64{4, -2},
+				PositiveSpans:  []BucketSpan{{Offset: 1, Length: 1}, {Offset: 2, Length: 2}},
+				PositiveDeltas: []int64{1, 2, -1},
+				ResetHint:      ResetHintNo,
+				Timestamp:      3000,
+			},
+			{
+				Float:          true,
+				CountFloat:     2.5,
+				ZeroCountFloat: 0.5,
+				Schema:         3,
+				PositiveSpans:  []BucketSpan{{Offset: 0, Length: 2}},
+				PositiveCounts: []float64{1, 1},
+				ResetHint:      ResetHintGauge,
+				Timestamp:      4000,
+			},
+			{
+				CountInt:     3,
+				Schema:       -53,
+				CustomValues: []float64{0.1, 1},
+				Timestamp:    5000,
+			},
+		},
+		Metadata: Metadata{
+			Type:    MetricTypeHistogram,
+			UnitRef: symbols.Symbolize("seconds"),
+		},
+	}
+	req := &Request{
+		Symbols:    symbols.Symbols(),
+		Timeseries: []TimeSeries{series, histograms},
+	}
+
+	data, err := req.Marshal()
+	require.NoError(t, err)
+	var got Request
+	require.NoError(t, got.Unmarshal(data))
+	assert.Equal(t, *req, got)
+}
+
+func TestUnmarshalUnknownFields(t *testing.T) {
+	var b []byte
+	b = protowire.AppendTag(b, 4, protowire.BytesType)
+	b = protowire.AppendString(b, "")
+	// reserved fields of the request, used by the 1.0 protocol
+	b = protowire.AppendTag(b, 1, protowire.BytesType)
+	b = protowire.AppendBytes(b, []byte{0x0a, 0x00})
+	b = protowire.AppendTag(b, 99, protowire.VarintType)
+	b = protowire.AppendVarint(b, 42)
+	b = protowire.AppendTag(b, 4, protowire.BytesType)
+	b = protowire.AppendString(b, "a")
+
+	var req Request
+	require.NoError(t, req.Unmarshal(b))
+	assert.Equal(t, Request{Symbols: []string{"", "a"}}, req)
+}
+
+func TestUnmarshalUnpackedRepeatedFields(t *testing.T) {
+	var series []byte
+	for _, ref := range []uint64{1, 2} {
+		series = protowire.AppendTag(series, 1, protowire.VarintType)
+		series = protowire.AppendVarint(series, ref)
+	}
+	var b []byte
+	b = protowire.AppendTag(b, 5, protowire.BytesType)
+	b = protowire.AppendBytes(b, series)
+
+	var req Request
+	require.NoError(t, req.Unmarshal(b))
+	require.Len(t, req.Timeseries, 1)
+	assert.Equal(t, []uint32{1, 2}, req.Timeseries[0].LabelsRefs)
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		err  string
+	}{
+		{
+			name: "truncated",
+			data: []byte{0x22, 0x05, 'a'},
+			err:  "field 4: unexpected EOF",
+		},
+		{
+			name: "wrong wire type",
+			data: func() []byte {
+				var series []byte
+				series = protowire.AppendTag(series, 6, protowire.Fixed64Type)
+				series = protowire.AppendFixed64(series, 1)
+				b := protowire.AppendTag(nil, 5, protowire.BytesType)
+				return protowire.AppendBytes(b, series)
+			}(),
+			err: "field 5: field 6: invalid wire type",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req Request
+			assert.EqualError(t, req.Unmarshal(tt.data), tt.err)
+		})
+	}
+}
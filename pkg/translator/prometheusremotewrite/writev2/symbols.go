@@ -0,0 +1,80 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package writev2 // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+
+import (
+	"fmt"
+)
+
+// SymbolsTable deduplicates the strings of a request.
+type SymbolsTable struct {
+	symbols []string
+	refs    map[string]uint32
+}
+
+// NewSymbolsTable returns a table holding the empty string only.
+func NewSymbolsTable() *SymbolsTable {
+	return &SymbolsTable{
+		symbols: []string{""},
+		refs:    map[string]uint32{"": 0},
+	}
+}
+
+// Symbolize returns the reference of the string, adding it to the table if needed.
+func (t *SymbolsTable) Symbolize(s string) uint32 {
+	if ref, ok := t.refs[s]; ok {
+		return ref
+	}
+	ref := uint32(len(t.symbols))
+	t.symbols = append(t.symbols, s)
+	t.refs[s] = ref
+	return ref
+}
+
+// SymbolizeLabels appends the references of the names and values of the labels to refs.
+func (t *SymbolsTable) SymbolizeLabels(names []string, values []string, refs []uint32) []uint32 {
+	for i := range names {
+		refs = append(refs, t.Symbolize(names[i]), t.Symbolize(values[i]))
+	}
+	return refs
+}
+
+// Symbols returns the strings of the table, in the order of their references.
+func (t *SymbolsTable) Symbols() []string {
+	return t.symbols
+}
+
+// Reset empties the table, so that it can be reused for another request.
+func (t *SymbolsTable) Reset() {
+	clear(t.refs)
+	t.symbols = append(t.symbols[:0], "")
+	t.refs[""] = 0
+}
+
+// Symbol returns the string referenced by ref.
+func (r *Request) Symbol(ref uint32) (string, error) {
+	if int(ref) >= len(r.Symbols) {
+		return "", fmt.Errorf("symbol reference %d out of range, the request has %d symbols", ref, len(r.Symbols))
+	}
+	return r.Symbols[ref], nil
+}
+
+// Labels calls fn with the name and value of each label referenced by refs.
+func (r *Request) Labels(refs []uint32, fn func(name, value string)) error {
+	if len(refs)%2 != 0 {
+		return fmt.Errorf("odd number of label references: %d", len(refs))
+	}
+	for i := 0; i < len(refs); i += 2 {
+		name, err := r.Symbol(refs[i])
+		if err != nil {
+			return err
+		}
+		value, err := r.Symbol(refs[i+1])
+		if err != nil {
+			return err
+		}
+		fn(name, value)
+	}
+	return nil
+}
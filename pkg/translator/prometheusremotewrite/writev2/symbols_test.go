@@ -0,0 +1,40 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package writev2
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSymbolsTable(t *testing.T) {
+	table := NewSymbolsTable()
+	assert.Equal(t, uint32(0), table.Symbolize(""))
+	assert.Equal(t, uint32(1), table.Symbolize("a"))
+	assert.Equal(t, uint32(2), table.Symbolize("b"))
+	assert.Equal(t, uint32(1), table.Symbolize("a"))
+	assert.Equal(t, []uint32{1, 2, 3, 1}, table.SymbolizeLabels([]string{"a", "c"}, []string{"b", "a"}, nil))
+	assert.Equal(t, []string{"", "a", "b", "c"}, table.Symbols())
+
+	table.Reset()
+	assert.Equal(t, []string{""}, table.Symbols())
+	assert.Equal(t, uint32(1), table.Symbolize("c"))
+}
+
+func TestRequestLabels(t *testing.T) {
+	req := &Request{Symbols: []string{"", "__name__", "up", "job"}}
+
+	var names, values []string
+	require.NoError(t, req.Labels([]uint32{1, 2, 3, 0}, func(name, value string) {
+		names = append(names, name)
+		values = append(values, value)
+	}))
+	assert.Equal(t, []string{"__name__", "job"}, names)
+	assert.Equal(t, []string{"up", ""}, values)
+
+	assert.EqualError(t, req.Labels([]uint32{1}, func(string, string) {}), "odd number of label references: 1")
+	assert.EqualError(t, req.Labels([]uint32{1, 4}, func(string, string) {}), "symbol reference 4 out of range, the request has 4 symbols")
+}
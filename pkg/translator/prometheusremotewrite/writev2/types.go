@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package writev2 implements the messages of the Prometheus Remote-Write 2.0 protocol
+// (io.prometheus.write.v2.Request), which are not yet provided by the prometheus module.
+package writev2 // import "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+
+// ContentType is the content type of remote-write 2.0 requests.
+const ContentType = "application/x-protobuf;proto=io.prometheus.write.v2.Request"
+
+// Headers set on the response to a remote-write 2.0 request.
+const (
+	SamplesWrittenHeader    = "X-Prometheus-Remote-Write-Samples-Written"
+	HistogramsWrittenHeader = "X-Prometheus-Remote-Write-Histograms-Written"
+	ExemplarsWrittenHeader  = "X-Prometheus-Remote-Write-Exemplars-Written"
+)
+
+// Request is a remote-write 2.0 request. The labels and metadata strings of the time series
+// reference the symbols of the request, the first of which is always the empty string.
+type Request struct {
+	Symbols    []string
+	Timeseries []TimeSeries
+}
+
+// TimeSeries is a series of samples or histograms sharing the same labels.
+type TimeSeries struct {
+	// LabelsRefs is a list of name and value references to the symbols of the request.
+	LabelsRefs []uint32
+	Samples    []Sample
+	Histograms []Histogram
+	Exemplars  []Exemplar
+	Metadata   Metadata
+	// CreatedTimestamp is the time in milliseconds at which the series was created, or 0 if unknown.
+	CreatedTimestamp int64
+}
+
+// Sample is a value at a time in milliseconds.
+type Sample struct {
+	Value     float64
+	Timestamp int64
+}
+
+// Exemplar is a sample with its own labels, typically identifying a trace.
+type Exemplar struct {
+	LabelsRefs []uint32
+	Value      float64
+	Timestamp  int64
+}
+
+// MetricType is the type of the metric a time series belongs to.
+type MetricType int32
+
+const (
+	MetricTypeUnspecified    MetricType = 0
+	MetricTypeCounter        MetricType = 1
+	MetricTypeGauge          MetricType = 2
+	MetricTypeHistogram      MetricType = 3
+	MetricTypeGaugeHistogram MetricType = 4
+	MetricTypeSummary        MetricType = 5
+	MetricTypeInfo           MetricType = 6
+	MetricTypeStateset       MetricType = 7
+)
+
+// Metadata describes the metric a time series belongs to.
+type Metadata struct {
+	Type    MetricType
+	HelpRef uint32
+	UnitRef uint32
+}
+
+// ResetHint tells whether a histogram is a reset of the previous one.
+type ResetHint int32
+
+const (
+	ResetHintUnknown ResetHint = 0
+	ResetHintYes     ResetHint = 1
+	ResetHintNo      ResetHint = 2
+	ResetHintGauge   ResetHint = 3
+)
+
+// Histogram is a native histogram.
+type Histogram struct {
+	// Float is true if the counts of the histogram are floats, in which case they are stored in
+	// CountFloat, ZeroCountFloat, NegativeCounts and PositiveCounts rather than CountInt,
+	// ZeroCountInt, NegativeDeltas and PositiveDeltas.
+	Float          bool
+	CountInt       uint64
+	CountFloat     float64
+	Sum            float64
+	Schema         int32
+	ZeroThreshold  float64
+	ZeroCountInt   uint64
+	ZeroCountFloat float64
+	NegativeSpans  []BucketSpan
+	// NegativeDeltas are the counts of the negative buckets, each as a delta to the previous one.
+	NegativeDeltas []int64
+	NegativeCounts []float64
+	PositiveSpans  []BucketSpan
+	// PositiveDeltas are the counts of the positive buckets, each as a delta to the previous one.
+	PositiveDeltas []int64
+	PositiveCounts []float64
+	ResetHint      ResetHint
+	Timestamp      int64
+	// CustomValues are the upper bounds of the buckets of histograms with custom buckets.
+	CustomValues []float64
+}
+
+// BucketSpan is a range of consecutive buckets, starting Offset buckets after the end of the previous span.
+type BucketSpan struct {
+	Offset int32
+	Length uint32
+}
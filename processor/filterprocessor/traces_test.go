@@ -239,6 +239,23 @@ func TestFilterTraceProcessorWithOTTL(t *testing.T) {
 			},
 			errorMode: ottl.IgnoreError,
 		},
+		{
+			name: "drop span links",
+			conditions: TraceFilters{
+				SpanLinkConditions: []string{
+					`attributes["link.type"] == "follows_from"`,
+				},
+			},
+			want: func(td ptrace.Traces) {
+				td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(1).Links().RemoveIf(func(link ptrace.SpanLink) bool {
+					return link.Attributes().Len() > 0
+				})
+				td.ResourceSpans().At(0).ScopeSpans().At(1).Spans().At(1).Links().RemoveIf(func(link ptrace.SpanLink) bool {
+					return link.Attributes().Len() > 0
+				})
+			},
+			errorMode: ottl.IgnoreError,
+		},
 		{
 			name: "multiple conditions",
 			conditions: TraceFilters{
@@ -346,6 +363,7 @@ func fillSpanTwo(span ptrace.Span) {
 	span.Attributes().PutStr("flags", "C|D")
 	link0 := span.Links().AppendEmpty()
 	link0.SetDroppedAttributesCount(4)
+	link0.Attributes().PutStr("link.type", "follows_from")
 	link1 := span.Links().AppendEmpty()
 	link1.SetDroppedAttributesCount(4)
 	span.SetDroppedLinksCount(3)
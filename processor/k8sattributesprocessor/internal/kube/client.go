@@ -16,6 +16,7 @@ import (
 	conventions "go.opentelemetry.io/collector/semconv/v1.6.1"
 	"go.uber.org/zap"
 	apps_v1 "k8s.io/api/apps/v1"
+	batch_v1 "k8s.io/api/batch/v1"
 	api_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
@@ -46,6 +47,7 @@ type WatchClient struct {
 	namespaceInformer  cache.SharedInformer
 	nodeInformer       cache.SharedInformer
 	replicasetInformer cache.SharedInformer
+	workloadInformers  map[string]cache.SharedInformer
 	replicasetRegex    *regexp.Regexp
 	cronJobRegex       *regexp.Regexp
 	deleteQueue        []deleteRequest
@@ -71,6 +73,10 @@ type WatchClient struct {
 	// Key is replicaset uid
 	ReplicaSets map[string]*ReplicaSet
 
+	// A map containing Deployments, StatefulSets, DaemonSets and Jobs related data, used to associate them with resources.
+	// Key is workload uid
+	Workloads map[string]*Workload
+
 	telemetryBuilder *metadata.TelemetryBuilder
 }
 
@@ -78,6 +84,9 @@ type WatchClient struct {
 // format: [deployment-name]-[Random-String-For-ReplicaSet]
 var rRegex = regexp.MustCompile(`^(.*)-[0-9a-zA-Z]+$`)
 
+// workloadKinds lists the kinds of workloads labels and annotations can be extracted from.
+var workloadKinds = []string{MetadataFromDeployment, MetadataFromStatefulSet, MetadataFromDaemonSet, MetadataFromJob}
+
 // Extract CronJob name from the Job name. Job name is created using
 // format: [cronjob-name]-[time-hash-int]
 var cronJobRegex = regexp.MustCompile(`^(.*)-[0-9]+$`)
@@ -105,6 +114,7 @@ func New(set component.TelemetrySettings, apiCfg k8sconfig.APIConfig, rules Extr
 	c.Namespaces = map[string]*Namespace{}
 	c.Nodes = map[string]*Node{}
 	c.ReplicaSets = map[string]*ReplicaSet{}
+	c.Workloads = map[string]*Workload{}
 	if newClientSet == nil {
 		newClientSet = k8sconfig.MakeClient
 	}
@@ -160,7 +170,7 @@ func New(set component.TelemetrySettings, apiCfg k8sconfig.APIConfig, rules Extr
 
 	c.namespaceInformer = newNamespaceInformer(c.kc)
 
-	if rules.DeploymentName || rules.DeploymentUID {
+	if c.needReplicaSets() {
 		if newReplicaSetInformer == nil {
 			newReplicaSetInformer = newReplicaSetSharedInformer
 		}
@@ -184,6 +194,25 @@ func New(set component.TelemetrySettings, apiCfg k8sconfig.APIConfig, rules Extr
 		c.nodeInformer = k8sconfig.NewNodeSharedInformer(c.kc, c.Filters.Node, 5*time.Minute)
 	}
 
+	for _, kind := range workloadKinds {
+		if !c.extractLabelsAnnotationsFrom(kind) {
+			continue
+		}
+		informer := newWorkloadSharedInformer(c.kc, c.Filters.Namespace, kind)
+		err = informer.SetTransform(
+			func(object any) (any, error) {
+				return removeUnnecessaryWorkloadData(object), nil
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if c.workloadInformers == nil {
+			c.workloadInformers = map[string]cache.SharedInformer{}
+		}
+		c.workloadInformers[kind] = informer
+	}
+
 	return c, err
 }
 
@@ -209,7 +238,7 @@ func (c *WatchClient) Start() {
 	}
 	go c.namespaceInformer.Run(c.stopCh)
 
-	if c.Rules.DeploymentName || c.Rules.DeploymentUID {
+	if c.replicasetInformer != nil {
 		_, err = c.replicasetInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
 			AddFunc:    c.handleReplicaSetAdd,
 			UpdateFunc: c.handleReplicaSetUpdate,
@@ -232,6 +261,18 @@ func (c *WatchClient) Start() {
 		}
 		go c.nodeInformer.Run(c.stopCh)
 	}
+
+	for kind, informer := range c.workloadInformers {
+		_, err = informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.handleWorkloadAdd,
+			UpdateFunc: c.handleWorkloadUpdate,
+			DeleteFunc: c.handleWorkloadDelete,
+		})
+		if err != nil {
+			c.logger.Error("error adding event handler to workload informer", zap.String("kind", kind), zap.Error(err))
+		}
+		go informer.Run(c.stopCh)
+	}
 }
 
 // Stop signals the the k8s watcher/informer to stop watching for new events.
@@ -404,6 +445,17 @@ func (c *WatchClient) GetNamespace(namespace string) (*Namespace, bool) {
 	return nil, false
 }
 
+// GetWorkload takes a workload uid and returns the deployment, statefulset, daemonset or job the uid is associated with.
+func (c *WatchClient) GetWorkload(uid string) (*Workload, bool) {
+	c.m.RLock()
+	workload, ok := c.Workloads[uid]
+	c.m.RUnlock()
+	if ok {
+		return workload, ok
+	}
+	return nil, false
+}
+
 // GetNode takes a node name and returns the node object the node name is associated with.
 func (c *WatchClient) GetNode(nodeName string) (*Node, bool) {
 	c.m.RLock()
@@ -704,6 +756,49 @@ func (c *WatchClient) extractNodeAttributes(node *api_v1.Node) map[string]string
 	return tags
 }
 
+func (c *WatchClient) extractWorkloadAttributes(kind string, workload meta_v1.Object) map[string]string {
+	tags := map[string]string{}
+
+	for _, r := range c.Rules.Labels {
+		r.extractFromWorkloadMetadata(kind, workload.GetLabels(), tags, "k8s."+kind+".labels.%s")
+	}
+
+	for _, r := range c.Rules.Annotations {
+		r.extractFromWorkloadMetadata(kind, workload.GetAnnotations(), tags, "k8s."+kind+".annotations.%s")
+	}
+
+	return tags
+}
+
+// extractPodWorkloadUIDs returns the uids of the workloads owning the pod whose labels or annotations are extracted.
+// The deployment owning the pod is found through its replicaset.
+func (c *WatchClient) extractPodWorkloadUIDs(pod *api_v1.Pod) []string {
+	var uids []string
+	for _, ref := range pod.OwnerReferences {
+		switch ref.Kind {
+		case "ReplicaSet":
+			if c.extractLabelsAnnotationsFrom(MetadataFromDeployment) {
+				if replicaset, ok := c.getReplicaSet(string(ref.UID)); ok && replicaset.Deployment.UID != "" {
+					uids = append(uids, replicaset.Deployment.UID)
+				}
+			}
+		case "StatefulSet":
+			if c.extractLabelsAnnotationsFrom(MetadataFromStatefulSet) {
+				uids = append(uids, string(ref.UID))
+			}
+		case "DaemonSet":
+			if c.extractLabelsAnnotationsFrom(MetadataFromDaemonSet) {
+				uids = append(uids, string(ref.UID))
+			}
+		case "Job":
+			if c.extractLabelsAnnotationsFrom(MetadataFromJob) {
+				uids = append(uids, string(ref.UID))
+			}
+		}
+	}
+	return uids
+}
+
 func (c *WatchClient) podFromAPI(pod *api_v1.Pod) *Pod {
 	newPod := &Pod{
 		Name:        pod.Name,
@@ -722,6 +817,7 @@ func (c *WatchClient) podFromAPI(pod *api_v1.Pod) *Pod {
 		if needContainerAttributes(c.Rules) {
 			newPod.Containers = c.extractPodContainersAttributes(pod)
 		}
+		newPod.WorkloadUIDs = c.extractPodWorkloadUIDs(pod)
 	}
 
 	return newPod
@@ -908,30 +1004,23 @@ func (c *WatchClient) addOrUpdateNamespace(namespace *api_v1.Namespace) {
 }
 
 func (c *WatchClient) extractNamespaceLabelsAnnotations() bool {
-	for _, r := range c.Rules.Labels {
-		if r.From == MetadataFromNamespace {
-			return true
-		}
-	}
-
-	for _, r := range c.Rules.Annotations {
-		if r.From == MetadataFromNamespace {
-			return true
-		}
-	}
-
-	return false
+	return c.extractLabelsAnnotationsFrom(MetadataFromNamespace)
 }
 
 func (c *WatchClient) extractNodeLabelsAnnotations() bool {
+	return c.extractLabelsAnnotationsFrom(MetadataFromNode)
+}
+
+// extractLabelsAnnotationsFrom determines whether labels or annotations are extracted from the given kubernetes object kind.
+func (c *WatchClient) extractLabelsAnnotationsFrom(from string) bool {
 	for _, r := range c.Rules.Labels {
-		if r.From == MetadataFromNode {
+		if r.From == from {
 			return true
 		}
 	}
 
 	for _, r := range c.Rules.Annotations {
-		if r.From == MetadataFromNode {
+		if r.From == from {
 			return true
 		}
 	}
@@ -939,6 +1028,11 @@ func (c *WatchClient) extractNodeLabelsAnnotations() bool {
 	return false
 }
 
+// needReplicaSets determines whether replicasets need to be watched to find the deployment owning a pod.
+func (c *WatchClient) needReplicaSets() bool {
+	return c.Rules.DeploymentName || c.Rules.DeploymentUID || c.extractLabelsAnnotationsFrom(MetadataFromDeployment)
+}
+
 func (c *WatchClient) extractNodeUID() bool {
 	return c.Rules.NodeUID
 }
@@ -1041,6 +1135,83 @@ func (c *WatchClient) getReplicaSet(uid string) (*ReplicaSet, bool) {
 	return nil, false
 }
 
+func (c *WatchClient) handleWorkloadAdd(obj any) {
+	c.addOrUpdateWorkload(obj)
+}
+
+func (c *WatchClient) handleWorkloadUpdate(_, newWorkload any) {
+	c.addOrUpdateWorkload(newWorkload)
+}
+
+func (c *WatchClient) handleWorkloadDelete(obj any) {
+	if _, workload, ok := workloadKind(ignoreDeletedFinalStateUnknown(obj)); ok {
+		c.m.Lock()
+		delete(c.Workloads, string(workload.GetUID()))
+		c.m.Unlock()
+	} else {
+		c.logger.Error("object received was not a deployment, statefulset, daemonset or job", zap.Any("received", obj))
+	}
+}
+
+func (c *WatchClient) addOrUpdateWorkload(obj any) {
+	kind, workload, ok := workloadKind(obj)
+	if !ok {
+		c.logger.Error("object received was not a deployment, statefulset, daemonset or job", zap.Any("received", obj))
+		return
+	}
+	newWorkload := &Workload{
+		Name:       workload.GetName(),
+		UID:        string(workload.GetUID()),
+		Attributes: c.extractWorkloadAttributes(kind, workload),
+	}
+
+	c.m.Lock()
+	if newWorkload.UID != "" {
+		c.Workloads[newWorkload.UID] = newWorkload
+	}
+	c.m.Unlock()
+}
+
+// workloadKind returns the kind and metadata of a deployment, statefulset, daemonset or job.
+func workloadKind(obj any) (string, meta_v1.Object, bool) {
+	switch workload := obj.(type) {
+	case *apps_v1.Deployment:
+		return MetadataFromDeployment, workload, true
+	case *apps_v1.StatefulSet:
+		return MetadataFromStatefulSet, workload, true
+	case *apps_v1.DaemonSet:
+		return MetadataFromDaemonSet, workload, true
+	case *batch_v1.Job:
+		return MetadataFromJob, workload, true
+	}
+	return "", nil, false
+}
+
+// This function removes all data from a workload except what is required by extraction rules
+func removeUnnecessaryWorkloadData(obj any) any {
+	kind, workload, ok := workloadKind(obj)
+	if !ok { // means this is a cache.DeletedFinalStateUnknown, in which case we do nothing
+		return obj
+	}
+	meta := meta_v1.ObjectMeta{
+		Name:        workload.GetName(),
+		Namespace:   workload.GetNamespace(),
+		UID:         workload.GetUID(),
+		Labels:      workload.GetLabels(),
+		Annotations: workload.GetAnnotations(),
+	}
+	switch kind {
+	case MetadataFromDeployment:
+		return &apps_v1.Deployment{ObjectMeta: meta}
+	case MetadataFromStatefulSet:
+		return &apps_v1.StatefulSet{ObjectMeta: meta}
+	case MetadataFromDaemonSet:
+		return &apps_v1.DaemonSet{ObjectMeta: meta}
+	default:
+		return &batch_v1.Job{ObjectMeta: meta}
+	}
+}
+
 // ignoreDeletedFinalStateUnknown returns the object wrapped in
 // DeletedFinalStateUnknown. Useful in OnDelete resource event handlers that do
 // not need the additional context.
@@ -16,6 +16,7 @@ import (
 	"go.uber.org/zap/zapcore"
 	"go.uber.org/zap/zaptest/observer"
 	apps_v1 "k8s.io/api/apps/v1"
+	batch_v1 "k8s.io/api/batch/v1"
 	api_v1 "k8s.io/api/core/v1"
 	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/selection"
@@ -293,6 +294,57 @@ func TestReplicaSetHandler(t *testing.T) {
 
 }
 
+func TestWorkloadHandler(t *testing.T) {
+	c, _ := newTestClient(t)
+	c.Rules = ExtractionRules{
+		Labels: []FieldExtractionRule{{
+			KeyRegex: regexp.MustCompile("^(?:.*)$"),
+			From:     MetadataFromStatefulSet,
+		}},
+	}
+	assert.Equal(t, len(c.Workloads), 0)
+
+	c.handleWorkloadAdd(&apps_v1.StatefulSet{})
+	assert.Equal(t, len(c.Workloads), 0)
+
+	// test add workload
+	statefulset := &apps_v1.StatefulSet{}
+	statefulset.Name = "statefulset"
+	statefulset.UID = "aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee"
+	statefulset.Labels = map[string]string{"label1": "lv1"}
+	c.handleWorkloadAdd(statefulset)
+	assert.Equal(t, len(c.Workloads), 1)
+	got, ok := c.GetWorkload(string(statefulset.UID))
+	require.True(t, ok)
+	assert.Equal(t, "statefulset", got.Name)
+	assert.Equal(t, map[string]string{"k8s.statefulset.labels.label1": "lv1"}, got.Attributes)
+
+	// test update workload
+	updatedStatefulset := statefulset.DeepCopy()
+	updatedStatefulset.Labels = map[string]string{"label1": "lv2"}
+	c.handleWorkloadUpdate(statefulset, updatedStatefulset)
+	assert.Equal(t, len(c.Workloads), 1)
+	got, ok = c.GetWorkload(string(statefulset.UID))
+	require.True(t, ok)
+	assert.Equal(t, map[string]string{"k8s.statefulset.labels.label1": "lv2"}, got.Attributes)
+
+	// test delete workload
+	c.handleWorkloadDelete(updatedStatefulset)
+	assert.Equal(t, len(c.Workloads), 0)
+	// test delete workload when DeletedFinalStateUnknown
+	c.handleWorkloadAdd(statefulset)
+	require.Equal(t, len(c.Workloads), 1)
+	c.handleWorkloadDelete(cache.DeletedFinalStateUnknown{
+		Obj: statefulset,
+	})
+	assert.Equal(t, len(c.Workloads), 0)
+
+	// test wrong type
+	c.handleWorkloadAdd(&api_v1.Pod{})
+	c.handleWorkloadDelete(&api_v1.Pod{})
+	assert.Equal(t, len(c.Workloads), 0)
+}
+
 func TestPodHostNetwork(t *testing.T) {
 	c, _ := newTestClient(t)
 	assert.Equal(t, 0, len(c.Pods))
@@ -1309,6 +1361,160 @@ func TestNodeExtractionRules(t *testing.T) {
 	}
 }
 
+func TestWorkloadExtractionRules(t *testing.T) {
+	c, _ := newTestClientWithRulesAndFilters(t, Filters{})
+	// Disable saving ip into k8s.pod.ip
+	c.Associations[0].Sources[0].Name = ""
+
+	isController := true
+	replicaset := &apps_v1.ReplicaSet{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "auth-service-66f5996c7c",
+			Namespace: "ns1",
+			UID:       "207ea729-c779-401d-8347-008ecbc137e3",
+			OwnerReferences: []meta_v1.OwnerReference{{
+				Name:       "auth-service",
+				Kind:       "Deployment",
+				UID:        "ffff-gggg-hhhh-iiii-eeeeeeeeeeee",
+				Controller: &isController,
+			}},
+		},
+	}
+	deployment := &apps_v1.Deployment{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:        "auth-service",
+			Namespace:   "ns1",
+			UID:         "ffff-gggg-hhhh-iiii-eeeeeeeeeeee",
+			Labels:      map[string]string{"team": "auth"},
+			Annotations: map[string]string{"owner": "alice"},
+		},
+	}
+	job := &batch_v1.Job{
+		ObjectMeta: meta_v1.ObjectMeta{
+			Name:      "migration",
+			Namespace: "ns1",
+			UID:       "1111-2222-3333-4444-555555555555",
+			Labels:    map[string]string{"team": "db"},
+		},
+	}
+
+	testCases := []struct {
+		name            string
+		rules           ExtractionRules
+		ownerReferences []meta_v1.OwnerReference
+		attributes      map[string]string
+	}{{
+		name: "no-rules",
+		ownerReferences: []meta_v1.OwnerReference{{
+			Kind: "ReplicaSet",
+			Name: replicaset.Name,
+			UID:  replicaset.UID,
+		}},
+		rules:      ExtractionRules{},
+		attributes: map[string]string{},
+	}, {
+		name: "deployment",
+		ownerReferences: []meta_v1.OwnerReference{{
+			Kind: "ReplicaSet",
+			Name: replicaset.Name,
+			UID:  replicaset.UID,
+		}},
+		rules: ExtractionRules{
+			Labels: []FieldExtractionRule{{
+				Name: "team",
+				Key:  "team",
+				From: MetadataFromDeployment,
+			}},
+			Annotations: []FieldExtractionRule{{
+				KeyRegex: regexp.MustCompile("^(?:.*)$"),
+				From:     MetadataFromDeployment,
+			}},
+		},
+		attributes: map[string]string{
+			"team":                             "auth",
+			"k8s.deployment.annotations.owner": "alice",
+		},
+	}, {
+		name: "job",
+		ownerReferences: []meta_v1.OwnerReference{{
+			Kind: "Job",
+			Name: job.Name,
+			UID:  job.UID,
+		}},
+		rules: ExtractionRules{
+			Labels: []FieldExtractionRule{{
+				KeyRegex: regexp.MustCompile("^(?:te.*)$"),
+				From:     MetadataFromJob,
+			}},
+		},
+		attributes: map[string]string{
+			"k8s.job.labels.team": "db",
+		},
+	}, {
+		name: "other-workload-kind",
+		ownerReferences: []meta_v1.OwnerReference{{
+			Kind: "Job",
+			Name: job.Name,
+			UID:  job.UID,
+		}},
+		rules: ExtractionRules{
+			Labels: []FieldExtractionRule{{
+				Name: "team",
+				Key:  "team",
+				From: MetadataFromDeployment,
+			}},
+		},
+		attributes: map[string]string{},
+	},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c.Rules = tc.rules
+			c.Workloads = map[string]*Workload{}
+			pod := &api_v1.Pod{
+				ObjectMeta: meta_v1.ObjectMeta{
+					Name:            "auth-service-abc12-xyz3",
+					UID:             "aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee",
+					Namespace:       "ns1",
+					OwnerReferences: tc.ownerReferences,
+				},
+				Status: api_v1.PodStatus{
+					PodIP: "1.1.1.1",
+				},
+			}
+
+			c.handleReplicaSetAdd(removeUnnecessaryReplicaSetData(replicaset))
+			c.handleWorkloadAdd(removeUnnecessaryWorkloadData(deployment))
+			c.handleWorkloadAdd(removeUnnecessaryWorkloadData(job))
+			c.handlePodAdd(removeUnnecessaryPodData(pod, c.Rules))
+			p, ok := c.GetPod(newPodIdentifier("connection", "", pod.Status.PodIP))
+			require.True(t, ok)
+
+			attributes := map[string]string{}
+			for _, uid := range p.WorkloadUIDs {
+				workload, ok := c.GetWorkload(uid)
+				require.True(t, ok)
+				for k, v := range workload.Attributes {
+					attributes[k] = v
+				}
+			}
+			assert.Equal(t, tc.attributes, attributes)
+		})
+	}
+}
+
+func TestIncludesOwnerMetadataDoesNotWriteRules(t *testing.T) {
+	// the rules are shared by the informer handlers, so the spare capacity of the labels must not be written
+	labels := make([]FieldExtractionRule, 1, 2)
+	labels[0] = FieldExtractionRule{Key: "team", From: MetadataFromPod}
+	rules := ExtractionRules{
+		Labels:      labels,
+		Annotations: []FieldExtractionRule{{Key: "owner", From: MetadataFromDeployment}},
+	}
+	assert.True(t, rules.IncludesOwnerMetadata())
+	assert.Equal(t, FieldExtractionRule{}, labels[:2][1])
+}
+
 func TestFilters(t *testing.T) {
 	testCases := []struct {
 		name    string
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	apps_v1 "k8s.io/api/apps/v1"
+	batch_v1 "k8s.io/api/batch/v1"
 	api_v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
@@ -155,3 +156,59 @@ func replicasetWatchFuncWithSelectors(client kubernetes.Interface, namespace str
 		return client.AppsV1().ReplicaSets(namespace).Watch(context.Background(), opts)
 	}
 }
+
+// newWorkloadSharedInformer returns a shared informer watching the workloads of the given kind,
+// one of MetadataFromDeployment, MetadataFromStatefulSet, MetadataFromDaemonSet or MetadataFromJob.
+func newWorkloadSharedInformer(
+	client kubernetes.Interface,
+	namespace string,
+	kind string,
+) cache.SharedInformer {
+	var lw *cache.ListWatch
+	var objType runtime.Object
+	switch kind {
+	case MetadataFromDeployment:
+		objType = &apps_v1.Deployment{}
+		lw = &cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				return client.AppsV1().Deployments(namespace).List(context.Background(), opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				return client.AppsV1().Deployments(namespace).Watch(context.Background(), opts)
+			},
+		}
+	case MetadataFromStatefulSet:
+		objType = &apps_v1.StatefulSet{}
+		lw = &cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				return client.AppsV1().StatefulSets(namespace).List(context.Background(), opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				return client.AppsV1().StatefulSets(namespace).Watch(context.Background(), opts)
+			},
+		}
+	case MetadataFromDaemonSet:
+		objType = &apps_v1.DaemonSet{}
+		lw = &cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				return client.AppsV1().DaemonSets(namespace).List(context.Background(), opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				return client.AppsV1().DaemonSets(namespace).Watch(context.Background(), opts)
+			},
+		}
+	case MetadataFromJob:
+		objType = &batch_v1.Job{}
+		lw = &cache.ListWatch{
+			ListFunc: func(opts metav1.ListOptions) (runtime.Object, error) {
+				return client.BatchV1().Jobs(namespace).List(context.Background(), opts)
+			},
+			WatchFunc: func(opts metav1.ListOptions) (watch.Interface, error) {
+				return client.BatchV1().Jobs(namespace).Watch(context.Background(), opts)
+			},
+		}
+	default:
+		return NewNoOpInformer(client)
+	}
+	return cache.NewSharedInformer(lw, objType, watchSyncPeriod)
+}
@@ -6,6 +6,7 @@ package kube // import "github.com/open-telemetry/opentelemetry-collector-contri
 import (
 	"fmt"
 	"regexp"
+	"slices"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -28,7 +29,15 @@ const (
 	// MetadataFromNamespace is used to specify to extract metadata/labels/annotations from namespace
 	MetadataFromNamespace = "namespace"
 	// MetadataFromNode is used to specify to extract metadata/labels/annotations from node
-	MetadataFromNode       = "node"
+	MetadataFromNode = "node"
+	// MetadataFromDeployment is used to specify to extract metadata/labels/annotations from the deployment owning the pod
+	MetadataFromDeployment = "deployment"
+	// MetadataFromStatefulSet is used to specify to extract metadata/labels/annotations from the statefulset owning the pod
+	MetadataFromStatefulSet = "statefulset"
+	// MetadataFromDaemonSet is used to specify to extract metadata/labels/annotations from the daemonset owning the pod
+	MetadataFromDaemonSet = "daemonset"
+	// MetadataFromJob is used to specify to extract metadata/labels/annotations from the job owning the pod
+	MetadataFromJob        = "job"
 	PodIdentifierMaxLength = 4
 
 	ResourceSource   = "resource_attribute"
@@ -91,6 +100,7 @@ type Client interface {
 	GetPod(PodIdentifier) (*Pod, bool)
 	GetNamespace(string) (*Namespace, bool)
 	GetNode(string) (*Node, bool)
+	GetWorkload(string) (*Workload, bool)
 	Start()
 	Stop()
 }
@@ -117,6 +127,9 @@ type Pod struct {
 	// Containers specifies all containers in this pod.
 	Containers PodContainers
 
+	// WorkloadUIDs lists the UIDs of the workloads owning this pod whose metadata is extracted.
+	WorkloadUIDs []string
+
 	DeletedAt time.Time
 }
 
@@ -159,6 +172,13 @@ type Node struct {
 	Attributes map[string]string
 }
 
+// Workload represents a kubernetes workload owning pods: a deployment, a statefulset, a daemonset or a job.
+type Workload struct {
+	Name       string
+	UID        string
+	Attributes map[string]string
+}
+
 type deleteRequest struct {
 	// id is identifier (IP address or Pod UID) of pod to remove from pods map
 	id PodIdentifier
@@ -242,6 +262,21 @@ func (rules *ExtractionRules) IncludesOwnerMetadata() bool {
 			return true
 		}
 	}
+	for _, r := range slices.Concat(rules.Labels, rules.Annotations) {
+		if IsWorkloadMetadataSource(r.From) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsWorkloadMetadataSource determines whether labels/annotations extracted from the given source
+// come from a workload owning the pod.
+func IsWorkloadMetadataSource(from string) bool {
+	switch from {
+	case MetadataFromDeployment, MetadataFromStatefulSet, MetadataFromDaemonSet, MetadataFromJob:
+		return true
+	}
 	return false
 }
 
@@ -259,10 +294,14 @@ type FieldExtractionRule struct {
 	// Full value is extracted when no regexp is provided.
 	Regex *regexp.Regexp
 	// From determines the kubernetes object the field should be retrieved from.
-	// Currently only three values are supported,
+	// Currently supported values are,
 	//  - pod
 	//  - namespace
 	//  - node
+	//  - deployment
+	//  - statefulset
+	//  - daemonset
+	//  - job
 	From string
 }
 
@@ -285,6 +324,12 @@ func (r *FieldExtractionRule) extractFromNodeMetadata(metadata map[string]string
 	}
 }
 
+func (r *FieldExtractionRule) extractFromWorkloadMetadata(kind string, metadata map[string]string, tags map[string]string, formatter string) {
+	if r.From == kind {
+		r.extractFromMetadata(metadata, tags, formatter)
+	}
+}
+
 func (r *FieldExtractionRule) extractFromMetadata(metadata map[string]string, tags map[string]string, formatter string) {
 	if r.KeyRegex != nil {
 		for k, v := range metadata {
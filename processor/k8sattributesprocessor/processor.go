@@ -151,6 +151,7 @@ func (kp *kubernetesprocessor) processResource(ctx context.Context, resource pco
 				}
 			}
 			kp.addContainerAttributes(resource.Attributes(), pod)
+			kp.addWorkloadAttributes(resource.Attributes(), pod)
 		}
 	}
 
@@ -259,6 +260,21 @@ func (kp *kubernetesprocessor) addContainerAttributes(attrs pcommon.Map, pod *ku
 	}
 }
 
+// addWorkloadAttributes adds the attributes extracted from the workloads owning the pod
+func (kp *kubernetesprocessor) addWorkloadAttributes(attrs pcommon.Map, pod *kube.Pod) {
+	for _, uid := range pod.WorkloadUIDs {
+		workload, ok := kp.kc.GetWorkload(uid)
+		if !ok {
+			continue
+		}
+		for key, val := range workload.Attributes {
+			if _, found := attrs.Get(key); !found {
+				attrs.PutStr(key, val)
+			}
+		}
+	}
+}
+
 func (kp *kubernetesprocessor) getAttributesForPodsNamespace(namespace string) map[string]string {
 	ns, ok := kp.kc.GetNamespace(namespace)
 	if !ok {
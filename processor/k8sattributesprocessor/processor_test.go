@@ -879,6 +879,70 @@ func TestAddNodeLabels(t *testing.T) {
 	})
 }
 
+func TestAddWorkloadLabels(t *testing.T) {
+	m := newMultiTest(
+		t,
+		func() component.Config {
+			cfg := createDefaultConfig().(*Config)
+			cfg.Extract.Metadata = []string{}
+			cfg.Extract.Labels = []FieldExtractConfig{
+				{
+					From: kube.MetadataFromDeployment,
+					Key:  "team",
+				},
+			}
+			return cfg
+		}(),
+		nil,
+	)
+
+	podIP := "1.1.1.1"
+	m.kubernetesProcessorOperation(func(kp *kubernetesprocessor) {
+		kp.podAssociations = []kube.Association{
+			{
+				Sources: []kube.AssociationSource{
+					{
+						From: "connection",
+					},
+				},
+			},
+		}
+	})
+
+	m.kubernetesProcessorOperation(func(kp *kubernetesprocessor) {
+		pi := kube.PodIdentifier{
+			kube.PodIdentifierAttributeFromConnection(podIP),
+		}
+		kp.kc.(*fakeClient).Pods[pi] = &kube.Pod{Name: "test-2323", WorkloadUIDs: []string{"deployment-1", "unknown"}}
+		kp.kc.(*fakeClient).Workloads = map[string]*kube.Workload{
+			"deployment-1": {Name: "deployment", UID: "deployment-1", Attributes: map[string]string{"k8s.deployment.labels.team": "auth"}},
+			"deployment-2": {Name: "other", UID: "deployment-2", Attributes: map[string]string{"k8s.deployment.labels.team": "db"}},
+		}
+	})
+
+	ctx := client.NewContext(context.Background(), client.Info{
+		Addr: &net.IPAddr{
+			IP: net.ParseIP(podIP),
+		},
+	})
+	m.testConsume(
+		ctx,
+		generateTraces(),
+		generateMetrics(),
+		generateLogs(),
+		func(err error) {
+			assert.NoError(t, err)
+		})
+
+	m.assertBatchesLen(1)
+	m.assertResourceObjectLen(0)
+	m.assertResource(0, func(res pcommon.Resource) {
+		assert.Equal(t, 2, res.Attributes().Len())
+		assertResourceHasStringAttribute(t, res, "k8s.pod.ip", podIP)
+		assertResourceHasStringAttribute(t, res, "k8s.deployment.labels.team", "auth")
+	})
+}
+
 func TestAddNodeUID(t *testing.T) {
 	nodeUID := "asdfasdf-asdfasdf-asdf"
 	m := newMultiTest(
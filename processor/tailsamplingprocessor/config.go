@@ -4,8 +4,11 @@
 package tailsamplingprocessor // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor"
 
 import (
+	"errors"
 	"time"
 
+	"go.opentelemetry.io/collector/config/configgrpc"
+
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 )
 
@@ -226,6 +229,30 @@ type DecisionCacheConfig struct {
 	// For effective use, this value should be at least an order of magnitude higher than Config.NumTraces.
 	// If left as default 0, a no-op DecisionCache will be used.
 	SampledCacheSize int `mapstructure:"sampled_cache_size"`
+	// NonSampledCacheSize specifies the size of the cache that holds the non-sampled trace IDs.
+	// This value will be the maximum amount of trace IDs that the cache can hold before overwriting previous IDs.
+	// For effective use, this value should be at least an order of magnitude higher than Config.NumTraces.
+	// If left as default 0, a no-op DecisionCache will be used.
+	NonSampledCacheSize int `mapstructure:"non_sampled_cache_size"`
+	// Sharing configures the sharing of sampling decisions with other collector replicas.
+	// If left unset, decisions are only kept locally.
+	Sharing *DecisionSharingConfig `mapstructure:"sharing"`
+}
+
+// DecisionSharingConfig holds the configuration for sharing sampling decisions between
+// collector replicas, so that spans of a trace that reach a replica other than the one
+// that took the decision follow the original decision.
+type DecisionSharingConfig struct {
+	// Server is the gRPC server receiving the decisions published by peers.
+	Server *configgrpc.ServerConfig `mapstructure:"server"`
+	// Peers lists the collectors to which the decisions taken by this collector are published.
+	Peers []configgrpc.ClientConfig `mapstructure:"peers"`
+	// CacheSize is the maximum number of shared decisions kept in memory. Defaults to 100000.
+	CacheSize int `mapstructure:"cache_size"`
+	// TTL is the duration for which a shared decision is kept. Defaults to 5m.
+	TTL time.Duration `mapstructure:"ttl"`
+	// PublishInterval is the interval at which new decisions are published to the peers. Defaults to 100ms.
+	PublishInterval time.Duration `mapstructure:"publish_interval"`
 }
 
 // Config holds the configuration for tail-based sampling.
@@ -244,4 +271,20 @@ type Config struct {
 	PolicyCfgs []PolicyCfg `mapstructure:"policies"`
 	// DecisionCache holds configuration for the decision cache(s)
 	DecisionCache DecisionCacheConfig `mapstructure:"decision_cache"`
+	// RecordPolicy adds the names of the policies that sampled a trace to its spans,
+	// using the "tailsampling.policy" attribute.
+	RecordPolicy bool `mapstructure:"record_policy"`
+}
+
+// Validate checks if the processor configuration is valid.
+func (cfg *Config) Validate() error {
+	if sharing := cfg.DecisionCache.Sharing; sharing != nil {
+		if sharing.Server == nil && len(sharing.Peers) == 0 {
+			return errors.New("decision_cache.sharing requires a server, peers or both")
+		}
+		if sharing.CacheSize < 0 || sharing.TTL < 0 || sharing.PublishInterval < 0 {
+			return errors.New("decision_cache.sharing cache_size, ttl and publish_interval must not be negative")
+		}
+	}
+	return nil
 }
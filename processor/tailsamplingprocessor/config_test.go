@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
@@ -36,7 +37,7 @@ func TestLoadConfig(t *testing.T) {
 			DecisionWait:            10 * time.Second,
 			NumTraces:               100,
 			ExpectedNewTracesPerSec: 10,
-			DecisionCache:           DecisionCacheConfig{SampledCacheSize: 500},
+			DecisionCache:           DecisionCacheConfig{SampledCacheSize: 500, NonSampledCacheSize: 1000},
 			PolicyCfgs: []PolicyCfg{
 				{
 					sharedPolicyCfg: sharedPolicyCfg{
@@ -187,3 +188,20 @@ func TestLoadConfig(t *testing.T) {
 			},
 		})
 }
+
+func TestValidateDecisionSharing(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	require.NoError(t, cfg.Validate())
+
+	cfg.DecisionCache.Sharing = &DecisionSharingConfig{}
+	assert.Error(t, cfg.Validate())
+
+	cfg.DecisionCache.Sharing = &DecisionSharingConfig{
+		Peers: []configgrpc.ClientConfig{{Endpoint: "localhost:4320"}},
+		TTL:   -time.Minute,
+	}
+	assert.Error(t, cfg.Validate())
+
+	cfg.DecisionCache.Sharing.TTL = time.Minute
+	assert.NoError(t, cfg.Validate())
+}
@@ -0,0 +1,62 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cache // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
+
+import (
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+// ttlDecisionCache implements Cache as an LRU cache whose entries additionally
+// expire once they are older than a fixed TTL. Expired entries are removed
+// lazily, when they are looked up, or by the LRU eviction policy.
+type ttlDecisionCache[V any] struct {
+	cache *lru.Cache[uint64, ttlEntry[V]]
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+type ttlEntry[V any] struct {
+	value     V
+	expiresAt time.Time
+}
+
+var _ Cache[any] = (*ttlDecisionCache[any])(nil)
+
+// NewTTLDecisionCache returns a new ttlDecisionCache.
+// The size parameter indicates the amount of keys the cache will hold before it
+// starts evicting the least recently used key, and ttl how long a key is kept
+// before it is considered expired.
+func NewTTLDecisionCache[V any](size int, ttl time.Duration) (Cache[V], error) {
+	c, err := lru.New[uint64, ttlEntry[V]](size)
+	if err != nil {
+		return nil, err
+	}
+	return &ttlDecisionCache[V]{cache: c, ttl: ttl, now: time.Now}, nil
+}
+
+func (c *ttlDecisionCache[V]) Get(id pcommon.TraceID) (V, bool) {
+	key := rightHalfTraceID(id)
+	e, ok := c.cache.Get(key)
+	if !ok {
+		var v V
+		return v, false
+	}
+	if c.now().After(e.expiresAt) {
+		c.cache.Remove(key)
+		var v V
+		return v, false
+	}
+	return e.value, true
+}
+
+func (c *ttlDecisionCache[V]) Put(id pcommon.TraceID, v V) {
+	_ = c.cache.Add(rightHalfTraceID(id), ttlEntry[V]{value: v, expiresAt: c.now().Add(c.ttl)})
+}
+
+func (c *ttlDecisionCache[V]) Delete(id pcommon.TraceID) {
+	c.cache.Remove(rightHalfTraceID(id))
+}
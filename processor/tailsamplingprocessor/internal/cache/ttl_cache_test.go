@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package cache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTTLCacheExpiresEntries(t *testing.T) {
+	c, err := NewTTLDecisionCache[bool](2, time.Minute)
+	require.NoError(t, err)
+	now := time.Now()
+	c.(*ttlDecisionCache[bool]).now = func() time.Time { return now }
+
+	id, err := traceIDFromHex("12341234123412341234123412341234")
+	require.NoError(t, err)
+	c.Put(id, true)
+
+	now = now.Add(30 * time.Second)
+	v, ok := c.Get(id)
+	assert.True(t, v)
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	v, ok = c.Get(id)
+	assert.False(t, v)
+	assert.False(t, ok)
+}
+
+func TestTTLCacheExceedsSizeLimit(t *testing.T) {
+	c, err := NewTTLDecisionCache[bool](2, time.Minute)
+	require.NoError(t, err)
+	id1, err := traceIDFromHex("12341234123412341234123412341231")
+	require.NoError(t, err)
+	id2, err := traceIDFromHex("12341234123412341234123412341232")
+	require.NoError(t, err)
+	id3, err := traceIDFromHex("12341234123412341234123412341233")
+	require.NoError(t, err)
+
+	c.Put(id1, true)
+	c.Put(id2, false)
+	c.Put(id3, true)
+
+	_, ok := c.Get(id1)
+	assert.False(t, ok) // evicted
+	v, ok := c.Get(id2)
+	assert.False(t, v)
+	assert.True(t, ok)
+	v, ok = c.Get(id3)
+	assert.True(t, v)
+	assert.True(t, ok)
+}
+
+func TestTTLCacheDelete(t *testing.T) {
+	c, err := NewTTLDecisionCache[bool](2, time.Minute)
+	require.NoError(t, err)
+	id, err := traceIDFromHex("12341234123412341234123412341234")
+	require.NoError(t, err)
+
+	c.Put(id, true)
+	c.Delete(id)
+	_, ok := c.Get(id)
+	assert.False(t, ok)
+}
@@ -25,8 +25,11 @@ func Tracer(settings component.TelemetrySettings) trace.Tracer {
 // as defined in metadata and user config.
 type TelemetryBuilder struct {
 	meter                                               metric.Meter
+	ProcessorTailSamplingCountPolicyDecisions           metric.Int64Counter
 	ProcessorTailSamplingCountSpansSampled              metric.Int64Counter
 	ProcessorTailSamplingCountTracesSampled             metric.Int64Counter
+	ProcessorTailSamplingDecisionsFromPeers             metric.Int64Counter
+	ProcessorTailSamplingEarlyDropsFromCacheDecision    metric.Int64Counter
 	ProcessorTailSamplingEarlyReleasesFromCacheDecision metric.Int64Counter
 	ProcessorTailSamplingGlobalCountTracesSampled       metric.Int64Counter
 	ProcessorTailSamplingNewTraceIDReceived             metric.Int64Counter
@@ -63,6 +66,12 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...teleme
 	} else {
 		builder.meter = noop.Meter{}
 	}
+	builder.ProcessorTailSamplingCountPolicyDecisions, err = builder.meter.Int64Counter(
+		"processor_tail_sampling_count_policy_decisions",
+		metric.WithDescription("Count of decisions per sampling policy, by decision type (sampled, not_sampled, inverted_sampled, inverted_not_sampled or error)"),
+		metric.WithUnit("{traces}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.ProcessorTailSamplingCountSpansSampled, err = builder.meter.Int64Counter(
 		"processor_tail_sampling_count_spans_sampled",
 		metric.WithDescription("Count of spans that were sampled or not per sampling policy"),
@@ -75,6 +84,18 @@ func NewTelemetryBuilder(settings component.TelemetrySettings, options ...teleme
 		metric.WithUnit("{traces}"),
 	)
 	errs = errors.Join(errs, err)
+	builder.ProcessorTailSamplingDecisionsFromPeers, err = builder.meter.Int64Counter(
+		"processor_tail_sampling_decisions_from_peers",
+		metric.WithDescription("Count of traces whose sampling decision was taken from a decision shared by another collector"),
+		metric.WithUnit("{traces}"),
+	)
+	errs = errors.Join(errs, err)
+	builder.ProcessorTailSamplingEarlyDropsFromCacheDecision, err = builder.meter.Int64Counter(
+		"processor_tail_sampling_early_drops_from_cache_decision",
+		metric.WithDescription("Number of spans that were able to be immediately dropped due to a not sampled decision cache hit."),
+		metric.WithUnit("{spans}"),
+	)
+	errs = errors.Join(errs, err)
 	builder.ProcessorTailSamplingEarlyReleasesFromCacheDecision, err = builder.meter.Int64Counter(
 		"processor_tail_sampling_early_releases_from_cache_decision",
 		metric.WithDescription("Number of spans that were able to be immediately released due to a decision cache hit."),
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package peer // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/peer"
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+const (
+	codecName = "tailsampling-decisions"
+
+	// minDecisionSize is the encoded size of a single decision without policies: the trace ID,
+	// one byte holding the sampled flag and the number of policies that sampled the trace.
+	minDecisionSize = 16 + 1 + 1
+)
+
+var errInvalidPayload = errors.New("invalid decision payload")
+
+// decision is a sampling decision taken for a trace by one of the collectors.
+type decision struct {
+	traceID pcommon.TraceID
+	Decision
+}
+
+// publishRequest carries a batch of decisions from one collector to its peers.
+type publishRequest struct {
+	decisions []decision
+}
+
+// publishResponse is the (empty) reply to a publishRequest.
+type publishResponse struct{}
+
+// decisionCodec is a gRPC codec encoding the decision messages in a compact
+// binary format, so that no protobuf definitions are required to exchange
+// decisions between collectors. Each decision is encoded as the trace ID, the
+// sampled flag, and the uvarint-prefixed list of the policies that sampled the
+// trace, each policy name being prefixed with its uvarint length.
+type decisionCodec struct{}
+
+func (decisionCodec) Name() string {
+	return codecName
+}
+
+func (decisionCodec) Marshal(v any) ([]byte, error) {
+	switch msg := v.(type) {
+	case *publishRequest:
+		buf := make([]byte, 0, len(msg.decisions)*minDecisionSize)
+		for _, d := range msg.decisions {
+			buf = append(buf, d.traceID[:]...)
+			if d.Sampled {
+				buf = append(buf, 1)
+			} else {
+				buf = append(buf, 0)
+			}
+			buf = binary.AppendUvarint(buf, uint64(len(d.Policies)))
+			for _, policy := range d.Policies {
+				buf = binary.AppendUvarint(buf, uint64(len(policy)))
+				buf = append(buf, policy...)
+			}
+		}
+		return buf, nil
+	case *publishResponse:
+		return []byte{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported message type %T", v)
+	}
+}
+
+func (decisionCodec) Unmarshal(data []byte, v any) error {
+	switch msg := v.(type) {
+	case *publishRequest:
+		msg.decisions = make([]decision, 0, len(data)/minDecisionSize)
+		for len(data) > 0 {
+			if len(data) < minDecisionSize {
+				return errInvalidPayload
+			}
+			var d decision
+			copy(d.traceID[:], data[:16])
+			d.Sampled = data[16] == 1
+			data = data[17:]
+
+			count, n := binary.Uvarint(data)
+			// each policy takes at least one byte
+			if n <= 0 || count > uint64(len(data)-n) {
+				return errInvalidPayload
+			}
+			data = data[n:]
+			if count > 0 {
+				d.Policies = make([]string, 0, count)
+			}
+			for ; count > 0; count-- {
+				size, n := binary.Uvarint(data)
+				if n <= 0 || size > uint64(len(data)-n) {
+					return errInvalidPayload
+				}
+				d.Policies = append(d.Policies, string(data[n:n+int(size)]))
+				data = data[n+int(size):]
+			}
+			msg.decisions = append(msg.decisions, d)
+		}
+		return nil
+	case *publishResponse:
+		return nil
+	default:
+		return fmt.Errorf("unsupported message type %T", v)
+	}
+}
@@ -0,0 +1,306 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+// Package peer implements a decision cache that shares sampling decisions with
+// other collector replicas over gRPC, so that spans of a trace arriving at a
+// replica other than the one that took the decision follow that decision.
+package peer // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/peer"
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
+)
+
+const (
+	serviceName   = "tailsampling.v1.DecisionSharing"
+	publishMethod = "/" + serviceName + "/Publish"
+
+	// maxBatchSize is the maximum number of decisions sent to a peer in a single request.
+	maxBatchSize = 10000
+	// publishTimeout bounds the time spent sending a batch of decisions to a single peer.
+	publishTimeout = 5 * time.Second
+)
+
+// Config holds the settings of a DecisionCache.
+type Config struct {
+	// Server is the configuration of the gRPC server receiving decisions from peers.
+	// When nil, decisions from peers are not received.
+	Server *configgrpc.ServerConfig
+	// Peers are the collectors to which local decisions are published.
+	Peers []configgrpc.ClientConfig
+	// CacheSize is the maximum number of decisions kept in memory.
+	CacheSize int
+	// TTL is the duration for which a decision is kept.
+	TTL time.Duration
+	// PublishInterval is the interval at which pending decisions are sent to the peers.
+	PublishInterval time.Duration
+}
+
+// decisionReceiver is implemented by the gRPC service receiving decisions from peers.
+type decisionReceiver interface {
+	receive(ctx context.Context, req *publishRequest) (*publishResponse, error)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*decisionReceiver)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Publish",
+			Handler:    publishHandler,
+		},
+	},
+}
+
+// Decision is a sampling decision held by a DecisionCache.
+type Decision struct {
+	// Sampled indicates whether the trace was sampled.
+	Sampled bool
+	// Policies are the names of the policies that sampled the trace, if any.
+	Policies []string
+}
+
+// DecisionCache is a cache.Cache holding sampling decisions. Decisions put in the cache are
+// published to the configured peers, and decisions received from peers are
+// made available through Get and GetFromPeer.
+type DecisionCache struct {
+	logger *zap.Logger
+	set    component.TelemetrySettings
+	cfg    Config
+	local  cache.Cache[entry]
+
+	mu      sync.Mutex
+	pending []decision
+
+	server  *grpc.Server
+	clients []*grpc.ClientConn
+
+	done       chan struct{}
+	doneOnce   sync.Once
+	shutdownWG sync.WaitGroup
+}
+
+// entry is a decision held by the cache, along with whether it was taken by a peer.
+type entry struct {
+	decision Decision
+	fromPeer bool
+}
+
+var (
+	_ cache.Cache[Decision] = (*DecisionCache)(nil)
+	_ component.Component   = (*DecisionCache)(nil)
+	_ decisionReceiver      = (*DecisionCache)(nil)
+)
+
+// NewDecisionCache returns a new DecisionCache. Peers are not contacted until
+// the cache is started.
+func NewDecisionCache(set component.TelemetrySettings, cfg Config) (*DecisionCache, error) {
+	local, err := cache.NewTTLDecisionCache[entry](cfg.CacheSize, cfg.TTL)
+	if err != nil {
+		return nil, err
+	}
+	return &DecisionCache{
+		logger: set.Logger,
+		set:    set,
+		cfg:    cfg,
+		local:  local,
+		done:   make(chan struct{}),
+	}, nil
+}
+
+// Start starts the gRPC server receiving decisions, connects to the peers and
+// starts publishing decisions. When it fails, what was started is stopped.
+func (c *DecisionCache) Start(ctx context.Context, host component.Host) (err error) {
+	defer func() {
+		if err != nil {
+			err = errors.Join(err, c.stop())
+		}
+	}()
+
+	if c.cfg.Server != nil {
+		server, err := c.cfg.Server.ToServer(ctx, host, c.set, grpc.ForceServerCodec(decisionCodec{}))
+		if err != nil {
+			return err
+		}
+		server.RegisterService(&serviceDesc, c)
+
+		c.logger.Info("Starting decision sharing server", zap.String("endpoint", c.cfg.Server.NetAddr.Endpoint))
+		listener, err := c.cfg.Server.NetAddr.Listen(ctx)
+		if err != nil {
+			return err
+		}
+		c.server = server
+
+		c.shutdownWG.Add(1)
+		go func() {
+			defer c.shutdownWG.Done()
+			if errGRPC := server.Serve(listener); errGRPC != nil && !errors.Is(errGRPC, grpc.ErrServerStopped) {
+				c.set.ReportStatus(component.NewFatalErrorEvent(errGRPC))
+			}
+		}()
+	}
+
+	for i := range c.cfg.Peers {
+		conn, err := c.cfg.Peers[i].ToClientConn(ctx, host, c.set, grpc.WithDefaultCallOptions(grpc.ForceCodec(decisionCodec{})))
+		if err != nil {
+			return err
+		}
+		c.clients = append(c.clients, conn)
+	}
+
+	if len(c.clients) > 0 {
+		c.shutdownWG.Add(1)
+		go c.publishLoop()
+	}
+	return nil
+}
+
+// Shutdown stops publishing decisions, closes the connections to the peers and
+// stops the gRPC server. It may be called more than once.
+func (c *DecisionCache) Shutdown(context.Context) error {
+	c.doneOnce.Do(func() {
+		close(c.done)
+	})
+	return c.stop()
+}
+
+// stop stops the gRPC server and closes the connections to the peers, once the
+// publishing of decisions is stopped.
+func (c *DecisionCache) stop() error {
+	if c.server != nil {
+		c.server.Stop()
+		c.server = nil
+	}
+	c.shutdownWG.Wait()
+
+	var errs error
+	for _, conn := range c.clients {
+		errs = errors.Join(errs, conn.Close())
+	}
+	c.clients = nil
+	return errs
+}
+
+// Get returns the decision for the trace with the given id, and a boolean
+// indicating whether a decision is known for it.
+func (c *DecisionCache) Get(id pcommon.TraceID) (Decision, bool) {
+	e, ok := c.local.Get(id)
+	return e.decision, ok
+}
+
+// GetFromPeer returns the decision for the trace with the given id, and a boolean
+// indicating whether a decision taken by a peer is known for it. The decisions put in
+// the cache by this collector are ignored.
+func (c *DecisionCache) GetFromPeer(id pcommon.TraceID) (Decision, bool) {
+	e, ok := c.local.Get(id)
+	return e.decision, ok && e.fromPeer
+}
+
+// Put records the decision for the given trace, and queues it for publishing to the peers.
+func (c *DecisionCache) Put(id pcommon.TraceID, d Decision) {
+	c.local.Put(id, entry{decision: d})
+	if len(c.cfg.Peers) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.pending) >= c.cfg.CacheSize {
+		c.logger.Debug("Dropping sampling decision, too many decisions pending publication")
+		return
+	}
+	c.pending = append(c.pending, decision{traceID: id, Decision: d})
+}
+
+// Delete removes the decision for the given trace from the local cache. It is not propagated to the peers.
+func (c *DecisionCache) Delete(id pcommon.TraceID) {
+	c.local.Delete(id)
+}
+
+func (c *DecisionCache) receive(_ context.Context, req *publishRequest) (*publishResponse, error) {
+	for _, d := range req.decisions {
+		c.local.Put(d.traceID, entry{decision: d.Decision, fromPeer: true})
+	}
+	return &publishResponse{}, nil
+}
+
+func (c *DecisionCache) publishLoop() {
+	defer c.shutdownWG.Done()
+
+	ticker := time.NewTicker(c.cfg.PublishInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			c.publish()
+		}
+	}
+}
+
+// publish sends all pending decisions to every peer.
+func (c *DecisionCache) publish() {
+	c.mu.Lock()
+	decisions := c.pending
+	c.pending = nil
+	c.mu.Unlock()
+
+	if len(decisions) == 0 {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i, conn := range c.clients {
+		wg.Add(1)
+		go func(endpoint string, conn *grpc.ClientConn) {
+			defer wg.Done()
+			for start := 0; start < len(decisions); start += maxBatchSize {
+				end := min(start+maxBatchSize, len(decisions))
+				if err := c.send(conn, decisions[start:end]); err != nil {
+					c.logger.Warn("Failed to publish sampling decisions to peer",
+						zap.String("endpoint", endpoint),
+						zap.Int("decisions", len(decisions)-start),
+						zap.Error(err))
+					return
+				}
+			}
+		}(c.cfg.Peers[i].Endpoint, conn)
+	}
+	wg.Wait()
+}
+
+func (c *DecisionCache) send(conn *grpc.ClientConn, decisions []decision) error {
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	return conn.Invoke(ctx, publishMethod, &publishRequest{decisions: decisions}, &publishResponse{})
+}
+
+//nolint:revive // the signature is mandated by grpc.MethodDesc
+func publishHandler(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	req := &publishRequest{}
+	if err := dec(req); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(decisionReceiver).receive(ctx, req)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: publishMethod,
+	}
+	handler := func(ctx context.Context, req any) (any, error) {
+		return srv.(decisionReceiver).receive(ctx, req.(*publishRequest))
+	}
+	return interceptor(ctx, req, info, handler)
+}
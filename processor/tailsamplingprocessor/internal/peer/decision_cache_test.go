@@ -0,0 +1,153 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package peer
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configgrpc"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/config/configtls"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	req := &publishRequest{decisions: []decision{
+		{traceID: pcommon.TraceID([16]byte{1, 2, 3}), Decision: Decision{Sampled: true, Policies: []string{"errors", "slow"}}},
+		{traceID: pcommon.TraceID([16]byte{4, 5, 6}), Decision: Decision{Sampled: false}},
+	}}
+
+	codec := decisionCodec{}
+	data, err := codec.Marshal(req)
+	require.NoError(t, err)
+	assert.Len(t, data, 2*minDecisionSize+len("errors")+len("slow")+2)
+
+	got := &publishRequest{}
+	require.NoError(t, codec.Unmarshal(data, got))
+	assert.Equal(t, req, got)
+}
+
+func TestCodecInvalidPayload(t *testing.T) {
+	err := decisionCodec{}.Unmarshal([]byte{1, 2, 3}, &publishRequest{})
+	assert.ErrorIs(t, err, errInvalidPayload)
+
+	// a policy name longer than the rest of the payload
+	data, err := decisionCodec{}.Marshal(&publishRequest{decisions: []decision{
+		{Decision: Decision{Sampled: true, Policies: []string{"errors"}}},
+	}})
+	require.NoError(t, err)
+	err = decisionCodec{}.Unmarshal(data[:len(data)-1], &publishRequest{})
+	assert.ErrorIs(t, err, errInvalidPayload)
+
+	_, err = decisionCodec{}.Marshal("unsupported")
+	assert.Error(t, err)
+}
+
+func TestDecisionsArePublishedToPeers(t *testing.T) {
+	endpoint := availableLocalAddress(t)
+
+	receiver, err := NewDecisionCache(componenttest.NewNopTelemetrySettings(), Config{
+		Server: &configgrpc.ServerConfig{
+			NetAddr: confignet.AddrConfig{Endpoint: endpoint, Transport: confignet.TransportTypeTCP},
+		},
+		CacheSize: 100,
+		TTL:       time.Minute,
+	})
+	require.NoError(t, err)
+	require.NoError(t, receiver.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, receiver.Shutdown(context.Background())) }()
+
+	publisher, err := NewDecisionCache(componenttest.NewNopTelemetrySettings(), Config{
+		Peers: []configgrpc.ClientConfig{
+			{Endpoint: endpoint, TLSSetting: configtls.ClientConfig{Insecure: true}},
+		},
+		CacheSize:       100,
+		TTL:             time.Minute,
+		PublishInterval: 10 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	require.NoError(t, publisher.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, publisher.Shutdown(context.Background())) }()
+
+	sampledID := pcommon.TraceID([16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16})
+	notSampledID := pcommon.TraceID([16]byte{16, 15, 14, 13, 12, 11, 10, 9, 8, 7, 6, 5, 4, 3, 2, 1})
+	publisher.Put(sampledID, Decision{Sampled: true, Policies: []string{"errors"}})
+	publisher.Put(notSampledID, Decision{Sampled: false})
+
+	// the publisher knows about its own decisions right away, which weren't taken by a peer
+	d, ok := publisher.Get(sampledID)
+	assert.True(t, ok)
+	assert.True(t, d.Sampled)
+	_, ok = publisher.GetFromPeer(sampledID)
+	assert.False(t, ok)
+
+	assert.Eventually(t, func() bool {
+		_, okSampled := receiver.Get(sampledID)
+		_, okNotSampled := receiver.Get(notSampledID)
+		return okSampled && okNotSampled
+	}, 5*time.Second, 10*time.Millisecond)
+
+	d, _ = receiver.Get(sampledID)
+	assert.True(t, d.Sampled)
+	d, _ = receiver.Get(notSampledID)
+	assert.False(t, d.Sampled)
+	d, ok = receiver.GetFromPeer(sampledID)
+	assert.True(t, ok)
+	assert.Equal(t, Decision{Sampled: true, Policies: []string{"errors"}}, d)
+}
+
+func TestShutdownWithoutStart(t *testing.T) {
+	c, err := NewDecisionCache(componenttest.NewNopTelemetrySettings(), Config{CacheSize: 10, TTL: time.Minute})
+	require.NoError(t, err)
+	assert.NoError(t, c.Shutdown(context.Background()))
+}
+
+func TestShutdownTwice(t *testing.T) {
+	c, err := NewDecisionCache(componenttest.NewNopTelemetrySettings(), Config{CacheSize: 10, TTL: time.Minute})
+	require.NoError(t, err)
+	require.NoError(t, c.Start(context.Background(), componenttest.NewNopHost()))
+	assert.NoError(t, c.Shutdown(context.Background()))
+	assert.NoError(t, c.Shutdown(context.Background()))
+}
+
+func TestStartFailureStopsServer(t *testing.T) {
+	endpoint := availableLocalAddress(t)
+
+	c, err := NewDecisionCache(componenttest.NewNopTelemetrySettings(), Config{
+		Server: &configgrpc.ServerConfig{
+			NetAddr: confignet.AddrConfig{Endpoint: endpoint, Transport: confignet.TransportTypeTCP},
+		},
+		Peers: []configgrpc.ClientConfig{
+			{Endpoint: endpoint, TLSSetting: configtls.ClientConfig{Insecure: true}},
+			{Endpoint: endpoint, TLSSetting: configtls.ClientConfig{Config: configtls.Config{CAFile: "/nonexistent/ca.pem"}}},
+		},
+		CacheSize:       10,
+		TTL:             time.Minute,
+		PublishInterval: time.Minute,
+	})
+	require.NoError(t, err)
+	require.Error(t, c.Start(context.Background(), componenttest.NewNopHost()))
+	assert.Nil(t, c.server)
+	assert.Empty(t, c.clients)
+
+	// the endpoint the server was listening on is released
+	ln, err := net.Listen("tcp", endpoint)
+	require.NoError(t, err)
+	require.NoError(t, ln.Close())
+
+	assert.NoError(t, c.Shutdown(context.Background()))
+}
+
+func availableLocalAddress(t *testing.T) string {
+	ln, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	return ln.Addr().String()
+}
@@ -26,6 +26,8 @@ type TraceData struct {
 	ReceivedBatches ptrace.Traces
 	// FinalDecision.
 	FinalDecision Decision
+	// SampledBy holds the names of the policies that caused the trace to be sampled, if any.
+	SampledBy []string
 }
 
 // Decision gives the status of sampling decision.
@@ -25,6 +25,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/idbatcher"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/metadata"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/peer"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/telemetry"
 )
@@ -48,16 +49,32 @@ type tailSamplingSpanProcessor struct {
 	telemetry *metadata.TelemetryBuilder
 	logger    *zap.Logger
 
-	nextConsumer    consumer.Traces
-	maxNumTraces    uint64
-	policies        []*policy
-	idToTrace       sync.Map
-	policyTicker    timeutils.TTicker
-	tickerFrequency time.Duration
-	decisionBatcher idbatcher.Batcher
-	sampledIDCache  cache.Cache[bool]
-	deleteChan      chan pcommon.TraceID
-	numTracesOnMap  *atomic.Uint64
+	nextConsumer      consumer.Traces
+	maxNumTraces      uint64
+	policies          []*policy
+	idToTrace         sync.Map
+	policyTicker      timeutils.TTicker
+	tickerFrequency   time.Duration
+	decisionBatcher   idbatcher.Batcher
+	sampledIDCache    cache.Cache[[]string]
+	nonSampledIDCache cache.Cache[bool]
+	deleteChan        chan pcommon.TraceID
+	numTracesOnMap    *atomic.Uint64
+	recordPolicy      bool
+
+	// sharedDecisions holds the decisions shared with other collector replicas.
+	// It is nil when decision sharing isn't configured.
+	sharedDecisions sharedDecisionCache
+}
+
+// sharedDecisionCache is a decision cache whose contents are shared with other
+// collector replicas, and which therefore needs to be started and stopped along
+// with the processor.
+type sharedDecisionCache interface {
+	component.Component
+	cache.Cache[peer.Decision]
+	// GetFromPeer returns the decision for the given trace if it was taken by another collector.
+	GetFromPeer(id pcommon.TraceID) (peer.Decision, bool)
 }
 
 // spanAndScope a structure for holding information about span and its instrumentation scope.
@@ -68,6 +85,9 @@ type spanAndScope struct {
 	instrumentationScope *pcommon.InstrumentationScope
 }
 
+// policyAttributeKey is the span attribute holding the names of the policies that sampled the trace.
+const policyAttributeKey = "tailsampling.policy"
+
 var (
 	attrSampledTrue     = metric.WithAttributes(attribute.String("sampled", "true"))
 	attrSampledFalse    = metric.WithAttributes(attribute.String("sampled", "false"))
@@ -77,6 +97,13 @@ var (
 		sampling.InvertNotSampled: attrSampledFalse,
 		sampling.InvertSampled:    attrSampledTrue,
 	}
+	policyDecisionToAttribute = map[sampling.Decision]metric.MeasurementOption{
+		sampling.Sampled:          metric.WithAttributes(attribute.String("decision", "sampled")),
+		sampling.NotSampled:       metric.WithAttributes(attribute.String("decision", "not_sampled")),
+		sampling.InvertSampled:    metric.WithAttributes(attribute.String("decision", "inverted_sampled")),
+		sampling.InvertNotSampled: metric.WithAttributes(attribute.String("decision", "inverted_not_sampled")),
+		sampling.Error:            metric.WithAttributes(attribute.String("decision", "error")),
+	}
 )
 
 type Option func(*tailSamplingSpanProcessor)
@@ -88,26 +115,42 @@ func newTracesProcessor(ctx context.Context, settings component.TelemetrySetting
 	if err != nil {
 		return nil, err
 	}
-	sampledDecisions := cache.NewNopDecisionCache[bool]()
+	sampledDecisions := cache.NewNopDecisionCache[[]string]()
 	if cfg.DecisionCache.SampledCacheSize > 0 {
-		sampledDecisions, err = cache.NewLRUDecisionCache[bool](cfg.DecisionCache.SampledCacheSize)
+		sampledDecisions, err = cache.NewLRUDecisionCache[[]string](cfg.DecisionCache.SampledCacheSize)
+		if err != nil {
+			return nil, err
+		}
+	}
+	nonSampledDecisions := cache.NewNopDecisionCache[bool]()
+	if cfg.DecisionCache.NonSampledCacheSize > 0 {
+		nonSampledDecisions, err = cache.NewLRUDecisionCache[bool](cfg.DecisionCache.NonSampledCacheSize)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	tsp := &tailSamplingSpanProcessor{
-		ctx:            ctx,
-		telemetry:      telemetry,
-		nextConsumer:   nextConsumer,
-		maxNumTraces:   cfg.NumTraces,
-		sampledIDCache: sampledDecisions,
-		logger:         settings.Logger,
-		numTracesOnMap: &atomic.Uint64{},
-		deleteChan:     make(chan pcommon.TraceID, cfg.NumTraces),
+		ctx:               ctx,
+		telemetry:         telemetry,
+		nextConsumer:      nextConsumer,
+		maxNumTraces:      cfg.NumTraces,
+		sampledIDCache:    sampledDecisions,
+		nonSampledIDCache: nonSampledDecisions,
+		logger:            settings.Logger,
+		numTracesOnMap:    &atomic.Uint64{},
+		deleteChan:        make(chan pcommon.TraceID, cfg.NumTraces),
+		recordPolicy:      cfg.RecordPolicy,
 	}
 	tsp.policyTicker = &timeutils.PolicyTicker{OnTickFunc: tsp.samplingPolicyOnTick}
 
+	if sharingCfg := cfg.DecisionCache.Sharing; sharingCfg != nil {
+		tsp.sharedDecisions, err = newSharedDecisionCache(settings, sharingCfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	for _, opt := range opts {
 		opt(tsp)
 	}
@@ -175,13 +218,48 @@ func withTickerFrequency(frequency time.Duration) Option {
 	}
 }
 
-// withSampledDecisionCache sets the cache which the processor uses to store recently sampled trace IDs.
-func withSampledDecisionCache(c cache.Cache[bool]) Option {
+// withSampledDecisionCache sets the cache which the processor uses to store recently sampled trace IDs,
+// along with the names of the policies that sampled them.
+func withSampledDecisionCache(c cache.Cache[[]string]) Option {
 	return func(tsp *tailSamplingSpanProcessor) {
 		tsp.sampledIDCache = c
 	}
 }
 
+// withNonSampledDecisionCache sets the cache which the processor uses to store recently non-sampled trace IDs.
+func withNonSampledDecisionCache(c cache.Cache[bool]) Option {
+	return func(tsp *tailSamplingSpanProcessor) {
+		tsp.nonSampledIDCache = c
+	}
+}
+
+// withSharedDecisionCache sets the cache which the processor uses to share decisions with other collectors.
+func withSharedDecisionCache(c sharedDecisionCache) Option {
+	return func(tsp *tailSamplingSpanProcessor) {
+		tsp.sharedDecisions = c
+	}
+}
+
+func newSharedDecisionCache(settings component.TelemetrySettings, cfg *DecisionSharingConfig) (sharedDecisionCache, error) {
+	peerCfg := peer.Config{
+		Server:          cfg.Server,
+		Peers:           cfg.Peers,
+		CacheSize:       cfg.CacheSize,
+		TTL:             cfg.TTL,
+		PublishInterval: cfg.PublishInterval,
+	}
+	if peerCfg.CacheSize == 0 {
+		peerCfg.CacheSize = 100000
+	}
+	if peerCfg.TTL == 0 {
+		peerCfg.TTL = 5 * time.Minute
+	}
+	if peerCfg.PublishInterval == 0 {
+		peerCfg.PublishInterval = 100 * time.Millisecond
+	}
+	return peer.NewDecisionCache(settings, peerCfg)
+}
+
 func getPolicyEvaluator(settings component.TelemetrySettings, cfg *PolicyCfg) (sampling.PolicyEvaluator, error) {
 	switch cfg.Type {
 	case Composite:
@@ -255,7 +333,15 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 		trace := d.(*sampling.TraceData)
 		trace.DecisionTime = time.Now()
 
-		decision := tsp.makeDecision(id, trace, &metrics)
+		decision, sampledBy, shared := tsp.sharedDecision(id)
+		if shared {
+			tsp.telemetry.ProcessorTailSamplingDecisionsFromPeers.Add(tsp.ctx, 1, decisionToAttribute[decision])
+		} else {
+			decision, sampledBy = tsp.makeDecision(id, trace, &metrics)
+			if tsp.sharedDecisions != nil {
+				tsp.sharedDecisions.Put(id, peer.Decision{Sampled: decision == sampling.Sampled, Policies: sampledBy})
+			}
+		}
 		tsp.telemetry.ProcessorTailSamplingSamplingDecisionTimerLatency.Record(tsp.ctx, int64(time.Since(startTime)/time.Microsecond))
 		tsp.telemetry.ProcessorTailSamplingSamplingTraceDroppedTooEarly.Add(tsp.ctx, metrics.idNotFoundOnMapCount)
 		tsp.telemetry.ProcessorTailSamplingSamplingPolicyEvaluationError.Add(tsp.ctx, metrics.evaluateErrorCount)
@@ -266,11 +352,14 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 		trace.Lock()
 		allSpans := trace.ReceivedBatches
 		trace.FinalDecision = decision
+		trace.SampledBy = sampledBy
 		trace.ReceivedBatches = ptrace.NewTraces()
 		trace.Unlock()
 
 		if decision == sampling.Sampled {
-			tsp.releaseSampledTrace(context.Background(), id, allSpans)
+			tsp.releaseSampledTrace(context.Background(), id, allSpans, sampledBy)
+		} else {
+			tsp.nonSampledIDCache.Put(id, true)
 		}
 	}
 
@@ -283,8 +372,29 @@ func (tsp *tailSamplingSpanProcessor) samplingPolicyOnTick() {
 	)
 }
 
-func (tsp *tailSamplingSpanProcessor) makeDecision(id pcommon.TraceID, trace *sampling.TraceData, metrics *policyMetrics) sampling.Decision {
+// sharedDecision returns the decision shared by another collector for the given trace, if any,
+// along with the names of the policies that sampled the trace.
+// The decisions taken by this collector are taken again, as if they weren't shared.
+func (tsp *tailSamplingSpanProcessor) sharedDecision(id pcommon.TraceID) (sampling.Decision, []string, bool) {
+	if tsp.sharedDecisions == nil {
+		return sampling.Unspecified, nil, false
+	}
+	d, ok := tsp.sharedDecisions.GetFromPeer(id)
+	if !ok {
+		return sampling.Unspecified, nil, false
+	}
+	if d.Sampled {
+		return sampling.Sampled, d.Policies, true
+	}
+	return sampling.NotSampled, nil, true
+}
+
+// makeDecision evaluates all policies for the given trace and returns the final decision,
+// along with the names of the policies that caused the trace to be sampled, if any, in
+// the order they're configured.
+func (tsp *tailSamplingSpanProcessor) makeDecision(id pcommon.TraceID, trace *sampling.TraceData, metrics *policyMetrics) (sampling.Decision, []string) {
 	finalDecision := sampling.NotSampled
+	var sampledBy, invertSampledBy []string
 	samplingDecision := map[sampling.Decision]bool{
 		sampling.Error:            false,
 		sampling.Sampled:          false,
@@ -302,14 +412,22 @@ func (tsp *tailSamplingSpanProcessor) makeDecision(id pcommon.TraceID, trace *sa
 		if err != nil {
 			samplingDecision[sampling.Error] = true
 			metrics.evaluateErrorCount++
+			tsp.telemetry.ProcessorTailSamplingCountPolicyDecisions.Add(ctx, 1, p.attribute, policyDecisionToAttribute[sampling.Error])
 			tsp.logger.Debug("Sampling policy error", zap.Error(err))
 		} else {
+			tsp.telemetry.ProcessorTailSamplingCountPolicyDecisions.Add(ctx, 1, p.attribute, policyDecisionToAttribute[decision])
 			tsp.telemetry.ProcessorTailSamplingCountTracesSampled.Add(ctx, 1, p.attribute, decisionToAttribute[decision])
 			if telemetry.IsMetricStatCountSpansSampledEnabled() {
 				tsp.telemetry.ProcessorTailSamplingCountSpansSampled.Add(ctx, trace.SpanCount.Load(), p.attribute, decisionToAttribute[decision])
 			}
 
 			samplingDecision[decision] = true
+			switch decision {
+			case sampling.Sampled:
+				sampledBy = append(sampledBy, p.name)
+			case sampling.InvertSampled:
+				invertSampledBy = append(invertSampledBy, p.name)
+			}
 		}
 	}
 
@@ -319,11 +437,13 @@ func (tsp *tailSamplingSpanProcessor) makeDecision(id pcommon.TraceID, trace *sa
 		finalDecision = sampling.NotSampled
 	case samplingDecision[sampling.Sampled]:
 		finalDecision = sampling.Sampled
+		return finalDecision, sampledBy
 	case samplingDecision[sampling.InvertSampled] && !samplingDecision[sampling.NotSampled]:
 		finalDecision = sampling.Sampled
+		return finalDecision, invertSampledBy
 	}
 
-	return finalDecision
+	return finalDecision, nil
 }
 
 // ConsumeTraces is required by the processor.Traces interface.
@@ -361,13 +481,31 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.Resourc
 	var newTraceIDs int64
 	for id, spans := range idToSpansAndScope {
 		// If the trace ID is in the sampled cache, short circuit the decision
-		if _, ok := tsp.sampledIDCache.Get(id); ok {
+		if sampledBy, ok := tsp.sampledIDCache.Get(id); ok {
 			traceTd := ptrace.NewTraces()
 			appendToTraces(traceTd, resourceSpans, spans)
-			tsp.releaseSampledTrace(tsp.ctx, id, traceTd)
+			tsp.releaseSampledTrace(tsp.ctx, id, traceTd, sampledBy)
 			tsp.telemetry.ProcessorTailSamplingEarlyReleasesFromCacheDecision.Add(tsp.ctx, int64(len(spans)))
 			continue
 		}
+		// If the trace ID is in the non-sampled cache, short circuit the decision
+		if _, ok := tsp.nonSampledIDCache.Get(id); ok {
+			tsp.telemetry.ProcessorTailSamplingEarlyDropsFromCacheDecision.Add(tsp.ctx, int64(len(spans)))
+			continue
+		}
+
+		// If a decision for the trace was shared by another collector, follow it
+		if decision, sampledBy, ok := tsp.sharedDecision(id); ok {
+			if decision == sampling.Sampled {
+				traceTd := ptrace.NewTraces()
+				appendToTraces(traceTd, resourceSpans, spans)
+				tsp.releaseSampledTrace(tsp.ctx, id, traceTd, sampledBy)
+				tsp.telemetry.ProcessorTailSamplingEarlyReleasesFromCacheDecision.Add(tsp.ctx, int64(len(spans)))
+			} else {
+				tsp.telemetry.ProcessorTailSamplingEarlyDropsFromCacheDecision.Add(tsp.ctx, int64(len(spans)))
+			}
+			continue
+		}
 
 		lenSpans := int64(len(spans))
 		lenPolicies := len(tsp.policies)
@@ -408,6 +546,7 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.Resourc
 		// The only thing we really care about here is the final decision.
 		actualData.Lock()
 		finalDecision := actualData.FinalDecision
+		sampledBy := actualData.SampledBy
 
 		if finalDecision == sampling.Unspecified {
 			// If the final decision hasn't been made, add the new spans under the lock.
@@ -421,7 +560,7 @@ func (tsp *tailSamplingSpanProcessor) processTraces(resourceSpans ptrace.Resourc
 				// Forward the spans to the policy destinations
 				traceTd := ptrace.NewTraces()
 				appendToTraces(traceTd, resourceSpans, spans)
-				tsp.releaseSampledTrace(tsp.ctx, id, traceTd)
+				tsp.releaseSampledTrace(tsp.ctx, id, traceTd, sampledBy)
 			case sampling.NotSampled:
 				tsp.telemetry.ProcessorTailSamplingSamplingLateSpanAge.Record(tsp.ctx, int64(time.Since(actualData.DecisionTime)/time.Second))
 			default:
@@ -439,15 +578,23 @@ func (tsp *tailSamplingSpanProcessor) Capabilities() consumer.Capabilities {
 }
 
 // Start is invoked during service startup.
-func (tsp *tailSamplingSpanProcessor) Start(context.Context, component.Host) error {
+func (tsp *tailSamplingSpanProcessor) Start(ctx context.Context, host component.Host) error {
+	if tsp.sharedDecisions != nil {
+		if err := tsp.sharedDecisions.Start(ctx, host); err != nil {
+			return err
+		}
+	}
 	tsp.policyTicker.Start(tsp.tickerFrequency)
 	return nil
 }
 
 // Shutdown is invoked during service shutdown.
-func (tsp *tailSamplingSpanProcessor) Shutdown(context.Context) error {
+func (tsp *tailSamplingSpanProcessor) Shutdown(ctx context.Context) error {
 	tsp.decisionBatcher.Stop()
 	tsp.policyTicker.Stop()
+	if tsp.sharedDecisions != nil {
+		return tsp.sharedDecisions.Shutdown(ctx)
+	}
 	return nil
 }
 
@@ -468,10 +615,14 @@ func (tsp *tailSamplingSpanProcessor) dropTrace(traceID pcommon.TraceID, deletio
 }
 
 // releaseSampledTrace sends the trace data to the next consumer.
-// It additionally adds the trace ID to the cache of sampled trace IDs.
+// It additionally adds the trace ID to the cache of sampled trace IDs, and
+// records the policies that sampled the trace on its spans when configured to.
 // It does not (yet) delete the spans from the internal map.
-func (tsp *tailSamplingSpanProcessor) releaseSampledTrace(ctx context.Context, id pcommon.TraceID, td ptrace.Traces) {
-	tsp.sampledIDCache.Put(id, true)
+func (tsp *tailSamplingSpanProcessor) releaseSampledTrace(ctx context.Context, id pcommon.TraceID, td ptrace.Traces, sampledBy []string) {
+	tsp.sampledIDCache.Put(id, sampledBy)
+	if tsp.recordPolicy && len(sampledBy) > 0 {
+		recordPolicy(td, sampledBy)
+	}
 	if err := tsp.nextConsumer.ConsumeTraces(ctx, td); err != nil {
 		tsp.logger.Warn(
 			"Error sending spans to destination",
@@ -479,6 +630,23 @@ func (tsp *tailSamplingSpanProcessor) releaseSampledTrace(ctx context.Context, i
 	}
 }
 
+func recordPolicy(td ptrace.Traces, policyNames []string) {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		ilss := rss.At(i).ScopeSpans()
+		for j := 0; j < ilss.Len(); j++ {
+			spans := ilss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				names := spans.At(k).Attributes().PutEmptySlice(policyAttributeKey)
+				names.EnsureCapacity(len(policyNames))
+				for _, name := range policyNames {
+					names.AppendEmpty().SetStr(name)
+				}
+			}
+		}
+	}
+}
+
 func appendToTraces(dest ptrace.Traces, rss ptrace.ResourceSpans, spanAndScopes []spanAndScope) {
 	rs := dest.ResourceSpans().AppendEmpty()
 	rss.Resource().CopyTo(rs.Resource())
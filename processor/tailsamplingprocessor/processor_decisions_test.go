@@ -9,13 +9,16 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/cache"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/peer"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/tailsamplingprocessor/internal/sampling"
 )
 
@@ -343,7 +346,7 @@ func TestLateArrivingSpanUsesDecisionCache(t *testing.T) {
 	}
 
 	// Use this instead of the default no-op cache
-	c, err := cache.NewLRUDecisionCache[bool](200)
+	c, err := cache.NewLRUDecisionCache[[]string](200)
 	require.NoError(t, err)
 	p, err := newTracesProcessor(context.Background(), ct, nextConsumer, cfg, withDecisionBatcher(idb), withPolicies(policies), withSampledDecisionCache(c))
 	require.NoError(t, err)
@@ -400,3 +403,249 @@ func TestLateArrivingSpanUsesDecisionCache(t *testing.T) {
 	require.EqualValues(t, 1, mpe.EvaluationCount)
 	require.EqualValues(t, 2, nextConsumer.SpanCount(), "original final decision not honored")
 }
+
+func TestSharedDecisionCache(t *testing.T) {
+	cfg := Config{
+		DecisionWait: defaultTestDecisionWait,
+		NumTraces:    defaultNumTraces,
+		RecordPolicy: true,
+	}
+	nextConsumer := new(consumertest.TracesSink)
+	s := setupTestTelemetry()
+	ct := s.NewSettings().TelemetrySettings
+	idb := newSyncIDBatcher()
+
+	mpe := &mockPolicyEvaluator{}
+	policies := []*policy{
+		{name: "mock-policy-1", evaluator: mpe, attribute: metric.WithAttributes(attribute.String("policy", "mock-policy-1"))},
+	}
+
+	c, err := cache.NewLRUDecisionCache[peer.Decision](200)
+	require.NoError(t, err)
+	shared := &fakeSharedDecisionCache{Cache: c, fromPeers: map[pcommon.TraceID]peer.Decision{}}
+	p, err := newTracesProcessor(context.Background(), ct, nextConsumer, cfg, withDecisionBatcher(idb), withPolicies(policies), withSharedDecisionCache(shared))
+	require.NoError(t, err)
+
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+	require.True(t, shared.started)
+
+	tsp := p.(*tailSamplingSpanProcessor)
+	mpe.NextDecision = sampling.Sampled
+
+	// A peer decided not to sample the first trace: it must not be sampled, even though the policy would sample it.
+	notSampledID := uInt64ToTraceID(1)
+	shared.fromPeers[notSampledID] = peer.Decision{Sampled: false}
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(notSampledID)))
+
+	// A peer decided to sample the second trace: its spans are released immediately, with the
+	// policies that sampled it on the peer.
+	sampledID := uInt64ToTraceID(2)
+	shared.fromPeers[sampledID] = peer.Decision{Sampled: true, Policies: []string{"peer-policy"}}
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(sampledID)))
+	require.EqualValues(t, 1, nextConsumer.SpanCount())
+	require.Equal(t, []any{"peer-policy"}, recordedPolicies(t, nextConsumer.AllTraces()[0]))
+
+	// The third trace is unknown to the peers, so the policies decide and the decision is shared.
+	localID := uInt64ToTraceID(3)
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(localID)))
+
+	tsp.policyTicker.OnTick()
+	tsp.policyTicker.OnTick()
+
+	require.EqualValues(t, 1, mpe.EvaluationCount)
+	require.EqualValues(t, 2, nextConsumer.SpanCount())
+	d, ok := shared.Get(localID)
+	require.True(t, ok)
+	require.Equal(t, peer.Decision{Sampled: true, Policies: []string{"mock-policy-1"}}, d)
+
+	// The spans of the third trace arriving once it was released from memory are not decided by the
+	// shared decision, which was taken by this collector: the policies decide again.
+	tsp.idToTrace.Delete(localID)
+	mpe.NextDecision = sampling.NotSampled
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(localID)))
+
+	tsp.policyTicker.OnTick()
+	tsp.policyTicker.OnTick()
+
+	require.EqualValues(t, 2, mpe.EvaluationCount)
+	require.EqualValues(t, 2, nextConsumer.SpanCount())
+	d, ok = shared.Get(localID)
+	require.True(t, ok)
+	require.False(t, d.Sampled)
+}
+
+type fakeSharedDecisionCache struct {
+	cache.Cache[peer.Decision]
+	// fromPeers holds the decisions taken by other collectors.
+	fromPeers map[pcommon.TraceID]peer.Decision
+	started   bool
+}
+
+func (f *fakeSharedDecisionCache) GetFromPeer(id pcommon.TraceID) (peer.Decision, bool) {
+	d, ok := f.fromPeers[id]
+	return d, ok
+}
+
+func (f *fakeSharedDecisionCache) Start(context.Context, component.Host) error {
+	f.started = true
+	return nil
+}
+
+func (f *fakeSharedDecisionCache) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestLateSpansInNonSampledDecisionCache(t *testing.T) {
+	cfg := Config{
+		DecisionWait: defaultTestDecisionWait,
+		NumTraces:    defaultNumTraces,
+	}
+	nextConsumer := new(consumertest.TracesSink)
+	s := setupTestTelemetry()
+	ct := s.NewSettings().TelemetrySettings
+	idb := newSyncIDBatcher()
+
+	mpe := &mockPolicyEvaluator{}
+	policies := []*policy{
+		{name: "mock-policy-1", evaluator: mpe, attribute: metric.WithAttributes(attribute.String("policy", "mock-policy-1"))},
+	}
+
+	// Use this instead of the default no-op cache
+	c, err := cache.NewLRUDecisionCache[bool](200)
+	require.NoError(t, err)
+	p, err := newTracesProcessor(context.Background(), ct, nextConsumer, cfg, withDecisionBatcher(idb), withPolicies(policies), withNonSampledDecisionCache(c))
+	require.NoError(t, err)
+
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	traceID := uInt64ToTraceID(1)
+
+	// The first span will not be sampled, this will later be set to sampled, but the sampling decision will be cached
+	mpe.NextDecision = sampling.NotSampled
+
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+
+	tsp := p.(*tailSamplingSpanProcessor)
+	tsp.policyTicker.OnTick()
+	tsp.policyTicker.OnTick()
+	require.EqualValues(t, 1, mpe.EvaluationCount)
+	require.EqualValues(t, 0, nextConsumer.SpanCount())
+
+	// Drop the trace to force cache to make decision
+	tsp.dropTrace(traceID, time.Now())
+	_, ok := tsp.idToTrace.Load(traceID)
+	require.False(t, ok)
+
+	// Set next decision to sampled, ensuring the next decision is determined by the decision cache, not the policy
+	mpe.NextDecision = sampling.Sampled
+
+	// The late span SHOULD get the same sampling decision as the first span, without evaluating the policies again.
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+	tsp.policyTicker.OnTick()
+	tsp.policyTicker.OnTick()
+	require.EqualValues(t, 1, mpe.EvaluationCount)
+	require.EqualValues(t, 0, nextConsumer.SpanCount(), "original final decision not honored")
+}
+
+func TestRecordPolicy(t *testing.T) {
+	cfg := Config{
+		DecisionWait: defaultTestDecisionWait,
+		NumTraces:    defaultNumTraces,
+		RecordPolicy: true,
+	}
+	nextConsumer := new(consumertest.TracesSink)
+	s := setupTestTelemetry()
+	ct := s.NewSettings().TelemetrySettings
+	idb := newSyncIDBatcher()
+
+	mpe1 := &mockPolicyEvaluator{NextDecision: sampling.NotSampled}
+	mpe2 := &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+	mpe3 := &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+	policies := []*policy{
+		{name: "mock-policy-1", evaluator: mpe1, attribute: metric.WithAttributes(attribute.String("policy", "mock-policy-1"))},
+		{name: "mock-policy-2", evaluator: mpe2, attribute: metric.WithAttributes(attribute.String("policy", "mock-policy-2"))},
+		{name: "mock-policy-3", evaluator: mpe3, attribute: metric.WithAttributes(attribute.String("policy", "mock-policy-3"))},
+	}
+
+	p, err := newTracesProcessor(context.Background(), ct, nextConsumer, cfg, withDecisionBatcher(idb), withPolicies(policies))
+	require.NoError(t, err)
+
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	traceID := uInt64ToTraceID(1)
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+
+	tsp := p.(*tailSamplingSpanProcessor)
+	tsp.policyTicker.OnTick()
+	tsp.policyTicker.OnTick()
+
+	// A late span arriving while the trace is still in memory is recorded with the same policy.
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+
+	require.EqualValues(t, 2, nextConsumer.SpanCount())
+	for _, td := range nextConsumer.AllTraces() {
+		require.Equal(t, []any{"mock-policy-2", "mock-policy-3"}, recordedPolicies(t, td))
+	}
+}
+
+func TestRecordPolicyOnDecisionCacheHit(t *testing.T) {
+	cfg := Config{
+		DecisionWait: defaultTestDecisionWait,
+		NumTraces:    defaultNumTraces,
+		DecisionCache: DecisionCacheConfig{
+			SampledCacheSize: 200,
+		},
+		RecordPolicy: true,
+	}
+	nextConsumer := new(consumertest.TracesSink)
+	s := setupTestTelemetry()
+	ct := s.NewSettings().TelemetrySettings
+	idb := newSyncIDBatcher()
+
+	mpe := &mockPolicyEvaluator{NextDecision: sampling.Sampled}
+	policies := []*policy{
+		{name: "mock-policy-1", evaluator: mpe, attribute: metric.WithAttributes(attribute.String("policy", "mock-policy-1"))},
+	}
+
+	p, err := newTracesProcessor(context.Background(), ct, nextConsumer, cfg, withDecisionBatcher(idb), withPolicies(policies))
+	require.NoError(t, err)
+
+	require.NoError(t, p.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, p.Shutdown(context.Background()))
+	}()
+
+	traceID := uInt64ToTraceID(1)
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+
+	tsp := p.(*tailSamplingSpanProcessor)
+	tsp.policyTicker.OnTick()
+	tsp.policyTicker.OnTick()
+
+	// A late span arriving once the trace was released from memory is recorded with the policy kept in the cache.
+	tsp.dropTrace(traceID, time.Now())
+	require.NoError(t, p.ConsumeTraces(context.Background(), simpleTracesWithID(traceID)))
+
+	require.EqualValues(t, 1, mpe.EvaluationCount)
+	require.EqualValues(t, 2, nextConsumer.SpanCount())
+	for _, td := range nextConsumer.AllTraces() {
+		require.Equal(t, []any{"mock-policy-1"}, recordedPolicies(t, td))
+	}
+}
+
+// recordedPolicies returns the policies recorded on the first span of the traces.
+func recordedPolicies(t *testing.T, td ptrace.Traces) []any {
+	span := td.ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0)
+	v, ok := span.Attributes().Get(policyAttributeKey)
+	require.True(t, ok)
+	return v.Slice().AsRaw()
+}
@@ -59,7 +59,7 @@ func TestMetricsAfterOneEvaluation(t *testing.T) {
 	// verify
 	var md metricdata.ResourceMetrics
 	require.NoError(t, s.reader.Collect(context.Background(), &md))
-	require.Equal(t, 8, s.len(md))
+	require.Equal(t, 9, s.len(md))
 
 	for _, tt := range []struct {
 		opts []metricdatatest.Option
@@ -86,6 +86,27 @@ func TestMetricsAfterOneEvaluation(t *testing.T) {
 				},
 			},
 		},
+		{
+			opts: []metricdatatest.Option{metricdatatest.IgnoreTimestamp()},
+			m: metricdata.Metrics{
+				Name:        "processor_tail_sampling_count_policy_decisions",
+				Description: "Count of decisions per sampling policy, by decision type (sampled, not_sampled, inverted_sampled, inverted_not_sampled or error)",
+				Unit:        "{traces}",
+				Data: metricdata.Sum[int64]{
+					IsMonotonic: true,
+					Temporality: metricdata.CumulativeTemporality,
+					DataPoints: []metricdata.DataPoint[int64]{
+						{
+							Attributes: attribute.NewSet(
+								attribute.String("decision", "sampled"),
+								attribute.String("policy", "always"),
+							),
+							Value: 1,
+						},
+					},
+				},
+			},
+		},
 		{
 			opts: []metricdatatest.Option{metricdatatest.IgnoreTimestamp()},
 			m: metricdata.Metrics{
@@ -260,7 +281,7 @@ func TestProcessorTailSamplingCountSpansSampled(t *testing.T) {
 	// verify
 	var md metricdata.ResourceMetrics
 	require.NoError(t, s.reader.Collect(context.Background(), &md))
-	require.Equal(t, 9, s.len(md))
+	require.Equal(t, 10, s.len(md))
 
 	m := metricdata.Metrics{
 		Name:        "processor_tail_sampling_count_spans_sampled",
@@ -12,6 +12,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/logs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/metrics"
@@ -36,11 +37,21 @@ type Config struct {
 	// The default value is `propagate`.
 	ErrorMode ottl.ErrorMode `mapstructure:"error_mode"`
 
+	// Macros are named groups of statements with parameters which can be invoked from any statement like a function.
+	Macros []ottl.Macro `mapstructure:"macros"`
+
+	// LookupTables are the tables available to the Lookup converter, loaded from local files.
+	LookupTables []ottlfuncs.LookupTableConfig `mapstructure:"lookup_tables"`
+
 	TraceStatements  []common.ContextStatements `mapstructure:"trace_statements"`
 	MetricStatements []common.ContextStatements `mapstructure:"metric_statements"`
 	LogStatements    []common.ContextStatements `mapstructure:"log_statements"`
 
 	FlattenData bool `mapstructure:"flatten_data"`
+
+	// Explain enables the explain mode of the statements, which records the evaluation of every statement for a sample
+	// of the records to help troubleshooting the configuration.
+	Explain ottl.ExplainConfig `mapstructure:"explain"`
 }
 
 var _ component.Config = (*Config)(nil)
@@ -48,8 +59,18 @@ var _ component.Config = (*Config)(nil)
 func (c *Config) Validate() error {
 	var errors error
 
+	if err := ottl.ValidateMacros(c.Macros); err != nil {
+		return err
+	}
+
+	// The files of the tables are only read when the processor is created.
+	lookupTables, err := newLookupTables(c.LookupTables, zap.NewNop())
+	if err != nil {
+		return err
+	}
+
 	if len(c.TraceStatements) > 0 {
-		pc, err := common.NewTraceParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithSpanParser(traces.SpanFunctions()), common.WithSpanEventParser(traces.SpanEventFunctions()))
+		pc, err := common.NewTraceParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithTraceLookupTables(lookupTables), common.WithSpanParser(traces.SpanFunctions()), common.WithSpanEventParser(traces.SpanEventFunctions()), common.WithSpanLinkParser(traces.SpanLinkFunctions()), common.WithTraceMacros(c.Macros))
 		if err != nil {
 			return err
 		}
@@ -62,7 +83,7 @@ func (c *Config) Validate() error {
 	}
 
 	if len(c.MetricStatements) > 0 {
-		pc, err := common.NewMetricParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithMetricParser(metrics.MetricFunctions()), common.WithDataPointParser(metrics.DataPointFunctions()))
+		pc, err := common.NewMetricParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithMetricLookupTables(lookupTables), common.WithMetricParser(metrics.MetricFunctions()), common.WithDataPointParser(metrics.DataPointFunctions()), common.WithMetricMacros(c.Macros))
 		if err != nil {
 			return err
 		}
@@ -75,7 +96,7 @@ func (c *Config) Validate() error {
 	}
 
 	if len(c.LogStatements) > 0 {
-		pc, err := common.NewLogParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithLogParser(logs.LogFunctions()))
+		pc, err := common.NewLogParserCollection(component.TelemetrySettings{Logger: zap.NewNop()}, common.WithLogLookupTables(lookupTables), common.WithLogParser(logs.LogFunctions()), common.WithLogMacros(c.Macros))
 		if err != nil {
 			return err
 		}
@@ -93,3 +114,10 @@ func (c *Config) Validate() error {
 
 	return errors
 }
+
+func newLookupTables(configs []ottlfuncs.LookupTableConfig, logger *zap.Logger) (*ottlfuncs.LookupTables, error) {
+	if len(configs) == 0 {
+		return nil, nil
+	}
+	return ottlfuncs.NewLookupTables(configs, logger)
+}
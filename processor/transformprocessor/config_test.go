@@ -6,6 +6,7 @@ package transformprocessor
 import (
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/component"
@@ -13,6 +14,7 @@ import (
 	"go.uber.org/multierr"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/metadata"
 )
@@ -125,6 +127,106 @@ func TestLoadConfig(t *testing.T) {
 				LogStatements:    []common.ContextStatements{},
 			},
 		},
+		{
+			id: component.NewIDWithName(metadata.Type, "with_macros"),
+			expected: &Config{
+				ErrorMode: ottl.PropagateError,
+				Macros: []ottl.Macro{
+					{
+						Name: "normalize_http",
+						Parameters: []ottl.MacroParameter{
+							{Name: "target", Type: ottl.MacroParameterTypePath},
+							{Name: "method", Type: ottl.MacroParameterTypeString},
+						},
+						Statements: []string{
+							`set(target["http.request.method"], method)`,
+							`delete_key(target, "http.method")`,
+						},
+					},
+				},
+				TraceStatements: []common.ContextStatements{
+					{
+						Context: "span",
+						Statements: []string{
+							`normalize_http(attributes, "GET") where attributes["http.method"] == "GET"`,
+						},
+					},
+				},
+				MetricStatements: []common.ContextStatements{},
+				LogStatements: []common.ContextStatements{
+					{
+						Context: "resource",
+						Statements: []string{
+							`normalize_http(target=attributes, method="POST")`,
+						},
+					},
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "macro_cycle"),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "bad_macro_argument"),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "with_lookup_tables"),
+			expected: &Config{
+				ErrorMode: ottl.PropagateError,
+				LookupTables: []ottlfuncs.LookupTableConfig{
+					{
+						Name: "teams",
+						Path: "testdata/lookup/teams.csv",
+					},
+					{
+						Name:           "sites",
+						Path:           "testdata/lookup/sites.json",
+						Match:          ottlfuncs.LookupMatchCIDR,
+						ReloadInterval: time.Minute,
+					},
+				},
+				TraceStatements: []common.ContextStatements{
+					{
+						Context: "span",
+						Statements: []string{
+							`set(attributes["team"], Lookup("teams", attributes["account.id"], "unknown"))`,
+							`set(attributes["site"], Lookup("sites", attributes["client.address"]))`,
+						},
+					},
+				},
+				MetricStatements: []common.ContextStatements{},
+				LogStatements:    []common.ContextStatements{},
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "unknown_lookup_table"),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "bad_lookup_table"),
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "with_explain"),
+			expected: &Config{
+				ErrorMode: ottl.PropagateError,
+				Explain: ottl.ExplainConfig{
+					SamplingRatio: 0.1,
+					Output:        ottl.ExplainOutputSpanEvent,
+				},
+				TraceStatements:  []common.ContextStatements{},
+				MetricStatements: []common.ContextStatements{},
+				LogStatements: []common.ContextStatements{
+					{
+						Context: "log",
+						Statements: []string{
+							`set(body, "bear")`,
+						},
+					},
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "bad_explain"),
+		},
 		{
 			id: component.NewIDWithName(metadata.Type, "bad_syntax_trace"),
 		},
@@ -11,8 +11,10 @@ import (
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/processor"
 	"go.opentelemetry.io/collector/processor/processorhelper"
+	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/logs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/metadata"
@@ -49,7 +51,11 @@ func createLogsProcessor(
 ) (processor.Logs, error) {
 	oCfg := cfg.(*Config)
 
-	proc, err := logs.NewProcessor(oCfg.LogStatements, oCfg.ErrorMode, oCfg.FlattenData, set.TelemetrySettings)
+	lookupTables, err := loadLookupTables(oCfg, set.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
+	}
+	proc, err := logs.NewProcessor(oCfg.LogStatements, oCfg.ErrorMode, oCfg.Macros, lookupTables, oCfg.Explain, oCfg.FlattenData, set.TelemetrySettings)
 	if err != nil {
 		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
 	}
@@ -59,7 +65,9 @@ func createLogsProcessor(
 		cfg,
 		nextConsumer,
 		proc.ProcessLogs,
-		processorhelper.WithCapabilities(processorCapabilities))
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(startLookupTables(lookupTables)),
+		processorhelper.WithShutdown(shutdownLookupTables(lookupTables)))
 }
 
 func createTracesProcessor(
@@ -70,7 +78,11 @@ func createTracesProcessor(
 ) (processor.Traces, error) {
 	oCfg := cfg.(*Config)
 
-	proc, err := traces.NewProcessor(oCfg.TraceStatements, oCfg.ErrorMode, set.TelemetrySettings)
+	lookupTables, err := loadLookupTables(oCfg, set.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
+	}
+	proc, err := traces.NewProcessor(oCfg.TraceStatements, oCfg.ErrorMode, oCfg.Macros, lookupTables, oCfg.Explain, set.TelemetrySettings)
 	if err != nil {
 		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
 	}
@@ -80,7 +92,9 @@ func createTracesProcessor(
 		cfg,
 		nextConsumer,
 		proc.ProcessTraces,
-		processorhelper.WithCapabilities(processorCapabilities))
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(startLookupTables(lookupTables)),
+		processorhelper.WithShutdown(shutdownLookupTables(lookupTables)))
 }
 
 func createMetricsProcessor(
@@ -91,7 +105,11 @@ func createMetricsProcessor(
 ) (processor.Metrics, error) {
 	oCfg := cfg.(*Config)
 
-	proc, err := metrics.NewProcessor(oCfg.MetricStatements, oCfg.ErrorMode, set.TelemetrySettings)
+	lookupTables, err := loadLookupTables(oCfg, set.Logger)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
+	}
+	proc, err := metrics.NewProcessor(oCfg.MetricStatements, oCfg.ErrorMode, oCfg.Macros, lookupTables, oCfg.Explain, set.TelemetrySettings)
 	if err != nil {
 		return nil, fmt.Errorf("invalid config for \"transform\" processor %w", err)
 	}
@@ -101,5 +119,31 @@ func createMetricsProcessor(
 		cfg,
 		nextConsumer,
 		proc.ProcessMetrics,
-		processorhelper.WithCapabilities(processorCapabilities))
+		processorhelper.WithCapabilities(processorCapabilities),
+		processorhelper.WithStart(startLookupTables(lookupTables)),
+		processorhelper.WithShutdown(shutdownLookupTables(lookupTables)))
+}
+
+// loadLookupTables reads the files of the lookup tables of the configuration.
+func loadLookupTables(cfg *Config, logger *zap.Logger) (*ottlfuncs.LookupTables, error) {
+	tables, err := newLookupTables(cfg.LookupTables, logger)
+	if err != nil || tables == nil {
+		return nil, err
+	}
+	return tables, tables.Load()
+}
+
+// startLookupTables reloads the lookup tables in the background while the processor is running.
+func startLookupTables(tables *ottlfuncs.LookupTables) component.StartFunc {
+	return func(context.Context, component.Host) error {
+		tables.Start()
+		return nil
+	}
+}
+
+func shutdownLookupTables(tables *ottlfuncs.LookupTables) component.ShutdownFunc {
+	return func(context.Context) error {
+		tables.Shutdown()
+		return nil
+	}
 }
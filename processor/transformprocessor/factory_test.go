@@ -5,9 +5,11 @@ package transformprocessor
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/consumer/consumertest"
@@ -17,6 +19,7 @@ import (
 	"go.opentelemetry.io/collector/processor/processortest"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/metadata"
 )
@@ -93,6 +96,55 @@ func TestFactoryCreateTracesProcessor(t *testing.T) {
 	assert.Equal(t, "pass", val.Str())
 }
 
+func TestFactoryCreateTracesProcessor_LookupTables(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	oCfg := cfg.(*Config)
+	oCfg.LookupTables = []ottlfuncs.LookupTableConfig{
+		{Name: "teams", Path: filepath.Join("testdata", "lookup", "teams.csv")},
+		{Name: "sites", Path: filepath.Join("testdata", "lookup", "sites.json"), Match: ottlfuncs.LookupMatchCIDR},
+	}
+	oCfg.TraceStatements = []common.ContextStatements{
+		{
+			Context: "span",
+			Statements: []string{
+				`set(attributes["team"], Lookup("teams", attributes["account.id"], "unknown"))`,
+				`set(attributes["site"], Lookup("sites", attributes["client.address"]))`,
+			},
+		},
+	}
+	tp, err := factory.CreateTracesProcessor(context.Background(), processortest.NewNopSettings(), cfg, consumertest.NewNop())
+	assert.NoError(t, err)
+	require.NoError(t, tp.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() { require.NoError(t, tp.Shutdown(context.Background())) }()
+
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	known := spans.AppendEmpty()
+	known.Attributes().PutInt("account.id", 1234)
+	known.Attributes().PutStr("client.address", "10.1.2.3")
+	unknown := spans.AppendEmpty()
+	unknown.Attributes().PutStr("account.id", "0000")
+	unknown.Attributes().PutStr("client.address", "192.168.0.1")
+
+	assert.NoError(t, tp.ConsumeTraces(context.Background(), td))
+
+	assert.Equal(t, map[string]any{"account.id": int64(1234), "client.address": "10.1.2.3", "team": "payments", "site": "dc1"}, known.Attributes().AsRaw())
+	assert.Equal(t, map[string]any{"account.id": "0000", "client.address": "192.168.0.1", "team": "unknown"}, unknown.Attributes().AsRaw())
+}
+
+func TestFactoryCreateTracesProcessor_MissingLookupTable(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig()
+	oCfg := cfg.(*Config)
+	oCfg.LookupTables = []ottlfuncs.LookupTableConfig{
+		{Name: "teams", Path: filepath.Join("testdata", "lookup", "missing.csv")},
+	}
+	tp, err := factory.CreateTracesProcessor(context.Background(), processortest.NewNopSettings(), cfg, consumertest.NewNop())
+	assert.ErrorContains(t, err, `failed to load lookup table "teams"`)
+	assert.Nil(t, tp)
+}
+
 func TestFactoryCreateMetricsProcessor_InvalidActions(t *testing.T) {
 	factory := NewFactory()
 	cfg := factory.CreateDefaultConfig()
@@ -4,6 +4,8 @@
 package common // import "github.com/open-telemetry/opentelemetry-collector-contrib/processor/transformprocessor/internal/common"
 
 import (
+	"maps"
+
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlresource"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlscope"
@@ -17,3 +19,14 @@ func ResourceFunctions() map[string]ottl.Factory[ottlresource.TransformContext]
 func ScopeFunctions() map[string]ottl.Factory[ottlscope.TransformContext] {
 	return ottlfuncs.StandardFuncs[ottlscope.TransformContext]()
 }
+
+// withLookupFunction adds the Lookup converter backed by the given tables to the functions.
+func withLookupFunction[K any](functions map[string]ottl.Factory[K], tables *ottlfuncs.LookupTables) map[string]ottl.Factory[K] {
+	if tables == nil {
+		return functions
+	}
+	lookup := ottlfuncs.NewLookupFactory[K](tables)
+	functions = maps.Clone(functions)
+	functions[lookup.Name()] = lookup
+	return functions
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottllog"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlresource"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlscope"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 )
 
 var _ consumer.Logs = &logStatements{}
@@ -64,11 +65,14 @@ type LogParserCollectionOption func(*LogParserCollection) error
 
 func WithLogParser(functions map[string]ottl.Factory[ottllog.TransformContext]) LogParserCollectionOption {
 	return func(lp *LogParserCollection) error {
-		logParser, err := ottllog.NewParser(functions, lp.settings)
-		if err != nil {
-			return err
-		}
-		lp.logParser = logParser
+		lp.newParsers = append(lp.newParsers, func() error {
+			logParser, err := ottllog.NewParser(withLookupFunction(functions, lp.lookupTables), lp.settings)
+			if err != nil {
+				return err
+			}
+			lp.logParser = logParser
+			return nil
+		})
 		return nil
 	}
 }
@@ -80,20 +84,34 @@ func WithLogErrorMode(errorMode ottl.ErrorMode) LogParserCollectionOption {
 	}
 }
 
-func NewLogParserCollection(settings component.TelemetrySettings, options ...LogParserCollectionOption) (*LogParserCollection, error) {
-	rp, err := ottlresource.NewParser(ResourceFunctions(), settings)
-	if err != nil {
-		return nil, err
+// WithLogLookupTables makes the Lookup converter backed by the given tables available to every log context.
+func WithLogLookupTables(tables *ottlfuncs.LookupTables) LogParserCollectionOption {
+	return func(lp *LogParserCollection) error {
+		lp.lookupTables = tables
+		return nil
 	}
-	sp, err := ottlscope.NewParser(ScopeFunctions(), settings)
-	if err != nil {
-		return nil, err
+}
+
+// WithLogExplain enables the explain mode of the statements of every log context.
+func WithLogExplain(config ottl.ExplainConfig) LogParserCollectionOption {
+	return func(lp *LogParserCollection) error {
+		lp.explain = config
+		return nil
+	}
+}
+
+// WithLogMacros makes the given macros available to the statements of every log context.
+func WithLogMacros(macros []ottl.Macro) LogParserCollectionOption {
+	return func(lp *LogParserCollection) error {
+		lp.macros = macros
+		return nil
 	}
+}
+
+func NewLogParserCollection(settings component.TelemetrySettings, options ...LogParserCollectionOption) (*LogParserCollection, error) {
 	lpc := &LogParserCollection{
 		parserCollection: parserCollection{
-			settings:       settings,
-			resourceParser: rp,
-			scopeParser:    sp,
+			settings: settings,
 		},
 	}
 
@@ -103,6 +121,24 @@ func NewLogParserCollection(settings component.TelemetrySettings, options ...Log
 			return nil, err
 		}
 	}
+	if err := lpc.buildParsers(); err != nil {
+		return nil, err
+	}
+
+	rp, err := ottlresource.NewParser(withLookupFunction(ResourceFunctions(), lpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	sp, err := ottlscope.NewParser(withLookupFunction(ScopeFunctions(), lpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	lpc.resourceParser = rp
+	lpc.scopeParser = sp
+
+	applyMacros(&lpc.resourceParser, lpc.macros)
+	applyMacros(&lpc.scopeParser, lpc.macros)
+	applyMacros(&lpc.logParser, lpc.macros)
 
 	return lpc, nil
 }
@@ -118,7 +154,7 @@ func (pc LogParserCollection) ParseContextStatements(contextStatements ContextSt
 		if errGlobalBoolExpr != nil {
 			return nil, errGlobalBoolExpr
 		}
-		lStatements := ottllog.NewStatementSequence(parsedStatements, pc.settings, ottllog.WithStatementSequenceErrorMode(pc.errorMode))
+		lStatements := ottllog.NewStatementSequence(parsedStatements, pc.settings, ottllog.WithStatementSequenceErrorMode(pc.errorMode), ottllog.WithStatementSequenceExplain(pc.explain))
 		return logStatements{lStatements, globalExpr}, nil
 	default:
 		statements, err := pc.parseCommonContextStatements(contextStatements)
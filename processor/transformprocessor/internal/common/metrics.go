@@ -18,6 +18,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlmetric"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlresource"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlscope"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 )
 
 var _ consumer.Metrics = &metricStatements{}
@@ -179,22 +180,28 @@ type MetricParserCollectionOption func(*MetricParserCollection) error
 
 func WithMetricParser(functions map[string]ottl.Factory[ottlmetric.TransformContext]) MetricParserCollectionOption {
 	return func(mp *MetricParserCollection) error {
-		metricParser, err := ottlmetric.NewParser(functions, mp.settings)
-		if err != nil {
-			return err
-		}
-		mp.metricParser = metricParser
+		mp.newParsers = append(mp.newParsers, func() error {
+			metricParser, err := ottlmetric.NewParser(withLookupFunction(functions, mp.lookupTables), mp.settings)
+			if err != nil {
+				return err
+			}
+			mp.metricParser = metricParser
+			return nil
+		})
 		return nil
 	}
 }
 
 func WithDataPointParser(functions map[string]ottl.Factory[ottldatapoint.TransformContext]) MetricParserCollectionOption {
 	return func(mp *MetricParserCollection) error {
-		dataPointParser, err := ottldatapoint.NewParser(functions, mp.settings)
-		if err != nil {
-			return err
-		}
-		mp.dataPointParser = dataPointParser
+		mp.newParsers = append(mp.newParsers, func() error {
+			dataPointParser, err := ottldatapoint.NewParser(withLookupFunction(functions, mp.lookupTables), mp.settings)
+			if err != nil {
+				return err
+			}
+			mp.dataPointParser = dataPointParser
+			return nil
+		})
 		return nil
 	}
 }
@@ -206,20 +213,34 @@ func WithMetricErrorMode(errorMode ottl.ErrorMode) MetricParserCollectionOption
 	}
 }
 
-func NewMetricParserCollection(settings component.TelemetrySettings, options ...MetricParserCollectionOption) (*MetricParserCollection, error) {
-	rp, err := ottlresource.NewParser(ResourceFunctions(), settings)
-	if err != nil {
-		return nil, err
+// WithMetricLookupTables makes the Lookup converter backed by the given tables available to every metric context.
+func WithMetricLookupTables(tables *ottlfuncs.LookupTables) MetricParserCollectionOption {
+	return func(mp *MetricParserCollection) error {
+		mp.lookupTables = tables
+		return nil
 	}
-	sp, err := ottlscope.NewParser(ScopeFunctions(), settings)
-	if err != nil {
-		return nil, err
+}
+
+// WithMetricExplain enables the explain mode of the statements of every metric context.
+func WithMetricExplain(config ottl.ExplainConfig) MetricParserCollectionOption {
+	return func(mp *MetricParserCollection) error {
+		mp.explain = config
+		return nil
+	}
+}
+
+// WithMetricMacros makes the given macros available to the statements of every metric context.
+func WithMetricMacros(macros []ottl.Macro) MetricParserCollectionOption {
+	return func(mp *MetricParserCollection) error {
+		mp.macros = macros
+		return nil
 	}
+}
+
+func NewMetricParserCollection(settings component.TelemetrySettings, options ...MetricParserCollectionOption) (*MetricParserCollection, error) {
 	mpc := &MetricParserCollection{
 		parserCollection: parserCollection{
-			settings:       settings,
-			resourceParser: rp,
-			scopeParser:    sp,
+			settings: settings,
 		},
 	}
 
@@ -229,6 +250,25 @@ func NewMetricParserCollection(settings component.TelemetrySettings, options ...
 			return nil, err
 		}
 	}
+	if err := mpc.buildParsers(); err != nil {
+		return nil, err
+	}
+
+	rp, err := ottlresource.NewParser(withLookupFunction(ResourceFunctions(), mpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	sp, err := ottlscope.NewParser(withLookupFunction(ScopeFunctions(), mpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	mpc.resourceParser = rp
+	mpc.scopeParser = sp
+
+	applyMacros(&mpc.resourceParser, mpc.macros)
+	applyMacros(&mpc.scopeParser, mpc.macros)
+	applyMacros(&mpc.metricParser, mpc.macros)
+	applyMacros(&mpc.dataPointParser, mpc.macros)
 
 	return mpc, nil
 }
@@ -244,7 +284,7 @@ func (pc MetricParserCollection) ParseContextStatements(contextStatements Contex
 		if errGlobalBoolExpr != nil {
 			return nil, errGlobalBoolExpr
 		}
-		mStatements := ottlmetric.NewStatementSequence(parseStatements, pc.settings, ottlmetric.WithStatementSequenceErrorMode(pc.errorMode))
+		mStatements := ottlmetric.NewStatementSequence(parseStatements, pc.settings, ottlmetric.WithStatementSequenceErrorMode(pc.errorMode), ottlmetric.WithStatementSequenceExplain(pc.explain))
 		return metricStatements{mStatements, globalExpr}, nil
 	case DataPoint:
 		parsedStatements, err := pc.dataPointParser.ParseStatements(contextStatements.Statements)
@@ -255,7 +295,7 @@ func (pc MetricParserCollection) ParseContextStatements(contextStatements Contex
 		if errGlobalBoolExpr != nil {
 			return nil, errGlobalBoolExpr
 		}
-		dpStatements := ottldatapoint.NewStatementSequence(parsedStatements, pc.settings, ottldatapoint.WithStatementSequenceErrorMode(pc.errorMode))
+		dpStatements := ottldatapoint.NewStatementSequence(parsedStatements, pc.settings, ottldatapoint.WithStatementSequenceErrorMode(pc.errorMode), ottldatapoint.WithStatementSequenceExplain(pc.explain))
 		return dataPointStatements{dpStatements, globalExpr}, nil
 	default:
 		statements, err := pc.parseCommonContextStatements(contextStatements)
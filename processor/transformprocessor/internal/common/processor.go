@@ -18,6 +18,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlresource"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlscope"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 )
 
 var _ consumer.Traces = &resourceStatements{}
@@ -174,6 +175,22 @@ type parserCollection struct {
 	resourceParser ottl.Parser[ottlresource.TransformContext]
 	scopeParser    ottl.Parser[ottlscope.TransformContext]
 	errorMode      ottl.ErrorMode
+	macros         []ottl.Macro
+	lookupTables   *ottlfuncs.LookupTables
+	explain        ottl.ExplainConfig
+	// newParsers create the parsers of the options once every option is applied, so that they get the
+	// lookup tables whatever the order of the options.
+	newParsers []func() error
+}
+
+// buildParsers creates the parsers of the options.
+func (pc *parserCollection) buildParsers() error {
+	for _, newParser := range pc.newParsers {
+		if err := newParser(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type baseContext interface {
@@ -193,7 +210,7 @@ func (pc parserCollection) parseCommonContextStatements(contextStatement Context
 		if errGlobalBoolExpr != nil {
 			return nil, errGlobalBoolExpr
 		}
-		rStatements := ottlresource.NewStatementSequence(parsedStatements, pc.settings, ottlresource.WithStatementSequenceErrorMode(pc.errorMode))
+		rStatements := ottlresource.NewStatementSequence(parsedStatements, pc.settings, ottlresource.WithStatementSequenceErrorMode(pc.errorMode), ottlresource.WithStatementSequenceExplain(pc.explain))
 		return resourceStatements{rStatements, globalExpr}, nil
 	case Scope:
 		parsedStatements, err := pc.scopeParser.ParseStatements(contextStatement.Statements)
@@ -204,13 +221,20 @@ func (pc parserCollection) parseCommonContextStatements(contextStatement Context
 		if errGlobalBoolExpr != nil {
 			return nil, errGlobalBoolExpr
 		}
-		sStatements := ottlscope.NewStatementSequence(parsedStatements, pc.settings, ottlscope.WithStatementSequenceErrorMode(pc.errorMode))
+		sStatements := ottlscope.NewStatementSequence(parsedStatements, pc.settings, ottlscope.WithStatementSequenceErrorMode(pc.errorMode), ottlscope.WithStatementSequenceExplain(pc.explain))
 		return scopeStatements{sStatements, globalExpr}, nil
 	default:
 		return nil, fmt.Errorf("unknown context %v", contextStatement.Context)
 	}
 }
 
+// applyMacros makes the macros of the collection available to the given parser.
+func applyMacros[K any](parser *ottl.Parser[K], macros []ottl.Macro) {
+	if len(macros) > 0 {
+		ottl.WithMacros[K](macros)(parser)
+	}
+}
+
 func parseGlobalExpr[K any](
 	boolExprFunc func([]string, map[string]ottl.Factory[K], ottl.ErrorMode, component.TelemetrySettings) (expr.BoolExpr[K], error),
 	conditions []string,
@@ -218,7 +242,7 @@ func parseGlobalExpr[K any](
 	standardFuncs map[string]ottl.Factory[K]) (expr.BoolExpr[K], error) {
 
 	if len(conditions) > 0 {
-		return boolExprFunc(conditions, standardFuncs, pc.errorMode, pc.settings)
+		return boolExprFunc(conditions, withLookupFunction(standardFuncs, pc.lookupTables), pc.errorMode, pc.settings)
 	}
 	// By default, set the global expression to always true unless conditions are specified.
 	return expr.AlwaysTrue[K](), nil
@@ -17,6 +17,8 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlscope"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspanevent"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspanlink"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 )
 
 var _ consumer.Traces = &traceStatements{}
@@ -97,32 +99,94 @@ func (s spanEventStatements) ConsumeTraces(ctx context.Context, td ptrace.Traces
 	return nil
 }
 
+var _ consumer.Traces = &spanLinkStatements{}
+
+type spanLinkStatements struct {
+	ottl.StatementSequence[ottlspanlink.TransformContext]
+	expr.BoolExpr[ottlspanlink.TransformContext]
+}
+
+func (s spanLinkStatements) Capabilities() consumer.Capabilities {
+	return consumer.Capabilities{
+		MutatesData: true,
+	}
+}
+
+func (s spanLinkStatements) ConsumeTraces(ctx context.Context, td ptrace.Traces) error {
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rspans := td.ResourceSpans().At(i)
+		for j := 0; j < rspans.ScopeSpans().Len(); j++ {
+			sspans := rspans.ScopeSpans().At(j)
+			spans := sspans.Spans()
+			for k := 0; k < spans.Len(); k++ {
+				span := spans.At(k)
+				spanLinks := span.Links()
+				for n := 0; n < spanLinks.Len(); n++ {
+					tCtx := ottlspanlink.NewTransformContext(spanLinks.At(n), span, sspans.Scope(), rspans.Resource())
+					condition, err := s.BoolExpr.Eval(ctx, tCtx)
+					if err != nil {
+						return err
+					}
+					if condition {
+						err := s.Execute(ctx, tCtx)
+						if err != nil {
+							return err
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
 type TraceParserCollection struct {
 	parserCollection
 	spanParser      ottl.Parser[ottlspan.TransformContext]
 	spanEventParser ottl.Parser[ottlspanevent.TransformContext]
+	spanLinkParser  ottl.Parser[ottlspanlink.TransformContext]
 }
 
 type TraceParserCollectionOption func(*TraceParserCollection) error
 
 func WithSpanParser(functions map[string]ottl.Factory[ottlspan.TransformContext]) TraceParserCollectionOption {
 	return func(tp *TraceParserCollection) error {
-		spanParser, err := ottlspan.NewParser(functions, tp.settings)
-		if err != nil {
-			return err
-		}
-		tp.spanParser = spanParser
+		tp.newParsers = append(tp.newParsers, func() error {
+			spanParser, err := ottlspan.NewParser(withLookupFunction(functions, tp.lookupTables), tp.settings)
+			if err != nil {
+				return err
+			}
+			tp.spanParser = spanParser
+			return nil
+		})
 		return nil
 	}
 }
 
 func WithSpanEventParser(functions map[string]ottl.Factory[ottlspanevent.TransformContext]) TraceParserCollectionOption {
 	return func(tp *TraceParserCollection) error {
-		spanEventParser, err := ottlspanevent.NewParser(functions, tp.settings)
-		if err != nil {
-			return err
-		}
-		tp.spanEventParser = spanEventParser
+		tp.newParsers = append(tp.newParsers, func() error {
+			spanEventParser, err := ottlspanevent.NewParser(withLookupFunction(functions, tp.lookupTables), tp.settings)
+			if err != nil {
+				return err
+			}
+			tp.spanEventParser = spanEventParser
+			return nil
+		})
+		return nil
+	}
+}
+
+func WithSpanLinkParser(functions map[string]ottl.Factory[ottlspanlink.TransformContext]) TraceParserCollectionOption {
+	return func(tp *TraceParserCollection) error {
+		tp.newParsers = append(tp.newParsers, func() error {
+			spanLinkParser, err := ottlspanlink.NewParser(withLookupFunction(functions, tp.lookupTables), tp.settings)
+			if err != nil {
+				return err
+			}
+			tp.spanLinkParser = spanLinkParser
+			return nil
+		})
 		return nil
 	}
 }
@@ -134,20 +198,34 @@ func WithTraceErrorMode(errorMode ottl.ErrorMode) TraceParserCollectionOption {
 	}
 }
 
-func NewTraceParserCollection(settings component.TelemetrySettings, options ...TraceParserCollectionOption) (*TraceParserCollection, error) {
-	rp, err := ottlresource.NewParser(ResourceFunctions(), settings)
-	if err != nil {
-		return nil, err
+// WithTraceLookupTables makes the Lookup converter backed by the given tables available to every trace context.
+func WithTraceLookupTables(tables *ottlfuncs.LookupTables) TraceParserCollectionOption {
+	return func(tp *TraceParserCollection) error {
+		tp.lookupTables = tables
+		return nil
 	}
-	sp, err := ottlscope.NewParser(ScopeFunctions(), settings)
-	if err != nil {
-		return nil, err
+}
+
+// WithTraceExplain enables the explain mode of the statements of every trace context.
+func WithTraceExplain(config ottl.ExplainConfig) TraceParserCollectionOption {
+	return func(tp *TraceParserCollection) error {
+		tp.explain = config
+		return nil
+	}
+}
+
+// WithTraceMacros makes the given macros available to the statements of every trace context.
+func WithTraceMacros(macros []ottl.Macro) TraceParserCollectionOption {
+	return func(tp *TraceParserCollection) error {
+		tp.macros = macros
+		return nil
 	}
+}
+
+func NewTraceParserCollection(settings component.TelemetrySettings, options ...TraceParserCollectionOption) (*TraceParserCollection, error) {
 	tpc := &TraceParserCollection{
 		parserCollection: parserCollection{
-			settings:       settings,
-			resourceParser: rp,
-			scopeParser:    sp,
+			settings: settings,
 		},
 	}
 
@@ -157,6 +235,26 @@ func NewTraceParserCollection(settings component.TelemetrySettings, options ...T
 			return nil, err
 		}
 	}
+	if err := tpc.buildParsers(); err != nil {
+		return nil, err
+	}
+
+	rp, err := ottlresource.NewParser(withLookupFunction(ResourceFunctions(), tpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	sp, err := ottlscope.NewParser(withLookupFunction(ScopeFunctions(), tpc.lookupTables), settings)
+	if err != nil {
+		return nil, err
+	}
+	tpc.resourceParser = rp
+	tpc.scopeParser = sp
+
+	applyMacros(&tpc.resourceParser, tpc.macros)
+	applyMacros(&tpc.scopeParser, tpc.macros)
+	applyMacros(&tpc.spanParser, tpc.macros)
+	applyMacros(&tpc.spanEventParser, tpc.macros)
+	applyMacros(&tpc.spanLinkParser, tpc.macros)
 
 	return tpc, nil
 }
@@ -172,7 +270,7 @@ func (pc TraceParserCollection) ParseContextStatements(contextStatements Context
 		if errGlobalBoolExpr != nil {
 			return nil, errGlobalBoolExpr
 		}
-		sStatements := ottlspan.NewStatementSequence(parsedStatements, pc.settings, ottlspan.WithStatementSequenceErrorMode(pc.errorMode))
+		sStatements := ottlspan.NewStatementSequence(parsedStatements, pc.settings, ottlspan.WithStatementSequenceErrorMode(pc.errorMode), ottlspan.WithStatementSequenceExplain(pc.explain))
 		return traceStatements{sStatements, globalExpr}, nil
 	case SpanEvent:
 		parsedStatements, err := pc.spanEventParser.ParseStatements(contextStatements.Statements)
@@ -183,8 +281,19 @@ func (pc TraceParserCollection) ParseContextStatements(contextStatements Context
 		if errGlobalBoolExpr != nil {
 			return nil, errGlobalBoolExpr
 		}
-		seStatements := ottlspanevent.NewStatementSequence(parsedStatements, pc.settings, ottlspanevent.WithStatementSequenceErrorMode(pc.errorMode))
+		seStatements := ottlspanevent.NewStatementSequence(parsedStatements, pc.settings, ottlspanevent.WithStatementSequenceErrorMode(pc.errorMode), ottlspanevent.WithStatementSequenceExplain(pc.explain))
 		return spanEventStatements{seStatements, globalExpr}, nil
+	case SpanLink:
+		parsedStatements, err := pc.spanLinkParser.ParseStatements(contextStatements.Statements)
+		if err != nil {
+			return nil, err
+		}
+		globalExpr, errGlobalBoolExpr := parseGlobalExpr(filterottl.NewBoolExprForSpanLink, contextStatements.Conditions, pc.parserCollection, filterottl.StandardSpanLinkFuncs())
+		if errGlobalBoolExpr != nil {
+			return nil, errGlobalBoolExpr
+		}
+		slStatements := ottlspanlink.NewStatementSequence(parsedStatements, pc.settings, ottlspanlink.WithStatementSequenceErrorMode(pc.errorMode), ottlspanlink.WithStatementSequenceExplain(pc.explain))
+		return spanLinkStatements{slStatements, globalExpr}, nil
 	default:
 		return pc.parseCommonContextStatements(contextStatements)
 	}
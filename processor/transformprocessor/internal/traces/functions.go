@@ -7,6 +7,7 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspan"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspanevent"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/contexts/ottlspanlink"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/ottl/ottlfuncs"
 )
 
@@ -19,3 +20,8 @@ func SpanEventFunctions() map[string]ottl.Factory[ottlspanevent.TransformContext
 	// No trace-only functions yet.
 	return ottlfuncs.StandardFuncs[ottlspanevent.TransformContext]()
 }
+
+func SpanLinkFunctions() map[string]ottl.Factory[ottlspanlink.TransformContext] {
+	// No trace-only functions yet.
+	return ottlfuncs.StandardFuncs[ottlspanlink.TransformContext]()
+}
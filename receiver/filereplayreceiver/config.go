@@ -0,0 +1,86 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver"
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+)
+
+const (
+	formatTypeJSON  = "json"
+	formatTypeProto = "proto"
+	compressionZSTD = "zstd"
+)
+
+var (
+	errMissingPath           = errors.New("path must be non-empty")
+	errUnsupportedFormat     = errors.New("format type is not supported")
+	errUnsupportedCompressor = errors.New("compression is not supported")
+	errNegativeSpeed         = errors.New("speed must not be negative")
+	errNegativeMaxDelay      = errors.New("max_delay must not be negative")
+)
+
+// Config defines configuration for the file replay receiver. The format, encoding and compression
+// settings must match the ones of the file exporter which wrote the files.
+type Config struct {
+	// Path of the files to replay, which is the path the file exporter writes to. It may be a glob
+	// pattern, such as the path of the file exporter with group_by enabled. The rotated backups
+	// of the files are replayed before the files.
+	Path string `mapstructure:"path"`
+
+	// FormatType define the data format of encoded telemetry data
+	// Options:
+	// - json[default]:  OTLP json bytes.
+	// - proto:  OTLP binary protobuf bytes.
+	FormatType string `mapstructure:"format"`
+
+	// Encoding defines the encoding of the telemetry data.
+	// If specified, it overrides `FormatType` and applies an encoding extension.
+	Encoding *component.ID `mapstructure:"encoding"`
+
+	// Compression Codec used to export telemetry data
+	// Supported compression algorithms:`zstd`
+	Compression string `mapstructure:"compression"`
+
+	// Speed is the pace of the replay relative to the timestamps of the telemetry: 1 replays the
+	// telemetry at its original pace, 10 replays it ten times faster, and 0 as fast as possible.
+	Speed float64 `mapstructure:"speed"`
+
+	// MaxDelay caps the time waited between two messages, to skip the gaps in the recorded telemetry.
+	// The default is not to cap the delay.
+	MaxDelay time.Duration `mapstructure:"max_delay"`
+
+	// ShiftTimestamps moves the timestamps of the telemetry to the time it's replayed at, as if
+	// it was produced during the replay.
+	ShiftTimestamps bool `mapstructure:"shift_timestamps"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks if the receiver configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.Path == "" {
+		return errMissingPath
+	}
+	if _, err := filepath.Match(cfg.Path, ""); err != nil {
+		return err
+	}
+	if cfg.FormatType != formatTypeJSON && cfg.FormatType != formatTypeProto {
+		return errUnsupportedFormat
+	}
+	if cfg.Compression != "" && cfg.Compression != compressionZSTD {
+		return errUnsupportedCompressor
+	}
+	if cfg.Speed < 0 {
+		return errNegativeSpeed
+	}
+	if cfg.MaxDelay < 0 {
+		return errNegativeMaxDelay
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/confmap/confmaptest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver/internal/metadata"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+
+	cm, err := confmaptest.LoadConf(filepath.Join("testdata", "config.yaml"))
+	require.NoError(t, err)
+
+	textEncoding := component.MustNewID("text")
+	tests := []struct {
+		id           component.ID
+		expected     component.Config
+		errorMessage string
+	}{
+		{
+			id: component.NewID(metadata.Type),
+			expected: &Config{
+				Path:       "./exported/*.json",
+				FormatType: formatTypeJSON,
+				Speed:      1,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "proto"),
+			expected: &Config{
+				Path:            "./exported/traces.proto",
+				FormatType:      formatTypeProto,
+				Compression:     compressionZSTD,
+				Speed:           10,
+				MaxDelay:        5 * time.Second,
+				ShiftTimestamps: true,
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "encoding"),
+			expected: &Config{
+				Path:       "./exported/logs.json",
+				FormatType: formatTypeJSON,
+				Encoding:   &textEncoding,
+				Speed:      1,
+			},
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_format"),
+			errorMessage: "format type is not supported",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_compression"),
+			errorMessage: "compression is not supported",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "negative_speed"),
+			errorMessage: "speed must not be negative",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "negative_max_delay"),
+			errorMessage: "max_delay must not be negative",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "missing_path"),
+			errorMessage: "path must be non-empty",
+		},
+		{
+			id:           component.NewIDWithName(metadata.Type, "invalid_path"),
+			errorMessage: "syntax error in pattern",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id.String(), func(t *testing.T) {
+			factory := NewFactory()
+			cfg := factory.CreateDefaultConfig()
+
+			sub, err := cm.Sub(tt.id.String())
+			require.NoError(t, err)
+			require.NoError(t, sub.Unmarshal(cfg))
+
+			if tt.expected == nil {
+				assert.EqualError(t, component.ValidateConfig(cfg), tt.errorMessage)
+				return
+			}
+			assert.NoError(t, component.ValidateConfig(cfg))
+			assert.Equal(t, tt.expected, cfg)
+		})
+	}
+}
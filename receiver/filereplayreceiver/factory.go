@@ -0,0 +1,83 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver"
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver/internal/metadata"
+)
+
+const (
+	transport = "file"
+)
+
+// NewFactory creates a factory for the file replay receiver
+func NewFactory() receiver.Factory {
+	return receiver.NewFactory(
+		metadata.Type,
+		createDefaultConfig,
+		receiver.WithTraces(createTracesReceiver, metadata.TracesStability),
+		receiver.WithMetrics(createMetricsReceiver, metadata.MetricsStability),
+		receiver.WithLogs(createLogsReceiver, metadata.LogsStability))
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		FormatType: formatTypeJSON,
+		Speed:      1,
+	}
+}
+
+func createTracesReceiver(_ context.Context, settings receiver.Settings, configuration component.Config, traces consumer.Traces) (receiver.Traces, error) {
+	cfg := configuration.(*Config)
+	obsrecv, err := newObsReport(settings)
+	if err != nil {
+		return nil, err
+	}
+	return newReplayReceiver(cfg, settings, &tracesSignal{
+		unmarshaler: tracesUnmarshalers[cfg.FormatType],
+		next:        traces,
+		obsrecv:     obsrecv,
+	}), nil
+}
+
+func createMetricsReceiver(_ context.Context, settings receiver.Settings, configuration component.Config, metrics consumer.Metrics) (receiver.Metrics, error) {
+	cfg := configuration.(*Config)
+	obsrecv, err := newObsReport(settings)
+	if err != nil {
+		return nil, err
+	}
+	return newReplayReceiver(cfg, settings, &metricsSignal{
+		unmarshaler: metricsUnmarshalers[cfg.FormatType],
+		next:        metrics,
+		obsrecv:     obsrecv,
+	}), nil
+}
+
+func createLogsReceiver(_ context.Context, settings receiver.Settings, configuration component.Config, logs consumer.Logs) (receiver.Logs, error) {
+	cfg := configuration.(*Config)
+	obsrecv, err := newObsReport(settings)
+	if err != nil {
+		return nil, err
+	}
+	return newReplayReceiver(cfg, settings, &logsSignal{
+		unmarshaler: logsUnmarshalers[cfg.FormatType],
+		next:        logs,
+		obsrecv:     obsrecv,
+	}), nil
+}
+
+func newObsReport(settings receiver.Settings) (*receiverhelper.ObsReport, error) {
+	return receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             settings.ID,
+		Transport:              transport,
+		ReceiverCreateSettings: settings,
+	})
+}
@@ -0,0 +1,227 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver"
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// backupTimeFormat is the format of the timestamp the file exporter puts in the name of the rotated files.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// errTruncatedMessage is returned when a file ends in the middle of a message, such as when it's still written.
+var errTruncatedMessage = errors.New("truncated message")
+
+var decoder, _ = zstd.NewReader(nil)
+
+// findSeries returns the files matching the pattern, grouped by series: a series is made of the rotated
+// backups of a file, ordered by their rotation time, followed by the file itself when it exists.
+func findSeries(pattern string) ([][]string, error) {
+	ext := filepath.Ext(pattern)
+	// the backups are named after the file, with the rotation time before the extension
+	backupsPattern := strings.TrimSuffix(pattern, ext) + "-*" + ext
+
+	// the backups may be matched by both patterns
+	paths := map[string]bool{}
+	for _, p := range []string{pattern, backupsPattern} {
+		matches, err := filepath.Glob(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range matches {
+			paths[match] = true
+		}
+	}
+
+	type backup struct {
+		path string
+		time time.Time
+	}
+	bases := map[string]bool{}
+	backups := map[string][]backup{}
+	for path := range paths {
+		if info, err := os.Stat(path); err != nil || info.IsDir() {
+			continue
+		}
+		if base, t, ok := parseBackupName(path); ok {
+			backups[base] = append(backups[base], backup{path: path, time: t})
+			if _, found := bases[base]; !found {
+				bases[base] = false
+			}
+			continue
+		}
+		bases[path] = true
+	}
+
+	series := make([][]string, 0, len(bases))
+	for base, exists := range bases {
+		rotated := backups[base]
+		sort.Slice(rotated, func(i, j int) bool {
+			return rotated[i].time.Before(rotated[j].time)
+		})
+		files := make([]string, 0, len(rotated)+1)
+		for _, b := range rotated {
+			files = append(files, b.path)
+		}
+		if exists {
+			files = append(files, base)
+		}
+		series = append(series, files)
+	}
+	sort.Slice(series, func(i, j int) bool {
+		return series[i][0] < series[j][0]
+	})
+	return series, nil
+}
+
+// parseBackupName returns the path of the file a rotated backup was created from, and its rotation time.
+func parseBackupName(path string) (string, time.Time, bool) {
+	// the milliseconds of the rotation time look like the extension of the files without extension
+	for _, ext := range []string{filepath.Ext(path), ""} {
+		name := strings.TrimSuffix(path, ext)
+		if len(name) <= len(backupTimeFormat)+1 || name[len(name)-len(backupTimeFormat)-1] != '-' {
+			continue
+		}
+		if t, err := time.Parse(backupTimeFormat, name[len(name)-len(backupTimeFormat):]); err == nil {
+			return name[:len(name)-len(backupTimeFormat)-1] + ext, t, true
+		}
+	}
+	return "", time.Time{}, false
+}
+
+// messageReader reads the messages written by the file exporter to the files of a series.
+type messageReader struct {
+	paths          []string
+	lengthPrefixed bool
+	decompress     bool
+
+	file   *os.File
+	reader *bufio.Reader
+	// offset is the position of the reader in the file, and size the last known size of the file,
+	// which keeps growing while the file exporter writes it.
+	offset int64
+	size   int64
+}
+
+func newMessageReader(cfg *Config, paths []string) *messageReader {
+	return &messageReader{
+		paths: paths,
+		// the file exporter writes the messages as lines only when they're uncompressed JSON
+		lengthPrefixed: cfg.FormatType == formatTypeProto || cfg.Compression != "",
+		decompress:     cfg.Compression == compressionZSTD,
+	}
+}
+
+// next returns the next message, or io.EOF once all the files are read. The reading goes on with
+// the next file after an error.
+func (r *messageReader) next() ([]byte, error) {
+	for {
+		if r.file == nil {
+			if len(r.paths) == 0 {
+				return nil, io.EOF
+			}
+			path := r.paths[0]
+			r.paths = r.paths[1:]
+			file, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			r.file = file
+			r.reader = bufio.NewReader(file)
+			r.offset = 0
+			r.size = 0
+		}
+
+		buf, err := r.read()
+		if err != nil {
+			name := r.file.Name()
+			closeErr := r.closeFile()
+			if !errors.Is(err, io.EOF) {
+				return nil, fmt.Errorf("reading %s: %w", name, err)
+			}
+			if closeErr != nil {
+				return nil, closeErr
+			}
+			continue
+		}
+		if r.decompress {
+			return decoder.DecodeAll(buf, nil)
+		}
+		return buf, nil
+	}
+}
+
+func (r *messageReader) read() ([]byte, error) {
+	if !r.lengthPrefixed {
+		for {
+			line, err := r.reader.ReadBytes('\n')
+			if errors.Is(err, io.EOF) && len(line) != 0 {
+				return nil, errTruncatedMessage
+			}
+			if err != nil {
+				return nil, err
+			}
+			if line = bytes.TrimSpace(line); len(line) != 0 {
+				return line, nil
+			}
+		}
+	}
+
+	// each message is preceded by its size, as a 32 bits unsigned integer
+	var size uint32
+	if err := binary.Read(r.reader, binary.BigEndian, &size); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, errTruncatedMessage
+		}
+		return nil, err
+	}
+	r.offset += 4
+	// the size isn't trusted before allocating the message, as a corrupted file would make it arbitrary:
+	// a message larger than the rest of the file is truncated, whatever its size
+	if r.offset+int64(size) > r.size {
+		info, err := r.file.Stat()
+		if err != nil {
+			return nil, err
+		}
+		r.size = info.Size()
+		if r.offset+int64(size) > r.size {
+			return nil, fmt.Errorf("%w: the message has %d bytes, while %d remain in the file", errTruncatedMessage, size, r.size-r.offset)
+		}
+	}
+	buf := make([]byte, size)
+	if _, err := io.ReadFull(r.reader, buf); err != nil {
+		if errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+			return nil, errTruncatedMessage
+		}
+		return nil, err
+	}
+	r.offset += int64(size)
+	return buf, nil
+}
+
+func (r *messageReader) closeFile() error {
+	err := r.file.Close()
+	r.file = nil
+	r.reader = nil
+	return err
+}
+
+func (r *messageReader) close() error {
+	if r.file == nil {
+		return nil
+	}
+	return r.closeFile()
+}
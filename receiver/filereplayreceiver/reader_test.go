@@ -0,0 +1,212 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var encoder, _ = zstd.NewWriter(nil)
+
+// writeMessages writes the messages to a file the way the file exporter does.
+func writeMessages(t *testing.T, cfg *Config, path string, messages ...[]byte) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	require.NoError(t, err)
+	defer file.Close()
+	for _, message := range messages {
+		if cfg.Compression == compressionZSTD {
+			message = encoder.EncodeAll(message, nil)
+		}
+		if cfg.FormatType == formatTypeJSON && cfg.Compression == "" {
+			_, err = file.Write(append(message, '\n'))
+		} else {
+			size := make([]byte, 4)
+			binary.BigEndian.PutUint32(size, uint32(len(message)))
+			_, err = file.Write(append(size, message...))
+		}
+		require.NoError(t, err)
+	}
+}
+
+func touch(t *testing.T, path string) {
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+}
+
+func TestFindSeries(t *testing.T) {
+	dir := t.TempDir()
+	touch(t, filepath.Join(dir, "a.json"))
+	touch(t, filepath.Join(dir, "a-2024-01-02T03-04-05.000.json"))
+	touch(t, filepath.Join(dir, "a-2024-01-01T23-59-59.999.json"))
+	touch(t, filepath.Join(dir, "b.json"))
+	touch(t, filepath.Join(dir, "c-2024-01-01T00-00-00.000.json"))
+	touch(t, filepath.Join(dir, "d.proto"))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "e.json"), 0o700))
+
+	series, err := findSeries(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{
+			filepath.Join(dir, "a-2024-01-01T23-59-59.999.json"),
+			filepath.Join(dir, "a-2024-01-02T03-04-05.000.json"),
+			filepath.Join(dir, "a.json"),
+		},
+		{filepath.Join(dir, "b.json")},
+		{filepath.Join(dir, "c-2024-01-01T00-00-00.000.json")},
+	}, series)
+
+	series, err = findSeries(filepath.Join(dir, "a.json"))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{
+			filepath.Join(dir, "a-2024-01-01T23-59-59.999.json"),
+			filepath.Join(dir, "a-2024-01-02T03-04-05.000.json"),
+			filepath.Join(dir, "a.json"),
+		},
+	}, series)
+
+	series, err = findSeries(filepath.Join(dir, "missing.json"))
+	require.NoError(t, err)
+	assert.Empty(t, series)
+}
+
+func TestParseBackupName(t *testing.T) {
+	base, ts, ok := parseBackupName("/tmp/traces-2024-06-15T10-20-30.400.json")
+	assert.True(t, ok)
+	assert.Equal(t, "/tmp/traces.json", base)
+	assert.Equal(t, "2024-06-15T10:20:30.4Z", ts.Format("2006-01-02T15:04:05.999Z07:00"))
+
+	base, _, ok = parseBackupName("/tmp/traces-2024-06-15T10-20-30.400")
+	assert.True(t, ok)
+	assert.Equal(t, "/tmp/traces", base)
+
+	_, _, ok = parseBackupName("/tmp/traces.json")
+	assert.False(t, ok)
+	_, _, ok = parseBackupName("/tmp/traces-2024-13-15T10-20-30.400.json")
+	assert.False(t, ok)
+	_, _, ok = parseBackupName("/tmp/traces_2024-06-15T10-20-30.400.json")
+	assert.False(t, ok)
+}
+
+func TestMessageReader(t *testing.T) {
+	tests := []struct {
+		name        string
+		format      string
+		compression string
+	}{
+		{
+			name:   "json",
+			format: formatTypeJSON,
+		},
+		{
+			name:        "json with compression",
+			format:      formatTypeJSON,
+			compression: compressionZSTD,
+		},
+		{
+			name:   "proto",
+			format: formatTypeProto,
+		},
+		{
+			name:        "proto with compression",
+			format:      formatTypeProto,
+			compression: compressionZSTD,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			cfg := &Config{FormatType: tt.format, Compression: tt.compression}
+			first := filepath.Join(dir, "first")
+			writeMessages(t, cfg, first, []byte(`{"a":1}`), []byte(`{"b":2}`))
+			empty := filepath.Join(dir, "empty")
+			touch(t, empty)
+			second := filepath.Join(dir, "second")
+			writeMessages(t, cfg, second, []byte(`{"c":3}`))
+
+			reader := newMessageReader(cfg, []string{first, empty, second})
+			defer func() {
+				assert.NoError(t, reader.close())
+			}()
+			var messages []string
+			for {
+				buf, err := reader.next()
+				if errors.Is(err, io.EOF) {
+					break
+				}
+				require.NoError(t, err)
+				messages = append(messages, string(buf))
+			}
+			assert.Equal(t, []string{`{"a":1}`, `{"b":2}`, `{"c":3}`}, messages)
+		})
+	}
+}
+
+func TestMessageReaderErrors(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &Config{FormatType: formatTypeProto}
+	truncated := filepath.Join(dir, "truncated")
+	writeMessages(t, cfg, truncated, []byte("message"))
+	content, err := os.ReadFile(truncated)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(truncated, content[:len(content)-1], 0o600))
+	valid := filepath.Join(dir, "valid")
+	writeMessages(t, cfg, valid, []byte("message"))
+
+	reader := newMessageReader(cfg, []string{filepath.Join(dir, "missing"), truncated, valid})
+	_, err = reader.next()
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	_, err = reader.next()
+	assert.ErrorIs(t, err, errTruncatedMessage)
+	buf, err := reader.next()
+	require.NoError(t, err)
+	assert.Equal(t, "message", string(buf))
+	_, err = reader.next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestMessageReaderInvalidSize(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &Config{FormatType: formatTypeProto}
+	corrupted := filepath.Join(dir, "corrupted")
+	writeMessages(t, cfg, corrupted, []byte("message"))
+	content, err := os.ReadFile(corrupted)
+	require.NoError(t, err)
+	binary.BigEndian.PutUint32(content, 0xffffffff)
+	require.NoError(t, os.WriteFile(corrupted, content, 0o600))
+	valid := filepath.Join(dir, "valid")
+	writeMessages(t, cfg, valid, []byte("message"))
+
+	reader := newMessageReader(cfg, []string{corrupted, valid})
+	_, err = reader.next()
+	assert.ErrorIs(t, err, errTruncatedMessage)
+	assert.ErrorContains(t, err, "the message has 4294967295 bytes, while 7 remain in the file")
+	buf, err := reader.next()
+	require.NoError(t, err)
+	assert.Equal(t, "message", string(buf))
+	_, err = reader.next()
+	assert.ErrorIs(t, err, io.EOF)
+}
+
+func TestMessageReaderTruncatedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "traces.json")
+	require.NoError(t, os.WriteFile(path, []byte("{\"a\":1}\n\n{\"b\""), 0o600))
+
+	reader := newMessageReader(&Config{FormatType: formatTypeJSON}, []string{path})
+	buf, err := reader.next()
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(buf))
+	_, err = reader.next()
+	assert.ErrorIs(t, err, errTruncatedMessage)
+	_, err = reader.next()
+	assert.ErrorIs(t, err, io.EOF)
+}
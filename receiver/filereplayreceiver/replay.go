@@ -0,0 +1,222 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/receiver"
+	"go.uber.org/zap"
+)
+
+// replayReceiver replays the telemetry of the files written by the file exporter.
+type replayReceiver struct {
+	cfg      *Config
+	settings receiver.Settings
+	signal   signal
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newReplayReceiver(cfg *Config, settings receiver.Settings, s signal) *replayReceiver {
+	return &replayReceiver{
+		cfg:      cfg,
+		settings: settings,
+		signal:   s,
+	}
+}
+
+func (r *replayReceiver) Start(_ context.Context, host component.Host) error {
+	if r.cfg.Encoding != nil {
+		encoding, ok := host.GetExtensions()[*r.cfg.Encoding]
+		if !ok {
+			return fmt.Errorf("unknown encoding %q", r.cfg.Encoding)
+		}
+		if err := r.signal.setEncoding(*r.cfg.Encoding, encoding); err != nil {
+			return err
+		}
+	}
+
+	series, err := findSeries(r.cfg.Path)
+	if err != nil {
+		return err
+	}
+	if len(series) == 0 {
+		r.settings.Logger.Warn("No file to replay", zap.String("path", r.cfg.Path))
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r.cancel = cancel
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.replay(ctx, series)
+	}()
+	return nil
+}
+
+func (r *replayReceiver) Shutdown(_ context.Context) error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+	return nil
+}
+
+// seriesHead is the next message of a series.
+type seriesHead struct {
+	reader    *messageReader
+	telemetry telemetry
+	timestamp pcommon.Timestamp
+}
+
+// replay consumes the messages of the series in the order of their timestamps, waiting for the
+// time between them.
+func (r *replayReceiver) replay(ctx context.Context, series [][]string) {
+	heads := make([]*seriesHead, 0, len(series))
+	for _, paths := range series {
+		head := &seriesHead{reader: newMessageReader(r.cfg, paths)}
+		if r.advance(head) {
+			heads = append(heads, head)
+		}
+	}
+	defer func() {
+		for _, head := range heads {
+			_ = head.reader.close()
+		}
+	}()
+
+	clock := newReplayClock(r.cfg.Speed, r.cfg.MaxDelay)
+	for len(heads) > 0 {
+		// the messages without timestamp can't be ordered, they're consumed right away
+		next := 0
+		for i, head := range heads[1:] {
+			if head.timestamp < heads[next].timestamp {
+				next = i + 1
+			}
+		}
+		head := heads[next]
+
+		if err := clock.wait(ctx, head.timestamp); err != nil {
+			return
+		}
+		if r.cfg.ShiftTimestamps {
+			head.telemetry.shiftTimestamps(clock.shift)
+		}
+		if err := r.signal.consume(ctx, head.telemetry); err != nil {
+			r.settings.Logger.Error("Failed to consume replayed telemetry", zap.Error(err))
+		}
+
+		if !r.advance(head) {
+			_ = head.reader.close()
+			heads = append(heads[:next], heads[next+1:]...)
+		}
+	}
+	r.settings.Logger.Info("Replay completed", zap.String("path", r.cfg.Path))
+}
+
+// advance reads the next message of the signal from the series, it returns false once the series is read.
+// The messages which can't be read or decoded are skipped.
+func (r *replayReceiver) advance(head *seriesHead) bool {
+	for {
+		buf, err := head.reader.next()
+		if errors.Is(err, io.EOF) {
+			return false
+		}
+		if err != nil {
+			r.settings.Logger.Warn("Failed to read replayed file", zap.Error(err))
+			continue
+		}
+		t, err := r.signal.decode(buf)
+		if err != nil {
+			r.settings.Logger.Warn("Failed to decode replayed message", zap.Error(err))
+			continue
+		}
+		// the files may hold the messages of the other signals
+		if t.isEmpty() {
+			continue
+		}
+		head.telemetry = t
+		head.timestamp = t.timestamp()
+		return true
+	}
+}
+
+// replayClock maps the timestamps of the replayed telemetry to the time they're replayed at.
+// The first timestamp is replayed when the replay starts, and the next ones after their time
+// from the first timestamp, divided by the speed.
+type replayClock struct {
+	speed    float64
+	maxDelay time.Duration
+
+	start time.Time
+	base  pcommon.Timestamp
+}
+
+func newReplayClock(speed float64, maxDelay time.Duration) *replayClock {
+	return &replayClock{
+		speed:    speed,
+		maxDelay: maxDelay,
+	}
+}
+
+// wait waits for the time a timestamp is replayed at, it returns an error when the context is done first.
+func (c *replayClock) wait(ctx context.Context, ts pcommon.Timestamp) error {
+	if ts == 0 {
+		return ctx.Err()
+	}
+	if c.base == 0 {
+		c.base = ts
+		c.start = time.Now()
+		return ctx.Err()
+	}
+	if c.speed == 0 {
+		return ctx.Err()
+	}
+
+	delay := time.Until(c.replayTime(ts))
+	if c.maxDelay > 0 && delay > c.maxDelay {
+		// the next timestamps are moved earlier by the skipped time
+		c.start = c.start.Add(c.maxDelay - delay)
+		delay = c.maxDelay
+	}
+	if delay <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// shift returns the time a timestamp is replayed at. When the telemetry is replayed as fast as
+// possible, the timestamps are shifted as if it was replayed at its original pace.
+func (c *replayClock) shift(ts pcommon.Timestamp) pcommon.Timestamp {
+	if ts == 0 || c.base == 0 {
+		return ts
+	}
+	return pcommon.NewTimestampFromTime(c.replayTime(ts))
+}
+
+func (c *replayClock) replayTime(ts pcommon.Timestamp) time.Time {
+	speed := c.speed
+	if speed == 0 {
+		speed = 1
+	}
+	offset := int64(ts) - int64(c.base)
+	return c.start.Add(time.Duration(float64(offset) / speed))
+}
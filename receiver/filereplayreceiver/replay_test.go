@@ -0,0 +1,310 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+)
+
+func newTraces(timestamps ...pcommon.Timestamp) ptrace.Traces {
+	td := ptrace.NewTraces()
+	spans := td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans()
+	for _, ts := range timestamps {
+		span := spans.AppendEmpty()
+		span.SetName("span")
+		span.SetStartTimestamp(ts)
+		span.SetEndTimestamp(ts + 10)
+		span.Events().AppendEmpty().SetTimestamp(ts + 5)
+	}
+	return td
+}
+
+func newMetrics(timestamps ...pcommon.Timestamp) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	metrics := md.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty().Metrics()
+	for _, ts := range timestamps {
+		metric := metrics.AppendEmpty()
+		metric.SetName("metric")
+		dp := metric.SetEmptySum().DataPoints().AppendEmpty()
+		dp.SetStartTimestamp(ts - 10)
+		dp.SetTimestamp(ts)
+		dp.SetIntValue(1)
+		dp.Exemplars().AppendEmpty().SetTimestamp(ts - 5)
+	}
+	return md
+}
+
+func newLogs(timestamps ...pcommon.Timestamp) plog.Logs {
+	ld := plog.NewLogs()
+	records := ld.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords()
+	for _, ts := range timestamps {
+		lr := records.AppendEmpty()
+		lr.Body().SetStr("log")
+		lr.SetTimestamp(ts)
+		lr.SetObservedTimestamp(ts + 1)
+	}
+	return ld
+}
+
+func marshalJSON(t *testing.T, data any) []byte {
+	var buf []byte
+	var err error
+	switch d := data.(type) {
+	case ptrace.Traces:
+		buf, err = (&ptrace.JSONMarshaler{}).MarshalTraces(d)
+	case pmetric.Metrics:
+		buf, err = (&pmetric.JSONMarshaler{}).MarshalMetrics(d)
+	case plog.Logs:
+		buf, err = (&plog.JSONMarshaler{}).MarshalLogs(d)
+	}
+	require.NoError(t, err)
+	return buf
+}
+
+func startReceiver(t *testing.T, rcvr component.Component, host component.Host) {
+	require.NoError(t, rcvr.Start(context.Background(), host))
+	t.Cleanup(func() {
+		assert.NoError(t, rcvr.Shutdown(context.Background()))
+	})
+}
+
+func spanTimestamps(traces []ptrace.Traces) []pcommon.Timestamp {
+	var timestamps []pcommon.Timestamp
+	for _, td := range traces {
+		forEachSpan(td, func(span ptrace.Span) {
+			timestamps = append(timestamps, span.StartTimestamp())
+		})
+	}
+	return timestamps
+}
+
+func TestReplayTraces(t *testing.T) {
+	dir := t.TempDir()
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = filepath.Join(dir, "*.json")
+	cfg.Speed = 0
+
+	// the files hold the telemetry of all the signals, and a message which can't be decoded
+	writeMessages(t, cfg, filepath.Join(dir, "a.json"),
+		marshalJSON(t, newTraces(1000)),
+		marshalJSON(t, newMetrics(1500)),
+		[]byte("{invalid"),
+		marshalJSON(t, newTraces(3000)))
+	writeMessages(t, cfg, filepath.Join(dir, "b.json"),
+		marshalJSON(t, newTraces(2000)),
+		marshalJSON(t, newLogs(2500)),
+		marshalJSON(t, newTraces(4000, 3500)))
+
+	sink := new(consumertest.TracesSink)
+	rcvr, err := NewFactory().CreateTracesReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	startReceiver(t, rcvr, componenttest.NewNopHost())
+
+	require.Eventually(t, func() bool {
+		return sink.SpanCount() == 5
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, []pcommon.Timestamp{1000, 2000, 3000, 4000, 3500}, spanTimestamps(sink.AllTraces()))
+}
+
+func TestReplayMetricsCompressedProto(t *testing.T) {
+	dir := t.TempDir()
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = filepath.Join(dir, "metrics.proto")
+	cfg.FormatType = formatTypeProto
+	cfg.Compression = compressionZSTD
+	cfg.Speed = 0
+
+	marshal := func(md pmetric.Metrics) []byte {
+		buf, err := (&pmetric.ProtoMarshaler{}).MarshalMetrics(md)
+		require.NoError(t, err)
+		return buf
+	}
+	// the rotated backups are replayed before the file
+	writeMessages(t, cfg, filepath.Join(dir, "metrics.proto"), marshal(newMetrics(3000)))
+	writeMessages(t, cfg, filepath.Join(dir, "metrics-2024-01-02T00-00-00.000.proto"), marshal(newMetrics(2000)))
+	writeMessages(t, cfg, filepath.Join(dir, "metrics-2024-01-01T00-00-00.000.proto"), marshal(newMetrics(1000)))
+
+	sink := new(consumertest.MetricsSink)
+	rcvr, err := NewFactory().CreateMetricsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	startReceiver(t, rcvr, componenttest.NewNopHost())
+
+	require.Eventually(t, func() bool {
+		return sink.DataPointCount() == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	var timestamps []pcommon.Timestamp
+	for _, md := range sink.AllMetrics() {
+		timestamps = append(timestamps, metrics{md}.timestamp())
+	}
+	assert.Equal(t, []pcommon.Timestamp{1000, 2000, 3000}, timestamps)
+}
+
+func TestReplayLogsShiftTimestamps(t *testing.T) {
+	dir := t.TempDir()
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = filepath.Join(dir, "logs.json")
+	cfg.Speed = 0
+	cfg.ShiftTimestamps = true
+
+	recorded := pcommon.NewTimestampFromTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	writeMessages(t, cfg, cfg.Path,
+		marshalJSON(t, newLogs(recorded)),
+		marshalJSON(t, newLogs(recorded+pcommon.Timestamp(time.Minute))))
+
+	sink := new(consumertest.LogsSink)
+	rcvr, err := NewFactory().CreateLogsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	before := time.Now()
+	startReceiver(t, rcvr, componenttest.NewNopHost())
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	first := sink.AllLogs()[0].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	second := sink.AllLogs()[1].ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	assert.WithinRange(t, first.Timestamp().AsTime(), before, time.Now())
+	assert.Equal(t, first.Timestamp()+1, first.ObservedTimestamp())
+	// the replay is as fast as possible, but the timestamps keep their original spacing
+	assert.Equal(t, first.Timestamp()+pcommon.Timestamp(time.Minute), second.Timestamp())
+}
+
+func TestReplayNoFile(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = filepath.Join(t.TempDir(), "*.json")
+
+	rcvr, err := NewFactory().CreateTracesReceiver(context.Background(), receivertest.NewNopSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, rcvr.Shutdown(context.Background()))
+}
+
+func TestReplayShutdownWhileWaiting(t *testing.T) {
+	dir := t.TempDir()
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = filepath.Join(dir, "traces.json")
+	writeMessages(t, cfg, cfg.Path,
+		marshalJSON(t, newTraces(1)),
+		marshalJSON(t, newTraces(1+pcommon.Timestamp(time.Hour))))
+
+	sink := new(consumertest.TracesSink)
+	rcvr, err := NewFactory().CreateTracesReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	require.Eventually(t, func() bool {
+		return sink.SpanCount() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(t, rcvr.Shutdown(context.Background()))
+	assert.Equal(t, 1, sink.SpanCount())
+}
+
+type hostWithExtensions struct {
+	component.Host
+	extensions map[component.ID]component.Component
+}
+
+func (h hostWithExtensions) GetExtensions() map[component.ID]component.Component {
+	return h.extensions
+}
+
+type tracesEncodingExtension struct {
+	component.StartFunc
+	component.ShutdownFunc
+}
+
+// UnmarshalTraces decodes the messages as the names of the spans.
+func (tracesEncodingExtension) UnmarshalTraces(buf []byte) (ptrace.Traces, error) {
+	td := ptrace.NewTraces()
+	td.ResourceSpans().AppendEmpty().ScopeSpans().AppendEmpty().Spans().AppendEmpty().SetName(string(buf))
+	return td, nil
+}
+
+func TestReplayEncoding(t *testing.T) {
+	dir := t.TempDir()
+	cfg := createDefaultConfig().(*Config)
+	cfg.Path = filepath.Join(dir, "traces.txt")
+	encodingID := component.MustNewID("spanname")
+	cfg.Encoding = &encodingID
+	writeMessages(t, cfg, cfg.Path, []byte("first"), []byte("second"))
+
+	host := hostWithExtensions{
+		Host:       componenttest.NewNopHost(),
+		extensions: map[component.ID]component.Component{encodingID: tracesEncodingExtension{}},
+	}
+
+	sink := new(consumertest.TracesSink)
+	rcvr, err := NewFactory().CreateTracesReceiver(context.Background(), receivertest.NewNopSettings(), cfg, sink)
+	require.NoError(t, err)
+	startReceiver(t, rcvr, host)
+	require.Eventually(t, func() bool {
+		return sink.SpanCount() == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Equal(t, "first", sink.AllTraces()[0].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+	assert.Equal(t, "second", sink.AllTraces()[1].ResourceSpans().At(0).ScopeSpans().At(0).Spans().At(0).Name())
+
+	// the extension doesn't support logs
+	logsRcvr, err := NewFactory().CreateLogsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.EqualError(t, logsRcvr.Start(context.Background(), host), `extension "spanname" is not a logs unmarshaler`)
+
+	missingRcvr, err := NewFactory().CreateTracesReceiver(context.Background(), receivertest.NewNopSettings(), cfg, consumertest.NewNop())
+	require.NoError(t, err)
+	assert.EqualError(t, missingRcvr.Start(context.Background(), componenttest.NewNopHost()), `unknown encoding "spanname"`)
+}
+
+func TestReplayClock(t *testing.T) {
+	base := pcommon.NewTimestampFromTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	clock := newReplayClock(100, 0)
+	require.NoError(t, clock.wait(context.Background(), base))
+	start := time.Now()
+	// one second of the recorded telemetry is replayed in 10 milliseconds
+	require.NoError(t, clock.wait(context.Background(), base+pcommon.Timestamp(time.Second)))
+	assert.GreaterOrEqual(t, time.Since(start), 5*time.Millisecond)
+	assert.Equal(t, pcommon.NewTimestampFromTime(clock.start.Add(10*time.Millisecond)), clock.shift(base+pcommon.Timestamp(time.Second)))
+	assert.Equal(t, pcommon.Timestamp(0), clock.shift(0))
+
+	// the telemetry without timestamp is replayed right away
+	require.NoError(t, clock.wait(context.Background(), 0))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, clock.wait(ctx, base+pcommon.Timestamp(time.Hour)), context.Canceled)
+}
+
+func TestReplayClockMaxDelay(t *testing.T) {
+	base := pcommon.NewTimestampFromTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	clock := newReplayClock(1, 10*time.Millisecond)
+	require.NoError(t, clock.wait(context.Background(), base))
+	start := clock.start
+	waitStart := time.Now()
+	// the gap of an hour is skipped
+	require.NoError(t, clock.wait(context.Background(), base+pcommon.Timestamp(time.Hour)))
+	assert.Less(t, time.Since(waitStart), time.Minute)
+	assert.WithinDuration(t, start.Add(-time.Hour), clock.start, time.Minute)
+	assert.WithinDuration(t, time.Now(), clock.shift(base+pcommon.Timestamp(time.Hour)).AsTime(), time.Minute)
+}
+
+func TestReplayClockAsFastAsPossible(t *testing.T) {
+	base := pcommon.NewTimestampFromTime(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	clock := newReplayClock(0, 0)
+	require.NoError(t, clock.wait(context.Background(), base))
+	require.NoError(t, clock.wait(context.Background(), base+pcommon.Timestamp(time.Hour)))
+	assert.Equal(t, pcommon.NewTimestampFromTime(clock.start.Add(time.Hour)), clock.shift(base+pcommon.Timestamp(time.Hour)))
+}
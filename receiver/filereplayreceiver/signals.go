@@ -0,0 +1,304 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package filereplayreceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver"
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/filereplayreceiver/internal/metadata"
+)
+
+// Unmarshaler configuration used for unmarshaling the formats of the file exporter
+var tracesUnmarshalers = map[string]ptrace.Unmarshaler{
+	formatTypeJSON:  &ptrace.JSONUnmarshaler{},
+	formatTypeProto: &ptrace.ProtoUnmarshaler{},
+}
+var metricsUnmarshalers = map[string]pmetric.Unmarshaler{
+	formatTypeJSON:  &pmetric.JSONUnmarshaler{},
+	formatTypeProto: &pmetric.ProtoUnmarshaler{},
+}
+var logsUnmarshalers = map[string]plog.Unmarshaler{
+	formatTypeJSON:  &plog.JSONUnmarshaler{},
+	formatTypeProto: &plog.ProtoUnmarshaler{},
+}
+
+// shiftFunc returns the timestamp an original timestamp of the telemetry is replayed at.
+type shiftFunc func(pcommon.Timestamp) pcommon.Timestamp
+
+// signal decodes and consumes the messages of a type of telemetry.
+type signal interface {
+	// setEncoding makes the signal decode the messages with an encoding extension.
+	setEncoding(id component.ID, encoding component.Component) error
+	// decode returns the telemetry of a message, which is empty when the message holds another type of telemetry.
+	decode(buf []byte) (telemetry, error)
+	consume(ctx context.Context, t telemetry) error
+}
+
+// telemetry is the decoded content of a message.
+type telemetry interface {
+	isEmpty() bool
+	// timestamp returns the earliest timestamp of the telemetry, or 0 when it has no timestamp.
+	timestamp() pcommon.Timestamp
+	shiftTimestamps(shift shiftFunc)
+}
+
+type tracesSignal struct {
+	unmarshaler ptrace.Unmarshaler
+	next        consumer.Traces
+	obsrecv     *receiverhelper.ObsReport
+}
+
+func (s *tracesSignal) setEncoding(id component.ID, encoding component.Component) error {
+	unmarshaler, ok := encoding.(ptrace.Unmarshaler)
+	if !ok {
+		return fmt.Errorf("extension %q is not a traces unmarshaler", id)
+	}
+	s.unmarshaler = unmarshaler
+	return nil
+}
+
+func (s *tracesSignal) decode(buf []byte) (telemetry, error) {
+	td, err := s.unmarshaler.UnmarshalTraces(buf)
+	return traces{td}, err
+}
+
+func (s *tracesSignal) consume(ctx context.Context, t telemetry) error {
+	td := t.(traces).Traces
+	ctx = s.obsrecv.StartTracesOp(ctx)
+	err := s.next.ConsumeTraces(ctx, td)
+	s.obsrecv.EndTracesOp(ctx, metadata.Type.String(), td.SpanCount(), err)
+	return err
+}
+
+type traces struct {
+	ptrace.Traces
+}
+
+func (t traces) isEmpty() bool {
+	return t.ResourceSpans().Len() == 0
+}
+
+func (t traces) timestamp() pcommon.Timestamp {
+	var earliest pcommon.Timestamp
+	forEachSpan(t.Traces, func(span ptrace.Span) {
+		earliest = earliestTimestamp(earliest, span.StartTimestamp())
+	})
+	return earliest
+}
+
+func (t traces) shiftTimestamps(shift shiftFunc) {
+	forEachSpan(t.Traces, func(span ptrace.Span) {
+		span.SetStartTimestamp(shift(span.StartTimestamp()))
+		span.SetEndTimestamp(shift(span.EndTimestamp()))
+		for i := 0; i < span.Events().Len(); i++ {
+			event := span.Events().At(i)
+			event.SetTimestamp(shift(event.Timestamp()))
+		}
+	})
+}
+
+func forEachSpan(td ptrace.Traces, f func(span ptrace.Span)) {
+	for i := 0; i < td.ResourceSpans().Len(); i++ {
+		rs := td.ResourceSpans().At(i)
+		for j := 0; j < rs.ScopeSpans().Len(); j++ {
+			spans := rs.ScopeSpans().At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				f(spans.At(k))
+			}
+		}
+	}
+}
+
+type metricsSignal struct {
+	unmarshaler pmetric.Unmarshaler
+	next        consumer.Metrics
+	obsrecv     *receiverhelper.ObsReport
+}
+
+func (s *metricsSignal) setEncoding(id component.ID, encoding component.Component) error {
+	unmarshaler, ok := encoding.(pmetric.Unmarshaler)
+	if !ok {
+		return fmt.Errorf("extension %q is not a metrics unmarshaler", id)
+	}
+	s.unmarshaler = unmarshaler
+	return nil
+}
+
+func (s *metricsSignal) decode(buf []byte) (telemetry, error) {
+	md, err := s.unmarshaler.UnmarshalMetrics(buf)
+	return metrics{md}, err
+}
+
+func (s *metricsSignal) consume(ctx context.Context, t telemetry) error {
+	md := t.(metrics).Metrics
+	ctx = s.obsrecv.StartMetricsOp(ctx)
+	err := s.next.ConsumeMetrics(ctx, md)
+	s.obsrecv.EndMetricsOp(ctx, metadata.Type.String(), md.DataPointCount(), err)
+	return err
+}
+
+type metrics struct {
+	pmetric.Metrics
+}
+
+func (m metrics) isEmpty() bool {
+	return m.ResourceMetrics().Len() == 0
+}
+
+func (m metrics) timestamp() pcommon.Timestamp {
+	var earliest pcommon.Timestamp
+	forEachDataPoint(m.Metrics, func(dp dataPoint, _ pmetric.ExemplarSlice) {
+		earliest = earliestTimestamp(earliest, dp.Timestamp())
+	})
+	return earliest
+}
+
+func (m metrics) shiftTimestamps(shift shiftFunc) {
+	forEachDataPoint(m.Metrics, func(dp dataPoint, exemplars pmetric.ExemplarSlice) {
+		dp.SetStartTimestamp(shift(dp.StartTimestamp()))
+		dp.SetTimestamp(shift(dp.Timestamp()))
+		for i := 0; i < exemplars.Len(); i++ {
+			exemplar := exemplars.At(i)
+			exemplar.SetTimestamp(shift(exemplar.Timestamp()))
+		}
+	})
+}
+
+// dataPoint holds the timestamps common to all the types of data points.
+type dataPoint interface {
+	StartTimestamp() pcommon.Timestamp
+	SetStartTimestamp(pcommon.Timestamp)
+	Timestamp() pcommon.Timestamp
+	SetTimestamp(pcommon.Timestamp)
+}
+
+// forEachDataPoint calls the function with the data points of the metrics, and their exemplars.
+// The summaries don't have exemplars, they are called with an empty slice.
+func forEachDataPoint(md pmetric.Metrics, f func(dp dataPoint, exemplars pmetric.ExemplarSlice)) {
+	noExemplars := pmetric.NewExemplarSlice()
+	for i := 0; i < md.ResourceMetrics().Len(); i++ {
+		rm := md.ResourceMetrics().At(i)
+		for j := 0; j < rm.ScopeMetrics().Len(); j++ {
+			ms := rm.ScopeMetrics().At(j).Metrics()
+			for k := 0; k < ms.Len(); k++ {
+				metric := ms.At(k)
+				//exhaustive:enforce
+				switch metric.Type() {
+				case pmetric.MetricTypeGauge:
+					dps := metric.Gauge().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						f(dps.At(l), dps.At(l).Exemplars())
+					}
+				case pmetric.MetricTypeSum:
+					dps := metric.Sum().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						f(dps.At(l), dps.At(l).Exemplars())
+					}
+				case pmetric.MetricTypeHistogram:
+					dps := metric.Histogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						f(dps.At(l), dps.At(l).Exemplars())
+					}
+				case pmetric.MetricTypeExponentialHistogram:
+					dps := metric.ExponentialHistogram().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						f(dps.At(l), dps.At(l).Exemplars())
+					}
+				case pmetric.MetricTypeSummary:
+					dps := metric.Summary().DataPoints()
+					for l := 0; l < dps.Len(); l++ {
+						f(dps.At(l), noExemplars)
+					}
+				case pmetric.MetricTypeEmpty:
+				}
+			}
+		}
+	}
+}
+
+type logsSignal struct {
+	unmarshaler plog.Unmarshaler
+	next        consumer.Logs
+	obsrecv     *receiverhelper.ObsReport
+}
+
+func (s *logsSignal) setEncoding(id component.ID, encoding component.Component) error {
+	unmarshaler, ok := encoding.(plog.Unmarshaler)
+	if !ok {
+		return fmt.Errorf("extension %q is not a logs unmarshaler", id)
+	}
+	s.unmarshaler = unmarshaler
+	return nil
+}
+
+func (s *logsSignal) decode(buf []byte) (telemetry, error) {
+	ld, err := s.unmarshaler.UnmarshalLogs(buf)
+	return logs{ld}, err
+}
+
+func (s *logsSignal) consume(ctx context.Context, t telemetry) error {
+	ld := t.(logs).Logs
+	ctx = s.obsrecv.StartLogsOp(ctx)
+	err := s.next.ConsumeLogs(ctx, ld)
+	s.obsrecv.EndLogsOp(ctx, metadata.Type.String(), ld.LogRecordCount(), err)
+	return err
+}
+
+type logs struct {
+	plog.Logs
+}
+
+func (l logs) isEmpty() bool {
+	return l.ResourceLogs().Len() == 0
+}
+
+// timestamp returns the earliest timestamp of the log records, using the observed timestamp of
+// the records without timestamp.
+func (l logs) timestamp() pcommon.Timestamp {
+	var earliest pcommon.Timestamp
+	forEachLogRecord(l.Logs, func(lr plog.LogRecord) {
+		ts := lr.Timestamp()
+		if ts == 0 {
+			ts = lr.ObservedTimestamp()
+		}
+		earliest = earliestTimestamp(earliest, ts)
+	})
+	return earliest
+}
+
+func (l logs) shiftTimestamps(shift shiftFunc) {
+	forEachLogRecord(l.Logs, func(lr plog.LogRecord) {
+		lr.SetTimestamp(shift(lr.Timestamp()))
+		lr.SetObservedTimestamp(shift(lr.ObservedTimestamp()))
+	})
+}
+
+func forEachLogRecord(ld plog.Logs, f func(lr plog.LogRecord)) {
+	for i := 0; i < ld.ResourceLogs().Len(); i++ {
+		rl := ld.ResourceLogs().At(i)
+		for j := 0; j < rl.ScopeLogs().Len(); j++ {
+			records := rl.ScopeLogs().At(j).LogRecords()
+			for k := 0; k < records.Len(); k++ {
+				f(records.At(k))
+			}
+		}
+	}
+}
+
+// earliestTimestamp returns the earliest of the timestamps, ignoring the unset ones.
+func earliestTimestamp(a, b pcommon.Timestamp) pcommon.Timestamp {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
@@ -4,12 +4,15 @@
 package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
 
 import (
+	"fmt"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configretry"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
 )
 
 type AutoCommit struct {
@@ -32,6 +35,13 @@ type MessageMarking struct {
 	OnError bool `mapstructure:"on_error"`
 }
 
+// DeadLetter defines the topic the messages failing to be unmarshaled are produced to.
+type DeadLetter struct {
+	// Topic the messages failing to be unmarshaled are produced to, with headers describing where they
+	// were consumed from and the error. The messages aren't produced anywhere when it's empty.
+	Topic string `mapstructure:"topic"`
+}
+
 type HeaderExtraction struct {
 	ExtractHeaders bool     `mapstructure:"extract_headers"`
 	Headers        []string `mapstructure:"headers"`
@@ -72,8 +82,20 @@ type Config struct {
 	// Controls the way the messages are marked as consumed
 	MessageMarking MessageMarking `mapstructure:"message_marking"`
 
+	// ErrorBackOff controls the retries of the messages whose consumption by the pipeline returned a
+	// retryable error. Their partition is paused while they're retried, and they're marked once they
+	// succeed, or once the retries are exhausted following MessageMarking (disabled by default).
+	ErrorBackOff configretry.BackOffConfig `mapstructure:"error_backoff"`
+
+	// DeadLetter controls the production of the messages failing to be unmarshaled to a dead-letter topic
+	DeadLetter DeadLetter `mapstructure:"dead_letter"`
+
 	// Extract headers from kafka records
 	HeaderExtraction HeaderExtraction `mapstructure:"header_extraction"`
+
+	// SchemaRegistry configures the schema registry the avro and protobuf encodings resolve
+	// the schemas of the messages with.
+	SchemaRegistry schemaregistry.ClientConfig `mapstructure:"schema_registry"`
 }
 
 const (
@@ -85,5 +107,8 @@ var _ component.Config = (*Config)(nil)
 
 // Validate checks the receiver configuration is valid
 func (cfg *Config) Validate() error {
+	if isSchemaRegistryEncoding(cfg.Encoding) && cfg.SchemaRegistry.Endpoint == "" {
+		return fmt.Errorf("schema_registry.endpoint is required by the %s encoding", cfg.Encoding)
+	}
 	return nil
 }
@@ -8,14 +8,17 @@ import (
 	"testing"
 	"time"
 
+	"github.com/cenkalti/backoff/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/confmap/confmaptest"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver/internal/metadata"
 )
 
@@ -60,6 +63,10 @@ func TestLoadConfig(t *testing.T) {
 					Enable:   true,
 					Interval: 1 * time.Second,
 				},
+				ErrorBackOff: newDefaultErrorBackOff(),
+				SchemaRegistry: schemaregistry.ClientConfig{
+					Timeout: 10 * time.Second,
+				},
 			},
 		},
 		{
@@ -92,6 +99,49 @@ func TestLoadConfig(t *testing.T) {
 					Enable:   true,
 					Interval: 1 * time.Second,
 				},
+				ErrorBackOff: configretry.BackOffConfig{
+					Enabled:             true,
+					InitialInterval:     time.Second,
+					RandomizationFactor: backoff.DefaultRandomizationFactor,
+					Multiplier:          backoff.DefaultMultiplier,
+					MaxInterval:         30 * time.Second,
+					MaxElapsedTime:      0,
+				},
+				DeadLetter: DeadLetter{
+					Topic: "logs-dlq",
+				},
+				SchemaRegistry: schemaregistry.ClientConfig{
+					Timeout: 10 * time.Second,
+				},
+			},
+		},
+		{
+			id: component.NewIDWithName(metadata.Type, "avro"),
+			expected: &Config{
+				Topic:         "orders",
+				Encoding:      "avro",
+				Brokers:       []string{"localhost:9092"},
+				ClientID:      "otel-collector",
+				GroupID:       "otel-collector",
+				InitialOffset: "latest",
+				Metadata: kafkaexporter.Metadata{
+					Full: true,
+					Retry: kafkaexporter.MetadataRetry{
+						Max:     3,
+						Backoff: time.Millisecond * 250,
+					},
+				},
+				AutoCommit: AutoCommit{
+					Enable:   true,
+					Interval: 1 * time.Second,
+				},
+				ErrorBackOff: newDefaultErrorBackOff(),
+				SchemaRegistry: schemaregistry.ClientConfig{
+					Endpoint: "http://localhost:8081",
+					Username: "jdoe",
+					Password: "pass",
+					Timeout:  5 * time.Second,
+				},
 			},
 		},
 	}
@@ -110,3 +160,12 @@ func TestLoadConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestValidate_schema_registry_endpoint(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Encoding = "protobuf"
+	assert.EqualError(t, component.ValidateConfig(cfg), "schema_registry.endpoint is required by the protobuf encoding")
+
+	cfg.SchemaRegistry.Endpoint = "http://localhost:8081"
+	assert.NoError(t, component.ValidateConfig(cfg))
+}
@@ -0,0 +1,169 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/cenkalti/backoff/v4"
+	"go.opencensus.io/stats"
+	"go.opencensus.io/tag"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+)
+
+// The headers added to the messages produced to the dead-letter topic.
+const (
+	deadLetterTopicHeader     = "otel.dead_letter.topic"
+	deadLetterPartitionHeader = "otel.dead_letter.partition"
+	deadLetterOffsetHeader    = "otel.dead_letter.offset"
+	deadLetterErrorHeader     = "otel.dead_letter.error"
+)
+
+// partitionPauser pauses the fetching of partitions, it's implemented by sarama.ConsumerGroup.
+type partitionPauser interface {
+	Pause(partitions map[string][]int32)
+	Resume(partitions map[string][]int32)
+}
+
+// messageRetrier retries the consumption of the messages failing with a retryable error, pausing
+// their partition while they're retried so that no more messages are fetched for it.
+// A nil messageRetrier doesn't retry.
+type messageRetrier struct {
+	id      component.ID
+	backOff configretry.BackOffConfig
+	pauser  partitionPauser
+	logger  *zap.Logger
+}
+
+func newMessageRetrier(id component.ID, backOff configretry.BackOffConfig, pauser partitionPauser, logger *zap.Logger) *messageRetrier {
+	if !backOff.Enabled {
+		return nil
+	}
+	return &messageRetrier{
+		id:      id,
+		backOff: backOff,
+		pauser:  pauser,
+		logger:  logger,
+	}
+}
+
+// consume calls the function consuming a message until it succeeds, returns a permanent error, or the
+// retries are exhausted. The last error is returned, or the error of the context once it's done.
+func (r *messageRetrier) consume(ctx context.Context, message *sarama.ConsumerMessage, consume func(context.Context) error) error {
+	err := consume(ctx)
+	if r == nil || err == nil || consumererror.IsPermanent(err) {
+		return err
+	}
+
+	partitions := map[string][]int32{message.Topic: {message.Partition}}
+	r.pauser.Pause(partitions)
+	defer r.pauser.Resume(partitions)
+
+	expBackOff := backoff.ExponentialBackOff{
+		InitialInterval:     r.backOff.InitialInterval,
+		RandomizationFactor: r.backOff.RandomizationFactor,
+		Multiplier:          r.backOff.Multiplier,
+		MaxInterval:         r.backOff.MaxInterval,
+		MaxElapsedTime:      r.backOff.MaxElapsedTime,
+		Stop:                backoff.Stop,
+		Clock:               backoff.SystemClock,
+	}
+	expBackOff.Reset()
+	statsTags := []tag.Mutator{
+		tag.Upsert(tagInstanceName, r.id.String()),
+		tag.Upsert(tagPartition, strconv.Itoa(int(message.Partition))),
+	}
+	for {
+		delay := expBackOff.NextBackOff()
+		if delay == backoff.Stop {
+			return err
+		}
+		r.logger.Warn("Failed to consume the message, retrying",
+			zap.Error(err),
+			zap.String("topic", message.Topic),
+			zap.Int32("partition", message.Partition),
+			zap.Int64("offset", message.Offset),
+			zap.Duration("interval", delay))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		_ = stats.RecordWithTags(ctx, statsTags, statMessageRetries.M(1))
+		if err = consume(ctx); err == nil || consumererror.IsPermanent(err) {
+			return err
+		}
+	}
+}
+
+// unmarshalError returns the error of a message failing to be unmarshaled, which is retried when it's temporary.
+func unmarshalError(err error) error {
+	if isTemporaryUnmarshalError(err) {
+		return err
+	}
+	return consumererror.NewPermanent(err)
+}
+
+// isTemporaryUnmarshalError returns whether the message may be unmarshaled when retried, which is the case
+// when the schema registry resolving its schema failed to respond.
+func isTemporaryUnmarshalError(err error) bool {
+	return schemaregistry.IsTemporary(err)
+}
+
+// deadLetterProducer produces the messages failing to be unmarshaled with a permanent error to the dead-letter topic.
+type deadLetterProducer struct {
+	topic    string
+	producer sarama.SyncProducer
+}
+
+// send produces a message to the dead-letter topic, with the headers of the message followed by
+// the headers describing where it was consumed from and the error it failed with.
+func (d *deadLetterProducer) send(message *sarama.ConsumerMessage, cause error) error {
+	headers := make([]sarama.RecordHeader, 0, len(message.Headers)+4)
+	for _, header := range message.Headers {
+		if header != nil {
+			headers = append(headers, *header)
+		}
+	}
+	headers = append(headers,
+		sarama.RecordHeader{Key: []byte(deadLetterTopicHeader), Value: []byte(message.Topic)},
+		sarama.RecordHeader{Key: []byte(deadLetterPartitionHeader), Value: []byte(strconv.Itoa(int(message.Partition)))},
+		sarama.RecordHeader{Key: []byte(deadLetterOffsetHeader), Value: []byte(strconv.FormatInt(message.Offset, 10))},
+		sarama.RecordHeader{Key: []byte(deadLetterErrorHeader), Value: []byte(cause.Error())},
+	)
+
+	msg := &sarama.ProducerMessage{
+		Topic:   d.topic,
+		Value:   sarama.ByteEncoder(message.Value),
+		Headers: headers,
+	}
+	// the messages without key are produced without key, rather than with an empty one
+	if message.Key != nil {
+		msg.Key = sarama.ByteEncoder(message.Key)
+	}
+	if _, _, err := d.producer.SendMessage(msg); err != nil {
+		return fmt.Errorf("failed to produce the message to the dead-letter topic %q: %w", d.topic, err)
+	}
+	return nil
+}
+
+func (d *deadLetterProducer) close() error {
+	if d == nil {
+		return nil
+	}
+	return d.producer.Close()
+}
@@ -0,0 +1,337 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/IBM/sarama"
+	"github.com/IBM/sarama/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opencensus.io/stats/view"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+)
+
+type testPauser struct {
+	mu      sync.Mutex
+	paused  []map[string][]int32
+	resumed []map[string][]int32
+}
+
+func (p *testPauser) Pause(partitions map[string][]int32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = append(p.paused, partitions)
+}
+
+func (p *testPauser) Resume(partitions map[string][]int32) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.resumed = append(p.resumed, partitions)
+}
+
+func newTestErrorBackOff() configretry.BackOffConfig {
+	return configretry.BackOffConfig{
+		Enabled:         true,
+		InitialInterval: time.Millisecond,
+		Multiplier:      1,
+		MaxInterval:     time.Millisecond,
+		MaxElapsedTime:  time.Minute,
+	}
+}
+
+func TestMessageRetrier(t *testing.T) {
+	pauser := &testPauser{}
+	r := newMessageRetrier(receivertest.NewNopSettings().ID, newTestErrorBackOff(), pauser, zap.NewNop())
+	message := &sarama.ConsumerMessage{Topic: "logs", Partition: 3}
+
+	calls := 0
+	err := r.consume(context.Background(), message, func(context.Context) error {
+		calls++
+		if calls < 3 {
+			return errors.New("exporter unavailable")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	// the partition is paused once while the message is retried
+	assert.Equal(t, []map[string][]int32{{"logs": {3}}}, pauser.paused)
+	assert.Equal(t, pauser.paused, pauser.resumed)
+}
+
+func TestMessageRetrier_permanent_error(t *testing.T) {
+	pauser := &testPauser{}
+	r := newMessageRetrier(receivertest.NewNopSettings().ID, newTestErrorBackOff(), pauser, zap.NewNop())
+
+	calls := 0
+	expectedErr := consumererror.NewPermanent(errors.New("invalid data"))
+	err := r.consume(context.Background(), &sarama.ConsumerMessage{}, func(context.Context) error {
+		calls++
+		return expectedErr
+	})
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, 1, calls)
+	assert.Empty(t, pauser.paused)
+}
+
+func TestMessageRetrier_exhausted(t *testing.T) {
+	pauser := &testPauser{}
+	backOff := newTestErrorBackOff()
+	backOff.MaxElapsedTime = 20 * time.Millisecond
+	r := newMessageRetrier(receivertest.NewNopSettings().ID, backOff, pauser, zap.NewNop())
+
+	calls := 0
+	expectedErr := errors.New("exporter unavailable")
+	err := r.consume(context.Background(), &sarama.ConsumerMessage{}, func(context.Context) error {
+		calls++
+		return expectedErr
+	})
+	assert.Equal(t, expectedErr, err)
+	assert.Greater(t, calls, 1)
+	assert.Len(t, pauser.resumed, 1)
+}
+
+func TestMessageRetrier_context_done(t *testing.T) {
+	pauser := &testPauser{}
+	backOff := newTestErrorBackOff()
+	backOff.InitialInterval = time.Hour
+	backOff.MaxInterval = time.Hour
+	backOff.MaxElapsedTime = 0
+	r := newMessageRetrier(receivertest.NewNopSettings().ID, backOff, pauser, zap.NewNop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := r.consume(ctx, &sarama.ConsumerMessage{}, func(context.Context) error {
+		cancel()
+		return errors.New("exporter unavailable")
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Len(t, pauser.resumed, 1)
+}
+
+func TestMessageRetrier_disabled(t *testing.T) {
+	r := newMessageRetrier(receivertest.NewNopSettings().ID, newDefaultErrorBackOff(), &testPauser{}, zap.NewNop())
+	require.Nil(t, r)
+
+	calls := 0
+	expectedErr := errors.New("exporter unavailable")
+	err := r.consume(context.Background(), &sarama.ConsumerMessage{}, func(context.Context) error {
+		calls++
+		return expectedErr
+	})
+	assert.Equal(t, expectedErr, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestDeadLetterProducer_send(t *testing.T) {
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageWithMessageCheckerFunctionAndSucceed(func(msg *sarama.ProducerMessage) error {
+		assert.Equal(t, "logs-dlq", msg.Topic)
+		assert.Equal(t, sarama.ByteEncoder("key"), msg.Key)
+		assert.Equal(t, sarama.ByteEncoder("invalid"), msg.Value)
+		assert.Equal(t, []sarama.RecordHeader{
+			{Key: []byte("source"), Value: []byte("app")},
+			{Key: []byte(deadLetterTopicHeader), Value: []byte("logs")},
+			{Key: []byte(deadLetterPartitionHeader), Value: []byte("3")},
+			{Key: []byte(deadLetterOffsetHeader), Value: []byte("42")},
+			{Key: []byte(deadLetterErrorHeader), Value: []byte("unmarshal failed")},
+		}, msg.Headers)
+		return nil
+	})
+	producer.ExpectSendMessageAndFail(sarama.ErrOutOfBrokers)
+	d := &deadLetterProducer{topic: "logs-dlq", producer: producer}
+
+	message := &sarama.ConsumerMessage{
+		Topic:     "logs",
+		Partition: 3,
+		Offset:    42,
+		Key:       []byte("key"),
+		Value:     []byte("invalid"),
+		Headers:   []*sarama.RecordHeader{{Key: []byte("source"), Value: []byte("app")}},
+	}
+	require.NoError(t, d.send(message, errors.New("unmarshal failed")))
+	assert.ErrorIs(t, d.send(message, errors.New("unmarshal failed")), sarama.ErrOutOfBrokers)
+	require.NoError(t, d.close())
+}
+
+func TestLogsConsumerGroupHandler_dead_letter(t *testing.T) {
+	view.Unregister(metricViews()...)
+	views := metricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	producer := mocks.NewSyncProducer(t, nil)
+	producer.ExpectSendMessageWithCheckerFunctionAndSucceed(func(val []byte) error {
+		assert.Equal(t, []byte("invalid"), val)
+		return nil
+	})
+	defer func() { require.NoError(t, producer.Close()) }()
+
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{ReceiverCreateSettings: receivertest.NewNopSettings()})
+	require.NoError(t, err)
+	sink := &consumertest.LogsSink{}
+	c := logsConsumerGroupHandler{
+		unmarshaler:     newPdataLogsUnmarshaler(&plog.ProtoUnmarshaler{}, defaultEncoding),
+		logger:          zap.NewNop(),
+		ready:           make(chan bool),
+		nextConsumer:    sink,
+		obsrecv:         obsrecv,
+		headerExtractor: &nopHeaderExtractor{},
+		deadLetter:      &deadLetterProducer{topic: "logs-dlq", producer: producer},
+	}
+
+	groupClaim := testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		// the consumption goes on after the message failing to be unmarshaled
+		require.NoError(t, c.ConsumeClaim(testConsumerGroupSession{ctx: context.Background()}, groupClaim))
+		wg.Done()
+	}()
+
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Value: []byte("invalid")}
+	groupClaim.messageChan <- &sarama.ConsumerMessage{}
+	close(groupClaim.messageChan)
+	wg.Wait()
+
+	assert.Len(t, sink.AllLogs(), 1)
+	viewData, err := view.RetrieveData(statDeadLetterMessages.Name())
+	require.NoError(t, err)
+	require.Len(t, viewData, 1)
+	assert.Equal(t, float64(1), viewData[0].Data.(*view.SumData).Value)
+}
+
+func TestLogsConsumerGroupHandler_retry(t *testing.T) {
+	view.Unregister(metricViews()...)
+	views := metricViews()
+	require.NoError(t, view.Register(views...))
+	defer view.Unregister(views...)
+
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{ReceiverCreateSettings: receivertest.NewNopSettings()})
+	require.NoError(t, err)
+	calls := 0
+	nextConsumer, err := consumer.NewLogs(func(_ context.Context, logs plog.Logs) error {
+		calls++
+		record := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+		// every attempt consumes the logs as they were unmarshaled
+		assert.Equal(t, "original", record.Body().Str())
+		if calls == 1 {
+			record.Body().SetStr("modified")
+			return errors.New("exporter unavailable")
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	pauser := &testPauser{}
+	c := logsConsumerGroupHandler{
+		unmarshaler:     newPdataLogsUnmarshaler(&plog.ProtoUnmarshaler{}, defaultEncoding),
+		logger:          zap.NewNop(),
+		ready:           make(chan bool),
+		nextConsumer:    nextConsumer,
+		obsrecv:         obsrecv,
+		headerExtractor: &nopHeaderExtractor{},
+		retrier:         newMessageRetrier(receivertest.NewNopSettings().ID, newTestErrorBackOff(), pauser, zap.NewNop()),
+	}
+
+	groupClaim := testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		require.NoError(t, c.ConsumeClaim(testConsumerGroupSession{ctx: context.Background()}, groupClaim))
+		wg.Done()
+	}()
+
+	logs := plog.NewLogs()
+	logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty().Body().SetStr("original")
+	value, err := (&plog.ProtoMarshaler{}).MarshalLogs(logs)
+	require.NoError(t, err)
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Topic: testTopic, Partition: testPartition, Value: value}
+	close(groupClaim.messageChan)
+	wg.Wait()
+
+	assert.Equal(t, 2, calls)
+	assert.Equal(t, []map[string][]int32{{testTopic: {testPartition}}}, pauser.paused)
+	viewData, err := view.RetrieveData(statMessageRetries.Name())
+	require.NoError(t, err)
+	require.Len(t, viewData, 1)
+	assert.Equal(t, float64(1), viewData[0].Data.(*view.SumData).Value)
+}
+
+// temporaryErrorLogsUnmarshaler fails to unmarshal the messages with a temporary error the first time.
+type temporaryErrorLogsUnmarshaler struct {
+	LogsUnmarshaler
+	calls int
+}
+
+func (u *temporaryErrorLogsUnmarshaler) Unmarshal(buf []byte) (plog.Logs, error) {
+	u.calls++
+	if u.calls == 1 {
+		return plog.NewLogs(), &schemaregistry.Error{StatusCode: http.StatusServiceUnavailable, Message: "Service Unavailable"}
+	}
+	return u.LogsUnmarshaler.Unmarshal(buf)
+}
+
+func TestLogsConsumerGroupHandler_temporary_unmarshal_error(t *testing.T) {
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{ReceiverCreateSettings: receivertest.NewNopSettings()})
+	require.NoError(t, err)
+	// the message isn't produced to the dead-letter topic
+	producer := mocks.NewSyncProducer(t, nil)
+	defer func() { require.NoError(t, producer.Close()) }()
+	sink := &consumertest.LogsSink{}
+	c := logsConsumerGroupHandler{
+		unmarshaler:     &temporaryErrorLogsUnmarshaler{LogsUnmarshaler: newPdataLogsUnmarshaler(&plog.ProtoUnmarshaler{}, defaultEncoding)},
+		logger:          zap.NewNop(),
+		ready:           make(chan bool),
+		nextConsumer:    sink,
+		obsrecv:         obsrecv,
+		headerExtractor: &nopHeaderExtractor{},
+		retrier:         newMessageRetrier(receivertest.NewNopSettings().ID, newTestErrorBackOff(), &testPauser{}, zap.NewNop()),
+		deadLetter:      &deadLetterProducer{topic: "logs-dlq", producer: producer},
+	}
+
+	groupClaim := testConsumerGroupClaim{
+		messageChan: make(chan *sarama.ConsumerMessage),
+	}
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		require.NoError(t, c.ConsumeClaim(testConsumerGroupSession{ctx: context.Background()}, groupClaim))
+		wg.Done()
+	}()
+
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Topic: testTopic, Partition: testPartition}
+	close(groupClaim.messageChan)
+	wg.Wait()
+
+	// the message is unmarshaled once the error is gone
+	assert.Len(t, sink.AllLogs(), 1)
+
+	// without retries, the temporary error fails the consumption
+	c.unmarshaler = &temporaryErrorLogsUnmarshaler{LogsUnmarshaler: newPdataLogsUnmarshaler(&plog.ProtoUnmarshaler{}, defaultEncoding)}
+	c.retrier = nil
+	groupClaim.messageChan = make(chan *sarama.ConsumerMessage, 1)
+	groupClaim.messageChan <- &sarama.ConsumerMessage{Topic: testTopic, Partition: testPartition}
+	err = c.ConsumeClaim(testConsumerGroupSession{ctx: context.Background()}, groupClaim)
+	assert.True(t, schemaregistry.IsTemporary(err))
+	assert.Len(t, sink.AllLogs(), 1)
+}
@@ -11,10 +11,12 @@ import (
 
 	"go.opencensus.io/stats/view"
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configretry"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/receiver"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/kafkaexporter"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver/internal/metadata"
 )
 
@@ -39,6 +41,9 @@ const (
 	defaultAutoCommitEnable = true
 	// default from sarama.NewConfig()
 	defaultAutoCommitInterval = 1 * time.Second
+
+	// default timeout of the requests to the schema registry
+	defaultSchemaRegistryTimeout = 10 * time.Second
 )
 
 var errUnrecognizedEncoding = fmt.Errorf("unrecognized encoding")
@@ -116,12 +121,23 @@ func createDefaultConfig() component.Config {
 			After:   false,
 			OnError: false,
 		},
+		ErrorBackOff: newDefaultErrorBackOff(),
 		HeaderExtraction: HeaderExtraction{
 			ExtractHeaders: false,
 		},
+		SchemaRegistry: schemaregistry.ClientConfig{
+			Timeout: defaultSchemaRegistryTimeout,
+		},
 	}
 }
 
+// newDefaultErrorBackOff returns the default retry settings, which are disabled.
+func newDefaultErrorBackOff() configretry.BackOffConfig {
+	backOff := configretry.NewDefaultBackOffConfig()
+	backOff.Enabled = false
+	return backOff
+}
+
 type kafkaReceiverFactory struct {
 	tracesUnmarshalers  map[string]TracesUnmarshaler
 	metricsUnmarshalers map[string]MetricsUnmarshaler
@@ -194,7 +210,13 @@ func (f *kafkaReceiverFactory) createLogsReceiver(
 	if oCfg.Topic == "" {
 		oCfg.Topic = defaultLogsTopic
 	}
-	unmarshaler, err := getLogsUnmarshaler(oCfg.Encoding, f.logsUnmarshalers)
+	var unmarshaler LogsUnmarshaler
+	var err error
+	if _, ok := f.logsUnmarshalers[oCfg.Encoding]; !ok && isSchemaRegistryEncoding(oCfg.Encoding) {
+		unmarshaler, err = newSchemaRegistryLogsUnmarshaler(oCfg.Encoding, oCfg.SchemaRegistry)
+	} else {
+		unmarshaler, err = getLogsUnmarshaler(oCfg.Encoding, f.logsUnmarshalers)
+	}
 	if err != nil {
 		return nil, err
 	}
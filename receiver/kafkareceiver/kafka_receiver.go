@@ -36,6 +36,7 @@ type kafkaTracesConsumer struct {
 	topics            []string
 	cancelConsumeLoop context.CancelFunc
 	unmarshaler       TracesUnmarshaler
+	deadLetter        *deadLetterProducer
 
 	settings receiver.Settings
 
@@ -53,6 +54,7 @@ type kafkaMetricsConsumer struct {
 	topics            []string
 	cancelConsumeLoop context.CancelFunc
 	unmarshaler       MetricsUnmarshaler
+	deadLetter        *deadLetterProducer
 
 	settings receiver.Settings
 
@@ -70,6 +72,7 @@ type kafkaLogsConsumer struct {
 	topics            []string
 	cancelConsumeLoop context.CancelFunc
 	unmarshaler       LogsUnmarshaler
+	deadLetter        *deadLetterProducer
 
 	settings receiver.Settings
 
@@ -102,17 +105,46 @@ func newTracesReceiver(config Config, set receiver.Settings, unmarshaler TracesU
 }
 
 func createKafkaClient(config Config) (sarama.ConsumerGroup, error) {
+	saramaConfig, err := newSaramaConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	saramaConfig.Consumer.Offsets.AutoCommit.Enable = config.AutoCommit.Enable
+	saramaConfig.Consumer.Offsets.AutoCommit.Interval = config.AutoCommit.Interval
+	if saramaConfig.Consumer.Offsets.Initial, err = toSaramaInitialOffset(config.InitialOffset); err != nil {
+		return nil, err
+	}
+	return sarama.NewConsumerGroup(config.Brokers, config.GroupID, saramaConfig)
+}
+
+// newDeadLetterProducer creates the producer of the messages to the dead-letter topic.
+func newDeadLetterProducer(config Config) (*deadLetterProducer, error) {
+	saramaConfig, err := newSaramaConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	// These setting are required by the sarama.SyncProducer implementation.
+	saramaConfig.Producer.Return.Successes = true
+	saramaConfig.Producer.Return.Errors = true
+	saramaConfig.Producer.RequiredAcks = sarama.WaitForAll
+	producer, err := sarama.NewSyncProducer(config.Brokers, saramaConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &deadLetterProducer{
+		topic:    config.DeadLetter.Topic,
+		producer: producer,
+	}, nil
+}
+
+// newSaramaConfig returns the settings of the sarama client shared by the consumer group and the producer.
+func newSaramaConfig(config Config) (*sarama.Config, error) {
 	saramaConfig := sarama.NewConfig()
 	saramaConfig.ClientID = config.ClientID
 	saramaConfig.Metadata.Full = config.Metadata.Full
 	saramaConfig.Metadata.Retry.Max = config.Metadata.Retry.Max
 	saramaConfig.Metadata.Retry.Backoff = config.Metadata.Retry.Backoff
-	saramaConfig.Consumer.Offsets.AutoCommit.Enable = config.AutoCommit.Enable
-	saramaConfig.Consumer.Offsets.AutoCommit.Interval = config.AutoCommit.Interval
 	var err error
-	if saramaConfig.Consumer.Offsets.Initial, err = toSaramaInitialOffset(config.InitialOffset); err != nil {
-		return nil, err
-	}
 	if config.ResolveCanonicalBootstrapServersOnly {
 		saramaConfig.Net.ResolveCanonicalBootstrapServers = true
 	}
@@ -124,7 +156,7 @@ func createKafkaClient(config Config) (sarama.ConsumerGroup, error) {
 	if err := kafka.ConfigureAuthentication(config.Authentication, saramaConfig); err != nil {
 		return nil, err
 	}
-	return sarama.NewConsumerGroup(config.Brokers, config.GroupID, saramaConfig)
+	return saramaConfig, nil
 }
 
 func (c *kafkaTracesConsumer) Start(_ context.Context, _ component.Host) error {
@@ -144,6 +176,12 @@ func (c *kafkaTracesConsumer) Start(_ context.Context, _ component.Host) error {
 			return err
 		}
 	}
+	// deadLetter may be set in tests to inject fake implementation.
+	if c.deadLetter == nil && c.config.DeadLetter.Topic != "" {
+		if c.deadLetter, err = newDeadLetterProducer(c.config); err != nil {
+			return err
+		}
+	}
 	consumerGroup := &tracesConsumerGroupHandler{
 		logger:            c.settings.Logger,
 		unmarshaler:       c.unmarshaler,
@@ -153,6 +191,8 @@ func (c *kafkaTracesConsumer) Start(_ context.Context, _ component.Host) error {
 		autocommitEnabled: c.autocommitEnabled,
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
+		retrier:           newMessageRetrier(c.settings.ID, c.config.ErrorBackOff, c.consumerGroup, c.settings.Logger),
+		deadLetter:        c.deadLetter,
 	}
 	if c.headerExtraction {
 		consumerGroup.headerExtractor = &headerExtractor{
@@ -191,9 +231,9 @@ func (c *kafkaTracesConsumer) Shutdown(context.Context) error {
 	}
 	c.cancelConsumeLoop()
 	if c.consumerGroup == nil {
-		return nil
+		return c.deadLetter.close()
 	}
-	return c.consumerGroup.Close()
+	return errors.Join(c.consumerGroup.Close(), c.deadLetter.close())
 }
 
 func newMetricsReceiver(config Config, set receiver.Settings, unmarshaler MetricsUnmarshaler, nextConsumer consumer.Metrics) (*kafkaMetricsConsumer, error) {
@@ -231,6 +271,12 @@ func (c *kafkaMetricsConsumer) Start(_ context.Context, _ component.Host) error
 			return err
 		}
 	}
+	// deadLetter may be set in tests to inject fake implementation.
+	if c.deadLetter == nil && c.config.DeadLetter.Topic != "" {
+		if c.deadLetter, err = newDeadLetterProducer(c.config); err != nil {
+			return err
+		}
+	}
 	metricsConsumerGroup := &metricsConsumerGroupHandler{
 		logger:            c.settings.Logger,
 		unmarshaler:       c.unmarshaler,
@@ -240,6 +286,8 @@ func (c *kafkaMetricsConsumer) Start(_ context.Context, _ component.Host) error
 		autocommitEnabled: c.autocommitEnabled,
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
+		retrier:           newMessageRetrier(c.settings.ID, c.config.ErrorBackOff, c.consumerGroup, c.settings.Logger),
+		deadLetter:        c.deadLetter,
 	}
 	if c.headerExtraction {
 		metricsConsumerGroup.headerExtractor = &headerExtractor{
@@ -278,9 +326,9 @@ func (c *kafkaMetricsConsumer) Shutdown(context.Context) error {
 	}
 	c.cancelConsumeLoop()
 	if c.consumerGroup == nil {
-		return nil
+		return c.deadLetter.close()
 	}
-	return c.consumerGroup.Close()
+	return errors.Join(c.consumerGroup.Close(), c.deadLetter.close())
 }
 
 func newLogsReceiver(config Config, set receiver.Settings, unmarshaler LogsUnmarshaler, nextConsumer consumer.Logs) (*kafkaLogsConsumer, error) {
@@ -318,6 +366,12 @@ func (c *kafkaLogsConsumer) Start(_ context.Context, _ component.Host) error {
 			return err
 		}
 	}
+	// deadLetter may be set in tests to inject fake implementation.
+	if c.deadLetter == nil && c.config.DeadLetter.Topic != "" {
+		if c.deadLetter, err = newDeadLetterProducer(c.config); err != nil {
+			return err
+		}
+	}
 	logsConsumerGroup := &logsConsumerGroupHandler{
 		logger:            c.settings.Logger,
 		unmarshaler:       c.unmarshaler,
@@ -327,6 +381,8 @@ func (c *kafkaLogsConsumer) Start(_ context.Context, _ component.Host) error {
 		autocommitEnabled: c.autocommitEnabled,
 		messageMarking:    c.messageMarking,
 		headerExtractor:   &nopHeaderExtractor{},
+		retrier:           newMessageRetrier(c.settings.ID, c.config.ErrorBackOff, c.consumerGroup, c.settings.Logger),
+		deadLetter:        c.deadLetter,
 	}
 	if c.headerExtraction {
 		logsConsumerGroup.headerExtractor = &headerExtractor{
@@ -365,9 +421,9 @@ func (c *kafkaLogsConsumer) Shutdown(context.Context) error {
 	}
 	c.cancelConsumeLoop()
 	if c.consumerGroup == nil {
-		return nil
+		return c.deadLetter.close()
 	}
-	return c.consumerGroup.Close()
+	return errors.Join(c.consumerGroup.Close(), c.deadLetter.close())
 }
 
 type tracesConsumerGroupHandler struct {
@@ -384,6 +440,8 @@ type tracesConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	retrier           *messageRetrier
+	deadLetter        *deadLetterProducer
 }
 
 type metricsConsumerGroupHandler struct {
@@ -400,6 +458,8 @@ type metricsConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	retrier           *messageRetrier
+	deadLetter        *deadLetterProducer
 }
 
 type logsConsumerGroupHandler struct {
@@ -416,6 +476,8 @@ type logsConsumerGroupHandler struct {
 	autocommitEnabled bool
 	messageMarking    MessageMarking
 	headerExtractor   HeaderExtractor
+	retrier           *messageRetrier
+	deadLetter        *deadLetterProducer
 }
 
 var _ sarama.ConsumerGroupHandler = (*tracesConsumerGroupHandler)(nil)
@@ -466,24 +528,59 @@ func (c *tracesConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSe
 				statMessageOffset.M(message.Offset),
 				statMessageOffsetLag.M(claim.HighWaterMarkOffset()-message.Offset-1))
 
-			traces, err := c.unmarshaler.Unmarshal(message.Value)
-			if err != nil {
-				c.logger.Error("failed to unmarshal message", zap.Error(err))
+			// the traces are unmarshaled on every attempt, as the next consumers may have modified them
+			var (
+				spanCount    int
+				unmarshalErr error
+			)
+			err := c.retrier.consume(session.Context(), message, func(consumeCtx context.Context) error {
+				traces, err := c.unmarshaler.Unmarshal(message.Value)
+				unmarshalErr = err
+				if err != nil {
+					return unmarshalError(err)
+				}
+				c.headerExtractor.extractHeadersTraces(traces, message)
+				spanCount = traces.SpanCount()
+				return c.nextConsumer.ConsumeTraces(consumeCtx, traces)
+			})
+			if unmarshalErr != nil {
+				c.logger.Error("failed to unmarshal message", zap.Error(unmarshalErr))
 				_ = stats.RecordWithTags(
 					ctx,
 					[]tag.Mutator{tag.Upsert(tagInstanceName, c.id.String())},
 					statUnmarshalFailedSpans.M(1))
+				// the messages failing because of a temporary error, such as the schema registry being
+				// unreachable, aren't poison messages: they fail the consumption instead
+				if c.deadLetter != nil && !isTemporaryUnmarshalError(unmarshalErr) {
+					if err = c.deadLetter.send(message, unmarshalErr); err == nil {
+						_ = stats.RecordWithTags(
+							ctx,
+							[]tag.Mutator{tag.Upsert(tagInstanceName, c.id.String())},
+							statDeadLetterMessages.M(1))
+						if c.messageMarking.After {
+							session.MarkMessage(message, "")
+						}
+						if !c.autocommitEnabled {
+							session.Commit()
+						}
+						continue
+					}
+					c.logger.Error("failed to produce message to the dead-letter topic", zap.Error(err))
+				}
+				if c.retrier != nil && session.Context().Err() != nil {
+					return nil
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
-				return err
+				return unmarshalErr
 			}
-
-			c.headerExtractor.extractHeadersTraces(traces, message)
-			spanCount := traces.SpanCount()
-			err = c.nextConsumer.ConsumeTraces(session.Context(), traces)
 			c.obsrecv.EndTracesOp(ctx, c.unmarshaler.Encoding(), spanCount, err)
 			if err != nil {
+				// the session ended while the message was retried, it's consumed again by the next session
+				if c.retrier != nil && session.Context().Err() != nil {
+					return nil
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
@@ -549,24 +646,59 @@ func (c *metricsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupS
 				statMessageOffset.M(message.Offset),
 				statMessageOffsetLag.M(claim.HighWaterMarkOffset()-message.Offset-1))
 
-			metrics, err := c.unmarshaler.Unmarshal(message.Value)
-			if err != nil {
-				c.logger.Error("failed to unmarshal message", zap.Error(err))
+			// the metrics are unmarshaled on every attempt, as the next consumers may have modified them
+			var (
+				dataPointCount int
+				unmarshalErr   error
+			)
+			err := c.retrier.consume(session.Context(), message, func(consumeCtx context.Context) error {
+				metrics, err := c.unmarshaler.Unmarshal(message.Value)
+				unmarshalErr = err
+				if err != nil {
+					return unmarshalError(err)
+				}
+				c.headerExtractor.extractHeadersMetrics(metrics, message)
+				dataPointCount = metrics.DataPointCount()
+				return c.nextConsumer.ConsumeMetrics(consumeCtx, metrics)
+			})
+			if unmarshalErr != nil {
+				c.logger.Error("failed to unmarshal message", zap.Error(unmarshalErr))
 				_ = stats.RecordWithTags(
 					ctx,
 					[]tag.Mutator{tag.Upsert(tagInstanceName, c.id.String())},
 					statUnmarshalFailedMetricPoints.M(1))
+				// the messages failing because of a temporary error, such as the schema registry being
+				// unreachable, aren't poison messages: they fail the consumption instead
+				if c.deadLetter != nil && !isTemporaryUnmarshalError(unmarshalErr) {
+					if err = c.deadLetter.send(message, unmarshalErr); err == nil {
+						_ = stats.RecordWithTags(
+							ctx,
+							[]tag.Mutator{tag.Upsert(tagInstanceName, c.id.String())},
+							statDeadLetterMessages.M(1))
+						if c.messageMarking.After {
+							session.MarkMessage(message, "")
+						}
+						if !c.autocommitEnabled {
+							session.Commit()
+						}
+						continue
+					}
+					c.logger.Error("failed to produce message to the dead-letter topic", zap.Error(err))
+				}
+				if c.retrier != nil && session.Context().Err() != nil {
+					return nil
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
-				return err
+				return unmarshalErr
 			}
-			c.headerExtractor.extractHeadersMetrics(metrics, message)
-
-			dataPointCount := metrics.DataPointCount()
-			err = c.nextConsumer.ConsumeMetrics(session.Context(), metrics)
 			c.obsrecv.EndMetricsOp(ctx, c.unmarshaler.Encoding(), dataPointCount, err)
 			if err != nil {
+				// the session ended while the message was retried, it's consumed again by the next session
+				if c.retrier != nil && session.Context().Err() != nil {
+					return nil
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
@@ -638,23 +770,59 @@ func (c *logsConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSess
 				statMessageOffset.M(message.Offset),
 				statMessageOffsetLag.M(claim.HighWaterMarkOffset()-message.Offset-1))
 
-			logs, err := c.unmarshaler.Unmarshal(message.Value)
-			if err != nil {
-				c.logger.Error("failed to unmarshal message", zap.Error(err))
+			// the logs are unmarshaled on every attempt, as the next consumers may have modified them
+			var (
+				logRecordCount int
+				unmarshalErr   error
+			)
+			err := c.retrier.consume(session.Context(), message, func(consumeCtx context.Context) error {
+				logs, err := c.unmarshaler.Unmarshal(message.Value)
+				unmarshalErr = err
+				if err != nil {
+					return unmarshalError(err)
+				}
+				c.headerExtractor.extractHeadersLogs(logs, message)
+				logRecordCount = logs.LogRecordCount()
+				return c.nextConsumer.ConsumeLogs(consumeCtx, logs)
+			})
+			if unmarshalErr != nil {
+				c.logger.Error("failed to unmarshal message", zap.Error(unmarshalErr))
 				_ = stats.RecordWithTags(
 					ctx,
 					[]tag.Mutator{tag.Upsert(tagInstanceName, c.id.String())},
 					statUnmarshalFailedLogRecords.M(1))
+				// the messages failing because of a temporary error, such as the schema registry being
+				// unreachable, aren't poison messages: they fail the consumption instead
+				if c.deadLetter != nil && !isTemporaryUnmarshalError(unmarshalErr) {
+					if err = c.deadLetter.send(message, unmarshalErr); err == nil {
+						_ = stats.RecordWithTags(
+							ctx,
+							[]tag.Mutator{tag.Upsert(tagInstanceName, c.id.String())},
+							statDeadLetterMessages.M(1))
+						if c.messageMarking.After {
+							session.MarkMessage(message, "")
+						}
+						if !c.autocommitEnabled {
+							session.Commit()
+						}
+						continue
+					}
+					c.logger.Error("failed to produce message to the dead-letter topic", zap.Error(err))
+				}
+				if c.retrier != nil && session.Context().Err() != nil {
+					return nil
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
-				return err
+				return unmarshalErr
 			}
-			c.headerExtractor.extractHeadersLogs(logs, message)
-			logRecordCount := logs.LogRecordCount()
-			err = c.nextConsumer.ConsumeLogs(session.Context(), logs)
 			c.obsrecv.EndLogsOp(ctx, c.unmarshaler.Encoding(), logRecordCount, err)
 			if err != nil {
+				// the session ended while the message was retried, it's consumed again by the next session
+				if c.retrier != nil && session.Context().Err() != nil {
+					return nil
+				}
 				if c.messageMarking.After && c.messageMarking.OnError {
 					session.MarkMessage(message, "")
 				}
@@ -23,6 +23,9 @@ var (
 	statUnmarshalFailedMetricPoints = stats.Int64("kafka_receiver_unmarshal_failed_metric_points", "Number of metric points failed to be unmarshaled", stats.UnitDimensionless)
 	statUnmarshalFailedLogRecords   = stats.Int64("kafka_receiver_unmarshal_failed_log_records", "Number of log records failed to be unmarshaled", stats.UnitDimensionless)
 	statUnmarshalFailedSpans        = stats.Int64("kafka_receiver_unmarshal_failed_spans", "Number of spans failed to be unmarshaled", stats.UnitDimensionless)
+
+	statMessageRetries     = stats.Int64("kafka_receiver_message_retries", "Number of retries of messages whose consumption failed", stats.UnitDimensionless)
+	statDeadLetterMessages = stats.Int64("kafka_receiver_dead_letter_messages", "Number of messages produced to the dead-letter topic", stats.UnitDimensionless)
 )
 
 // metricViews return metric views for Kafka receiver.
@@ -94,6 +97,22 @@ func metricViews() []*view.View {
 		Aggregation: view.Sum(),
 	}
 
+	countMessageRetries := &view.View{
+		Name:        statMessageRetries.Name(),
+		Measure:     statMessageRetries,
+		Description: statMessageRetries.Description(),
+		TagKeys:     partitionSpecificTagKeys,
+		Aggregation: view.Sum(),
+	}
+
+	countDeadLetterMessages := &view.View{
+		Name:        statDeadLetterMessages.Name(),
+		Measure:     statDeadLetterMessages,
+		Description: statDeadLetterMessages.Description(),
+		TagKeys:     partitionAgnosticTagKeys,
+		Aggregation: view.Sum(),
+	}
+
 	return []*view.View{
 		countMessages,
 		lastValueOffset,
@@ -103,5 +122,7 @@ func metricViews() []*view.View {
 		countUnmarshalFailedMetricPoints,
 		countUnmarshalFailedLogRecords,
 		countUnmarshalFailedSpans,
+		countMessageRetries,
+		countDeadLetterMessages,
 	}
 }
@@ -25,6 +25,8 @@ func TestMetrics(t *testing.T) {
 		{name: "kafka_receiver_unmarshal_failed_metric_points", tagCount: 1},
 		{name: "kafka_receiver_unmarshal_failed_log_records", tagCount: 1},
 		{name: "kafka_receiver_unmarshal_failed_spans", tagCount: 1},
+		{name: "kafka_receiver_message_retries", tagCount: 2},
+		{name: "kafka_receiver_dead_letter_messages", tagCount: 1},
 	}
 
 	for i, expectedView := range viewNames {
@@ -0,0 +1,65 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kafkareceiver"
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+)
+
+// The encodings of the messages serialized with a schema of the schema registry.
+const (
+	avroEncoding     = "avro"
+	protobufEncoding = "protobuf"
+)
+
+func isSchemaRegistryEncoding(encoding string) bool {
+	return encoding == avroEncoding || encoding == protobufEncoding
+}
+
+// schemaRegistryLogsUnmarshaler unmarshals the messages in the wire format of the schema registry into a log
+// record, whose body is the JSON value of the message.
+type schemaRegistryLogsUnmarshaler struct {
+	encoding     string
+	deserializer *schemaregistry.Deserializer
+}
+
+func newSchemaRegistryLogsUnmarshaler(encoding string, cfg schemaregistry.ClientConfig) (LogsUnmarshaler, error) {
+	client, err := schemaregistry.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	schemaType := schemaregistry.SchemaTypeAvro
+	if encoding == protobufEncoding {
+		schemaType = schemaregistry.SchemaTypeProtobuf
+	}
+	return &schemaRegistryLogsUnmarshaler{
+		encoding:     encoding,
+		deserializer: schemaregistry.NewDeserializer(client, schemaType),
+	}, nil
+}
+
+func (r *schemaRegistryLogsUnmarshaler) Unmarshal(buf []byte) (plog.Logs, error) {
+	p := plog.NewLogs()
+	value, err := r.deserializer.Deserialize(context.Background(), buf)
+	if err != nil {
+		return p, err
+	}
+
+	logRecord := p.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty().LogRecords().AppendEmpty()
+	logRecord.SetObservedTimestamp(pcommon.NewTimestampFromTime(time.Now()))
+	if err := logRecord.Body().FromRaw(value); err != nil {
+		return p, err
+	}
+	return p, nil
+}
+
+func (r *schemaRegistryLogsUnmarshaler) Encoding() string {
+	return r.encoding
+}
@@ -0,0 +1,126 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package kafkareceiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/kafka/schemaregistry/schemaregistrytest"
+)
+
+const userSchema = `{
+  "type": "record",
+  "name": "User",
+  "fields": [
+    {"name": "name", "type": "string"},
+    {"name": "age", "type": "long"}
+  ]
+}`
+
+const userProto = `syntax = "proto3";
+package test;
+
+message User {
+  string name = 1;
+  int64 age = 2;
+}
+`
+
+func TestSchemaRegistryLogsUnmarshaler(t *testing.T) {
+	tests := []struct {
+		name       string
+		encoding   string
+		schemaType string
+		schema     string
+		expected   map[string]any
+	}{
+		{
+			name:       "avro",
+			encoding:   avroEncoding,
+			schemaType: schemaregistry.SchemaTypeAvro,
+			schema:     userSchema,
+			expected:   map[string]any{"name": "alice", "age": int64(42)},
+		},
+		{
+			name:       "protobuf",
+			encoding:   protobufEncoding,
+			schemaType: schemaregistry.SchemaTypeProtobuf,
+			schema:     userProto,
+			// the JSON mapping of Protobuf represents the 64 bits integers as strings
+			expected: map[string]any{"name": "alice", "age": "42"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry := schemaregistrytest.NewRegistry()
+			defer registry.Close()
+			cfg := schemaregistry.ClientConfig{Endpoint: registry.URL}
+
+			client, err := schemaregistry.NewClient(cfg)
+			require.NoError(t, err)
+			serializer := schemaregistry.NewSerializer(client, schemaregistry.SerializerConfig{
+				SchemaType:   tt.schemaType,
+				Schema:       tt.schema,
+				AutoRegister: true,
+			})
+			buf, err := serializer.Serialize(context.Background(), "users-value", map[string]any{"name": "alice", "age": 42})
+			require.NoError(t, err)
+
+			um, err := newSchemaRegistryLogsUnmarshaler(tt.encoding, cfg)
+			require.NoError(t, err)
+			assert.Equal(t, tt.encoding, um.Encoding())
+
+			logs, err := um.Unmarshal(buf)
+			require.NoError(t, err)
+			require.Equal(t, 1, logs.LogRecordCount())
+			lr := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+			assert.NotZero(t, lr.ObservedTimestamp())
+			assert.Equal(t, tt.expected, lr.Body().AsRaw())
+
+			// the message of another type of schema is rejected
+			other := avroEncoding
+			if tt.encoding == avroEncoding {
+				other = protobufEncoding
+			}
+			um, err = newSchemaRegistryLogsUnmarshaler(other, cfg)
+			require.NoError(t, err)
+			_, err = um.Unmarshal(buf)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSchemaRegistryLogsUnmarshaler_missing_magic_byte(t *testing.T) {
+	registry := schemaregistrytest.NewRegistry()
+	defer registry.Close()
+
+	um, err := newSchemaRegistryLogsUnmarshaler(avroEncoding, schemaregistry.ClientConfig{Endpoint: registry.URL})
+	require.NoError(t, err)
+	_, err = um.Unmarshal([]byte(`{"name": "alice"}`))
+	assert.Error(t, err)
+	// the registry isn't requested for the messages in another format
+	assert.Zero(t, registry.Requests())
+}
+
+func TestCreateLogsReceiver_schema_registry(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Brokers = []string{"invalid:9092"}
+	cfg.ProtocolVersion = "2.0.0"
+	cfg.Encoding = avroEncoding
+	cfg.SchemaRegistry.Endpoint = "http://localhost:8081"
+	f := kafkaReceiverFactory{logsUnmarshalers: defaultLogsUnmarshalers("Test Version", zap.NewNop())}
+	r, err := f.createLogsReceiver(context.Background(), receivertest.NewNopSettings(), cfg, nil)
+	require.NoError(t, err)
+	assert.Equal(t, avroEncoding, r.(*kafkaLogsConsumer).unmarshaler.Encoding())
+	// no available broker
+	require.Error(t, r.Start(context.Background(), componenttest.NewNopHost()))
+}
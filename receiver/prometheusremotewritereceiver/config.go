@@ -0,0 +1,39 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+)
+
+var errMissingEndpoint = errors.New("missing receiver server endpoint from config")
+
+// Config defines configuration for the Prometheus remote-write receiver.
+type Config struct {
+	confighttp.ServerConfig `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
+	// Path is the path on which remote-write requests are accepted.
+	Path string `mapstructure:"path"`
+	// MaxDecompressedSize is the maximum size in bytes of a request once decompressed. The requests
+	// whose decompressed size is larger are rejected before being decompressed.
+	MaxDecompressedSize int64 `mapstructure:"max_decompressed_size"`
+}
+
+var _ component.Config = (*Config)(nil)
+
+// Validate checks the receiver configuration is valid
+func (cfg *Config) Validate() error {
+	if cfg.Endpoint == "" {
+		return errMissingEndpoint
+	}
+	if cfg.Path == "" {
+		return errors.New("path cannot be empty")
+	}
+	if cfg.MaxDecompressedSize <= 0 {
+		return errors.New("max_decompressed_size must be greater than 0")
+	}
+	return nil
+}
@@ -0,0 +1,836 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	conventions "go.opentelemetry.io/collector/semconv/v1.25.0"
+
+	prometheustranslator "github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+const (
+	scopeName = "otelcol/prometheusremotewritereceiver"
+
+	bucketSuffix = "_bucket"
+	sumSuffix    = "_sum"
+	countSuffix  = "_count"
+	totalSuffix  = "_total"
+
+	quantileLabel = "quantile"
+
+	// customBucketsSchema is the schema of native histograms with custom buckets.
+	customBucketsSchema = -53
+	minSchema           = -4
+	maxSchema           = 8
+
+	// float64Exponents is the number of powers of 2 in the range of the float64 values, from 2^-1074 to 2^1024.
+	float64Exponents = 2098
+	// maxDenseBuckets bounds the number of buckets a native histogram is expanded to, whatever its schema.
+	maxDenseBuckets = 1 << 14
+)
+
+// metricMetadata is the metadata of a metric family.
+type metricMetadata struct {
+	typ  writev2.MetricType
+	help string
+	unit string
+}
+
+type writeStats struct {
+	samples    int
+	histograms int
+	exemplars  int
+}
+
+type label struct {
+	name  string
+	value string
+}
+
+type exemplar struct {
+	labels    []label
+	value     float64
+	timestamp int64
+}
+
+// series is a time series of either version of the protocol.
+type series struct {
+	name string
+	// labels are sorted by name, and include the name of the metric.
+	labels           []label
+	metadata         metricMetadata
+	samples          []writev2.Sample
+	histograms       []writev2.Histogram
+	exemplars        []exemplar
+	createdTimestamp int64
+}
+
+func (s *series) label(name string) (string, bool) {
+	for _, l := range s.labels {
+		if l.name == name {
+			return l.value, true
+		}
+	}
+	return "", false
+}
+
+// converter converts the time series of remote-write requests to metrics. The series sharing
+// the same job and instance labels are grouped into a resource, whose attributes are
+// completed by the labels of their target_info series.
+type converter struct {
+	metrics   pmetric.Metrics
+	resources map[resourceKey]*resourceMetrics
+	stats     writeStats
+}
+
+type resourceKey struct {
+	job      string
+	instance string
+}
+
+type resourceMetrics struct {
+	resource   pmetric.ResourceMetrics
+	metrics    pmetric.MetricSlice
+	families   map[familyKey]pmetric.Metric
+	histograms map[pointKey]*classicHistogram
+	summaries  map[pointKey]pmetric.SummaryDataPoint
+}
+
+type familyKey struct {
+	name string
+	typ  pmetric.MetricType
+}
+
+// pointKey identifies the data point of a classic histogram or summary, which is made of several series.
+type pointKey struct {
+	family     string
+	attributes string
+	timestamp  int64
+}
+
+type classicHistogram struct {
+	point    pmetric.HistogramDataPoint
+	buckets  []classicBucket
+	count    float64
+	hasCount bool
+}
+
+type classicBucket struct {
+	upperBound float64
+	cumulative float64
+}
+
+func newConverter() *converter {
+	return &converter{
+		metrics:   pmetric.NewMetrics(),
+		resources: make(map[resourceKey]*resourceMetrics),
+	}
+}
+
+// fromV1 converts a remote-write 1.0 request. The metadata of its series is looked up by their family name.
+func (c *converter) fromV1(req *prompb.WriteRequest, familyMetadata func(string) (metricMetadata, bool)) error {
+	all := make([]series, 0, len(req.Timeseries))
+	for i := range req.Timeseries {
+		ts := &req.Timeseries[i]
+		s := series{labels: make([]label, 0, len(ts.Labels))}
+		for _, l := range ts.Labels {
+			s.labels = append(s.labels, label{name: l.Name, value: l.Value})
+		}
+		sortLabels(s.labels)
+		s.name, _ = s.label(model.MetricNameLabel)
+		s.metadata = lookupMetadata(s.name, familyMetadata)
+		for _, sample := range ts.Samples {
+			s.samples = append(s.samples, writev2.Sample{Value: sample.Value, Timestamp: sample.Timestamp})
+		}
+		for j := range ts.Histograms {
+			s.histograms = append(s.histograms, histogramFromV1(&ts.Histograms[j]))
+		}
+		for _, e := range ts.Exemplars {
+			ex := exemplar{value: e.Value, timestamp: e.Timestamp}
+			for _, l := range e.Labels {
+				ex.labels = append(ex.labels, label{name: l.Name, value: l.Value})
+			}
+			s.exemplars = append(s.exemplars, ex)
+		}
+		all = append(all, s)
+	}
+	return c.add(all)
+}
+
+// fromV2 converts a remote-write 2.0 request.
+func (c *converter) fromV2(req *writev2.Request) error {
+	all := make([]series, 0, len(req.Timeseries))
+	for i := range req.Timeseries {
+		ts := &req.Timeseries[i]
+		s := series{
+			samples:          ts.Samples,
+			histograms:       ts.Histograms,
+			createdTimestamp: ts.CreatedTimestamp,
+		}
+		err := req.Labels(ts.LabelsRefs, func(name, value string) {
+			s.labels = append(s.labels, label{name: name, value: value})
+		})
+		if err != nil {
+			return fmt.Errorf("time series %d: %w", i, err)
+		}
+		sortLabels(s.labels)
+		s.name, _ = s.label(model.MetricNameLabel)
+
+		s.metadata.typ = ts.Metadata.Type
+		if s.metadata.help, err = req.Symbol(ts.Metadata.HelpRef); err != nil {
+			return fmt.Errorf("time series %d: %w", i, err)
+		}
+		if s.metadata.unit, err = req.Symbol(ts.Metadata.UnitRef); err != nil {
+			return fmt.Errorf("time series %d: %w", i, err)
+		}
+
+		for _, e := range ts.Exemplars {
+			ex := exemplar{value: e.Value, timestamp: e.Timestamp}
+			err = req.Labels(e.LabelsRefs, func(name, value string) {
+				ex.labels = append(ex.labels, label{name: name, value: value})
+			})
+			if err != nil {
+				return fmt.Errorf("time series %d: exemplar: %w", i, err)
+			}
+			s.exemplars = append(s.exemplars, ex)
+		}
+		all = append(all, s)
+	}
+	return c.add(all)
+}
+
+// lookupMetadata returns the metadata of the family of the series. The families of counters,
+// histograms and summaries may be named after the series without its suffix.
+func lookupMetadata(name string, familyMetadata func(string) (metricMetadata, bool)) metricMetadata {
+	if m, ok := familyMetadata(name); ok {
+		return m
+	}
+	for suffix, types := range map[string][]writev2.MetricType{
+		totalSuffix:  {writev2.MetricTypeCounter},
+		bucketSuffix: {writev2.MetricTypeHistogram, writev2.MetricTypeGaugeHistogram},
+		sumSuffix:    {writev2.MetricTypeHistogram, writev2.MetricTypeGaugeHistogram, writev2.MetricTypeSummary},
+		countSuffix:  {writev2.MetricTypeHistogram, writev2.MetricTypeGaugeHistogram, writev2.MetricTypeSummary},
+	} {
+		family, ok := strings.CutSuffix(name, suffix)
+		if !ok {
+			continue
+		}
+		if m, ok := familyMetadata(family); ok {
+			for _, typ := range types {
+				if m.typ == typ {
+					return m
+				}
+			}
+		}
+	}
+	return metricMetadata{}
+}
+
+func (c *converter) add(all []series) error {
+	// A classic histogram or summary is made of several series, so the type of its family
+	// must be known before any of them is converted.
+	families := classicFamilies(all)
+	for i := range all {
+		if err := c.addSeries(&all[i], families); err != nil {
+			return fmt.Errorf("time series %q: %w", all[i].name, err)
+		}
+	}
+	return nil
+}
+
+// classicFamilies returns the type of the families of classic histograms and summaries, identified
+// by their metadata or, if it is unknown, by the le label of the buckets and the quantile label.
+func classicFamilies(all []series) map[string]writev2.MetricType {
+	families := make(map[string]writev2.MetricType)
+	for i := range all {
+		s := &all[i]
+		if len(s.samples) == 0 {
+			continue
+		}
+		switch s.metadata.typ {
+		case writev2.MetricTypeHistogram, writev2.MetricTypeGaugeHistogram:
+			families[trimClassicSuffix(s.name)] = writev2.MetricTypeHistogram
+		case writev2.MetricTypeSummary:
+			families[trimClassicSuffix(s.name)] = writev2.MetricTypeSummary
+		case writev2.MetricTypeUnspecified:
+			if _, ok := s.label(model.BucketLabel); ok && strings.HasSuffix(s.name, bucketSuffix) {
+				families[strings.TrimSuffix(s.name, bucketSuffix)] = writev2.MetricTypeHistogram
+			} else if _, ok := s.label(quantileLabel); ok {
+				families[s.name] = writev2.MetricTypeSummary
+			}
+		}
+	}
+	return families
+}
+
+func trimClassicSuffix(name string) string {
+	for _, suffix := range []string{bucketSuffix, sumSuffix, countSuffix} {
+		if family, ok := strings.CutSuffix(name, suffix); ok {
+			return family
+		}
+	}
+	return name
+}
+
+// classicRole returns the family of the classic histogram or summary the series belongs to,
+// and the suffix telling which part of the data points it holds.
+func classicRole(s *series, families map[string]writev2.MetricType) (string, writev2.MetricType, string) {
+	for _, suffix := range []string{bucketSuffix, sumSuffix, countSuffix} {
+		family, ok := strings.CutSuffix(s.name, suffix)
+		if !ok {
+			continue
+		}
+		typ, ok := families[family]
+		if !ok {
+			continue
+		}
+		if suffix == bucketSuffix {
+			if _, hasBound := s.label(model.BucketLabel); typ != writev2.MetricTypeHistogram || !hasBound {
+				continue
+			}
+		}
+		return family, typ, suffix
+	}
+	if typ, ok := families[s.name]; ok && typ == writev2.MetricTypeSummary {
+		if _, ok := s.label(quantileLabel); ok {
+			return s.name, typ, ""
+		}
+	}
+	return "", writev2.MetricTypeUnspecified, ""
+}
+
+func (c *converter) addSeries(s *series, families map[string]writev2.MetricType) error {
+	c.stats.samples += len(s.samples)
+	c.stats.histograms += len(s.histograms)
+	c.stats.exemplars += len(s.exemplars)
+
+	job, _ := s.label(model.JobLabel)
+	instance, _ := s.label(model.InstanceLabel)
+	rm := c.resourceMetrics(job, instance)
+	if s.name == prometheustranslator.TargetInfoMetricName {
+		attrs := rm.resource.Resource().Attributes()
+		for _, l := range s.labels {
+			if !isIdentifyingLabel(l.name) {
+				attrs.PutStr(l.name, l.value)
+			}
+		}
+		return nil
+	}
+
+	if len(s.histograms) > 0 {
+		return rm.addNativeHistograms(s)
+	}
+	if family, typ, suffix := classicRole(s, families); family != "" {
+		if typ == writev2.MetricTypeHistogram {
+			return rm.addClassicHistogram(s, family, suffix)
+		}
+		return rm.addSummary(s, family, suffix)
+	}
+	rm.addNumbers(s)
+	return nil
+}
+
+func (c *converter) resourceMetrics(job, instance string) *resourceMetrics {
+	key := resourceKey{job: job, instance: instance}
+	if rm, ok := c.resources[key]; ok {
+		return rm
+	}
+	resource := c.metrics.ResourceMetrics().AppendEmpty()
+	attrs := resource.Resource().Attributes()
+	if job != "" {
+		if namespace, name, ok := strings.Cut(job, "/"); ok {
+			attrs.PutStr(conventions.AttributeServiceNamespace, namespace)
+			attrs.PutStr(conventions.AttributeServiceName, name)
+		} else {
+			attrs.PutStr(conventions.AttributeServiceName, job)
+		}
+	}
+	if instance != "" {
+		attrs.PutStr(conventions.AttributeServiceInstanceID, instance)
+	}
+	scope := resource.ScopeMetrics().AppendEmpty()
+	scope.Scope().SetName(scopeName)
+	rm := &resourceMetrics{
+		resource:   resource,
+		metrics:    scope.Metrics(),
+		families:   make(map[familyKey]pmetric.Metric),
+		histograms: make(map[pointKey]*classicHistogram),
+		summaries:  make(map[pointKey]pmetric.SummaryDataPoint),
+	}
+	c.resources[key] = rm
+	return rm
+}
+
+// finish completes the classic histograms and returns the metrics, without the resources whose
+// only series was target_info.
+func (c *converter) finish() (pmetric.Metrics, writeStats) {
+	for _, rm := range c.resources {
+		for _, h := range rm.histograms {
+			h.finish()
+		}
+		for _, dp := range rm.summaries {
+			dp.QuantileValues().Sort(func(a, b pmetric.SummaryDataPointValueAtQuantile) bool {
+				return a.Quantile() < b.Quantile()
+			})
+		}
+	}
+	c.metrics.ResourceMetrics().RemoveIf(func(rm pmetric.ResourceMetrics) bool {
+		return rm.ScopeMetrics().At(0).Metrics().Len() == 0
+	})
+	return c.metrics, c.stats
+}
+
+// metric returns the metric of the family, creating it if needed.
+func (rm *resourceMetrics) metric(name string, typ pmetric.MetricType, md metricMetadata) pmetric.Metric {
+	key := familyKey{name: name, typ: typ}
+	if m, ok := rm.families[key]; ok {
+		return m
+	}
+	m := rm.metrics.AppendEmpty()
+	m.SetName(name)
+	m.SetDescription(md.help)
+	m.SetUnit(md.unit)
+	switch typ {
+	case pmetric.MetricTypeGauge:
+		m.SetEmptyGauge()
+	case pmetric.MetricTypeSum:
+		sum := m.SetEmptySum()
+		sum.SetIsMonotonic(true)
+		sum.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	case pmetric.MetricTypeHistogram:
+		m.SetEmptyHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	case pmetric.MetricTypeExponentialHistogram:
+		m.SetEmptyExponentialHistogram().SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	case pmetric.MetricTypeSummary:
+		m.SetEmptySummary()
+	}
+	rm.families[key] = m
+	return m
+}
+
+func (rm *resourceMetrics) addNumbers(s *series) {
+	var points pmetric.NumberDataPointSlice
+	typ := s.metadata.typ
+	if typ == writev2.MetricTypeUnspecified && strings.HasSuffix(s.name, totalSuffix) {
+		typ = writev2.MetricTypeCounter
+	}
+	if typ == writev2.MetricTypeCounter {
+		points = rm.metric(s.name, pmetric.MetricTypeSum, s.metadata).Sum().DataPoints()
+	} else {
+		points = rm.metric(s.name, pmetric.MetricTypeGauge, s.metadata).Gauge().DataPoints()
+	}
+
+	var dp pmetric.NumberDataPoint
+	for _, sample := range s.samples {
+		dp = points.AppendEmpty()
+		setAttributes(dp.Attributes(), s.labels)
+		dp.SetTimestamp(timestampFromMs(sample.Timestamp))
+		if typ == writev2.MetricTypeCounter {
+			dp.SetStartTimestamp(timestampFromMs(s.createdTimestamp))
+		}
+		if value.IsStaleNaN(sample.Value) {
+			dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+		} else {
+			dp.SetDoubleValue(sample.Value)
+		}
+	}
+	if len(s.samples) > 0 {
+		addExemplars(dp.Exemplars(), s.exemplars)
+	}
+}
+
+func (rm *resourceMetrics) addClassicHistogram(s *series, family string, suffix string) error {
+	var bound float64
+	if suffix == bucketSuffix {
+		le, _ := s.label(model.BucketLabel)
+		var err error
+		if bound, err = strconv.ParseFloat(le, 64); err != nil {
+			return fmt.Errorf("invalid %s label %q: %w", model.BucketLabel, le, err)
+		}
+	}
+
+	attributes := attributesKey(s.labels)
+	var h *classicHistogram
+	for _, sample := range s.samples {
+		key := pointKey{family: family, attributes: attributes, timestamp: sample.Timestamp}
+		var ok bool
+		if h, ok = rm.histograms[key]; !ok {
+			dp := rm.metric(family, pmetric.MetricTypeHistogram, s.metadata).Histogram().DataPoints().AppendEmpty()
+			setAttributes(dp.Attributes(), s.labels)
+			dp.SetTimestamp(timestampFromMs(sample.Timestamp))
+			dp.SetStartTimestamp(timestampFromMs(s.createdTimestamp))
+			h = &classicHistogram{point: dp}
+			rm.histograms[key] = h
+		}
+		if value.IsStaleNaN(sample.Value) {
+			h.point.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+			continue
+		}
+		switch suffix {
+		case bucketSuffix:
+			h.buckets = append(h.buckets, classicBucket{upperBound: bound, cumulative: sample.Value})
+		case sumSuffix:
+			h.point.SetSum(sample.Value)
+		case countSuffix:
+			h.count, h.hasCount = sample.Value, true
+		}
+	}
+	if h != nil {
+		addExemplars(h.point.Exemplars(), s.exemplars)
+	}
+	return nil
+}
+
+// finish sets the buckets of the data point from the cumulative counts of the series.
+func (h *classicHistogram) finish() {
+	if h.point.Flags().NoRecordedValue() {
+		h.point.RemoveSum()
+		return
+	}
+	sort.Slice(h.buckets, func(i, j int) bool {
+		return h.buckets[i].upperBound < h.buckets[j].upperBound
+	})
+	if len(h.buckets) == 0 || !math.IsInf(h.buckets[len(h.buckets)-1].upperBound, 1) {
+		// the +Inf bucket holds all the observations
+		h.buckets = append(h.buckets, classicBucket{upperBound: math.Inf(1), cumulative: h.count})
+	}
+	count := h.buckets[len(h.buckets)-1].cumulative
+	if h.hasCount {
+		count = h.count
+	}
+	h.point.SetCount(countFromFloat(count))
+
+	bounds := make([]float64, 0, len(h.buckets)-1)
+	counts := make([]uint64, 0, len(h.buckets))
+	var previous float64
+	for i, b := range h.buckets {
+		if i < len(h.buckets)-1 {
+			bounds = append(bounds, b.upperBound)
+		}
+		counts = append(counts, countFromFloat(b.cumulative-previous))
+		previous = b.cumulative
+	}
+	h.point.ExplicitBounds().FromRaw(bounds)
+	h.point.BucketCounts().FromRaw(counts)
+}
+
+func (rm *resourceMetrics) addSummary(s *series, family string, suffix string) error {
+	var quantile float64
+	if suffix == "" {
+		q, _ := s.label(quantileLabel)
+		var err error
+		if quantile, err = strconv.ParseFloat(q, 64); err != nil {
+			return fmt.Errorf("invalid %s label %q: %w", quantileLabel, q, err)
+		}
+	}
+
+	attributes := attributesKey(s.labels)
+	for _, sample := range s.samples {
+		key := pointKey{family: family, attributes: attributes, timestamp: sample.Timestamp}
+		dp, ok := rm.summaries[key]
+		if !ok {
+			dp = rm.metric(family, pmetric.MetricTypeSummary, s.metadata).Summary().DataPoints().AppendEmpty()
+			setAttributes(dp.Attributes(), s.labels)
+			dp.SetTimestamp(timestampFromMs(sample.Timestamp))
+			dp.SetStartTimestamp(timestampFromMs(s.createdTimestamp))
+			rm.summaries[key] = dp
+		}
+		if value.IsStaleNaN(sample.Value) {
+			dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+			continue
+		}
+		switch suffix {
+		case "":
+			q := dp.QuantileValues().AppendEmpty()
+			q.SetQuantile(quantile)
+			q.SetValue(sample.Value)
+		case sumSuffix:
+			dp.SetSum(sample.Value)
+		case countSuffix:
+			dp.SetCount(countFromFloat(sample.Value))
+		}
+	}
+	return nil
+}
+
+func (rm *resourceMetrics) addNativeHistograms(s *series) error {
+	var exemplars pmetric.ExemplarSlice
+	for i := range s.histograms {
+		h := &s.histograms[i]
+		var err error
+		if h.Schema == customBucketsSchema {
+			exemplars, err = rm.addCustomBucketsHistogram(s, h)
+		} else {
+			exemplars, err = rm.addExponentialHistogram(s, h)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	addExemplars(exemplars, s.exemplars)
+	return nil
+}
+
+func (rm *resourceMetrics) addExponentialHistogram(s *series, h *writev2.Histogram) (pmetric.ExemplarSlice, error) {
+	if h.Schema < minSchema || h.Schema > maxSchema {
+		return pmetric.ExemplarSlice{}, fmt.Errorf("unsupported native histogram schema %d", h.Schema)
+	}
+	dp := rm.metric(s.name, pmetric.MetricTypeExponentialHistogram, s.metadata).ExponentialHistogram().DataPoints().AppendEmpty()
+	setAttributes(dp.Attributes(), s.labels)
+	dp.SetTimestamp(timestampFromMs(h.Timestamp))
+	dp.SetStartTimestamp(timestampFromMs(s.createdTimestamp))
+	if value.IsStaleNaN(h.Sum) {
+		dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+		return dp.Exemplars(), nil
+	}
+
+	// The scale of exponential histograms has the same meaning as the schema of native histograms.
+	dp.SetScale(h.Schema)
+	dp.SetSum(h.Sum)
+	dp.SetZeroThreshold(h.ZeroThreshold)
+	if h.Float {
+		dp.SetCount(countFromFloat(h.CountFloat))
+		dp.SetZeroCount(countFromFloat(h.ZeroCountFloat))
+	} else {
+		dp.SetCount(h.CountInt)
+		dp.SetZeroCount(h.ZeroCountInt)
+	}
+	for _, buckets := range []struct {
+		dst    pmetric.ExponentialHistogramDataPointBuckets
+		spans  []writev2.BucketSpan
+		deltas []int64
+		counts []float64
+	}{
+		{dp.Positive(), h.PositiveSpans, h.PositiveDeltas, h.PositiveCounts},
+		{dp.Negative(), h.NegativeSpans, h.NegativeDeltas, h.NegativeCounts},
+	} {
+		first, counts, err := denseBuckets(buckets.spans, buckets.deltas, buckets.counts, h.Float, bucketsLimit(h.Schema))
+		if err != nil {
+			return pmetric.ExemplarSlice{}, err
+		}
+		// The bucket of index i of a native histogram is (base^(i-1), base^i], while the
+		// bucket of index i of an exponential histogram is (base^i, base^(i+1)].
+		if len(counts) > 0 {
+			buckets.dst.SetOffset(first - 1)
+		}
+		buckets.dst.BucketCounts().FromRaw(counts)
+	}
+	return dp.Exemplars(), nil
+}
+
+// addCustomBucketsHistogram converts a native histogram with custom buckets, whose upper bounds are
+// its custom values, to a histogram with explicit bounds.
+func (rm *resourceMetrics) addCustomBucketsHistogram(s *series, h *writev2.Histogram) (pmetric.ExemplarSlice, error) {
+	dp := rm.metric(s.name, pmetric.MetricTypeHistogram, s.metadata).Histogram().DataPoints().AppendEmpty()
+	setAttributes(dp.Attributes(), s.labels)
+	dp.SetTimestamp(timestampFromMs(h.Timestamp))
+	dp.SetStartTimestamp(timestampFromMs(s.createdTimestamp))
+	if value.IsStaleNaN(h.Sum) {
+		dp.SetFlags(pmetric.DefaultDataPointFlags.WithNoRecordedValue(true))
+		return dp.Exemplars(), nil
+	}
+
+	dp.SetSum(h.Sum)
+	if h.Float {
+		dp.SetCount(countFromFloat(h.CountFloat))
+	} else {
+		dp.SetCount(h.CountInt)
+	}
+	counts := make([]uint64, len(h.CustomValues)+1)
+	first, dense, err := denseBuckets(h.PositiveSpans, h.PositiveDeltas, h.PositiveCounts, h.Float, len(counts))
+	if err != nil {
+		return pmetric.ExemplarSlice{}, err
+	}
+	for i, count := range dense {
+		idx := int(first) + i
+		if idx < 0 || idx >= len(counts) {
+			return pmetric.ExemplarSlice{}, fmt.Errorf("bucket %d out of the %d custom buckets", idx, len(counts))
+		}
+		counts[idx] = count
+	}
+	dp.ExplicitBounds().FromRaw(h.CustomValues)
+	dp.BucketCounts().FromRaw(counts)
+	return dp.Exemplars(), nil
+}
+
+// bucketsLimit returns the number of buckets of a native histogram of the schema covering
+// the range of the float64 values, bounded by maxDenseBuckets.
+func bucketsLimit(schema int32) int {
+	limit := float64Exponents
+	if schema >= 0 {
+		limit <<= schema
+	} else {
+		limit = (limit >> -schema) + 1
+	}
+	return min(limit, maxDenseBuckets)
+}
+
+// denseBuckets expands the spans of buckets of a native histogram. It returns the index
+// of the first bucket and the counts of the consecutive buckets from this one.
+// The spans expanding to more than limit buckets are rejected before any allocation.
+func denseBuckets(spans []writev2.BucketSpan, deltas []int64, floatCounts []float64, float bool, limit int) (int32, []uint64, error) {
+	total := 0
+	for i, span := range spans {
+		if i > 0 {
+			if span.Offset < 0 {
+				return 0, nil, fmt.Errorf("invalid negative offset %d of bucket span %d", span.Offset, i)
+			}
+			total += int(span.Offset)
+		}
+		total += int(span.Length)
+		if total > limit {
+			return 0, nil, fmt.Errorf("bucket spans expand to more than %d buckets", limit)
+		}
+	}
+
+	var first int32
+	counts := make([]uint64, 0, total)
+	var n int
+	var current int64
+	for i, span := range spans {
+		if i == 0 {
+			first = span.Offset
+		} else {
+			for j := int32(0); j < span.Offset; j++ {
+				counts = append(counts, 0)
+			}
+		}
+		for j := uint32(0); j < span.Length; j++ {
+			if float {
+				if n >= len(floatCounts) {
+					return 0, nil, fmt.Errorf("bucket spans hold more buckets than the %d counts", len(floatCounts))
+				}
+				counts = append(counts, countFromFloat(floatCounts[n]))
+			} else {
+				if n >= len(deltas) {
+					return 0, nil, fmt.Errorf("bucket spans hold more buckets than the %d deltas", len(deltas))
+				}
+				current += deltas[n]
+				counts = append(counts, countFromFloat(float64(current)))
+			}
+			n++
+		}
+	}
+	return first, counts, nil
+}
+
+func histogramFromV1(h *prompb.Histogram) writev2.Histogram {
+	out := writev2.Histogram{
+		Sum:            h.Sum,
+		Schema:         h.Schema,
+		ZeroThreshold:  h.ZeroThreshold,
+		NegativeDeltas: h.NegativeDeltas,
+		NegativeCounts: h.NegativeCounts,
+		PositiveDeltas: h.PositiveDeltas,
+		PositiveCounts: h.PositiveCounts,
+		ResetHint:      writev2.ResetHint(h.ResetHint),
+		Timestamp:      h.Timestamp,
+	}
+	if _, ok := h.Count.(*prompb.Histogram_CountFloat); ok {
+		out.Float = true
+		out.CountFloat = h.GetCountFloat()
+		out.ZeroCountFloat = h.GetZeroCountFloat()
+	} else {
+		out.CountInt = h.GetCountInt()
+		out.ZeroCountInt = h.GetZeroCountInt()
+	}
+	for _, span := range h.NegativeSpans {
+		out.NegativeSpans = append(out.NegativeSpans, writev2.BucketSpan{Offset: span.Offset, Length: span.Length})
+	}
+	for _, span := range h.PositiveSpans {
+		out.PositiveSpans = append(out.PositiveSpans, writev2.BucketSpan{Offset: span.Offset, Length: span.Length})
+	}
+	return out
+}
+
+func addExemplars(dst pmetric.ExemplarSlice, exemplars []exemplar) {
+	for _, ex := range exemplars {
+		e := dst.AppendEmpty()
+		e.SetTimestamp(timestampFromMs(ex.timestamp))
+		e.SetDoubleValue(ex.value)
+		for _, l := range ex.labels {
+			switch l.name {
+			case prometheustranslator.ExemplarTraceIDKey:
+				var id pcommon.TraceID
+				if n, err := hex.Decode(id[:], []byte(l.value)); err == nil && n == len(id) {
+					e.SetTraceID(id)
+					continue
+				}
+			case prometheustranslator.ExemplarSpanIDKey:
+				var id pcommon.SpanID
+				if n, err := hex.Decode(id[:], []byte(l.value)); err == nil && n == len(id) {
+					e.SetSpanID(id)
+					continue
+				}
+			}
+			e.FilteredAttributes().PutStr(l.name, l.value)
+		}
+	}
+}
+
+// isIdentifyingLabel returns true if the label is not an attribute of the data points,
+// as it is the name of the metric or identifies its resource.
+func isIdentifyingLabel(name string) bool {
+	return name == model.MetricNameLabel || name == model.JobLabel || name == model.InstanceLabel
+}
+
+// setAttributes sets the labels of the series, except the identifying labels and the labels
+// of the buckets and quantiles of classic histograms and summaries, as attributes.
+func setAttributes(attrs pcommon.Map, labels []label) {
+	attrs.EnsureCapacity(len(labels))
+	for _, l := range labels {
+		if !isIdentifyingLabel(l.name) && l.name != model.BucketLabel && l.name != quantileLabel {
+			attrs.PutStr(l.name, l.value)
+		}
+	}
+}
+
+// attributesKey returns a key identifying the attributes set by setAttributes.
+func attributesKey(labels []label) string {
+	var b strings.Builder
+	for _, l := range labels {
+		if !isIdentifyingLabel(l.name) && l.name != model.BucketLabel && l.name != quantileLabel {
+			b.WriteString(l.name)
+			b.WriteByte(0xff)
+			b.WriteString(l.value)
+			b.WriteByte(0xff)
+		}
+	}
+	return b.String()
+}
+
+func sortLabels(labels []label) {
+	sort.Slice(labels, func(i, j int) bool {
+		return labels[i].name < labels[j].name
+	})
+}
+
+func timestampFromMs(ms int64) pcommon.Timestamp {
+	if ms == 0 {
+		return 0
+	}
+	return pcommon.NewTimestampFromTime(time.UnixMilli(ms))
+}
+
+// countFromFloat rounds a count, which may be a float in Prometheus, to an integer.
+func countFromFloat(count float64) uint64 {
+	if math.IsNaN(count) || count <= 0 {
+		return 0
+	}
+	return uint64(math.Round(count))
+}
@@ -0,0 +1,420 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"math"
+	"testing"
+
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+func v1Labels(pairs ...string) []prompb.Label {
+	labels := make([]prompb.Label, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		labels = append(labels, prompb.Label{Name: pairs[i], Value: pairs[i+1]})
+	}
+	return labels
+}
+
+func v1Series(sample float64, timestamp int64, pairs ...string) prompb.TimeSeries {
+	return prompb.TimeSeries{
+		Labels:  v1Labels(pairs...),
+		Samples: []prompb.Sample{{Value: sample, Timestamp: timestamp}},
+	}
+}
+
+func noMetadata(string) (metricMetadata, bool) {
+	return metricMetadata{}, false
+}
+
+func metricsByName(t *testing.T, rm pmetric.ResourceMetrics) map[string]pmetric.Metric {
+	require.Equal(t, 1, rm.ScopeMetrics().Len())
+	assert.Equal(t, scopeName, rm.ScopeMetrics().At(0).Scope().Name())
+	metrics := rm.ScopeMetrics().At(0).Metrics()
+	byName := make(map[string]pmetric.Metric, metrics.Len())
+	for i := 0; i < metrics.Len(); i++ {
+		byName[metrics.At(i).Name()] = metrics.At(i)
+	}
+	return byName
+}
+
+func TestConvertV1Resources(t *testing.T) {
+	req := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			v1Series(1, 1000, "__name__", "up", "job", "shop/checkout", "instance", "pod-1"),
+			v1Series(1, 1000, "__name__", "target_info", "job", "shop/checkout", "instance", "pod-1", "k8s_pod_name", "checkout-abc"),
+			v1Series(2, 1000, "__name__", "up", "job", "cart", "instance", "pod-2", "zone", "a"),
+			// target_info of a target without other series does not create a resource
+			v1Series(1, 1000, "__name__", "target_info", "job", "idle"),
+		},
+	}
+	c := newConverter()
+	require.NoError(t, c.fromV1(req, noMetadata))
+	md, stats := c.finish()
+	assert.Equal(t, writeStats{samples: 4}, stats)
+
+	require.Equal(t, 2, md.ResourceMetrics().Len())
+	checkout := md.ResourceMetrics().At(0)
+	assert.Equal(t, map[string]any{
+		"service.namespace":   "shop",
+		"service.name":        "checkout",
+		"service.instance.id": "pod-1",
+		"k8s_pod_name":        "checkout-abc",
+	}, checkout.Resource().Attributes().AsRaw())
+	up := metricsByName(t, checkout)["up"]
+	require.Equal(t, pmetric.MetricTypeGauge, up.Type())
+	dp := up.Gauge().DataPoints().At(0)
+	assert.Equal(t, 1.0, dp.DoubleValue())
+	assert.Equal(t, pcommon.Timestamp(1000*1e6), dp.Timestamp())
+	assert.Equal(t, 0, dp.Attributes().Len())
+
+	cart := md.ResourceMetrics().At(1)
+	assert.Equal(t, map[string]any{
+		"service.name":        "cart",
+		"service.instance.id": "pod-2",
+	}, cart.Resource().Attributes().AsRaw())
+	dp = metricsByName(t, cart)["up"].Gauge().DataPoints().At(0)
+	assert.Equal(t, map[string]any{"zone": "a"}, dp.Attributes().AsRaw())
+}
+
+func TestConvertV1Numbers(t *testing.T) {
+	metadata := map[string]metricMetadata{
+		"requests":    {typ: writev2.MetricTypeCounter, help: "Requests served", unit: "1"},
+		"temperature": {typ: writev2.MetricTypeGauge, help: "Current temperature", unit: "Cel"},
+	}
+	req := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			v1Series(10, 1000, "__name__", "requests_total", "code", "200"),
+			v1Series(21.5, 1000, "__name__", "temperature"),
+			v1Series(3, 1000, "__name__", "errors_total"),
+			v1Series(math.Float64frombits(value.StaleNaN), 2000, "__name__", "queue_length"),
+			{
+				Labels:  v1Labels("__name__", "bytes_total"),
+				Samples: []prompb.Sample{{Value: 5, Timestamp: 1000}},
+				Exemplars: []prompb.Exemplar{{
+					Labels:    v1Labels("trace_id", "0102030405060708090a0b0c0d0e0f10", "span_id", "0102030405060708", "user", "bob"),
+					Value:     5,
+					Timestamp: 900,
+				}},
+			},
+		},
+	}
+	c := newConverter()
+	require.NoError(t, c.fromV1(req, func(family string) (metricMetadata, bool) {
+		m, ok := metadata[family]
+		return m, ok
+	}))
+	md, _ := c.finish()
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	metrics := metricsByName(t, md.ResourceMetrics().At(0))
+
+	requests := metrics["requests_total"]
+	require.Equal(t, pmetric.MetricTypeSum, requests.Type())
+	assert.Equal(t, "Requests served", requests.Description())
+	assert.Equal(t, "1", requests.Unit())
+	assert.True(t, requests.Sum().IsMonotonic())
+	assert.Equal(t, pmetric.AggregationTemporalityCumulative, requests.Sum().AggregationTemporality())
+	assert.Equal(t, map[string]any{"code": "200"}, requests.Sum().DataPoints().At(0).Attributes().AsRaw())
+
+	temperature := metrics["temperature"]
+	require.Equal(t, pmetric.MetricTypeGauge, temperature.Type())
+	assert.Equal(t, "Cel", temperature.Unit())
+
+	// without metadata, the _total suffix identifies counters
+	assert.Equal(t, pmetric.MetricTypeSum, metrics["errors_total"].Type())
+
+	stale := metrics["queue_length"].Gauge().DataPoints().At(0)
+	assert.True(t, stale.Flags().NoRecordedValue())
+
+	exemplars := metrics["bytes_total"].Sum().DataPoints().At(0).Exemplars()
+	require.Equal(t, 1, exemplars.Len())
+	e := exemplars.At(0)
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", e.TraceID().String())
+	assert.Equal(t, "0102030405060708", e.SpanID().String())
+	assert.Equal(t, map[string]any{"user": "bob"}, e.FilteredAttributes().AsRaw())
+	assert.Equal(t, 5.0, e.DoubleValue())
+	assert.Equal(t, pcommon.Timestamp(900*1e6), e.Timestamp())
+}
+
+func TestConvertV1ClassicHistogramAndSummary(t *testing.T) {
+	req := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			v1Series(10, 1000, "__name__", "latency_bucket", "le", "+Inf", "path", "/"),
+			v1Series(4, 1000, "__name__", "latency_bucket", "le", "0.5", "path", "/"),
+			v1Series(1, 1000, "__name__", "latency_bucket", "le", "0.1", "path", "/"),
+			v1Series(12.5, 1000, "__name__", "latency_sum", "path", "/"),
+			v1Series(10, 1000, "__name__", "latency_count", "path", "/"),
+			v1Series(2, 1000, "__name__", "latency_bucket", "le", "+Inf", "path", "/other"),
+			v1Series(0.2, 1000, "__name__", "rpc_duration", "quantile", "0.99"),
+			v1Series(0.1, 1000, "__name__", "rpc_duration", "quantile", "0.5"),
+			v1Series(30, 1000, "__name__", "rpc_duration_sum"),
+			v1Series(100, 1000, "__name__", "rpc_duration_count"),
+			// a _count series which is not part of a histogram or summary
+			v1Series(7, 1000, "__name__", "retry_count"),
+		},
+	}
+	c := newConverter()
+	require.NoError(t, c.fromV1(req, noMetadata))
+	md, _ := c.finish()
+	metrics := metricsByName(t, md.ResourceMetrics().At(0))
+	require.Len(t, metrics, 3)
+
+	latency := metrics["latency"]
+	require.Equal(t, pmetric.MetricTypeHistogram, latency.Type())
+	require.Equal(t, 2, latency.Histogram().DataPoints().Len())
+	dp := latency.Histogram().DataPoints().At(0)
+	assert.Equal(t, map[string]any{"path": "/"}, dp.Attributes().AsRaw())
+	assert.Equal(t, uint64(10), dp.Count())
+	assert.Equal(t, 12.5, dp.Sum())
+	assert.Equal(t, []float64{0.1, 0.5}, dp.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{1, 3, 6}, dp.BucketCounts().AsRaw())
+	dp = latency.Histogram().DataPoints().At(1)
+	assert.Equal(t, uint64(2), dp.Count())
+	assert.False(t, dp.HasSum())
+	assert.Equal(t, []uint64{2}, dp.BucketCounts().AsRaw())
+
+	rpc := metrics["rpc_duration"]
+	require.Equal(t, pmetric.MetricTypeSummary, rpc.Type())
+	sdp := rpc.Summary().DataPoints().At(0)
+	assert.Equal(t, uint64(100), sdp.Count())
+	assert.Equal(t, 30.0, sdp.Sum())
+	require.Equal(t, 2, sdp.QuantileValues().Len())
+	assert.Equal(t, 0.5, sdp.QuantileValues().At(0).Quantile())
+	assert.Equal(t, 0.1, sdp.QuantileValues().At(0).Value())
+	assert.Equal(t, 0.99, sdp.QuantileValues().At(1).Quantile())
+
+	assert.Equal(t, pmetric.MetricTypeGauge, metrics["retry_count"].Type())
+}
+
+func TestConvertV1NativeHistogram(t *testing.T) {
+	req := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{{
+			Labels: v1Labels("__name__", "latency"),
+			Histograms: []prompb.Histogram{{
+				Count:         &prompb.Histogram_CountInt{CountInt: 7},
+				Sum:           3.5,
+				Schema:        1,
+				ZeroThreshold: 0.001,
+				ZeroCount:     &prompb.Histogram_ZeroCountInt{ZeroCountInt: 1},
+				PositiveSpans: []prompb.BucketSpan{{Offset: 2, Length: 2}, {Offset: 1, Length: 1}},
+				// counts 2, 1, 0, 3
+				PositiveDeltas: []int64{2, -1, 2},
+				NegativeSpans:  []prompb.BucketSpan{{Offset: -1, Length: 1}},
+				NegativeDeltas: []int64{1},
+				Timestamp:      1000,
+			}},
+		}},
+	}
+	c := newConverter()
+	require.NoError(t, c.fromV1(req, noMetadata))
+	md, stats := c.finish()
+	assert.Equal(t, writeStats{histograms: 1}, stats)
+
+	latency := metricsByName(t, md.ResourceMetrics().At(0))["latency"]
+	require.Equal(t, pmetric.MetricTypeExponentialHistogram, latency.Type())
+	dp := latency.ExponentialHistogram().DataPoints().At(0)
+	assert.Equal(t, int32(1), dp.Scale())
+	assert.Equal(t, uint64(7), dp.Count())
+	assert.Equal(t, 3.5, dp.Sum())
+	assert.Equal(t, 0.001, dp.ZeroThreshold())
+	assert.Equal(t, uint64(1), dp.ZeroCount())
+	assert.Equal(t, int32(1), dp.Positive().Offset())
+	assert.Equal(t, []uint64{2, 1, 0, 3}, dp.Positive().BucketCounts().AsRaw())
+	assert.Equal(t, int32(-2), dp.Negative().Offset())
+	assert.Equal(t, []uint64{1}, dp.Negative().BucketCounts().AsRaw())
+	assert.Equal(t, pcommon.Timestamp(1000*1e6), dp.Timestamp())
+}
+
+func TestConvertV2(t *testing.T) {
+	symbols := writev2.NewSymbolsTable()
+	labels := func(pairs ...string) []uint32 {
+		var refs []uint32
+		for i := 0; i < len(pairs); i += 2 {
+			refs = append(refs, symbols.Symbolize(pairs[i]), symbols.Symbolize(pairs[i+1]))
+		}
+		return refs
+	}
+	req := &writev2.Request{
+		Timeseries: []writev2.TimeSeries{
+			{
+				LabelsRefs: labels("__name__", "http_requests_total", "job", "api"),
+				Samples:    []writev2.Sample{{Value: 4, Timestamp: 2000}},
+				Metadata: writev2.Metadata{
+					Type:    writev2.MetricTypeCounter,
+					HelpRef: symbols.Symbolize("Requests"),
+				},
+				CreatedTimestamp: 500,
+				Exemplars: []writev2.Exemplar{{
+					LabelsRefs: labels("trace_id", "0102030405060708090a0b0c0d0e0f10"),
+					Value:      1,
+					Timestamp:  1500,
+				}},
+			},
+			{
+				// a counter which is identified by its metadata only
+				LabelsRefs: labels("__name__", "jobs_done", "job", "api"),
+				Samples:    []writev2.Sample{{Value: 2, Timestamp: 2000}},
+				Metadata:   writev2.Metadata{Type: writev2.MetricTypeCounter},
+			},
+			{
+				LabelsRefs: labels("__name__", "size", "job", "api"),
+				Histograms: []writev2.Histogram{{
+					Float:          true,
+					CountFloat:     3.2,
+					Sum:            10,
+					Schema:         0,
+					ZeroCountFloat: 0.4,
+					PositiveSpans:  []writev2.BucketSpan{{Offset: 1, Length: 2}},
+					PositiveCounts: []float64{1.1, 1.7},
+					Timestamp:      2000,
+				}},
+				Metadata: writev2.Metadata{
+					Type:    writev2.MetricTypeHistogram,
+					UnitRef: symbols.Symbolize("By"),
+				},
+				CreatedTimestamp: 1000,
+			},
+			{
+				LabelsRefs: labels("__name__", "duration", "job", "api"),
+				Histograms: []writev2.Histogram{{
+					CountInt:       6,
+					Sum:            2,
+					Schema:         customBucketsSchema,
+					PositiveSpans:  []writev2.BucketSpan{{Offset: 1, Length: 2}},
+					PositiveDeltas: []int64{4, -2},
+					CustomValues:   []float64{0.1, 0.5, 1},
+					Timestamp:      2000,
+				}},
+			},
+		},
+	}
+	req.Symbols = symbols.Symbols()
+
+	c := newConverter()
+	require.NoError(t, c.fromV2(req))
+	md, stats := c.finish()
+	assert.Equal(t, writeStats{samples: 2, histograms: 2, exemplars: 1}, stats)
+	require.Equal(t, 1, md.ResourceMetrics().Len())
+	metrics := metricsByName(t, md.ResourceMetrics().At(0))
+
+	requests := metrics["http_requests_total"]
+	require.Equal(t, pmetric.MetricTypeSum, requests.Type())
+	assert.Equal(t, "Requests", requests.Description())
+	dp := requests.Sum().DataPoints().At(0)
+	assert.Equal(t, pcommon.Timestamp(500*1e6), dp.StartTimestamp())
+	assert.Equal(t, pcommon.Timestamp(2000*1e6), dp.Timestamp())
+	require.Equal(t, 1, dp.Exemplars().Len())
+	assert.Equal(t, "0102030405060708090a0b0c0d0e0f10", dp.Exemplars().At(0).TraceID().String())
+
+	assert.Equal(t, pmetric.MetricTypeSum, metrics["jobs_done"].Type())
+
+	size := metrics["size"]
+	require.Equal(t, pmetric.MetricTypeExponentialHistogram, size.Type())
+	assert.Equal(t, "By", size.Unit())
+	edp := size.ExponentialHistogram().DataPoints().At(0)
+	assert.Equal(t, pcommon.Timestamp(1000*1e6), edp.StartTimestamp())
+	assert.Equal(t, uint64(3), edp.Count())
+	assert.Equal(t, uint64(0), edp.ZeroCount())
+	assert.Equal(t, int32(0), edp.Positive().Offset())
+	assert.Equal(t, []uint64{1, 2}, edp.Positive().BucketCounts().AsRaw())
+
+	duration := metrics["duration"]
+	require.Equal(t, pmetric.MetricTypeHistogram, duration.Type())
+	hdp := duration.Histogram().DataPoints().At(0)
+	assert.Equal(t, uint64(6), hdp.Count())
+	assert.Equal(t, []float64{0.1, 0.5, 1}, hdp.ExplicitBounds().AsRaw())
+	assert.Equal(t, []uint64{0, 4, 2, 0}, hdp.BucketCounts().AsRaw())
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *prompb.WriteRequest
+		err  string
+	}{
+		{
+			name: "invalid bucket bound",
+			req: &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{
+				v1Series(1, 1000, "__name__", "latency_bucket", "le", "high"),
+			}},
+			err: `time series "latency_bucket": invalid le label "high": strconv.ParseFloat: parsing "high": invalid syntax`,
+		},
+		{
+			name: "unsupported schema",
+			req: &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{{
+				Labels:     v1Labels("__name__", "latency"),
+				Histograms: []prompb.Histogram{{Schema: 9}},
+			}}},
+			err: `time series "latency": unsupported native histogram schema 9`,
+		},
+		{
+			name: "missing bucket counts",
+			req: &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{{
+				Labels: v1Labels("__name__", "latency"),
+				Histograms: []prompb.Histogram{{
+					PositiveSpans:  []prompb.BucketSpan{{Length: 2}},
+					PositiveDeltas: []int64{1},
+				}},
+			}}},
+			err: `time series "latency": bucket spans hold more buckets than the 1 deltas`,
+		},
+		{
+			name: "too many buckets",
+			req: &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{{
+				Labels: v1Labels("__name__", "latency"),
+				Histograms: []prompb.Histogram{{
+					Schema:         8,
+					PositiveSpans:  []prompb.BucketSpan{{Length: 1}, {Offset: math.MaxInt32, Length: 1}, {Offset: math.MaxInt32, Length: 1}},
+					PositiveDeltas: []int64{1, 1, 1},
+				}},
+			}}},
+			err: `time series "latency": bucket spans expand to more than 16384 buckets`,
+		},
+		{
+			name: "too many buckets for the schema",
+			req: &prompb.WriteRequest{Timeseries: []prompb.TimeSeries{{
+				Labels: v1Labels("__name__", "latency"),
+				Histograms: []prompb.Histogram{{
+					Schema:         -4,
+					PositiveSpans:  []prompb.BucketSpan{{Length: 1}, {Offset: 200, Length: 1}},
+					PositiveDeltas: []int64{1, 1},
+				}},
+			}}},
+			err: `time series "latency": bucket spans expand to more than 132 buckets`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.EqualError(t, newConverter().fromV1(tt.req, noMetadata), tt.err)
+		})
+	}
+
+	req := &writev2.Request{
+		Symbols:    []string{"", "__name__"},
+		Timeseries: []writev2.TimeSeries{{LabelsRefs: []uint32{1, 2}}},
+	}
+	assert.EqualError(t, newConverter().fromV2(req), "time series 0: symbol reference 2 out of range, the request has 2 symbols")
+
+	req = &writev2.Request{
+		Symbols: []string{"", "__name__", "latency"},
+		Timeseries: []writev2.TimeSeries{{
+			LabelsRefs: []uint32{1, 2},
+			Histograms: []writev2.Histogram{{
+				Schema:         customBucketsSchema,
+				CustomValues:   []float64{1, 2},
+				PositiveSpans:  []writev2.BucketSpan{{Length: 1}, {Offset: math.MaxInt32, Length: 1}},
+				PositiveDeltas: []int64{1, 1},
+			}},
+		}},
+	}
+	assert.EqualError(t, newConverter().fromV2(req), `time series "latency": bucket spans expand to more than 3 buckets`)
+}
@@ -0,0 +1,234 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/prometheusremotewritereceiver"
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/golang/snappy"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/prometheus/prometheus/prompb"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/receiver"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+const (
+	protobufContentType = "application/x-protobuf"
+	// protoV1 and protoV2 are the values of the proto parameter of the content type
+	// identifying the version of the remote-write protocol.
+	protoV1 = "prometheus.WriteRequest"
+	protoV2 = "io.prometheus.write.v2.Request"
+
+	dataFormat = "prometheusremotewrite"
+
+	// maxMetadataFamilies bounds the number of metric families whose metadata is kept,
+	// the metadata of the least recently sent families being evicted first.
+	maxMetadataFamilies = 10000
+)
+
+var errUnsupportedContentType = fmt.Errorf("unsupported content type, supported: [%s, %s;proto=%s, %s]",
+	protobufContentType, protobufContentType, protoV1, writev2.ContentType)
+
+type prometheusRemoteWriteReceiver struct {
+	settings     receiver.Settings
+	cfg          *Config
+	nextConsumer consumer.Metrics
+	obsrecv      *receiverhelper.ObsReport
+	server       *http.Server
+	shutdownWG   sync.WaitGroup
+
+	// metadata keeps the metadata of the metric families sent by remote-write 1.0 clients,
+	// which send it periodically in requests separate from the samples.
+	metadata *lru.Cache[string, metricMetadata]
+}
+
+func newReceiver(settings receiver.Settings, cfg *Config, nextConsumer consumer.Metrics) (*prometheusRemoteWriteReceiver, error) {
+	transport := "http"
+	if cfg.TLSSetting != nil {
+		transport = "https"
+	}
+	obsrecv, err := receiverhelper.NewObsReport(receiverhelper.ObsReportSettings{
+		ReceiverID:             settings.ID,
+		Transport:              transport,
+		ReceiverCreateSettings: settings,
+	})
+	if err != nil {
+		return nil, err
+	}
+	metadata, err := lru.New[string, metricMetadata](maxMetadataFamilies)
+	if err != nil {
+		return nil, err
+	}
+	return &prometheusRemoteWriteReceiver{
+		settings:     settings,
+		cfg:          cfg,
+		nextConsumer: nextConsumer,
+		obsrecv:      obsrecv,
+		metadata:     metadata,
+	}, nil
+}
+
+func (prw *prometheusRemoteWriteReceiver) Start(ctx context.Context, host component.Host) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc(prw.cfg.Path, prw.handleWrite)
+
+	var err error
+	// The remote-write protocol uses the block format of snappy, rather than the framed format
+	// decoded by the server, so the body is decompressed by the handler.
+	prw.server, err = prw.cfg.ServerConfig.ToServer(ctx, host, prw.settings.TelemetrySettings, mux,
+		confighttp.WithDecoder("snappy", func(body io.ReadCloser) (io.ReadCloser, error) { return body, nil }))
+	if err != nil {
+		return fmt.Errorf("failed to create http server: %w", err)
+	}
+	listener, err := prw.cfg.ServerConfig.ToListener(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create listener: %w", err)
+	}
+	prw.settings.Logger.Info("Starting HTTP server", zap.String("endpoint", prw.cfg.Endpoint))
+
+	prw.shutdownWG.Add(1)
+	go func() {
+		defer prw.shutdownWG.Done()
+		if errHTTP := prw.server.Serve(listener); !errors.Is(errHTTP, http.ErrServerClosed) && errHTTP != nil {
+			prw.settings.ReportStatus(component.NewFatalErrorEvent(errHTTP))
+		}
+	}()
+	return nil
+}
+
+func (prw *prometheusRemoteWriteReceiver) Shutdown(ctx context.Context) error {
+	if prw.server == nil {
+		return nil
+	}
+	err := prw.server.Shutdown(ctx)
+	prw.shutdownWG.Wait()
+	return err
+}
+
+func (prw *prometheusRemoteWriteReceiver) handleWrite(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		http.Error(w, fmt.Sprintf("%v method not allowed, supported: [POST]", req.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	proto, err := protoVersion(req.Header.Get("Content-Type"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+		return
+	}
+	if encoding := req.Header.Get("Content-Encoding"); encoding != "" && encoding != "snappy" {
+		http.Error(w, fmt.Sprintf("unsupported content encoding %q, supported: [snappy]", encoding), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	compressed, err := io.ReadAll(req.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	size, err := snappy.DecodedLen(compressed)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to decompress request: %v", err), http.StatusBadRequest)
+		return
+	}
+	if int64(size) > prw.cfg.MaxDecompressedSize {
+		http.Error(w, fmt.Sprintf("decompressed request size %d exceeds the limit of %d bytes", size, prw.cfg.MaxDecompressedSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to decompress request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	c := newConverter()
+	switch proto {
+	case protoV1:
+		var wr prompb.WriteRequest
+		if err = wr.Unmarshal(data); err != nil {
+			http.Error(w, fmt.Sprintf("failed to decode request: %v", err), http.StatusBadRequest)
+			return
+		}
+		err = c.fromV1(&wr, prw.familyMetadata(wr.Metadata))
+	case protoV2:
+		var wr writev2.Request
+		if err = wr.Unmarshal(data); err != nil {
+			http.Error(w, fmt.Sprintf("failed to decode request: %v", err), http.StatusBadRequest)
+			return
+		}
+		err = c.fromV2(&wr)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	md, stats := c.finish()
+
+	if md.DataPointCount() > 0 {
+		ctx := prw.obsrecv.StartMetricsOp(req.Context())
+		err = prw.nextConsumer.ConsumeMetrics(ctx, md)
+		prw.obsrecv.EndMetricsOp(ctx, dataFormat, md.DataPointCount(), err)
+		if err != nil {
+			status := http.StatusInternalServerError
+			if consumererror.IsPermanent(err) {
+				status = http.StatusBadRequest
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+	}
+
+	if proto == protoV2 {
+		w.Header().Set(writev2.SamplesWrittenHeader, strconv.Itoa(stats.samples))
+		w.Header().Set(writev2.HistogramsWrittenHeader, strconv.Itoa(stats.histograms))
+		w.Header().Set(writev2.ExemplarsWrittenHeader, strconv.Itoa(stats.exemplars))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// protoVersion returns the version of the remote-write protocol from the content type of the request.
+// A content type without proto parameter identifies the 1.0 protocol.
+func protoVersion(contentType string) (string, error) {
+	if contentType == "" {
+		return protoV1, nil
+	}
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != protobufContentType {
+		return "", errUnsupportedContentType
+	}
+	switch proto := params["proto"]; proto {
+	case "", protoV1:
+		return protoV1, nil
+	case protoV2:
+		return protoV2, nil
+	default:
+		return "", errUnsupportedContentType
+	}
+}
+
+// familyMetadata records the metadata sent with a remote-write 1.0 request, and returns
+// the metadata of the metric families known so far.
+func (prw *prometheusRemoteWriteReceiver) familyMetadata(sent []prompb.MetricMetadata) func(string) (metricMetadata, bool) {
+	for _, m := range sent {
+		prw.metadata.Add(m.MetricFamilyName, metricMetadata{
+			typ:  writev2.MetricType(m.Type),
+			help: m.Help,
+			unit: m.Unit,
+		})
+	}
+	return prw.metadata.Get
+}
@@ -0,0 +1,242 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package prometheusremotewritereceiver
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheusremotewrite/writev2"
+)
+
+func newTestReceiver(t *testing.T, next consumer.Metrics) *prometheusRemoteWriteReceiver {
+	cfg := createDefaultConfig().(*Config)
+	prw, err := newReceiver(receivertest.NewNopSettings(), cfg, next)
+	require.NoError(t, err)
+	return prw
+}
+
+func writeRequest(t *testing.T, prw *prometheusRemoteWriteReceiver, contentType string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, defaultPath, bytes.NewReader(snappy.Encode(nil, body)))
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Content-Encoding", "snappy")
+	resp := httptest.NewRecorder()
+	prw.handleWrite(resp, req)
+	return resp
+}
+
+func TestHandleWriteV1(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	prw := newTestReceiver(t, sink)
+
+	// the metadata is sent separately from the samples
+	metadataReq := &prompb.WriteRequest{
+		Metadata: []prompb.MetricMetadata{{
+			Type:             prompb.MetricMetadata_COUNTER,
+			MetricFamilyName: "jobs_done",
+			Help:             "Number of jobs done",
+		}},
+	}
+	body, err := metadataReq.Marshal()
+	require.NoError(t, err)
+	resp := writeRequest(t, prw, "application/x-protobuf", body)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Empty(t, sink.AllMetrics())
+
+	samplesReq := &prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{
+			v1Series(3, 1000, "__name__", "jobs_done", "job", "worker"),
+		},
+	}
+	body, err = samplesReq.Marshal()
+	require.NoError(t, err)
+	resp = writeRequest(t, prw, "application/x-protobuf;proto=prometheus.WriteRequest", body)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Empty(t, resp.Header().Get(writev2.SamplesWrittenHeader))
+
+	require.Len(t, sink.AllMetrics(), 1)
+	md := sink.AllMetrics()[0]
+	m := md.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "jobs_done", m.Name())
+	assert.Equal(t, "Number of jobs done", m.Description())
+	assert.Equal(t, pmetric.MetricTypeSum, m.Type())
+}
+
+func TestHandleWriteV2(t *testing.T) {
+	sink := new(consumertest.MetricsSink)
+	prw := newTestReceiver(t, sink)
+
+	symbols := writev2.NewSymbolsTable()
+	req := &writev2.Request{
+		Timeseries: []writev2.TimeSeries{{
+			LabelsRefs: symbols.SymbolizeLabels([]string{"__name__", "job"}, []string{"up", "api"}, nil),
+			Samples:    []writev2.Sample{{Value: 1, Timestamp: 1000}, {Value: 1, Timestamp: 2000}},
+		}},
+	}
+	req.Symbols = symbols.Symbols()
+	body, err := req.Marshal()
+	require.NoError(t, err)
+
+	resp := writeRequest(t, prw, writev2.ContentType, body)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, "2", resp.Header().Get(writev2.SamplesWrittenHeader))
+	assert.Equal(t, "0", resp.Header().Get(writev2.HistogramsWrittenHeader))
+	assert.Equal(t, "0", resp.Header().Get(writev2.ExemplarsWrittenHeader))
+	assert.Equal(t, 2, sink.DataPointCount())
+}
+
+func TestHandleWriteErrors(t *testing.T) {
+	valid, err := (&prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{v1Series(1, 1000, "__name__", "up")},
+	}).Marshal()
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		next        consumer.Metrics
+		method      string
+		contentType string
+		encoding    string
+		body        []byte
+		status      int
+	}{
+		{
+			name:   "wrong method",
+			method: http.MethodGet,
+			status: http.StatusMethodNotAllowed,
+		},
+		{
+			name:        "unsupported content type",
+			contentType: "application/json",
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			name:        "unsupported proto",
+			contentType: "application/x-protobuf;proto=io.prometheus.write.v3.Request",
+			status:      http.StatusUnsupportedMediaType,
+		},
+		{
+			name:     "unsupported encoding",
+			encoding: "gzip",
+			status:   http.StatusUnsupportedMediaType,
+		},
+		{
+			name:   "not snappy",
+			body:   []byte("not snappy"),
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid protobuf",
+			body:   snappy.Encode(nil, []byte{0xff}),
+			status: http.StatusBadRequest,
+		},
+		{
+			name:   "retryable error",
+			next:   consumertest.NewErr(errors.New("busy")),
+			body:   snappy.Encode(nil, valid),
+			status: http.StatusInternalServerError,
+		},
+		{
+			name:   "permanent error",
+			next:   consumertest.NewErr(consumererror.NewPermanent(errors.New("invalid"))),
+			body:   snappy.Encode(nil, valid),
+			status: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := tt.next
+			if next == nil {
+				next = consumertest.NewNop()
+			}
+			prw := newTestReceiver(t, next)
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			contentType := tt.contentType
+			if contentType == "" {
+				contentType = "application/x-protobuf"
+			}
+			encoding := tt.encoding
+			if encoding == "" {
+				encoding = "snappy"
+			}
+			req := httptest.NewRequest(method, defaultPath, bytes.NewReader(tt.body))
+			req.Header.Set("Content-Type", contentType)
+			req.Header.Set("Content-Encoding", encoding)
+			resp := httptest.NewRecorder()
+			prw.handleWrite(resp, req)
+			assert.Equal(t, tt.status, resp.Code)
+		})
+	}
+}
+
+func TestHandleWriteTooLarge(t *testing.T) {
+	body, err := (&prompb.WriteRequest{
+		Timeseries: []prompb.TimeSeries{v1Series(1, 1000, "__name__", "up")},
+	}).Marshal()
+	require.NoError(t, err)
+
+	sink := new(consumertest.MetricsSink)
+	prw := newTestReceiver(t, sink)
+	prw.cfg.MaxDecompressedSize = int64(len(body) - 1)
+
+	resp := writeRequest(t, prw, "application/x-protobuf", body)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, resp.Code)
+	assert.Zero(t, sink.DataPointCount())
+
+	prw.cfg.MaxDecompressedSize = int64(len(body))
+	resp = writeRequest(t, prw, "application/x-protobuf", body)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, 1, sink.DataPointCount())
+}
+
+func TestProtoVersion(t *testing.T) {
+	for contentType, expected := range map[string]string{
+		"":                       protoV1,
+		"application/x-protobuf": protoV1,
+		"application/x-protobuf; proto=prometheus.WriteRequest": protoV1,
+		writev2.ContentType: protoV2,
+	} {
+		proto, err := protoVersion(contentType)
+		require.NoError(t, err, contentType)
+		assert.Equal(t, expected, proto, contentType)
+	}
+	_, err := protoVersion("text/plain")
+	assert.ErrorIs(t, err, errUnsupportedContentType)
+}
+
+func TestFamilyMetadata_bounded(t *testing.T) {
+	prw := newTestReceiver(t, consumertest.NewNop())
+
+	sent := make([]prompb.MetricMetadata, 0, maxMetadataFamilies+1)
+	for i := 0; i <= maxMetadataFamilies; i++ {
+		sent = append(sent, prompb.MetricMetadata{MetricFamilyName: "family_" + strconv.Itoa(i), Type: prompb.MetricMetadata_COUNTER})
+	}
+	lookup := prw.familyMetadata(sent)
+	assert.Equal(t, maxMetadataFamilies, prw.metadata.Len())
+
+	// the metadata of the least recently sent family is evicted
+	_, ok := lookup("family_0")
+	assert.False(t, ok)
+	m, ok := lookup("family_1")
+	assert.True(t, ok)
+	assert.Equal(t, writev2.MetricTypeCounter, m.typ)
+}
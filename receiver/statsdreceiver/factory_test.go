@@ -8,9 +8,13 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
 	"go.opentelemetry.io/collector/receiver/receivertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/sharedcomponent"
 )
 
 func TestCreateDefaultConfig(t *testing.T) {
@@ -29,3 +33,27 @@ func TestCreateReceiver(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, tReceiver, "receiver creation failed")
 }
+
+func TestCreateSharedReceiver(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.NetAddr.Endpoint = "localhost:0"
+
+	params := receivertest.NewNopSettings()
+	metricsSink := new(consumertest.MetricsSink)
+	mReceiver, err := createMetricsReceiver(context.Background(), params, cfg, metricsSink)
+	require.NoError(t, err)
+	logsSink := new(consumertest.LogsSink)
+	lReceiver, err := createLogsReceiver(context.Background(), params, cfg, logsSink)
+	require.NoError(t, err)
+
+	// the metrics and logs share the receiver of the configuration
+	assert.Same(t, mReceiver, lReceiver)
+	r := mReceiver.(*sharedcomponent.SharedComponent).Unwrap().(*statsdReceiver)
+	assert.Equal(t, consumer.Metrics(metricsSink), r.nextConsumer)
+	assert.Equal(t, consumer.Logs(logsSink), r.nextLogsConsumer)
+
+	require.NoError(t, mReceiver.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, lReceiver.Start(context.Background(), componenttest.NewNopHost()))
+	require.NoError(t, mReceiver.Shutdown(context.Background()))
+	require.NoError(t, lReceiver.Shutdown(context.Background()))
+}
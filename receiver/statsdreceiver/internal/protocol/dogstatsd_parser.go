@@ -0,0 +1,294 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package protocol // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/protocol"
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/collector/client"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	semconv "go.opentelemetry.io/collector/semconv/v1.22.0"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	eventPrefix        = "_e{"
+	serviceCheckPrefix = "_sc|"
+
+	attributeEventTitle          = "dogstatsd.event.title"
+	attributeEventPriority       = "dogstatsd.event.priority"
+	attributeEventAlertType      = "dogstatsd.event.alert_type"
+	attributeEventAggregationKey = "dogstatsd.event.aggregation_key"
+	attributeEventSourceTypeName = "dogstatsd.event.source_type_name"
+	attributeServiceCheckName    = "dogstatsd.service_check.name"
+	attributeServiceCheckStatus  = "dogstatsd.service_check.status"
+	defaultEventPriority         = "normal"
+	defaultEventAlertType        = "info"
+)
+
+var (
+	errEmptyEventTitle       = errors.New("empty event title")
+	errEmptyServiceCheckName = errors.New("empty service check name")
+
+	eventSeverities = map[string]plog.SeverityNumber{
+		"error":   plog.SeverityNumberError,
+		"warning": plog.SeverityNumberWarn,
+		"info":    plog.SeverityNumberInfo,
+		"success": plog.SeverityNumberInfo,
+	}
+
+	serviceCheckStatuses = []string{"ok", "warning", "critical", "unknown"}
+
+	serviceCheckSeverities = map[string]plog.SeverityNumber{
+		"ok":       plog.SeverityNumberInfo,
+		"warning":  plog.SeverityNumberWarn,
+		"critical": plog.SeverityNumberError,
+	}
+)
+
+// IsDogStatsDLog returns whether the line is a DogStatsD event or service check, which are mapped to logs.
+func IsDogStatsDLog(line string) bool {
+	return strings.HasPrefix(line, eventPrefix) || strings.HasPrefix(line, serviceCheckPrefix)
+}
+
+// DogStatsDLogParser maps the DogStatsD events and service checks to log records, grouped by address.
+// See https://docs.datadoghq.com/developers/dogstatsd/datagram_shell/?tab=events
+type DogStatsDLogParser struct {
+	logsByAddress    map[netAddr]*addressLogs
+	enableSimpleTags bool
+	BuildInfo        component.BuildInfo
+}
+
+type addressLogs struct {
+	addr net.Addr
+	logs plog.Logs
+}
+
+func (p *DogStatsDLogParser) Initialize(enableSimpleTags bool) {
+	p.enableSimpleTags = enableSimpleTags
+	p.logsByAddress = make(map[netAddr]*addressLogs)
+}
+
+// GetLogs gets the logs preparing for flushing and reset the state.
+func (p *DogStatsDLogParser) GetLogs() []BatchLogs {
+	batchLogs := make([]BatchLogs, 0, len(p.logsByAddress))
+	for _, logs := range p.logsByAddress {
+		batchLogs = append(batchLogs, BatchLogs{
+			Info: client.Info{
+				Addr: logs.addr,
+			},
+			Logs: logs.logs,
+		})
+	}
+	p.logsByAddress = make(map[netAddr]*addressLogs)
+	return batchLogs
+}
+
+// Aggregate adds the log record of an event or service check line.
+func (p *DogStatsDLogParser) Aggregate(line string, addr net.Addr) error {
+	lr := plog.NewLogRecord()
+	var err error
+	if strings.HasPrefix(line, eventPrefix) {
+		err = p.parseEvent(line, lr)
+	} else {
+		err = p.parseServiceCheck(line, lr)
+	}
+	if err != nil {
+		return err
+	}
+	lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(timeNowFunc()))
+
+	addrKey := newNetAddr(addr)
+	logs, ok := p.logsByAddress[addrKey]
+	if !ok {
+		logs = &addressLogs{
+			addr: addr,
+			logs: plog.NewLogs(),
+		}
+		sl := logs.logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+		sl.Scope().SetName(receiverName)
+		sl.Scope().SetVersion(p.BuildInfo.Version)
+		p.logsByAddress[addrKey] = logs
+	}
+	lr.MoveTo(logs.logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().AppendEmpty())
+	return nil
+}
+
+// parseEvent parses an event formatted as:
+// _e{<title length>,<text length>}:<title>|<text>|d:<timestamp>|h:<hostname>|k:<aggregation key>|p:<priority>|s:<source type name>|t:<alert type>|#<tags>|c:<container id>
+func (p *DogStatsDLogParser) parseEvent(line string, lr plog.LogRecord) error {
+	headerEnd := strings.Index(line, "}:")
+	if headerEnd < 0 {
+		return fmt.Errorf("invalid event format: %s", line)
+	}
+	lengths := strings.Split(line[len(eventPrefix):headerEnd], ",")
+	if len(lengths) != 2 {
+		return fmt.Errorf("invalid event lengths: %s", line[:headerEnd+1])
+	}
+	titleLen, err := strconv.Atoi(lengths[0])
+	if err != nil || titleLen < 0 {
+		return fmt.Errorf("invalid event title length: %s", lengths[0])
+	}
+	textLen, err := strconv.Atoi(lengths[1])
+	if err != nil || textLen < 0 {
+		return fmt.Errorf("invalid event text length: %s", lengths[1])
+	}
+
+	content := line[headerEnd+2:]
+	if len(content) < titleLen+1+textLen || content[titleLen] != '|' {
+		return fmt.Errorf("event title and text don't match their lengths: %s", line)
+	}
+	title := content[:titleLen]
+	if title == "" {
+		return errEmptyEventTitle
+	}
+	lr.Body().SetStr(unescapeNewlines(content[titleLen+1 : titleLen+1+textLen]))
+
+	fields := content[titleLen+1+textLen:]
+	if fields != "" && fields[0] != '|' {
+		return fmt.Errorf("event title and text don't match their lengths: %s", line)
+	}
+
+	attrs := lr.Attributes()
+	attrs.PutStr(attributeEventTitle, title)
+	priority, alertType := defaultEventPriority, defaultEventAlertType
+	var kvs []attribute.KeyValue
+	for _, field := range splitFields(fields) {
+		switch {
+		case strings.HasPrefix(field, "d:"):
+			if err := setTimestamp(lr, strings.TrimPrefix(field, "d:")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(field, "h:"):
+			attrs.PutStr(semconv.AttributeHostName, strings.TrimPrefix(field, "h:"))
+		case strings.HasPrefix(field, "k:"):
+			attrs.PutStr(attributeEventAggregationKey, strings.TrimPrefix(field, "k:"))
+		case strings.HasPrefix(field, "p:"):
+			priority = strings.TrimPrefix(field, "p:")
+			if priority != "normal" && priority != "low" {
+				return fmt.Errorf("invalid event priority: %s", priority)
+			}
+		case strings.HasPrefix(field, "s:"):
+			attrs.PutStr(attributeEventSourceTypeName, strings.TrimPrefix(field, "s:"))
+		case strings.HasPrefix(field, "t:"):
+			alertType = strings.TrimPrefix(field, "t:")
+			if _, ok := eventSeverities[alertType]; !ok {
+				return fmt.Errorf("invalid event alert type: %s", alertType)
+			}
+		case strings.HasPrefix(field, "#"):
+			tags, err := parseTags(strings.TrimPrefix(field, "#"), p.enableSimpleTags)
+			if err != nil {
+				return err
+			}
+			kvs = append(kvs, tags...)
+		case strings.HasPrefix(field, "c:"):
+			if containerID := strings.TrimPrefix(field, "c:"); containerID != "" {
+				attrs.PutStr(semconv.AttributeContainerID, containerID)
+			}
+		default:
+			return fmt.Errorf("unrecognized event part: %s", field)
+		}
+	}
+	attrs.PutStr(attributeEventPriority, priority)
+	attrs.PutStr(attributeEventAlertType, alertType)
+	lr.SetSeverityText(alertType)
+	lr.SetSeverityNumber(eventSeverities[alertType])
+	putTags(attrs, kvs)
+	return nil
+}
+
+// parseServiceCheck parses a service check formatted as:
+// _sc|<name>|<status>|d:<timestamp>|h:<hostname>|#<tags>|c:<container id>|m:<message>
+func (p *DogStatsDLogParser) parseServiceCheck(line string, lr plog.LogRecord) error {
+	parts := strings.SplitN(line[len(serviceCheckPrefix):], "|", 3)
+	if len(parts) < 2 {
+		return fmt.Errorf("invalid service check format: %s", line)
+	}
+	if parts[0] == "" {
+		return errEmptyServiceCheckName
+	}
+	status, err := strconv.Atoi(parts[1])
+	if err != nil || status < 0 || status >= len(serviceCheckStatuses) {
+		return fmt.Errorf("invalid service check status: %s", parts[1])
+	}
+	statusName := serviceCheckStatuses[status]
+
+	attrs := lr.Attributes()
+	attrs.PutStr(attributeServiceCheckName, parts[0])
+	attrs.PutStr(attributeServiceCheckStatus, statusName)
+	lr.SetSeverityText(statusName)
+	lr.SetSeverityNumber(serviceCheckSeverities[statusName])
+
+	var fields []string
+	if len(parts) == 3 {
+		fields = strings.Split(parts[2], "|")
+	}
+	var kvs []attribute.KeyValue
+	for i, field := range fields {
+		switch {
+		case strings.HasPrefix(field, "m:"):
+			// the message is the last field, and it may contain the separator
+			lr.Body().SetStr(unescapeNewlines(strings.TrimPrefix(strings.Join(fields[i:], "|"), "m:")))
+			putTags(attrs, kvs)
+			return nil
+		case strings.HasPrefix(field, "d:"):
+			if err := setTimestamp(lr, strings.TrimPrefix(field, "d:")); err != nil {
+				return err
+			}
+		case strings.HasPrefix(field, "h:"):
+			attrs.PutStr(semconv.AttributeHostName, strings.TrimPrefix(field, "h:"))
+		case strings.HasPrefix(field, "#"):
+			tags, err := parseTags(strings.TrimPrefix(field, "#"), p.enableSimpleTags)
+			if err != nil {
+				return err
+			}
+			kvs = append(kvs, tags...)
+		case strings.HasPrefix(field, "c:"):
+			if containerID := strings.TrimPrefix(field, "c:"); containerID != "" {
+				attrs.PutStr(semconv.AttributeContainerID, containerID)
+			}
+		default:
+			return fmt.Errorf("unrecognized service check part: %s", field)
+		}
+	}
+	putTags(attrs, kvs)
+	return nil
+}
+
+// splitFields splits the fields following the text of an event, which starts with the separator.
+func splitFields(fields string) []string {
+	if fields == "" {
+		return nil
+	}
+	return strings.Split(fields[1:], "|")
+}
+
+func setTimestamp(lr plog.LogRecord, timestampStr string) error {
+	timestampSeconds, err := strconv.ParseUint(timestampStr, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp: %s", timestampStr)
+	}
+	lr.SetTimestamp(pcommon.Timestamp(timestampSeconds * 1e9))
+	return nil
+}
+
+// putTags adds the tags to the attributes, the tags don't override the fields of the message.
+func putTags(attrs pcommon.Map, kvs []attribute.KeyValue) {
+	for _, kv := range kvs {
+		if _, ok := attrs.Get(string(kv.Key)); !ok {
+			attrs.PutStr(string(kv.Key), kv.Value.AsString())
+		}
+	}
+}
+
+// unescapeNewlines restores the newlines of a text, which DogStatsD clients escape as "\n".
+func unescapeNewlines(text string) string {
+	return strings.ReplaceAll(text, `\n`, "\n")
+}
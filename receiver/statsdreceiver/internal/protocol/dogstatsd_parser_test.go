@@ -0,0 +1,246 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package protocol
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+func Test_IsDogStatsDLog(t *testing.T) {
+	assert.True(t, IsDogStatsDLog("_e{5,4}:title|text"))
+	assert.True(t, IsDogStatsDLog("_sc|name|0"))
+	assert.False(t, IsDogStatsDLog("test.metric:42|c"))
+	assert.False(t, IsDogStatsDLog("_e.metric:42|c"))
+}
+
+func Test_DogStatsDLogParser_Parse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected func() plog.LogRecord
+		err      error
+	}{
+		{
+			name:  "event",
+			input: "_e{5,4}:title|text",
+			expected: func() plog.LogRecord {
+				lr := plog.NewLogRecord()
+				lr.Body().SetStr("text")
+				lr.SetSeverityText("info")
+				lr.SetSeverityNumber(plog.SeverityNumberInfo)
+				lr.Attributes().PutStr("dogstatsd.event.title", "title")
+				lr.Attributes().PutStr("dogstatsd.event.priority", "normal")
+				lr.Attributes().PutStr("dogstatsd.event.alert_type", "info")
+				return lr
+			},
+		},
+		{
+			name:  "event with all the fields",
+			input: `_e{10,12}:deployment|line1\nline2|d:1656581400|h:my-host|k:my-key|p:low|s:my-source|t:error|#env:prod,team:infra|c:my-container`,
+			expected: func() plog.LogRecord {
+				lr := plog.NewLogRecord()
+				lr.Body().SetStr("line1\nline2")
+				lr.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(1656581400, 0)))
+				lr.SetSeverityText("error")
+				lr.SetSeverityNumber(plog.SeverityNumberError)
+				lr.Attributes().PutStr("dogstatsd.event.title", "deployment")
+				lr.Attributes().PutStr("host.name", "my-host")
+				lr.Attributes().PutStr("dogstatsd.event.aggregation_key", "my-key")
+				lr.Attributes().PutStr("dogstatsd.event.source_type_name", "my-source")
+				lr.Attributes().PutStr("container.id", "my-container")
+				lr.Attributes().PutStr("dogstatsd.event.priority", "low")
+				lr.Attributes().PutStr("dogstatsd.event.alert_type", "error")
+				lr.Attributes().PutStr("env", "prod")
+				lr.Attributes().PutStr("team", "infra")
+				return lr
+			},
+		},
+		{
+			name:  "event with separators in the title and text",
+			input: "_e{3,3}:a|b|c|d",
+			expected: func() plog.LogRecord {
+				lr := plog.NewLogRecord()
+				lr.Body().SetStr("c|d")
+				lr.SetSeverityText("info")
+				lr.SetSeverityNumber(plog.SeverityNumberInfo)
+				lr.Attributes().PutStr("dogstatsd.event.title", "a|b")
+				lr.Attributes().PutStr("dogstatsd.event.priority", "normal")
+				lr.Attributes().PutStr("dogstatsd.event.alert_type", "info")
+				return lr
+			},
+		},
+		{
+			name:  "event with mismatched lengths",
+			input: "_e{5,10}:title|text",
+			err:   errors.New("event title and text don't match their lengths: _e{5,10}:title|text"),
+		},
+		{
+			name:  "event with text longer than its length",
+			input: "_e{5,2}:title|text",
+			err:   errors.New("event title and text don't match their lengths: _e{5,2}:title|text"),
+		},
+		{
+			name:  "event with invalid lengths",
+			input: "_e{5}:title|text",
+			err:   errors.New("invalid event lengths: _e{5}"),
+		},
+		{
+			name:  "event with empty title",
+			input: "_e{0,4}:|text",
+			err:   errEmptyEventTitle,
+		},
+		{
+			name:  "event with invalid alert type",
+			input: "_e{5,4}:title|text|t:fatal",
+			err:   errors.New("invalid event alert type: fatal"),
+		},
+		{
+			name:  "event with unknown field",
+			input: "_e{5,4}:title|text|x:y",
+			err:   errors.New("unrecognized event part: x:y"),
+		},
+		{
+			name:  "service check",
+			input: "_sc|my.service|2",
+			expected: func() plog.LogRecord {
+				lr := plog.NewLogRecord()
+				lr.SetSeverityText("critical")
+				lr.SetSeverityNumber(plog.SeverityNumberError)
+				lr.Attributes().PutStr("dogstatsd.service_check.name", "my.service")
+				lr.Attributes().PutStr("dogstatsd.service_check.status", "critical")
+				return lr
+			},
+		},
+		{
+			name:  "service check with all the fields",
+			input: `_sc|my.service|1|d:1656581400|h:my-host|#env:prod|c:my-container|m:degraded|slow\nretrying`,
+			expected: func() plog.LogRecord {
+				lr := plog.NewLogRecord()
+				lr.Body().SetStr("degraded|slow\nretrying")
+				lr.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(1656581400, 0)))
+				lr.SetSeverityText("warning")
+				lr.SetSeverityNumber(plog.SeverityNumberWarn)
+				lr.Attributes().PutStr("dogstatsd.service_check.name", "my.service")
+				lr.Attributes().PutStr("dogstatsd.service_check.status", "warning")
+				lr.Attributes().PutStr("host.name", "my-host")
+				lr.Attributes().PutStr("container.id", "my-container")
+				lr.Attributes().PutStr("env", "prod")
+				return lr
+			},
+		},
+		{
+			name:  "service check with unknown status",
+			input: "_sc|my.service|3",
+			expected: func() plog.LogRecord {
+				lr := plog.NewLogRecord()
+				lr.SetSeverityText("unknown")
+				lr.Attributes().PutStr("dogstatsd.service_check.name", "my.service")
+				lr.Attributes().PutStr("dogstatsd.service_check.status", "unknown")
+				return lr
+			},
+		},
+		{
+			name:  "service check with invalid status",
+			input: "_sc|my.service|4",
+			err:   errors.New("invalid service check status: 4"),
+		},
+		{
+			name:  "service check without status",
+			input: "_sc|my.service",
+			err:   errors.New("invalid service check format: _sc|my.service"),
+		},
+		{
+			name:  "service check with empty name",
+			input: "_sc||0",
+			err:   errEmptyServiceCheckName,
+		},
+		{
+			name:  "service check with invalid timestamp",
+			input: "_sc|my.service|0|d:now",
+			err:   errors.New("invalid timestamp: now"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &DogStatsDLogParser{}
+			p.Initialize(false)
+			lr := plog.NewLogRecord()
+
+			var err error
+			if strings.HasPrefix(tt.input, eventPrefix) {
+				err = p.parseEvent(tt.input, lr)
+			} else {
+				err = p.parseServiceCheck(tt.input, lr)
+			}
+
+			if tt.err != nil {
+				assert.Equal(t, tt.err, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected(), lr)
+		})
+	}
+}
+
+func Test_DogStatsDLogParser_SimpleTags(t *testing.T) {
+	p := &DogStatsDLogParser{}
+	p.Initialize(false)
+	assert.EqualError(t, p.parseServiceCheck("_sc|my.service|0|#mykey", plog.NewLogRecord()), `invalid tag format: "mykey"`)
+
+	p.Initialize(true)
+	lr := plog.NewLogRecord()
+	require.NoError(t, p.parseServiceCheck("_sc|my.service|0|#mykey", lr))
+	value, ok := lr.Attributes().Get("mykey")
+	require.True(t, ok)
+	assert.Equal(t, "", value.Str())
+}
+
+func Test_DogStatsDLogParser_AggregateByAddress(t *testing.T) {
+	now := time.Now()
+	timeNowFunc = func() time.Time {
+		return now
+	}
+	defer func() { timeNowFunc = time.Now }()
+
+	addr1 := &net.UDPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 5678}
+	addr2 := &net.UDPAddr{IP: net.IPv4(8, 7, 6, 5), Port: 4321}
+
+	p := &DogStatsDLogParser{BuildInfo: component.BuildInfo{Version: "v1.2.3"}}
+	p.Initialize(false)
+	require.NoError(t, p.Aggregate("_e{5,4}:title|text", addr1))
+	require.NoError(t, p.Aggregate("_sc|my.service|0", addr1))
+	require.NoError(t, p.Aggregate("_sc|my.service|2", addr2))
+	assert.Error(t, p.Aggregate("_sc|my.service", addr2))
+
+	batches := p.GetLogs()
+	require.Len(t, batches, 2)
+	records := map[string]int{}
+	for _, batch := range batches {
+		require.Equal(t, 1, batch.Logs.ResourceLogs().Len())
+		require.Equal(t, 1, batch.Logs.ResourceLogs().At(0).ScopeLogs().Len())
+		sl := batch.Logs.ResourceLogs().At(0).ScopeLogs().At(0)
+		assert.Equal(t, "otelcol/statsdreceiver", sl.Scope().Name())
+		assert.Equal(t, "v1.2.3", sl.Scope().Version())
+		for i := 0; i < sl.LogRecords().Len(); i++ {
+			assert.Equal(t, pcommon.NewTimestampFromTime(now), sl.LogRecords().At(i).ObservedTimestamp())
+		}
+		records[batch.Info.Addr.String()] = sl.LogRecords().Len()
+	}
+	assert.Equal(t, map[string]int{addr1.String(): 2, addr2.String(): 1}, records)
+
+	// the state is reset after the logs are flushed
+	assert.Empty(t, p.GetLogs())
+}
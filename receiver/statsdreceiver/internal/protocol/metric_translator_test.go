@@ -91,6 +91,24 @@ func TestBuildGaugeMetric(t *testing.T) {
 	assert.Equal(t, metric, expectedMetrics)
 }
 
+func TestBuildGaugeMetricWithTimestamp(t *testing.T) {
+	parsedMetric := statsDMetric{
+		description: statsDMetricDescription{
+			name: "testGauge",
+		},
+		asFloat:   32.3,
+		timestamp: 1656581400 * 1e9,
+	}
+	metric := buildGaugeMetric(parsedMetric, time.Now())
+	expectedMetrics := pmetric.NewScopeMetrics()
+	expectedMetric := expectedMetrics.Metrics().AppendEmpty()
+	expectedMetric.SetName("testGauge")
+	dp := expectedMetric.SetEmptyGauge().DataPoints().AppendEmpty()
+	dp.SetDoubleValue(32.3)
+	dp.SetTimestamp(pcommon.NewTimestampFromTime(time.Unix(1656581400, 0)))
+	assert.Equal(t, metric, expectedMetrics)
+}
+
 func TestBuildSummaryMetricUnsampled(t *testing.T) {
 	timeNow := time.Now()
 
@@ -45,7 +45,7 @@ func (s *StatsD) connect() error {
 		if err != nil {
 			return err
 		}
-	case "tcp":
+	case "tcp", "unix", "unixgram":
 		var err error
 		s.conn, err = net.Dial(s.transport, s.address)
 		if err != nil {
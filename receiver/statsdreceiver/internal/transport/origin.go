@@ -0,0 +1,93 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package transport // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/transport"
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// procPath is the mount point of the proc filesystem.
+var procPath = "/proc"
+
+// containerIDPattern matches the last element of the control group path of a container, such as
+// "/docker/<id>", "/system.slice/docker-<id>.scope" or "/kubepods/burstable/pod<uid>/cri-containerd-<id>.scope".
+var containerIDPattern = regexp.MustCompile(`(?:^|[/-])([0-9a-f]{64})(?:\.scope)?$`)
+
+// ContainerIDFromPID returns the ID of the container a process runs in, found in its control groups,
+// or an empty string when the process is gone or doesn't run in a container.
+func ContainerIDFromPID(pid int32) string {
+	f, err := os.Open(filepath.Join(procPath, strconv.FormatInt(int64(pid), 10), "cgroup"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// each line is formatted as hierarchy-ID:controller-list:cgroup-path
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if match := containerIDPattern.FindStringSubmatch(parts[2]); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+// ContainerIDCache caches the container ID of the processes, so that the control groups of a process are
+// read once per TTL rather than for every batch it sends. The entries expire as the PIDs get reused.
+type ContainerIDCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu        sync.Mutex
+	entries   map[int32]containerIDEntry
+	nextSweep time.Time
+}
+
+type containerIDEntry struct {
+	containerID string
+	expiry      time.Time
+}
+
+// NewContainerIDCache creates a ContainerIDCache which keeps the container ID of a process for the TTL.
+func NewContainerIDCache(ttl time.Duration) *ContainerIDCache {
+	return &ContainerIDCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: map[int32]containerIDEntry{},
+	}
+}
+
+// Get returns the container ID of a process, as returned by ContainerIDFromPID when it was last read.
+func (c *ContainerIDCache) Get(pid int32) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if entry, ok := c.entries[pid]; ok && now.Before(entry.expiry) {
+		return entry.containerID
+	}
+	if !now.Before(c.nextSweep) {
+		// the processes which stopped sending data are forgotten once their entry expired
+		for p, entry := range c.entries {
+			if !now.Before(entry.expiry) {
+				delete(c.entries, p)
+			}
+		}
+		c.nextSweep = now.Add(c.ttl)
+	}
+	containerID := ContainerIDFromPID(pid)
+	c.entries[pid] = containerIDEntry{containerID: containerID, expiry: now.Add(c.ttl)}
+	return containerID
+}
@@ -0,0 +1,91 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerIDFromPID(t *testing.T) {
+	const containerID = "3ad8c6ef1b4a4f3bbf36b6e6fa3e5b1a1a0c3d2e9b8f7a6c5d4e3f2a1b0c9d8e"
+
+	for _, tt := range []struct {
+		desc     string
+		cgroup   string
+		expected string
+	}{
+		{
+			desc:     "docker with cgroup v1",
+			cgroup:   "12:pids:/docker/" + containerID + "\n11:memory:/docker/" + containerID + "\n",
+			expected: containerID,
+		},
+		{
+			desc:     "docker with systemd and cgroup v2",
+			cgroup:   "0::/system.slice/docker-" + containerID + ".scope\n",
+			expected: containerID,
+		},
+		{
+			desc:     "kubernetes with containerd",
+			cgroup:   "0::/kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod1234.slice/cri-containerd-" + containerID + ".scope\n",
+			expected: containerID,
+		},
+		{
+			desc:     "not in a container",
+			cgroup:   "0::/user.slice/user-1000.slice/session-2.scope\n",
+			expected: "",
+		},
+	} {
+		t.Run(tt.desc, func(t *testing.T) {
+			// prepare
+			dir := t.TempDir()
+			require.NoError(t, os.Mkdir(filepath.Join(dir, "42"), 0700))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "42", "cgroup"), []byte(tt.cgroup), 0600))
+			previous := procPath
+			procPath = dir
+			defer func() { procPath = previous }()
+
+			// test
+			assert.Equal(t, tt.expected, ContainerIDFromPID(42))
+			assert.Equal(t, "", ContainerIDFromPID(43))
+		})
+	}
+}
+
+func TestContainerIDCache(t *testing.T) {
+	const (
+		containerID      = "3ad8c6ef1b4a4f3bbf36b6e6fa3e5b1a1a0c3d2e9b8f7a6c5d4e3f2a1b0c9d8e"
+		otherContainerID = "9b8f7a6c5d4e3f2a1b0c9d8e3ad8c6ef1b4a4f3bbf36b6e6fa3e5b1a1a0c3d2e"
+	)
+	dir := t.TempDir()
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "42"), 0700))
+	cgroupPath := filepath.Join(dir, "42", "cgroup")
+	require.NoError(t, os.WriteFile(cgroupPath, []byte("0::/docker/"+containerID+"\n"), 0600))
+	previous := procPath
+	procPath = dir
+	defer func() { procPath = previous }()
+
+	now := time.Unix(1000, 0)
+	cache := NewContainerIDCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	assert.Equal(t, containerID, cache.Get(42))
+
+	// the control groups aren't read again until the entry expires
+	require.NoError(t, os.WriteFile(cgroupPath, []byte("0::/docker/"+otherContainerID+"\n"), 0600))
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, containerID, cache.Get(42))
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, otherContainerID, cache.Get(42))
+
+	// the expired entries of the other processes are removed
+	assert.Equal(t, "", cache.Get(43))
+	now = now.Add(2 * time.Minute)
+	assert.Equal(t, otherContainerID, cache.Get(42))
+	assert.Len(t, cache.entries, 1)
+}
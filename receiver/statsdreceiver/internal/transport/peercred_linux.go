@@ -0,0 +1,64 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+package transport // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/transport"
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// peerCredentialsOOBSize is the size of the out-of-band data holding the credentials of a datagram.
+var peerCredentialsOOBSize = unix.CmsgSpace(unix.SizeofUcred)
+
+// enablePeerCredentials has the kernel attach the credentials of the sender to the received datagrams.
+func enablePeerCredentials(conn *net.UnixConn) error {
+	rawConn, err := conn.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var sockErr error
+	if err = rawConn.Control(func(fd uintptr) {
+		sockErr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_PASSCRED, 1)
+	}); err != nil {
+		return err
+	}
+	return sockErr
+}
+
+// peerPIDFromControlMessages returns the process ID found in the credentials of a datagram, or 0.
+func peerPIDFromControlMessages(oob []byte) int32 {
+	msgs, err := unix.ParseSocketControlMessage(oob)
+	if err != nil {
+		return 0
+	}
+	for i := range msgs {
+		if cred, err := unix.ParseUnixCredentials(&msgs[i]); err == nil {
+			return cred.Pid
+		}
+	}
+	return 0
+}
+
+// peerPID returns the process ID of the client of a connection, or 0.
+func peerPID(c net.Conn) int32 {
+	unixConn, ok := c.(*net.UnixConn)
+	if !ok {
+		return 0
+	}
+	rawConn, err := unixConn.SyscallConn()
+	if err != nil {
+		return 0
+	}
+	var cred *unix.Ucred
+	var credErr error
+	if err = rawConn.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptUcred(int(fd), unix.SOL_SOCKET, unix.SO_PEERCRED)
+	}); err != nil || credErr != nil {
+		return 0
+	}
+	return cred.Pid
+}
@@ -0,0 +1,23 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !linux
+
+package transport // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/transport"
+
+import "net"
+
+// The peer credentials are only supported on Linux, the clients aren't identified on the other platforms.
+const peerCredentialsOOBSize = 0
+
+func enablePeerCredentials(_ *net.UnixConn) error {
+	return nil
+}
+
+func peerPIDFromControlMessages(_ []byte) int32 {
+	return 0
+}
+
+func peerPID(_ net.Conn) int32 {
+	return 0
+}
@@ -6,6 +6,7 @@ package transport
 import (
 	"io"
 	"net"
+	"path/filepath"
 	"runtime"
 	"sync"
 	"testing"
@@ -41,6 +42,20 @@ func Test_Server_ListenAndServe(t *testing.T) {
 			buildServerFn:     NewTCPServer,
 			buildClientFn:     client.NewStatsD,
 		},
+		{
+			name:              "unixgram",
+			transport:         UnixGram,
+			getFreeEndpointFn: getSocketPath,
+			buildServerFn:     NewUnixgramServer,
+			buildClientFn:     client.NewStatsD,
+		},
+		{
+			name:              "unix",
+			transport:         Unix,
+			getFreeEndpointFn: getSocketPath,
+			buildServerFn:     NewUnixServer,
+			buildClientFn:     client.NewStatsD,
+		},
 	}
 
 	for _, tt := range tests {
@@ -94,6 +109,10 @@ func Test_Server_ListenAndServe(t *testing.T) {
 	}
 }
 
+func getSocketPath(t testing.TB, _ string) string {
+	return filepath.Join(t.TempDir(), "statsd.sock")
+}
+
 func testFreeEndpoint(t *testing.T, transport string, address string) {
 	t.Helper()
 
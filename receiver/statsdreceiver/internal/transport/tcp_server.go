@@ -23,6 +23,9 @@ type tcpServer struct {
 	wg        sync.WaitGroup
 	transport Transport
 	stopChan  chan struct{}
+	// connAddr returns the address the lines received on a connection are attributed to,
+	// the local address of the connection is used when it's nil.
+	connAddr func(c net.Conn) net.Addr
 }
 
 // Ensure that Server is implemented on TCP Server.
@@ -80,6 +83,10 @@ LOOP:
 
 // handleConn is helper that parses the buffer and split it line by line to be parsed upstream.
 func (t *tcpServer) handleConn(c net.Conn, transferChan chan<- Metric) {
+	addr := c.LocalAddr()
+	if t.connAddr != nil {
+		addr = t.connAddr(c)
+	}
 	payload := make([]byte, 4096)
 	var remainder []byte
 	for {
@@ -100,7 +107,7 @@ func (t *tcpServer) handleConn(c net.Conn, transferChan chan<- Metric) {
 			}
 			line := strings.TrimSpace(string(bytes))
 			if line != "" {
-				transferChan <- Metric{line, c.LocalAddr()}
+				transferChan <- Metric{line, addr}
 			}
 		}
 	}
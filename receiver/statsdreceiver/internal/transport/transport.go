@@ -21,6 +21,9 @@ const (
 	TCP  Transport = "tcp"
 	TCP4 Transport = "tcp4"
 	TCP6 Transport = "tcp6"
+
+	UnixGram Transport = "unixgram"
+	Unix     Transport = "unix"
 )
 
 // NewTransport creates a Transport based on the transport string or returns an empty Transport.
@@ -31,6 +34,8 @@ func NewTransport(ts string) Transport {
 		return trans
 	case TCP, TCP4, TCP6:
 		return trans
+	case UnixGram, Unix:
+		return trans
 	}
 	return Transport("")
 }
@@ -38,7 +43,7 @@ func NewTransport(ts string) Transport {
 // String casts the transport to a String if the Transport is supported. Return an empty Transport overwise.
 func (trans Transport) String() string {
 	switch trans {
-	case UDP, UDP4, UDP6, TCP, TCP4, TCP6:
+	case UDP, UDP4, UDP6, TCP, TCP4, TCP6, UnixGram, Unix:
 		return string(trans)
 	}
 	return ""
@@ -47,7 +52,7 @@ func (trans Transport) String() string {
 // IsPacketTransport returns true if the transport is packet based.
 func (trans Transport) IsPacketTransport() bool {
 	switch trans {
-	case UDP, UDP4, UDP6:
+	case UDP, UDP4, UDP6, UnixGram:
 		return true
 	}
 	return false
@@ -56,7 +61,7 @@ func (trans Transport) IsPacketTransport() bool {
 // IsStreamTransport returns true if the transport is stream based.
 func (trans Transport) IsStreamTransport() bool {
 	switch trans {
-	case TCP, TCP4, TCP6:
+	case TCP, TCP4, TCP6, Unix:
 		return true
 	}
 	return false
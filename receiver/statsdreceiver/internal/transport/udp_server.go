@@ -55,7 +55,7 @@ func (u *udpServer) ListenAndServe(
 		if n > 0 {
 			bufCopy := make([]byte, n)
 			copy(bufCopy, buf)
-			u.handlePacket(bufCopy, addr, transferChan)
+			handlePacket(bufCopy, addr, transferChan)
 		}
 		if err != nil {
 			reporter.OnDebugf("%s Transport (%s) - ReadFrom error: %v",
@@ -79,7 +79,7 @@ func (u *udpServer) Close() error {
 }
 
 // handlePacket is helper that parses the buffer and split it line by line to be parsed upstream.
-func (u *udpServer) handlePacket(
+func handlePacket(
 	data []byte,
 	addr net.Addr,
 	transferChan chan<- Metric,
@@ -0,0 +1,179 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package transport // import "github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/transport"
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.uber.org/multierr"
+)
+
+// maxUnixgramPacketSize is the size of the buffer used to read the datagrams of a Unix domain socket,
+// the datagrams aren't limited by the UDP size, and the clients usually send up to 8KiB.
+const maxUnixgramPacketSize = 65536
+
+// UnixAddr is the address of a client of a Unix domain socket. As the clients are usually unnamed sockets,
+// they're identified by the process ID found in the peer credentials, when the platform supports them.
+type UnixAddr struct {
+	Net  string
+	Path string
+	PID  int32
+}
+
+var _ net.Addr = (*UnixAddr)(nil)
+
+// Network returns the transport of the socket.
+func (a *UnixAddr) Network() string {
+	return a.Net
+}
+
+// String returns the path of the socket, followed by the process ID of the client when it's known.
+func (a *UnixAddr) String() string {
+	if a.PID == 0 {
+		return a.Path
+	}
+	return a.Path + "@pid:" + strconv.FormatInt(int64(a.PID), 10)
+}
+
+type unixgramServer struct {
+	conn      *net.UnixConn
+	path      string
+	transport Transport
+}
+
+// Ensure that Server is implemented on Unix datagram Server.
+var _ Server = (*unixgramServer)(nil)
+
+// NewUnixgramServer creates a transport.Server using a Unix domain datagram socket as its transport.
+func NewUnixgramServer(transport Transport, path string) (Server, error) {
+	if transport != UnixGram {
+		return nil, fmt.Errorf("NewUnixgramServer with %s: %w", transport.String(), ErrUnsupportedPacketTransport)
+	}
+
+	if err := removeStaleSocket(transport, path); err != nil {
+		return nil, err
+	}
+	conn, err := net.ListenUnixgram(transport.String(), &net.UnixAddr{Name: path, Net: transport.String()})
+	if err != nil {
+		return nil, fmt.Errorf("starting to listen %s socket: %w", transport.String(), err)
+	}
+	if err = enablePeerCredentials(conn); err != nil {
+		_ = conn.Close()
+		_ = os.Remove(path)
+		return nil, fmt.Errorf("enabling the peer credentials of %s socket: %w", transport.String(), err)
+	}
+
+	return &unixgramServer{
+		conn:      conn,
+		path:      path,
+		transport: transport,
+	}, nil
+}
+
+// ListenAndServe starts the server ready to receive metrics.
+func (u *unixgramServer) ListenAndServe(
+	nextConsumer consumer.Metrics,
+	reporter Reporter,
+	transferChan chan<- Metric,
+) error {
+	if nextConsumer == nil || reporter == nil {
+		return errNilListenAndServeParameters
+	}
+
+	buf := make([]byte, maxUnixgramPacketSize)
+	oob := make([]byte, peerCredentialsOOBSize)
+	for {
+		n, oobn, _, _, err := u.conn.ReadMsgUnix(buf, oob)
+		if n > 0 {
+			bufCopy := make([]byte, n)
+			copy(bufCopy, buf)
+			addr := &UnixAddr{
+				Net:  u.transport.String(),
+				Path: u.path,
+				PID:  peerPIDFromControlMessages(oob[:oobn]),
+			}
+			handlePacket(bufCopy, addr, transferChan)
+		}
+		if err != nil {
+			reporter.OnDebugf("%s Transport (%s) - ReadMsgUnix error: %v",
+				u.transport,
+				u.path,
+				err)
+			var netErr net.Error
+			if errors.As(err, &netErr) {
+				if netErr.Timeout() {
+					continue
+				}
+			}
+			return err
+		}
+	}
+}
+
+// Close closes the server, and removes its socket.
+func (u *unixgramServer) Close() error {
+	err := u.conn.Close()
+	if removeErr := os.Remove(u.path); removeErr != nil && !errors.Is(removeErr, fs.ErrNotExist) {
+		err = multierr.Append(err, removeErr)
+	}
+	return err
+}
+
+// NewUnixServer creates a transport.Server using a Unix domain stream socket as its transport.
+func NewUnixServer(transport Transport, path string) (Server, error) {
+	if transport != Unix {
+		return nil, fmt.Errorf("NewUnixServer with %s: %w", transport.String(), ErrUnsupportedStreamTransport)
+	}
+
+	if err := removeStaleSocket(transport, path); err != nil {
+		return nil, err
+	}
+	// the socket is removed when the listener is closed
+	listener, err := net.Listen(transport.String(), path)
+	if err != nil {
+		return nil, fmt.Errorf("starting to listen %s socket: %w", transport.String(), err)
+	}
+
+	return &tcpServer{
+		listener:  listener,
+		transport: transport,
+		stopChan:  make(chan struct{}),
+		connAddr: func(c net.Conn) net.Addr {
+			return &UnixAddr{
+				Net:  transport.String(),
+				Path: path,
+				PID:  peerPID(c),
+			}
+		},
+	}, nil
+}
+
+// removeStaleSocket removes the socket left behind by a previous process, as a socket can't be bound to
+// an existing file. The sockets still served by another process are kept.
+func removeStaleSocket(transport Transport, path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("checking %s socket %q: %w", transport.String(), path, err)
+	}
+	if info.Mode()&fs.ModeSocket == 0 {
+		return fmt.Errorf("%q is not a socket", path)
+	}
+	if conn, err := net.Dial(transport.String(), path); err == nil {
+		_ = conn.Close()
+		return fmt.Errorf("%s socket %q is already in use", transport.String(), path)
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("removing stale %s socket %q: %w", transport.String(), path, err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright The OpenTelemetry Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package transport
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumertest"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/transport/client"
+)
+
+func TestUnixAddr(t *testing.T) {
+	addr := &UnixAddr{Net: "unixgram", Path: "/var/run/statsd.sock"}
+	assert.Equal(t, "unixgram", addr.Network())
+	assert.Equal(t, "/var/run/statsd.sock", addr.String())
+
+	addr.PID = 42
+	assert.Equal(t, "/var/run/statsd.sock@pid:42", addr.String())
+}
+
+func TestRemoveStaleSocket(t *testing.T) {
+	dir := t.TempDir()
+
+	// test
+	require.NoError(t, removeStaleSocket(UnixGram, filepath.Join(dir, "missing.sock")))
+
+	// a datagram socket isn't removed when it's closed
+	stale := filepath.Join(dir, "stale.sock")
+	conn, err := net.ListenPacket("unixgram", stale)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+	require.FileExists(t, stale)
+
+	// test
+	require.NoError(t, removeStaleSocket(UnixGram, stale))
+
+	// verify
+	assert.NoFileExists(t, stale)
+
+	// prepare
+	inUse := filepath.Join(dir, "in-use.sock")
+	listener, err := net.Listen("unix", inUse)
+	require.NoError(t, err)
+	defer listener.Close()
+
+	// test
+	assert.ErrorContains(t, removeStaleSocket(Unix, inUse), "is already in use")
+
+	// prepare
+	file := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(file, nil, 0600))
+
+	// test
+	assert.ErrorContains(t, removeStaleSocket(Unix, file), "is not a socket")
+	assert.FileExists(t, file)
+}
+
+func TestUnixServersPeerCredentials(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("the peer credentials are only supported on Linux")
+	}
+
+	for _, tt := range []struct {
+		transport     Transport
+		buildServerFn func(transport Transport, path string) (Server, error)
+	}{
+		{UnixGram, NewUnixgramServer},
+		{Unix, NewUnixServer},
+	} {
+		t.Run(tt.transport.String(), func(t *testing.T) {
+			path := getSocketPath(t, tt.transport.String())
+			srv, err := tt.buildServerFn(tt.transport, path)
+			require.NoError(t, err)
+
+			transferChan := make(chan Metric, 10)
+			wg := sync.WaitGroup{}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.Error(t, srv.ListenAndServe(new(consumertest.MetricsSink), NewMockReporter(1), transferChan))
+			}()
+
+			gc, err := client.NewStatsD(tt.transport.String(), path)
+			require.NoError(t, err)
+			require.NoError(t, gc.SendMetric(client.Metric{Name: "test.metric", Value: "42", Type: "c"}))
+			require.NoError(t, gc.Disconnect())
+
+			var metric Metric
+			select {
+			case metric = <-transferChan:
+			case <-time.After(10 * time.Second):
+				t.Fatal("no metric received")
+			}
+			assert.Equal(t, "test.metric:42|c", metric.Raw)
+			assert.Equal(t, &UnixAddr{Net: tt.transport.String(), Path: path, PID: int32(os.Getpid())}, metric.Addr)
+
+			require.NoError(t, srv.Close())
+			wg.Wait()
+			assert.NoFileExists(t, path)
+		})
+	}
+}
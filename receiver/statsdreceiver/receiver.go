@@ -14,9 +14,11 @@ import (
 	"go.opentelemetry.io/collector/client"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver"
 	"go.opentelemetry.io/collector/receiver/receiverhelper"
+	semconv "go.opentelemetry.io/collector/semconv/v1.22.0"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/metadata"
@@ -24,19 +26,29 @@ import (
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/internal/transport"
 )
 
-var _ receiver.Metrics = (*statsdReceiver)(nil)
+var (
+	_ receiver.Metrics = (*statsdReceiver)(nil)
+	_ receiver.Logs    = (*statsdReceiver)(nil)
+)
+
+// containerIDTTL is how long the container ID of a process sending data on a Unix domain socket is cached.
+const containerIDTTL = time.Minute
 
-// statsdReceiver implements the receiver.Metrics for StatsD protocol.
+// statsdReceiver implements the receiver.Metrics for StatsD protocol, and the receiver.Logs
+// for the DogStatsD events and service checks.
 type statsdReceiver struct {
 	settings receiver.Settings
 	config   *Config
 
-	server       transport.Server
-	reporter     *reporter
-	obsrecv      *receiverhelper.ObsReport
-	parser       protocol.Parser
-	nextConsumer consumer.Metrics
-	cancel       context.CancelFunc
+	server           transport.Server
+	reporter         *reporter
+	obsrecv          *receiverhelper.ObsReport
+	parser           protocol.Parser
+	logsParser       *protocol.DogStatsDLogParser
+	nextConsumer     consumer.Metrics
+	nextLogsConsumer consumer.Logs
+	containerIDs     *transport.ContainerIDCache
+	cancel           context.CancelFunc
 }
 
 // newReceiver creates the StatsD receiver with the given parameters.
@@ -75,18 +87,25 @@ func newReceiver(
 		parser: &protocol.StatsDParser{
 			BuildInfo: set.BuildInfo,
 		},
+		logsParser: &protocol.DogStatsDLogParser{
+			BuildInfo: set.BuildInfo,
+		},
+		containerIDs: transport.NewContainerIDCache(containerIDTTL),
 	}
 	return r, nil
 }
 
 func buildTransportServer(config Config) (transport.Server, error) {
-	// TODO: Add unix socket transport implementations
 	trans := transport.NewTransport(strings.ToLower(string(config.NetAddr.Transport)))
 	switch trans {
 	case transport.UDP, transport.UDP4, transport.UDP6:
 		return transport.NewUDPServer(trans, config.NetAddr.Endpoint)
 	case transport.TCP, transport.TCP4, transport.TCP6:
 		return transport.NewTCPServer(trans, config.NetAddr.Endpoint)
+	case transport.UnixGram:
+		return transport.NewUnixgramServer(trans, config.NetAddr.Endpoint)
+	case transport.Unix:
+		return transport.NewUnixServer(trans, config.NetAddr.Endpoint)
 	}
 
 	return nil, fmt.Errorf("unsupported transport %q", string(config.NetAddr.Transport))
@@ -111,8 +130,16 @@ func (r *statsdReceiver) Start(ctx context.Context, _ component.Host) error {
 	if err != nil {
 		return err
 	}
+	r.logsParser.Initialize(r.config.EnableSimpleTags)
+	nextConsumer := r.nextConsumer
+	if nextConsumer == nil {
+		// the receiver is only in logs pipelines, the metrics it receives are dropped before they're aggregated
+		if nextConsumer, err = consumer.NewMetrics(func(context.Context, pmetric.Metrics) error { return nil }); err != nil {
+			return err
+		}
+	}
 	go func() {
-		if err := r.server.ListenAndServe(r.nextConsumer, r.reporter, transferChan); err != nil {
+		if err := r.server.ListenAndServe(nextConsumer, r.reporter, transferChan); err != nil {
 			if !errors.Is(err, net.ErrClosed) {
 				r.settings.TelemetrySettings.ReportStatus(component.NewFatalErrorEvent(err))
 			}
@@ -126,6 +153,9 @@ func (r *statsdReceiver) Start(ctx context.Context, _ component.Host) error {
 				batchMetrics := r.parser.GetMetrics()
 				for _, batch := range batchMetrics {
 					batchCtx := client.NewContext(ctx, batch.Info)
+					for i := 0; i < batch.Metrics.ResourceMetrics().Len(); i++ {
+						r.setOriginAttributes(batch.Metrics.ResourceMetrics().At(i).Resource(), batch.Info.Addr)
+					}
 					numPoints := batch.Metrics.DataPointCount()
 					flushCtx := r.obsrecv.StartMetricsOp(batchCtx)
 					err := r.Flush(flushCtx, batch.Metrics, r.nextConsumer)
@@ -134,8 +164,34 @@ func (r *statsdReceiver) Start(ctx context.Context, _ component.Host) error {
 					}
 					r.obsrecv.EndMetricsOp(flushCtx, metadata.Type.String(), numPoints, err)
 				}
+				for _, batch := range r.logsParser.GetLogs() {
+					batchCtx := client.NewContext(ctx, batch.Info)
+					for i := 0; i < batch.Logs.ResourceLogs().Len(); i++ {
+						r.setOriginAttributes(batch.Logs.ResourceLogs().At(i).Resource(), batch.Info.Addr)
+					}
+					numRecords := batch.Logs.LogRecordCount()
+					flushCtx := r.obsrecv.StartLogsOp(batchCtx)
+					err := r.nextLogsConsumer.ConsumeLogs(flushCtx, batch.Logs)
+					if err != nil {
+						r.reporter.OnDebugf("Error flushing logs", zap.Error(err))
+					}
+					r.obsrecv.EndLogsOp(flushCtx, metadata.Type.String(), numRecords, err)
+				}
 			case metric := <-transferChan:
-				err := r.parser.Aggregate(metric.Raw, metric.Addr)
+				var err error
+				switch {
+				case protocol.IsDogStatsDLog(metric.Raw):
+					if r.nextLogsConsumer == nil {
+						r.reporter.OnDebugf("Dropping DogStatsD event or service check, the receiver isn't in a logs pipeline")
+						continue
+					}
+					err = r.logsParser.Aggregate(metric.Raw, metric.Addr)
+				case r.nextConsumer == nil:
+					r.reporter.OnDebugf("Dropping StatsD metric, the receiver isn't in a metrics pipeline")
+					continue
+				default:
+					err = r.parser.Aggregate(metric.Raw, metric.Addr)
+				}
 				if err != nil {
 					r.reporter.RecordParseFailure()
 					r.reporter.OnDebugf("Error aggregating pmetric", zap.Error(err))
@@ -167,6 +223,18 @@ func (r *statsdReceiver) Shutdown(context.Context) error {
 	return err
 }
 
+// setOriginAttributes identifies the process, and its container, which sent the data received on a Unix domain socket.
+func (r *statsdReceiver) setOriginAttributes(resource pcommon.Resource, addr net.Addr) {
+	unixAddr, ok := addr.(*transport.UnixAddr)
+	if !ok || unixAddr.PID == 0 {
+		return
+	}
+	resource.Attributes().PutInt(semconv.AttributeProcessPID, int64(unixAddr.PID))
+	if containerID := r.containerIDs.Get(unixAddr.PID); containerID != "" {
+		resource.Attributes().PutStr(semconv.AttributeContainerID, containerID)
+	}
+}
+
 func (r *statsdReceiver) Flush(ctx context.Context, metrics pmetric.Metrics, nextConsumer consumer.Metrics) error {
 	return nextConsumer.ConsumeMetrics(ctx, metrics)
 }
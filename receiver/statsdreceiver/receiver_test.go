@@ -6,6 +6,10 @@ package statsdreceiver
 import (
 	"context"
 	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
 	"testing"
 	"time"
 
@@ -15,6 +19,7 @@ import (
 	"go.opentelemetry.io/collector/config/confignet"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumertest"
+	"go.opentelemetry.io/collector/pdata/pcommon"
 	"go.opentelemetry.io/collector/pdata/pmetric"
 	"go.opentelemetry.io/collector/receiver/receivertest"
 
@@ -159,3 +164,89 @@ func Test_statsdreceiver_EndToEnd(t *testing.T) {
 		})
 	}
 }
+
+func Test_statsdreceiver_EndToEndUnixgram(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.NetAddr = confignet.AddrConfig{
+		Endpoint:  filepath.Join(t.TempDir(), "statsd.sock"),
+		Transport: confignet.TransportTypeUnixgram,
+	}
+	cfg.AggregationInterval = 100 * time.Millisecond
+	metricsSink := new(consumertest.MetricsSink)
+	rcv, err := newReceiver(receivertest.NewNopSettings(), *cfg, metricsSink)
+	require.NoError(t, err)
+	r := rcv.(*statsdReceiver)
+	logsSink := new(consumertest.LogsSink)
+	r.nextLogsConsumer = logsSink
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, r.Shutdown(context.Background()))
+	}()
+
+	conn, err := net.Dial("unixgram", cfg.NetAddr.Endpoint)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("test.metric:42|g|T1656581400\n_e{5,4}:title|text|t:warning\n_sc|my.service|0\n"))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return metricsSink.DataPointCount() == 1 && logsSink.LogRecordCount() == 2
+	}, 10*time.Second, 50*time.Millisecond)
+
+	rm := metricsSink.AllMetrics()[0].ResourceMetrics().At(0)
+	metric := rm.ScopeMetrics().At(0).Metrics().At(0)
+	assert.Equal(t, "test.metric", metric.Name())
+	assert.Equal(t, pcommon.NewTimestampFromTime(time.Unix(1656581400, 0)), metric.Gauge().DataPoints().At(0).Timestamp())
+
+	var records []string
+	for _, logs := range logsSink.AllLogs() {
+		rl := logs.ResourceLogs().At(0)
+		if runtime.GOOS == "linux" {
+			pid, ok := rl.Resource().Attributes().Get("process.pid")
+			require.True(t, ok)
+			assert.Equal(t, int64(os.Getpid()), pid.Int())
+		}
+		for i := 0; i < rl.ScopeLogs().At(0).LogRecords().Len(); i++ {
+			records = append(records, rl.ScopeLogs().At(0).LogRecords().At(i).SeverityText())
+		}
+	}
+	assert.Equal(t, []string{"warning", "ok"}, records)
+
+	if runtime.GOOS == "linux" {
+		// the process sending the data is identified by its credentials
+		pid, ok := rm.Resource().Attributes().Get("process.pid")
+		require.True(t, ok)
+		assert.Equal(t, int64(os.Getpid()), pid.Int())
+	}
+}
+
+func Test_statsdreceiver_DropsWithoutPipeline(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.NetAddr.Endpoint = testutil.GetAvailableLocalNetworkAddress(t, "udp")
+	cfg.AggregationInterval = 100 * time.Millisecond
+	logsSink := new(consumertest.LogsSink)
+	rcv, err := newReceiver(receivertest.NewNopSettings(), *cfg, nil)
+	require.NoError(t, err)
+	r := rcv.(*statsdReceiver)
+	r.nextLogsConsumer = logsSink
+
+	require.NoError(t, r.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		assert.NoError(t, r.Shutdown(context.Background()))
+	}()
+
+	statsdClient, err := client.NewStatsD("udp", cfg.NetAddr.Endpoint)
+	require.NoError(t, err)
+	require.NoError(t, statsdClient.SendMetric(client.Metric{Name: "test.metric", Value: "42", Type: "c"}))
+	conn, err := net.Dial("udp", cfg.NetAddr.Endpoint)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("_sc|my.service|0\n"))
+	require.NoError(t, err)
+
+	// the metrics are dropped without a metrics pipeline, while the service check is flushed
+	require.Eventually(t, func() bool {
+		return logsSink.LogRecordCount() == 1
+	}, 10*time.Second, 50*time.Millisecond)
+}